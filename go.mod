@@ -5,4 +5,5 @@ go 1.23.4
 require (
 	github.com/shopspring/decimal v1.4.0
 	golang.org/x/exp v0.0.0-20250106191152-7588d65b2ba8
+	golang.org/x/text v0.21.0
 )
@@ -0,0 +1,102 @@
+package jsonx
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 按路径修改数组
+//
+// 与 Append / Prepend / Remove 不同，这些方法通过路径定位数组，并把生成的新切片写回父容器，
+// 因此数组嵌套在对象或其他数组中时无需 Get + 修改 + Set。
+
+// AppendAt 向 path 处的数组末尾追加元素
+// 路径不存在或值为 null 时创建新数组，中间容器按 Set 的规则自动创建；值不是数组时设置 *TypeError
+func (j *JSON) AppendAt(path string, values ...interface{}) *JSON {
+	if j.err != nil {
+		return j
+	}
+
+	arr, err := j.lookupArray(path, true)
+	if err != nil {
+		return &JSON{data: j.data, err: err}
+	}
+	grown := make([]interface{}, 0, len(arr)+len(values))
+	grown = append(grown, arr...)
+	grown = append(grown, values...)
+	return j.writeArrayAt(path, grown)
+}
+
+// InsertAt 在 path 处数组的 index 位置之前插入元素
+// index 取值 [0, len]，等于 len 时追加到末尾；负数从末尾倒数（-1 表示插入到最后一个元素之前）
+func (j *JSON) InsertAt(path string, index int, values ...interface{}) *JSON {
+	if j.err != nil {
+		return j
+	}
+
+	arr, err := j.lookupArray(path, false)
+	if err != nil {
+		return &JSON{data: j.data, err: err}
+	}
+	if index < 0 {
+		index += len(arr)
+	}
+	if index < 0 || index > len(arr) {
+		return &JSON{data: j.data, err: fmt.Errorf("insert index out of range at %s: %d", displayPath(path), index)}
+	}
+
+	grown := make([]interface{}, 0, len(arr)+len(values))
+	grown = append(grown, arr[:index]...)
+	grown = append(grown, values...)
+	grown = append(grown, arr[index:]...)
+	return j.writeArrayAt(path, grown)
+}
+
+// RemoveAt 删除 path 处数组 index 位置的元素，支持负数下标
+func (j *JSON) RemoveAt(path string, index int) *JSON {
+	if j.err != nil {
+		return j
+	}
+
+	arr, err := j.lookupArray(path, false)
+	if err != nil {
+		return &JSON{data: j.data, err: err}
+	}
+	resolved, ok := resolveIndex(index, len(arr))
+	if !ok {
+		return &JSON{data: j.data, err: fmt.Errorf("array index out of range at %s: %d", displayPath(path), index)}
+	}
+
+	remaining := make([]interface{}, 0, len(arr)-1)
+	remaining = append(remaining, arr[:resolved]...)
+	remaining = append(remaining, arr[resolved+1:]...)
+	return j.writeArrayAt(path, remaining)
+}
+
+// lookupArray 读取 path 处的数组，create 为 true 时路径不存在或为 null 视为空数组
+func (j *JSON) lookupArray(path string, create bool) ([]interface{}, error) {
+	value, err := j.getByPath(path)
+	if err != nil {
+		if create && errors.Is(err, ErrPathNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if value == nil && create {
+		return nil, nil
+	}
+
+	arr, ok := value.([]interface{})
+	if !ok {
+		return nil, (&JSON{data: value, path: path}).typeError("array")
+	}
+	return arr, nil
+}
+
+// writeArrayAt 将新切片写回 path，由 setByPath 负责逐级更新父容器
+func (j *JSON) writeArrayAt(path string, arr []interface{}) *JSON {
+	if err := j.setByPath(path, arr); err != nil {
+		return &JSON{data: j.data, err: err}
+	}
+	return &JSON{data: j.data}
+}
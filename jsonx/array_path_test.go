@@ -0,0 +1,67 @@
+package jsonx
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestArrayMutationAtPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		apply func(j *JSON) *JSON
+		want  string
+	}{
+		{"append existing", `{"user":{"tags":["a"]}}`, func(j *JSON) *JSON { return j.AppendAt("user.tags", "b", "c") }, `{"user":{"tags":["a","b","c"]}}`},
+		{"append creates missing", `{"user":{}}`, func(j *JSON) *JSON { return j.AppendAt("user.tags", "a") }, `{"user":{"tags":["a"]}}`},
+		{"append creates intermediates", `{}`, func(j *JSON) *JSON { return j.AppendAt("a.b", 1) }, `{"a":{"b":[1]}}`},
+		{"append replaces null", `{"tags":null}`, func(j *JSON) *JSON { return j.AppendAt("tags", 1) }, `{"tags":[1]}`},
+		{"append at root", `[1]`, func(j *JSON) *JSON { return j.AppendAt("", 2) }, `[1,2]`},
+		{"insert front", `{"l":[2,3]}`, func(j *JSON) *JSON { return j.InsertAt("l", 0, 0, 1) }, `{"l":[0,1,2,3]}`},
+		{"insert end", `{"l":[1]}`, func(j *JSON) *JSON { return j.InsertAt("l", 1, 2) }, `{"l":[1,2]}`},
+		{"insert negative", `{"l":[1,3]}`, func(j *JSON) *JSON { return j.InsertAt("l", -1, 2) }, `{"l":[1,2,3]}`},
+		{"remove", `{"l":[1,2,3]}`, func(j *JSON) *JSON { return j.RemoveAt("l", 1) }, `{"l":[1,3]}`},
+		{"remove negative", `{"l":[1,2,3]}`, func(j *JSON) *JSON { return j.RemoveAt("l", -1) }, `{"l":[1,2]}`},
+		{"append nested in arrays", `{"m":[[1],[2]]}`, func(j *JSON) *JSON { return j.AppendAt("m.1", 3, 4) }, `{"m":[[1],[2,3,4]]}`},
+		{"insert nested in arrays", `[[["x"]]]`, func(j *JSON) *JSON { return j.InsertAt("0.0", 0, "w") }, `[[["w","x"]]]`},
+		{"remove nested in arrays", `{"m":[{"rows":[[1,2],[3]]}]}`, func(j *JSON) *JSON { return j.RemoveAt("m.0.rows.0", 0) }, `{"m":[{"rows":[[2],[3]]}]}`},
+		{"append creates in array element", `{"m":[{}]}`, func(j *JSON) *JSON { return j.AppendAt("m.0.tags", "t") }, `{"m":[{"tags":["t"]}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := Parse(tt.input)
+			result := tt.apply(j)
+			if result.Error() != nil {
+				t.Fatalf("error: %v", result.Error())
+			}
+			if got := result.MustJSON(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+			if got := j.MustJSON(); got != tt.want {
+				t.Errorf("receiver not updated: got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArrayMutationAtPathErrors(t *testing.T) {
+	j := Parse(`{"name":"tom","l":[1]}`)
+
+	var typeErr *TypeError
+	if err := j.AppendAt("name", 1).Error(); !errors.As(err, &typeErr) || typeErr.Path != "name" {
+		t.Errorf("AppendAt on string error = %v, want *TypeError", err)
+	}
+	if err := j.InsertAt("missing", 0, 1).Error(); !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("InsertAt on missing path error = %v, want ErrPathNotFound", err)
+	}
+	if err := j.InsertAt("l", 2, 1).Error(); err == nil {
+		t.Error("InsertAt beyond length should fail")
+	}
+	if err := j.RemoveAt("l", 1).Error(); err == nil {
+		t.Error("RemoveAt out of range should fail")
+	}
+	if got := j.MustJSON(); got != `{"l":[1],"name":"tom"}` {
+		t.Errorf("failed operations changed the document: %s", got)
+	}
+}
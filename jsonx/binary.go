@@ -0,0 +1,87 @@
+package jsonx
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 二进制值：以 base64 字符串的形式嵌入 JSON
+
+// MaxBinarySize SetBytes / GetBytes 允许的最大原始数据字节数，0 表示不限制
+// 用于防止意外将数 MB 的数据嵌入文档
+var MaxBinarySize = 1 << 20
+
+// ErrBinaryTooLarge 二进制数据超过 MaxBinarySize，可通过 errors.Is 判断
+var ErrBinaryTooLarge = errors.New("binary value too large")
+
+// base64Encodings GetBytes 依次尝试的编码：标准、无填充、URL 安全、URL 安全无填充
+var base64Encodings = []*base64.Encoding{
+	base64.StdEncoding,
+	base64.RawStdEncoding,
+	base64.URLEncoding,
+	base64.RawURLEncoding,
+}
+
+// SetBytes 将二进制数据以标准 base64（带填充）写入指定路径
+func (j *JSON) SetBytes(path string, data []byte) *JSON {
+	if j.err != nil {
+		return j
+	}
+	if err := checkBinarySize(joinJSONPath(j.path, path), len(data)); err != nil {
+		return &JSON{data: j.data, err: err}
+	}
+	return j.Set(path, base64.StdEncoding.EncodeToString(data))
+}
+
+// GetBytes 读取指定路径的 base64 字符串并解码，标准编码失败时依次尝试无填充与 URL 安全编码
+// 字符串中的换行（如 MIME 风格每 76 字符折行）会被忽略
+func (j *JSON) GetBytes(path string) ([]byte, error) {
+	value := j.Get(path)
+	s, err := value.TryString()
+	if err != nil {
+		return nil, err
+	}
+
+	s = strings.NewReplacer("\r", "", "\n", "").Replace(s)
+	if err := checkBinarySize(value.path, base64.RawStdEncoding.DecodedLen(len(strings.TrimRight(s, "=")))); err != nil {
+		return nil, err
+	}
+
+	for _, enc := range base64Encodings {
+		if data, err := enc.DecodeString(s); err == nil {
+			return data, nil
+		}
+	}
+	return nil, fmt.Errorf("value at %s is not valid base64", displayPath(value.path))
+}
+
+// IsProbablyBase64 启发式判断指定路径的值是否为 base64 编码的二进制数据
+//
+// 要求至少 8 个字符且能被 GetBytes 支持的某种编码解码；只含字母且不足 16 个字符的字符串
+// （如 "username"）虽然可以解码，但更可能是普通文本，视为 false。
+func (j *JSON) IsProbablyBase64(path string) bool {
+	s, err := j.Get(path).TryString()
+	if err != nil || len(s) < 8 {
+		return false
+	}
+	if len(s) < 16 && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ") == "" {
+		return false
+	}
+
+	for _, enc := range base64Encodings {
+		if _, err := enc.DecodeString(s); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// checkBinarySize 检查二进制数据大小
+func checkBinarySize(path string, size int) error {
+	if MaxBinarySize > 0 && size > MaxBinarySize {
+		return fmt.Errorf("%w: value at %s is %d bytes, limit is %d (MaxBinarySize)", ErrBinaryTooLarge, displayPath(path), size, MaxBinarySize)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package jsonx
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestBytesRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	j := Object()
+	// 长度 0..9 覆盖全部填充情况，另加较大的随机数据
+	for _, n := range []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 255, 4096} {
+		data := make([]byte, n)
+		rng.Read(data)
+		if n > 0 {
+			data[0] = 0xff // 高位字节
+		}
+
+		j = j.SetBytes("blob", data)
+		if err := j.Error(); err != nil {
+			t.Fatalf("SetBytes(%d bytes) error = %v", n, err)
+		}
+		if got := j.Get("blob").String(); got != base64.StdEncoding.EncodeToString(data) {
+			t.Errorf("SetBytes should store standard base64, got %q", got)
+		}
+
+		// 经序列化往返后仍能还原
+		s, _ := j.ToJSON()
+		got, err := Parse(s).GetBytes("blob")
+		if err != nil || !bytes.Equal(got, data) {
+			t.Errorf("round trip of %d bytes: err = %v", n, err)
+		}
+	}
+}
+
+func TestGetBytesEncodings(t *testing.T) {
+	data := []byte{0xfb, 0xff, 0xfe, 0x00, 0x10}
+	j := New(map[string]interface{}{
+		"std":     base64.StdEncoding.EncodeToString(data),
+		"raw":     base64.RawStdEncoding.EncodeToString(data),
+		"url":     base64.URLEncoding.EncodeToString(data),
+		"rawURL":  base64.RawURLEncoding.EncodeToString(data),
+		"wrapped": "+//+\nABA=",
+		"bad":     "not base64!",
+		"number":  1,
+	})
+
+	for _, path := range []string{"std", "raw", "url", "rawURL", "wrapped"} {
+		if got, err := j.GetBytes(path); err != nil || !bytes.Equal(got, data) {
+			t.Errorf("GetBytes(%s) = %x, %v", path, got, err)
+		}
+	}
+
+	if _, err := j.GetBytes("bad"); err == nil || err.Error() != "value at bad is not valid base64" {
+		t.Errorf("GetBytes(bad) error = %v", err)
+	}
+	var typeErr *TypeError
+	if _, err := j.GetBytes("number"); !errors.As(err, &typeErr) {
+		t.Errorf("GetBytes(number) error = %v", err)
+	}
+	if _, err := j.GetBytes("missing"); !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("GetBytes(missing) error = %v", err)
+	}
+}
+
+func TestBinarySizeLimit(t *testing.T) {
+	defer func(limit int) { MaxBinarySize = limit }(MaxBinarySize)
+	MaxBinarySize = 16
+
+	j := Object().SetBytes("ok", make([]byte, 16))
+	if err := j.Error(); err != nil {
+		t.Fatalf("data at the limit should be accepted: %v", err)
+	}
+
+	err := Object().SetBytes("thumb", make([]byte, 17)).Error()
+	if !errors.Is(err, ErrBinaryTooLarge) || err.Error() != "binary value too large: value at thumb is 17 bytes, limit is 16 (MaxBinarySize)" {
+		t.Errorf("SetBytes over limit error = %v", err)
+	}
+
+	j.Set("big", base64.StdEncoding.EncodeToString(make([]byte, 64)))
+	if _, err := j.GetBytes("big"); !errors.Is(err, ErrBinaryTooLarge) {
+		t.Errorf("GetBytes over limit error = %v", err)
+	}
+
+	MaxBinarySize = 0
+	if err := Object().SetBytes("big", make([]byte, 1<<16)).Error(); err != nil {
+		t.Errorf("MaxBinarySize = 0 should disable the limit: %v", err)
+	}
+}
+
+func TestIsProbablyBase64(t *testing.T) {
+	j := New(map[string]interface{}{
+		"sig":      base64.StdEncoding.EncodeToString([]byte("a signature payload")),
+		"urlSafe":  base64.RawURLEncoding.EncodeToString([]byte{0xfb, 0xff, 0xbf, 0x01, 0x02, 0x03, 0x04}),
+		"word":     "username",
+		"sentence": "hello world",
+		"short":    "YQ==",
+		"number":   12345678,
+	})
+
+	for path, want := range map[string]bool{
+		"sig": true, "urlSafe": true, "word": false, "sentence": false, "short": false, "number": false, "missing": false,
+	} {
+		if got := j.IsProbablyBase64(path); got != want {
+			t.Errorf("IsProbablyBase64(%s) = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -1,13 +1,21 @@
 package jsonx
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Builder JSON 构建器，支持链式调用
+// 构建过程中的错误（如非法的原始 JSON、路径冲突）会被累积，并由 Build 返回的 *JSON 携带
 type Builder struct {
 	json *JSON
+	errs []error
 }
 
 // NewBuilder 创建新的构建器
@@ -22,62 +30,62 @@ func NewArrayBuilder() *Builder {
 
 // AddString 添加字符串字段
 func (b *Builder) AddString(key, value string) *Builder {
-	b.json.Set(key, value)
+	b.set(key, value)
 	return b
 }
 
 // AddInt 添加整数字段
 func (b *Builder) AddInt(key string, value int) *Builder {
-	b.json.Set(key, value)
+	b.set(key, value)
 	return b
 }
 
 // AddInt64 添加 int64 字段
 func (b *Builder) AddInt64(key string, value int64) *Builder {
-	b.json.Set(key, value)
+	b.set(key, value)
 	return b
 }
 
 // AddFloat 添加浮点数字段
 func (b *Builder) AddFloat(key string, value float64) *Builder {
-	b.json.Set(key, value)
+	b.set(key, value)
 	return b
 }
 
 // AddBool 添加布尔字段
 func (b *Builder) AddBool(key string, value bool) *Builder {
-	b.json.Set(key, value)
+	b.set(key, value)
 	return b
 }
 
 // AddObject 添加对象字段
 func (b *Builder) AddObject(key string, value *JSON) *Builder {
-	b.json.Set(key, value.data)
+	b.set(key, value.data)
 	return b
 }
 
 // AddArray 添加数组字段
 func (b *Builder) AddArray(key string, value *JSON) *Builder {
-	b.json.Set(key, value.data)
+	b.set(key, value.data)
 	return b
 }
 
 // AddRaw 添加原始值
 func (b *Builder) AddRaw(key string, value interface{}) *Builder {
-	b.json.Set(key, value)
+	b.set(key, value)
 	return b
 }
 
 // AddNull 添加 null 字段
 func (b *Builder) AddNull(key string) *Builder {
-	b.json.Set(key, nil)
+	b.set(key, nil)
 	return b
 }
 
 // AddIf 条件添加字段
 func (b *Builder) AddIf(condition bool, key string, value interface{}) *Builder {
 	if condition {
-		b.json.Set(key, value)
+		b.set(key, value)
 	}
 	return b
 }
@@ -85,7 +93,7 @@ func (b *Builder) AddIf(condition bool, key string, value interface{}) *Builder
 // AddStringIf 条件添加字符串字段
 func (b *Builder) AddStringIf(condition bool, key, value string) *Builder {
 	if condition {
-		b.json.Set(key, value)
+		b.set(key, value)
 	}
 	return b
 }
@@ -93,80 +101,161 @@ func (b *Builder) AddStringIf(condition bool, key, value string) *Builder {
 // AddMany 批量添加字段
 func (b *Builder) AddMany(fields map[string]interface{}) *Builder {
 	for k, v := range fields {
-		b.json.Set(k, v)
+		b.set(k, v)
 	}
 	return b
 }
 
+// AddTime 添加按 layout 格式化的时间字段，layout 为空时使用 RFC3339
+func (b *Builder) AddTime(key string, t time.Time, layout string) *Builder {
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	b.set(key, t.Format(layout))
+	return b
+}
+
+// AddRawJSON 解析 JSON 片段并作为字段嵌入，片段非法时记录错误
+func (b *Builder) AddRawJSON(key, jsonStr string) *Builder {
+	fragment := Parse(jsonStr)
+	if fragment.err != nil {
+		b.errs = append(b.errs, fmt.Errorf("%s: invalid raw JSON: %w", key, fragment.err))
+		return b
+	}
+	b.set(key, fragment.data)
+	return b
+}
+
+// AddObjectFn 通过闭包内联构建嵌套对象
+func (b *Builder) AddObjectFn(key string, fn func(b *Builder)) *Builder {
+	b.set(key, b.nested(NewBuilder(), key, fn))
+	return b
+}
+
+// AddArrayFn 通过闭包内联构建嵌套数组
+func (b *Builder) AddArrayFn(key string, fn func(b *Builder)) *Builder {
+	b.set(key, b.nested(NewArrayBuilder(), key, fn))
+	return b
+}
+
+// nested 执行闭包构建子结构，子构建器的错误以 key 为前缀并入当前构建器
+func (b *Builder) nested(child *Builder, key string, fn func(b *Builder)) interface{} {
+	fn(child)
+	for _, err := range child.errs {
+		b.errs = append(b.errs, fmt.Errorf("%s.%w", key, err))
+	}
+	return child.json.data
+}
+
 // 数组构建器方法
 
 // AppendString 向数组添加字符串
 func (b *Builder) AppendString(value string) *Builder {
-	b.json.Append(value)
+	b.append(value)
 	return b
 }
 
 // AppendInt 向数组添加整数
 func (b *Builder) AppendInt(value int) *Builder {
-	b.json.Append(value)
+	b.append(value)
 	return b
 }
 
 // AppendFloat 向数组添加浮点数
 func (b *Builder) AppendFloat(value float64) *Builder {
-	b.json.Append(value)
+	b.append(value)
 	return b
 }
 
 // AppendBool 向数组添加布尔值
 func (b *Builder) AppendBool(value bool) *Builder {
-	b.json.Append(value)
+	b.append(value)
 	return b
 }
 
 // AppendObject 向数组添加对象
 func (b *Builder) AppendObject(value *JSON) *Builder {
-	b.json.Append(value.data)
+	b.append(value.data)
 	return b
 }
 
 // AppendArray 向数组添加数组
 func (b *Builder) AppendArray(value *JSON) *Builder {
-	b.json.Append(value.data)
+	b.append(value.data)
 	return b
 }
 
 // AppendRaw 向数组添加原始值
 func (b *Builder) AppendRaw(value interface{}) *Builder {
-	b.json.Append(value)
+	b.append(value)
+	return b
+}
+
+// AppendRawJSON 解析 JSON 片段并添加到数组，片段非法时记录错误
+func (b *Builder) AppendRawJSON(jsonStr string) *Builder {
+	fragment := Parse(jsonStr)
+	if fragment.err != nil {
+		b.errs = append(b.errs, fmt.Errorf("invalid raw JSON: %w", fragment.err))
+		return b
+	}
+	b.append(fragment.data)
 	return b
 }
 
 // AppendNull 向数组添加 null
 func (b *Builder) AppendNull() *Builder {
-	b.json.Append(nil)
+	b.append(nil)
+	return b
+}
+
+// AppendObjectFn 通过闭包内联构建对象并添加到数组
+func (b *Builder) AppendObjectFn(fn func(b *Builder)) *Builder {
+	b.append(b.nested(NewBuilder(), strconv.Itoa(b.json.Length()), fn))
+	return b
+}
+
+// AppendArrayFn 通过闭包内联构建数组并添加到数组
+func (b *Builder) AppendArrayFn(fn func(b *Builder)) *Builder {
+	b.append(b.nested(NewArrayBuilder(), strconv.Itoa(b.json.Length()), fn))
 	return b
 }
 
 // AppendMany 向数组批量添加值
 func (b *Builder) AppendMany(values ...interface{}) *Builder {
-	b.json.Append(values...)
+	b.append(values...)
 	return b
 }
 
-// Build 构建最终的 JSON
+// Build 构建最终的 JSON，构建过程中有错误时 Error() 返回全部错误
 func (b *Builder) Build() *JSON {
+	if len(b.errs) > 0 {
+		return &JSON{data: b.json.data, err: errors.Join(b.errs...)}
+	}
 	return b.json
 }
 
 // BuildString 构建 JSON 字符串
 func (b *Builder) BuildString() (string, error) {
-	return b.json.ToJSON()
+	return b.Build().ToJSON()
 }
 
 // BuildPrettyString 构建格式化的 JSON 字符串
 func (b *Builder) BuildPrettyString() (string, error) {
-	return b.json.ToPrettyJSON()
+	return b.Build().ToPrettyJSON()
+}
+
+// set 设置字段并记录错误
+func (b *Builder) set(key string, value interface{}) {
+	if err := b.json.Set(key, value).err; err != nil {
+		b.errs = append(b.errs, fmt.Errorf("%s: %w", key, err))
+	}
+}
+
+// append 追加数组元素并记录错误
+func (b *Builder) append(values ...interface{}) {
+	if err := b.json.Append(values...).err; err != nil {
+		b.errs = append(b.errs, err)
+	}
 }
 
 // 快速构建器函数
@@ -211,32 +300,154 @@ func (t *TemplateBuilder) SetMany(values map[string]interface{}) *TemplateBuilde
 	return t
 }
 
-// Build 构建 JSON（简单的字符串替换）
+// Build 构建 JSON
+//
+// 占位符写作 {{name}}，可带默认值 {{name|默认值}}：
+//   - 独占一个 JSON 字符串的占位符（"{{name}}"）或裸写的占位符（{{name}}）替换为值的 JSON 表示，
+//     对象、数组、*JSON 均按 json.Marshal 序列化
+//   - 嵌在字符串中间的占位符（"Hello {{name}}"）替换为值的文本形式
+//   - 默认值在裸写位置若本身是合法 JSON（如 0、[]、true）则按 JSON 解析，否则视为字符串
+//
+// 未设置且无默认值的占位符替换为 null（字符串中间替换为空串），需要报错时使用 BuildStrict
 func (t *TemplateBuilder) Build() *JSON {
-	result := t.template
-	for k, v := range t.values {
-		placeholder := fmt.Sprintf("{{%s}}", k)
-		var replacement string
-
-		switch val := v.(type) {
-		case string:
-			replacement = fmt.Sprintf(`"%s"`, escapeJSONString(val))
-		case nil:
-			replacement = "null"
-		case bool:
-			if val {
-				replacement = "true"
-			} else {
-				replacement = "false"
+	result, _, err := t.render()
+	if err != nil {
+		return &JSON{err: err}
+	}
+	return Parse(result)
+}
+
+// BuildStrict 构建 JSON，存在未设置且无默认值的占位符时返回列出这些占位符的错误
+func (t *TemplateBuilder) BuildStrict() (*JSON, error) {
+	result, missing, err := t.render()
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("template placeholders not set: %s", strings.Join(missing, ", "))
+	}
+
+	j := Parse(result)
+	if j.err != nil {
+		return nil, j.err
+	}
+	return j, nil
+}
+
+// render 替换模板中的占位符，返回结果文本与未设置的占位符（已排序去重）
+func (t *TemplateBuilder) render() (string, []string, error) {
+	var b bytes.Buffer
+	missingSet := make(map[string]bool)
+	src := t.template
+	inString := false
+
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		if inString && c == '\\' && i+1 < len(src) {
+			b.WriteByte(c)
+			b.WriteByte(src[i+1])
+			i++
+			continue
+		}
+		if c == '"' {
+			inString = !inString
+			b.WriteByte(c)
+			continue
+		}
+		if c != '{' || !strings.HasPrefix(src[i:], "{{") {
+			b.WriteByte(c)
+			continue
+		}
+
+		end := strings.Index(src[i+2:], "}}")
+		if end < 0 {
+			b.WriteString(src[i:])
+			break
+		}
+		name, defaultValue, hasDefault := strings.Cut(src[i+2:i+2+end], "|")
+		name = strings.TrimSpace(name)
+		next := i + 2 + end + 2
+
+		value, isSet := t.values[name]
+		if !isSet && !hasDefault {
+			missingSet[name] = true
+		}
+
+		// 占位符独占整个字符串时，连同引号一起替换为 JSON 值
+		wholeString := inString && i > 0 && src[i-1] == '"' && next < len(src) && src[next] == '"'
+
+		switch {
+		case wholeString:
+			if !isSet && hasDefault {
+				value = defaultValue
+			}
+			encoded, err := marshalTemplateValue(value)
+			if err != nil {
+				return "", nil, fmt.Errorf("template placeholder %s: %w", name, err)
 			}
+			b.Truncate(b.Len() - 1)
+			b.WriteString(encoded)
+			inString = false
+			next++
+		case inString:
+			text := defaultValue
+			if isSet {
+				var err error
+				if text, err = templateValueText(value); err != nil {
+					return "", nil, fmt.Errorf("template placeholder %s: %w", name, err)
+				}
+			}
+			b.WriteString(escapeJSONString(text))
 		default:
-			replacement = fmt.Sprintf("%v", val)
+			if !isSet && hasDefault {
+				if json.Valid([]byte(defaultValue)) {
+					b.WriteString(defaultValue)
+					i = next - 1
+					continue
+				}
+				value = defaultValue
+			}
+			encoded, err := marshalTemplateValue(value)
+			if err != nil {
+				return "", nil, fmt.Errorf("template placeholder %s: %w", name, err)
+			}
+			b.WriteString(encoded)
 		}
+		i = next - 1
+	}
 
-		result = strings.ReplaceAll(result, placeholder, replacement)
+	missing := make([]string, 0, len(missingSet))
+	for name := range missingSet {
+		missing = append(missing, name)
 	}
+	sort.Strings(missing)
+	return b.String(), missing, nil
+}
 
-	return Parse(result)
+// marshalTemplateValue 将模板变量序列化为 JSON
+func marshalTemplateValue(value interface{}) (string, error) {
+	if j, ok := value.(*JSON); ok {
+		if j.err != nil {
+			return "", j.err
+		}
+		value = j.data
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// templateValueText 模板变量嵌入字符串时的文本形式
+func templateValueText(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case nil:
+		return "", nil
+	}
+	return marshalTemplateValue(value)
 }
 
 // 实用工具函数
@@ -275,62 +486,119 @@ func DeepMergeAll(jsons ...*JSON) *JSON {
 	return result
 }
 
-// Compare 比较两个 JSON 是否相等
+// Compare 比较两个 JSON 是否相等，等价于 Equal
 func Compare(j1, j2 *JSON) bool {
-	if j1.err != nil || j2.err != nil {
-		return false
-	}
-
-	json1, err1 := j1.ToJSON()
-	json2, err2 := j2.ToJSON()
-
-	if err1 != nil || err2 != nil {
-		return false
-	}
-
-	return json1 == json2
+	return Equal(j1, j2)
 }
 
 // Flatten 扁平化 JSON 对象
+// 空对象与空数组作为叶子保留（根为空数组时键为 ""），以便 Unflatten 完整还原
+// 数据存在循环引用时返回空结果，并在 j 上记录 ErrCyclicData；嵌套过深时记录 ErrTooDeep
 func Flatten(j *JSON) map[string]interface{} {
 	result := make(map[string]interface{})
-	flattenRecursive(j.data, "", result)
+	if err := flattenRecursive(j.data, "", result, &cycleGuard{}); err != nil {
+		j.err = err
+		return make(map[string]interface{})
+	}
 	return result
 }
 
 // flattenRecursive 递归扁平化
-func flattenRecursive(data interface{}, prefix string, result map[string]interface{}) {
+func flattenRecursive(data interface{}, prefix string, result map[string]interface{}, guard *cycleGuard) error {
 	switch v := data.(type) {
 	case map[string]interface{}:
+		ptr, err := guard.enter(v)
+		if err != nil {
+			return err
+		}
+		if len(v) == 0 && prefix != "" {
+			result[prefix] = make(map[string]interface{})
+		}
 		for k, val := range v {
 			key := k
 			if prefix != "" {
 				key = prefix + "." + k
 			}
-			flattenRecursive(val, key, result)
+			if err := flattenRecursive(val, key, result, guard); err != nil {
+				return err
+			}
 		}
+		guard.leave(ptr)
 	case []interface{}:
+		ptr, err := guard.enter(v)
+		if err != nil {
+			return err
+		}
+		if len(v) == 0 {
+			result[prefix] = make([]interface{}, 0)
+		}
 		for i, val := range v {
 			key := fmt.Sprintf("%d", i)
 			if prefix != "" {
 				key = prefix + "." + key
 			}
-			flattenRecursive(val, key, result)
+			if err := flattenRecursive(val, key, result, guard); err != nil {
+				return err
+			}
 		}
+		guard.leave(ptr)
 	default:
 		result[prefix] = v
 	}
+	return nil
 }
 
-// Unflatten 反扁平化为 JSON 对象
+// Unflatten 反扁平化，Flatten 的逆操作
+// 键恰好为 0..n-1 的连续数字的层级还原为数组，其余还原为对象
 func Unflatten(flat map[string]interface{}) *JSON {
-	result := Object()
+	if value, ok := flat[""]; ok && len(flat) == 1 {
+		return New(value)
+	}
 
+	root := make(unflattenNode)
 	for path, value := range flat {
-		result.Set(path, value)
+		parts := strings.Split(path, ".")
+		current := root
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := current[part].(unflattenNode)
+			if !ok {
+				next = make(unflattenNode)
+				current[part] = next
+			}
+			current = next
+		}
+		current[parts[len(parts)-1]] = value
 	}
 
-	return result
+	return New(root.build())
+}
+
+// unflattenNode Unflatten 过程中创建的中间层级，与叶子值中的 map 区分
+type unflattenNode map[string]interface{}
+
+// build 将中间层级转换为对象或数组
+func (n unflattenNode) build() interface{} {
+	children := make(map[string]interface{}, len(n))
+	for k, v := range n {
+		if child, ok := v.(unflattenNode); ok {
+			children[k] = child.build()
+		} else {
+			children[k] = v
+		}
+	}
+
+	if len(children) == 0 {
+		return children
+	}
+	arr := make([]interface{}, len(children))
+	for i := range arr {
+		value, ok := children[strconv.Itoa(i)]
+		if !ok {
+			return children
+		}
+		arr[i] = value
+	}
+	return arr
 }
 
 // Transform 转换 JSON 结构
@@ -338,7 +606,9 @@ func Transform(j *JSON, transformer func(key string, value *JSON) interface{}) *
 	return j.Map(transformer)
 }
 
-// Pick 选择指定字段
+// Pick 选择指定字段，支持点路径与通配符片段 "*"（如 "user.name"、"users.*.email"）
+// 结果为恰好包含这些叶子的最小嵌套结构，数组保留原索引位置（空位为 null），
+// 不存在的路径会被忽略；结果为深拷贝，修改结果不影响原文档
 func Pick(j *JSON, fields ...string) *JSON {
 	if !j.IsObject() {
 		return &JSON{err: fmt.Errorf("not an object")}
@@ -346,16 +616,24 @@ func Pick(j *JSON, fields ...string) *JSON {
 
 	result := Object()
 	for _, field := range fields {
-		if j.Has(field) {
-			value := j.Get(field)
-			result.Set(field, value.data)
+		for _, path := range expandWildcardPath(j.data, field) {
+			value, err := j.getByPath(path)
+			if err != nil {
+				continue
+			}
+			cloned, err := deepClone(value)
+			if err != nil {
+				return &JSON{err: err}
+			}
+			result.setByPath(path, cloned)
 		}
 	}
 
 	return result
 }
 
-// Omit 排除指定字段
+// Omit 排除指定字段，支持点路径与通配符片段 "*"，只删除对象中的字段，兄弟字段保持不变
+// 不存在的路径会被忽略；结果为深拷贝，不修改原文档
 func Omit(j *JSON, fields ...string) *JSON {
 	if !j.IsObject() {
 		return &JSON{err: fmt.Errorf("not an object")}
@@ -363,116 +641,58 @@ func Omit(j *JSON, fields ...string) *JSON {
 
 	result := j.Clone()
 	for _, field := range fields {
-		result.Delete(field)
+		for _, path := range expandWildcardPath(result.data, field) {
+			result.deleteByPath(path)
+		}
 	}
 
 	return result
 }
 
-// Schema 简单的 JSON Schema 验证
-type Schema struct {
-	Type       string             `json:"type"`
-	Properties map[string]*Schema `json:"properties,omitempty"`
-	Items      *Schema            `json:"items,omitempty"`
-	Required   []string           `json:"required,omitempty"`
-	MinLength  *int               `json:"minLength,omitempty"`
-	MaxLength  *int               `json:"maxLength,omitempty"`
-	Minimum    *float64           `json:"minimum,omitempty"`
-	Maximum    *float64           `json:"maximum,omitempty"`
-}
+// 辅助函数
 
-// Validate 验证 JSON 是否符合 Schema
-func (s *Schema) Validate(j *JSON) error {
-	return s.validateValue(j, "")
-}
+// expandWildcardPath 将含 "*" 片段的路径展开为文档中实际存在的具体路径
+// 不含通配符的路径原样返回，是否存在由调用方判断
+func expandWildcardPath(data interface{}, path string) []string {
+	if !strings.Contains(path, "*") {
+		return []string{path}
+	}
 
-// validateValue 验证值
-func (s *Schema) validateValue(j *JSON, path string) error {
-	switch s.Type {
-	case "object":
-		if !j.IsObject() {
-			return fmt.Errorf("expected object at %s", path)
+	var result []string
+	var walk func(current interface{}, parts []string, prefix string)
+	walk = func(current interface{}, parts []string, prefix string) {
+		if len(parts) == 0 {
+			result = append(result, prefix)
+			return
 		}
-
-		// 验证必需字段
-		for _, required := range s.Required {
-			if !j.Has(required) {
-				return fmt.Errorf("missing required field '%s' at %s", required, path)
+		if parts[0] != "*" {
+			child, err := New(current).getByPath(parts[0])
+			if err == nil {
+				walk(child, parts[1:], joinJSONPath(prefix, parts[0]))
 			}
+			return
 		}
 
-		// 验证属性
-		if s.Properties != nil {
-			for prop, schema := range s.Properties {
-				if j.Has(prop) {
-					propPath := path + "." + prop
-					if path == "" {
-						propPath = prop
-					}
-					if err := schema.validateValue(j.Get(prop), propPath); err != nil {
-						return err
-					}
-				}
+		switch container := current.(type) {
+		case map[string]interface{}:
+			keys := make([]string, 0, len(container))
+			for key := range container {
+				keys = append(keys, key)
 			}
-		}
-
-	case "array":
-		if !j.IsArray() {
-			return fmt.Errorf("expected array at %s", path)
-		}
-
-		if s.Items != nil {
-			length := j.Length()
-			for i := 0; i < length; i++ {
-				itemPath := fmt.Sprintf("%s[%d]", path, i)
-				if err := s.Items.validateValue(j.Index(i), itemPath); err != nil {
-					return err
-				}
+			sort.Strings(keys)
+			for _, key := range keys {
+				walk(container[key], parts[1:], joinJSONPath(prefix, key))
+			}
+		case []interface{}:
+			for i, item := range container {
+				walk(item, parts[1:], joinJSONPath(prefix, strconv.Itoa(i)))
 			}
-		}
-
-	case "string":
-		if !j.IsString() {
-			return fmt.Errorf("expected string at %s", path)
-		}
-
-		str := j.String()
-		if s.MinLength != nil && len(str) < *s.MinLength {
-			return fmt.Errorf("string too short at %s", path)
-		}
-		if s.MaxLength != nil && len(str) > *s.MaxLength {
-			return fmt.Errorf("string too long at %s", path)
-		}
-
-	case "number":
-		if !j.IsNumber() {
-			return fmt.Errorf("expected number at %s", path)
-		}
-
-		num := j.Float64()
-		if s.Minimum != nil && num < *s.Minimum {
-			return fmt.Errorf("number too small at %s", path)
-		}
-		if s.Maximum != nil && num > *s.Maximum {
-			return fmt.Errorf("number too large at %s", path)
-		}
-
-	case "boolean":
-		if !j.IsBool() {
-			return fmt.Errorf("expected boolean at %s", path)
-		}
-
-	case "null":
-		if !j.IsNull() {
-			return fmt.Errorf("expected null at %s", path)
 		}
 	}
-
-	return nil
+	walk(data, strings.Split(path, "."), "")
+	return result
 }
 
-// 辅助函数
-
 // escapeJSONString 转义 JSON 字符串
 func escapeJSONString(s string) string {
 	replacer := strings.NewReplacer(
@@ -544,32 +764,47 @@ func Size(j *JSON) int {
 }
 
 // Depth 计算 JSON 的深度
+// 数据存在循环引用时返回 -1，并在 j 上记录 ErrCyclicData；嵌套过深时记录 ErrTooDeep
 func Depth(j *JSON) int {
-	return calculateDepth(j.data, 0)
+	depth, err := calculateDepth(j.data, 0, &cycleGuard{})
+	if err != nil {
+		j.err = err
+		return -1
+	}
+	return depth
 }
 
 // calculateDepth 递归计算深度
-func calculateDepth(data interface{}, currentDepth int) int {
+func calculateDepth(data interface{}, currentDepth int, guard *cycleGuard) (int, error) {
+	var children []interface{}
 	switch v := data.(type) {
 	case map[string]interface{}:
-		maxDepth := currentDepth
+		children = make([]interface{}, 0, len(v))
 		for _, val := range v {
-			depth := calculateDepth(val, currentDepth+1)
-			if depth > maxDepth {
-				maxDepth = depth
-			}
+			children = append(children, val)
 		}
-		return maxDepth
 	case []interface{}:
-		maxDepth := currentDepth
-		for _, val := range v {
-			depth := calculateDepth(val, currentDepth+1)
-			if depth > maxDepth {
-				maxDepth = depth
-			}
-		}
-		return maxDepth
+		children = v
 	default:
-		return currentDepth
+		return currentDepth, nil
 	}
+
+	ptr, err := guard.enter(data)
+	if err != nil {
+		return 0, err
+	}
+
+	maxDepth := currentDepth
+	for _, val := range children {
+		depth, err := calculateDepth(val, currentDepth+1, guard)
+		if err != nil {
+			return 0, err
+		}
+		if depth > maxDepth {
+			maxDepth = depth
+		}
+	}
+
+	guard.leave(ptr)
+	return maxDepth, nil
 }
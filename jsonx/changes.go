@@ -0,0 +1,284 @@
+package jsonx
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChangeOp 变更操作类型
+type ChangeOp string
+
+const (
+	ChangeSet    ChangeOp = "set"
+	ChangeDelete ChangeOp = "delete"
+	ChangeAppend ChangeOp = "append"
+)
+
+// Change 一次变更记录，Old 与 New 均为操作时的深拷贝，不受后续修改影响
+type Change struct {
+	Path    string      // 操作路径
+	Op      ChangeOp    // 操作类型
+	Old     interface{} // 操作前的值，路径不存在时为 nil；Append 为追加前的数组
+	New     interface{} // 写入的值；Append 为追加的元素数组；Delete 为 nil
+	Existed bool        // 操作前路径是否存在
+	Time    time.Time   // 操作时间
+
+	undo undoStep
+}
+
+// undoStep 撤销一次变更所需的操作
+type undoStep struct {
+	path     []string    // 已解析负数下标的路径
+	value    interface{} // restore 时写回的值
+	truncate int         // 大于等于 0 时将 path 处的数组截断到该长度
+	remove   bool        // 删除 path 处的对象键
+}
+
+// Recorder 记录文档变更的包装器
+//
+// 通过 Recorder 执行的 Set / Delete / Append 会立即作用于文档，并按顺序记录到变更日志，
+// 可用于撤销（Undo）或在其他文档上重放（ReplayOn）。失败的操作不会被记录，错误通过 Error 返回。
+type Recorder struct {
+	json    *JSON
+	changes []Change
+	errs    []error
+}
+
+// TrackChanges 开始记录文档变更，之后应通过返回的 Recorder 修改文档
+func (j *JSON) TrackChanges() *Recorder {
+	r := &Recorder{json: j}
+	if j.err != nil {
+		r.errs = append(r.errs, j.err)
+	}
+	return r
+}
+
+// JSON 返回被记录的文档
+func (r *Recorder) JSON() *JSON {
+	return r.json
+}
+
+// Error 返回所有失败操作的错误
+func (r *Recorder) Error() error {
+	return errors.Join(r.errs...)
+}
+
+// Changes 返回按操作顺序排列的变更日志副本
+func (r *Recorder) Changes() []Change {
+	changes := make([]Change, len(r.changes))
+	copy(changes, r.changes)
+	return changes
+}
+
+// Set 设置指定路径的值并记录变更
+func (r *Recorder) Set(path string, value interface{}) *Recorder {
+	if r.json.err != nil {
+		return r
+	}
+
+	change, err := r.prepare(path, ChangeSet)
+	if err == nil {
+		change.New, err = deepClone(value)
+	}
+	if err == nil {
+		err = r.json.setByPath(path, value)
+	}
+	return r.record(change, err)
+}
+
+// Delete 删除指定路径的值并记录变更
+func (r *Recorder) Delete(path string) *Recorder {
+	if r.json.err != nil {
+		return r
+	}
+
+	change, err := r.prepare(path, ChangeDelete)
+	if err == nil {
+		err = r.json.deleteByPath(path)
+	}
+	return r.record(change, err)
+}
+
+// Append 向指定路径的数组追加元素并记录变更，path 为空时表示根数组
+func (r *Recorder) Append(path string, values ...interface{}) *Recorder {
+	if r.json.err != nil {
+		return r
+	}
+
+	change := Change{Path: path, Op: ChangeAppend, Time: time.Now()}
+	arr, err := r.json.arrayAt(path)
+	if err == nil {
+		change.Existed = true
+		change.Old, err = deepClone(arr)
+	}
+	if err == nil {
+		change.New, err = deepClone(values)
+	}
+	if err == nil {
+		change.undo = undoStep{path: splitPath(path), truncate: len(arr)}
+		err = r.json.appendAt(path, values)
+	}
+	return r.record(change, err)
+}
+
+// record 记录成功的变更或保存错误
+func (r *Recorder) record(change Change, err error) *Recorder {
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s %s: %w", change.Op, change.Path, err))
+		return r
+	}
+	r.changes = append(r.changes, change)
+	return r
+}
+
+// prepare 在操作前记录旧值并计算撤销步骤
+//
+// 沿路径查找第一个不存在的节点：路径完整存在时撤销写回旧值；
+// 对象中缺失的键撤销时删除该键；数组越界（Set 会扩展数组）撤销时截断回原长度；
+// 途经 null 时撤销写回该 null，从而移除 Set 自动创建的中间容器。
+func (r *Recorder) prepare(path string, op ChangeOp) (Change, error) {
+	change := Change{Path: path, Op: op, Time: time.Now()}
+	parts := splitPath(path)
+	resolved := make([]string, 0, len(parts))
+
+	var current interface{} = r.json.data
+	for _, part := range parts {
+		switch container := current.(type) {
+		case map[string]interface{}:
+			value, exists := container[part]
+			if !exists {
+				change.undo = undoStep{path: append(resolved, part), truncate: -1, remove: true}
+				return change, nil
+			}
+			current = value
+		case []interface{}:
+			idx, err := strconv.Atoi(part)
+			if err != nil {
+				return change, fmt.Errorf("cannot access property '%s' on array", part)
+			}
+			if idx < 0 {
+				idx += len(container)
+			}
+			if idx < 0 {
+				return change, fmt.Errorf("invalid array index: %s", part)
+			}
+			if idx >= len(container) {
+				change.undo = undoStep{path: resolved, truncate: len(container)}
+				return change, nil
+			}
+			part = strconv.Itoa(idx)
+			current = container[idx]
+		default:
+			// null 或标量：Set 会将 null 替换为容器，标量则会失败
+			change.undo = undoStep{path: resolved, value: current, truncate: -1}
+			return change, nil
+		}
+		resolved = append(resolved, part)
+	}
+
+	old, err := deepClone(current)
+	if err != nil {
+		return change, err
+	}
+	change.Old = old
+	change.Existed = true
+	change.undo = undoStep{path: resolved, value: old, truncate: -1}
+	return change, nil
+}
+
+// Undo 按相反顺序撤销最近 n 次变更，并将其从日志中移除
+func (r *Recorder) Undo(n int) error {
+	if n > len(r.changes) {
+		return fmt.Errorf("cannot undo %d changes, only %d recorded", n, len(r.changes))
+	}
+
+	for ; n > 0; n-- {
+		last := r.changes[len(r.changes)-1]
+		if err := r.json.applyUndo(last.undo); err != nil {
+			return fmt.Errorf("undo %s %s: %w", last.Op, last.Path, err)
+		}
+		r.changes = r.changes[:len(r.changes)-1]
+	}
+	return nil
+}
+
+// ReplayOn 按顺序在另一个文档上重放全部变更
+func (r *Recorder) ReplayOn(other *JSON) error {
+	if other.err != nil {
+		return other.err
+	}
+
+	for _, change := range r.changes {
+		value, err := deepClone(change.New)
+		if err != nil {
+			return err
+		}
+
+		switch change.Op {
+		case ChangeSet:
+			err = other.setByPath(change.Path, value)
+		case ChangeDelete:
+			err = other.deleteByPath(change.Path)
+		case ChangeAppend:
+			values, _ := value.([]interface{})
+			err = other.appendAt(change.Path, values)
+		}
+		if err != nil {
+			return fmt.Errorf("replay %s %s: %w", change.Op, change.Path, err)
+		}
+	}
+	return nil
+}
+
+// applyUndo 执行撤销步骤
+func (j *JSON) applyUndo(step undoStep) error {
+	path := strings.Join(step.path, ".")
+	switch {
+	case step.remove:
+		return j.deleteByPath(path)
+	case step.truncate >= 0:
+		arr, err := j.arrayAt(path)
+		if err != nil {
+			return err
+		}
+		if step.truncate > len(arr) {
+			return fmt.Errorf("array at %s is shorter than recorded", path)
+		}
+		return j.setByPath(path, arr[:step.truncate])
+	default:
+		return j.setByPath(path, step.value)
+	}
+}
+
+// arrayAt 获取指定路径的数组
+func (j *JSON) arrayAt(path string) ([]interface{}, error) {
+	value, err := j.getByPath(path)
+	if err != nil {
+		return nil, err
+	}
+	arr, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("not an array")
+	}
+	return arr, nil
+}
+
+// appendAt 向指定路径的数组追加元素
+func (j *JSON) appendAt(path string, values []interface{}) error {
+	arr, err := j.arrayAt(path)
+	if err != nil {
+		return err
+	}
+	return j.setByPath(path, append(arr, values...))
+}
+
+// splitPath 拆分路径，空路径表示根
+func splitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
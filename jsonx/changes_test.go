@@ -0,0 +1,126 @@
+package jsonx
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const changesFixture = `{
+	"title": "doc",
+	"tags": ["a", "b"],
+	"meta": {"owner": {"name": "tom", "roles": ["admin"]}, "empty": null},
+	"items": [{"id": 1}, {"id": 2}]
+}`
+
+func TestRecorderReplayAndUndo(t *testing.T) {
+	doc := Parse(changesFixture)
+	original := doc.Clone()
+
+	rec := doc.TrackChanges().
+		Set("title", "renamed").
+		Set("meta.owner.name", "jerry").
+		Set("meta.created.by.user", "sys"). // 自动创建中间对象
+		Set("meta.empty.x", 1).             // 替换 null
+		Set("items.-1.id", 20).             // 负数下标
+		Set("items.4.id", 5).               // 扩展数组
+		Append("tags", "c", map[string]interface{}{"k": "v"}).
+		Delete("meta.owner"). // 删除整棵子树
+		Set("", doc.data)     // 根
+
+	if err := rec.Error(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(rec.Changes()); n != 9 {
+		t.Fatalf("recorded %d changes, want 9", n)
+	}
+
+	// 在原始副本上重放得到相同结果
+	replica := original.Clone()
+	if err := rec.ReplayOn(replica); err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(replica, doc) {
+		got, _ := replica.ToJSON()
+		want, _ := doc.ToJSON()
+		t.Fatalf("replay mismatch:\n got  %s\n want %s", got, want)
+	}
+
+	// 分步撤销
+	if err := rec.Undo(1); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Has("meta.owner") {
+		t.Fatal("undoing root set should keep owner deleted")
+	}
+	if err := rec.Undo(1); err != nil {
+		t.Fatal(err)
+	}
+	if got := doc.Get("meta.owner.roles.0").String(); got != "admin" {
+		t.Fatalf("undo delete should restore subtree, got %q", got)
+	}
+
+	// 撤销全部恢复原始文档
+	if err := rec.Undo(len(rec.Changes())); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(doc.data, original.data) {
+		got, _ := doc.ToJSON()
+		t.Fatalf("undo mismatch:\n got  %s", got)
+	}
+	if len(rec.Changes()) != 0 {
+		t.Errorf("changes should be empty after undoing all")
+	}
+}
+
+func TestRecorderChangeLog(t *testing.T) {
+	doc := Parse(`{"a": {"b": 1}}`)
+	value := map[string]interface{}{"x": 1}
+
+	rec := doc.TrackChanges().Set("a.b", value).Set("a.c", 2).Delete("a.b")
+	value["x"] = 99 // 之后修改传入的值不影响日志
+
+	changes := rec.Changes()
+	if changes[0].Op != ChangeSet || changes[0].Old != float64(1) || !changes[0].Existed {
+		t.Errorf("first change = %+v", changes[0])
+	}
+	if !reflect.DeepEqual(changes[0].New, map[string]interface{}{"x": 1}) {
+		t.Errorf("New should be a snapshot, got %v", changes[0].New)
+	}
+	if changes[1].Existed || changes[1].Old != nil {
+		t.Errorf("new key should not have old value: %+v", changes[1])
+	}
+	if changes[2].Op != ChangeDelete || changes[2].New != nil {
+		t.Errorf("delete change = %+v", changes[2])
+	}
+	for i := 1; i < len(changes); i++ {
+		if changes[i].Time.Before(changes[i-1].Time) {
+			t.Error("changes should be ordered by time")
+		}
+	}
+}
+
+func TestRecorderErrors(t *testing.T) {
+	doc := Parse(`{"a": 1, "list": [1]}`)
+	rec := doc.TrackChanges().
+		Set("a.b", 2).    // 标量上设置属性
+		Append("a", 3).   // 非数组
+		Set("list.x", 1). // 数组上的属性
+		Set("ok", true)
+
+	err := rec.Error()
+	if err == nil {
+		t.Fatal("expected errors")
+	}
+	for _, want := range []string{"set a.b", "append a", "set list.x"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+	if n := len(rec.Changes()); n != 1 {
+		t.Errorf("only successful operations should be recorded, got %d", n)
+	}
+	if err := rec.Undo(2); err == nil {
+		t.Error("undoing more changes than recorded should fail")
+	}
+}
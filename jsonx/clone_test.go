@@ -0,0 +1,102 @@
+package jsonx
+
+import (
+	"fmt"
+	"testing"
+)
+
+// largeDocument 构造约 size 个条目的配置类文档
+func largeDocument(size int) *JSON {
+	items := make([]interface{}, 0, size)
+	for i := 0; i < size; i++ {
+		items = append(items, map[string]interface{}{
+			"id":      float64(i),
+			"name":    fmt.Sprintf("item-%d", i),
+			"enabled": i%2 == 0,
+			"tags":    []interface{}{"a", "b", "c"},
+			"limits":  map[string]interface{}{"rate": 1.5, "burst": float64(i % 10), "window": "1m"},
+			"owner":   nil,
+		})
+	}
+	return FromMap(map[string]interface{}{"version": "1", "items": items})
+}
+
+func TestCloneIndependence(t *testing.T) {
+	doc := largeDocument(300)
+	clone := doc.Clone()
+	if !Equal(doc, clone) {
+		t.Fatal("clone should equal the original")
+	}
+
+	// 数组元素从共享块中切出，追加不能覆盖相邻数组
+	tags0 := clone.Get("items.0.tags")
+	tags1 := clone.Get("items.1.tags")
+	clone.Set("items.0.tags", append(tags0.data.([]interface{}), "x"))
+	if tags1.Length() != 3 || clone.Get("items.1.tags.0").String() != "a" {
+		t.Errorf("appending to one cloned array corrupted its neighbour: %v", tags1.data)
+	}
+
+	clone.Set("items.2.limits.rate", 9).Set("version", "2")
+	if doc.Get("items.2.limits.rate").Float64() != 1.5 || doc.Get("version").String() != "1" || doc.Get("items.0.tags").Length() != 3 {
+		t.Error("modifying the clone changed the original")
+	}
+}
+
+func TestCloneEmptyArray(t *testing.T) {
+	// 第一个被克隆的数组为空时也要保持 [] 而不是 null
+	for _, input := range []string{`[]`, `{"list":[]}`, `{"a":[[],[1]]}`} {
+		if got, _ := Parse(input).Clone().ToJSON(); got != input {
+			t.Errorf("Clone(%s) = %s", input, got)
+		}
+	}
+}
+
+func TestCloneShallow(t *testing.T) {
+	doc := Parse(`{"a": 1, "nested": {"b": 2}, "list": [1, 2]}`)
+	shallow := doc.CloneShallow()
+
+	shallow.Set("a", 10).Set("c", 3).Delete("list")
+	if doc.Get("a").Int() != 1 || doc.Has("c") || !doc.Has("list") {
+		t.Error("top-level changes on the shallow clone must not affect the original")
+	}
+
+	// 嵌套值与原文档共享
+	shallow.Set("nested.b", 20)
+	if doc.Get("nested.b").Int() != 20 {
+		t.Error("nested containers are expected to be shared")
+	}
+
+	arr := Parse(`[1, {"x": 1}]`)
+	arrClone := arr.CloneShallow()
+	arrClone.Set("0", 100)
+	if arr.Get("0").Int() != 1 {
+		t.Error("shallow array clone should copy the top level")
+	}
+	if s := Parse(`"text"`).CloneShallow().String(); s != "text" {
+		t.Errorf("scalar CloneShallow = %q", s)
+	}
+	if err := Parse(`{`).CloneShallow().Error(); err == nil {
+		t.Error("CloneShallow should keep the error")
+	}
+}
+
+func BenchmarkClone(b *testing.B) {
+	doc := largeDocument(20000)
+	if s, _ := doc.ToJSON(); b.N == 1 {
+		b.Logf("document size: %d bytes", len(s))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		doc.Clone()
+	}
+}
+
+func BenchmarkCloneShallow(b *testing.B) {
+	doc := largeDocument(20000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		doc.CloneShallow()
+	}
+}
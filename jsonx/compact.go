@@ -0,0 +1,126 @@
+package jsonx
+
+// CompactOption Compact 的选项
+type CompactOption func(*compactOptions)
+
+// compactOptions Compact 选项集合
+type compactOptions struct {
+	emptyStrings bool
+	emptyArrays  bool
+	emptyObjects bool
+}
+
+// DropEmptyStrings 同时删除空字符串
+func DropEmptyStrings() CompactOption {
+	return func(o *compactOptions) { o.emptyStrings = true }
+}
+
+// DropEmptyArrays 同时删除空数组（包括删除元素后变空的数组）
+func DropEmptyArrays() CompactOption {
+	return func(o *compactOptions) { o.emptyArrays = true }
+}
+
+// DropEmptyObjects 同时删除空对象（包括删除字段后变空的对象）
+func DropEmptyObjects() CompactOption {
+	return func(o *compactOptions) { o.emptyObjects = true }
+}
+
+// Compact 递归删除对象中值为 null 的字段与数组中的 null 元素，返回新文档，不修改原文档
+// 根节点始终保留，即使它在清理后为空
+func (j *JSON) Compact(opts ...CompactOption) *JSON {
+	return j.compact(false, opts)
+}
+
+// CompactInPlace 与 Compact 相同，但直接修改当前文档
+func (j *JSON) CompactInPlace(opts ...CompactOption) *JSON {
+	return j.compact(true, opts)
+}
+
+// compact 执行清理
+func (j *JSON) compact(inPlace bool, opts []CompactOption) *JSON {
+	if j.err != nil {
+		return j
+	}
+
+	c := &compactor{inPlace: inPlace}
+	for _, opt := range opts {
+		opt(&c.opts)
+	}
+
+	result, _, err := c.compact(j.data)
+	if err != nil {
+		return &JSON{data: j.data, err: err}
+	}
+	if inPlace {
+		j.data = result
+		return j
+	}
+	return &JSON{data: result}
+}
+
+// compactor 清理过程的状态
+type compactor struct {
+	opts    compactOptions
+	inPlace bool
+	guard   cycleGuard
+}
+
+// compact 返回清理后的值，以及该值是否应保留
+func (c *compactor) compact(v interface{}) (interface{}, bool, error) {
+	switch val := v.(type) {
+	case nil, explicitNull:
+		return nil, false, nil
+	case string:
+		return val, !(c.opts.emptyStrings && val == ""), nil
+	case map[string]interface{}:
+		ptr, err := c.guard.enter(val)
+		if err != nil {
+			return nil, false, err
+		}
+		result := val
+		if !c.inPlace {
+			result = make(map[string]interface{}, len(val))
+		}
+		for k, item := range val {
+			cleaned, keep, err := c.compact(item)
+			if err != nil {
+				return nil, false, err
+			}
+			if keep {
+				result[k] = cleaned
+			} else if c.inPlace {
+				delete(result, k)
+			}
+		}
+		c.guard.leave(ptr)
+		return result, !(c.opts.emptyObjects && len(result) == 0), nil
+	case []interface{}:
+		ptr, err := c.guard.enter(val)
+		if err != nil {
+			return nil, false, err
+		}
+		var result []interface{}
+		if c.inPlace {
+			result = val[:0]
+		} else {
+			result = make([]interface{}, 0, len(val))
+		}
+		for _, item := range val {
+			cleaned, keep, err := c.compact(item)
+			if err != nil {
+				return nil, false, err
+			}
+			if keep {
+				result = append(result, cleaned)
+			}
+		}
+		if c.inPlace {
+			// 清除被移出的尾部元素，避免残留引用
+			clear(val[len(result):])
+		}
+		c.guard.leave(ptr)
+		return result, !(c.opts.emptyArrays && len(result) == 0), nil
+	default:
+		return v, true, nil
+	}
+}
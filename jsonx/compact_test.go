@@ -0,0 +1,84 @@
+package jsonx
+
+import (
+	"testing"
+)
+
+const compactSample = `{
+	"name": "tom",
+	"nick": null,
+	"bio": "",
+	"tags": [],
+	"meta": {},
+	"scores": [1, null, 2],
+	"profile": {"avatar": null, "links": [null], "extra": {"note": ""}},
+	"zero": 0,
+	"off": false
+}`
+
+func TestCompactDefaults(t *testing.T) {
+	doc := Parse(compactSample)
+	original, _ := doc.ToJSON()
+
+	got, _ := doc.Compact().ToJSON()
+	want := `{"bio":"","meta":{},"name":"tom","off":false,"profile":{"extra":{"note":""},"links":[]},"scores":[1,2],"tags":[],"zero":0}`
+	if got != want {
+		t.Errorf("Compact() = %s\nwant        %s", got, want)
+	}
+	if after, _ := doc.ToJSON(); after != original {
+		t.Errorf("Compact must not modify the receiver: %s", after)
+	}
+}
+
+func TestCompactOptions(t *testing.T) {
+	cases := []struct {
+		name string
+		opts []CompactOption
+		want string
+	}{
+		{"strings", []CompactOption{DropEmptyStrings()},
+			`{"meta":{},"name":"tom","off":false,"profile":{"extra":{},"links":[]},"scores":[1,2],"tags":[],"zero":0}`},
+		{"arrays", []CompactOption{DropEmptyArrays()},
+			`{"bio":"","meta":{},"name":"tom","off":false,"profile":{"extra":{"note":""}},"scores":[1,2],"zero":0}`},
+		{"objects", []CompactOption{DropEmptyObjects()},
+			`{"bio":"","name":"tom","off":false,"profile":{"extra":{"note":""},"links":[]},"scores":[1,2],"tags":[],"zero":0}`},
+		// 级联：extra 清空字符串后变为空对象，profile 随之变空
+		{"all", []CompactOption{DropEmptyStrings(), DropEmptyArrays(), DropEmptyObjects()},
+			`{"name":"tom","off":false,"scores":[1,2],"zero":0}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, _ := Parse(compactSample).Compact(tc.opts...).ToJSON()
+			if got != tc.want {
+				t.Errorf("got  %s\nwant %s", got, tc.want)
+			}
+		})
+	}
+
+	// 根节点即使为空也保留
+	if got, _ := Parse(`{"a": null}`).Compact(DropEmptyObjects()).ToJSON(); got != `{}` {
+		t.Errorf("root should be kept, got %s", got)
+	}
+	if got, _ := Parse(`[null, {"a": null}, [null]]`).Compact(DropEmptyObjects(), DropEmptyArrays()).ToJSON(); got != `[]` {
+		t.Errorf("array root = %s", got)
+	}
+}
+
+func TestCompactInPlace(t *testing.T) {
+	doc := Parse(compactSample)
+	profile := doc.Get("profile").data.(map[string]interface{})
+
+	if doc.CompactInPlace(DropEmptyStrings(), DropEmptyArrays(), DropEmptyObjects()) != doc {
+		t.Error("CompactInPlace should return the receiver")
+	}
+	if got, _ := doc.ToJSON(); got != `{"name":"tom","off":false,"scores":[1,2],"zero":0}` {
+		t.Errorf("CompactInPlace = %s", got)
+	}
+	if _, exists := profile["avatar"]; exists {
+		t.Error("nested containers should be modified in place")
+	}
+
+	if err := FromMap(newCyclicMap()).Compact().Error(); err == nil {
+		t.Error("cyclic data should be reported")
+	}
+}
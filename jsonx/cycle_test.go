@@ -0,0 +1,64 @@
+package jsonx
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// newCyclicMap 构造直接和间接自引用的数据
+func newCyclicMap() map[string]interface{} {
+	root := map[string]interface{}{"name": "root"}
+	child := map[string]interface{}{"parent": root}
+	root["self"] = root
+	root["items"] = []interface{}{child}
+	return root
+}
+
+func TestCyclicDataGuard(t *testing.T) {
+	start := time.Now()
+
+	if err := FromMap(newCyclicMap()).Clone().Error(); !errors.Is(err, ErrCyclicData) {
+		t.Errorf("Clone: expected ErrCyclicData, got %v", err)
+	}
+
+	merged := Object().Set("name", "dst").DeepMerge(FromMap(newCyclicMap()))
+	if !errors.Is(merged.Error(), ErrCyclicData) {
+		t.Errorf("DeepMerge: expected ErrCyclicData, got %v", merged.Error())
+	}
+
+	j := FromMap(newCyclicMap())
+	if flat := Flatten(j); len(flat) != 0 || !errors.Is(j.Error(), ErrCyclicData) {
+		t.Errorf("Flatten: expected empty result and ErrCyclicData, got %d entries, %v", len(flat), j.Error())
+	}
+
+	j = FromMap(newCyclicMap())
+	if depth := Depth(j); depth != -1 || !errors.Is(j.Error(), ErrCyclicData) {
+		t.Errorf("Depth: expected -1 and ErrCyclicData, got %d, %v", depth, j.Error())
+	}
+
+	if _, err := FromMap(newCyclicMap()).ToJSON(); !errors.Is(err, ErrCyclicData) {
+		t.Errorf("ToJSON: expected ErrCyclicData, got %v", err)
+	}
+
+	if err := Safe(FromMap(newCyclicMap())).Get("self").Error(); !errors.Is(err, ErrCyclicData) {
+		t.Errorf("SafeJSON.Get: expected ErrCyclicData, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("cycle detection took too long: %v", elapsed)
+	}
+}
+
+func TestSharedReferencesAreNotCycles(t *testing.T) {
+	shared := map[string]interface{}{"v": 1}
+	j := FromMap(map[string]interface{}{"a": shared, "b": shared})
+
+	clone := j.Clone()
+	if clone.Error() != nil || clone.Get("b.v").Int() != 1 {
+		t.Errorf("shared reference should clone cleanly, got %v", clone.Error())
+	}
+	if depth := Depth(j); depth != 2 || j.Error() != nil {
+		t.Errorf("Depth = %d, err = %v", depth, j.Error())
+	}
+}
@@ -0,0 +1,199 @@
+package jsonx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 可控序列化
+
+// EncodeOptions 序列化选项
+type EncodeOptions struct {
+	// Indent 缩进字符串，为空时紧凑输出
+	Indent string
+	// NumberFormat 数值格式化策略，为 nil 时与 encoding/json 一致
+	NumberFormat *NumberFormat
+}
+
+// NumberFormat 数值格式化策略
+type NumberFormat struct {
+	// NoExponent 从不使用科学计数法，整数值不带小数部分
+	NoExponent bool
+	// decimals 按路径固定小数位数
+	decimals []numberFormatRule
+}
+
+// numberFormatRule 单条路径的小数位规则
+type numberFormatRule struct {
+	pattern  []string
+	decimals int
+}
+
+// NewNumberFormat 创建不使用科学计数法的数值格式化策略
+func NewNumberFormat() *NumberFormat {
+	return &NumberFormat{NoExponent: true}
+}
+
+// FormatNumber 为指定路径的数值固定小数位数，片段 "*" 匹配任意键或索引，如 "items.*.price"
+// 同一路径匹配多条规则时以最后添加的为准
+func (f *NumberFormat) FormatNumber(path string, decimals int) *NumberFormat {
+	f.decimals = append(f.decimals, numberFormatRule{
+		pattern:  strings.Split(path, "."),
+		decimals: decimals,
+	})
+	return f
+}
+
+// rule 查找路径对应的小数位数
+func (f *NumberFormat) rule(path []string) (int, bool) {
+	for i := len(f.decimals) - 1; i >= 0; i-- {
+		if matchPathPattern(f.decimals[i].pattern, path) {
+			return f.decimals[i].decimals, true
+		}
+	}
+	return 0, false
+}
+
+// Encode 按选项序列化为 JSON
+func (j *JSON) Encode(opts EncodeOptions) ([]byte, error) {
+	if j.err != nil {
+		return nil, j.err
+	}
+
+	if opts.NumberFormat == nil {
+		if opts.Indent != "" {
+			return json.MarshalIndent(j.data, "", opts.Indent)
+		}
+		return marshalJSON(j.data, false)
+	}
+
+	var buf bytes.Buffer
+	e := &encoder{buf: &buf, numbers: opts.NumberFormat, guard: &cycleGuard{}}
+	if err := e.encode(j.data, nil); err != nil {
+		return nil, err
+	}
+
+	if opts.Indent == "" {
+		return buf.Bytes(), nil
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, buf.Bytes(), "", opts.Indent); err != nil {
+		return nil, err
+	}
+	return indented.Bytes(), nil
+}
+
+// encoder 逐值写出 JSON，对数值叶子应用格式化策略
+type encoder struct {
+	buf     *bytes.Buffer
+	numbers *NumberFormat
+	guard   *cycleGuard
+}
+
+// encode 递归写出值，path 为当前位置的路径片段
+func (e *encoder) encode(v interface{}, path []string) error {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		ptr, err := e.guard.enter(val)
+		if err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		e.buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				e.buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(k)
+			e.buf.Write(key)
+			e.buf.WriteByte(':')
+			if err := e.encode(val[k], append(path, k)); err != nil {
+				return err
+			}
+		}
+		e.buf.WriteByte('}')
+		e.guard.leave(ptr)
+		return nil
+
+	case []interface{}:
+		ptr, err := e.guard.enter(val)
+		if err != nil {
+			return err
+		}
+		e.buf.WriteByte('[')
+		for i, item := range val {
+			if i > 0 {
+				e.buf.WriteByte(',')
+			}
+			if err := e.encode(item, append(path, strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+		e.buf.WriteByte(']')
+		e.guard.leave(ptr)
+		return nil
+
+	case float64, float32:
+		f, _ := toFloat64(val)
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Errorf("json: unsupported value: %v", f)
+		}
+		if decimals, ok := e.numbers.rule(path); ok {
+			e.buf.WriteString(strconv.FormatFloat(f, 'f', decimals, 64))
+			return nil
+		}
+		if e.numbers.NoExponent {
+			bitSize := 64
+			if _, isFloat32 := val.(float32); isFloat32 {
+				bitSize = 32
+			}
+			e.buf.WriteString(strconv.FormatFloat(f, 'f', -1, bitSize))
+			return nil
+		}
+
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, json.Number:
+		// 整数仅在命中路径规则时转换为浮点格式化，否则原样输出以保留大整数精度
+		f, ok := toFloat64(val)
+		if !ok {
+			break
+		}
+		if decimals, ok := e.numbers.rule(path); ok {
+			e.buf.WriteString(strconv.FormatFloat(f, 'f', decimals, 64))
+			return nil
+		}
+		if n, isNumber := val.(json.Number); isNumber && e.numbers.NoExponent && strings.ContainsAny(string(n), "eE") {
+			e.buf.WriteString(strconv.FormatFloat(f, 'f', -1, 64))
+			return nil
+		}
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	e.buf.Write(data)
+	return nil
+}
+
+// matchPathPattern 判断路径是否匹配模式，片段 "*" 匹配任意键或索引
+func matchPathPattern(pattern, path []string) bool {
+	if len(pattern) != len(path) {
+		return false
+	}
+	for i := range pattern {
+		if pattern[i] != "*" && pattern[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package jsonx
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEncodeNumberFormat(t *testing.T) {
+	j := New(map[string]interface{}{
+		"big":   1e21,
+		"id":    int64(9007199254740993),
+		"tiny":  0.000000123,
+		"whole": 100.0,
+		"items": []interface{}{
+			map[string]interface{}{"price": 19.9, "qty": 3},
+			map[string]interface{}{"price": 5, "qty": 1},
+		},
+		"total": 64.7,
+		"rate":  float32(0.1),
+	})
+
+	tests := []struct {
+		name     string
+		format   *NumberFormat
+		expected string
+	}{
+		{
+			name:     "default",
+			format:   nil,
+			expected: `{"big":1e+21,"id":9007199254740993,"items":[{"price":19.9,"qty":3},{"price":5,"qty":1}],"rate":0.1,"tiny":1.23e-7,"total":64.7,"whole":100}`,
+		},
+		{
+			name:     "no exponent",
+			format:   NewNumberFormat(),
+			expected: `{"big":1000000000000000000000,"id":9007199254740993,"items":[{"price":19.9,"qty":3},{"price":5,"qty":1}],"rate":0.1,"tiny":0.000000123,"total":64.7,"whole":100}`,
+		},
+		{
+			name:     "per-path decimals",
+			format:   NewNumberFormat().FormatNumber("items.*.price", 2).FormatNumber("total", 2).FormatNumber("tiny", 4),
+			expected: `{"big":1000000000000000000000,"id":9007199254740993,"items":[{"price":19.90,"qty":3},{"price":5.00,"qty":1}],"rate":0.1,"tiny":0.0000,"total":64.70,"whole":100}`,
+		},
+	}
+
+	for _, test := range tests {
+		data, err := j.Encode(EncodeOptions{NumberFormat: test.format})
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if string(data) != test.expected {
+			t.Errorf("%s:\n got  %s\n want %s", test.name, data, test.expected)
+		}
+		if !json.Valid(data) {
+			t.Errorf("%s: output is not valid JSON", test.name)
+		}
+	}
+}
+
+func TestEncodeOptionsIndentAndErrors(t *testing.T) {
+	j := Parse(`{"a":[1.5,2]}`)
+	data, err := j.Encode(EncodeOptions{Indent: "  ", NumberFormat: NewNumberFormat().FormatNumber("a.0", 3)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "{\n  \"a\": [\n    1.500,\n    2\n  ]\n}"
+	if string(data) != expected {
+		t.Errorf("got %q, want %q", data, expected)
+	}
+
+	if _, err := Parse(`{bad`).Encode(EncodeOptions{}); err == nil {
+		t.Error("Encode should propagate chain errors")
+	}
+}
+
+func TestSerializedBuffersAreIndependent(t *testing.T) {
+	doc := Parse(`{"name":"tom","tags":["a","b"]}`)
+	const want = `{"name":"tom","tags":["a","b"]}`
+
+	// 修改 ToBytes / GetRaw 返回的缓冲区不影响文档和之后的序列化结果
+	for _, get := range []func() ([]byte, error){
+		doc.ToBytes,
+		func() ([]byte, error) { return doc.GetRaw("") },
+		func() ([]byte, error) { return doc.Encode(EncodeOptions{}) },
+	} {
+		b, err := get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range b {
+			b[i] = 'X'
+		}
+	}
+
+	s, err := doc.ToJSON()
+	if err != nil || s != want {
+		t.Fatalf("ToJSON after mutating buffers = %s, %v", s, err)
+	}
+	// 通过 []byte 转换修改 ToJSON 的结果也不影响已返回的字符串
+	b := []byte(s)
+	b[2] = 'X'
+	if s != want || doc.MustJSON() != want {
+		t.Errorf("ToJSON result changed to %s", s)
+	}
+
+	// 解析时的输入缓冲区被复用或修改后，文档内容保持不变
+	input := []byte(`{"k":"value"}`)
+	parsed := ParseBytes(input)
+	limited := ParseWithLimits(string(input), ParseLimits{MaxDepth: 8})
+	copy(input, `{"k":"XXXXX"}`)
+	if parsed.Get("k").String() != "value" || limited.Get("k").String() != "value" {
+		t.Errorf("documents should not alias the input buffer: %s, %s", parsed.MustJSON(), limited.MustJSON())
+	}
+}
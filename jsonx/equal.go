@@ -0,0 +1,139 @@
+package jsonx
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// 结构化比较
+
+// EqualOptions 结构化比较选项
+type EqualOptions struct {
+	// NumericTolerance 数值比较的绝对容差，0 表示数值相等即可（1 与 1.0 始终相等）
+	NumericTolerance float64
+	// IgnorePaths 忽略比较的路径，片段 "*" 匹配任意键或索引，如 "items.*.updatedAt"
+	IgnorePaths []string
+	// NullEqualsMissing 为 true 时，对象中值为 null 的字段与缺失字段视为相等
+	NullEqualsMissing bool
+}
+
+// Equal 结构化比较两个 JSON 是否相等，与键顺序和数值的具体类型无关
+func Equal(a, b *JSON) bool {
+	return EqualWithOptions(a, b, EqualOptions{})
+}
+
+// EqualWithOptions 按选项结构化比较两个 JSON，任一方带有错误时返回 false
+func EqualWithOptions(a, b *JSON, opts EqualOptions) bool {
+	if a == nil || b == nil || a.err != nil || b.err != nil {
+		return false
+	}
+
+	ignore := make([][]string, 0, len(opts.IgnorePaths))
+	for _, path := range opts.IgnorePaths {
+		ignore = append(ignore, strings.Split(path, "."))
+	}
+
+	c := &equalComparer{opts: opts, ignore: ignore}
+	return c.equal(a.data, b.data, nil)
+}
+
+// EqualsTo 结构化比较当前 JSON 与另一个 JSON 是否相等
+func (j *JSON) EqualsTo(other *JSON) bool {
+	return Equal(j, other)
+}
+
+// equalComparer 结构化比较器
+type equalComparer struct {
+	opts   EqualOptions
+	ignore [][]string
+}
+
+// equal 递归比较两个值，path 为当前位置的路径片段；嵌套过深时视为不相等
+func (c *equalComparer) equal(a, b interface{}, path []string) bool {
+	if len(path) > maxNestingDepth {
+		return false
+	}
+	if c.ignored(path) {
+		return true
+	}
+
+	if isNullValue(a) || isNullValue(b) {
+		return isNullValue(a) && isNullValue(b)
+	}
+
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		return c.equalObjects(av, bv, path)
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !c.equal(av[i], bv[i], append(path, strconv.Itoa(i))) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if af, ok := toFloat64(a); ok {
+		bf, ok := toFloat64(b)
+		if !ok {
+			return false
+		}
+		if c.opts.NumericTolerance > 0 {
+			return math.Abs(af-bf) <= c.opts.NumericTolerance
+		}
+		return af == bf
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// equalObjects 比较两个对象的所有键
+func (c *equalComparer) equalObjects(a, b map[string]interface{}, path []string) bool {
+	for key, av := range a {
+		bv, exists := b[key]
+		if !exists {
+			if (c.opts.NullEqualsMissing && isNullValue(av)) || c.ignored(append(path, key)) {
+				continue
+			}
+			return false
+		}
+		if !c.equal(av, bv, append(path, key)) {
+			return false
+		}
+	}
+
+	for key, bv := range b {
+		if _, exists := a[key]; exists {
+			continue
+		}
+		if (c.opts.NullEqualsMissing && isNullValue(bv)) || c.ignored(append(path, key)) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// ignored 判断路径是否在忽略列表中
+func (c *equalComparer) ignored(path []string) bool {
+	if len(path) == 0 {
+		return false
+	}
+
+	for _, pattern := range c.ignore {
+		if matchPathPattern(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
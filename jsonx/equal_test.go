@@ -0,0 +1,59 @@
+package jsonx
+
+import "testing"
+
+func TestEqual(t *testing.T) {
+	a := Parse(`{"name":"tom","tags":["a","b"],"meta":{"x":1,"y":null}}`)
+	b := Parse(`{"meta":{"y":null,"x":1.0},"tags":["a","b"],"name":"tom"}`)
+
+	if !Equal(a, b) || !a.EqualsTo(b) || !Compare(a, b) {
+		t.Error("documents differing only in key order should be equal")
+	}
+	if !Equal(New(map[string]interface{}{"n": 1}), Parse(`{"n":1.0}`)) {
+		t.Error("int 1 and float 1.0 should be equal")
+	}
+	if Equal(a, Parse(`{"name":"tom","tags":["b","a"],"meta":{"x":1,"y":null}}`)) {
+		t.Error("array order should matter")
+	}
+	if Equal(a, Parse(`{"name":"tom","tags":["a","b"],"meta":{"x":"1","y":null}}`)) {
+		t.Error("number and string should not be equal")
+	}
+	if Equal(a, Parse(`{invalid`)) {
+		t.Error("document with error should not be equal")
+	}
+}
+
+func TestEqualWithOptions(t *testing.T) {
+	a := Parse(`{"price":10.0,"items":[{"id":1,"updatedAt":"2024-01-01"}],"note":null}`)
+	b := Parse(`{"price":10.004,"items":[{"id":1,"updatedAt":"2025-06-30"}]}`)
+
+	if Equal(a, b) {
+		t.Fatal("documents should differ without options")
+	}
+
+	opts := EqualOptions{
+		NumericTolerance:  0.01,
+		IgnorePaths:       []string{"items.*.updatedAt"},
+		NullEqualsMissing: true,
+	}
+	if !EqualWithOptions(a, b, opts) {
+		t.Error("documents should be equal with tolerance, ignored paths and null-as-missing")
+	}
+
+	strict := opts
+	strict.NullEqualsMissing = false
+	if EqualWithOptions(a, b, strict) {
+		t.Error("null and missing should differ when NullEqualsMissing is false")
+	}
+
+	tight := opts
+	tight.NumericTolerance = 0.001
+	if EqualWithOptions(a, b, tight) {
+		t.Error("price difference should exceed tolerance")
+	}
+
+	ignoreMissing := EqualOptions{IgnorePaths: []string{"extra"}}
+	if !EqualWithOptions(Parse(`{"a":1}`), Parse(`{"a":1,"extra":true}`), ignoreMissing) {
+		t.Error("ignored path present on one side only should be skipped")
+	}
+}
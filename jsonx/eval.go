@@ -0,0 +1,685 @@
+package jsonx
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// jq 风格的简单表达式
+//
+// Eval 支持 jq 的一个很小的子集，用于运维工具中的取值与筛选：
+//
+//	pipeline  = stage { "|" stage }
+//	stage     = path | "length" | "keys" | "select" "(" condition ")"
+//	path      = "." [ name | index ] { "." name | index }
+//	name      = identifier | string
+//	index     = "[" [ number | string ] "]"
+//	condition = path [ op literal ]
+//	op        = "==" | "!=" | ">" | ">=" | "<" | "<="
+//	literal   = number | string | "true" | "false" | "null"
+//
+// identifier 为 [A-Za-z_][A-Za-z0-9_]*，含其他字符的键写作字符串，如 ."first-name" 或 .["first-name"]；
+// string 为 JSON 字符串字面量，number 为十进制数（可带负号与小数部分）。
+//
+// 求值语义与 jq 一致（select 除外）：
+//   - .a 取对象字段，字段不存在或值为 null 时得到 null；对其他类型取字段是 *TypeError
+//   - [n] 取数组元素，负数从末尾倒数，越界得到 null；["k"] 等同于 ."k"
+//   - [] 展开数组的每个元素（对象按键排序后展开值），后续阶段分别作用于每个元素
+//   - length：数组与对象为元素个数，字符串为字符数，null 为 0，数值为绝对值
+//   - keys：对象为排序后的键，数组为下标
+//   - select(cond)：条件成立时保留输入，否则丢弃；条件路径产生多个值时任一成立即保留。
+//     与 jq 不同，条件路径在该输入上求值出错（如对字符串取字段）时视为不成立而不是报错。
+//     没有 op 时路径的值不为 null 且不为 false 即成立；== 与 != 按 Equal 结构化比较，
+//     其他比较按 SortBy 的类型顺序（null < 布尔 < 数值 < 字符串）
+//
+// 表达式中出现 [] 时结果总是数组（没有输出时为空数组），否则结果为单个值，select 丢弃时为 null。
+// 与 Get 相同，结果与原文档共享数据。
+
+// ExprError 表达式语法错误，给出出错位置与该位置允许的记号
+type ExprError struct {
+	Expr     string   // 原始表达式
+	Pos      int      // 出错位置（从 0 开始的字节偏移）
+	Found    string   // 实际遇到的记号
+	Expected []string // 该位置允许的记号
+}
+
+// Error 实现 error 接口
+func (e *ExprError) Error() string {
+	return fmt.Sprintf("eval: unexpected %s at position %d in %q, expected %s",
+		e.Found, e.Pos, e.Expr, strings.Join(e.Expected, " or "))
+}
+
+// Eval 对文档求值 jq 风格的表达式，语法见上方说明
+//
+//	names, err := doc.Eval(".company.departments[] | select(.active == true) | .name")
+//
+// 语法错误为 *ExprError，求值时的类型错误为 *TypeError
+func (j *JSON) Eval(expr string) (*JSON, error) {
+	if j.err != nil {
+		return nil, j.err
+	}
+
+	prog, err := parseExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := []evalValue{{data: j.data, path: j.path}}
+	for _, stage := range prog.stages {
+		var next []evalValue
+		for _, in := range outputs {
+			out, err := stage.eval(in)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, out...)
+		}
+		outputs = next
+	}
+
+	if prog.iterates {
+		result := make([]interface{}, len(outputs))
+		for i, out := range outputs {
+			result[i] = out.data
+		}
+		return &JSON{data: result}, nil
+	}
+	if len(outputs) == 0 {
+		return &JSON{}, nil
+	}
+	return &JSON{data: outputs[0].data, path: outputs[0].path}, nil
+}
+
+// evalValue 求值过程中的值及其相对根的路径，路径用于错误信息
+type evalValue struct {
+	data interface{}
+	path string
+}
+
+// evalStage 管道中的一个阶段，一个输入可以产生零个或多个输出
+type evalStage interface {
+	eval(in evalValue) ([]evalValue, error)
+}
+
+// evalProgram 解析后的表达式
+type evalProgram struct {
+	stages   []evalStage
+	iterates bool // 是否含有 []，决定结果是否收集为数组
+}
+
+// 路径步骤的类型
+const (
+	stepField = iota
+	stepIndex
+	stepIterate
+)
+
+// pathStep 路径中的一步
+type pathStep struct {
+	kind  int
+	name  string
+	index int
+}
+
+// pathStage 路径阶段，如 .items[].name
+type pathStage struct {
+	steps []pathStep
+}
+
+func (s pathStage) eval(in evalValue) ([]evalValue, error) {
+	current := []evalValue{in}
+	for _, step := range s.steps {
+		var next []evalValue
+		for _, v := range current {
+			out, err := step.apply(v)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, out...)
+		}
+		current = next
+	}
+	return current, nil
+}
+
+// apply 对单个值执行路径步骤
+func (step pathStep) apply(v evalValue) ([]evalValue, error) {
+	switch step.kind {
+	case stepField:
+		switch data := v.data.(type) {
+		case map[string]interface{}:
+			return []evalValue{{data: data[step.name], path: joinJSONPath(v.path, step.name)}}, nil
+		case nil:
+			return []evalValue{{path: joinJSONPath(v.path, step.name)}}, nil
+		}
+		return nil, (&JSON{data: v.data, path: v.path}).typeError("object")
+
+	case stepIndex:
+		switch data := v.data.(type) {
+		case []interface{}:
+			path := joinJSONPath(v.path, strconv.Itoa(step.index))
+			if i, ok := resolveIndex(step.index, len(data)); ok {
+				return []evalValue{{data: data[i], path: path}}, nil
+			}
+			return []evalValue{{path: path}}, nil
+		case nil:
+			return []evalValue{{path: joinJSONPath(v.path, strconv.Itoa(step.index))}}, nil
+		}
+		return nil, (&JSON{data: v.data, path: v.path}).typeError("array")
+
+	default:
+		switch data := v.data.(type) {
+		case []interface{}:
+			out := make([]evalValue, len(data))
+			for i, item := range data {
+				out[i] = evalValue{data: item, path: joinJSONPath(v.path, strconv.Itoa(i))}
+			}
+			return out, nil
+		case map[string]interface{}:
+			keys := sortedKeys(data)
+			out := make([]evalValue, len(keys))
+			for i, key := range keys {
+				out[i] = evalValue{data: data[key], path: joinJSONPath(v.path, key)}
+			}
+			return out, nil
+		}
+		return nil, (&JSON{data: v.data, path: v.path}).typeError("array or object")
+	}
+}
+
+// lengthStage 内置函数 length
+type lengthStage struct{}
+
+func (lengthStage) eval(in evalValue) ([]evalValue, error) {
+	var n float64
+	switch data := in.data.(type) {
+	case []interface{}:
+		n = float64(len(data))
+	case map[string]interface{}:
+		n = float64(len(data))
+	case string:
+		n = float64(utf8.RuneCountInString(data))
+	case nil:
+		n = 0
+	default:
+		f, ok := toFloat64(data)
+		if !ok {
+			return nil, (&JSON{data: in.data, path: in.path}).typeError("array, object, string or number")
+		}
+		n = math.Abs(f)
+	}
+	return []evalValue{{data: n}}, nil
+}
+
+// keysStage 内置函数 keys
+type keysStage struct{}
+
+func (keysStage) eval(in evalValue) ([]evalValue, error) {
+	switch data := in.data.(type) {
+	case map[string]interface{}:
+		keys := sortedKeys(data)
+		result := make([]interface{}, len(keys))
+		for i, key := range keys {
+			result[i] = key
+		}
+		return []evalValue{{data: result}}, nil
+	case []interface{}:
+		result := make([]interface{}, len(data))
+		for i := range data {
+			result[i] = float64(i)
+		}
+		return []evalValue{{data: result}}, nil
+	}
+	return nil, (&JSON{data: in.data, path: in.path}).typeError("object or array")
+}
+
+// selectStage 内置函数 select
+type selectStage struct {
+	path    pathStage
+	op      string // 为空时按真值判断
+	literal interface{}
+}
+
+func (s selectStage) eval(in evalValue) ([]evalValue, error) {
+	values, err := s.path.eval(in)
+	if err != nil {
+		// 条件路径无法作用于该值（如对字符串取字段）时条件不成立，便于在混合类型的数组中筛选
+		return nil, nil
+	}
+	for _, v := range values {
+		if s.holds(v.data) {
+			return []evalValue{in}, nil
+		}
+	}
+	return nil, nil
+}
+
+// holds 判断单个值是否满足条件
+func (s selectStage) holds(v interface{}) bool {
+	switch s.op {
+	case "":
+		return v != nil && v != false
+	case "==":
+		return (&equalComparer{}).equal(v, s.literal, nil)
+	case "!=":
+		return !(&equalComparer{}).equal(v, s.literal, nil)
+	}
+
+	cmp := compareJSONValues(v, s.literal)
+	switch s.op {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	default:
+		return cmp <= 0
+	}
+}
+
+// sortedKeys 返回对象排序后的键
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// 词法分析
+
+// 记号类型
+const (
+	tokEOF = iota
+	tokIllegal
+	tokDot
+	tokLBracket
+	tokRBracket
+	tokLParen
+	tokRParen
+	tokPipe
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+)
+
+// tokenNames 记号类型在错误信息中的名称，标点记号直接显示其文本
+var tokenNames = map[int]string{
+	tokEOF:      "end of expression",
+	tokDot:      `"."`,
+	tokLBracket: `"["`,
+	tokRBracket: `"]"`,
+	tokLParen:   `"("`,
+	tokRParen:   `")"`,
+	tokPipe:     `"|"`,
+	tokIdent:    "identifier",
+	tokString:   "string",
+	tokNumber:   "number",
+	tokOp:       "comparison operator",
+}
+
+// punctuation 单字符标点对应的记号类型
+var punctuation = map[byte]int{'.': tokDot, '[': tokLBracket, ']': tokRBracket, '(': tokLParen, ')': tokRParen, '|': tokPipe}
+
+// token 记号
+type token struct {
+	kind int
+	text string
+	pos  int
+}
+
+// describe 返回记号在错误信息中的描述
+func (t token) describe() string {
+	switch t.kind {
+	case tokEOF:
+		return tokenNames[tokEOF]
+	case tokIllegal:
+		return t.text
+	}
+	return strconv.Quote(t.text)
+}
+
+// lexExpr 将表达式拆分为记号，非法字符与未闭合的字符串产生 tokIllegal，由语法分析报告
+func lexExpr(expr string) []token {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case punctuation[c] != 0:
+			tokens = append(tokens, token{kind: punctuation[c], text: string(c), pos: i})
+			i++
+			continue
+		case c == '=' || c == '!' || c == '<' || c == '>':
+			end := i + 1
+			if end < len(expr) && expr[end] == '=' {
+				end++
+			}
+			text := expr[i:end]
+			kind := tokOp
+			if text == "=" || text == "!" {
+				kind = tokIllegal
+				text = strconv.Quote(text)
+			}
+			tokens = append(tokens, token{kind: kind, text: text, pos: i})
+			i = end
+			continue
+		case c == '"':
+			end := i + 1
+			for end < len(expr) && expr[end] != '"' {
+				if expr[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(expr) {
+				tokens = append(tokens, token{kind: tokIllegal, text: "unterminated string", pos: i})
+				return append(tokens, token{kind: tokEOF, pos: len(expr)})
+			}
+			tokens = append(tokens, token{kind: tokString, text: expr[i : end+1], pos: i})
+			i = end + 1
+			continue
+		case c == '-' || isDigit(c):
+			end := i + 1
+			for end < len(expr) && (isDigit(expr[end]) || expr[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: expr[i:end], pos: i})
+			i = end
+			continue
+		case isIdentStart(c):
+			end := i + 1
+			for end < len(expr) && (isIdentStart(expr[end]) || isDigit(expr[end])) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: expr[i:end], pos: i})
+			i = end
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(expr[i:])
+		tokens = append(tokens, token{kind: tokIllegal, text: strconv.Quote(string(r)), pos: i})
+		i += size
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(expr)})
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// 语法分析
+
+// exprParser 递归下降分析器，expected 累积当前位置尝试过的记号，用于错误信息
+type exprParser struct {
+	expr     string
+	tokens   []token
+	pos      int
+	expected []string
+	iterates bool
+}
+
+// parseExpr 解析表达式
+func parseExpr(expr string) (*evalProgram, error) {
+	p := &exprParser{expr: expr, tokens: lexExpr(expr)}
+
+	var stages []evalStage
+	for {
+		stage, err := p.stage()
+		if err != nil {
+			return nil, err
+		}
+		stages = append(stages, stage)
+		if !p.accept(tokPipe) {
+			break
+		}
+	}
+	if !p.peekIs(tokEOF) {
+		return nil, p.fail()
+	}
+	return &evalProgram{stages: stages, iterates: p.iterates}, nil
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// peekIs 判断下一个记号的类型，不匹配时记录为期望的记号
+func (p *exprParser) peekIs(kind int) bool {
+	if p.peek().kind == kind {
+		return true
+	}
+	p.expect(tokenNames[kind])
+	return false
+}
+
+// peekWord 判断下一个记号是否为指定的关键字
+func (p *exprParser) peekWord(word string) bool {
+	if tok := p.peek(); tok.kind == tokIdent && tok.text == word {
+		return true
+	}
+	p.expect(strconv.Quote(word))
+	return false
+}
+
+// accept 下一个记号为指定类型时消费它
+func (p *exprParser) accept(kind int) bool {
+	if !p.peekIs(kind) {
+		return false
+	}
+	p.next()
+	return true
+}
+
+// next 消费一个记号并清空期望列表
+func (p *exprParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	p.expected = p.expected[:0]
+	return tok
+}
+
+// expect 记录期望的记号，去重并保持顺序
+func (p *exprParser) expect(name string) {
+	for _, e := range p.expected {
+		if e == name {
+			return
+		}
+	}
+	p.expected = append(p.expected, name)
+}
+
+// fail 在当前记号处生成语法错误
+func (p *exprParser) fail() error {
+	tok := p.peek()
+	return &ExprError{
+		Expr:     p.expr,
+		Pos:      tok.pos,
+		Found:    tok.describe(),
+		Expected: append([]string(nil), p.expected...),
+	}
+}
+
+// stage 解析管道中的一个阶段
+func (p *exprParser) stage() (evalStage, error) {
+	if p.peekIs(tokDot) {
+		path, err := p.path(true)
+		if err != nil {
+			return nil, err
+		}
+		return path, nil
+	}
+	switch {
+	case p.peekWord("select"):
+		p.next()
+		return p.selectCall()
+	case p.peekWord("length"):
+		p.next()
+		return lengthStage{}, nil
+	case p.peekWord("keys"):
+		p.next()
+		return keysStage{}, nil
+	}
+	return nil, p.fail()
+}
+
+// path 解析路径，下一个记号必须是 "."；topLevel 为 false 时（select 条件中）不影响结果形状
+func (p *exprParser) path(topLevel bool) (pathStage, error) {
+	var s pathStage
+	p.next() // "."
+
+	// 开头的 "." 之后可以直接跟字段名或下标，也可以是单独的 "."
+	if p.peekIs(tokIdent) || p.peekIs(tokString) {
+		step, err := p.fieldName()
+		if err != nil {
+			return s, err
+		}
+		s.steps = append(s.steps, step)
+	}
+
+	for {
+		switch {
+		case p.peekIs(tokLBracket):
+			step, err := p.bracket()
+			if err != nil {
+				return s, err
+			}
+			if step.kind == stepIterate && topLevel {
+				p.iterates = true
+			}
+			s.steps = append(s.steps, step)
+		case p.accept(tokDot):
+			if !p.peekIs(tokIdent) && !p.peekIs(tokString) {
+				return s, p.fail()
+			}
+			step, err := p.fieldName()
+			if err != nil {
+				return s, err
+			}
+			s.steps = append(s.steps, step)
+		default:
+			return s, nil
+		}
+	}
+}
+
+// fieldName 解析字段名，下一个记号必须是标识符或字符串
+func (p *exprParser) fieldName() (pathStep, error) {
+	tok := p.peek()
+	if tok.kind == tokIdent {
+		p.next()
+		return pathStep{kind: stepField, name: tok.text}, nil
+	}
+	name, err := p.stringLiteral()
+	return pathStep{kind: stepField, name: name}, err
+}
+
+// bracket 解析 "[" [ number | string ] "]"，下一个记号必须是 "["
+func (p *exprParser) bracket() (pathStep, error) {
+	p.next() // "["
+	step := pathStep{kind: stepIterate}
+
+	switch tok := p.peek(); {
+	case p.peekIs(tokNumber):
+		index, err := strconv.Atoi(tok.text)
+		if err != nil {
+			p.expected = []string{"integer"}
+			return step, p.fail()
+		}
+		p.next()
+		step = pathStep{kind: stepIndex, index: index}
+	case p.peekIs(tokString):
+		name, err := p.stringLiteral()
+		if err != nil {
+			return step, err
+		}
+		step = pathStep{kind: stepField, name: name}
+	}
+
+	if !p.accept(tokRBracket) {
+		return step, p.fail()
+	}
+	return step, nil
+}
+
+// stringLiteral 解析 JSON 字符串字面量，下一个记号必须是字符串
+func (p *exprParser) stringLiteral() (string, error) {
+	tok := p.peek()
+	var s string
+	if err := json.Unmarshal([]byte(tok.text), &s); err != nil {
+		p.expected = []string{"valid string"}
+		return "", p.fail()
+	}
+	p.next()
+	return s, nil
+}
+
+// selectCall 解析 select 的参数 "(" condition ")"
+func (p *exprParser) selectCall() (evalStage, error) {
+	if !p.accept(tokLParen) {
+		return nil, p.fail()
+	}
+	if !p.peekIs(tokDot) {
+		return nil, p.fail()
+	}
+	path, err := p.path(false)
+	if err != nil {
+		return nil, err
+	}
+	s := selectStage{path: path}
+
+	if p.peekIs(tokOp) {
+		s.op = p.next().text
+		s.literal, err = p.literal()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !p.accept(tokRParen) {
+		return nil, p.fail()
+	}
+	return s, nil
+}
+
+// literal 解析比较运算右侧的字面量
+func (p *exprParser) literal() (interface{}, error) {
+	tok := p.peek()
+	switch {
+	case p.peekIs(tokNumber):
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			p.expected = []string{"number"}
+			return nil, p.fail()
+		}
+		p.next()
+		return f, nil
+	case p.peekIs(tokString):
+		return p.stringLiteral()
+	case p.peekWord("true"):
+		p.next()
+		return true, nil
+	case p.peekWord("false"):
+		p.next()
+		return false, nil
+	case p.peekWord("null"):
+		p.next()
+		return nil, nil
+	}
+	return nil, p.fail()
+}
@@ -0,0 +1,118 @@
+package jsonx
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestEval(t *testing.T) {
+	doc := Parse(companyJSON)
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`.`, companyJSON},
+		{`.company.departments[0].name`, `"研发部"`},
+		{`.company.departments[-2].code`, `4`},
+		{`.company.departments[9]`, `null`},
+		{`.company.missing.name`, `null`},
+		{`.company["departments"][1]."name"`, `"市场部"`},
+		{`.company.departments[0].employees[].name`, `["张三", "李四"]`},
+		{`.company.departments | length`, `5`},
+		{`.company.departments[0].name | length`, `3`},
+		{`.company.departments[0] | keys`, `["active", "code", "employees", "name"]`},
+		{`.company.departments[0].employees | keys`, `[0, 1]`},
+		{`.company.departments[] | select(.active == true) | .name`, `["研发部", "R&D.v2"]`},
+		{`.company.departments[1] | select(.code > 1) | .name`, `"市场部"`},
+		{`.company.departments[0] | select(.code > 1) | .name`, `null`},
+		{`.company.departments[] | select(.code >= 3) | .code`, `[3, 4]`},
+		{`.company.departments[] | select(.code != 1) | .code`, `[2, 3, 4]`},
+		{`.company.departments[] | select(.lead == null) | .code`, `[1, 2, 3, 4]`},
+		{`.company.departments[] | select(.employees) | .employees | length`, `[2, 1, 0]`},
+		{`.company.departments[] | select(.employees[].name == "王五") | .name`, `["市场部"]`},
+		{`.company.departments[] | select(.name < "a") | .code`, `[3]`},
+		{`.company.departments[] | select(.code > 10)`, `[]`},
+		{`.company | .departments[2] | .name`, `"R&D.v2"`},
+		{`.company.departments[0].employees[] | .name | length`, `[2, 2]`},
+		{` .company . departments [ 1 ] . code `, `2`},
+	}
+	for _, tt := range tests {
+		got, err := doc.Eval(tt.expr)
+		if err != nil {
+			t.Errorf("Eval(%q) error: %v", tt.expr, err)
+			continue
+		}
+		if want := Parse(tt.want); !Equal(got, want) {
+			s, _ := got.ToJSON()
+			t.Errorf("Eval(%q) = %s, want %s", tt.expr, s, tt.want)
+		}
+	}
+}
+
+func TestEvalTypeError(t *testing.T) {
+	doc := Parse(companyJSON)
+
+	_, err := doc.Eval(".company.departments[] | .name")
+	var typeErr *TypeError
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("err = %v, want *TypeError", err)
+	}
+	if typeErr.Path != "company.departments.4" || typeErr.Expected != "object" || typeErr.Actual != "string" {
+		t.Errorf("type error = %+v", typeErr)
+	}
+
+	if _, err := doc.Eval(".company.departments[0].active | length"); !errors.As(err, &typeErr) {
+		t.Errorf("length of boolean: err = %v", err)
+	}
+
+	if _, err := Parse("{").Eval("."); err == nil {
+		t.Error("Eval should propagate the document error")
+	}
+}
+
+func TestEvalSyntaxError(t *testing.T) {
+	doc := Parse(companyJSON)
+
+	tests := []struct {
+		expr     string
+		pos      int
+		found    string
+		expected []string
+	}{
+		{``, 0, "end of expression", []string{`"."`, `"select"`, `"length"`, `"keys"`}},
+		{`company`, 0, `"company"`, []string{`"."`, `"select"`, `"length"`, `"keys"`}},
+		{`.a |`, 4, "end of expression", []string{`"."`, `"select"`, `"length"`, `"keys"`}},
+		{`.a b`, 3, `"b"`, []string{`"["`, `"."`, `"|"`, "end of expression"}},
+		{`.a.`, 3, "end of expression", []string{"identifier", "string"}},
+		{`.a[`, 3, "end of expression", []string{"number", "string", `"]"`}},
+		{`.a[1.5]`, 3, `"1.5"`, []string{"integer"}},
+		{`.a[1`, 4, "end of expression", []string{`"]"`}},
+		{`select .a`, 7, `"."`, []string{`"("`}},
+		{`select(.a > )`, 12, `")"`, []string{"number", "string", `"true"`, `"false"`, `"null"`}},
+		{`select(.a = 1)`, 10, `"="`, []string{`"["`, `"."`, "comparison operator", `")"`}},
+		{`select(.a > 1`, 13, "end of expression", []string{`")"`}},
+		{`select(length)`, 7, `"length"`, []string{`"."`}},
+		{`.a @`, 3, `"@"`, []string{`"["`, `"."`, `"|"`, "end of expression"}},
+		{`."name`, 1, "unterminated string", []string{"identifier", "string", `"["`, `"."`, `"|"`, "end of expression"}},
+	}
+	for _, tt := range tests {
+		_, err := doc.Eval(tt.expr)
+		var exprErr *ExprError
+		if !errors.As(err, &exprErr) {
+			t.Errorf("Eval(%q) err = %v, want *ExprError", tt.expr, err)
+			continue
+		}
+		if exprErr.Pos != tt.pos || exprErr.Found != tt.found || !reflect.DeepEqual(exprErr.Expected, tt.expected) {
+			t.Errorf("Eval(%q) = pos %d, found %s, expected %q; want pos %d, found %s, expected %q",
+				tt.expr, exprErr.Pos, exprErr.Found, exprErr.Expected, tt.pos, tt.found, tt.expected)
+		}
+	}
+
+	_, err := doc.Eval(".a b")
+	want := `eval: unexpected "b" at position 3 in ".a b", expected "[" or "." or "|" or end of expression`
+	if err.Error() != want {
+		t.Errorf("message = %s", err)
+	}
+}
@@ -0,0 +1,372 @@
+package jsonx
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 环境变量与文档内引用的展开
+
+// ExpandError 单个无法展开的变量或引用
+type ExpandError struct {
+	Path    string // 出错位置，根为空
+	Name    string // 变量名或 $ref 的值
+	Message string
+}
+
+// Error 实现 error 接口
+func (e *ExpandError) Error() string {
+	return displayPath(e.Path) + ": " + e.Message
+}
+
+// ExpandErrors 展开过程中收集到的全部错误，按路径排序
+type ExpandErrors []*ExpandError
+
+// Error 实现 error 接口，每个错误占一行
+func (e ExpandErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Unwrap 返回全部错误，支持 errors.As 获取单个 *ExpandError
+func (e ExpandErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// ExpandEnv 展开字符串叶子中的 ${VAR} 与 ${VAR:-default}，返回新文档，不修改原文档
+//
+// lookup 为 nil 时使用 os.LookupEnv；变量未设置或为空时使用 default，"$${" 输出字面量 "${"。
+// 整个字符串恰好是一个占位符时，展开结果为数字或 true/false 的会转换为对应类型，
+// 如 "${PORT}" 展开为 8080 而不是 "8080"。对象键不会展开。
+// 未设置且没有默认值的变量会全部收集到 ExpandErrors 中作为链式错误返回。
+func (j *JSON) ExpandEnv(lookup func(string) (string, bool)) *JSON {
+	if j.err != nil {
+		return j
+	}
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+
+	e := &envExpander{lookup: lookup}
+	result, err := e.walk(j.data, "", 0)
+	if err != nil {
+		return &JSON{err: err}
+	}
+	if len(e.errs) > 0 {
+		sortExpandErrors(e.errs)
+		return &JSON{err: e.errs}
+	}
+	return &JSON{data: result}
+}
+
+// envExpander 环境变量展开的遍历状态
+type envExpander struct {
+	lookup func(string) (string, bool)
+	errs   ExpandErrors
+}
+
+// walk 复制容器并展开字符串叶子
+func (e *envExpander) walk(v interface{}, path string, depth int) (interface{}, error) {
+	if depth > maxNestingDepth {
+		return nil, ErrTooDeep
+	}
+
+	var err error
+	switch val := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			if result[k], err = e.walk(item, joinJSONPath(path, k), depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(val))
+		for i, item := range val {
+			if result[i], err = e.walk(item, joinJSONPath(path, strconv.Itoa(i)), depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	case string:
+		return e.expandString(val, path), nil
+	default:
+		return v, nil
+	}
+}
+
+// expandString 展开单个字符串，出错时记录错误并保留原值
+func (e *envExpander) expandString(s, path string) interface{} {
+	if !strings.Contains(s, "${") {
+		return s
+	}
+
+	var sb strings.Builder
+	placeholders, failed := 0, false
+	for i := 0; i < len(s); {
+		if strings.HasPrefix(s[i:], "$${") {
+			sb.WriteString("${")
+			i += 3
+			continue
+		}
+		if !strings.HasPrefix(s[i:], "${") {
+			sb.WriteByte(s[i])
+			i++
+			continue
+		}
+
+		end := strings.IndexByte(s[i+2:], '}')
+		if end < 0 {
+			e.errs = append(e.errs, &ExpandError{Path: path, Message: fmt.Sprintf("unterminated placeholder in %q", s)})
+			return s
+		}
+		expr := s[i+2 : i+2+end]
+		i += end + 3
+		placeholders++
+
+		name, def, hasDefault := strings.Cut(expr, ":-")
+		if name == "" {
+			e.errs = append(e.errs, &ExpandError{Path: path, Message: fmt.Sprintf("empty variable name in %q", s)})
+			failed = true
+			continue
+		}
+		value, ok := e.lookup(name)
+		if !ok || value == "" {
+			if !hasDefault {
+				e.errs = append(e.errs, &ExpandError{Path: path, Name: name, Message: fmt.Sprintf("environment variable %s is not set", name)})
+				failed = true
+				continue
+			}
+			value = def
+		}
+		sb.WriteString(value)
+	}
+	if failed {
+		return s
+	}
+
+	expanded := sb.String()
+	if placeholders == 1 && strings.HasPrefix(s, "${") && strings.HasSuffix(s, "}") && strings.Count(s, "${") == 1 {
+		return coercePlaceholder(expanded)
+	}
+	return expanded
+}
+
+// coercePlaceholder 将单占位符的展开结果转换为数字或布尔值
+func coercePlaceholder(s string) interface{} {
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if n, err := strconv.ParseFloat(s, 64); err == nil && isJSONNumber(s) {
+		return n
+	}
+	return s
+}
+
+// isJSONNumber 判断字符串是否符合 JSON 数字语法，排除 "Inf"、"0x10"、"1_000" 等 ParseFloat 接受的形式
+func isJSONNumber(s string) bool {
+	i := 0
+	if i < len(s) && s[i] == '-' {
+		i++
+	}
+	digits := func() int {
+		start := i
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		return i - start
+	}
+	if n := digits(); n == 0 || (n > 1 && s[i-n] == '0') {
+		return false
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		if digits() == 0 {
+			return false
+		}
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		if digits() == 0 {
+			return false
+		}
+	}
+	return i == len(s)
+}
+
+// ResolveRefs 将 {"$ref": "#/defaults/timeouts"} 形式的对象替换为 JSON Pointer 指向的值，返回新文档
+//
+// 只支持以 "#" 开头的文档内引用，"~1" 与 "~0" 分别表示 "/" 与 "~"。被引用的值中的引用会继续展开，
+// 引用对象的其他字段会覆盖到解析结果上（结果为对象时），因此可以在引用的基础上局部修改。
+// 无法解析或形成循环的引用全部收集到 ExpandErrors 中返回，此时结果为 nil。
+func (j *JSON) ResolveRefs() (*JSON, error) {
+	if j.err != nil {
+		return nil, j.err
+	}
+
+	r := &refResolver{root: j.data, seen: make(map[string]bool)}
+	result, err := r.resolve(j.data, "", nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.errs) > 0 {
+		sortExpandErrors(r.errs)
+		return nil, r.errs
+	}
+	return &JSON{data: result}, nil
+}
+
+// refResolver 引用解析的状态
+type refResolver struct {
+	root interface{}
+	errs ExpandErrors
+	seen map[string]bool // 已记录的错误，避免同一位置被多次引用时重复报告
+}
+
+// resolve 复制 v 并展开其中的引用，stack 为当前正在解析的引用链
+func (r *refResolver) resolve(v interface{}, path string, stack []string, depth int) (interface{}, error) {
+	if depth > maxNestingDepth {
+		return nil, ErrTooDeep
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := val["$ref"].(string); ok {
+			return r.resolveRef(val, ref, path, stack, depth)
+		}
+		result := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			child, err := r.resolve(item, joinJSONPath(path, k), stack, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = child
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(val))
+		for i, item := range val {
+			child, err := r.resolve(item, joinJSONPath(path, strconv.Itoa(i)), stack, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = child
+		}
+		return result, nil
+	default:
+		return v, nil
+	}
+}
+
+// resolveRef 解析单个引用对象
+func (r *refResolver) resolveRef(obj map[string]interface{}, ref, path string, stack []string, depth int) (interface{}, error) {
+	for i, active := range stack {
+		if active == ref {
+			chain := append(append([]string{}, stack[i:]...), ref)
+			r.fail(path, ref, "circular $ref: "+strings.Join(chain, " -> "))
+			return nil, nil
+		}
+	}
+
+	targetPath, target, err := lookupPointer(r.root, ref)
+	if err != nil {
+		r.fail(path, ref, fmt.Sprintf("unresolvable $ref %q: %v", ref, err))
+		return nil, nil
+	}
+	resolved, err := r.resolve(target, targetPath, append(stack, ref), depth+1)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(obj) == 1 {
+		return resolved, nil
+	}
+	base, ok := resolved.(map[string]interface{})
+	if !ok {
+		r.fail(path, ref, fmt.Sprintf("$ref %q has sibling fields but does not point to an object", ref))
+		return nil, nil
+	}
+	for k, item := range obj {
+		if k == "$ref" {
+			continue
+		}
+		if base[k], err = r.resolve(item, joinJSONPath(path, k), stack, depth+1); err != nil {
+			return nil, err
+		}
+	}
+	return base, nil
+}
+
+// fail 记录错误，同一位置的相同错误只记录一次
+func (r *refResolver) fail(path, ref, message string) {
+	key := path + "\x00" + message
+	if r.seen[key] {
+		return
+	}
+	r.seen[key] = true
+	r.errs = append(r.errs, &ExpandError{Path: path, Name: ref, Message: message})
+}
+
+// lookupPointer 按文档内 JSON Pointer（如 "#/a/b/0"）查找值，返回对应的点分路径
+func lookupPointer(root interface{}, ref string) (string, interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return "", nil, fmt.Errorf("only local references starting with '#' are supported")
+	}
+	pointer := ref[1:]
+	if pointer == "" {
+		return "", root, nil
+	}
+	if pointer[0] != '/' {
+		return "", nil, fmt.Errorf("JSON pointer must start with '/'")
+	}
+
+	path, current := "", root
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch container := current.(type) {
+		case map[string]interface{}:
+			value, exists := container[token]
+			if !exists {
+				return "", nil, ErrPathNotFound
+			}
+			current = value
+		case []interface{}:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= len(container) {
+				return "", nil, fmt.Errorf("invalid array index %q", token)
+			}
+			current = container[idx]
+		default:
+			return "", nil, ErrPathNotFound
+		}
+		path = joinJSONPath(path, token)
+	}
+	return path, current, nil
+}
+
+// sortExpandErrors 按路径排序，路径相同时按消息排序
+func sortExpandErrors(errs ExpandErrors) {
+	sort.SliceStable(errs, func(a, b int) bool {
+		if errs[a].Path != errs[b].Path {
+			return errs[a].Path < errs[b].Path
+		}
+		return errs[a].Message < errs[b].Message
+	})
+}
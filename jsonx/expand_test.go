@@ -0,0 +1,134 @@
+package jsonx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func mapLookup(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	doc := Parse(`{
+		"db": {"host": "${DB_HOST}", "port": "${PORT}", "url": "postgres://${DB_HOST}:${PORT}/app"},
+		"debug": "${DEBUG:-false}",
+		"ratio": "${RATIO}",
+		"region": "${REGION:-cn-east}",
+		"zip": "${ZIP}",
+		"literal": "cost $${PRICE}",
+		"hosts": ["${DB_HOST}", 1]
+	}`)
+	vars := map[string]string{"DB_HOST": "db.local", "PORT": "5432", "RATIO": "0.75", "REGION": "", "ZIP": "007"}
+
+	expanded := doc.ExpandEnv(mapLookup(vars))
+	if expanded.Error() != nil {
+		t.Fatal(expanded.Error())
+	}
+	want := `{"db":{"host":"db.local","port":5432,"url":"postgres://db.local:5432/app"},"debug":false,"hosts":["db.local",1],"literal":"cost ${PRICE}","ratio":0.75,"region":"cn-east","zip":"007"}`
+	if got := expanded.MustJSON(); got != want {
+		t.Errorf("ExpandEnv =\n%s\nwant\n%s", got, want)
+	}
+	if !expanded.Get("db.port").IsNumber() {
+		t.Error(`"${PORT}" should be coerced into a number`)
+	}
+	if got := doc.Get("db.port").String(); got != "${PORT}" {
+		t.Errorf("original document changed: %s", got)
+	}
+}
+
+func TestExpandEnvErrors(t *testing.T) {
+	doc := Parse(`{"a":"${MISSING}","b":{"c":"x-${ALSO_MISSING}-${SET}"},"d":"${SET}","e":"${broken"}`)
+	err := doc.ExpandEnv(mapLookup(map[string]string{"SET": "1"})).Error()
+
+	var errs ExpandErrors
+	if !errors.As(err, &errs) || len(errs) != 3 {
+		t.Fatalf("error = %v, want 3 ExpandErrors", err)
+	}
+	if errs[0].Path != "a" || errs[0].Name != "MISSING" || errs[1].Path != "b.c" || errs[1].Name != "ALSO_MISSING" || errs[2].Path != "e" {
+		t.Errorf("errors = %v", err)
+	}
+	var single *ExpandError
+	if !errors.As(err, &single) {
+		t.Error("errors.As should find a single *ExpandError")
+	}
+
+	t.Setenv("JSONX_EXPAND_TEST", "from-env")
+	if got := Parse(`"${JSONX_EXPAND_TEST}"`).ExpandEnv(nil).String(); got != "from-env" {
+		t.Errorf("nil lookup should use os.LookupEnv, got %q", got)
+	}
+}
+
+func TestResolveRefs(t *testing.T) {
+	doc := Parse(`{
+		"defaults": {"timeouts": {"read": 5, "write": 10}, "retry": {"$ref": "#/policies/0"}},
+		"policies": [{"max": 3, "timeouts": {"$ref": "#/defaults/timeouts"}}],
+		"services": {
+			"api": {"timeouts": {"$ref": "#/defaults/timeouts"}, "retry": {"$ref": "#/defaults/retry"}},
+			"worker": {"timeouts": {"$ref": "#/defaults/timeouts", "read": 30}}
+		},
+		"odd~key": {"a/b": 1},
+		"escaped": {"$ref": "#/odd~0key/a~1b"}
+	}`)
+
+	resolved, err := doc.ResolveRefs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checks := map[string]string{
+		"services.api.timeouts":    `{"read":5,"write":10}`,
+		"services.api.retry":       `{"max":3,"timeouts":{"read":5,"write":10}}`,
+		"services.worker.timeouts": `{"read":30,"write":10}`,
+		"escaped":                  `1`,
+	}
+	for path, want := range checks {
+		if got := resolved.Get(path).MustJSON(); got != want {
+			t.Errorf("%s = %s, want %s", path, got, want)
+		}
+	}
+
+	// 每个引用得到独立的副本
+	resolved.Set("services.api.timeouts.read", 99)
+	if resolved.Get("defaults.timeouts.read").Int() != 5 || resolved.Get("services.worker.timeouts.write").Int() != 10 {
+		t.Error("resolved references should not share containers")
+	}
+	if !doc.Has("services.api.timeouts.$ref") {
+		t.Error("original document should be unchanged")
+	}
+}
+
+func TestResolveRefsErrors(t *testing.T) {
+	doc := Parse(`{
+		"a": {"$ref": "#/b"},
+		"b": {"$ref": "#/a"},
+		"self": {"child": {"$ref": "#/self"}},
+		"missing": {"$ref": "#/nowhere"},
+		"remote": {"$ref": "other.json#/x"},
+		"ok": {"$ref": "#/value"},
+		"value": 1
+	}`)
+
+	result, err := doc.ResolveRefs()
+	if result != nil {
+		t.Error("result should be nil on error")
+	}
+	var errs ExpandErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error = %v, want ExpandErrors", err)
+	}
+
+	paths := make([]string, len(errs))
+	for i, e := range errs {
+		paths[i] = e.Path
+	}
+	if got := strings.Join(paths, ","); got != "a,b,missing,remote,self.child" {
+		t.Errorf("error paths = %s\n%v", got, err)
+	}
+	if !strings.Contains(errs[0].Message, "circular $ref: #/b -> #/a -> #/b") {
+		t.Errorf("cycle message = %s", errs[0].Message)
+	}
+}
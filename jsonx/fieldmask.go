@@ -0,0 +1,98 @@
+package jsonx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 字段掩码：PATCH 接口按 "user.name,user.address.city" 形式的掩码只更新指定字段，
+// 语义与 protobuf 的 FieldMask 一致，路径语法与 Get 相同，另支持 "*" 片段。
+// 以 "*" 结尾的路径（如 "tags.*"）整体复制其父节点，目标中原有的该子树被替换；
+// 中间的 "*"（如 "items.*.name"）按源文档展开为具体路径后逐个复制。
+
+// ParseFieldMask 解析逗号分隔的字段掩码，去除每个路径两端的空白并忽略空路径
+func ParseFieldMask(s string) []string {
+	var mask []string
+	for _, path := range strings.Split(s, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			mask = append(mask, path)
+		}
+	}
+	return mask
+}
+
+// ValidateMask 检查掩码中的路径是否都存在于 doc，不存在时返回包装 ErrPathNotFound 的错误并列出所有无效路径
+//
+// 含 "*" 的路径只要求第一个 "*" 之前的部分存在，展开后为空（如空数组）视为有效。
+func ValidateMask(doc *JSON, mask []string) error {
+	if doc == nil {
+		return errors.New("field mask: nil document")
+	}
+	if doc.err != nil {
+		return doc.err
+	}
+
+	var invalid []string
+	for _, path := range mask {
+		if path == "" {
+			return errors.New("field mask: empty path")
+		}
+		check := path
+		if i := strings.Index(path, "*"); i >= 0 {
+			check = strings.TrimSuffix(path[:i], ".")
+		}
+		if _, err := doc.getByPath(check); err != nil {
+			invalid = append(invalid, path)
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("%w: invalid field mask: %s", ErrPathNotFound, strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
+// ApplyFieldMask 将 src 中掩码指定的路径复制到 dst，返回新的文档，dst 与 src 均不被修改
+//
+// 掩码先经 ValidateMask 检查，路径不存在于 src 时返回错误；dst 中缺少的中间对象（或数组）会自动创建。
+// 掩码为空时不复制任何字段，结果为 dst 的深拷贝。
+//
+//	mask := jsonx.ParseFieldMask(r.URL.Query().Get("update_mask"))
+//	updated, err := jsonx.ApplyFieldMask(stored, patch, mask)
+func ApplyFieldMask(dst, src *JSON, mask []string) (*JSON, error) {
+	if dst == nil {
+		return nil, errors.New("field mask: nil document")
+	}
+	if err := ValidateMask(src, mask); err != nil {
+		return nil, err
+	}
+
+	result := dst.Clone()
+	if result.err != nil {
+		return nil, result.err
+	}
+	for _, path := range mask {
+		for _, concrete := range fieldMaskPaths(src.data, path) {
+			value, err := src.getByPath(concrete)
+			if err != nil {
+				continue
+			}
+			cloned, err := deepClone(value)
+			if err != nil {
+				return nil, err
+			}
+			if err := result.setByPath(concrete, cloned); err != nil {
+				return nil, fmt.Errorf("field mask %s: %w", displayPath(concrete), err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// fieldMaskPaths 将掩码路径展开为 src 中的具体路径，结尾的 "*" 表示整体复制父节点
+func fieldMaskPaths(src interface{}, path string) []string {
+	if path == "*" {
+		return []string{""}
+	}
+	return expandWildcardPath(src, strings.TrimSuffix(path, ".*"))
+}
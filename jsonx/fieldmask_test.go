@@ -0,0 +1,115 @@
+package jsonx
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFieldMask(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"user.name,user.address.city", []string{"user.name", "user.address.city"}},
+		{" user.name , tags.* ,, ", []string{"user.name", "tags.*"}},
+		{"", nil},
+		{" , ", nil},
+	}
+	for _, tt := range tests {
+		if got := ParseFieldMask(tt.input); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseFieldMask(%q) = %#v, want %#v", tt.input, got, tt.want)
+		}
+	}
+}
+
+// patchUser 模拟 PATCH /users/1?update_mask=... 的处理流程
+func patchUser(stored *JSON, body, updateMask string) (*JSON, error) {
+	patch := Parse(body)
+	mask := ParseFieldMask(updateMask)
+	if err := ValidateMask(patch, mask); err != nil {
+		return nil, err
+	}
+	return ApplyFieldMask(stored, patch, mask)
+}
+
+func TestApplyFieldMaskPatchFlow(t *testing.T) {
+	stored := Parse(`{
+		"id": 1,
+		"user": {"name": "old", "email": "old@example.com", "address": {"city": "Beijing", "zip": "100000"}},
+		"tags": ["a", "b", "c"],
+		"items": [{"sku": "A", "name": "x"}, {"sku": "B", "name": "y"}]
+	}`)
+	body := `{
+		"id": 99,
+		"user": {"name": "new", "email": "ignored@example.com", "address": {"city": "Shanghai"}},
+		"tags": ["z"],
+		"items": [{"sku": "ignored", "name": "x2"}, {"name": "y2"}],
+		"profile": {"bio": "hello"}
+	}`
+
+	updated, err := patchUser(stored, body, "user.name, user.address.city, tags.*, items.*.name, profile.bio")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"id":1,"items":[{"name":"x2","sku":"A"},{"name":"y2","sku":"B"}],"profile":{"bio":"hello"},"tags":["z"],"user":{"address":{"city":"Shanghai","zip":"100000"},"email":"old@example.com","name":"new"}}`
+	if got, _ := updated.ToJSON(); got != want {
+		t.Errorf("updated = %s\nwant %s", got, want)
+	}
+	if stored.Get("user.name").String() != "old" || stored.Get("tags").Length() != 3 || stored.Has("profile") {
+		t.Error("ApplyFieldMask must not modify dst")
+	}
+
+	// 复制的值与 src 不共享
+	patch := Parse(body)
+	updated, _ = ApplyFieldMask(stored, patch, []string{"user.address"})
+	patch.Set("user.address.city", "changed")
+	if updated.Get("user.address.city").String() != "Shanghai" || updated.Has("user.address.zip") {
+		t.Errorf("user.address = %v", updated.Get("user.address").ToInterface())
+	}
+
+	// 无效掩码：列出所有不存在的路径
+	_, err = patchUser(stored, body, "user.name,user.phone,missing.*,items.*.name")
+	if !errors.Is(err, ErrPathNotFound) || !strings.Contains(err.Error(), "user.phone, missing.*") {
+		t.Errorf("invalid mask err = %v", err)
+	}
+	if _, err := ApplyFieldMask(stored, Parse(body), []string{"user.phone"}); !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("ApplyFieldMask invalid mask err = %v", err)
+	}
+}
+
+func TestApplyFieldMaskEdgeCases(t *testing.T) {
+	src := Parse(`{"a":{"b":{"c":1}},"list":[],"s":"x"}`)
+
+	// 缺少的中间容器自动创建，空掩码不复制任何字段
+	updated, err := ApplyFieldMask(Parse(`{}`), src, []string{"a.b.c", "list.*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := updated.ToJSON(); got != `{"a":{"b":{"c":1}},"list":[]}` {
+		t.Errorf("updated = %s", got)
+	}
+	if updated, _ := ApplyFieldMask(Parse(`{"k":1}`), src, nil); updated.Get("k").Int() != 1 || updated.Has("a") {
+		t.Errorf("empty mask = %v", updated.ToInterface())
+	}
+
+	// "*" 替换整个文档
+	if updated, _ := ApplyFieldMask(Parse(`{"k":1}`), src, []string{"*"}); !Equal(updated, src) {
+		t.Errorf("root wildcard = %v", updated.ToInterface())
+	}
+
+	// 目标中间节点不是容器时报错
+	if _, err := ApplyFieldMask(Parse(`{"a":"scalar"}`), src, []string{"a.b"}); err == nil || !strings.Contains(err.Error(), "a.b") {
+		t.Errorf("scalar parent err = %v", err)
+	}
+	if err := ValidateMask(src, []string{"a", ""}); err == nil {
+		t.Error("empty path should be rejected")
+	}
+	if err := ValidateMask(Parse(`{`), []string{"a"}); err == nil {
+		t.Error("invalid document should be rejected")
+	}
+	if _, err := ApplyFieldMask(nil, src, nil); err == nil {
+		t.Error("nil dst should be rejected")
+	}
+}
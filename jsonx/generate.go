@@ -0,0 +1,513 @@
+package jsonx
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 测试数据工厂：根据 Schema 生成满足约束的随机文档，用于压测与模糊测试
+
+// GenOptions Generate 的选项，零值可直接使用
+type GenOptions struct {
+	// Seed 随机种子，非 0 时相同的 Schema 与选项总是生成相同的文档；为 0 时每次结果不同
+	Seed int64
+	// OptionalProbability 非必需字段出现的概率（0~1），0 使用默认值 0.5，小于 0 表示从不出现
+	OptionalProbability float64
+	// NullProbability Nullable 的值生成 null 的概率（0~1），0 使用默认值 0.1，小于 0 表示从不生成
+	NullProbability float64
+	// MaxItems 未设置 maxItems 的数组最多生成的元素数，默认 3
+	MaxItems int
+	// MaxStringLength 未设置 maxLength 的字符串最大长度，默认 12
+	MaxStringLength int
+}
+
+// maxGenAttempts 单个值生成后未通过校验时的重试次数
+const maxGenAttempts = 50
+
+// maxGenDepth Schema 嵌套的最大深度，防止自引用的 Schema 无限展开
+const maxGenDepth = 64
+
+// Generate 根据 Schema 生成满足约束的文档
+//
+// 支持 type、properties / required、items / minItems / maxItems / uniqueItems、enum、
+// minLength / maxLength、pattern、format（email、uri、date-time、date、uuid）、
+// minimum / maximum / multipleOf、nullable 与 oneOf / anyOf / allOf。
+// 每个值生成后都会用 Schema 校验，不通过时重试；约束互相矛盾或无法满足时返回链式错误。
+func Generate(s *Schema, opts GenOptions) *JSON {
+	if s == nil {
+		return &JSON{err: fmt.Errorf("generate: nil schema")}
+	}
+
+	if opts.OptionalProbability == 0 {
+		opts.OptionalProbability = 0.5
+	}
+	if opts.NullProbability == 0 {
+		opts.NullProbability = 0.1
+	}
+	if opts.MaxItems <= 0 {
+		opts.MaxItems = 3
+	}
+	if opts.MaxStringLength <= 0 {
+		opts.MaxStringLength = 12
+	}
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	g := &generator{opts: opts, rnd: rand.New(rand.NewSource(seed))}
+	data, err := g.valid(s, "", 0)
+	if err != nil {
+		return &JSON{err: err}
+	}
+	return &JSON{data: data}
+}
+
+// generator 生成状态
+type generator struct {
+	opts GenOptions
+	rnd  *rand.Rand
+}
+
+// valid 生成并校验，失败时重试
+func (g *generator) valid(s *Schema, path string, depth int) (interface{}, error) {
+	if depth > maxGenDepth {
+		return nil, fmt.Errorf("generate %s: %w", displayPath(path), ErrTooDeep)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < maxGenAttempts; attempt++ {
+		value, err := g.value(s, path, depth)
+		if err != nil {
+			return nil, err
+		}
+		var errs ValidationErrors
+		s.validateValue(&JSON{data: value}, path, &errs)
+		if len(errs) == 0 {
+			return value, nil
+		}
+		lastErr = errs[0]
+	}
+	return nil, fmt.Errorf("generate %s: no valid value after %d attempts: %w", displayPath(path), maxGenAttempts, lastErr)
+}
+
+// value 生成一个候选值，不保证通过校验
+func (g *generator) value(s *Schema, path string, depth int) (interface{}, error) {
+	if s.Nullable && g.chance(g.opts.NullProbability) {
+		return nil, nil
+	}
+
+	// 组合关键字：allOf 合并为一个 Schema，oneOf / anyOf 随机选择一个分支合并
+	if len(s.AllOf) > 0 || len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+		merged := *s
+		merged.AllOf, merged.OneOf, merged.AnyOf, merged.Nullable = nil, nil, nil, false
+		for _, branch := range s.AllOf {
+			merged = mergeSchemas(merged, branch)
+		}
+		for _, branches := range [][]*Schema{s.OneOf, s.AnyOf} {
+			if len(branches) > 0 {
+				merged = mergeSchemas(merged, branches[g.rnd.Intn(len(branches))])
+			}
+		}
+		return g.value(&merged, path, depth+1)
+	}
+
+	if len(s.Enum) > 0 {
+		return deepClone(s.Enum[g.rnd.Intn(len(s.Enum))])
+	}
+
+	switch g.inferType(s) {
+	case "object":
+		return g.object(s, path, depth)
+	case "array":
+		return g.array(s, path, depth)
+	case "string":
+		return g.string(s)
+	case "integer":
+		return g.number(s, true), nil
+	case "number":
+		return g.number(s, false), nil
+	case "boolean":
+		return g.rnd.Intn(2) == 1, nil
+	case "null":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("generate %s: unsupported type %q", displayPath(path), s.Type)
+	}
+}
+
+// inferType 未设置 type 时根据关键字推断类型
+func (g *generator) inferType(s *Schema) string {
+	switch {
+	case s.Type != "":
+		return s.Type
+	case s.Properties != nil || len(s.Required) > 0:
+		return "object"
+	case s.Items != nil || s.MinItems != nil || s.MaxItems != nil:
+		return "array"
+	case s.Minimum != nil || s.Maximum != nil || s.MultipleOf != nil:
+		return "number"
+	default:
+		return "string"
+	}
+}
+
+// object 生成对象，必需字段总是生成，其他字段按概率生成
+func (g *generator) object(s *Schema, path string, depth int) (interface{}, error) {
+	required := make(map[string]bool, len(s.Required))
+	for _, name := range s.Required {
+		required[name] = true
+	}
+
+	// 按名称排序，保证相同种子生成相同结果
+	names := make([]string, 0, len(s.Properties)+len(s.Required))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	for _, name := range s.Required {
+		if _, declared := s.Properties[name]; !declared {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	result := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		if !required[name] && !g.chance(g.opts.OptionalProbability) {
+			continue
+		}
+		schema := s.Properties[name]
+		if schema == nil {
+			schema = &Schema{}
+		}
+		value, err := g.valid(schema, joinSchemaPath(path, name), depth+1)
+		if err != nil {
+			return nil, err
+		}
+		result[name] = value
+	}
+	return result, nil
+}
+
+// array 生成数组，uniqueItems 时重新生成重复的元素
+func (g *generator) array(s *Schema, path string, depth int) (interface{}, error) {
+	low, high := 0, g.opts.MaxItems
+	if s.MinItems != nil {
+		low = *s.MinItems
+	}
+	if s.MaxItems != nil {
+		high = *s.MaxItems
+	} else if high < low {
+		high = low
+	}
+	if high < low {
+		return nil, fmt.Errorf("generate %s: minItems %d exceeds maxItems %d", displayPath(path), low, high)
+	}
+
+	items := s.Items
+	if items == nil {
+		items = &Schema{}
+	}
+	n := low + g.rnd.Intn(high-low+1)
+	result := make([]interface{}, 0, n)
+	comparer := &equalComparer{}
+	for i := 0; i < n; i++ {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		for attempt := 0; ; attempt++ {
+			value, err := g.valid(items, itemPath, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			duplicate := false
+			if s.UniqueItems {
+				for _, existing := range result {
+					if comparer.equal(existing, value, nil) {
+						duplicate = true
+						break
+					}
+				}
+			}
+			if !duplicate {
+				result = append(result, value)
+				break
+			}
+			if attempt >= maxGenAttempts {
+				return nil, fmt.Errorf("generate %s: cannot produce %d unique items", displayPath(path), n)
+			}
+		}
+	}
+	return result, nil
+}
+
+// string 生成字符串，优先满足 format 与 pattern
+func (g *generator) string(s *Schema) (interface{}, error) {
+	switch s.Format {
+	case "email":
+		return g.word(3, 8) + "." + g.word(2, 6) + "@example.com", nil
+	case "uri":
+		return "https://example.com/" + g.word(3, 10), nil
+	case "date-time":
+		return g.time().Format(time.RFC3339), nil
+	case "date":
+		return g.time().Format("2006-01-02"), nil
+	case "uuid":
+		b := make([]byte, 16)
+		g.rnd.Read(b)
+		b[6] = b[6]&0x0f | 0x40
+		b[8] = b[8]&0x3f | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+	}
+
+	if s.Pattern != "" {
+		re, err := syntax.Parse(s.Pattern, syntax.Perl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", s.Pattern, err)
+		}
+		var sb strings.Builder
+		g.regexp(re.Simplify(), &sb)
+		return sb.String(), nil
+	}
+
+	low, high := 0, g.opts.MaxStringLength
+	if s.MinLength != nil {
+		low = *s.MinLength
+	}
+	if s.MaxLength != nil {
+		high = *s.MaxLength
+	}
+	if high < low {
+		high = low
+	}
+	return g.word(low, high), nil
+}
+
+// number 生成 [minimum, maximum] 范围内的数值，满足 multipleOf
+func (g *generator) number(s *Schema, integer bool) float64 {
+	low, high := 0.0, 1000.0
+	if s.Minimum != nil {
+		low = *s.Minimum
+		if s.Maximum == nil {
+			high = low + 1000
+		}
+	}
+	if s.Maximum != nil {
+		high = *s.Maximum
+		if s.Minimum == nil {
+			low = math.Min(0, high-1000)
+		}
+	}
+
+	step := 0.0
+	switch {
+	case s.MultipleOf != nil && *s.MultipleOf > 0:
+		step = *s.MultipleOf
+	case integer:
+		step = 1
+	}
+	if step > 0 {
+		first, last := math.Ceil(low/step), math.Floor(high/step)
+		if last < first {
+			return low
+		}
+		k := first + float64(g.rnd.Int63n(int64(last-first)+1))
+		// 消除 0.01 * 1999 这类乘法的浮点误差
+		return roundTo(k*step, step)
+	}
+
+	value := low + g.rnd.Float64()*(high-low)
+	return math.Min(high, math.Max(low, math.Round(value*100)/100))
+}
+
+// roundTo 按步长的小数位数四舍五入
+func roundTo(v, step float64) float64 {
+	scale := 1.0
+	for i := 0; i < 12 && step*scale != math.Trunc(step*scale); i++ {
+		scale *= 10
+	}
+	return math.Round(v*scale) / scale
+}
+
+// regexp 根据正则语法树生成匹配的字符串
+func (g *generator) regexp(re *syntax.Regexp, sb *strings.Builder) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		sb.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		sb.WriteRune(g.classRune(re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		sb.WriteByte(byte('a' + g.rnd.Intn(26)))
+	case syntax.OpCapture:
+		g.regexp(re.Sub[0], sb)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			g.regexp(sub, sb)
+		}
+	case syntax.OpAlternate:
+		g.regexp(re.Sub[g.rnd.Intn(len(re.Sub))], sb)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		low, high := 0, 3
+		switch re.Op {
+		case syntax.OpPlus:
+			low = 1
+		case syntax.OpQuest:
+			high = 1
+		case syntax.OpRepeat:
+			low, high = re.Min, re.Max
+			if high < 0 {
+				high = low + 3
+			}
+		}
+		for n := low + g.rnd.Intn(high-low+1); n > 0; n-- {
+			g.regexp(re.Sub[0], sb)
+		}
+	}
+	// 锚点、单词边界与空匹配不产生字符
+}
+
+// classRune 从字符类中选取字符，优先选择可打印的 ASCII 字符
+func (g *generator) classRune(ranges []rune) rune {
+	var printable []rune
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := max(ranges[i], 0x21), min(ranges[i+1], 0x7e)
+		if lo <= hi {
+			printable = append(printable, lo, hi)
+		}
+	}
+	if len(printable) > 0 {
+		ranges = printable
+	}
+	if len(ranges) < 2 {
+		return 'a'
+	}
+	i := g.rnd.Intn(len(ranges)/2) * 2
+	return ranges[i] + rune(g.rnd.Intn(int(ranges[i+1]-ranges[i])+1))
+}
+
+// word 生成长度在 [low, high] 之间的小写字母串
+func (g *generator) word(low, high int) string {
+	n := low
+	if high > low {
+		n += g.rnd.Intn(high - low + 1)
+	}
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('a' + g.rnd.Intn(26))
+	}
+	return string(b)
+}
+
+// time 生成 2000 年至 2030 年之间的 UTC 时间（精确到秒）
+func (g *generator) time() time.Time {
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	end := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	return time.Unix(start+g.rnd.Int63n(end-start), 0).UTC()
+}
+
+// chance 以概率 p 返回 true，p < 0 时总是 false
+func (g *generator) chance(p float64) bool {
+	return p > 0 && g.rnd.Float64() < p
+}
+
+// mergeSchemas 将 branch 的约束叠加到 base 上，用于生成 allOf 与所选分支的组合
+// 数值与长度取更严格的边界，properties 与 required 取并集，其余关键字 base 未设置时取 branch 的
+func mergeSchemas(base Schema, branch *Schema) Schema {
+	if branch == nil {
+		return base
+	}
+	b := *branch
+	if len(b.AllOf) > 0 {
+		inner := b
+		inner.AllOf = nil
+		for _, sub := range b.AllOf {
+			inner = mergeSchemas(inner, sub)
+		}
+		b = inner
+	}
+
+	if base.Type == "" || base.Type == "number" && b.Type == "integer" {
+		base.Type = b.Type
+	}
+	if len(b.Properties) > 0 {
+		props := make(map[string]*Schema, len(base.Properties)+len(b.Properties))
+		for name, schema := range base.Properties {
+			props[name] = schema
+		}
+		for name, schema := range b.Properties {
+			if existing, ok := props[name]; ok && existing != nil && schema != nil {
+				combined := mergeSchemas(*existing, schema)
+				props[name] = &combined
+			} else {
+				props[name] = schema
+			}
+		}
+		base.Properties = props
+	}
+	base.Required = append(append([]string(nil), base.Required...), b.Required...)
+	if base.Items == nil {
+		base.Items = b.Items
+	} else if b.Items != nil {
+		combined := mergeSchemas(*base.Items, b.Items)
+		base.Items = &combined
+	}
+
+	base.MinLength = tighterInt(base.MinLength, b.MinLength, true)
+	base.MaxLength = tighterInt(base.MaxLength, b.MaxLength, false)
+	base.MinItems = tighterInt(base.MinItems, b.MinItems, true)
+	base.MaxItems = tighterInt(base.MaxItems, b.MaxItems, false)
+	base.Minimum = tighterFloat(base.Minimum, b.Minimum, true)
+	base.Maximum = tighterFloat(base.Maximum, b.Maximum, false)
+	if base.MultipleOf == nil {
+		base.MultipleOf = b.MultipleOf
+	}
+	if len(base.Enum) == 0 {
+		base.Enum = b.Enum
+	}
+	if base.Pattern == "" {
+		base.Pattern = b.Pattern
+	}
+	if base.Format == "" {
+		base.Format = b.Format
+	}
+	if base.AdditionalProperties == nil {
+		base.AdditionalProperties = b.AdditionalProperties
+	}
+	base.UniqueItems = base.UniqueItems || b.UniqueItems
+	if len(base.OneOf) == 0 {
+		base.OneOf = b.OneOf
+	}
+	if len(base.AnyOf) == 0 {
+		base.AnyOf = b.AnyOf
+	}
+	return base
+}
+
+// tighterInt 返回更严格的边界，lower 为 true 时取较大值，否则取较小值
+func tighterInt(a, b *int, lower bool) *int {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case (*b > *a) == lower:
+		return b
+	}
+	return a
+}
+
+// tighterFloat 返回更严格的边界，lower 为 true 时取较大值，否则取较小值
+func tighterFloat(a, b *float64, lower bool) *float64 {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case (*b > *a) == lower:
+		return b
+	}
+	return a
+}
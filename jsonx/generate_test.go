@@ -0,0 +1,113 @@
+package jsonx
+
+import (
+	"strings"
+	"testing"
+)
+
+const orderSchemaJSON = `{
+	"type": "object",
+	"required": ["id", "customer", "items", "status"],
+	"additionalProperties": false,
+	"properties": {
+		"id":       {"type": "string", "format": "uuid"},
+		"created":  {"type": "string", "format": "date-time"},
+		"shipOn":   {"type": "string", "format": "date"},
+		"status":   {"enum": ["pending", "paid", "shipped"]},
+		"code":     {"type": "string", "pattern": "^[A-Z]{3}-\\d{2,4}(-[a-z]+)?$"},
+		"note":     {"type": "string", "maxLength": 20, "nullable": true},
+		"customer": {
+			"type": "object",
+			"required": ["email", "name"],
+			"properties": {
+				"email":    {"type": "string", "format": "email"},
+				"name":     {"type": "string", "minLength": 2, "maxLength": 8},
+				"homepage": {"type": "string", "format": "uri"},
+				"vip":      {"type": "boolean"}
+			}
+		},
+		"items": {
+			"type": "array", "minItems": 1, "maxItems": 5,
+			"items": {
+				"type": "object",
+				"required": ["sku", "qty", "price"],
+				"properties": {
+					"sku":   {"type": "string", "minLength": 4, "maxLength": 4},
+					"qty":   {"type": "integer", "minimum": 1, "maximum": 10},
+					"price": {"type": "number", "minimum": 0, "maximum": 500, "multipleOf": 0.01}
+				}
+			}
+		},
+		"tags":     {"type": "array", "uniqueItems": true, "maxItems": 3, "items": {"enum": ["a", "b", "c", "d"]}},
+		"discount": {"allOf": [{"type": "number", "minimum": 0}, {"maximum": 0.5}]},
+		"payment":  {"oneOf": [
+			{"type": "object", "required": ["card"], "properties": {"card": {"type": "string", "pattern": "^\\d{4}$"}}, "additionalProperties": false},
+			{"type": "object", "required": ["iban"], "properties": {"iban": {"type": "string", "pattern": "^DE\\d{6}$"}}, "additionalProperties": false}
+		]}
+	}
+}`
+
+func TestGenerateSatisfiesSchema(t *testing.T) {
+	for _, schemaJSON := range []string{orderSchemaJSON, userSchemaJSON, webhookSchemaJSON} {
+		schema, err := ParseSchema(schemaJSON)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for seed := int64(1); seed <= 200; seed++ {
+			doc := Generate(schema, GenOptions{Seed: seed})
+			if doc.Error() != nil {
+				t.Fatalf("seed %d: %v", seed, doc.Error())
+			}
+			if err := schema.Validate(doc); err != nil {
+				text, _ := doc.ToJSON()
+				t.Fatalf("seed %d: generated document is invalid:\n%v\n%s", seed, err, text)
+			}
+		}
+	}
+}
+
+func TestGenerateSeedDeterminism(t *testing.T) {
+	schema, err := ParseSchema(orderSchemaJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := Generate(schema, GenOptions{Seed: 42}).ToJSON()
+	b, _ := Generate(schema, GenOptions{Seed: 42}).ToJSON()
+	if a != b {
+		t.Fatalf("same seed produced different documents:\n%s\n%s", a, b)
+	}
+	if c, _ := Generate(schema, GenOptions{Seed: 43}).ToJSON(); c == a {
+		t.Error("different seeds should produce different documents")
+	}
+}
+
+func TestGenerateOptionalProbability(t *testing.T) {
+	schema, err := ParseSchema(orderSchemaJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	never := Generate(schema, GenOptions{Seed: 7, OptionalProbability: -1})
+	if keys := never.Keys(); strings.Join(keys, ",") != "customer,id,items,status" {
+		t.Errorf("only required fields expected, got %v", keys)
+	}
+	always := Generate(schema, GenOptions{Seed: 7, OptionalProbability: 1, NullProbability: -1})
+	if len(always.Keys()) != len(schema.Properties) || always.Get("note").IsNull() {
+		t.Errorf("all fields expected, got %v", always.Keys())
+	}
+}
+
+func TestGenerateUnsatisfiable(t *testing.T) {
+	minLen, maxLen := 5, 2
+	doc := Generate(&Schema{Type: "string", MinLength: &minLen, MaxLength: &maxLen}, GenOptions{Seed: 1})
+	if doc.Error() == nil || !strings.Contains(doc.Error().Error(), "no valid value") {
+		t.Errorf("contradictory schema should fail, got %v", doc.Error())
+	}
+
+	maxItems := 5
+	unique := &Schema{Type: "array", MinItems: &maxItems, UniqueItems: true, Items: &Schema{Enum: []interface{}{1, 2}}}
+	if doc := Generate(unique, GenOptions{Seed: 1}); doc.Error() == nil {
+		t.Error("impossible uniqueItems should fail")
+	}
+}
@@ -1,3 +1,3 @@
 module github.com/zhoudm1743/go-util/jsonx
 
-go 1.23.4 
\ No newline at end of file
+go 1.23.4
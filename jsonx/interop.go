@@ -0,0 +1,183 @@
+package jsonx
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// 与 types.XMap / types.XArray 的互操作
+//
+// jsonx 模块只依赖标准库（YAML 支持在独立模块 jsonx/yamlx 中），而 types 位于根模块并带有第三方依赖，
+// 因此 jsonx 不能导入 types，这里的函数以底层类型为参数：
+// types.XMap[string, interface{}] 可直接传给 FromXMap，types.XArray[T] 可直接传给 FromXArrayAny。
+//
+// 反向转换时方法无法声明 types.XMap 返回类型，(*JSON).ToXMap 返回无名的 map[string]interface{}，
+// 可直接赋值给 types.XMap[string, interface{}] 类型的变量；需要直接得到具名类型时使用
+// 泛型函数 ToXMap[types.XMap[string, interface{}]](j)。
+//
+// 嵌套在其中的具名 map / slice 类型（如值为 XMap 或 XArray）、具名数字与字符串类型以及结构体
+// 会被转换为 jsonx 使用的 map[string]interface{}、[]interface{} 与基础类型，否则 Get 等路径操作无法识别。
+
+// FromXMap 从 map 创建 JSON，可直接传入 types.XMap[string, interface{}]
+// 与 FromMap 不同，容器会被复制并规范化嵌套的具名类型
+func FromXMap(m map[string]interface{}) *JSON {
+	if m == nil {
+		return &JSON{data: nil}
+	}
+	data, err := normalizeGoValue(m, 0)
+	return &JSON{data: data, err: err}
+}
+
+// FromXArrayAny 从任意元素类型的切片创建 JSON 数组，可直接传入 types.XArray[T]
+// 元素按 FromXMap 的规则规范化，nil 切片得到空数组
+func FromXArrayAny[T any](a []T) *JSON {
+	result := make([]interface{}, len(a))
+	for i, item := range a {
+		value, err := normalizeGoValue(item, 0)
+		if err != nil {
+			return &JSON{err: fmt.Errorf("element %d: %w", i, err)}
+		}
+		result[i] = value
+	}
+	return &JSON{data: result}
+}
+
+// ToXMap 将 JSON 对象转换为指定的 map 类型，如 jsonx.ToXMap[types.XMap[string, interface{}]](j)
+// 返回的 map 与文档共享数据，修改会反映到文档中；值不是对象时返回 *TypeError
+func ToXMap[M ~map[string]interface{}](j *JSON) (M, error) {
+	if j.err != nil {
+		return nil, j.err
+	}
+	obj, ok := j.data.(map[string]interface{})
+	if !ok {
+		return nil, j.typeError("object")
+	}
+	return M(obj), nil
+}
+
+// ToXMap 将 JSON 对象转换为 map，结果可直接赋值给 types.XMap[string, interface{}]：
+//
+//	var m types.XMap[string, interface{}]
+//	m, err = j.ToXMap()
+//
+// 返回的 map 与文档共享数据；值不是对象时返回 *TypeError
+func (j *JSON) ToXMap() (map[string]interface{}, error) {
+	return ToXMap[map[string]interface{}](j)
+}
+
+// normalizeGoValue 将任意 Go 值转换为 jsonx 的内部表示
+func normalizeGoValue(v interface{}, depth int) (interface{}, error) {
+	if depth > maxNestingDepth {
+		return nil, ErrTooDeep
+	}
+
+	switch val := v.(type) {
+	case nil, string, bool, float64, float32, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, json.Number:
+		return v, nil
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			child, err := normalizeGoValue(item, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = child
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(val))
+		for i, item := range val {
+			child, err := normalizeGoValue(item, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = child
+		}
+		return result, nil
+	case *JSON:
+		if val.err != nil {
+			return nil, val.err
+		}
+		return normalizeGoValue(val.data, depth+1)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Type().Implements(jsonMarshalerType) || rv.Type().Implements(textMarshalerType) {
+		return roundTripJSON(v)
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return normalizeGoValue(rv.Elem().Interface(), depth+1)
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if rv.IsNil() {
+			return nil, nil
+		}
+		result := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			child, err := normalizeGoValue(iter.Value().Interface(), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			result[iter.Key().String()] = child
+		}
+		return result, nil
+	case reflect.Slice, reflect.Array:
+		// []byte 按 encoding/json 的规则编码为 base64 字符串
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil, nil
+		}
+		result := make([]interface{}, rv.Len())
+		for i := range result {
+			child, err := normalizeGoValue(rv.Index(i).Interface(), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = child
+		}
+		return result, nil
+	}
+
+	return roundTripJSON(v)
+}
+
+// roundTripJSON 经由 encoding/json 转换结构体、[]byte 及自定义编码的类型
+func roundTripJSON(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var result interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
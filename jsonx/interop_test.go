@@ -0,0 +1,108 @@
+package jsonx
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// 与 types.XMap / types.XArray 定义相同的具名类型，jsonx 不依赖 types 包
+type (
+	testXMap[K comparable, V any] map[K]V
+	testXArray[T any]             []T
+	testLevel                     int
+)
+
+func TestFromXMap(t *testing.T) {
+	m := testXMap[string, interface{}]{
+		"name":    "tom",
+		"level":   testLevel(3),
+		"profile": testXMap[string, interface{}]{"city": "bj", "scores": testXArray[int]{90, 85}},
+		"tags":    []string{"a", "b"},
+		"since":   time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		"owner":   struct{ ID int }{ID: 7},
+		"empty":   nil,
+	}
+
+	j := FromXMap(m)
+	if j.Error() != nil {
+		t.Fatal(j.Error())
+	}
+	checks := map[string]string{
+		"profile.city":     `"bj"`,
+		"profile.scores.1": `85`,
+		"tags.0":           `"a"`,
+		"level":            `3`,
+		"since":            `"2024-01-02T00:00:00Z"`,
+		"owner.ID":         `7`,
+		"empty":            `null`,
+	}
+	for path, want := range checks {
+		if got := j.Get(path).MustJSON(); got != want {
+			t.Errorf("Get(%s) = %s, want %s", path, got, want)
+		}
+	}
+	if j.Get("level").Int() != 3 {
+		t.Error("named integer types should be usable with Int()")
+	}
+
+	// 容器被复制，修改文档不影响原 map
+	j.Set("profile.city", "sh")
+	if m["profile"].(testXMap[string, interface{}])["city"] != "bj" {
+		t.Error("FromXMap should not share containers with the source")
+	}
+}
+
+func TestFromXArrayAny(t *testing.T) {
+	if got := FromXArrayAny(testXArray[int]{1, 2, 3}).MustJSON(); got != `[1,2,3]` {
+		t.Errorf("ints = %s", got)
+	}
+	if got := FromXArrayAny(testXArray[string]{"x"}).Append("y").MustJSON(); got != `["x","y"]` {
+		t.Errorf("strings = %s", got)
+	}
+	type item struct {
+		Name string `json:"name"`
+	}
+	if got := FromXArrayAny([]item{{"a"}, {"b"}}).Get("1.name").String(); got != "b" {
+		t.Errorf("structs = %s", got)
+	}
+	if got := FromXArrayAny[int](nil).MustJSON(); got != `[]` {
+		t.Errorf("nil slice = %s", got)
+	}
+	if err := FromXArrayAny([]interface{}{func() {}}).Error(); err == nil {
+		t.Error("unsupported element should fail")
+	}
+}
+
+func TestToXMap(t *testing.T) {
+	j := Parse(`{"a":1,"b":{"c":true}}`)
+	m, err := ToXMap[testXMap[string, interface{}]](j)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m) != 2 || m["a"] != 1.0 {
+		t.Errorf("ToXMap = %v", m)
+	}
+
+	var typeErr *TypeError
+	if _, err := ToXMap[testXMap[string, interface{}]](Parse(`[1]`)); !errors.As(err, &typeErr) {
+		t.Errorf("array error = %v, want *TypeError", err)
+	}
+	if _, err := ToXMap[map[string]interface{}](Parse(`{bad`)); err == nil {
+		t.Error("chained error should be returned")
+	}
+}
+
+func TestJSONToXMapMethod(t *testing.T) {
+	// 方法的返回值可直接赋值给具名 map 类型
+	var m testXMap[string, interface{}]
+	m, err := Parse(`{"a":1}`).ToXMap()
+	if err != nil || m["a"] != 1.0 {
+		t.Errorf("ToXMap() = %v, %v", m, err)
+	}
+
+	var typeErr *TypeError
+	if _, err := Parse(`"text"`).ToXMap(); !errors.As(err, &typeErr) {
+		t.Errorf("string error = %v, want *TypeError", err)
+	}
+}
@@ -0,0 +1,277 @@
+package jsonx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestIterationValueLifetime(t *testing.T) {
+	arr := Parse(`[{"id": 1}, {"id": 2}, {"id": 3}]`)
+
+	// 复用的 value 在下一次回调时被替换
+	var kept []*JSON
+	arr.ForEach(func(key string, value *JSON) bool {
+		kept = append(kept, value)
+		return true
+	})
+	if kept[0] != kept[2] || kept[0].Get("id").Int() != 3 {
+		t.Error("ForEach is expected to reuse the value wrapper")
+	}
+
+	// ForEachCopy 与派生值可以安全保留
+	var copies, derived []*JSON
+	arr.ForEachCopy(func(key string, value *JSON) bool {
+		copies = append(copies, value)
+		return true
+	})
+	arr.ForEach(func(key string, value *JSON) bool {
+		derived = append(derived, value.Get("id"))
+		return true
+	})
+	for i := range copies {
+		if copies[i].Get("id").Int() != i+1 || derived[i].Int() != i+1 {
+			t.Errorf("element %d not preserved: copy %v, derived %v", i, copies[i].data, derived[i].data)
+		}
+	}
+
+	// Map 回调直接返回 value 时结果不会被后续迭代覆盖
+	mapped := arr.Map(func(key string, value *JSON) interface{} { return value })
+	for i := 0; i < 3; i++ {
+		if got := mapped.Get("").data.([]interface{})[i].(*JSON).Get("id").Int(); got != i+1 {
+			t.Errorf("Map result %d = %d", i, got)
+		}
+	}
+
+	// 回调中记录的错误不会影响下一个元素
+	var lengths []int
+	Parse(`[{"a": 1}, [1, 2]]`).ForEach(func(key string, value *JSON) bool {
+		value.Get("missing").Int()
+		if key == "0" {
+			value.err = ErrPathNotFound
+		}
+		lengths = append(lengths, value.Length())
+		return true
+	})
+	if len(lengths) != 2 || lengths[1] != 2 {
+		t.Errorf("state leaked between iterations: %v", lengths)
+	}
+}
+
+func TestIterationIndexKeys(t *testing.T) {
+	arr := benchmarkArray(indexKeyChunk*2 + 500)
+	next := 0
+	arr.ForEach(func(key string, value *JSON) bool {
+		if key != strconv.Itoa(next) || value.Get("id").Int() != next {
+			t.Fatalf("key %q at index %d", key, next)
+		}
+		next++
+		return true
+	})
+	if next != arr.Length() {
+		t.Errorf("visited %d of %d elements", next, arr.Length())
+	}
+
+	// 键可以在回调之外保留
+	var keys []string
+	arr.Filter(func(key string, value *JSON) bool {
+		keys = append(keys, key)
+		return false
+	})
+	if keys[150] != "150" || keys[indexKeyChunk+200] != strconv.Itoa(indexKeyChunk+200) {
+		t.Errorf("retained keys changed: %q, %q", keys[150], keys[indexKeyChunk+200])
+	}
+}
+
+func TestIterationOnScalar(t *testing.T) {
+	doc := Parse(`{"name": "tom", "tags": null}`)
+	name := doc.Get("name")
+
+	calls := 0
+	count := func(string, *JSON) bool { calls++; return true }
+	results := map[string]*JSON{
+		"ForEach":     name.ForEach(count),
+		"ForEachCopy": name.ForEachCopy(count),
+		"Filter":      name.Filter(count),
+		"Map":         name.Map(func(string, *JSON) interface{} { calls++; return nil }),
+		"MapE":        name.MapE(func(string, *JSON) (interface{}, error) { calls++; return nil, nil }),
+	}
+	for method, got := range results {
+		var typeErr *TypeError
+		if !errors.As(got.Error(), &typeErr) || typeErr.Path != "name" || typeErr.Actual != "string" {
+			t.Errorf("%s on scalar: error = %v", method, got.Error())
+		}
+	}
+	if calls != 0 {
+		t.Errorf("callback invoked %d times on scalar", calls)
+	}
+
+	// null 视为空集合
+	if err := doc.Get("tags").Map(func(string, *JSON) interface{} { return nil }).Error(); err != nil {
+		t.Errorf("Map on null: %v", err)
+	}
+}
+
+func TestMapElementErrors(t *testing.T) {
+	doc := Parse(`{"users": [{"name": "tom"}, {"id": 2}, {"id": 3}]}`)
+	users := doc.Get("users")
+
+	// Map 映射全部元素并合并元素错误
+	calls := 0
+	got := users.Map(func(key string, value *JSON) interface{} {
+		calls++
+		return value.Get("name")
+	})
+	err := got.Error()
+	if !errors.Is(err, ErrPathNotFound) || calls != 3 {
+		t.Fatalf("Map error = %v, calls = %d", err, calls)
+	}
+	for _, want := range []string{"users.1: ", "users.2: "} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Map error %q should mention %q", err, want)
+		}
+	}
+	if !reflect.DeepEqual(got.data, users.data) {
+		t.Error("failed Map should keep the receiver's data")
+	}
+
+	// MapE 在第一个错误处停止
+	calls = 0
+	boom := errors.New("boom")
+	got = users.MapE(func(key string, value *JSON) (interface{}, error) {
+		calls++
+		if !value.Has("name") {
+			return nil, boom
+		}
+		return value.Get("name").String(), nil
+	})
+	if !errors.Is(got.Error(), boom) || calls != 2 || !strings.HasPrefix(got.Error().Error(), "users.1: ") {
+		t.Errorf("MapE error = %v, calls = %d", got.Error(), calls)
+	}
+
+	// MapE 回调返回带错误的 *JSON 同样视为失败
+	if err := users.MapE(func(key string, value *JSON) (interface{}, error) {
+		return value.Get("name"), nil
+	}).Error(); !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("MapE with *JSON error = %v", err)
+	}
+
+	// 成功时与 Map 结果一致
+	got = Parse(`{"a": 1, "b": 2}`).MapE(func(key string, value *JSON) (interface{}, error) {
+		return fmt.Sprintf("%s=%d", key, value.Int()), nil
+	})
+	if got.Error() != nil || got.Get("b").String() != "b=2" {
+		t.Errorf("MapE = %v, %v", got.data, got.Error())
+	}
+
+	// 链上的第一个错误保留到末尾
+	chained := doc.Get("missing").Map(func(string, *JSON) interface{} { return nil }).Filter(func(string, *JSON) bool { return true })
+	if !errors.Is(chained.Error(), ErrPathNotFound) {
+		t.Errorf("chained error = %v", chained.Error())
+	}
+}
+
+// benchmarkArray 构造 n 个对象组成的数组
+func benchmarkArray(n int) *JSON {
+	items := make([]interface{}, n)
+	for i := range items {
+		items[i] = map[string]interface{}{"id": float64(i), "active": i%3 == 0}
+	}
+	return FromSlice(items)
+}
+
+func BenchmarkForEach(b *testing.B) {
+	arr := benchmarkArray(100000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		arr.ForEach(func(key string, value *JSON) bool {
+			return value.IsObject()
+		})
+	}
+}
+
+func BenchmarkForEachCopy(b *testing.B) {
+	arr := benchmarkArray(100000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		arr.ForEachCopy(func(key string, value *JSON) bool {
+			return value.IsObject()
+		})
+	}
+}
+
+func BenchmarkFilter(b *testing.B) {
+	arr := benchmarkArray(100000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		arr.Filter(func(key string, value *JSON) bool {
+			return value.IsObject()
+		})
+	}
+}
+
+func BenchmarkMap(b *testing.B) {
+	arr := benchmarkArray(100000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		arr.Map(func(key string, value *JSON) interface{} {
+			return value.IsObject()
+		})
+	}
+}
+
+func TestIterationObjectOrderIsSorted(t *testing.T) {
+	obj := Parse(`{"delta":4,"alpha":1,"charlie":3,"bravo":2,"echo":5,"10":0,"9":0}`)
+	want := []string{"10", "9", "alpha", "bravo", "charlie", "delta", "echo"}
+
+	for run := 0; run < 20; run++ {
+		var forEach, forEachCopy, mapped, filtered []string
+		obj.ForEach(func(key string, value *JSON) bool {
+			forEach = append(forEach, key)
+			return true
+		})
+		obj.ForEachCopy(func(key string, value *JSON) bool {
+			forEachCopy = append(forEachCopy, key)
+			return true
+		})
+		obj.Map(func(key string, value *JSON) interface{} {
+			mapped = append(mapped, key)
+			return nil
+		})
+		obj.Filter(func(key string, value *JSON) bool {
+			filtered = append(filtered, key)
+			return true
+		})
+
+		for name, got := range map[string][]string{"ForEach": forEach, "ForEachCopy": forEachCopy, "Map": mapped, "Filter": filtered} {
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("%s order = %v, want %v", name, got, want)
+			}
+		}
+	}
+
+	var values []int
+	for _, v := range obj.Values() {
+		values = append(values, v.Int())
+	}
+	if !reflect.DeepEqual(values, []int{0, 0, 1, 2, 3, 4, 5}) {
+		t.Errorf("Values should follow Keys order, got %v", values)
+	}
+
+	// 提前终止时只访问字典序靠前的键
+	var visited []string
+	obj.ForEach(func(key string, value *JSON) bool {
+		visited = append(visited, key)
+		return key != "alpha"
+	})
+	if !reflect.DeepEqual(visited, want[:3]) {
+		t.Errorf("early stop visited %v", visited)
+	}
+}
@@ -0,0 +1,67 @@
+package jsonx
+
+import "strings"
+
+// ParseLenient 宽松解析 JSONC：允许 // 与 /* */ 注释以及对象和数组末尾的多余逗号
+// 适用于人工编写的配置文件，字符串字面量中的 // 等内容保持不变
+func ParseLenient(jsonStr string) *JSON {
+	return Parse(stripJSONC(jsonStr, true))
+}
+
+// StripComments 移除 JSONC 中的 // 与 /* */ 注释，字符串字面量保持不变
+// 注释被替换为空白并保留换行，解析错误报告的行号与原文一致
+func StripComments(jsonStr string) string {
+	return stripJSONC(jsonStr, false)
+}
+
+// stripJSONC 扫描并移除注释，trailingCommas 为 true 时同时移除 } 与 ] 前的多余逗号
+func stripJSONC(src string, trailingCommas bool) string {
+	out := []byte(src)
+	// commaAt 最近一个跟在值之后的逗号，其后只出现空白与注释时遇到 } 或 ] 即为多余逗号
+	// 紧跟 { [ 或另一个逗号的逗号不处理，交由解析器报错
+	commaAt := -1
+	// prev 上一个非空白、非注释字符
+	var prev byte
+	for i := 0; i < len(out); i++ {
+		c := out[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			continue
+		case c == '"':
+			for i++; i < len(out) && out[i] != '"'; i++ {
+				if out[i] == '\\' {
+					i++
+				}
+			}
+		case c == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+			continue
+		case c == '/' && i+1 < len(out) && out[i+1] == '*':
+			// 未闭合的块注释原样保留，交由解析器报错
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return string(out)
+			}
+			end += i + 4
+			for ; i < end; i++ {
+				if out[i] != '\n' {
+					out[i] = ' '
+				}
+			}
+			i--
+			continue
+		case c == ',' && trailingCommas && prev != '{' && prev != '[' && prev != ',':
+			commaAt = i
+			prev = c
+			continue
+		case (c == '}' || c == ']') && commaAt >= 0:
+			out[commaAt] = ' '
+		}
+		commaAt = -1
+		prev = c
+	}
+
+	return string(out)
+}
@@ -0,0 +1,79 @@
+package jsonx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLenient(t *testing.T) {
+	src := `{
+	// 服务配置
+	"url": "http://example.com/a//b", /* 字符串中的 // 不是注释 */
+	"pattern": "/* keep */",
+	"quote": "say \"hi\" // still string",
+	"ports": [80, 443,], // 末尾逗号
+	"nested": {
+		"a": 1,
+		/* 块注释
+		   跨多行 */
+	},
+}`
+
+	j := ParseLenient(src)
+	if err := j.Error(); err != nil {
+		t.Fatalf("ParseLenient error: %v", err)
+	}
+
+	checks := map[string]string{
+		"url":     "http://example.com/a//b",
+		"pattern": "/* keep */",
+		"quote":   `say "hi" // still string`,
+	}
+	for path, want := range checks {
+		if got := j.Get(path).String(); got != want {
+			t.Errorf("%s = %q, want %q", path, got, want)
+		}
+	}
+	if got := j.Get("ports").Length(); got != 2 {
+		t.Errorf("ports length = %d, want 2", got)
+	}
+	if got := j.Get("nested.a").Int(); got != 1 {
+		t.Errorf("nested.a = %d, want 1", got)
+	}
+
+	// 严格解析拒绝同样的输入
+	if Parse(src).Error() == nil {
+		t.Error("Parse should reject JSONC")
+	}
+}
+
+func TestParseLenientInvalid(t *testing.T) {
+	for _, src := range []string{
+		`{"a": 1 /* unterminated`,
+		`[1,,]`,
+		`{,}`,
+	} {
+		if ParseLenient(src).Error() == nil {
+			t.Errorf("ParseLenient(%q) should fail", src)
+		}
+	}
+}
+
+func TestStripComments(t *testing.T) {
+	src := "{\n  \"a\": 1, // one\n  /* two\n  lines */ \"b\": \"//x\",\n}"
+	got := StripComments(src)
+
+	if strings.Count(got, "\n") != strings.Count(src, "\n") {
+		t.Errorf("line count changed: %q", got)
+	}
+	if strings.Contains(got, "one") || strings.Contains(got, "lines") {
+		t.Errorf("comments not stripped: %q", got)
+	}
+	if !strings.Contains(got, `"//x"`) {
+		t.Errorf("string literal modified: %q", got)
+	}
+	// StripComments 不处理末尾逗号
+	if !strings.Contains(got, `"//x",`) {
+		t.Errorf("trailing comma should be kept: %q", got)
+	}
+}
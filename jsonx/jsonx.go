@@ -4,7 +4,10 @@ package jsonx
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"reflect"
 	"sort"
 	"strconv"
 	"strings"
@@ -15,6 +18,7 @@ import (
 type JSON struct {
 	data interface{}
 	err  error
+	path string // 通过 Get / Index 得到时相对根的路径，用于错误信息
 }
 
 // New 创建一个新的 JSON 实例
@@ -22,18 +26,23 @@ func New(data interface{}) *JSON {
 	return &JSON{data: data}
 }
 
-// Parse 解析 JSON 字符串
+// FromError 创建携带错误的 JSON，供扩展包在链式调用中传递错误
+func FromError(err error) *JSON {
+	return &JSON{err: err}
+}
+
+// Parse 解析 JSON 字符串，语法错误为带有行列信息的 *SyntaxError
 func Parse(jsonStr string) *JSON {
-	var data interface{}
-	err := json.Unmarshal([]byte(jsonStr), &data)
-	return &JSON{data: data, err: err}
+	return ParseBytes([]byte(jsonStr))
 }
 
-// ParseBytes 解析 JSON 字节数组
+// ParseBytes 解析 JSON 字节数组，语法错误为带有行列信息的 *SyntaxError
 func ParseBytes(jsonBytes []byte) *JSON {
 	var data interface{}
-	err := json.Unmarshal(jsonBytes, &data)
-	return &JSON{data: data, err: err}
+	if err := json.Unmarshal(jsonBytes, &data); err != nil {
+		return &JSON{err: wrapSyntaxError(jsonBytes, err)}
+	}
+	return &JSON{data: data}
 }
 
 // Object 创建一个新的 JSON 对象
@@ -71,14 +80,20 @@ func (j *JSON) Get(path string) *JSON {
 	}
 
 	value, err := j.getByPath(path)
-	return &JSON{data: value, err: err}
+	return &JSON{data: value, err: err, path: joinJSONPath(j.path, path)}
 }
 
-// Set 设置指定路径的值
+// Set 设置指定路径的值，value 为 *JSON 时写入其数据（如 Set("a", jsonx.Null())）
 func (j *JSON) Set(path string, value interface{}) *JSON {
 	if j.err != nil {
 		return j
 	}
+	if node, ok := value.(*JSON); ok {
+		if node.err != nil {
+			return &JSON{data: j.data, err: node.err}
+		}
+		value = node.data
+	}
 
 	err := j.setByPath(path, value)
 	return &JSON{data: j.data, err: err}
@@ -94,8 +109,14 @@ func (j *JSON) Delete(path string) *JSON {
 	return &JSON{data: j.data, err: err}
 }
 
-// Has 检查指定路径是否存在
+// Has 是 Exists 的别名，为兼容已有调用保留：值为 null 的键也视为存在
+// 新代码建议使用意图更明确的 Exists（键存在即可）或 HasValue（存在且不为 null）
 func (j *JSON) Has(path string) bool {
+	return j.Exists(path)
+}
+
+// Exists 检查路径是否存在（键存在即可，值可以为 null），如 {"a": null} 中 Exists("a") 为 true
+func (j *JSON) Exists(path string) bool {
 	if j.err != nil {
 		return false
 	}
@@ -104,6 +125,16 @@ func (j *JSON) Has(path string) bool {
 	return err == nil
 }
 
+// HasValue 检查路径是否存在且值不为 null，如 {"a": null} 中 HasValue("a") 为 false
+func (j *JSON) HasValue(path string) bool {
+	if j.err != nil {
+		return false
+	}
+
+	value, err := j.getByPath(path)
+	return err == nil && value != nil
+}
+
 // 类型检查方法
 
 // IsObject 检查是否为对象
@@ -127,7 +158,7 @@ func (j *JSON) IsString() bool {
 // IsNumber 检查是否为数字
 func (j *JSON) IsNumber() bool {
 	switch j.data.(type) {
-	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, json.Number:
 		return true
 	default:
 		return false
@@ -142,7 +173,7 @@ func (j *JSON) IsBool() bool {
 
 // IsNull 检查是否为 null
 func (j *JSON) IsNull() bool {
-	return j.data == nil
+	return isNullValue(j.data)
 }
 
 // 类型转换方法
@@ -169,20 +200,14 @@ func (j *JSON) Int() int {
 		return 0
 	}
 
-	switch v := j.data.(type) {
-	case int:
+	if v, ok := j.data.(int); ok {
 		return v
-	case float64:
-		return int(v)
-	case string:
-		if i, err := strconv.Atoi(v); err == nil {
-			return i
-		}
 	}
-	return 0
+	return int(j.Int64())
 }
 
-// Int64 转换为 int64
+// Int64 转换为 int64，字符串支持十进制与科学计数法（如 "1e10"）
+// 由 Parse 解析的数字为 float64，超过 2^53 的整数已丢失精度，需要精确值时请使用 ParseUseNumber
 func (j *JSON) Int64() int64 {
 	if j.err != nil {
 		return 0
@@ -195,10 +220,20 @@ func (j *JSON) Int64() int64 {
 		return int64(v)
 	case float64:
 		return int64(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return int64(f)
+		}
 	case string:
 		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
 			return i
 		}
+		if n, ok := parseIntegerText(v); ok && n.IsInt64() {
+			return n.Int64()
+		}
 	}
 	return 0
 }
@@ -214,6 +249,10 @@ func (j *JSON) Float64() float64 {
 		return v
 	case int:
 		return float64(v)
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
 	case string:
 		if f, err := strconv.ParseFloat(v, 64); err == nil {
 			return f
@@ -271,11 +310,35 @@ func (j *JSON) Index(i int) *JSON {
 		return &JSON{err: fmt.Errorf("not an array")}
 	}
 
-	if i < 0 || i >= len(arr) {
+	idx, ok := resolveIndex(i, len(arr))
+	if !ok {
 		return &JSON{err: fmt.Errorf("index out of range")}
 	}
 
-	return &JSON{data: arr[i]}
+	return &JSON{data: arr[idx], path: joinJSONPath(j.path, strconv.Itoa(i))}
+}
+
+// Slice 截取数组 [start, end) 区间，返回新数组
+// 支持负数下标（-1 表示最后一个元素），越界时自动截断，区间为空时返回空数组
+func (j *JSON) Slice(start, end int) *JSON {
+	if j.err != nil {
+		return j
+	}
+
+	arr, ok := j.data.([]interface{})
+	if !ok {
+		return &JSON{err: fmt.Errorf("not an array")}
+	}
+
+	start = clampIndex(start, len(arr))
+	end = clampIndex(end, len(arr))
+	if start >= end {
+		return &JSON{data: make([]interface{}, 0)}
+	}
+
+	result := make([]interface{}, end-start)
+	copy(result, arr[start:end])
+	return &JSON{data: result}
 }
 
 // Append 向数组添加元素
@@ -348,7 +411,7 @@ func (j *JSON) Keys() []string {
 	return keys
 }
 
-// Values 获取对象的所有值
+// Values 获取对象的所有值，顺序与 Keys 一致
 func (j *JSON) Values() []*JSON {
 	if j.err != nil {
 		return nil
@@ -360,19 +423,75 @@ func (j *JSON) Values() []*JSON {
 	}
 
 	values := make([]*JSON, 0, len(obj))
-	for _, v := range obj {
-		values = append(values, &JSON{data: v})
+	for _, k := range j.Keys() {
+		values = append(values, &JSON{data: obj[k]})
 	}
 	return values
 }
 
 // 迭代方法
+//
+// ForEach / Map / Filter 在整个遍历过程中复用同一个 *JSON 传给回调，避免每个元素一次分配。
+// 回调收到的 value 仅在本次调用内有效，下一次回调时其内容会被替换：
+// 需要在回调之外保留元素时，应保存 value.Clone() 或 value.ToInterface()，或改用 ForEachCopy。
+// 通过 value.Get 等方法得到的新 *JSON 不受影响，可以安全保留。
+//
+// 数组按下标顺序遍历，对象按键的字典序遍历（与 Keys 一致），保证每次运行的回调顺序相同。
+// 在字符串、数字、布尔值上调用时设置 *TypeError 链式错误，null 视为空集合。
+
+// iterable 检查当前值能否遍历，标量返回类型错误
+func (j *JSON) iterable() error {
+	switch j.data.(type) {
+	case []interface{}, map[string]interface{}, nil:
+		return nil
+	}
+	return j.typeError("array or object")
+}
 
-// ForEach 遍历数组或对象
+// elementError 为元素错误附加元素路径
+func (j *JSON) elementError(key string, err error) error {
+	return fmt.Errorf("%s: %w", displayPath(joinJSONPath(j.path, key)), err)
+}
+
+// ForEach 遍历数组或对象，value 仅在回调内有效
 func (j *JSON) ForEach(fn func(key string, value *JSON) bool) *JSON {
 	if j.err != nil {
 		return j
 	}
+	if err := j.iterable(); err != nil {
+		return &JSON{data: j.data, err: err}
+	}
+
+	elem := &JSON{}
+	switch v := j.data.(type) {
+	case []interface{}:
+		var keys indexKeys
+		for i, item := range v {
+			*elem = JSON{data: item}
+			if !fn(keys.get(i), elem) {
+				break
+			}
+		}
+	case map[string]interface{}:
+		for _, k := range j.Keys() {
+			*elem = JSON{data: v[k]}
+			if !fn(k, elem) {
+				break
+			}
+		}
+	}
+
+	return j
+}
+
+// ForEachCopy 遍历数组或对象，每个元素使用独立的 *JSON，可在回调之外保留
+func (j *JSON) ForEachCopy(fn func(key string, value *JSON) bool) *JSON {
+	if j.err != nil {
+		return j
+	}
+	if err := j.iterable(); err != nil {
+		return &JSON{data: j.data, err: err}
+	}
 
 	switch v := j.data.(type) {
 	case []interface{}:
@@ -382,8 +501,8 @@ func (j *JSON) ForEach(fn func(key string, value *JSON) bool) *JSON {
 			}
 		}
 	case map[string]interface{}:
-		for k, item := range v {
-			if !fn(k, &JSON{data: item}) {
+		for _, k := range j.Keys() {
+			if !fn(k, &JSON{data: v[k]}) {
 				break
 			}
 		}
@@ -392,24 +511,74 @@ func (j *JSON) ForEach(fn func(key string, value *JSON) bool) *JSON {
 	return j
 }
 
-// Map 映射数组或对象的值
+// Map 映射数组或对象的值，value 仅在回调内有效；回调直接返回 value 时保存其副本
+// 回调返回带有错误的 *JSON 时继续映射其余元素，最后以 errors.Join 合并所有元素错误（附带元素路径）
+// 作为链式错误返回；需要在第一个错误处停止时使用 MapE
 func (j *JSON) Map(fn func(key string, value *JSON) interface{}) *JSON {
+	var errs []error
+	result := j.mapValues(func(key string, value *JSON) (interface{}, error) {
+		mapped := fn(key, value)
+		if r, ok := mapped.(*JSON); ok && r.err != nil {
+			errs = append(errs, j.elementError(key, r.err))
+		}
+		return detachElem(mapped, value), nil
+	})
+	if result.err == nil && len(errs) > 0 {
+		return &JSON{data: j.data, err: errors.Join(errs...)}
+	}
+	return result
+}
+
+// MapE 映射数组或对象的值，回调返回错误时立即停止，错误（附带元素路径）通过 Error() 返回
+// 回调返回带有错误的 *JSON 同样视为失败；value 仅在回调内有效
+func (j *JSON) MapE(fn func(key string, value *JSON) (interface{}, error)) *JSON {
+	return j.mapValues(func(key string, value *JSON) (interface{}, error) {
+		mapped, err := fn(key, value)
+		if err == nil {
+			if r, ok := mapped.(*JSON); ok {
+				err = r.err
+			}
+		}
+		if err != nil {
+			return nil, j.elementError(key, err)
+		}
+		return detachElem(mapped, value), nil
+	})
+}
+
+// mapValues Map 与 MapE 的公共实现，fn 返回错误时停止
+func (j *JSON) mapValues(fn func(key string, value *JSON) (interface{}, error)) *JSON {
 	if j.err != nil {
 		return j
 	}
+	if err := j.iterable(); err != nil {
+		return &JSON{data: j.data, err: err}
+	}
 
+	elem := &JSON{}
 	switch v := j.data.(type) {
 	case []interface{}:
+		var keys indexKeys
 		result := make([]interface{}, len(v))
 		for i, item := range v {
-			result[i] = fn(strconv.Itoa(i), &JSON{data: item})
+			*elem = JSON{data: item}
+			mapped, err := fn(keys.get(i), elem)
+			if err != nil {
+				return &JSON{data: j.data, err: err}
+			}
+			result[i] = mapped
 		}
 		return &JSON{data: result}
 
 	case map[string]interface{}:
-		result := make(map[string]interface{})
-		for k, item := range v {
-			result[k] = fn(k, &JSON{data: item})
+		result := make(map[string]interface{}, len(v))
+		for _, k := range j.Keys() {
+			*elem = JSON{data: v[k]}
+			mapped, err := fn(k, elem)
+			if err != nil {
+				return &JSON{data: j.data, err: err}
+			}
+			result[k] = mapped
 		}
 		return &JSON{data: result}
 	}
@@ -417,17 +586,23 @@ func (j *JSON) Map(fn func(key string, value *JSON) interface{}) *JSON {
 	return j
 }
 
-// Filter 过滤数组或对象
+// Filter 过滤数组或对象，value 仅在回调内有效
 func (j *JSON) Filter(fn func(key string, value *JSON) bool) *JSON {
 	if j.err != nil {
 		return j
 	}
+	if err := j.iterable(); err != nil {
+		return &JSON{data: j.data, err: err}
+	}
 
+	elem := &JSON{}
 	switch v := j.data.(type) {
 	case []interface{}:
+		var keys indexKeys
 		result := make([]interface{}, 0)
 		for i, item := range v {
-			if fn(strconv.Itoa(i), &JSON{data: item}) {
+			*elem = JSON{data: item}
+			if fn(keys.get(i), elem) {
 				result = append(result, item)
 			}
 		}
@@ -435,9 +610,10 @@ func (j *JSON) Filter(fn func(key string, value *JSON) bool) *JSON {
 
 	case map[string]interface{}:
 		result := make(map[string]interface{})
-		for k, item := range v {
-			if fn(k, &JSON{data: item}) {
-				result[k] = item
+		for _, k := range j.Keys() {
+			*elem = JSON{data: v[k]}
+			if fn(k, elem) {
+				result[k] = v[k]
 			}
 		}
 		return &JSON{data: result}
@@ -446,6 +622,137 @@ func (j *JSON) Filter(fn func(key string, value *JSON) bool) *JSON {
 	return j
 }
 
+// indexKeyChunk 每次批量生成的数组下标键数量
+const indexKeyChunk = 1024
+
+// indexKeys 按顺序生成数组下标键，每批键共享一个字符串，避免逐个分配
+type indexKeys struct {
+	chunk string
+	ends  []int
+	base  int
+}
+
+// get 返回下标 i 的键，i 需按递增顺序访问
+func (k *indexKeys) get(i int) string {
+	if i < 100 {
+		return strconv.Itoa(i) // 小整数由 strconv 缓存，不分配
+	}
+	if k.chunk == "" || i < k.base || i-k.base >= len(k.ends) {
+		k.fill(i)
+	}
+	start := 0
+	if i > k.base {
+		start = k.ends[i-k.base-1]
+	}
+	return k.chunk[start:k.ends[i-k.base]]
+}
+
+// fill 从下标 from 开始生成一批键
+func (k *indexKeys) fill(from int) {
+	buf := make([]byte, 0, indexKeyChunk*(len(strconv.Itoa(from))+1))
+	k.ends = k.ends[:0]
+	for i := from; i < from+indexKeyChunk; i++ {
+		buf = strconv.AppendInt(buf, int64(i), 10)
+		k.ends = append(k.ends, len(buf))
+	}
+	k.chunk = string(buf)
+	k.base = from
+}
+
+// detachElem 回调返回复用的 *JSON 本身时替换为独立副本，避免结果中的元素被后续迭代覆盖
+func detachElem(result interface{}, elem *JSON) interface{} {
+	if r, ok := result.(*JSON); ok && r == elem {
+		copied := *elem
+		return &copied
+	}
+	return result
+}
+
+// 查找方法
+
+// Find 返回第一个满足条件的元素，未找到时返回错误状态
+// 数组按索引顺序查找，对象按键的字典序查找
+func (j *JSON) Find(fn func(key string, value *JSON) bool) *JSON {
+	if j.err != nil {
+		return j
+	}
+
+	_, value, err := j.find(fn)
+	if err != nil {
+		return &JSON{err: err}
+	}
+	if value == nil {
+		return &JSON{err: fmt.Errorf("no matching element")}
+	}
+	return value
+}
+
+// FindIndex 返回数组中第一个满足条件的元素索引，未找到或非数组时返回 -1
+func (j *JSON) FindIndex(fn func(key string, value *JSON) bool) int {
+	if j.err != nil || !j.IsArray() {
+		return -1
+	}
+
+	key, value, _ := j.find(fn)
+	if value == nil {
+		return -1
+	}
+	idx, _ := strconv.Atoi(key)
+	return idx
+}
+
+// FindKey 返回第一个满足条件的元素的键（数组为索引字符串），未找到时返回 false
+func (j *JSON) FindKey(fn func(key string, value *JSON) bool) (string, bool) {
+	if j.err != nil {
+		return "", false
+	}
+
+	key, value, _ := j.find(fn)
+	return key, value != nil
+}
+
+// Some 判断是否有任一元素满足条件
+func (j *JSON) Some(fn func(key string, value *JSON) bool) bool {
+	_, ok := j.FindKey(fn)
+	return ok
+}
+
+// Every 判断是否所有元素都满足条件，空数组或空对象返回 true
+func (j *JSON) Every(fn func(key string, value *JSON) bool) bool {
+	if j.err != nil {
+		return false
+	}
+
+	_, value, err := j.find(func(key string, value *JSON) bool {
+		return !fn(key, value)
+	})
+	return err == nil && value == nil
+}
+
+// find 按确定顺序查找第一个满足条件的元素
+func (j *JSON) find(fn func(key string, value *JSON) bool) (string, *JSON, error) {
+	switch v := j.data.(type) {
+	case []interface{}:
+		for i, item := range v {
+			key := strconv.Itoa(i)
+			value := &JSON{data: item}
+			if fn(key, value) {
+				return key, value, nil
+			}
+		}
+	case map[string]interface{}:
+		for _, k := range j.Keys() {
+			value := &JSON{data: v[k]}
+			if fn(k, value) {
+				return k, value, nil
+			}
+		}
+	default:
+		return "", nil, fmt.Errorf("not an array or object")
+	}
+	return "", nil, nil
+}
+
 // 序列化方法
 
 // ToJSON 转换为 JSON 字符串
@@ -454,12 +761,12 @@ func (j *JSON) ToJSON() (string, error) {
 		return "", j.err
 	}
 
-	jsonBytes, err := json.Marshal(j.data)
+	jsonBytes, err := marshalJSON(j.data, false)
 	if err != nil {
 		return "", err
 	}
 
-	return bytesToString(jsonBytes), nil
+	return string(jsonBytes), nil
 }
 
 // ToPrettyJSON 转换为格式化的 JSON 字符串
@@ -468,12 +775,12 @@ func (j *JSON) ToPrettyJSON() (string, error) {
 		return "", j.err
 	}
 
-	jsonBytes, err := json.MarshalIndent(j.data, "", "  ")
+	jsonBytes, err := marshalJSON(j.data, true)
 	if err != nil {
 		return "", err
 	}
 
-	return bytesToString(jsonBytes), nil
+	return string(jsonBytes), nil
 }
 
 // ToBytes 转换为 JSON 字节数组
@@ -482,7 +789,7 @@ func (j *JSON) ToBytes() ([]byte, error) {
 		return nil, j.err
 	}
 
-	return json.Marshal(j.data)
+	return marshalJSON(j.data, false)
 }
 
 // ToMap 转换为 map
@@ -524,10 +831,32 @@ func (j *JSON) Clone() *JSON {
 		return &JSON{err: j.err}
 	}
 
-	cloned := deepClone(j.data)
+	cloned, err := deepClone(j.data)
+	if err != nil {
+		return &JSON{err: err}
+	}
 	return &JSON{data: cloned}
 }
 
+// CloneShallow 浅克隆，只复制顶层对象或数组
+//
+// 嵌套的对象与数组仍与原文档共享，适合只增删改顶层键的场景；
+// 修改嵌套值（如 Set("a.b", 1)）会同时影响原文档，此时应使用 Clone。
+func (j *JSON) CloneShallow() *JSON {
+	if j.err != nil {
+		return &JSON{err: j.err}
+	}
+
+	switch v := j.data.(type) {
+	case map[string]interface{}:
+		return &JSON{data: maps.Clone(v)}
+	case []interface{}:
+		return &JSON{data: append(make([]interface{}, 0, len(v)), v...)}
+	default:
+		return &JSON{data: v}
+	}
+}
+
 // Merge 合并另一个 JSON 对象
 func (j *JSON) Merge(other *JSON) *JSON {
 	if j.err != nil {
@@ -568,11 +897,25 @@ func (j *JSON) DeepMerge(other *JSON) *JSON {
 		return &JSON{data: j.data, err: other.err}
 	}
 
-	result := deepMerge(j.data, other.data)
+	result, err := deepMerge(j.data, other.data)
+	if err != nil {
+		return &JSON{data: j.data, err: err}
+	}
 	return &JSON{data: result}
 }
 
 // 错误处理
+//
+// 链式调用中的错误按以下规则传播：
+//   - 带有错误的 *JSON 上调用任何链式方法都原样返回自身，因此链上第一个错误会一直保留到末尾，
+//     只需在链的最后检查一次 Error()
+//   - 读取类方法（Get、Index、Where 等）失败时返回只带错误的新 *JSON，接收者不受影响
+//   - 修改类方法（Set、Delete、Merge 等）失败时返回带有原数据与错误的 *JSON
+//   - 取值方法（String、Int 等）在出错时返回零值；Try* 返回错误；Must* 触发 panic
+//   - ForEach / Map / Filter 在标量上调用时返回 *TypeError；Map 回调返回的 *JSON 带有错误时
+//     合并全部元素错误，MapE 在第一个错误处停止，元素错误都带有元素路径
+//
+// 常见错误可通过 errors.Is / errors.As 判断：ErrPathNotFound、*TypeError、ErrTooDeep、ErrCyclicData 等。
 
 // Error 获取错误信息
 func (j *JSON) Error() error {
@@ -623,42 +966,15 @@ func (j *JSON) MustJSON() string {
 
 // 内部方法
 
-// getByPath 根据路径获取值
+// getByPath 根据路径获取值，路径语法见 query.go 中的过滤片段说明，编译结果由 path.go 中的缓存复用
 func (j *JSON) getByPath(path string) (interface{}, error) {
-	if path == "" {
-		return j.data, nil
-	}
-
-	parts := strings.Split(path, ".")
-	current := j.data
-
-	for _, part := range parts {
-		// 检查是否为数组索引
-		if idx, err := strconv.Atoi(part); err == nil {
-			if arr, ok := current.([]interface{}); ok {
-				if idx < 0 || idx >= len(arr) {
-					return nil, fmt.Errorf("array index out of range: %d", idx)
-				}
-				current = arr[idx]
-				continue
-			}
-		}
-
-		// 处理对象属性
-		if obj, ok := current.(map[string]interface{}); ok {
-			if value, exists := obj[part]; exists {
-				current = value
-			} else {
-				return nil, fmt.Errorf("path not found: %s", path)
-			}
-		} else {
-			return nil, fmt.Errorf("cannot access property '%s' on non-object", part)
-		}
-	}
-
-	return current, nil
+	return lookupPath(path).get(j.data)
 }
 
+// MaxArrayIndex Set 时允许自动扩展到的最大数组索引（不含），防止误传大索引导致巨量内存分配
+// 设置为 0 或负数表示不限制
+var MaxArrayIndex = 10000
+
 // setByPath 根据路径设置值
 func (j *JSON) setByPath(path string, value interface{}) error {
 	if path == "" {
@@ -666,182 +982,97 @@ func (j *JSON) setByPath(path string, value interface{}) error {
 		return nil
 	}
 
-	parts := strings.Split(path, ".")
-
-	// 确保根数据结构存在
-	if j.data == nil {
-		if idx, err := strconv.Atoi(parts[0]); err == nil {
-			j.data = make([]interface{}, idx+1)
-		} else {
-			j.data = make(map[string]interface{})
-		}
+	data, err := setValue(j.data, lookupPath(path).segments, value)
+	if err != nil {
+		return err
 	}
-
-	return j.setByPathRecursive(j.data, parts, value, "")
+	j.data = data
+	return nil
 }
 
-// setByPathRecursive 递归设置路径值
-func (j *JSON) setByPathRecursive(current interface{}, parts []string, value interface{}, currentPath string) error {
-	if len(parts) == 0 {
-		return fmt.Errorf("empty path parts")
+// setValue 在 current 上按路径设置值，返回更新后的容器
+// 数组扩容会产生新的切片，由调用方写回父容器，因此任意深度的数组增长都能正确传播
+func setValue(current interface{}, segments []pathSegment, value interface{}) (interface{}, error) {
+	if len(segments) == 0 {
+		return value, nil
 	}
 
-	part := parts[0]
-	isLast := len(parts) == 1
-
-	// 如果是最后一个部分，直接设置值
-	if isLast {
-		if idx, err := strconv.Atoi(part); err == nil {
-			// 设置数组元素
-			if arr, ok := current.([]interface{}); ok {
-				if idx < 0 || idx >= 10000 {
-					return fmt.Errorf("invalid array index: %d", idx)
-				}
-				// 扩展数组
-				for len(arr) <= idx {
-					arr = append(arr, nil)
-				}
-				arr[idx] = value
-				j.updateDataReference(current, arr, currentPath)
-				return nil
-			}
-			return fmt.Errorf("cannot set array index on non-array")
-		} else {
-			// 设置对象属性
-			if obj, ok := current.(map[string]interface{}); ok {
-				obj[part] = value
-				return nil
-			}
-			return fmt.Errorf("cannot set property on non-object")
-		}
+	seg := segments[0]
+	if current == nil {
+		current = newContainer(seg)
 	}
 
-	// 不是最后一个部分，需要递归
-	nextPart := parts[1]
-	var nextCurrent interface{}
-
-	if idx, err := strconv.Atoi(part); err == nil {
-		// 当前部分是数组索引
-		if arr, ok := current.([]interface{}); ok {
-			if idx < 0 || idx >= 10000 {
-				return fmt.Errorf("invalid array index: %d", idx)
-			}
-			// 扩展数组
-			for len(arr) <= idx {
-				arr = append(arr, nil)
-			}
-
-			// 如果该位置为 nil，需要创建新的容器
-			if arr[idx] == nil {
-				if nextIdx, nextErr := strconv.Atoi(nextPart); nextErr == nil {
-					arr[idx] = make([]interface{}, nextIdx+1)
-				} else {
-					arr[idx] = make(map[string]interface{})
-				}
-			}
-
-			nextCurrent = arr[idx]
-			j.updateDataReference(current, arr, currentPath)
-		} else {
-			return fmt.Errorf("cannot access array index on non-array")
+	switch container := current.(type) {
+	case []interface{}:
+		if !seg.isIndex {
+			return nil, fmt.Errorf("cannot access property '%s' on array", seg.key)
 		}
-	} else {
-		// 当前部分是对象属性
-		if obj, ok := current.(map[string]interface{}); ok {
-			if _, exists := obj[part]; !exists {
-				// 创建新的容器
-				if _, nextErr := strconv.Atoi(nextPart); nextErr == nil {
-					obj[part] = make([]interface{}, 0)
-				} else {
-					obj[part] = make(map[string]interface{})
-				}
-			}
-			nextCurrent = obj[part]
-		} else {
-			return fmt.Errorf("cannot access property on non-object")
+		idx := seg.index
+		if idx < 0 {
+			idx += len(container)
+		}
+		if idx < 0 || (MaxArrayIndex > 0 && idx >= MaxArrayIndex) {
+			return nil, fmt.Errorf("invalid array index: %s", seg.key)
 		}
-	}
-
-	// 递归处理剩余路径
-	nextPath := currentPath
-	if nextPath != "" {
-		nextPath += "."
-	}
-	nextPath += part
-
-	return j.setByPathRecursive(nextCurrent, parts[1:], value, nextPath)
-}
 
-// updateDataReference 更新数据引用
-func (j *JSON) updateDataReference(oldRef, newRef interface{}, path string) {
-	if path == "" {
-		j.data = newRef
-		return
-	}
+		// 扩展数组
+		for len(container) <= idx {
+			container = append(container, nil)
+		}
 
-	// 更新嵌套引用的逻辑
-	parts := strings.Split(path, ".")
-	current := j.data
+		child, err := setValue(container[idx], segments[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		container[idx] = child
+		return container, nil
 
-	for i, part := range parts {
-		if i == len(parts)-1 {
-			// 最后一个部分，更新引用
-			if obj, ok := current.(map[string]interface{}); ok {
-				obj[part] = newRef
-			}
-			break
+	case map[string]interface{}:
+		child, err := setValue(container[seg.key], segments[1:], value)
+		if err != nil {
+			return nil, err
 		}
+		container[seg.key] = child
+		return container, nil
 
-		// 继续深入
-		if idx, err := strconv.Atoi(part); err == nil {
-			if arr, ok := current.([]interface{}); ok && idx >= 0 && idx < len(arr) {
-				current = arr[idx]
-			}
-		} else {
-			if obj, ok := current.(map[string]interface{}); ok {
-				current = obj[part]
-			}
+	default:
+		if seg.isIndex {
+			return nil, fmt.Errorf("cannot set array index on non-array")
 		}
+		return nil, fmt.Errorf("cannot set property on non-object")
 	}
 }
 
+// newContainer 根据路径片段创建容器：数字创建数组，否则创建对象
+func newContainer(seg pathSegment) interface{} {
+	if seg.isIndex {
+		return make([]interface{}, 0)
+	}
+	return make(map[string]interface{})
+}
+
 // deleteByPath 根据路径删除值
+// 父路径与 Get 使用相同的编译结果解析（负数下标、转义的过滤片段等），父路径必须解析为对象；
+// 与 Set 一样，无法解析的过滤片段按普通键处理
 func (j *JSON) deleteByPath(path string) error {
 	if path == "" {
 		j.data = nil
 		return nil
 	}
 
-	parts := strings.Split(path, ".")
-	current := j.data
-
-	// 遍历到最后一个部分之前
-	for _, part := range parts[:len(parts)-1] {
-		if idx, err := strconv.Atoi(part); err == nil {
-			if arr, ok := current.([]interface{}); ok {
-				if idx < 0 || idx >= len(arr) {
-					return fmt.Errorf("array index out of range: %d", idx)
-				}
-				current = arr[idx]
-				continue
-			}
-		}
-
-		if obj, ok := current.(map[string]interface{}); ok {
-			if value, exists := obj[part]; exists {
-				current = value
-			} else {
-				return fmt.Errorf("path not found: %s", path)
-			}
-		} else {
-			return fmt.Errorf("cannot access property '%s' on non-object", part)
+	segments := lookupPath(path).segments
+	parent := j.data
+	if len(segments) > 1 {
+		var err error
+		parent, err = getBySegments(j.data, segments[:len(segments)-1], path)
+		if err != nil {
+			return err
 		}
 	}
 
 	// 删除最后一个部分
-	lastPart := parts[len(parts)-1]
-	if obj, ok := current.(map[string]interface{}); ok {
-		delete(obj, lastPart)
+	if obj, ok := parent.(map[string]interface{}); ok {
+		delete(obj, segments[len(segments)-1].key)
 		return nil
 	}
 
@@ -888,6 +1119,31 @@ func (j *JSON) updateArrayAtPath(path []string, newArray []interface{}) {
 
 // 工具函数
 
+// resolveIndex 解析数组下标，负数表示从末尾倒数
+func resolveIndex(idx, length int) (int, bool) {
+	if idx < 0 {
+		idx += length
+	}
+	if idx < 0 || idx >= length {
+		return 0, false
+	}
+	return idx, true
+}
+
+// clampIndex 将切片边界规范到 [0, length] 区间，负数表示从末尾倒数
+func clampIndex(idx, length int) int {
+	if idx < 0 {
+		idx += length
+	}
+	if idx < 0 {
+		return 0
+	}
+	if idx > length {
+		return length
+	}
+	return idx
+}
+
 // structToMap 将结构体转换为 map
 func structToMap(v interface{}) (map[string]interface{}, error) {
 	data, err := json.Marshal(v)
@@ -900,33 +1156,113 @@ func structToMap(v interface{}) (map[string]interface{}, error) {
 	return result, err
 }
 
-// deepClone 深度克隆
-func deepClone(v interface{}) interface{} {
+// deepClone 深度克隆，遇到循环引用时返回 ErrCyclicData
+func deepClone(v interface{}) (interface{}, error) {
+	c := &cloner{}
+	return c.clone(v)
+}
+
+// cloneSlabSize 克隆时批量分配数组元素的块大小
+const cloneSlabSize = 1024
+
+// cloner 深度克隆器
+//
+// 小数组的元素从预先分配的块中切出（容量与长度相同，追加时会重新分配而不会覆盖相邻数组），
+// 标量直接复制而不进入递归，大文档克隆时可显著减少分配次数与函数调用。
+type cloner struct {
+	guard cycleGuard
+	slab  []interface{}
+}
+
+// clone 带循环检测的递归克隆
+func (c *cloner) clone(v interface{}) (interface{}, error) {
 	switch val := v.(type) {
 	case map[string]interface{}:
-		result := make(map[string]interface{})
-		for k, v := range val {
-			result[k] = deepClone(v)
+		ptr, err := c.guard.enter(val)
+		if err != nil {
+			return nil, err
 		}
-		return result
+		// maps.Clone 直接复制哈希表结构，比逐个插入快得多，之后只需替换其中的容器
+		result := maps.Clone(val)
+		for k, item := range result {
+			if !isContainer(item) {
+				continue
+			}
+			cloned, err := c.clone(item)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = cloned
+		}
+		c.guard.leave(ptr)
+		return result, nil
 	case []interface{}:
-		result := make([]interface{}, len(val))
-		for i, v := range val {
-			result[i] = deepClone(v)
+		ptr, err := c.guard.enter(val)
+		if err != nil {
+			return nil, err
+		}
+		result := c.allocSlice(len(val))
+		for i, item := range val {
+			if !isContainer(item) {
+				result[i] = item
+				continue
+			}
+			cloned, err := c.clone(item)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = cloned
 		}
-		return result
+		c.guard.leave(ptr)
+		return result, nil
 	default:
-		return v
+		return v, nil
+	}
+}
+
+// allocSlice 分配长度为 n 的数组，小数组从块中切出
+func (c *cloner) allocSlice(n int) []interface{} {
+	if n == 0 {
+		// 块尚未分配时切出的是 nil，会被序列化为 null
+		return []interface{}{}
+	}
+	if n > cloneSlabSize/8 {
+		return make([]interface{}, n)
+	}
+	if len(c.slab) < n {
+		c.slab = make([]interface{}, cloneSlabSize)
+	}
+	result := c.slab[:n:n]
+	c.slab = c.slab[n:]
+	return result
+}
+
+// isContainer 判断值是否为需要深拷贝的对象或数组
+func isContainer(v interface{}) bool {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
 	}
+	return false
 }
 
-// deepMerge 深度合并
-func deepMerge(dst, src interface{}) interface{} {
+// deepMerge 深度合并，遇到循环引用时返回 ErrCyclicData
+func deepMerge(dst, src interface{}) (interface{}, error) {
+	return mergeValue(dst, src, &cloner{})
+}
+
+// mergeValue 带循环检测的递归合并，源对象中的值通过 c 克隆
+func mergeValue(dst, src interface{}, c *cloner) (interface{}, error) {
 	dstMap, dstOk := dst.(map[string]interface{})
 	srcMap, srcOk := src.(map[string]interface{})
 
 	if dstOk && srcOk {
-		result := make(map[string]interface{})
+		ptr, err := c.guard.enter(srcMap)
+		if err != nil {
+			return nil, err
+		}
+
+		result := make(map[string]interface{}, len(dstMap)+len(srcMap))
 
 		// 复制目标对象
 		for k, v := range dstMap {
@@ -935,30 +1271,102 @@ func deepMerge(dst, src interface{}) interface{} {
 
 		// 深度合并源对象
 		for k, v := range srcMap {
+			var merged interface{}
 			if dstVal, exists := result[k]; exists {
-				result[k] = deepMerge(dstVal, v)
+				merged, err = mergeValue(dstVal, v, c)
 			} else {
-				result[k] = deepClone(v)
+				merged, err = c.clone(v)
 			}
+			if err != nil {
+				return nil, err
+			}
+			result[k] = merged
 		}
 
-		return result
+		c.guard.leave(ptr)
+		return result, nil
+	}
+
+	return c.clone(src)
+}
+
+// ErrCyclicData 数据中存在循环引用（如 map 直接或间接包含自身）
+var ErrCyclicData = errors.New("jsonx: cyclic data detected")
+
+// ErrTooDeep 数据嵌套层数超过 maxNestingDepth，递归处理会有栈溢出风险
+var ErrTooDeep = fmt.Errorf("jsonx: nesting depth exceeds %d", maxNestingDepth)
+
+// cycleCheckDepth 嵌套超过该深度后才开始记录容器指针，正常文档无额外开销
+const cycleCheckDepth = 1000
+
+// maxNestingDepth 递归处理允许的最大嵌套层数，与 encoding/json 解析的上限一致
+const maxNestingDepth = 10000
+
+// cycleGuard 循环引用检测器，记录当前递归路径上的容器指针
+type cycleGuard struct {
+	depth int
+	seen  map[uintptr]struct{}
+}
+
+// enter 进入一个容器，若该容器已在当前路径上则返回 ErrCyclicData，嵌套过深时返回 ErrTooDeep
+func (g *cycleGuard) enter(container interface{}) (uintptr, error) {
+	g.depth++
+	if g.depth > maxNestingDepth {
+		return 0, ErrTooDeep
+	}
+	if g.depth <= cycleCheckDepth {
+		return 0, nil
 	}
 
-	return deepClone(src)
+	ptr := containerPointer(container)
+	if ptr == 0 {
+		return 0, nil
+	}
+	if g.seen == nil {
+		g.seen = make(map[uintptr]struct{})
+	}
+	if _, exists := g.seen[ptr]; exists {
+		return 0, ErrCyclicData
+	}
+	g.seen[ptr] = struct{}{}
+	return ptr, nil
+}
+
+// leave 离开一个容器
+func (g *cycleGuard) leave(ptr uintptr) {
+	g.depth--
+	if ptr != 0 {
+		delete(g.seen, ptr)
+	}
 }
 
-// bytesToString 高效的字节数组转字符串
-func bytesToString(b []byte) string {
-	return *(*string)(unsafe.Pointer(&b))
+// containerPointer 获取 map 或 slice 底层数据的指针
+func containerPointer(container interface{}) uintptr {
+	switch v := container.(type) {
+	case map[string]interface{}:
+		return reflect.ValueOf(v).Pointer()
+	case []interface{}:
+		if len(v) == 0 {
+			return 0
+		}
+		return uintptr(unsafe.Pointer(&v[0]))
+	}
+	return 0
 }
 
-// stringToBytes 高效的字符串转字节数组
-func stringToBytes(s string) []byte {
-	return *(*[]byte)(unsafe.Pointer(
-		&struct {
-			string
-			Cap int
-		}{s, len(s)},
-	))
+// marshalJSON 序列化数据，将循环引用错误转换为 ErrCyclicData
+func marshalJSON(v interface{}, indent bool) ([]byte, error) {
+	var data []byte
+	var err error
+	if indent {
+		data, err = json.MarshalIndent(v, "", "  ")
+	} else {
+		data, err = json.Marshal(v)
+	}
+
+	var unsupported *json.UnsupportedValueError
+	if errors.As(err, &unsupported) && strings.Contains(unsupported.Str, "cycle") {
+		return nil, fmt.Errorf("%w: %v", ErrCyclicData, err)
+	}
+	return data, err
 }
@@ -1,7 +1,9 @@
 package jsonx
 
 import (
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestBasicOperations(t *testing.T) {
@@ -64,6 +66,48 @@ func TestPathOperations(t *testing.T) {
 	}
 }
 
+func TestExistsAndHasValue(t *testing.T) {
+	j := Parse(`{"a": null, "b": {"c": null, "d": 0}, "list": [null, 1]}`)
+	if j.Error() != nil {
+		t.Fatal(j.Error())
+	}
+
+	tests := []struct {
+		path             string
+		exists, hasValue bool
+	}{
+		{"a", true, false},
+		{"b", true, true},
+		{"b.c", true, false},
+		{"b.d", true, true},
+		{"b.e", false, false},
+		{"a.x", false, false},
+		{"list.0", true, false},
+		{"list.1", true, true},
+		{"list.2", false, false},
+		{"list.-1", true, true},
+		{"list.-2", true, false},
+		{"list.-3", false, false},
+		{"missing", false, false},
+	}
+	for _, tt := range tests {
+		if got := j.Exists(tt.path); got != tt.exists {
+			t.Errorf("Exists(%q) = %v, want %v", tt.path, got, tt.exists)
+		}
+		if got := j.Has(tt.path); got != tt.exists {
+			t.Errorf("Has(%q) = %v, want %v", tt.path, got, tt.exists)
+		}
+		if got := j.HasValue(tt.path); got != tt.hasValue {
+			t.Errorf("HasValue(%q) = %v, want %v", tt.path, got, tt.hasValue)
+		}
+	}
+
+	safe := Safe(j)
+	if !safe.Exists("b.c") || safe.HasValue("b.c") {
+		t.Error("SafeJSON should follow the same null semantics")
+	}
+}
+
 func TestArrayOperations(t *testing.T) {
 	arr := Array()
 
@@ -177,6 +221,65 @@ func TestBuilder(t *testing.T) {
 	}
 }
 
+func TestBuilderNested(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	obj := NewBuilder().
+		AddTime("created", ts, "").
+		AddTime("day", ts, "2006-01-02").
+		AddRawJSON("meta", `{"tags": ["a", "b"], "n": 1}`).
+		AddObjectFn("user", func(b *Builder) {
+			b.AddString("name", "test").
+				AddArrayFn("roles", func(b *Builder) {
+					b.AppendString("admin").
+						AppendObjectFn(func(b *Builder) {
+							b.AddString("scope", "read")
+						})
+				})
+		}).
+		Build()
+
+	if err := obj.Error(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, _ := obj.ToJSON()
+	want := `{"created":"2024-01-02T03:04:05Z","day":"2024-01-02","meta":{"n":1,"tags":["a","b"]},"user":{"name":"test","roles":["admin",{"scope":"read"}]}}`
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	b := NewBuilder().
+		AddString("name", "test").
+		AddRawJSON("bad", `{"a":`).
+		AddObjectFn("nested", func(b *Builder) {
+			b.AddRawJSON("inner", `[1,`)
+		})
+
+	obj := b.Build()
+	err := obj.Error()
+	if err == nil {
+		t.Fatal("expected accumulated error")
+	}
+	for _, want := range []string{"bad: invalid raw JSON", "nested.inner: invalid raw JSON"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+	// 有效字段仍然保留
+	if data, _ := obj.data.(map[string]interface{}); data["name"] != "test" {
+		t.Errorf("valid fields should be kept, got %v", obj.data)
+	}
+	if _, err := b.BuildString(); err == nil {
+		t.Error("BuildString should surface builder errors")
+	}
+
+	// 对对象构建器追加元素也会记录错误
+	if err := NewBuilder().AppendInt(1).Build().Error(); err == nil {
+		t.Error("expected error appending to object builder")
+	}
+}
+
 func TestQuickFunctions(t *testing.T) {
 	// 测试快速对象创建
 	obj := QuickObject(map[string]interface{}{
@@ -437,6 +540,33 @@ func TestFlatten(t *testing.T) {
 	if unflattened.Get("user.profile.age").Int() != 25 {
 		t.Error("Unflatten should restore nested values")
 	}
+
+	if !unflattened.Get("items").IsArray() {
+		t.Error("Unflatten should restore arrays")
+	}
+}
+
+func TestFlattenUnflattenRoundTrip(t *testing.T) {
+	docs := []string{
+		`{}`,
+		`[]`,
+		`[1,2,3]`,
+		`"scalar"`,
+		`{"a":[1,{"b":[2,[3,4]]}],"c":{"d":null,"e":true}}`,
+		`{"matrix":[[1,2],[3,4]],"empty":{"arr":[],"obj":{}}}`,
+		`{"users":[{"name":"a","tags":["x","y"]},{"name":"b","tags":[]}]}`,
+		`{"ids":{"1":"one","2":"two"},"sparse":{"0":"a","2":"c"}}`,
+		`[{"items":[{"id":1},{"id":2}]},null,"text",12.5]`,
+	}
+
+	for _, doc := range docs {
+		j := Parse(doc)
+		restored := Unflatten(Flatten(j))
+		if !Equal(restored, j) {
+			got, _ := restored.ToJSON()
+			t.Errorf("Unflatten(Flatten(%s)) = %s", doc, got)
+		}
+	}
 }
 
 func TestPickAndOmit(t *testing.T) {
@@ -544,3 +674,231 @@ func TestErrorHandling(t *testing.T) {
 		t.Error("Append on non-array should produce an error")
 	}
 }
+
+func TestFindAndPredicates(t *testing.T) {
+	users := Parse(`[
+		{"name": "alice", "age": 17},
+		{"name": "bob", "age": 25},
+		{"name": "carol", "age": 31}
+	]`)
+
+	adult := func(key string, value *JSON) bool {
+		return value.Get("age").Int() >= 18
+	}
+
+	if name := users.Find(adult).Get("name").String(); name != "bob" {
+		t.Errorf("Expected first adult 'bob', got '%s'", name)
+	}
+	if idx := users.FindIndex(adult); idx != 1 {
+		t.Errorf("Expected index 1, got %d", idx)
+	}
+	if !users.Some(adult) {
+		t.Error("Some should be true")
+	}
+	if users.Every(adult) {
+		t.Error("Every should be false")
+	}
+
+	none := users.Find(func(key string, value *JSON) bool { return false })
+	if none.Error() == nil {
+		t.Error("Find without match should set an error")
+	}
+	if idx := users.FindIndex(func(key string, value *JSON) bool { return false }); idx != -1 {
+		t.Errorf("Expected -1, got %d", idx)
+	}
+
+	// 对象按键的字典序查找
+	obj := Parse(`{"b": 2, "a": 1, "c": 3}`)
+	key, ok := obj.FindKey(func(key string, value *JSON) bool { return value.Int() > 1 })
+	if !ok || key != "b" {
+		t.Errorf("Expected key 'b', got '%s'", key)
+	}
+	if !obj.Every(func(key string, value *JSON) bool { return value.IsNumber() }) {
+		t.Error("Every should be true for all numbers")
+	}
+	if !Array().Every(adult) {
+		t.Error("Every should be true for an empty array")
+	}
+
+	// 错误传播
+	broken := Parse(`{invalid`)
+	if broken.Find(adult).Error() == nil || broken.Some(adult) || broken.Every(adult) {
+		t.Error("Errors should propagate through Find/Some/Every")
+	}
+}
+
+func TestNegativeIndexAndSlice(t *testing.T) {
+	j := Parse(`{"events": [{"id": 1}, {"id": 2}, {"id": 3}, {"id": 4}, {"id": 5}]}`)
+
+	if id := j.Get("events.-1.id").Int(); id != 5 {
+		t.Errorf("Expected last id=5, got %d", id)
+	}
+	if id := j.Get("events.-5.id").Int(); id != 1 {
+		t.Errorf("Expected first id=1, got %d", id)
+	}
+	if j.Has("events.-6") {
+		t.Error("Path 'events.-6' should not exist")
+	}
+	if id := j.Get("events").Index(-2).Get("id").Int(); id != 4 {
+		t.Errorf("Expected Index(-2) id=4, got %d", id)
+	}
+
+	j.Set("events.-1.id", 50)
+	if id := j.Get("events.4.id").Int(); id != 50 {
+		t.Errorf("Expected id=50 after Set with negative index, got %d", id)
+	}
+
+	events := j.Get("events")
+	tests := []struct {
+		start, end int
+		expected   []int
+	}{
+		{-2, 5, []int{4, 50}},
+		{0, 2, []int{1, 2}},
+		{1, -1, []int{2, 3, 4}},
+		{-100, 100, []int{1, 2, 3, 4, 50}},
+		{3, 1, []int{}},
+		{10, 20, []int{}},
+	}
+
+	for _, test := range tests {
+		slice := events.Slice(test.start, test.end)
+		if slice.Error() != nil {
+			t.Errorf("Slice(%d, %d) error: %v", test.start, test.end, slice.Error())
+			continue
+		}
+		ids := make([]int, 0)
+		slice.ForEach(func(key string, value *JSON) bool {
+			ids = append(ids, value.Get("id").Int())
+			return true
+		})
+		if len(ids) != len(test.expected) {
+			t.Errorf("Slice(%d, %d) = %v, want %v", test.start, test.end, ids, test.expected)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != test.expected[i] {
+				t.Errorf("Slice(%d, %d) = %v, want %v", test.start, test.end, ids, test.expected)
+				break
+			}
+		}
+	}
+
+	if Object().Slice(0, 1).Error() == nil {
+		t.Error("Slice on non-array should set an error")
+	}
+}
+
+func TestSetSparseNestedArrays(t *testing.T) {
+	j := Object().Set("a.0.b.3.c.7", "deep")
+	if j.Error() != nil {
+		t.Fatalf("Set error: %v", j.Error())
+	}
+
+	expected := `{"a":[{"b":[null,null,null,{"c":[null,null,null,null,null,null,null,"deep"]}]}]}`
+	if got, _ := j.ToJSON(); got != expected {
+		t.Errorf("unexpected structure:\n got  %s\n want %s", got, expected)
+	}
+
+	// 数组→数组→对象链上的扩容
+	j = Parse(`{"data":[{"info":{"tags":["x"]}}]}`)
+	j.Set("data.0.info.tags.5", "y").Set("data.2.1.name", "n")
+	if j.Error() != nil {
+		t.Fatalf("Set error: %v", j.Error())
+	}
+	if j.Get("data.0.info.tags").Length() != 6 || j.Get("data.0.info.tags.5").String() != "y" {
+		t.Errorf("tags not extended: %v", j.Get("data.0.info.tags").data)
+	}
+	if j.Get("data.0.info.tags.0").String() != "x" {
+		t.Error("existing element lost after growth")
+	}
+	if j.Get("data.2.1.name").String() != "n" || j.Get("data").Length() != 3 {
+		t.Errorf("nested array growth lost: %v", j.Get("data").data)
+	}
+
+	// 根数组扩容
+	root := Array().Set("2.1", true)
+	if got, _ := root.ToJSON(); got != `[null,null,[null,true]]` {
+		t.Errorf("root array growth = %s", got)
+	}
+
+	// 索引上限可配置
+	if Array().Set("20000", 1).Error() == nil {
+		t.Error("index beyond MaxArrayIndex should fail")
+	}
+	defer func(limit int) { MaxArrayIndex = limit }(MaxArrayIndex)
+	MaxArrayIndex = 0
+	if big := Array().Set("20000", 1); big.Error() != nil || big.Length() != 20001 {
+		t.Errorf("unlimited index failed: %v", big.Error())
+	}
+
+	if Object().Set("name", "x").Set("name.first", "y").Error() == nil {
+		t.Error("setting property on scalar should fail")
+	}
+}
+
+func TestTemplateBuilder(t *testing.T) {
+	template := `{
+		"user": "{{username}}",
+		"greeting": "Hello, {{username}}! You have {{count}} messages",
+		"profile": {{profile}},
+		"tags": {{tags}},
+		"count": {{count}},
+		"role": "{{role|guest}}",
+		"limit": {{limit|10}},
+		"extra": {{extra|[]}},
+		"note": {{note|none}}
+	}`
+
+	j := NewTemplate(template).
+		Set("username", `张"三`).
+		Set("count", 3).
+		Set("profile", map[string]interface{}{"age": 30, "city": "北京"}).
+		Set("tags", []string{"a", "b"}).
+		Build()
+	if j.Error() != nil {
+		t.Fatalf("Build error: %v", j.Error())
+	}
+
+	checks := map[string]interface{}{
+		"user":         `张"三`,
+		"greeting":     `Hello, 张"三! You have 3 messages`,
+		"profile.city": "北京",
+		"tags.1":       "b",
+		"role":         "guest",
+		"note":         "none",
+	}
+	for path, want := range checks {
+		if got := j.Get(path).String(); got != want {
+			t.Errorf("%s = %q, want %q", path, got, want)
+		}
+	}
+	if j.Get("count").Int() != 3 || j.Get("limit").Int() != 10 || !j.Get("extra").IsArray() {
+		t.Errorf("unexpected numeric/default values: %v", j.data)
+	}
+
+	// 嵌入 *JSON
+	nested := NewTemplate(`{"data": {{payload}}}`).Set("payload", QuickArray(1, 2)).Build()
+	if nested.Get("data").Length() != 2 {
+		t.Errorf("*JSON value not substituted: %v", nested.data)
+	}
+}
+
+func TestTemplateBuilderMissing(t *testing.T) {
+	template := `{"name": "{{name}}", "age": {{age}}, "city": "{{city|unknown}}", "bio": "about {{name}}"}`
+
+	lenient := NewTemplate(template).Build()
+	if lenient.Error() != nil || !lenient.Get("name").IsNull() || lenient.Get("bio").String() != "about " {
+		t.Errorf("Build should fill missing placeholders with null: %v %v", lenient.data, lenient.Error())
+	}
+
+	_, err := NewTemplate(template).BuildStrict()
+	if err == nil || err.Error() != "template placeholders not set: age, name" {
+		t.Errorf("unexpected BuildStrict error: %v", err)
+	}
+
+	j, err := NewTemplate(template).Set("name", "tom").Set("age", 20).BuildStrict()
+	if err != nil || j.Get("city").String() != "unknown" {
+		t.Errorf("BuildStrict with all values = %v, %v", j, err)
+	}
+}
@@ -0,0 +1,192 @@
+package jsonx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// 分层配置加载：默认配置 → 环境覆盖 → 环境变量覆盖
+
+// Source LoadLayered 的配置来源，通过 FileSource、BytesSource、EnvSource 等创建
+type Source struct {
+	name string
+	load func() (map[string]interface{}, error) // 返回 nil 表示该来源为空
+}
+
+// Name 返回来源名称，如 "file:defaults.json"、"env:APP_"
+func (s Source) Name() string {
+	return s.name
+}
+
+// Provenance 记录合并结果中每个叶子路径的值来自第几个来源（从 0 开始）
+// 数组整体视为叶子；空对象仅在其下没有其他叶子时记录
+type Provenance map[string]int
+
+// FileSource 从文件读取配置，支持 JSONC 注释与尾随逗号，文件不存在时返回错误
+func FileSource(path string) Source {
+	return Source{name: "file:" + path, load: func() (map[string]interface{}, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return decodeSource(ParseLenient(string(data)))
+	}}
+}
+
+// OptionalFileSource 与 FileSource 相同，但文件不存在时视为空来源
+func OptionalFileSource(path string) Source {
+	file := FileSource(path)
+	return Source{name: file.name, load: func() (map[string]interface{}, error) {
+		obj, err := file.load()
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return obj, err
+	}}
+}
+
+// BytesSource 从内存中的 JSON（支持 JSONC）读取配置，name 仅用于错误信息
+func BytesSource(name string, data []byte) Source {
+	return Source{name: "bytes:" + name, load: func() (map[string]interface{}, error) {
+		return decodeSource(ParseLenient(string(data)))
+	}}
+}
+
+// EnvSource 读取以 prefix 开头的环境变量，默认映射去掉前缀后转为小写并以 . 替换 _，
+// 如 prefix 为 "APP" 时 APP_DB_HOST → db.host，APP_SERVERS_0 → servers.0
+//
+// prefix 末尾的 _ 可省略；mapper 可替换默认的键映射，返回空字符串表示忽略该变量。
+// 值为 JSON 数字或 true/false 时按对应类型写入，其余按字符串写入。
+func EnvSource(prefix string, mapper ...func(key string) string) Source {
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+	mapKey := defaultEnvMapper
+	if len(mapper) > 0 && mapper[0] != nil {
+		mapKey = mapper[0]
+	}
+
+	return Source{name: "env:" + prefix, load: func() (map[string]interface{}, error) {
+		doc := Object()
+		for _, kv := range os.Environ() {
+			key, value, _ := strings.Cut(kv, "=")
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			path := mapKey(strings.TrimPrefix(key, prefix))
+			if path == "" {
+				continue
+			}
+			if err := doc.setByPath(path, envValue(value)); err != nil {
+				return nil, fmt.Errorf("environment variable %s: %w", key, err)
+			}
+		}
+		return doc.data.(map[string]interface{}), nil
+	}}
+}
+
+// defaultEnvMapper 默认的环境变量键映射
+func defaultEnvMapper(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", "."))
+}
+
+// envValue 将环境变量值转换为 JSON 数字、布尔或字符串
+func envValue(s string) interface{} {
+	if s == "true" || s == "false" {
+		return s == "true"
+	}
+	if s != "" && (s[0] == '-' || (s[0] >= '0' && s[0] <= '9')) && json.Valid([]byte(s)) {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return s
+}
+
+// decodeSource 检查来源的根节点为对象
+func decodeSource(doc *JSON) (map[string]interface{}, error) {
+	if doc.err != nil {
+		return nil, doc.err
+	}
+	obj, ok := doc.data.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("root must be an object")
+	}
+	return obj, nil
+}
+
+// LoadLayered 按顺序加载并深度合并多个来源，后面的来源覆盖前面的来源
+//
+// 对象逐键递归合并；数组与标量整体替换，不做元素级合并。任一来源失败时返回带来源名称的错误。
+func LoadLayered(sources ...Source) (*JSON, error) {
+	doc, _, err := LoadLayeredWithProvenance(sources...)
+	return doc, err
+}
+
+// LoadLayeredWithProvenance 与 LoadLayered 相同，并返回每个叶子路径的值来自哪个来源，
+// 用于排查"这个配置值是从哪里来的"
+func LoadLayeredWithProvenance(sources ...Source) (*JSON, Provenance, error) {
+	var merged interface{} = map[string]interface{}{}
+	provenance := make(Provenance)
+
+	for i, source := range sources {
+		obj, err := source.load()
+		if err != nil {
+			return nil, nil, fmt.Errorf("config source %d (%s): %w", i, source.name, err)
+		}
+		if obj == nil {
+			continue
+		}
+		if merged, err = deepMerge(merged, obj); err != nil {
+			return nil, nil, fmt.Errorf("config source %d (%s): %w", i, source.name, err)
+		}
+		provenance.record("", obj, i)
+	}
+	return New(merged), provenance, nil
+}
+
+// record 记录来源 index 写入的所有叶子路径
+func (p Provenance) record(path string, value interface{}, index int) {
+	if obj, ok := value.(map[string]interface{}); ok {
+		if len(obj) > 0 {
+			for key, child := range obj {
+				p.record(joinJSONPath(path, key), child, index)
+			}
+			return
+		}
+		if path == "" || p.hasDescendant(path) {
+			return
+		}
+	}
+	p.setLeaf(path, index)
+}
+
+// setLeaf 记录叶子路径，并清除被它覆盖的祖先与后代记录
+func (p Provenance) setLeaf(path string, index int) {
+	for i := 0; i < len(path); i++ {
+		if path[i] == '.' {
+			delete(p, path[:i])
+		}
+	}
+	for existing := range p {
+		if strings.HasPrefix(existing, path+".") {
+			delete(p, existing)
+		}
+	}
+	p[path] = index
+}
+
+// hasDescendant 判断 path 下是否已有叶子记录
+func (p Provenance) hasDescendant(path string) bool {
+	for existing := range p {
+		if strings.HasPrefix(existing, path+".") {
+			return true
+		}
+	}
+	return false
+}
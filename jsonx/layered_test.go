@@ -0,0 +1,158 @@
+package jsonx
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadLayered(t *testing.T) {
+	dir := t.TempDir()
+	defaults := writeConfig(t, dir, "defaults.json", `{
+		// 默认配置
+		"db": {"host": "localhost", "port": 5432, "pool": {"max": 10, "idle": 2}},
+		"servers": ["a", "b"],
+		"log": {"level": "info", "format": "text"},
+		"debug": false,
+	}`)
+	t.Setenv("APP_DB_HOST", "db.internal")
+	t.Setenv("APP_DB_POOL_MAX", "50")
+	t.Setenv("APP_DEBUG", "true")
+	t.Setenv("OTHER_DB_HOST", "ignored")
+
+	doc, provenance, err := LoadLayeredWithProvenance(
+		FileSource(defaults),
+		BytesSource("production", []byte(`{"db":{"host":"db.prod","pool":{"idle":5}},"servers":["c"],"log":"json"}`)),
+		EnvSource("APP"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"db":{"host":"db.internal","pool":{"idle":5,"max":50},"port":5432},"debug":true,"log":"json","servers":["c"]}`
+	if got := doc.MustJSON(); got != want {
+		t.Fatalf("merged = %s\nwant     %s", got, want)
+	}
+
+	wantProvenance := Provenance{
+		"db.host":      2,
+		"db.port":      0,
+		"db.pool.max":  2,
+		"db.pool.idle": 1,
+		"servers":      1,
+		"log":          1, // 标量覆盖对象后，log.level 与 log.format 的记录被清除
+		"debug":        2,
+	}
+	if !reflect.DeepEqual(provenance, wantProvenance) {
+		t.Errorf("provenance = %v\nwant         %v", provenance, wantProvenance)
+	}
+
+	plain, err := LoadLayered(FileSource(defaults), EnvSource("APP_"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := plain.Get("db.pool.max").Int(); got != 50 {
+		t.Errorf("db.pool.max = %d, want 50", got)
+	}
+}
+
+func TestLoadLayeredObjectReplacesScalar(t *testing.T) {
+	doc, provenance, err := LoadLayeredWithProvenance(
+		BytesSource("a", []byte(`{"log":"json","empty":{"x":1}}`)),
+		BytesSource("b", []byte(`{"log":{"level":"debug"},"empty":{},"fresh":{}}`)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := doc.MustJSON(); got != `{"empty":{"x":1},"fresh":{},"log":{"level":"debug"}}` {
+		t.Fatalf("merged = %s", got)
+	}
+	want := Provenance{"log.level": 1, "empty.x": 0, "fresh": 1}
+	if !reflect.DeepEqual(provenance, want) {
+		t.Errorf("provenance = %v, want %v", provenance, want)
+	}
+}
+
+func TestLoadLayeredSources(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		_, err := LoadLayered(FileSource(filepath.Join(t.TempDir(), "missing.json")))
+		if !errors.Is(err, fs.ErrNotExist) || !strings.Contains(err.Error(), "config source 0 (file:") {
+			t.Fatalf("error = %v", err)
+		}
+	})
+
+	t.Run("optional file", func(t *testing.T) {
+		doc, err := LoadLayered(
+			BytesSource("base", []byte(`{"a":1}`)),
+			OptionalFileSource(filepath.Join(t.TempDir(), "missing.json")),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := doc.MustJSON(); got != `{"a":1}` {
+			t.Errorf("got %s", got)
+		}
+	})
+
+	t.Run("non-object root", func(t *testing.T) {
+		_, err := LoadLayered(BytesSource("list", []byte(`[1,2]`)))
+		if err == nil || !strings.Contains(err.Error(), "bytes:list") {
+			t.Fatalf("error = %v", err)
+		}
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, err := LoadLayered(BytesSource("bad", []byte(`{"a":`)))
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Fatalf("error = %v, want *SyntaxError", err)
+		}
+	})
+
+	t.Run("env values and arrays", func(t *testing.T) {
+		t.Setenv("SVC_SERVERS_0", "alpha")
+		t.Setenv("SVC_SERVERS_1", "beta")
+		t.Setenv("SVC_PORT", "8080")
+		t.Setenv("SVC_VERSION", "007")
+		t.Setenv("SVC_NAME", "-")
+		doc, err := LoadLayered(EnvSource("SVC"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"name":"-","port":8080,"servers":["alpha","beta"],"version":"007"}`
+		if got := doc.MustJSON(); got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	})
+
+	t.Run("custom env mapper", func(t *testing.T) {
+		t.Setenv("CFG_MAX__CONNS", "5")
+		t.Setenv("CFG_SKIP", "x")
+		mapper := func(key string) string {
+			if key == "SKIP" {
+				return ""
+			}
+			return strings.ToLower(strings.ReplaceAll(key, "__", "_"))
+		}
+		doc, err := LoadLayered(EnvSource("CFG_", mapper))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := doc.MustJSON(); got != `{"max_conns":5}` {
+			t.Errorf("got %s", got)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package jsonx
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrLimitExceeded 文档超出 ParseLimits 的限制，可通过 errors.Is 判断
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// ParseLimits 解析不可信输入时的安全限制，字段为 0 表示不限制
+type ParseLimits struct {
+	MaxBytes int // 文档最大字节数
+	MaxDepth int // 对象与数组的最大嵌套层数，顶层容器为第 1 层
+	MaxKeys  int // 整个文档中对象键的最大总数
+}
+
+// LimitError 文档超出解析限制
+type LimitError struct {
+	Limit  string // 超出的限制项：MaxBytes / MaxDepth / MaxKeys
+	Max    int    // 限制值
+	Offset int64  // 超出限制时的字节偏移
+}
+
+// Error 实现 error 接口
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("jsonx: document exceeds %s of %d at offset %d", e.Limit, e.Max, e.Offset)
+}
+
+// Unwrap 返回 ErrLimitExceeded
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// ParseWithLimits 按限制解析 JSON 字符串，超出限制时返回 *LimitError
+//
+// 解析前先对输入做一次线性扫描检查大小、嵌套层数和键数量，超限的文档不会进入
+// encoding/json，从而避免恶意构造的深层嵌套或超大文档造成内存峰值。
+func ParseWithLimits(input string, limits ParseLimits) *JSON {
+	if limits.MaxBytes > 0 && len(input) > limits.MaxBytes {
+		return &JSON{err: &LimitError{Limit: "MaxBytes", Max: limits.MaxBytes, Offset: int64(limits.MaxBytes)}}
+	}
+	data := []byte(input)
+	if err := checkLimits(data, limits); err != nil {
+		return &JSON{err: err}
+	}
+	return ParseBytes(data)
+}
+
+// checkLimits 扫描文档结构，跳过字符串内容，不校验语法
+func checkLimits(data []byte, limits ParseLimits) error {
+	if limits.MaxBytes > 0 && len(data) > limits.MaxBytes {
+		return &LimitError{Limit: "MaxBytes", Max: limits.MaxBytes, Offset: int64(limits.MaxBytes)}
+	}
+	if limits.MaxDepth <= 0 && limits.MaxKeys <= 0 {
+		return nil
+	}
+
+	depth, keys := 0, 0
+	inString, escaped := false, false
+	for i, c := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if limits.MaxDepth > 0 && depth > limits.MaxDepth {
+				return &LimitError{Limit: "MaxDepth", Max: limits.MaxDepth, Offset: int64(i)}
+			}
+		case '}', ']':
+			depth--
+		case ':':
+			// 字符串之外的冒号只会出现在键值之间
+			keys++
+			if limits.MaxKeys > 0 && keys > limits.MaxKeys {
+				return &LimitError{Limit: "MaxKeys", Max: limits.MaxKeys, Offset: int64(i)}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package jsonx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// nested 构造 depth 层嵌套的数组
+func nested(depth int) string {
+	return strings.Repeat("[", depth) + strings.Repeat("]", depth)
+}
+
+// nestedData 以编程方式构造 depth 层嵌套的数据，绕过 encoding/json 的解析上限
+func nestedData(depth int) interface{} {
+	var data interface{} = "leaf"
+	for i := 0; i < depth; i++ {
+		data = map[string]interface{}{"n": data}
+	}
+	return data
+}
+
+func TestParseWithLimits(t *testing.T) {
+	limits := ParseLimits{MaxBytes: 1 << 20, MaxDepth: 64, MaxKeys: 3}
+
+	if j := ParseWithLimits(nested(64), limits); j.Error() != nil || Depth(j) != 63 {
+		t.Errorf("depth at limit: err = %v, depth = %d", j.Error(), Depth(j))
+	}
+
+	var limitErr *LimitError
+	err := ParseWithLimits(nested(65), limits).Error()
+	if !errors.As(err, &limitErr) || limitErr.Limit != "MaxDepth" || limitErr.Offset != 64 {
+		t.Fatalf("depth beyond limit: got %v", err)
+	}
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("LimitError should match ErrLimitExceeded")
+	}
+
+	// 字符串中的括号与冒号不计入
+	doc := `{"a": "[[[[{{{{:::", "b": {"c": 1}}`
+	if j := ParseWithLimits(doc, ParseLimits{MaxDepth: 2, MaxKeys: 3}); j.Error() != nil || j.Get("a").String() != "[[[[{{{{:::" {
+		t.Errorf("brackets inside strings should be ignored: %v", j.Error())
+	}
+	if j := ParseWithLimits(`{"a\"[": 1}`, ParseLimits{MaxDepth: 1}); j.Error() != nil {
+		t.Errorf("escaped quote handling: %v", j.Error())
+	}
+
+	err = ParseWithLimits(`{"a":1,"b":2,"c":3,"d":4}`, limits).Error()
+	if !errors.As(err, &limitErr) || limitErr.Limit != "MaxKeys" {
+		t.Errorf("too many keys: got %v", err)
+	}
+
+	err = ParseWithLimits(`"`+strings.Repeat("x", 100)+`"`, ParseLimits{MaxBytes: 50}).Error()
+	if !errors.As(err, &limitErr) || limitErr.Limit != "MaxBytes" || err.Error() != "jsonx: document exceeds MaxBytes of 50 at offset 50" {
+		t.Errorf("oversized document: got %v", err)
+	}
+
+	// 未超限的语法错误仍为 *SyntaxError
+	var syntaxErr *SyntaxError
+	if err := ParseWithLimits(`{"a": }`, limits).Error(); !errors.As(err, &syntaxErr) {
+		t.Errorf("expected *SyntaxError, got %v", err)
+	}
+
+	// 零值表示不限制
+	if err := ParseWithLimits(nested(500), ParseLimits{}).Error(); err != nil {
+		t.Errorf("zero limits should not restrict: %v", err)
+	}
+}
+
+func TestPathologicalNesting(t *testing.T) {
+	// 3 MB 的 [[[[...：限制在扫描阶段即拒绝，未设限制时 encoding/json 也会报错而不是崩溃
+	payload := strings.Repeat("[", 3<<20)
+	if err := ParseWithLimits(payload, ParseLimits{MaxDepth: 128}).Error(); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("ParseWithLimits: got %v", err)
+	}
+	if err := Parse(payload).Error(); err == nil {
+		t.Error("Parse should reject pathological nesting")
+	}
+
+	// 嵌套恰好在上限内的数据可以正常处理
+	j := New(nestedData(maxNestingDepth - 1))
+	if Depth(j) != maxNestingDepth-1 || j.Clone().Error() != nil {
+		t.Errorf("data within the nesting limit should be handled, err = %v", j.Error())
+	}
+
+	// 编程构造的超深数据：递归函数返回 ErrTooDeep 而不是栈溢出
+	deep := nestedData(maxNestingDepth + 10)
+
+	if err := New(deep).Clone().Error(); !errors.Is(err, ErrTooDeep) {
+		t.Errorf("Clone: expected ErrTooDeep, got %v", err)
+	}
+	j = New(deep)
+	if depth := Depth(j); depth != -1 || !errors.Is(j.Error(), ErrTooDeep) {
+		t.Errorf("Depth: expected -1 and ErrTooDeep, got %d, %v", depth, j.Error())
+	}
+	j = New(deep)
+	if flat := Flatten(j); len(flat) != 0 || !errors.Is(j.Error(), ErrTooDeep) {
+		t.Errorf("Flatten: expected ErrTooDeep, got %v", j.Error())
+	}
+	if merged := Object().DeepMerge(New(deep)); !errors.Is(merged.Error(), ErrTooDeep) {
+		t.Errorf("DeepMerge: expected ErrTooDeep, got %v", merged.Error())
+	}
+	if _, err := New(deep).Encode(EncodeOptions{NumberFormat: &NumberFormat{}}); !errors.Is(err, ErrTooDeep) {
+		t.Errorf("Encode: expected ErrTooDeep, got %v", err)
+	}
+	if Equal(New(deep), New(nestedData(maxNestingDepth+10))) {
+		t.Error("Equal should give up on data beyond the nesting limit")
+	}
+	if changes := TypeDiff(New(deep), New(deep)); len(changes) != 0 {
+		t.Errorf("TypeDiff: unexpected changes %v", changes)
+	}
+}
+
+func FuzzParseWithLimits(f *testing.F) {
+	for _, seed := range []string{
+		nested(8), nested(9), `{"a":{"b":[1,{"c":2}]}}`, `{"k":"[{\"}"}`, `[`, `"\\"`, `{"a":1,"b":2,"c":3,"d":4,"e":5}`,
+	} {
+		f.Add(seed)
+	}
+
+	limits := ParseLimits{MaxDepth: 8, MaxKeys: 4, MaxBytes: 4096}
+	f.Fuzz(func(t *testing.T, input string) {
+		j := ParseWithLimits(input, limits)
+		if j.Error() != nil {
+			return
+		}
+		// 成功解析的文档必然在限制之内
+		if depth := Depth(j); depth >= limits.MaxDepth {
+			t.Fatalf("depth %d exceeds limit for %q", depth, input)
+		}
+		if len(input) > limits.MaxBytes || countKeys(j.data) > limits.MaxKeys {
+			t.Fatalf("limits not enforced for %q", input)
+		}
+	})
+}
+
+// countKeys 统计文档中对象键的总数
+func countKeys(data interface{}) int {
+	n := 0
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for _, val := range v {
+			n += 1 + countKeys(val)
+		}
+	case []interface{}:
+		for _, val := range v {
+			n += countKeys(val)
+		}
+	}
+	return n
+}
@@ -0,0 +1,112 @@
+package jsonx
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AmbiguousKeyError 宽松匹配时多个键折叠为同一名称
+type AmbiguousKeyError struct {
+	Path       string   // 完整查询路径
+	Segment    string   // 出现歧义的路径段
+	Candidates []string // 匹配的键，按字典序排列
+}
+
+// Error 实现 error 接口
+func (e *AmbiguousKeyError) Error() string {
+	return fmt.Sprintf("ambiguous key '%s' in path %s: matches %s", e.Segment, e.Path, strings.Join(e.Candidates, ", "))
+}
+
+// GetAny 按顺序尝试多个路径，返回第一个存在的值
+// 适用于不同来源对同一字段使用不同命名的情况，如 GetAny("userName", "username", "user_name")
+func (j *JSON) GetAny(paths ...string) *JSON {
+	if j.err != nil {
+		return j
+	}
+
+	for _, path := range paths {
+		if value, err := j.getByPath(path); err == nil {
+			return &JSON{data: value, path: joinJSONPath(j.path, path)}
+		}
+	}
+	return &JSON{err: fmt.Errorf("%w: %s", ErrPathNotFound, strings.Join(paths, ", "))}
+}
+
+// GetFold 宽松地获取指定路径的值，对象的每一段按以下顺序解析：
+//  1. 精确匹配
+//  2. 忽略大小写匹配（如 userName 与 USERNAME）
+//  3. 忽略大小写且忽略 "_" 与 "-" 匹配（如 userName 与 user_name）
+//
+// 某一级存在多个同等优先级的候选键时返回 *AmbiguousKeyError，而不是任选其一
+func (j *JSON) GetFold(path string) *JSON {
+	if j.err != nil {
+		return j
+	}
+	if path == "" {
+		return &JSON{data: j.data}
+	}
+
+	current := j.data
+	for _, part := range strings.Split(path, ".") {
+		switch v := current.(type) {
+		case []interface{}:
+			idx, err := strconv.Atoi(part)
+			if err != nil {
+				return &JSON{err: fmt.Errorf("cannot access property '%s' on non-object", part)}
+			}
+			resolved, ok := resolveIndex(idx, len(v))
+			if !ok {
+				return &JSON{err: fmt.Errorf("array index out of range: %d", idx)}
+			}
+			current = v[resolved]
+		case map[string]interface{}:
+			key, err := foldKey(v, part, path)
+			if err != nil {
+				return &JSON{err: err}
+			}
+			current = v[key]
+		default:
+			return &JSON{err: fmt.Errorf("cannot access property '%s' on non-object", part)}
+		}
+	}
+
+	return &JSON{data: current, path: joinJSONPath(j.path, path)}
+}
+
+// foldKey 在对象中按 精确 > 忽略大小写 > 忽略分隔符 的顺序解析键
+func foldKey(obj map[string]interface{}, part, path string) (string, error) {
+	if _, exists := obj[part]; exists {
+		return part, nil
+	}
+
+	matchers := []func(key string) bool{
+		func(key string) bool { return strings.EqualFold(key, part) },
+		func(key string) bool { return foldSeparators(key) == foldSeparators(part) },
+	}
+	for _, match := range matchers {
+		var candidates []string
+		for key := range obj {
+			if match(key) {
+				candidates = append(candidates, key)
+			}
+		}
+		switch len(candidates) {
+		case 0:
+			continue
+		case 1:
+			return candidates[0], nil
+		default:
+			sort.Strings(candidates)
+			return "", &AmbiguousKeyError{Path: path, Segment: part, Candidates: candidates}
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrPathNotFound, path)
+}
+
+// foldSeparators 转为小写并去除 "_" 与 "-"
+func foldSeparators(key string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+}
@@ -0,0 +1,82 @@
+package jsonx
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGetAny(t *testing.T) {
+	j := Parse(`{"user_name": "alice", "profile": {"age": 30}}`)
+
+	if got := j.GetAny("userName", "username", "user_name").String(); got != "alice" {
+		t.Errorf("GetAny = %q, want alice", got)
+	}
+	if got := j.GetAny("profile.years", "profile.age").Int(); got != 30 {
+		t.Errorf("GetAny nested = %d, want 30", got)
+	}
+	if err := j.GetAny("a", "b").Error(); err == nil {
+		t.Error("expected error when no path exists")
+	}
+}
+
+func TestGetFold(t *testing.T) {
+	j := Parse(`{
+		"userName": "exact",
+		"USERNAME": "upper",
+		"Profile": {"First-Name": "bob", "tags": [{"Label": "x"}]},
+		"user_id": 7
+	}`)
+
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{"userName", "exact"},          // 精确匹配优先
+		{"USERNAME", "upper"},          // 精确匹配优先
+		{"profile.first_name", "bob"},  // 大小写与分隔符均不同
+		{"PROFILE.tags.0.label", "x"},  // 经过数组
+		{"profile.tags.-1.LABEL", "x"}, // 负索引
+		{"userId", float64(7)},         // 分隔符折叠
+	}
+	for _, tt := range tests {
+		got := j.GetFold(tt.path)
+		if got.Error() != nil {
+			t.Errorf("GetFold(%q) error: %v", tt.path, got.Error())
+			continue
+		}
+		if !reflect.DeepEqual(got.data, tt.want) {
+			t.Errorf("GetFold(%q) = %v, want %v", tt.path, got.data, tt.want)
+		}
+	}
+
+	if err := j.GetFold("profile.missing").Error(); err == nil {
+		t.Error("expected error for missing key")
+	}
+}
+
+func TestGetFoldAmbiguous(t *testing.T) {
+	// username 没有精确匹配，两个键忽略大小写后相同
+	j := Parse(`{"userName": 1, "USERNAME": 2, "user_id": 3, "user-id": 4}`)
+
+	var ambiguous *AmbiguousKeyError
+	if err := j.GetFold("username").Error(); !errors.As(err, &ambiguous) {
+		t.Fatalf("expected AmbiguousKeyError, got %v", err)
+	}
+	if !reflect.DeepEqual(ambiguous.Candidates, []string{"USERNAME", "userName"}) {
+		t.Errorf("candidates = %v", ambiguous.Candidates)
+	}
+
+	// 分隔符折叠同样报告歧义
+	if err := j.GetFold("userid").Error(); !errors.As(err, &ambiguous) {
+		t.Fatalf("expected AmbiguousKeyError, got %v", err)
+	}
+	if ambiguous.Segment != "userid" {
+		t.Errorf("segment = %q", ambiguous.Segment)
+	}
+
+	// 精确匹配不受歧义影响
+	if got := j.GetFold("user_id").Int(); got != 3 {
+		t.Errorf("exact match = %d, want 3", got)
+	}
+}
@@ -0,0 +1,272 @@
+package jsonx
+
+import (
+	"strconv"
+	"strings"
+)
+
+// 可配置策略的深度合并
+
+// ArrayStrategy 两侧都是数组时的合并策略，零值为 MergeReplace
+type ArrayStrategy struct {
+	mode arrayMergeMode
+	key  string
+}
+
+// arrayMergeMode 数组合并方式
+type arrayMergeMode int
+
+const (
+	arrayReplace arrayMergeMode = iota
+	arrayAppend
+	arrayUniqueAppend
+	arrayMergeByKey
+)
+
+var (
+	// MergeReplace 右侧数组整体替换左侧数组（DeepMerge 的行为）
+	MergeReplace = ArrayStrategy{mode: arrayReplace}
+	// MergeAppend 右侧数组的元素追加到左侧数组之后
+	MergeAppend = ArrayStrategy{mode: arrayAppend}
+	// MergeUniqueAppend 只追加左侧数组中不存在（结构化比较）的元素
+	MergeUniqueAppend = ArrayStrategy{mode: arrayUniqueAppend}
+)
+
+// MergeByKey 按对象元素的 key 字段配对：key 相同的元素递归合并，其余元素追加到末尾
+// 右侧缺少 key 字段或不是对象的元素直接追加
+func MergeByKey(key string) ArrayStrategy {
+	return ArrayStrategy{mode: arrayMergeByKey, key: key}
+}
+
+// ConflictStrategy 两侧值无法递归合并（标量、类型不同）时保留哪一侧
+type ConflictStrategy int
+
+const (
+	PreferRight ConflictStrategy = iota // 使用右侧的值（DeepMerge 的行为）
+	PreferLeft                          // 保留左侧已有的值，右侧只补充左侧缺失的键
+)
+
+// MergeOptions DeepMergeWith 的选项
+type MergeOptions struct {
+	// Arrays 全局数组合并策略
+	Arrays ArrayStrategy
+	// ArrayPaths 按路径指定数组合并策略，优先于 Arrays；片段 "*" 匹配任意键或索引，如 "services.*.ports"
+	ArrayPaths map[string]ArrayStrategy
+	// Conflict 标量或类型冲突时的取舍
+	Conflict ConflictStrategy
+	// NullMeans 右侧对象字段为 null 时的含义，默认 NullAsValue
+	NullMeans NullMode
+}
+
+// NullMode 合并时右侧 null 的含义，使同一个补丁文档可以同时表达"置空"、"删除"与"不修改"
+type NullMode int
+
+const (
+	NullAsValue NullMode = iota // null 作为普通值写入结果（DeepMerge 的行为）
+	NullDeletes                 // null 删除左侧的同名字段（JSON Merge Patch 语义）
+	NullIgnored                 // null 表示不修改，保留左侧的值
+)
+
+// DeepMergeWith 按选项深度合并另一个 JSON，返回新文档，两侧原文档均不会被修改
+// 对象始终逐键递归合并；数组按 Arrays / ArrayPaths 合并；其余冲突按 Conflict 取舍
+func (j *JSON) DeepMergeWith(other *JSON, opts MergeOptions) *JSON {
+	if j.err != nil {
+		return j
+	}
+	if other.err != nil {
+		return &JSON{data: j.data, err: other.err}
+	}
+
+	m := &merger{opts: opts}
+	for pattern, strategy := range opts.ArrayPaths {
+		m.paths = append(m.paths, arrayPathStrategy{pattern: strings.Split(pattern, "."), strategy: strategy})
+	}
+
+	result, err := m.merge(j.data, other.data, nil)
+	if err != nil {
+		return &JSON{data: j.data, err: err}
+	}
+	return &JSON{data: result}
+}
+
+// arrayPathStrategy 预先拆分的路径策略
+type arrayPathStrategy struct {
+	pattern  []string
+	strategy ArrayStrategy
+}
+
+// merger 按选项合并的合并器，右侧的值通过 cloner 克隆，左侧未改动的值与原文档共享
+type merger struct {
+	opts  MergeOptions
+	paths []arrayPathStrategy
+	c     cloner
+}
+
+// merge 递归合并，path 为当前位置的路径片段
+func (m *merger) merge(dst, src interface{}, path []string) (interface{}, error) {
+	if len(path) > maxNestingDepth {
+		return nil, ErrTooDeep
+	}
+
+	if _, explicit := src.(explicitNull); explicit {
+		// 显式 null 不受 NullMeans 影响，按普通的 null 值合并
+		src = nil
+	} else if src == nil && m.opts.NullMeans != NullAsValue {
+		return dst, nil
+	}
+
+	switch srcVal := src.(type) {
+	case map[string]interface{}:
+		if dstMap, ok := dst.(map[string]interface{}); ok {
+			return m.mergeObjects(dstMap, srcVal, path)
+		}
+	case []interface{}:
+		if dstArr, ok := dst.([]interface{}); ok {
+			return m.mergeArrays(dstArr, srcVal, path)
+		}
+	}
+
+	if m.opts.Conflict == PreferLeft {
+		return dst, nil
+	}
+	return m.c.clone(src)
+}
+
+// mergeObjects 逐键合并两个对象
+func (m *merger) mergeObjects(dst, src map[string]interface{}, path []string) (interface{}, error) {
+	ptr, err := m.c.guard.enter(src)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]interface{}, len(dst)+len(src))
+	for k, v := range dst {
+		result[k] = v
+	}
+	for k, v := range src {
+		if v == nil && m.opts.NullMeans != NullAsValue {
+			if m.opts.NullMeans == NullDeletes {
+				delete(result, k)
+			}
+			continue
+		}
+
+		var merged interface{}
+		dstVal, exists := result[k]
+		_, isObject := v.(map[string]interface{})
+		switch {
+		case exists:
+			merged, err = m.merge(dstVal, v, append(path, k))
+		case isObject:
+			// 新增的对象同样逐键合并，其中的 null 按 NullMeans 处理
+			merged, err = m.merge(map[string]interface{}{}, v, append(path, k))
+		case isNullValue(v):
+			merged = nil
+		default:
+			merged, err = m.c.clone(v)
+		}
+		if err != nil {
+			return nil, err
+		}
+		result[k] = merged
+	}
+
+	m.c.guard.leave(ptr)
+	return result, nil
+}
+
+// mergeArrays 按路径对应的策略合并两个数组
+func (m *merger) mergeArrays(dst, src []interface{}, path []string) (interface{}, error) {
+	strategy := m.strategyAt(path)
+	if strategy.mode == arrayReplace {
+		return m.c.clone(src)
+	}
+
+	ptr, err := m.c.guard.enter(src)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]interface{}, len(dst), len(dst)+len(src))
+	copy(result, dst)
+	eq := &equalComparer{}
+
+	for _, item := range src {
+		switch strategy.mode {
+		case arrayUniqueAppend:
+			if containsValue(eq, result, item) {
+				continue
+			}
+		case arrayMergeByKey:
+			if idx := indexByKey(eq, result, strategy.key, item); idx >= 0 {
+				merged, err := m.merge(result[idx], item, append(path, strconv.Itoa(idx)))
+				if err != nil {
+					return nil, err
+				}
+				result[idx] = merged
+				continue
+			}
+		}
+
+		cloned, err := m.c.clone(item)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, cloned)
+	}
+
+	m.c.guard.leave(ptr)
+	return result, nil
+}
+
+// strategyAt 返回路径对应的数组策略，多个模式匹配时取第一个在字典序上最小的模式，保证结果确定
+func (m *merger) strategyAt(path []string) ArrayStrategy {
+	var (
+		best    ArrayStrategy
+		bestKey string
+		found   bool
+	)
+	for _, p := range m.paths {
+		if !matchPathPattern(p.pattern, path) {
+			continue
+		}
+		key := strings.Join(p.pattern, ".")
+		if !found || key < bestKey {
+			best, bestKey, found = p.strategy, key, true
+		}
+	}
+	if found {
+		return best
+	}
+	return m.opts.Arrays
+}
+
+// containsValue 判断数组中是否存在与 v 结构化相等的元素
+func containsValue(eq *equalComparer, arr []interface{}, v interface{}) bool {
+	for _, item := range arr {
+		if eq.equal(item, v, nil) {
+			return true
+		}
+	}
+	return false
+}
+
+// indexByKey 查找 key 字段与 v 相同的第一个对象元素，v 不是对象或缺少 key 时返回 -1
+func indexByKey(eq *equalComparer, arr []interface{}, key string, v interface{}) int {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return -1
+	}
+	want, exists := obj[key]
+	if !exists {
+		return -1
+	}
+	for i, item := range arr {
+		if itemObj, ok := item.(map[string]interface{}); ok {
+			if got, exists := itemObj[key]; exists && eq.equal(got, want, nil) {
+				return i
+			}
+		}
+	}
+	return -1
+}
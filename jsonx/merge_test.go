@@ -0,0 +1,191 @@
+package jsonx
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDeepMergeWithArrayStrategies(t *testing.T) {
+	left := `{"tags":["a","b"],"nums":[1,2],"users":[{"id":1,"name":"tom","roles":["admin"]},{"id":2,"name":"ann"}]}`
+	right := `{"tags":["b","c","c"],"nums":[2.0,3],"users":[{"id":2,"age":30},{"id":3,"name":"bob"},{"name":"anon"}]}`
+
+	tests := []struct {
+		name string
+		opts MergeOptions
+		want string
+	}{
+		{
+			name: "replace by default",
+			want: `{"nums":[2,3],"tags":["b","c","c"],"users":[{"age":30,"id":2},{"id":3,"name":"bob"},{"name":"anon"}]}`,
+		},
+		{
+			name: "append",
+			opts: MergeOptions{Arrays: MergeAppend},
+			want: `{"nums":[1,2,2,3],"tags":["a","b","b","c","c"],"users":[{"id":1,"name":"tom","roles":["admin"]},{"id":2,"name":"ann"},{"age":30,"id":2},{"id":3,"name":"bob"},{"name":"anon"}]}`,
+		},
+		{
+			name: "unique append compares structurally",
+			opts: MergeOptions{Arrays: MergeUniqueAppend},
+			want: `{"nums":[1,2,3],"tags":["a","b","c"],"users":[{"id":1,"name":"tom","roles":["admin"]},{"id":2,"name":"ann"},{"age":30,"id":2},{"id":3,"name":"bob"},{"name":"anon"}]}`,
+		},
+		{
+			name: "merge by key per path",
+			opts: MergeOptions{Arrays: MergeUniqueAppend, ArrayPaths: map[string]ArrayStrategy{"users": MergeByKey("id")}},
+			want: `{"nums":[1,2,3],"tags":["a","b","c"],"users":[{"id":1,"name":"tom","roles":["admin"]},{"age":30,"id":2,"name":"ann"},{"id":3,"name":"bob"},{"name":"anon"}]}`,
+		},
+		{
+			name: "per path overrides global",
+			opts: MergeOptions{Arrays: MergeAppend, ArrayPaths: map[string]ArrayStrategy{"tags": MergeReplace}},
+			want: `{"nums":[1,2,2,3],"tags":["b","c","c"],"users":[{"id":1,"name":"tom","roles":["admin"]},{"id":2,"name":"ann"},{"age":30,"id":2},{"id":3,"name":"bob"},{"name":"anon"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := Parse(left), Parse(right)
+			result := a.DeepMergeWith(b, tt.opts)
+			if result.Error() != nil {
+				t.Fatal(result.Error())
+			}
+			if got := result.MustJSON(); got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+			if got := a.MustJSON(); got != Parse(left).MustJSON() {
+				t.Errorf("left document modified: %s", got)
+			}
+			if got := b.MustJSON(); got != Parse(right).MustJSON() {
+				t.Errorf("right document modified: %s", got)
+			}
+		})
+	}
+}
+
+func TestDeepMergeWithWildcardPath(t *testing.T) {
+	left := Parse(`{"services":{"web":{"ports":[80]},"db":{"ports":[5432]}},"hosts":["a"]}`)
+	right := Parse(`{"services":{"web":{"ports":[443]},"db":{"ports":[5433]}},"hosts":["b"]}`)
+
+	result := left.DeepMergeWith(right, MergeOptions{ArrayPaths: map[string]ArrayStrategy{"services.*.ports": MergeAppend}})
+	want := `{"hosts":["b"],"services":{"db":{"ports":[5432,5433]},"web":{"ports":[80,443]}}}`
+	if got := result.MustJSON(); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestDeepMergeWithNestedByKey(t *testing.T) {
+	// 按 key 配对后的元素继续按路径策略合并其内部数组，路径使用左侧元素的下标
+	left := Parse(`{"users":[{"id":"u1","roles":["read"]}]}`)
+	right := Parse(`{"users":[{"id":"u1","roles":["write","read"]}]}`)
+
+	result := left.DeepMergeWith(right, MergeOptions{ArrayPaths: map[string]ArrayStrategy{
+		"users":         MergeByKey("id"),
+		"users.*.roles": MergeUniqueAppend,
+	}})
+	if got := result.MustJSON(); got != `{"users":[{"id":"u1","roles":["read","write"]}]}` {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestDeepMergeWithConflict(t *testing.T) {
+	left := `{"name":"left","port":80,"db":{"host":"a"},"mode":"x","list":[1]}`
+	right := `{"name":"right","extra":true,"db":{"host":"b","user":"root"},"mode":{"kind":"y"},"list":[2]}`
+
+	preferRight := Parse(left).DeepMergeWith(Parse(right), MergeOptions{})
+	if got, want := preferRight.MustJSON(), Parse(left).DeepMerge(Parse(right)).MustJSON(); got != want {
+		t.Errorf("zero options differ from DeepMerge:\n got  %s\n want %s", got, want)
+	}
+
+	preferLeft := Parse(left).DeepMergeWith(Parse(right), MergeOptions{Conflict: PreferLeft})
+	want := `{"db":{"host":"a","user":"root"},"extra":true,"list":[2],"mode":"x","name":"left","port":80}`
+	if got := preferLeft.MustJSON(); got != want {
+		t.Errorf("PreferLeft got  %s\nwant %s", got, want)
+	}
+}
+
+func TestDeepMergeWithErrors(t *testing.T) {
+	bad := FromError(errors.New("boom"))
+	if err := Object().DeepMergeWith(bad, MergeOptions{}).Error(); err == nil || err.Error() != "boom" {
+		t.Errorf("error = %v, want boom", err)
+	}
+
+	cyclic := map[string]interface{}{}
+	cyclic["self"] = []interface{}{cyclic}
+	left := New(map[string]interface{}{"self": []interface{}{}})
+	err := left.DeepMergeWith(New(cyclic), MergeOptions{Arrays: MergeAppend}).Error()
+	if !errors.Is(err, ErrCyclicData) {
+		t.Errorf("error = %v, want ErrCyclicData", err)
+	}
+}
+
+func TestDeepMergeWithNullMeans(t *testing.T) {
+	base := `{"name":"tom","email":"tom@example.com","phone":"123","nickname":"t","profile":{"city":"bj","zip":"100000"}}`
+
+	// 补丁同时表达三种意图：phone 置为 null，nickname 与 profile.zip 为普通 null，
+	// email 不出现（保持不变），name 与 profile.city 正常更新
+	patch := Parse(`{"name":"jerry","nickname":null,"profile":{"city":"sh","zip":null},"extra":{"a":null,"b":1}}`).
+		SetNull("phone")
+	if err := patch.Error(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		mode NullMode
+		want string
+	}{
+		{
+			name: "null as value",
+			mode: NullAsValue,
+			want: `{"email":"tom@example.com","extra":{"a":null,"b":1},"name":"jerry","nickname":null,"phone":null,"profile":{"city":"sh","zip":null}}`,
+		},
+		{
+			name: "null deletes",
+			mode: NullDeletes,
+			want: `{"email":"tom@example.com","extra":{"b":1},"name":"jerry","phone":null,"profile":{"city":"sh"}}`,
+		},
+		{
+			name: "null ignored",
+			mode: NullIgnored,
+			want: `{"email":"tom@example.com","extra":{"b":1},"name":"jerry","nickname":"t","phone":null,"profile":{"city":"sh","zip":"100000"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(base).DeepMergeWith(patch, MergeOptions{NullMeans: tt.mode})
+			if err := got.Error(); err != nil {
+				t.Fatal(err)
+			}
+			if !Equal(got, Parse(tt.want)) {
+				t.Errorf("got %s, want %s", got.MustJSON(), tt.want)
+			}
+			if _, ok := got.Get("phone").ToInterface().(explicitNull); ok {
+				t.Error("explicit null leaked into merge result")
+			}
+		})
+	}
+}
+
+func TestExplicitNull(t *testing.T) {
+	j := Object().Set("a", Null()).SetNull("b.c")
+	if err := j.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if !j.Get("a").IsNull() || GetType(j.Get("b.c")) != "null" {
+		t.Error("explicit null should be reported as null")
+	}
+	if got := j.MustJSON(); got != `{"a":null,"b":{"c":null}}` {
+		t.Errorf("got %s", got)
+	}
+	if !Equal(j, Parse(`{"a":null,"b":{"c":null}}`)) {
+		t.Error("explicit null should equal plain null")
+	}
+	if got := j.Compact().MustJSON(); got != `{"b":{}}` {
+		t.Errorf("Compact should remove explicit nulls, got %s", got)
+	}
+
+	// 显式 null 按普通值参与冲突取舍
+	got := Parse(`{"a":1}`).DeepMergeWith(j, MergeOptions{NullMeans: NullDeletes, Conflict: PreferLeft})
+	if !Equal(got, Parse(`{"a":1,"b":{"c":null}}`)) {
+		t.Errorf("got %s", got.MustJSON())
+	}
+}
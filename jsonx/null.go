@@ -0,0 +1,40 @@
+package jsonx
+
+// 显式 null
+//
+// 补丁文档中的字段可以表达三种意图：
+//   - 不出现：保持不变
+//   - 普通 null（解析得到或 Set(path, nil)）：含义由 MergeOptions.NullMeans 决定，可配置为删除或忽略
+//   - 显式 null（SetNull 或 Set(path, Null())）：无论 NullMeans 如何，合并结果中都写入 null
+//
+// 因此在 NullDeletes 模式下，同一个补丁可以同时删除字段、把字段置为 null 并保留其余字段。
+// 显式 null 编码为 null，IsNull 与 Equal 将其视为 null；解析编码结果得到的是普通 null，
+// 补丁需要序列化传输时，接收方应约定 NullMeans 的含义。
+// Compact 会删除包括显式 null 在内的所有 null，不要在合并前对补丁调用 Compact。
+
+// explicitNull 显式 null 标记，合并时始终写入 null
+type explicitNull struct{}
+
+// MarshalJSON 编码为 null
+func (explicitNull) MarshalJSON() ([]byte, error) {
+	return []byte("null"), nil
+}
+
+// isNullValue 判断值是否为 null（包括显式 null）
+func isNullValue(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	_, ok := v.(explicitNull)
+	return ok
+}
+
+// Null 创建显式 null，可作为 Set 的值，合并时不受 MergeOptions.NullMeans 影响
+func Null() *JSON {
+	return &JSON{data: explicitNull{}}
+}
+
+// SetNull 将指定路径的值设置为显式 null（字段保留，值为 null），等同于 Set(path, Null())
+func (j *JSON) SetNull(path string) *JSON {
+	return j.Set(path, explicitNull{})
+}
@@ -0,0 +1,185 @@
+package jsonx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// maxNumberExponent 解析科学计数法整数时允许的最大指数，防止 "1e999999999" 耗尽内存
+const maxNumberExponent = 1000
+
+// ParseUseNumber 解析 JSON 字符串，数字保存为 json.Number 而不是 float64
+//
+// 默认的 Parse 会将所有数字解析为 float64，超过 2^53 的整数（如 64 位 ID）会丢失精度，
+// Int64 / Uint64 / BigInt 读取到的已是被舍入的值。需要精确读取大整数时应使用本函数。
+func ParseUseNumber(jsonStr string) *JSON {
+	return ParseBytesUseNumber([]byte(jsonStr))
+}
+
+// ParseBytesUseNumber 解析 JSON 字节数组，数字保存为 json.Number
+func ParseBytesUseNumber(jsonBytes []byte) *JSON {
+	if !json.Valid(jsonBytes) {
+		// 由 ParseBytes 生成带行列信息的语法错误
+		return ParseBytes(jsonBytes)
+	}
+
+	var data interface{}
+	decoder := json.NewDecoder(bytes.NewReader(jsonBytes))
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil {
+		return &JSON{err: err}
+	}
+	return &JSON{data: data}
+}
+
+// Uint64 转换为 uint64，负数或无法转换时返回 0
+//
+// 字符串支持十进制与科学计数法（如 "1e10"）。由 Parse 解析的数字为 float64，
+// 超过 2^53 的值已丢失精度，需要精确值时请使用 ParseUseNumber。
+func (j *JSON) Uint64() uint64 {
+	if j.err != nil {
+		return 0
+	}
+
+	switch v := j.data.(type) {
+	case uint64:
+		return v
+	case int:
+		if v >= 0 {
+			return uint64(v)
+		}
+	case int64:
+		if v >= 0 {
+			return uint64(v)
+		}
+	case float64:
+		if v >= 0 && v < math.MaxUint64 {
+			return uint64(v)
+		}
+	case json.Number:
+		if n, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+			return n
+		}
+		if f, err := v.Float64(); err == nil && f >= 0 && f < math.MaxUint64 {
+			return uint64(f)
+		}
+	case string:
+		if n, err := strconv.ParseUint(v, 10, 64); err == nil {
+			return n
+		}
+		if n, ok := parseIntegerText(v); ok && n.IsUint64() {
+			return n.Uint64()
+		}
+	}
+	return 0
+}
+
+// TryUint64 获取无符号整数，值不是数字、带有小数部分、为负数或超出范围时返回错误
+func (j *JSON) TryUint64() (uint64, error) {
+	n, err := j.exactInteger(false)
+	if err != nil {
+		return 0, err
+	}
+	if !n.IsUint64() {
+		return 0, fmt.Errorf("value at %s is %v, expected unsigned integer", displayPath(j.path), j.data)
+	}
+	return n.Uint64(), nil
+}
+
+// BigInt 获取任意精度整数，支持数字以及十进制或科学计数法表示的数字字符串
+//
+// 值为 float64 时返回该浮点数精确表示的整数，超过 2^53 的值可能已在解析时被舍入；
+// 需要与原文一致的结果请使用 ParseUseNumber 解析文档。
+func (j *JSON) BigInt() (*big.Int, error) {
+	return j.exactInteger(true)
+}
+
+// exactInteger 将当前值精确转换为整数，allowString 为 true 时接受数字字符串
+func (j *JSON) exactInteger(allowString bool) (*big.Int, error) {
+	if j.err != nil {
+		return nil, j.err
+	}
+
+	switch v := j.data.(type) {
+	case int:
+		return big.NewInt(int64(v)), nil
+	case int8:
+		return big.NewInt(int64(v)), nil
+	case int16:
+		return big.NewInt(int64(v)), nil
+	case int32:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case uint:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint8:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint16:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint32:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+	case float32:
+		return j.floatInteger(float64(v))
+	case float64:
+		return j.floatInteger(v)
+	case json.Number:
+		if n, ok := parseIntegerText(string(v)); ok {
+			return n, nil
+		}
+		return nil, j.notInteger()
+	case string:
+		if !allowString {
+			break
+		}
+		if n, ok := parseIntegerText(v); ok {
+			return n, nil
+		}
+		return nil, fmt.Errorf("value at %s is %q, expected integer", displayPath(j.path), v)
+	}
+	return nil, j.typeError("integer")
+}
+
+// floatInteger 将不带小数部分的浮点数转换为整数
+func (j *JSON) floatInteger(f float64) (*big.Int, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) || f != math.Trunc(f) {
+		return nil, j.notInteger()
+	}
+	n, _ := new(big.Float).SetFloat64(f).Int(nil)
+	return n, nil
+}
+
+// notInteger 值是数字但不是整数
+func (j *JSON) notInteger() error {
+	return fmt.Errorf("value at %s is %v, expected integer", displayPath(j.path), j.data)
+}
+
+// parseIntegerText 精确解析十进制或科学计数法表示的整数，如 "42"、"-7"、"1e10"、"1.5e3"
+func parseIntegerText(s string) (*big.Int, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.Trim(s, "0123456789+-.eE") != "" {
+		return nil, false
+	}
+	if n, ok := new(big.Int).SetString(s, 10); ok {
+		return n, true
+	}
+
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		exp, err := strconv.Atoi(s[i+1:])
+		if err != nil || exp > maxNumberExponent || exp < -maxNumberExponent {
+			return nil, false
+		}
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || !r.IsInt() {
+		return nil, false
+	}
+	return new(big.Int).Set(r.Num()), true
+}
@@ -0,0 +1,128 @@
+package jsonx
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestParseUseNumberPreservesLargeIntegers(t *testing.T) {
+	doc := `{"id": 9007199254740993, "max": 18446744073709551615, "huge": 123456789012345678901234567890, "neg": -9223372036854775808}`
+
+	// float64 模式下 2^53+1 会被舍入
+	if got := Parse(doc).Get("id").Int64(); got == 9007199254740993 {
+		t.Fatalf("expected float64 parsing to lose precision, got exact %d", got)
+	}
+
+	j := ParseUseNumber(doc)
+	if err := j.Error(); err != nil {
+		t.Fatalf("ParseUseNumber() error = %v", err)
+	}
+	if !j.Get("id").IsNumber() || GetType(j.Get("id")) != "number" {
+		t.Errorf("json.Number should be reported as number")
+	}
+	if got := j.Get("id").Int64(); got != 9007199254740993 {
+		t.Errorf("Int64() = %d, want 9007199254740993", got)
+	}
+	if got := j.Get("max").Uint64(); got != 18446744073709551615 {
+		t.Errorf("Uint64() = %d, want max uint64", got)
+	}
+	if got, err := j.Get("max").TryUint64(); err != nil || got != 18446744073709551615 {
+		t.Errorf("TryUint64() = %d, %v", got, err)
+	}
+	if got, err := j.Get("neg").TryInt(); err != nil || got != -9223372036854775808 {
+		t.Errorf("TryInt() = %d, %v", got, err)
+	}
+
+	huge, err := j.Get("huge").BigInt()
+	want, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	if err != nil || huge.Cmp(want) != 0 {
+		t.Errorf("BigInt() = %v, %v, want %v", huge, err, want)
+	}
+	if _, err := j.Get("huge").TryUint64(); err == nil || !strings.Contains(err.Error(), "expected unsigned integer") {
+		t.Errorf("TryUint64() on overflow error = %v", err)
+	}
+
+	// 往返序列化保留原文
+	out, _ := j.ToJSON()
+	if !strings.Contains(out, "9007199254740993") || !strings.Contains(out, "123456789012345678901234567890") {
+		t.Errorf("round trip lost digits: %s", out)
+	}
+}
+
+func TestParseUseNumberErrors(t *testing.T) {
+	var syntaxErr *SyntaxError
+	if err := ParseUseNumber(`{"a": 1,}`).Error(); !errors.As(err, &syntaxErr) {
+		t.Errorf("expected *SyntaxError, got %v", err)
+	}
+	if err := ParseUseNumber(`{"a": 1} {"b": 2}`).Error(); err == nil {
+		t.Error("trailing data should be rejected")
+	}
+}
+
+func TestScientificNotation(t *testing.T) {
+	j := New(map[string]interface{}{
+		"str":     "1e10",
+		"frac":    "1.5e3",
+		"notInt":  "1.5e-1",
+		"float":   1e10,
+		"number":  json.Number("2.5E2"),
+		"bomb":    "1e999999999",
+		"hex":     "0x10",
+		"negStr":  "-3",
+		"half":    9.5,
+		"text":    "abc",
+		"boolean": true,
+	})
+
+	for _, path := range []string{"str", "float"} {
+		if got := j.Get(path).Int64(); got != 1e10 {
+			t.Errorf("%s Int64() = %d", path, got)
+		}
+		if got := j.Get(path).Uint64(); got != 1e10 {
+			t.Errorf("%s Uint64() = %d", path, got)
+		}
+		if got := j.Get(path).Int(); got != 1e10 {
+			t.Errorf("%s Int() = %d", path, got)
+		}
+		if n, err := j.Get(path).BigInt(); err != nil || n.Int64() != 1e10 {
+			t.Errorf("%s BigInt() = %v, %v", path, n, err)
+		}
+	}
+
+	if n, err := j.Get("frac").BigInt(); err != nil || n.Int64() != 1500 {
+		t.Errorf("BigInt(1.5e3) = %v, %v", n, err)
+	}
+	if got, err := j.Get("number").TryInt(); err != nil || got != 250 {
+		t.Errorf("TryInt(json.Number 2.5E2) = %d, %v", got, err)
+	}
+	if got := j.Get("number").Float64(); got != 250 {
+		t.Errorf("Float64(json.Number) = %v", got)
+	}
+
+	for _, path := range []string{"notInt", "bomb", "hex", "text"} {
+		if _, err := j.Get(path).BigInt(); err == nil {
+			t.Errorf("BigInt(%s) should fail", path)
+		}
+	}
+	if _, err := j.Get("half").BigInt(); err == nil || err.Error() != "value at half is 9.5, expected integer" {
+		t.Errorf("BigInt(9.5) error = %v", err)
+	}
+
+	// 严格访问器不转换字符串
+	var typeErr *TypeError
+	if _, err := j.Get("str").TryUint64(); !errors.As(err, &typeErr) {
+		t.Errorf("TryUint64(string) error = %v, want *TypeError", err)
+	}
+	if _, err := j.Get("boolean").BigInt(); !errors.As(err, &typeErr) {
+		t.Errorf("BigInt(bool) error = %v, want *TypeError", err)
+	}
+	if got := j.Get("negStr").Uint64(); got != 0 {
+		t.Errorf("Uint64(-3) = %d, want 0", got)
+	}
+	if _, err := j.Get("missing").TryUint64(); !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("TryUint64(missing) error = %v", err)
+	}
+}
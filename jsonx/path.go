@@ -0,0 +1,214 @@
+package jsonx
+
+import (
+	"container/list"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// 预编译路径
+//
+// 路径在第一次使用时拆分为片段并解析数组下标与过滤片段，之后直接复用。
+// 字符串形式的 Get / Set / Has / Delete 等方法通过内部缓存共享编译结果，
+// 热点循环中也可以用 CompilePath 显式编译并持有，省去每次的缓存查找。
+
+// Path 编译后的路径，创建后不可变，可在多个 goroutine 间共享
+type Path struct {
+	raw      string
+	segments []pathSegment
+	err      error // 读取时的解析错误（如未闭合的过滤片段），写入时这类片段按普通键处理
+}
+
+// pathSegment 路径片段
+type pathSegment struct {
+	key     string         // 原始片段，作为对象的键
+	index   int            // 片段为整数时的数组下标
+	isIndex bool           // 片段可以解析为整数
+	filter  *filterSegment // 过滤片段，只用于读取与 Delete 的父路径
+}
+
+// CompilePath 编译路径，语法与 Get 相同（点号分隔、数组下标、负数下标与过滤片段）
+// 路径无法解析时，错误在 Get / Has 时返回，也可以通过 Err 提前检查
+func CompilePath(path string) *Path {
+	p := &Path{raw: path}
+	if path == "" {
+		return p
+	}
+
+	parts, err := splitQueryPath(path)
+	if err != nil {
+		p.err = err
+		parts = strings.Split(path, ".")
+	}
+	p.segments = make([]pathSegment, len(parts))
+	for i, part := range parts {
+		seg := pathSegment{key: part}
+		if idx, ok := parseIndex(part); ok {
+			seg.index, seg.isIndex = idx, true
+		} else if p.err == nil {
+			if filter, ok := parseFilterSegment(part); ok {
+				seg.filter = &filter
+			}
+		}
+		p.segments[i] = seg
+	}
+	return p
+}
+
+// parseIndex 解析数组下标，规则与 strconv.Atoi 相同
+// 先检查首字符，普通的对象键不会产生 strconv 的错误值分配
+func parseIndex(part string) (int, bool) {
+	if part == "" {
+		return 0, false
+	}
+	if c := part[0]; (c < '0' || c > '9') && c != '-' && c != '+' {
+		return 0, false
+	}
+	idx, err := strconv.Atoi(part)
+	return idx, err == nil
+}
+
+// String 返回原始路径
+func (p *Path) String() string {
+	return p.raw
+}
+
+// Err 返回路径的解析错误
+func (p *Path) Err() error {
+	return p.err
+}
+
+// Get 获取路径处的值，与 j.Get(path) 相同
+func (p *Path) Get(j *JSON) *JSON {
+	if j.err != nil {
+		return j
+	}
+
+	value, err := p.get(j.data)
+	return &JSON{data: value, err: err, path: joinJSONPath(j.path, p.raw)}
+}
+
+// Set 设置路径处的值，与 j.Set(path, value) 相同
+func (p *Path) Set(j *JSON, value interface{}) *JSON {
+	if j.err != nil {
+		return j
+	}
+	if node, ok := value.(*JSON); ok {
+		if node.err != nil {
+			return &JSON{data: j.data, err: node.err}
+		}
+		value = node.data
+	}
+
+	data, err := setValue(j.data, p.segments, value)
+	if err != nil {
+		return &JSON{data: j.data, err: err}
+	}
+	j.data = data
+	return &JSON{data: j.data}
+}
+
+// Has 检查路径是否存在（值可以为 null），与 j.Has(path) 相同
+func (p *Path) Has(j *JSON) bool {
+	if j.err != nil {
+		return false
+	}
+
+	_, err := p.get(j.data)
+	return err == nil
+}
+
+// get 在 data 上按路径取值
+func (p *Path) get(data interface{}) (interface{}, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return getBySegments(data, p.segments, p.raw)
+}
+
+// pathCacheSize 路径缓存的容量，超出后淘汰最近未使用的路径
+const pathCacheSize = 1024
+
+// pathCache 以路径字符串为键的近似 LRU 缓存，并发安全
+//
+// 采用 CLOCK（second-chance）淘汰：命中时只读取 sync.Map 并设置访问标记，不加锁，并发读取的 goroutine 之间没有争用；
+// 加入新路径时加锁，从最早加入的路径开始检查，有访问标记的清除标记后移到队尾，淘汰第一个没有标记的路径。
+// 因此持续命中的热点路径不会被大量一次性的动态路径（如 items.%d）挤出缓存。
+type pathCache struct {
+	entries  sync.Map // string -> *pathCacheEntry
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // 元素为 *pathCacheEntry，队首为下一个淘汰候选
+}
+
+// pathCacheEntry 缓存项，referenced 记录上次检查后是否被命中
+type pathCacheEntry struct {
+	key        string
+	path       *Path
+	referenced atomic.Bool
+}
+
+// compiledPaths 字符串路径 API 共用的缓存
+var compiledPaths = newPathCache(pathCacheSize)
+
+func newPathCache(capacity int) *pathCache {
+	return &pathCache{
+		capacity: capacity,
+		order:    list.New(),
+	}
+}
+
+// get 返回路径的编译结果，不在缓存中时编译并加入缓存
+func (c *pathCache) get(path string) *Path {
+	if e, ok := c.entries.Load(path); ok {
+		entry := e.(*pathCacheEntry)
+		// 已有标记时不再写入，避免热点路径在多个 CPU 间反复写同一缓存行
+		if !entry.referenced.Load() {
+			entry.referenced.Store(true)
+		}
+		return entry.path
+	}
+
+	// 编译在锁外进行，并发编译同一路径时结果相同，保留先加入的一个即可
+	entry := &pathCacheEntry{key: path, path: CompilePath(path)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if existing, loaded := c.entries.LoadOrStore(path, entry); loaded {
+		return existing.(*pathCacheEntry).path
+	}
+	c.order.PushBack(entry)
+	if c.order.Len() > c.capacity {
+		c.evictLocked()
+	}
+	return entry.path
+}
+
+// evictLocked 淘汰一个最近未命中的路径，调用方需持有锁
+func (c *pathCache) evictLocked() {
+	// 最多检查一轮：并发命中可能在检查期间重新设置标记，一轮之后直接淘汰队首
+	for i := c.order.Len(); i > 0; i-- {
+		front := c.order.Front()
+		if !front.Value.(*pathCacheEntry).referenced.CompareAndSwap(true, false) {
+			break
+		}
+		c.order.MoveToBack(front)
+	}
+	oldest := c.order.Front()
+	c.order.Remove(oldest)
+	c.entries.Delete(oldest.Value.(*pathCacheEntry).key)
+}
+
+// len 返回缓存中的路径数量
+func (c *pathCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// lookupPath 从缓存获取路径的编译结果
+func lookupPath(path string) *Path {
+	return compiledPaths.get(path)
+}
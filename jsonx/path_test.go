@@ -0,0 +1,246 @@
+package jsonx
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCompilePath(t *testing.T) {
+	doc := Parse(companyJSON)
+
+	p := CompilePath("company.departments.[name=研发部].employees.-1.name")
+	if err := p.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Get(doc).String(); got != "李四" {
+		t.Errorf("Get = %q", got)
+	}
+	if !p.Has(doc) || CompilePath("company.departments.9").Has(doc) {
+		t.Error("Has mismatch")
+	}
+	if got := p.Get(doc).path; got != doc.Get(p.String()).path {
+		t.Errorf("path = %q", got)
+	}
+
+	// 与字符串 API 结果一致
+	for _, path := range []string{"company.departments.1.code", "company.departments.[code=3].name", "company.missing"} {
+		want, got := doc.Get(path), CompilePath(path).Get(doc)
+		if want.Error() != nil || got.Error() != nil {
+			if fmt.Sprint(want.Error()) != fmt.Sprint(got.Error()) {
+				t.Errorf("%s: error %v, want %v", path, got.Error(), want.Error())
+			}
+		} else if !Equal(want, got) {
+			t.Errorf("%s: compiled result differs from Get", path)
+		}
+	}
+
+	// 同一个编译结果可反复用于不同文档
+	set := CompilePath("a.list.2.name")
+	for i := 0; i < 3; i++ {
+		j := set.Set(Object(), fmt.Sprintf("v%d", i))
+		if err := j.Error(); err != nil {
+			t.Fatal(err)
+		}
+		if got := j.MustJSON(); got != fmt.Sprintf(`{"a":{"list":[null,null,{"name":"v%d"}]}}`, i) {
+			t.Errorf("Set = %s", got)
+		}
+	}
+	if got := CompilePath("a").Set(Object(), Null()).MustJSON(); got != `{"a":null}` {
+		t.Errorf("Set with *JSON value = %s", got)
+	}
+	if err := CompilePath("a.x").Set(Parse(`{"a":[1]}`), 1).Error(); err == nil {
+		t.Error("expected error setting property on array")
+	}
+
+	// 未闭合的过滤片段：读取时报错，写入时按普通键处理（与 Set 一致）
+	bad := CompilePath("a.[b=1")
+	if bad.Err() == nil || bad.Get(Object()).Error() == nil || bad.Has(Object()) {
+		t.Error("unterminated filter should fail on read")
+	}
+	if got := bad.Set(Object(), 1).MustJSON(); got != `{"a":{"[b=1":1}}` {
+		t.Errorf("Set with unterminated filter = %s", got)
+	}
+
+	if got := CompilePath("").Get(Parse(`[1]`)).MustJSON(); got != `[1]` {
+		t.Errorf("empty path = %s", got)
+	}
+}
+
+func TestPathCacheEviction(t *testing.T) {
+	c := newPathCache(2)
+	a := c.get("a")
+	c.get("b")
+	if c.get("a") != a {
+		t.Error("cached path should be reused")
+	}
+	c.get("c") // a 被命中过，淘汰最近未命中的 b
+	if c.len() != 2 {
+		t.Errorf("len = %d, want 2", c.len())
+	}
+	if _, ok := c.entries.Load("b"); ok {
+		t.Error("least recently used path should be evicted")
+	}
+	if c.get("a") != a {
+		t.Error("recently hit path should survive eviction")
+	}
+
+	// 未被命中的路径按加入顺序淘汰
+	c.get("d")
+	if _, ok := c.entries.Load("c"); ok {
+		t.Error("unreferenced path should be evicted first")
+	}
+	if c.get("c") == nil || c.len() != 2 {
+		t.Errorf("len = %d, want 2", c.len())
+	}
+}
+
+func TestPathCacheKeepsHotPath(t *testing.T) {
+	const capacity = 16
+	c := newPathCache(capacity)
+	hot := c.get("items.0.id")
+
+	// 热点路径与大量一次性的动态路径交替使用
+	for i := 1; i <= capacity+1; i++ {
+		c.get(fmt.Sprintf("items.%d", i))
+		if c.get("items.0.id") != hot {
+			t.Fatalf("hot path was evicted after %d insertions", i)
+		}
+	}
+	if c.len() != capacity {
+		t.Errorf("len = %d, want %d", c.len(), capacity)
+	}
+	if _, ok := c.entries.Load("items.1"); ok {
+		t.Error("oldest dynamic path should have been evicted")
+	}
+}
+
+func TestPathCacheConcurrent(t *testing.T) {
+	c := newPathCache(8)
+	doc := Parse(`{"items":[{"id":0},{"id":1},{"id":2},{"id":3}]}`)
+
+	// 12 个不同的路径轮流使用，超过容量，持续触发淘汰
+	var paths []string
+	for k := 0; k < 4; k++ {
+		paths = append(paths, fmt.Sprintf("items.%d.id", k), fmt.Sprintf("items.%d.id", k-4), fmt.Sprintf("items.[id=%d].id", k))
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				n := (g + i) % len(paths)
+				if got := c.get(paths[n]).Get(doc).Int(); got != n/3 {
+					t.Errorf("%s = %d, want %d", paths[n], got, n/3)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if c.len() > 8 {
+		t.Errorf("cache exceeded capacity: %d", c.len())
+	}
+}
+
+func benchmarkPathDocument() *JSON {
+	return Parse(`{"a":{"b":{"c":{"d":[{"e":1},{"e":2},{"e":3}]}}}}`)
+}
+
+// BenchmarkPathGet 比较每次重新解析路径、字符串 API（命中缓存）与预编译路径的开销
+func BenchmarkPathGet(b *testing.B) {
+	doc := benchmarkPathDocument()
+	const path = "a.b.c.d.2.e"
+
+	b.Run("parse each call", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			CompilePath(path).Get(doc)
+		}
+	})
+	b.Run("string cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			doc.Get(path)
+		}
+	})
+	b.Run("compiled", func(b *testing.B) {
+		p := CompilePath(path)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			p.Get(doc)
+		}
+	})
+	b.Run("string cached parallel", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				doc.Get(path)
+			}
+		})
+	})
+}
+
+func BenchmarkPathSet(b *testing.B) {
+	doc := benchmarkPathDocument()
+	const path = "a.b.c.d.2.e"
+
+	b.Run("parse each call", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			CompilePath(path).Set(doc, i)
+		}
+	})
+	b.Run("string cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			doc.Set(path, i)
+		}
+	})
+	b.Run("compiled", func(b *testing.B) {
+		p := CompilePath(path)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			p.Set(doc, i)
+		}
+	})
+}
+
+func TestDeleteUsesCompiledPath(t *testing.T) {
+	doc := Parse(companyJSON)
+
+	// 负数下标与 Get 的解析一致
+	if doc.Get("company.departments.-2.lead").Error() != nil {
+		t.Fatal("precondition: lead should exist")
+	}
+	if err := doc.Delete("company.departments.-2.lead").Error(); err != nil {
+		t.Fatalf("delete with negative index: %v", err)
+	}
+	if doc.Exists("company.departments.3.lead") {
+		t.Error("negative index should delete from the same element Get resolves")
+	}
+
+	// 转义的过滤片段作为父路径
+	if err := doc.Delete(`company.departments.[name=R&D\.v2].employees`).Error(); err != nil {
+		t.Fatalf("delete through filter segment: %v", err)
+	}
+	if doc.Exists("company.departments.2.employees") || !doc.Exists("company.departments.2.code") {
+		t.Errorf("filter delete = %s", doc.Get("company.departments.2").MustJSON())
+	}
+
+	// 父路径不存在时与 Get 返回相同的错误
+	if err := doc.Delete("company.departments.[name=财务部].code").Error(); !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("missing filter match = %v, want ErrPathNotFound", err)
+	}
+
+	// 无法解析的过滤片段与 Set 一样按普通键处理
+	literal := Object().Set("a.[b=1", 1)
+	if err := literal.Delete("a.[b=1").Error(); err != nil || literal.MustJSON() != `{"a":{}}` {
+		t.Errorf("literal key delete = %s, %v", literal.MustJSON(), err)
+	}
+}
@@ -0,0 +1,194 @@
+package jsonx
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 数据质量分析：按路径模式统计字段分布，并查找数组中重复的值
+
+// MaxProfileDistinct Profile 对每个路径统计不同取值的上限，达到上限后停止记录以限制内存
+var MaxProfileDistinct = 1000
+
+// PathProfile 单个路径模式的统计，路径格式与 TypeDiff 一致（数组元素为 []，如 users[].email）
+type PathProfile struct {
+	Path           string   `json:"path"`
+	Count          int      `json:"count"`          // 出现次数
+	Types          []string `json:"types"`          // 观察到的类型，按字典序排列
+	Nulls          int      `json:"nulls"`          // null 的次数
+	NullRatio      float64  `json:"nullRatio"`      // Nulls / Count
+	Numbers        int      `json:"numbers"`        // 数值的次数，为 0 时 Min / Max 无意义
+	Min            float64  `json:"min"`            // 数值最小值
+	Max            float64  `json:"max"`            // 数值最大值
+	Distinct       int      `json:"distinct"`       // 不同标量取值的数量，最多为 MaxProfileDistinct
+	DistinctCapped bool     `json:"distinctCapped"` // 达到上限，真实数量可能更多
+}
+
+// DocProfile 文档的字段分布，按路径排序
+type DocProfile struct {
+	Paths []PathProfile `json:"paths"`
+}
+
+// Get 获取指定路径模式的统计
+func (p DocProfile) Get(path string) (PathProfile, bool) {
+	i := sort.Search(len(p.Paths), func(i int) bool { return p.Paths[i].Path >= path })
+	if i < len(p.Paths) && p.Paths[i].Path == path {
+		return p.Paths[i], true
+	}
+	return PathProfile{}, false
+}
+
+// Profile 统计文档中每个路径模式的出现次数、类型、null 比例、数值范围与不同取值数量
+// 数组下标折叠为 []，因此对象数组的同名字段汇总在同一条统计中；超过 maxNestingDepth 的部分不再展开
+func Profile(j *JSON) DocProfile {
+	if j.err != nil {
+		return DocProfile{Paths: []PathProfile{}}
+	}
+
+	acc := make(map[string]*profileAcc)
+	collectProfile(j.data, "", 0, acc)
+
+	paths := make([]PathProfile, 0, len(acc))
+	for path, a := range acc {
+		p := a.profile
+		p.Path = path
+		p.Types = make([]string, 0, len(a.types))
+		for t := range a.types {
+			p.Types = append(p.Types, t)
+		}
+		sort.Strings(p.Types)
+		p.NullRatio = float64(p.Nulls) / float64(p.Count)
+		p.Distinct = len(a.distinct)
+		paths = append(paths, p)
+	}
+	sort.Slice(paths, func(i, k int) bool {
+		return paths[i].Path < paths[k].Path
+	})
+	return DocProfile{Paths: paths}
+}
+
+// profileAcc 单个路径的累计状态
+type profileAcc struct {
+	profile  PathProfile
+	types    map[string]bool
+	distinct map[string]struct{}
+}
+
+// collectProfile 递归累计每个路径模式的统计
+func collectProfile(data interface{}, path string, depth int, acc map[string]*profileAcc) {
+	a, ok := acc[path]
+	if !ok {
+		a = &profileAcc{types: make(map[string]bool), distinct: make(map[string]struct{})}
+		acc[path] = a
+	}
+	p := &a.profile
+	p.Count++
+	a.types[GetType(&JSON{data: data})] = true
+
+	switch v := data.(type) {
+	case map[string]interface{}:
+		if depth >= maxNestingDepth {
+			return
+		}
+		for k, val := range v {
+			key := k
+			if path != "" {
+				key = path + "." + k
+			}
+			collectProfile(val, key, depth+1, acc)
+		}
+		return
+	case []interface{}:
+		if depth >= maxNestingDepth {
+			return
+		}
+		for _, val := range v {
+			collectProfile(val, path+"[]", depth+1, acc)
+		}
+		return
+	case nil:
+		p.Nulls++
+	}
+
+	if f, ok := toFloat64(data); ok {
+		if p.Numbers == 0 || f < p.Min {
+			p.Min = f
+		}
+		if p.Numbers == 0 || f > p.Max {
+			p.Max = f
+		}
+		p.Numbers++
+	}
+	if len(a.distinct) < MaxProfileDistinct {
+		a.distinct[scalarKey(data)] = struct{}{}
+	} else if _, seen := a.distinct[scalarKey(data)]; !seen {
+		p.DistinctCapped = true
+	}
+}
+
+// scalarKey 标量的比较键，数值按值比较（1 与 1.0 相同），不同类型互不冲突
+func scalarKey(v interface{}) string {
+	if f, ok := toFloat64(v); ok {
+		return "n:" + strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	switch val := v.(type) {
+	case string:
+		return "s:" + val
+	case bool:
+		return "b:" + strconv.FormatBool(val)
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("?:%v", val)
+	}
+}
+
+// FindDuplicateValues 查找对象数组中重复的字段值，返回 值 → 元素下标（升序），只包含出现两次以上的值
+//
+// path 由数组路径、"[]" 与元素内的字段路径组成，如 "users[].email"、"orders[].buyer.phone"；
+// 根数组使用 "[].email"。字符串值原样作为键，其他值使用紧凑 JSON 编码（如 1、true）。
+// 字段缺失或为 null 的元素被忽略；path 格式不正确或数组不存在时设置链式错误并返回 nil。
+func (j *JSON) FindDuplicateValues(path string) map[string][]int {
+	if j.err != nil {
+		return nil
+	}
+
+	arrayPath, fieldPath, ok := strings.Cut(path, "[]")
+	if !ok {
+		j.err = fmt.Errorf("invalid duplicate path %q: expected array[].field", path)
+		return nil
+	}
+	fieldPath = strings.TrimPrefix(fieldPath, ".")
+
+	arr, err := j.lookupArray(arrayPath, false)
+	if err != nil {
+		j.err = err
+		return nil
+	}
+
+	groups := make(map[string][]int)
+	for i, item := range arr {
+		value, err := (&JSON{data: item}).getByPath(fieldPath)
+		if err != nil || value == nil {
+			continue
+		}
+		key, ok := value.(string)
+		if !ok {
+			encoded, err := marshalJSON(value, false)
+			if err != nil {
+				continue
+			}
+			key = string(encoded)
+		}
+		groups[key] = append(groups[key], i)
+	}
+
+	for key, indexes := range groups {
+		if len(indexes) < 2 {
+			delete(groups, key)
+		}
+	}
+	return groups
+}
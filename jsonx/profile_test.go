@@ -0,0 +1,100 @@
+package jsonx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProfileFiftyUsers(t *testing.T) {
+	j := Parse(fiftyUsersJSON())
+	j.Set("users.3.email", nil).Set("users.40.email", 40)
+
+	profile := Profile(j)
+	tests := []struct {
+		path     string
+		count    int
+		types    []string
+		nulls    int
+		numbers  int
+		min, max float64
+		distinct int
+	}{
+		{"", 1, []string{"object"}, 0, 0, 0, 0, 0},
+		{"users", 1, []string{"array"}, 0, 0, 0, 0, 0},
+		{"users[]", 50, []string{"object"}, 0, 0, 0, 0, 0},
+		{"users[].id", 50, []string{"number"}, 0, 50, 0, 49, 50},
+		{"users[].email", 50, []string{"null", "number", "string"}, 1, 1, 40, 40, 50},
+		{"users[].profile.age", 50, []string{"number"}, 0, 50, 20, 69, 50},
+		{"users[].profile.bio", 50, []string{"string"}, 0, 0, 0, 0, 1},
+		{"users[].profile.tags", 50, []string{"array"}, 0, 0, 0, 0, 0},
+		{"users[].profile.tags[]", 100, []string{"string"}, 0, 0, 0, 0, 15},
+		{"users[].profile.settings.privacy.email_visible", 50, []string{"boolean"}, 0, 0, 0, 0, 2},
+	}
+	for _, tt := range tests {
+		p, ok := profile.Get(tt.path)
+		if !ok {
+			t.Errorf("missing profile for %q", tt.path)
+			continue
+		}
+		if p.Count != tt.count || !reflect.DeepEqual(p.Types, tt.types) || p.Nulls != tt.nulls ||
+			p.Numbers != tt.numbers || p.Min != tt.min || p.Max != tt.max || p.Distinct != tt.distinct {
+			t.Errorf("%q profile = %+v, want count=%d types=%v nulls=%d numbers=%d min=%v max=%v distinct=%d",
+				tt.path, p, tt.count, tt.types, tt.nulls, tt.numbers, tt.min, tt.max, tt.distinct)
+		}
+	}
+
+	if p, _ := profile.Get("users[].email"); p.NullRatio != 0.02 {
+		t.Errorf("email NullRatio = %v, want 0.02", p.NullRatio)
+	}
+	if len(profile.Paths) != 17 {
+		t.Errorf("expected 17 path patterns, got %d", len(profile.Paths))
+	}
+	for i := 1; i < len(profile.Paths); i++ {
+		if profile.Paths[i-1].Path >= profile.Paths[i].Path {
+			t.Fatalf("paths not sorted: %q before %q", profile.Paths[i-1].Path, profile.Paths[i].Path)
+		}
+	}
+}
+
+func TestProfileDistinctCap(t *testing.T) {
+	old := MaxProfileDistinct
+	MaxProfileDistinct = 3
+	defer func() { MaxProfileDistinct = old }()
+
+	p, _ := Profile(Parse(`[1, 2, 2, 3, 1.0]`)).Get("[]")
+	if p.Distinct != 3 || p.DistinctCapped {
+		t.Errorf("exactly at cap: %+v", p)
+	}
+	p, _ = Profile(Parse(`[1, 2, 3, 4]`)).Get("[]")
+	if p.Distinct != 3 || !p.DistinctCapped {
+		t.Errorf("over cap: %+v", p)
+	}
+}
+
+func TestFindDuplicateValues(t *testing.T) {
+	j := Parse(fiftyUsersJSON())
+	j.Set("users.7.email", "user8@example.com").Set("users.30.email", "user8@example.com").Set("users.9.email", nil)
+
+	dups := j.FindDuplicateValues("users[].email")
+	if want := map[string][]int{"user8@example.com": {7, 8, 30}}; !reflect.DeepEqual(dups, want) {
+		t.Errorf("email duplicates = %v, want %v", dups, want)
+	}
+
+	tags := j.FindDuplicateValues("users[].profile.tags.0")
+	if len(tags) != 5 || !reflect.DeepEqual(tags["tag_0"], []int{0, 5, 10, 15, 20, 25, 30, 35, 40, 45}) {
+		t.Errorf("tag duplicates = %v", tags)
+	}
+
+	if got := Parse(`[{"n":1},{"n":"x"},{"n":1.0}]`).FindDuplicateValues("[].n"); !reflect.DeepEqual(got, map[string][]int{"1": {0, 2}}) {
+		t.Errorf("root array duplicates = %v", got)
+	}
+
+	bad := Parse(`{"users":{}}`)
+	if got := bad.FindDuplicateValues("users[].email"); got != nil || bad.Error() == nil {
+		t.Errorf("non-array should set error, got %v, %v", got, bad.Error())
+	}
+	bad = Parse(`{"users":[]}`)
+	if got := bad.FindDuplicateValues("users.email"); got != nil || bad.Error() == nil {
+		t.Errorf("path without [] should set error, got %v, %v", got, bad.Error())
+	}
+}
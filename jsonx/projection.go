@@ -0,0 +1,48 @@
+package jsonx
+
+// ProjectOption Project 的选项
+type ProjectOption func(*projectOptions)
+
+// projectOptions Project 选项集合
+type projectOptions struct {
+	skipMissing bool
+}
+
+// SkipMissing 源路径不存在时不输出该字段，默认输出 null
+func SkipMissing() ProjectOption {
+	return func(o *projectOptions) { o.skipMissing = true }
+}
+
+// Project 按映射提取字段，生成新的扁平对象：键为 mapping 的键，值取自对应的源路径
+//
+//	j.Project(map[string]string{"userName": "user.profile.name", "firstTag": "user.tags.0"})
+//
+// 源路径与 Get 的语法相同（支持负数下标）；路径不存在时输出 null，使用 SkipMissing 时跳过。
+// 输出的键不会按 "." 拆分。结果为深拷贝，修改结果不影响原文档。
+func (j *JSON) Project(mapping map[string]string, opts ...ProjectOption) *JSON {
+	if j.err != nil {
+		return j
+	}
+
+	var o projectOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	result := make(map[string]interface{}, len(mapping))
+	for key, path := range mapping {
+		value, err := j.getByPath(path)
+		if err != nil {
+			if !o.skipMissing {
+				result[key] = nil
+			}
+			continue
+		}
+		cloned, err := deepClone(value)
+		if err != nil {
+			return &JSON{err: err}
+		}
+		result[key] = cloned
+	}
+	return &JSON{data: result}
+}
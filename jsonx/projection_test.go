@@ -0,0 +1,121 @@
+package jsonx
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// projectionCase 与 types.XMap 共享的投影测试用例，见 testdata/projection_cases.json
+// jsonx 与 types 属于不同的模块，各自保存一份用例，types 的测试检查两份内容一致
+type projectionCase struct {
+	Name     string                 `json:"name"`
+	Input    map[string]interface{} `json:"input"`
+	Pick     []string               `json:"pick"`
+	Omit     []string               `json:"omit"`
+	Expected map[string]interface{} `json:"expected"`
+}
+
+func loadProjectionCases(t *testing.T) []projectionCase {
+	t.Helper()
+	data, err := os.ReadFile("testdata/projection_cases.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cases []projectionCase
+	if err := json.Unmarshal(data, &cases); err != nil {
+		t.Fatal(err)
+	}
+	return cases
+}
+
+func TestPickOmitSharedCases(t *testing.T) {
+	for _, c := range loadProjectionCases(t) {
+		result := FromMap(c.Input)
+		if c.Pick != nil {
+			result = Pick(result, c.Pick...)
+		}
+		if c.Omit != nil {
+			result = Omit(result, c.Omit...)
+		}
+
+		if result.Error() != nil {
+			t.Errorf("%s: unexpected error %v", c.Name, result.Error())
+			continue
+		}
+		if !reflect.DeepEqual(result.data, c.Expected) {
+			t.Errorf("%s: got %v, want %v", c.Name, result.data, c.Expected)
+		}
+	}
+}
+
+func TestPickOmitWildcard(t *testing.T) {
+	doc := Parse(`{"users":[{"name":"tom","email":"t@x","auth":{"token":"1"}},{"name":"ann","auth":{"token":"2"}},{"name":"bob","email":"b@x"}],"teams":{"a":{"lead":"tom","budget":1},"b":{"lead":"ann","budget":2}}}`)
+
+	picked := Pick(doc, "users.*.email", "teams.*.lead")
+	want := `{"teams":{"a":{"lead":"tom"},"b":{"lead":"ann"}},"users":[{"email":"t@x"},null,{"email":"b@x"}]}`
+	if got := picked.MustJSON(); got != want {
+		t.Errorf("Pick = %s\nwant   %s", got, want)
+	}
+
+	omitted := Omit(doc, "users.*.auth.token", "teams.*.budget", "missing.*.x")
+	want = `{"teams":{"a":{"lead":"tom"},"b":{"lead":"ann"}},"users":[{"auth":{},"email":"t@x","name":"tom"},{"auth":{},"name":"ann"},{"email":"b@x","name":"bob"}]}`
+	if got := omitted.MustJSON(); got != want {
+		t.Errorf("Omit = %s\nwant   %s", got, want)
+	}
+	if !doc.Has("users.0.auth.token") {
+		t.Error("Omit modified the source document")
+	}
+}
+
+func TestPickReturnsDeepCopy(t *testing.T) {
+	doc := Parse(`{"db":{"host":"localhost","password":"secret"},"tags":["a"]}`)
+
+	picked := Pick(doc, "db", "db.host", "tags")
+	picked.Set("db.host", "changed").Delete("db.password").Set("tags.0", "b")
+
+	if got := doc.MustJSON(); got != `{"db":{"host":"localhost","password":"secret"},"tags":["a"]}` {
+		t.Errorf("source modified through Pick result: %s", got)
+	}
+}
+
+func TestProject(t *testing.T) {
+	doc := Parse(`{"user": {"profile": {"name": "张三", "age": 30}, "tags": ["admin", "dev"], "address": {"city": "北京"}}}`)
+
+	dto := doc.Project(map[string]string{
+		"userName": "user.profile.name",
+		"firstTag": "user.tags.0",
+		"lastTag":  "user.tags.-1",
+		"address":  "user.address",
+		"email":    "user.profile.email",
+		"a.b":      "user.profile.age",
+	})
+	want := map[string]interface{}{
+		"userName": "张三",
+		"firstTag": "admin",
+		"lastTag":  "dev",
+		"address":  map[string]interface{}{"city": "北京"},
+		"email":    nil,
+		"a.b":      float64(30),
+	}
+	if !reflect.DeepEqual(dto.data, want) {
+		t.Fatalf("Project = %#v", dto.data)
+	}
+
+	// 结果为深拷贝
+	dto.Set("address.city", "上海")
+	if doc.Get("user.address.city").String() != "北京" {
+		t.Error("Project result should not share containers with the source")
+	}
+
+	skipped := doc.Project(map[string]string{"name": "user.profile.name", "email": "user.profile.email"}, SkipMissing())
+	if !reflect.DeepEqual(skipped.data, map[string]interface{}{"name": "张三"}) {
+		t.Fatalf("SkipMissing = %#v", skipped.data)
+	}
+
+	failed := Parse(`{`).Project(map[string]string{"a": "a"})
+	if failed.Error() == nil {
+		t.Error("Project should propagate chain errors")
+	}
+}
@@ -0,0 +1,240 @@
+package jsonx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 按字段值查询数组元素
+//
+// 路径中以 "[" 开头、"]" 结尾且含 "=" 的片段为过滤片段，如
+// "company.departments.[name=研发部].employees.0.name"，选出数组中 name 字段等于 "研发部" 的对象：
+//   - 第一个未转义的 "=" 分隔字段名与值，字段名为元素的直接键
+//   - "\" 转义下一个字符，值中的 "]"、"="、"." 与 "\" 写作 "\]"、"\="、"\." 与 "\\"
+//   - 值按字段的类型比较：字符串原样比较，数值按数值比较（"1" 与 1.0 相等），布尔为 true/false，null 为 null
+//
+// 过滤片段按"查找"语义设计，结果的形状取决于匹配的个数：
+//   - 一个元素匹配时折叠为该元素本身，后续路径直接在其上解析，如 "[name=研发部].employees.0.name" 得到字符串
+//   - 多个元素匹配时结果为数组，后续路径分别作用于每个匹配元素（不存在的跳过），得到投影后的数组
+//   - 没有元素匹配时返回 ErrPathNotFound
+//
+// 用于唯一键（如 id、code）时可以直接取到单个值；字段可能匹配多个元素而调用方需要固定的数组类型时，
+// 使用总是返回数组的 Where。过滤片段用于读取（Get、Has 等）与 Delete 的父路径，Set 不支持。
+
+// filterSegment 解析后的过滤片段
+type filterSegment struct {
+	field string
+	value string
+}
+
+// String 还原为路径片段，用于错误信息
+func (f filterSegment) String() string {
+	return "[" + escapeFilter(f.field) + "=" + escapeFilter(f.value) + "]"
+}
+
+// escapeFilter 转义过滤片段中的特殊字符
+func escapeFilter(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		switch r {
+		case '\\', ']', '=', '.':
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// FilterSegment 生成过滤片段，对字段名与值中的特殊字符转义，可直接拼接到路径中
+//
+//	j.Get("departments." + jsonx.FilterSegment("name", "R&D.1") + ".manager")
+func FilterSegment(field, value string) string {
+	return filterSegment{field: field, value: value}.String()
+}
+
+// splitQueryPath 拆分路径，过滤片段内的 "." 不作为分隔符
+// 路径不含 "[" 时与 strings.Split 相同
+func splitQueryPath(path string) ([]string, error) {
+	if !strings.Contains(path, "[") {
+		return strings.Split(path, "."), nil
+	}
+
+	var parts []string
+	start := 0
+	for i := 0; i <= len(path); i++ {
+		if i == len(path) || path[i] == '.' {
+			parts = append(parts, path[start:i])
+			start = i + 1
+			continue
+		}
+		if path[i] != '[' || i != start {
+			continue
+		}
+		// 过滤片段：跳到未转义的 "]"
+		end := -1
+		for k := i + 1; k < len(path); k++ {
+			if path[k] == '\\' {
+				k++
+				continue
+			}
+			if path[k] == ']' {
+				end = k
+				break
+			}
+		}
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated filter segment in path %q", path)
+		}
+		i = end
+	}
+	return parts, nil
+}
+
+// parseFilterSegment 解析过滤片段，不是过滤片段时返回 false
+func parseFilterSegment(part string) (filterSegment, bool) {
+	if len(part) < 2 || part[0] != '[' || part[len(part)-1] != ']' {
+		return filterSegment{}, false
+	}
+
+	var field, value strings.Builder
+	target, seenEq := &field, false
+	inner := part[1 : len(part)-1]
+	for i := 0; i < len(inner); i++ {
+		c := inner[i]
+		switch {
+		case c == '\\' && i+1 < len(inner):
+			i++
+			target.WriteByte(inner[i])
+		case c == '=' && !seenEq:
+			target, seenEq = &value, true
+		default:
+			target.WriteByte(c)
+		}
+	}
+	if !seenEq || field.Len() == 0 {
+		return filterSegment{}, false
+	}
+	return filterSegment{field: field.String(), value: value.String()}, true
+}
+
+// matches 判断数组元素是否匹配
+func (f filterSegment) matches(item interface{}) bool {
+	obj, ok := item.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	actual, exists := obj[f.field]
+	if !exists {
+		return false
+	}
+
+	switch v := actual.(type) {
+	case string:
+		return v == f.value
+	case bool:
+		return strconv.FormatBool(v) == f.value
+	case nil:
+		return f.value == "null"
+	}
+	if af, ok := toFloat64(actual); ok {
+		want, err := strconv.ParseFloat(f.value, 64)
+		return err == nil && af == want
+	}
+	return false
+}
+
+// getBySegments 按编译后的路径片段取值，支持数组下标、负数下标与过滤片段
+func getBySegments(current interface{}, segments []pathSegment, path string) (interface{}, error) {
+	for i, seg := range segments {
+		if arr, isArray := current.([]interface{}); isArray {
+			if seg.isIndex {
+				resolved, ok := resolveIndex(seg.index, len(arr))
+				if !ok {
+					return nil, fmt.Errorf("array index out of range: %d", seg.index)
+				}
+				current = arr[resolved]
+				continue
+			}
+			if seg.filter != nil {
+				return filterArray(arr, *seg.filter, segments[i+1:], path)
+			}
+		}
+
+		if obj, ok := current.(map[string]interface{}); ok {
+			value, exists := obj[seg.key]
+			if !exists {
+				return nil, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+			}
+			current = value
+		} else {
+			return nil, fmt.Errorf("cannot access property '%s' on non-object", seg.key)
+		}
+	}
+	return current, nil
+}
+
+// filterArray 选出匹配的元素并在其上解析剩余路径
+func filterArray(arr []interface{}, filter filterSegment, rest []pathSegment, path string) (interface{}, error) {
+	var matches []interface{}
+	for _, item := range arr {
+		if filter.matches(item) {
+			matches = append(matches, item)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: no element matches %s in %s", ErrPathNotFound, filter, path)
+	case 1:
+		return getBySegments(matches[0], rest, path)
+	}
+
+	if len(rest) == 0 {
+		return matches, nil
+	}
+	results := make([]interface{}, 0, len(matches))
+	for _, item := range matches {
+		if value, err := getBySegments(item, rest, path); err == nil {
+			results = append(results, value)
+		}
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+	}
+	return results, nil
+}
+
+// Where 选出 path 处数组中 field 字段等于 value 的对象元素（数值按数值比较，容器按结构比较）
+// 结果总是由匹配元素组成的数组（只有一个匹配时也是单元素数组），没有匹配时设置链式错误
+func (j *JSON) Where(path, field string, value interface{}) *JSON {
+	if j.err != nil {
+		return j
+	}
+
+	target, err := j.getByPath(path)
+	if err != nil {
+		return &JSON{err: err}
+	}
+	arr, ok := target.([]interface{})
+	if !ok {
+		return &JSON{err: (&JSON{data: target, path: joinJSONPath(j.path, path)}).typeError("array")}
+	}
+
+	comparer := &equalComparer{}
+	var matches []interface{}
+	for _, item := range arr {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if actual, exists := obj[field]; exists && comparer.equal(actual, value, nil) {
+			matches = append(matches, item)
+		}
+	}
+
+	if len(matches) == 0 {
+		return &JSON{err: fmt.Errorf("%w: no element in %s has %s = %v", ErrPathNotFound, displayPath(path), field, value)}
+	}
+	return &JSON{data: matches}
+}
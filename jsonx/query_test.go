@@ -0,0 +1,135 @@
+package jsonx
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const companyJSON = `{"company": {"departments": [
+	{"name": "研发部", "code": 1, "active": true, "employees": [{"name": "张三"}, {"name": "李四"}]},
+	{"name": "市场部", "code": 2, "active": false, "employees": [{"name": "王五"}]},
+	{"name": "R&D.v2", "code": 3, "active": true, "employees": []},
+	{"name": "a=b]c\\d", "code": 4, "lead": null},
+	"not an object"
+]}}`
+
+func TestFilterSegmentInPath(t *testing.T) {
+	doc := Parse(companyJSON)
+
+	if got := doc.Get("company.departments.[name=研发部].employees.0.name").String(); got != "张三" {
+		t.Errorf("filter by name = %q", got)
+	}
+	if got := doc.Get("company.departments.[code=2].name").String(); got != "市场部" {
+		t.Errorf("numeric filter = %q", got)
+	}
+	if got := doc.Get("company.departments.[code=2.0].name").String(); got != "市场部" {
+		t.Errorf("numeric filter should compare by value, got %q", got)
+	}
+	if got := doc.Get("company.departments.[lead=null].code").Int(); got != 4 {
+		t.Errorf("null filter = %d", got)
+	}
+	if !doc.Has("company.departments.[name=市场部]") || doc.Has("company.departments.[name=财务部]") {
+		t.Error("Has should understand filter segments")
+	}
+
+	// 多个匹配时返回数组，后续路径作用于每个匹配元素
+	names := doc.Get("company.departments.[active=true].name")
+	if !reflect.DeepEqual(names.data, []interface{}{"研发部", "R&D.v2"}) {
+		t.Errorf("multiple matches = %#v", names.data)
+	}
+	if n := doc.Get("company.departments.[active=true]").Length(); n != 2 {
+		t.Errorf("multiple matches without rest should return 2 elements, got %d", n)
+	}
+
+	missing := doc.Get("company.departments.[name=财务部].name")
+	if !errors.Is(missing.Error(), ErrPathNotFound) {
+		t.Errorf("zero matches should be ErrPathNotFound, got %v", missing.Error())
+	}
+}
+
+func TestFilterSegmentEscaping(t *testing.T) {
+	doc := Parse(companyJSON)
+
+	if got := doc.Get(`company.departments.[name=R&D\.v2].code`).Int(); got != 3 {
+		t.Errorf("escaped dot = %d", got)
+	}
+	// 未转义的 "." 位于过滤片段内也不会拆分路径
+	if got := doc.Get(`company.departments.[name=R&D.v2].code`).Int(); got != 3 {
+		t.Errorf("dot inside brackets = %d", got)
+	}
+	if got := doc.Get(`company.departments.[name=a\=b\]c\\d].code`).Int(); got != 4 {
+		t.Errorf("escaped = ] and backslash = %d", got)
+	}
+
+	segment := FilterSegment("name", `a=b]c\d`)
+	if segment != `[name=a\=b\]c\\d]` {
+		t.Errorf("FilterSegment = %s", segment)
+	}
+	if got := doc.Get("company.departments." + segment + ".code").Int(); got != 4 {
+		t.Errorf("FilterSegment round trip = %d", got)
+	}
+
+	if err := doc.Get(`company.departments.[name=x`).Error(); err == nil {
+		t.Error("unterminated filter should fail")
+	}
+
+	// 对象上的方括号键按普通键处理
+	literal := Parse(`{"[a=b]": 1}`)
+	if literal.Get("[a=b]").Int() != 1 {
+		t.Error("bracket keys on objects should still be accessible")
+	}
+}
+
+func TestFilterSegmentResultShape(t *testing.T) {
+	doc := Parse(companyJSON)
+
+	// 一个匹配：折叠为元素本身，后续路径得到单个值
+	if one := doc.Get("company.departments.[name=研发部]"); !one.IsObject() {
+		t.Errorf("single match should collapse to the element, got %#v", one.data)
+	}
+	if one := doc.Get("company.departments.[name=研发部].code"); one.IsArray() || one.Int() != 1 {
+		t.Errorf("single match projection = %#v", one.data)
+	}
+
+	// 多个匹配：数组，后续路径投影到每个匹配元素
+	many := doc.Get("company.departments.[active=true]")
+	if !many.IsArray() || many.Length() != 2 {
+		t.Errorf("multiple matches should be an array, got %#v", many.data)
+	}
+	codes := doc.Get("company.departments.[active=true].code")
+	if !codes.IsArray() || codes.Length() != 2 || !codes.Get("0").IsNumber() {
+		t.Errorf("multiple match projection = %#v", codes.data)
+	}
+}
+
+func TestWhere(t *testing.T) {
+	doc := Parse(companyJSON)
+
+	// 单个匹配同样返回数组
+	rd := doc.Where("company.departments", "name", "研发部")
+	if rd.Error() != nil || !rd.IsArray() || rd.Length() != 1 || rd.Get("0.employees.1.name").String() != "李四" {
+		t.Fatalf("single match = %v %v", rd.data, rd.Error())
+	}
+	if got := doc.Where("company.departments", "code", 3).Get("0.name").String(); got != "R&D.v2" {
+		t.Errorf("numeric Where = %q", got)
+	}
+
+	active := doc.Where("company.departments", "active", true)
+	if !active.IsArray() || active.Length() != 2 {
+		t.Errorf("multiple matches should return an array, got %#v", active.data)
+	}
+
+	none := doc.Where("company.departments", "name", "财务部")
+	if !errors.Is(none.Error(), ErrPathNotFound) {
+		t.Errorf("zero matches should set chain error, got %v", none.Error())
+	}
+	if none.Get("name").Error() == nil {
+		t.Error("chain error should propagate")
+	}
+
+	var typeErr *TypeError
+	if err := doc.Where("company", "name", "x").Error(); !errors.As(err, &typeErr) {
+		t.Errorf("non-array should be a TypeError, got %v", err)
+	}
+}
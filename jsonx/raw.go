@@ -0,0 +1,34 @@
+package jsonx
+
+import "fmt"
+
+// 原始 JSON 片段的嵌入与提取
+
+// SetRaw 校验并解析已编码的 JSON 片段后写入指定路径，避免 Set(string) 将其二次编码为字符串
+//
+// 片段中的数字以 json.Number 保存，GetRaw / ToJSON 输出时保持原有的数字文本与精度；
+// 但片段会被重新序列化，空白与对象键顺序不保证与原文一致。片段非法时设置链式错误（*SyntaxError）。
+func (j *JSON) SetRaw(path string, rawJSON []byte) *JSON {
+	if j.err != nil {
+		return j
+	}
+
+	fragment := ParseBytesUseNumber(rawJSON)
+	if fragment.err != nil {
+		return &JSON{data: j.data, err: fmt.Errorf("invalid raw JSON for %s: %w", displayPath(joinJSONPath(j.path, path)), fragment.err)}
+	}
+	return j.Set(path, fragment.data)
+}
+
+// GetRaw 将指定路径的子树序列化为紧凑 JSON，只编码该子树而不序列化整个文档
+func (j *JSON) GetRaw(path string) ([]byte, error) {
+	if j.err != nil {
+		return nil, j.err
+	}
+
+	value, err := j.getByPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return marshalJSON(value, false)
+}
@@ -0,0 +1,93 @@
+package jsonx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSetRaw(t *testing.T) {
+	doc := Object().
+		SetRaw("payload", []byte(`{"id": 9007199254740993, "amount": 1.50, "tags": ["a"]}`)).
+		SetRaw("meta.cached", []byte(` [1, 2] `))
+	if doc.Error() != nil {
+		t.Fatal(doc.Error())
+	}
+
+	// 嵌入为结构而不是字符串，数字文本与精度保持不变
+	want := `{"meta":{"cached":[1,2]},"payload":{"amount":1.50,"id":9007199254740993,"tags":["a"]}}`
+	if got := doc.MustJSON(); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+	if !doc.Get("payload").IsObject() {
+		t.Error("payload should be an object")
+	}
+	if id, err := doc.Get("payload.id").TryUint64(); err != nil || id != 9007199254740993 {
+		t.Errorf("payload.id = %d, %v", id, err)
+	}
+}
+
+func TestSetRawInvalid(t *testing.T) {
+	doc := Parse(`{"a":1}`)
+	result := doc.Get("a").SetRaw("", []byte(`{"broken":`))
+	var syntaxErr *SyntaxError
+	if !errors.As(result.Error(), &syntaxErr) {
+		t.Fatalf("error = %v, want *SyntaxError", result.Error())
+	}
+	if got := result.Error().Error(); !strings.HasPrefix(got, "invalid raw JSON for a:") {
+		t.Errorf("error message = %q", got)
+	}
+
+	if err := doc.SetRaw("b", []byte(`not json`)).Set("c", 1).Error(); err == nil {
+		t.Error("chain error should propagate")
+	}
+	if doc.Has("b") || doc.Has("c") {
+		t.Errorf("invalid fragment modified the document: %s", doc.MustJSON())
+	}
+}
+
+func TestGetRaw(t *testing.T) {
+	doc := Parse(`{"user":{"name":"tom","roles":["admin"]},"n":1}`)
+
+	raw, err := doc.GetRaw("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(raw) != `{"name":"tom","roles":["admin"]}` {
+		t.Errorf("GetRaw(user) = %s", raw)
+	}
+
+	raw, err = doc.GetRaw("user.roles.0")
+	if err != nil || string(raw) != `"admin"` {
+		t.Errorf("GetRaw(user.roles.0) = %s, %v", raw, err)
+	}
+
+	if _, err := doc.GetRaw("missing"); !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("error = %v, want ErrPathNotFound", err)
+	}
+
+	// SetRaw 与 GetRaw 往返
+	copied := Object().SetRaw("u", mustRaw(t, doc, "user"))
+	if !Equal(copied.Get("u"), doc.Get("user")) {
+		t.Errorf("round trip = %s", copied.MustJSON())
+	}
+}
+
+func TestBuilderAppendRawJSON(t *testing.T) {
+	arr := NewArrayBuilder().AppendRawJSON(`{"a":1}`).AppendRawJSON(`[true]`).Build()
+	if got := arr.MustJSON(); got != `[{"a":1},[true]]` {
+		t.Errorf("got %s", got)
+	}
+	if err := NewArrayBuilder().AppendRawJSON(`{`).Build().Error(); err == nil {
+		t.Error("expected error for invalid fragment")
+	}
+}
+
+func mustRaw(t *testing.T, j *JSON, path string) []byte {
+	t.Helper()
+	raw, err := j.GetRaw(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
@@ -0,0 +1,118 @@
+package jsonx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 路径间移动与复制值
+
+// Rename 将 oldPath 的值移动到 newPath，目标路径的中间容器按 Set 的规则自动创建
+//
+// oldPath 为数组元素时该元素会从数组中移除，后续元素前移，newPath 按移除后的文档解析。
+// 源路径不存在或 newPath 位于 oldPath 之下（如 a → a.b）时设置链式错误；
+// 写入目标失败时源值会被还原，文档保持不变。
+func (j *JSON) Rename(oldPath, newPath string) *JSON {
+	if j.err != nil {
+		return j
+	}
+	if oldPath == newPath {
+		if _, err := j.getByPath(oldPath); err != nil {
+			return &JSON{data: j.data, err: fmt.Errorf("cannot rename %s: %w", displayPath(oldPath), err)}
+		}
+		return &JSON{data: j.data}
+	}
+	if isSubPath(oldPath, newPath) {
+		return &JSON{data: j.data, err: fmt.Errorf("cannot rename %s to %s: target is inside the source", displayPath(oldPath), displayPath(newPath))}
+	}
+
+	value, restore, err := j.detachByPath(oldPath)
+	if err != nil {
+		return &JSON{data: j.data, err: fmt.Errorf("cannot rename %s: %w", displayPath(oldPath), err)}
+	}
+	if err := j.setByPath(newPath, value); err != nil {
+		restore()
+		return &JSON{data: j.data, err: fmt.Errorf("cannot rename %s to %s: %w", displayPath(oldPath), displayPath(newPath), err)}
+	}
+	return &JSON{data: j.data}
+}
+
+// Copy 将 srcPath 的值深拷贝到 dstPath，目标路径的中间容器按 Set 的规则自动创建
+// 源路径不存在时设置链式错误；允许复制到源路径之下（如 a → a.b）
+func (j *JSON) Copy(srcPath, dstPath string) *JSON {
+	if j.err != nil {
+		return j
+	}
+
+	value, err := j.getByPath(srcPath)
+	if err != nil {
+		return &JSON{data: j.data, err: fmt.Errorf("cannot copy %s: %w", displayPath(srcPath), err)}
+	}
+	cloned, err := deepClone(value)
+	if err != nil {
+		return &JSON{data: j.data, err: err}
+	}
+	if err := j.setByPath(dstPath, cloned); err != nil {
+		return &JSON{data: j.data, err: fmt.Errorf("cannot copy %s to %s: %w", displayPath(srcPath), displayPath(dstPath), err)}
+	}
+	return &JSON{data: j.data}
+}
+
+// detachByPath 从文档中取出路径处的值，返回取出的值与还原函数
+// 对象字段直接删除；数组元素通过生成新切片移除并写回父容器，原切片保持不变以便还原
+func (j *JSON) detachByPath(path string) (interface{}, func(), error) {
+	if path == "" {
+		value := j.data
+		j.data = nil
+		return value, func() { j.data = value }, nil
+	}
+
+	parentPath, last := "", path
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		parentPath, last = path[:i], path[i+1:]
+	}
+	parent, err := j.getByPath(parentPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch container := parent.(type) {
+	case map[string]interface{}:
+		value, exists := container[last]
+		if !exists {
+			return nil, nil, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+		}
+		delete(container, last)
+		return value, func() { container[last] = value }, nil
+
+	case []interface{}:
+		idx, err := strconv.Atoi(last)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot access property '%s' on array", last)
+		}
+		resolved, ok := resolveIndex(idx, len(container))
+		if !ok {
+			return nil, nil, fmt.Errorf("array index out of range: %d", idx)
+		}
+		value := container[resolved]
+		remaining := make([]interface{}, 0, len(container)-1)
+		remaining = append(remaining, container[:resolved]...)
+		remaining = append(remaining, container[resolved+1:]...)
+		if err := j.setByPath(parentPath, remaining); err != nil {
+			return nil, nil, err
+		}
+		return value, func() { j.setByPath(parentPath, container) }, nil
+
+	default:
+		return nil, nil, fmt.Errorf("cannot access property '%s' on non-object", last)
+	}
+}
+
+// isSubPath 判断 path 是否位于 parent 之下（不含相等），空路径表示根节点
+func isSubPath(parent, path string) bool {
+	if parent == "" {
+		return path != ""
+	}
+	return strings.HasPrefix(path, parent+".")
+}
@@ -0,0 +1,133 @@
+package jsonx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRename(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		from, to string
+		want     string
+	}{
+		{"object key", `{"a":1,"b":2}`, "a", "c", `{"b":2,"c":1}`},
+		{"nested target creates objects", `{"user":{"name":"tom"}}`, "user.name", "profile.info.name", `{"profile":{"info":{"name":"tom"}},"user":{}}`},
+		{"nested target creates arrays", `{"tag":"x"}`, "tag", "tags.0", `{"tags":["x"]}`},
+		{"array element to key", `{"list":[1,2,3]}`, "list.1", "second", `{"list":[1,3],"second":2}`},
+		{"negative index", `{"list":[1,2,3]}`, "list.-1", "last", `{"last":3,"list":[1,2]}`},
+		{"into array after removal", `{"list":["a","b","c"]}`, "list.0", "list.2", `{"list":["b","c","a"]}`},
+		{"promote child to parent", `{"a":{"b":{"c":1}}}`, "a.b", "a", `{"a":{"c":1}}`},
+		{"same path", `{"a":1}`, "a", "a", `{"a":1}`},
+		{"into nested array", `{"v":1,"rows":[[0]]}`, "v", "rows.0.1", `{"rows":[[0,1]]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Parse(tt.input).Rename(tt.from, tt.to)
+			if result.Error() != nil {
+				t.Fatalf("Rename(%q, %q) error: %v", tt.from, tt.to, result.Error())
+			}
+			if got := result.MustJSON(); got != tt.want {
+				t.Errorf("Rename(%q, %q) = %s, want %s", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenameErrors(t *testing.T) {
+	t.Run("missing source", func(t *testing.T) {
+		j := Parse(`{"a":1}`)
+		result := j.Rename("missing", "b")
+		if !errors.Is(result.Error(), ErrPathNotFound) {
+			t.Fatalf("error = %v, want ErrPathNotFound", result.Error())
+		}
+		if got := j.MustJSON(); got != `{"a":1}` {
+			t.Errorf("document changed to %s", got)
+		}
+	})
+
+	t.Run("into own child", func(t *testing.T) {
+		j := Parse(`{"a":{"x":1}}`)
+		err := j.Rename("a", "a.b").Error()
+		if err == nil || !strings.Contains(err.Error(), "inside the source") {
+			t.Fatalf("error = %v, want target inside source error", err)
+		}
+		if got := j.MustJSON(); got != `{"a":{"x":1}}` {
+			t.Errorf("document changed to %s", got)
+		}
+	})
+
+	t.Run("root into child", func(t *testing.T) {
+		if err := Parse(`{"a":1}`).Rename("", "b").Error(); err == nil {
+			t.Fatal("expected error renaming root into a child")
+		}
+	})
+
+	t.Run("sibling with common prefix is allowed", func(t *testing.T) {
+		result := Parse(`{"a":1}`).Rename("a", "ab")
+		if result.Error() != nil {
+			t.Fatal(result.Error())
+		}
+		if got := result.MustJSON(); got != `{"ab":1}` {
+			t.Errorf("got %s", got)
+		}
+	})
+
+	t.Run("failed target restores object source", func(t *testing.T) {
+		j := Parse(`{"a":1,"s":"text"}`)
+		if err := j.Rename("a", "s.x").Error(); err == nil {
+			t.Fatal("expected error setting property on string")
+		}
+		if got := j.MustJSON(); got != `{"a":1,"s":"text"}` {
+			t.Errorf("document = %s, want source restored", got)
+		}
+	})
+
+	t.Run("failed target restores array source", func(t *testing.T) {
+		j := Parse(`{"list":[1,2,3],"s":"text"}`)
+		if err := j.Rename("list.1", "s.0").Error(); err == nil {
+			t.Fatal("expected error setting index on string")
+		}
+		if got := j.MustJSON(); got != `{"list":[1,2,3],"s":"text"}` {
+			t.Errorf("document = %s, want source restored", got)
+		}
+	})
+
+	t.Run("chain error propagates", func(t *testing.T) {
+		result := Parse(`{"a":1}`).Rename("missing", "b").Set("c", 2)
+		if !errors.Is(result.Error(), ErrPathNotFound) {
+			t.Fatalf("error = %v, want ErrPathNotFound", result.Error())
+		}
+	})
+}
+
+func TestCopy(t *testing.T) {
+	j := Parse(`{"user":{"name":"tom","tags":["a"]}}`)
+	result := j.Copy("user", "backup.user").Copy("user.tags.0", "first")
+	if result.Error() != nil {
+		t.Fatal(result.Error())
+	}
+	want := `{"backup":{"user":{"name":"tom","tags":["a"]}},"first":"a","user":{"name":"tom","tags":["a"]}}`
+	if got := result.MustJSON(); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	// 副本与源互不影响
+	j.Set("user.tags.0", "changed")
+	if got := j.Get("backup.user.tags.0").String(); got != "a" {
+		t.Errorf("copy shares data with source: %q", got)
+	}
+
+	// 允许复制到源路径之下
+	nested := Parse(`{"a":{"x":1}}`).Copy("a", "a.b")
+	if got := nested.MustJSON(); got != `{"a":{"b":{"x":1},"x":1}}` {
+		t.Errorf("copy into child = %s", got)
+	}
+
+	if err := Parse(`{}`).Copy("missing", "b").Error(); !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("error = %v, want ErrPathNotFound", err)
+	}
+}
@@ -0,0 +1,152 @@
+package jsonx
+
+import "sync"
+
+// SafeJSON 并发安全的 JSON 包装器，所有读写操作由读写锁保护
+//
+// 读取方法返回的 *JSON 均为深拷贝，可在锁外自由使用；
+// 回调（ForEach / Update / View）在持锁期间执行，回调内不可再调用同一 SafeJSON 的方法。
+type SafeJSON struct {
+	mu   sync.RWMutex
+	json *JSON
+}
+
+// Safe 将 JSON 包装为并发安全版本，包装后不应再直接修改原 JSON
+func Safe(j *JSON) *SafeJSON {
+	if j == nil {
+		j = Object()
+	}
+	return &SafeJSON{json: j}
+}
+
+// Get 获取指定路径的值（深拷贝）
+func (s *SafeJSON) Get(path string) *JSON {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.json.err != nil {
+		return &JSON{err: s.json.err}
+	}
+	value, err := s.json.getByPath(path)
+	if err != nil {
+		return &JSON{err: err}
+	}
+	cloned, err := deepClone(value)
+	return &JSON{data: cloned, err: err}
+}
+
+// Has 检查指定路径是否存在
+func (s *SafeJSON) Has(path string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.json.Has(path)
+}
+
+// Exists 检查路径是否存在，值可以为 null
+func (s *SafeJSON) Exists(path string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.json.Exists(path)
+}
+
+// HasValue 检查路径是否存在且值不为 null
+func (s *SafeJSON) HasValue(path string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.json.HasValue(path)
+}
+
+// Set 设置指定路径的值
+func (s *SafeJSON) Set(path string, value interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.json.Set(path, value).err
+}
+
+// Delete 删除指定路径的值
+func (s *SafeJSON) Delete(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.json.Delete(path).err
+}
+
+// Length 获取数组或对象的长度
+func (s *SafeJSON) Length() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.json.Length()
+}
+
+// Keys 获取对象的所有键
+func (s *SafeJSON) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.json.Keys()
+}
+
+// ForEach 在读锁保护下遍历数组或对象
+func (s *SafeJSON) ForEach(fn func(key string, value *JSON) bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	s.json.ForEach(fn)
+}
+
+// View 在读锁保护下执行只读操作
+func (s *SafeJSON) View(fn func(j *JSON)) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fn(s.json)
+}
+
+// Update 在写锁保护下执行一组修改操作
+func (s *SafeJSON) Update(fn func(j *JSON) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return fn(s.json)
+}
+
+// Clone 返回独立的非并发安全副本，适合本地大量读取
+func (s *SafeJSON) Clone() *JSON {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.json.Clone()
+}
+
+// ToJSON 转换为 JSON 字符串
+func (s *SafeJSON) ToJSON() (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	jsonBytes, err := s.json.ToBytes()
+	if err != nil {
+		return "", err
+	}
+	return string(jsonBytes), nil
+}
+
+// ToPrettyJSON 转换为格式化的 JSON 字符串
+func (s *SafeJSON) ToPrettyJSON() (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.json.ToPrettyJSON()
+}
+
+// Error 获取错误信息
+func (s *SafeJSON) Error() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.json.err
+}
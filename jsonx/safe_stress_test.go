@@ -82,9 +82,8 @@ func TestMediumArrayOperations(t *testing.T) {
 	}
 }
 
-// 测试中等大小 JSON 字符串解析
-func TestMediumJSONParsing(t *testing.T) {
-	// 构建中等大小 JSON 字符串（减少到50个用户）
+// fiftyUsersJSON 构建包含 50 个用户的中等大小 JSON，供解析与数据分析测试共用
+func fiftyUsersJSON() string {
 	var builder strings.Builder
 	builder.WriteString(`{"users": [`)
 
@@ -113,7 +112,12 @@ func TestMediumJSONParsing(t *testing.T) {
 	}
 
 	builder.WriteString(`]}`)
-	mediumJSON := builder.String()
+	return builder.String()
+}
+
+// 测试中等大小 JSON 字符串解析
+func TestMediumJSONParsing(t *testing.T) {
+	mediumJSON := fiftyUsersJSON()
 
 	// 解析中等大小 JSON
 	j := Parse(mediumJSON)
@@ -337,10 +341,13 @@ func TestBoundaryConditions(t *testing.T) {
 		t.Error("超大索引应该产生错误")
 	}
 
-	// 测试负数索引
-	negativeIndex := arr.Index(-1)
+	// 测试负数索引：-1 表示最后一个元素，超出长度的负数索引产生错误
+	if last := arr.Index(-1); last.Error() != nil || last.String() != "item" {
+		t.Errorf("负数索引 -1 应该返回最后一个元素: %v", last.Error())
+	}
+	negativeIndex := arr.Index(-2)
 	if negativeIndex.Error() == nil {
-		t.Error("负数索引应该产生错误")
+		t.Error("越界的负数索引应该产生错误")
 	}
 }
 
@@ -0,0 +1,69 @@
+package jsonx
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestSafeJSONConcurrentAccess(t *testing.T) {
+	s := Safe(Parse(`{"config": {"version": 0}}`))
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(2)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				if err := s.Set(fmt.Sprintf("config.worker%d", w), i); err != nil {
+					t.Errorf("Set failed: %v", err)
+					return
+				}
+				s.Set("config.version", i)
+			}
+		}(w)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				s.Get("config").Get("version").Int()
+				s.Has("config.worker0")
+				s.ForEach(func(key string, value *JSON) bool { return true })
+				if _, err := s.ToJSON(); err != nil {
+					t.Errorf("ToJSON failed: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for w := 0; w < 4; w++ {
+		if v := s.Get(fmt.Sprintf("config.worker%d", w)).Int(); v != 199 {
+			t.Errorf("worker%d = %d, want 199", w, v)
+		}
+	}
+}
+
+func TestSafeJSONCloneIsIndependent(t *testing.T) {
+	s := Safe(Parse(`{"a": {"b": 1}}`))
+
+	local := s.Clone()
+	local.Set("a.b", 2)
+	if v := s.Get("a.b").Int(); v != 1 {
+		t.Errorf("Clone should be independent, got a.b=%d", v)
+	}
+
+	got := s.Get("a")
+	got.Set("b", 3)
+	if v := s.Get("a.b").Int(); v != 1 {
+		t.Errorf("Get should return a copy, got a.b=%d", v)
+	}
+
+	err := s.Update(func(j *JSON) error {
+		j.Set("a.b", 4)
+		return j.Set("a.c", 5).Error()
+	})
+	if err != nil || s.Get("a.b").Int() != 4 || s.Get("a.c").Int() != 5 {
+		t.Errorf("Update failed: %v", err)
+	}
+}
@@ -0,0 +1,427 @@
+package jsonx
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Schema 简单的 JSON Schema 验证
+//
+// 设置 Type 时先校验类型；其余关键字按值的实际类型生效，
+// 例如 Pattern 只作用于字符串，MinItems 只作用于数组。
+type Schema struct {
+	Type       string             `json:"type"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Items      *Schema            `json:"items,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	MinLength  *int               `json:"minLength,omitempty"`
+	MaxLength  *int               `json:"maxLength,omitempty"`
+	Minimum    *float64           `json:"minimum,omitempty"`
+	Maximum    *float64           `json:"maximum,omitempty"`
+
+	Enum                 []interface{} `json:"enum,omitempty"`                 // 允许的取值，按结构比较
+	Pattern              string        `json:"pattern,omitempty"`              // 字符串正则（RE2 语法）
+	Format               string        `json:"format,omitempty"`               // 字符串格式：email、uri、date-time、date、uuid
+	AdditionalProperties *bool         `json:"additionalProperties,omitempty"` // 为 false 时不允许 Properties 以外的字段
+	MinItems             *int          `json:"minItems,omitempty"`
+	MaxItems             *int          `json:"maxItems,omitempty"`
+	UniqueItems          bool          `json:"uniqueItems,omitempty"`
+	MultipleOf           *float64      `json:"multipleOf,omitempty"`
+
+	Nullable bool      `json:"nullable,omitempty"` // 为 true 时 null 直接通过，不再检查其他关键字
+	OneOf    []*Schema `json:"oneOf,omitempty"`    // 恰好匹配其中一个
+	AnyOf    []*Schema `json:"anyOf,omitempty"`    // 至少匹配其中一个
+	AllOf    []*Schema `json:"allOf,omitempty"`    // 全部匹配
+}
+
+// ParseSchema 从 JSON 字符串加载 Schema，并检查正则表达式是否有效
+func ParseSchema(jsonStr string) (*Schema, error) {
+	schema := &Schema{}
+	if err := json.Unmarshal([]byte(jsonStr), schema); err != nil {
+		return nil, err
+	}
+	if err := schema.checkPatterns(""); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// checkPatterns 递归编译 Schema 中的正则表达式
+func (s *Schema) checkPatterns(path string) error {
+	if s.Pattern != "" {
+		if _, err := compileSchemaPattern(s.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern at %s: %w", displayPath(path), err)
+		}
+	}
+	for prop, schema := range s.Properties {
+		if schema == nil {
+			continue
+		}
+		if err := schema.checkPatterns(joinSchemaPath(path, prop)); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		if err := s.Items.checkPatterns(path + "[]"); err != nil {
+			return err
+		}
+	}
+	for _, branches := range [][]*Schema{s.OneOf, s.AnyOf, s.AllOf} {
+		for _, schema := range branches {
+			if schema == nil {
+				continue
+			}
+			if err := schema.checkPatterns(path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ValidationError 单个校验失败
+type ValidationError struct {
+	Path    string // 出错位置，对象字段以 "." 连接，数组元素为 [i]，根为空
+	Message string
+
+	// Branches oneOf / anyOf 没有分支匹配时各分支的失败原因，下标与分支一一对应
+	Branches []ValidationErrors
+}
+
+// Error 实现 error 接口
+func (e *ValidationError) Error() string {
+	return displayPath(e.Path) + ": " + e.Message
+}
+
+// ValidationErrors 校验收集到的全部失败
+type ValidationErrors []*ValidationError
+
+// Error 实现 error 接口，每个失败占一行
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Unwrap 返回全部失败，支持 errors.As 获取单个 *ValidationError
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Validate 验证 JSON 是否符合 Schema，返回 ValidationErrors 包含所有失败
+func (s *Schema) Validate(j *JSON) error {
+	if j.err != nil {
+		return j.err
+	}
+
+	var errs ValidationErrors
+	s.validateValue(j, "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidateString 解析 JSON 字符串并验证，解析失败时返回解析错误
+func (s *Schema) ValidateString(jsonStr string) error {
+	return s.Validate(Parse(jsonStr))
+}
+
+// validateValue 验证值，失败追加到 errs
+func (s *Schema) validateValue(j *JSON, path string, errs *ValidationErrors) {
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.Nullable && j.data == nil {
+		return
+	}
+
+	if s.Type != "" && !matchesSchemaType(j, s.Type) {
+		fail("expected %s, got %s", s.Type, GetType(j))
+		return
+	}
+
+	if len(s.Enum) > 0 && !s.matchesEnum(j.data) {
+		allowed, _ := json.Marshal(s.Enum)
+		fail("value must be one of %s", allowed)
+	}
+
+	switch {
+	case j.IsObject():
+		s.validateObject(j, path, errs)
+	case j.IsArray():
+		s.validateArray(j, path, errs)
+	case j.IsString():
+		s.validateString(j.String(), fail)
+	case j.IsNumber():
+		s.validateNumber(j.Float64(), fail)
+	}
+
+	s.validateCombinators(j, path, errs)
+}
+
+// validateCombinators 验证 allOf / anyOf / oneOf
+//
+// allOf 的失败直接追加，消息前加上分支下标；anyOf 与 oneOf 没有分支匹配时
+// 只追加一条汇总错误，各分支的失败原因放在 Branches 中。
+func (s *Schema) validateCombinators(j *JSON, path string, errs *ValidationErrors) {
+	for i, branch := range s.AllOf {
+		if branch == nil {
+			continue
+		}
+		var branchErrs ValidationErrors
+		branch.validateValue(j, path, &branchErrs)
+		for _, err := range branchErrs {
+			err.Message = fmt.Sprintf("allOf[%d]: %s", i, err.Message)
+			*errs = append(*errs, err)
+		}
+	}
+
+	if len(s.AnyOf) > 0 {
+		if matched, branches := matchBranches(s.AnyOf, j, path); len(matched) == 0 {
+			*errs = append(*errs, branchMismatch(path, "anyOf", branches))
+		}
+	}
+
+	if len(s.OneOf) > 0 {
+		matched, branches := matchBranches(s.OneOf, j, path)
+		switch len(matched) {
+		case 0:
+			*errs = append(*errs, branchMismatch(path, "oneOf", branches))
+		case 1:
+		default:
+			*errs = append(*errs, &ValidationError{
+				Path:    path,
+				Message: fmt.Sprintf("value matches %d schemas in oneOf (branches %v), expected exactly one", len(matched), matched),
+			})
+		}
+	}
+}
+
+// matchBranches 逐个验证分支，返回匹配的分支下标与每个分支的失败
+func matchBranches(branches []*Schema, j *JSON, path string) ([]int, []ValidationErrors) {
+	var matched []int
+	failures := make([]ValidationErrors, len(branches))
+	for i, branch := range branches {
+		if branch != nil {
+			branch.validateValue(j, path, &failures[i])
+		}
+		if len(failures[i]) == 0 {
+			matched = append(matched, i)
+		}
+	}
+	return matched, failures
+}
+
+// branchMismatch 没有分支匹配时的汇总错误，消息中列出每个分支的第一个失败
+func branchMismatch(path, keyword string, branches []ValidationErrors) *ValidationError {
+	reasons := make([]string, len(branches))
+	for i, failures := range branches {
+		reasons[i] = fmt.Sprintf("[%d] %s", i, failures[0].Error())
+		if len(failures) > 1 {
+			reasons[i] += fmt.Sprintf(" (and %d more)", len(failures)-1)
+		}
+	}
+	return &ValidationError{
+		Path:     path,
+		Message:  fmt.Sprintf("value does not match any schema in %s: %s", keyword, strings.Join(reasons, "; ")),
+		Branches: branches,
+	}
+}
+
+// validateObject 验证对象关键字
+func (s *Schema) validateObject(j *JSON, path string, errs *ValidationErrors) {
+	// 验证必需字段
+	for _, required := range s.Required {
+		if !j.Has(required) {
+			*errs = append(*errs, &ValidationError{
+				Path:    joinSchemaPath(path, required),
+				Message: fmt.Sprintf("missing required field '%s'", required),
+			})
+		}
+	}
+
+	for _, key := range j.Keys() {
+		schema, declared := s.Properties[key]
+		if !declared {
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				*errs = append(*errs, &ValidationError{
+					Path:    joinSchemaPath(path, key),
+					Message: fmt.Sprintf("additional property '%s' is not allowed", key),
+				})
+			}
+			continue
+		}
+		if schema != nil {
+			schema.validateValue(j.Get(key), joinSchemaPath(path, key), errs)
+		}
+	}
+}
+
+// validateArray 验证数组关键字
+func (s *Schema) validateArray(j *JSON, path string, errs *ValidationErrors) {
+	length := j.Length()
+	if s.MinItems != nil && length < *s.MinItems {
+		*errs = append(*errs, &ValidationError{Path: path, Message: fmt.Sprintf("array has %d items, minimum is %d", length, *s.MinItems)})
+	}
+	if s.MaxItems != nil && length > *s.MaxItems {
+		*errs = append(*errs, &ValidationError{Path: path, Message: fmt.Sprintf("array has %d items, maximum is %d", length, *s.MaxItems)})
+	}
+
+	arr, _ := j.data.([]interface{})
+	if s.UniqueItems {
+		comparer := &equalComparer{}
+	outer:
+		for i := 1; i < len(arr); i++ {
+			for k := 0; k < i; k++ {
+				if comparer.equal(arr[k], arr[i], nil) {
+					*errs = append(*errs, &ValidationError{
+						Path:    fmt.Sprintf("%s[%d]", path, i),
+						Message: fmt.Sprintf("duplicate of item %d", k),
+					})
+					continue outer
+				}
+			}
+		}
+	}
+
+	if s.Items != nil {
+		for i, item := range arr {
+			s.Items.validateValue(&JSON{data: item}, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+// validateString 验证字符串关键字
+func (s *Schema) validateString(str string, fail func(format string, args ...interface{})) {
+	if s.MinLength != nil && len(str) < *s.MinLength {
+		fail("string too short")
+	}
+	if s.MaxLength != nil && len(str) > *s.MaxLength {
+		fail("string too long")
+	}
+
+	if s.Pattern != "" {
+		re, err := compileSchemaPattern(s.Pattern)
+		if err != nil {
+			fail("invalid pattern %q: %v", s.Pattern, err)
+		} else if !re.MatchString(str) {
+			fail("string does not match pattern %q", s.Pattern)
+		}
+	}
+
+	if s.Format != "" && !matchesFormat(str, s.Format) {
+		fail("string is not a valid %s", s.Format)
+	}
+}
+
+// validateNumber 验证数值关键字
+func (s *Schema) validateNumber(num float64, fail func(format string, args ...interface{})) {
+	if s.Minimum != nil && num < *s.Minimum {
+		fail("number too small")
+	}
+	if s.Maximum != nil && num > *s.Maximum {
+		fail("number too large")
+	}
+	if s.MultipleOf != nil && *s.MultipleOf > 0 {
+		quotient := num / *s.MultipleOf
+		if math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			fail("number is not a multiple of %v", *s.MultipleOf)
+		}
+	}
+}
+
+// matchesEnum 判断值是否在枚举中
+func (s *Schema) matchesEnum(value interface{}) bool {
+	comparer := &equalComparer{}
+	for _, allowed := range s.Enum {
+		if comparer.equal(allowed, value, nil) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesSchemaType 判断值是否符合 Schema 类型，integer 要求数值没有小数部分
+func matchesSchemaType(j *JSON, schemaType string) bool {
+	switch schemaType {
+	case "integer":
+		if !j.IsNumber() {
+			return false
+		}
+		f := j.Float64()
+		return f == math.Trunc(f)
+	default:
+		return GetType(j) == schemaType
+	}
+}
+
+// uuidPattern UUID 格式（不区分版本）
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// matchesFormat 校验字符串格式，未知格式视为通过
+func matchesFormat(str, format string) bool {
+	switch format {
+	case "email":
+		addr, err := mail.ParseAddress(str)
+		return err == nil && addr.Address == str
+	case "uri":
+		u, err := url.Parse(str)
+		return err == nil && u.Scheme != "" && (u.Host != "" || u.Opaque != "")
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, str)
+		return err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", str)
+		return err == nil
+	case "uuid":
+		return uuidPattern.MatchString(str)
+	}
+	return true
+}
+
+// schemaPatterns 已编译的正则缓存
+var schemaPatterns sync.Map
+
+// compileSchemaPattern 编译并缓存正则表达式
+func compileSchemaPattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := schemaPatterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	schemaPatterns.Store(pattern, re)
+	return re, nil
+}
+
+// joinSchemaPath 拼接对象字段路径
+func joinSchemaPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// displayPath 用于错误信息的路径，根显示为 (root)
+func displayPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
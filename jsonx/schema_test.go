@@ -0,0 +1,251 @@
+package jsonx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const userSchemaJSON = `{
+	"type": "object",
+	"required": ["id", "email", "role"],
+	"additionalProperties": false,
+	"properties": {
+		"id":       {"type": "string", "format": "uuid"},
+		"email":    {"type": "string", "format": "email"},
+		"homepage": {"type": "string", "format": "uri"},
+		"created":  {"type": "string", "format": "date-time"},
+		"role":     {"type": "string", "enum": ["admin", "editor", "viewer"]},
+		"code":     {"type": "string", "pattern": "^[A-Z]{3}-\\d+$"},
+		"price":    {"type": "number", "minimum": 0, "multipleOf": 0.01},
+		"age":      {"type": "integer", "maximum": 150},
+		"tags":     {"type": "array", "minItems": 1, "maxItems": 3, "uniqueItems": true, "items": {"type": "string"}}
+	}
+}`
+
+func TestSchemaValidDocument(t *testing.T) {
+	schema, err := ParseSchema(userSchemaJSON)
+	if err != nil {
+		t.Fatalf("ParseSchema failed: %v", err)
+	}
+
+	doc := Parse(`{
+		"id": "3f0c9e2a-8d3b-4b8e-9a51-0c2d7e4f6a10",
+		"email": "tom@example.com",
+		"homepage": "https://example.com/tom",
+		"created": "2024-05-01T10:00:00Z",
+		"role": "editor",
+		"code": "ABC-123",
+		"price": 19.99,
+		"age": 30,
+		"tags": ["a", "b"]
+	}`)
+	if err := schema.Validate(doc); err != nil {
+		t.Errorf("valid document rejected:\n%v", err)
+	}
+}
+
+func TestSchemaCollectsAllViolations(t *testing.T) {
+	schema, err := ParseSchema(userSchemaJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc := Parse(`{
+		"id": "not-a-uuid",
+		"email": "not an email",
+		"homepage": "/relative",
+		"created": "yesterday",
+		"role": "owner",
+		"code": "abc-1",
+		"price": 1.005,
+		"age": 30.5,
+		"tags": ["a", "a", "b", "c"],
+		"extra": true
+	}`)
+
+	err = schema.Validate(doc)
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+
+	expected := map[string]string{
+		"age":      "expected integer",
+		"code":     "does not match pattern",
+		"created":  "not a valid date-time",
+		"email":    "not a valid email",
+		"extra":    "additional property 'extra' is not allowed",
+		"homepage": "not a valid uri",
+		"id":       "not a valid uuid",
+		"price":    "not a multiple of 0.01",
+		"role":     `must be one of ["admin","editor","viewer"]`,
+		"tags":     "array has 4 items, maximum is 3",
+		"tags[1]":  "duplicate of item 0",
+	}
+	if len(verrs) != len(expected) {
+		t.Errorf("got %d violations, want %d:\n%v", len(verrs), len(expected), err)
+	}
+	for _, v := range verrs {
+		want, ok := expected[v.Path]
+		if !ok || !strings.Contains(v.Message, want) {
+			t.Errorf("unexpected violation %s (want %q)", v, want)
+		}
+	}
+
+	var single *ValidationError
+	if !errors.As(err, &single) {
+		t.Error("errors.As should reach individual *ValidationError")
+	}
+}
+
+func TestSchemaRequiredAndNested(t *testing.T) {
+	schema, err := ParseSchema(`{
+		"type": "object",
+		"required": ["user"],
+		"properties": {
+			"user": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string", "minLength": 2}}},
+			"items": {"type": "array", "items": {"type": "number", "minimum": 1}}
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = schema.Validate(Parse(`{"user": {}, "items": [1, 0, "x"]}`))
+	if err == nil {
+		t.Fatal("expected violations")
+	}
+	msg := err.Error()
+	for _, want := range []string{
+		"user.name: missing required field 'name'",
+		"items[1]: number too small",
+		"items[2]: expected number, got string",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("missing %q in:\n%s", want, msg)
+		}
+	}
+
+	if err := schema.Validate(Parse(`[]`)); err == nil || !strings.Contains(err.Error(), "(root): expected object, got array") {
+		t.Errorf("unexpected root error: %v", err)
+	}
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	if _, err := ParseSchema(`{invalid`); err == nil {
+		t.Error("invalid JSON should fail")
+	}
+	if _, err := ParseSchema(`{"properties": {"a": {"pattern": "(["}}}`); err == nil || !strings.Contains(err.Error(), "invalid pattern at a") {
+		t.Errorf("invalid pattern should fail with path, got %v", err)
+	}
+}
+
+const webhookSchemaJSON = `{
+	"type": "object",
+	"required": ["event"],
+	"oneOf": [
+		{
+			"properties": {
+				"event": {"enum": ["order.paid"]},
+				"order": {"type": "object", "required": ["id", "amount"], "properties": {"amount": {"type": "number", "minimum": 0}}}
+			},
+			"required": ["order"]
+		},
+		{
+			"properties": {
+				"event": {"enum": ["user.deleted"]},
+				"userId": {"type": "string"}
+			},
+			"required": ["userId"]
+		}
+	],
+	"properties": {
+		"note": {"type": "string", "nullable": true}
+	}
+}`
+
+func TestSchemaOneOf(t *testing.T) {
+	schema, err := ParseSchema(webhookSchemaJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, valid := range []string{
+		`{"event": "order.paid", "order": {"id": "o1", "amount": 10}}`,
+		`{"event": "user.deleted", "userId": "u1", "note": null}`,
+	} {
+		if err := schema.ValidateString(valid); err != nil {
+			t.Errorf("%s rejected:\n%v", valid, err)
+		}
+	}
+
+	err = schema.ValidateString(`{"event": "order.paid", "order": {"id": "o1", "amount": -1}}`)
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) || len(verrs) != 1 {
+		t.Fatalf("expected one oneOf error, got %v", err)
+	}
+	mismatch := verrs[0]
+	if len(mismatch.Branches) != 2 {
+		t.Fatalf("expected failures for 2 branches, got %d", len(mismatch.Branches))
+	}
+	if got := mismatch.Branches[0][0].Error(); got != "order.amount: number too small" {
+		t.Errorf("branch 0 failure = %q", got)
+	}
+	if !strings.Contains(mismatch.Message, "oneOf") || !strings.Contains(mismatch.Message, "[1] userId: missing required field 'userId' (and 1 more)") {
+		t.Errorf("message should explain each branch, got %q", mismatch.Message)
+	}
+
+	ambiguous := &Schema{OneOf: []*Schema{{Type: "number"}, {Type: "integer"}}}
+	if err := ambiguous.ValidateString(`3`); err == nil || !strings.Contains(err.Error(), "matches 2 schemas in oneOf (branches [0 1])") {
+		t.Errorf("expected ambiguous oneOf error, got %v", err)
+	}
+	if err := ambiguous.ValidateString(`3.5`); err != nil {
+		t.Errorf("3.5 matches only number: %v", err)
+	}
+}
+
+func TestSchemaAnyOfAllOf(t *testing.T) {
+	minLen := 3
+	schema := &Schema{
+		AnyOf: []*Schema{{Type: "string", MinLength: &minLen}, {Type: "integer"}},
+	}
+	if err := schema.ValidateString(`"abcd"`); err != nil {
+		t.Errorf("string branch should match: %v", err)
+	}
+	if err := schema.ValidateString(`42`); err != nil {
+		t.Errorf("integer branch should match: %v", err)
+	}
+	err := schema.ValidateString(`"ab"`)
+	if err == nil || !strings.Contains(err.Error(), "anyOf: [0] (root): string too short; [1] (root): expected integer, got string") {
+		t.Errorf("unexpected anyOf error: %v", err)
+	}
+
+	max := 10.0
+	all := &Schema{AllOf: []*Schema{{Type: "number"}, {Maximum: &max}}}
+	if err := all.ValidateString(`5`); err != nil {
+		t.Errorf("allOf should pass: %v", err)
+	}
+	if err := all.ValidateString(`11`); err == nil || err.Error() != "(root): allOf[1]: number too large" {
+		t.Errorf("unexpected allOf error: %v", err)
+	}
+}
+
+func TestSchemaNullable(t *testing.T) {
+	schema, err := ParseSchema(`{"type": "object", "properties": {
+		"a": {"type": "string", "nullable": true},
+		"b": {"type": "string"}
+	}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := schema.ValidateString(`{"a": null, "b": "x"}`); err != nil {
+		t.Errorf("nullable field rejected: %v", err)
+	}
+	if err := schema.ValidateString(`{"a": "x", "b": null}`); err == nil || err.Error() != "b: expected string, got null" {
+		t.Errorf("unexpected error for non-nullable null: %v", err)
+	}
+	if err := schema.ValidateString(`{"a": `); err == nil {
+		t.Error("ValidateString should report parse errors")
+	}
+}
@@ -0,0 +1,91 @@
+package jsonx
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// 按值搜索路径
+
+// FindPaths 返回所有满足 fn 的节点路径（包括对象与数组本身），结果可直接传给 Get
+//
+// 遍历为深度优先的前序：父节点先于子节点，对象键按字典序，数组按下标；根节点匹配时路径为空字符串。
+// 键中含有 "." 的字段其路径无法被 Get 正确解析。与 ForEach 相同，回调收到的 v 仅在本次调用内有效。
+// 数据存在循环引用时返回 nil，并在 j 上记录 ErrCyclicData；嵌套过深时记录 ErrTooDeep
+func FindPaths(j *JSON, fn func(v *JSON) bool) []string {
+	if j == nil || j.err != nil {
+		return nil
+	}
+
+	s := &pathSearcher{fn: fn, elem: &JSON{}, base: j.path}
+	if err := s.walk(j.data, ""); err != nil {
+		j.err = err
+		return nil
+	}
+	return s.paths
+}
+
+// FindValue 返回所有与 target 相等的标量节点路径
+// 数字按数值比较（42 与 42.0 相等），字符串与布尔值精确比较，target 为 nil 时匹配 null
+func FindValue(j *JSON, target interface{}) []string {
+	targetNum, targetIsNum := toFloat64(target)
+	return FindPaths(j, func(v *JSON) bool {
+		if isContainer(v.data) {
+			return false
+		}
+		if targetIsNum {
+			n, ok := toFloat64(v.data)
+			return ok && n == targetNum
+		}
+		return reflect.DeepEqual(v.data, target)
+	})
+}
+
+// pathSearcher 路径搜索的遍历状态
+type pathSearcher struct {
+	fn    func(v *JSON) bool
+	elem  *JSON
+	base  string
+	guard cycleGuard
+	paths []string
+}
+
+// walk 前序遍历并记录匹配的路径
+func (s *pathSearcher) walk(v interface{}, path string) error {
+	*s.elem = JSON{data: v, path: joinJSONPath(s.base, path)}
+	if s.fn(s.elem) {
+		s.paths = append(s.paths, path)
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		ptr, err := s.guard.enter(val)
+		if err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if err := s.walk(val[k], joinJSONPath(path, k)); err != nil {
+				return err
+			}
+		}
+		s.guard.leave(ptr)
+	case []interface{}:
+		ptr, err := s.guard.enter(val)
+		if err != nil {
+			return err
+		}
+		for i, item := range val {
+			if err := s.walk(item, joinJSONPath(path, strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+		s.guard.leave(ptr)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package jsonx
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindValue(t *testing.T) {
+	webhook := Parse(`{
+		"event": "order.paid",
+		"data": {
+			"headers": [{"name":"X-Request-Id","value":"corr-123"}],
+			"meta": {"trace": "corr-123", "retries": 2},
+			"matrix": [[1, "corr-123"], [2.0]]
+		},
+		"correlation": "corr-123",
+		"flag": null
+	}`)
+
+	want := []string{"correlation", "data.headers.0.value", "data.matrix.0.1", "data.meta.trace"}
+	got := FindValue(webhook, "corr-123")
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("FindValue = %v, want %v", got, want)
+	}
+	for _, path := range got {
+		if v := webhook.Get(path).String(); v != "corr-123" {
+			t.Errorf("Get(%q) = %q", path, v)
+		}
+	}
+
+	if got := FindValue(webhook, 2); !reflect.DeepEqual(got, []string{"data.matrix.1.0", "data.meta.retries"}) {
+		t.Errorf("numeric FindValue = %v", got)
+	}
+	if got := FindValue(webhook, nil); !reflect.DeepEqual(got, []string{"flag"}) {
+		t.Errorf("null FindValue = %v", got)
+	}
+	if got := FindValue(webhook, "missing"); len(got) != 0 {
+		t.Errorf("FindValue for missing value = %v", got)
+	}
+}
+
+func TestFindPaths(t *testing.T) {
+	j := Parse(`{"users":[{"id":1,"tags":[]},{"id":2,"tags":["x"]}]}`)
+
+	arrays := FindPaths(j, func(v *JSON) bool { return v.IsArray() })
+	if !reflect.DeepEqual(arrays, []string{"users", "users.0.tags", "users.1.tags"}) {
+		t.Errorf("arrays = %v", arrays)
+	}
+
+	root := FindPaths(j, func(v *JSON) bool { return v.IsObject() && v.Has("users") })
+	if !reflect.DeepEqual(root, []string{""}) {
+		t.Errorf("root match = %v", root)
+	}
+
+	var seen []string
+	FindPaths(j.Get("users"), func(v *JSON) bool {
+		if v.IsNumber() {
+			_, err := v.TryString()
+			seen = append(seen, err.Error())
+		}
+		return false
+	})
+	if len(seen) != 2 || !strings.Contains(seen[0], "users.0.id") {
+		t.Errorf("callback value should carry the full path for errors, got %v", seen)
+	}
+
+	if got := FindPaths(Parse(`{bad`), func(v *JSON) bool { return true }); got != nil {
+		t.Errorf("document with error should return nil, got %v", got)
+	}
+	cyclic := FromMap(newCyclicMap())
+	if got := FindValue(cyclic, "root"); got != nil || !errors.Is(cyclic.Error(), ErrCyclicData) {
+		t.Errorf("cyclic document: got %d paths, error %v, want nil and ErrCyclicData", len(got), cyclic.Error())
+	}
+}
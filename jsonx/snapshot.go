@@ -0,0 +1,261 @@
+package jsonx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// 快照测试
+//
+// Snapshot 将 JSON 规范化后与 testdata/snapshots/<name>.json 比较：
+//
+//	jsonx.Snapshot(t, "orders/list", jsonx.Parse(body), jsonx.SnapshotOptions{
+//		Placeholders: map[string]string{"items.*.id": "<id>", "createdAt": "<time>"},
+//		SortArrays:   []string{"tags"},
+//		IgnorePaths:  []string{"debug"},
+//	})
+//
+// golden 文件不存在时写入并通过；设置环境变量 UPDATE_SNAPSHOTS=1 时覆盖已有文件。
+// 不一致时逐条列出结构化差异（DiffWithTolerance 的结果），而不是整段文本的差异。
+
+// SnapshotUpdateEnv 设置为非空（且不是 0 / false）时，Snapshot 覆盖已有的 golden 文件
+const SnapshotUpdateEnv = "UPDATE_SNAPSHOTS"
+
+// TestingTB Snapshot 使用的 testing.TB 子集，*testing.T 与 *testing.B 均满足
+type TestingTB interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// SnapshotOptions 快照规范化与比较选项
+//
+// 规范化按 IgnorePaths、Placeholders、SortArrays 的顺序进行，路径中的片段 "*" 匹配任意键或索引，
+// 不存在的路径会被忽略。排序在替换占位符之后进行，生成的 ID 等易变值不会影响数组顺序。
+type SnapshotOptions struct {
+	// Placeholders 将路径上的值替换为占位字符串，如 {"items.*.createdAt": "<time>"}
+	Placeholders map[string]string
+	// SortArrays 需要排序的数组路径，标量按 SortBy 的规则排序，对象与数组按其规范化 JSON 文本排序
+	SortArrays []string
+	// IgnorePaths 从快照中删除的路径
+	IgnorePaths []string
+	// Tolerance 数值比较的绝对容差，0 表示精确比较
+	Tolerance float64
+	// Dir golden 文件目录，默认为 testdata/snapshots
+	Dir string
+}
+
+// Snapshot 将规范化后的 JSON 与 golden 文件比较，不一致或出错时通过 t.Errorf 报告
+// name 可以包含 "/" 以分目录存放，不能为绝对路径或包含 ".."
+func Snapshot(t TestingTB, name string, j *JSON, opts SnapshotOptions) {
+	t.Helper()
+
+	path, err := snapshotPath(name, opts.Dir)
+	if err != nil {
+		t.Errorf("snapshot %q: %v", name, err)
+		return
+	}
+	if j == nil {
+		t.Errorf("snapshot %q: nil JSON", name)
+		return
+	}
+	if j.err != nil {
+		t.Errorf("snapshot %q: %v", name, j.err)
+		return
+	}
+	actual, err := normalizeSnapshot(j, opts)
+	if err != nil {
+		t.Errorf("snapshot %q: %v", name, err)
+		return
+	}
+
+	golden, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && snapshotUpdateRequested()) {
+		if err := writeSnapshot(path, actual); err != nil {
+			t.Errorf("snapshot %q: %v", name, err)
+		}
+		return
+	}
+	if err != nil {
+		t.Errorf("snapshot %q: %v", name, err)
+		return
+	}
+
+	expected := ParseBytes(golden)
+	if expected.err != nil {
+		t.Errorf("snapshot %q: golden file %s: %v", name, path, expected.err)
+		return
+	}
+	var lines []string
+	for _, d := range DiffWithTolerance(expected, actual, opts.Tolerance) {
+		if !d.NearMiss {
+			lines = append(lines, formatSnapshotDifference(d))
+		}
+	}
+	if len(lines) > 0 {
+		t.Errorf("snapshot %q does not match %s (set %s=1 to update):\n%s",
+			name, filepath.ToSlash(path), SnapshotUpdateEnv, strings.Join(lines, "\n"))
+	}
+}
+
+// snapshotPath 返回 golden 文件路径
+func snapshotPath(name, dir string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("empty snapshot name")
+	}
+	clean := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("snapshot name must be a relative path inside the snapshot directory")
+	}
+	if dir == "" {
+		dir = filepath.Join("testdata", "snapshots")
+	}
+	return filepath.Join(dir, clean+".json"), nil
+}
+
+// snapshotUpdateRequested 判断是否设置了更新 golden 文件的环境变量
+func snapshotUpdateRequested() bool {
+	switch strings.ToLower(os.Getenv(SnapshotUpdateEnv)) {
+	case "", "0", "false":
+		return false
+	default:
+		return true
+	}
+}
+
+// normalizeSnapshot 复制文档并按选项规范化
+func normalizeSnapshot(j *JSON, opts SnapshotOptions) (*JSON, error) {
+	out := j.Clone()
+	if out.err != nil {
+		return nil, out.err
+	}
+
+	for _, pattern := range opts.IgnorePaths {
+		for _, path := range expandWildcardPath(out.data, pattern) {
+			if _, err := out.getByPath(path); err == nil {
+				out.deleteByPath(path)
+			}
+		}
+	}
+
+	patterns := make([]string, 0, len(opts.Placeholders))
+	for pattern := range opts.Placeholders {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		for _, path := range expandWildcardPath(out.data, pattern) {
+			if _, err := out.getByPath(path); err != nil {
+				continue
+			}
+			if err := out.setByPath(path, opts.Placeholders[pattern]); err != nil {
+				return nil, fmt.Errorf("placeholder %s: %w", path, err)
+			}
+		}
+	}
+
+	for _, pattern := range opts.SortArrays {
+		for _, path := range expandWildcardPath(out.data, pattern) {
+			value, err := out.getByPath(path)
+			if err != nil {
+				continue
+			}
+			arr, ok := value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("sort %s: %w", path, (&JSON{data: value, path: path}).typeError("array"))
+			}
+			if err := sortSnapshotArray(arr); err != nil {
+				return nil, fmt.Errorf("sort %s: %w", path, err)
+			}
+		}
+	}
+	return out, nil
+}
+
+// sortSnapshotArray 原地稳定排序，类型不同时按 SortBy 的类型顺序，容器按规范化 JSON 文本比较
+func sortSnapshotArray(arr []interface{}) error {
+	keys := make([]string, len(arr))
+	for i, item := range arr {
+		switch item.(type) {
+		case map[string]interface{}, []interface{}:
+			data, err := marshalJSON(item, false)
+			if err != nil {
+				return err
+			}
+			keys[i] = string(data)
+		}
+	}
+
+	indexes := make([]int, len(arr))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(a, b int) bool {
+		x, y := indexes[a], indexes[b]
+		if c := compareJSONValues(arr[x], arr[y]); c != 0 {
+			return c < 0
+		}
+		return keys[x] < keys[y]
+	})
+
+	sorted := make([]interface{}, len(arr))
+	for i, idx := range indexes {
+		sorted[i] = arr[idx]
+	}
+	copy(arr, sorted)
+	return nil
+}
+
+// writeSnapshot 以缩进格式写入 golden 文件，对象键按字典序排列
+func writeSnapshot(path string, j *JSON) error {
+	data, err := encodeSnapshot(j.data, "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// encodeSnapshot 编码快照中的值，不转义 <、> 与 &，占位符保持可读
+func encodeSnapshot(v interface{}, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", indent)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// formatSnapshotDifference 将差异格式化为一行，- 为仅在 golden 中，+ 为仅在实际结果中，~ 为值不同
+func formatSnapshotDifference(d Difference) string {
+	path := d.Path
+	if path == "" {
+		path = "(root)"
+	}
+	switch d.Kind {
+	case DifferenceAdded:
+		return fmt.Sprintf("  + %s: %s", path, snapshotValue(d.B))
+	case DifferenceRemoved:
+		return fmt.Sprintf("  - %s: %s", path, snapshotValue(d.A))
+	default:
+		return fmt.Sprintf("  ~ %s: %s -> %s", path, snapshotValue(d.A), snapshotValue(d.B))
+	}
+}
+
+// snapshotValue 以紧凑 JSON 显示差异中的值
+func snapshotValue(v interface{}) string {
+	data, err := encodeSnapshot(v, "")
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,144 @@
+package jsonx
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// recordingTB 记录 Errorf 的输出
+type recordingTB struct {
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// orderResponse 模拟每次请求都会变化的 API 响应
+func orderResponse(id, createdAt string, total float64, tags ...string) *JSON {
+	tagValues := make([]interface{}, len(tags))
+	for i, tag := range tags {
+		tagValues[i] = tag
+	}
+	return New(map[string]interface{}{
+		"id":        id,
+		"createdAt": createdAt,
+		"total":     total,
+		"tags":      tagValues,
+		"items": []interface{}{
+			map[string]interface{}{"id": id + "-1", "sku": "A-1", "qty": 2},
+			map[string]interface{}{"id": id + "-2", "sku": "B-7", "qty": 1},
+		},
+		"debug": map[string]interface{}{"host": "worker-3"},
+	})
+}
+
+var orderSnapshotOptions = SnapshotOptions{
+	Placeholders: map[string]string{"id": "<id>", "createdAt": "<time>", "items.*.id": "<id>"},
+	SortArrays:   []string{"tags"},
+	IgnorePaths:  []string{"debug"},
+	Tolerance:    0.001,
+}
+
+func TestSnapshotGolden(t *testing.T) {
+	// testdata/snapshots/order.json 已提交，易变字段经规范化后与之一致
+	Snapshot(t, "order", orderResponse("ord_9f2c", "2024-05-01T10:00:00Z", 59.9, "vip", "gift"), orderSnapshotOptions)
+	Snapshot(t, "order", orderResponse("ord_11aa", "2024-06-12T08:30:00Z", 59.9004, "gift", "vip"), orderSnapshotOptions)
+}
+
+func TestSnapshotCreateAndCompare(t *testing.T) {
+	t.Setenv(SnapshotUpdateEnv, "")
+	opts := orderSnapshotOptions
+	opts.Dir = t.TempDir()
+	path := filepath.Join(opts.Dir, "api", "order.json")
+
+	// 首次运行写入 golden 文件
+	tb := &recordingTB{}
+	Snapshot(tb, "api/order", orderResponse("a", "t1", 10, "b", "a"), opts)
+	if len(tb.errors) != 0 {
+		t.Fatalf("first run errors: %v", tb.errors)
+	}
+	golden, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"id": "<id>"`, `"createdAt": "<time>"`, "\"tags\": [\n    \"a\",\n    \"b\"\n  ]"} {
+		if !strings.Contains(string(golden), want) {
+			t.Errorf("golden file missing %s:\n%s", want, golden)
+		}
+	}
+	if strings.Contains(string(golden), "debug") || !strings.HasSuffix(string(golden), "}\n") {
+		t.Errorf("golden file:\n%s", golden)
+	}
+
+	// 规范化后相同即通过
+	Snapshot(tb, "api/order", orderResponse("b", "t2", 10.0005, "a", "b"), opts)
+	if len(tb.errors) != 0 {
+		t.Errorf("matching run errors: %v", tb.errors)
+	}
+
+	// 不一致时输出结构化差异
+	changed := orderResponse("c", "t3", 12, "a", "c")
+	changed.Set("items.1.qty", 3).Set("currency", "<EUR>").Delete("items.0.sku")
+	Snapshot(tb, "api/order", changed, opts)
+	want := `snapshot "api/order" does not match ` + filepath.ToSlash(path) + ` (set UPDATE_SNAPSHOTS=1 to update):
+  + currency: "<EUR>"
+  - items.0.sku: "A-1"
+  ~ items.1.qty: 1 -> 3
+  ~ tags.1: "b" -> "c"
+  ~ total: 10 -> 12`
+	if len(tb.errors) != 1 || tb.errors[0] != want {
+		t.Errorf("diff output:\n%s\nwant:\n%s", strings.Join(tb.errors, "\n"), want)
+	}
+
+	// 设置环境变量后覆盖 golden 文件
+	t.Setenv(SnapshotUpdateEnv, "1")
+	tb = &recordingTB{}
+	Snapshot(tb, "api/order", changed, opts)
+	t.Setenv(SnapshotUpdateEnv, "")
+	Snapshot(tb, "api/order", changed, opts)
+	if len(tb.errors) != 0 {
+		t.Errorf("after update errors: %v", tb.errors)
+	}
+}
+
+func TestSnapshotNormalization(t *testing.T) {
+	doc := Parse(`{"rows":[{"k":2,"v":"x"},{"k":1,"v":"y"},{"k":1,"v":"a"}],"mixed":["b",2,null,true,"a",1],"meta":{"at":"now"},"empty":[]}`)
+	out, err := normalizeSnapshot(doc, SnapshotOptions{
+		SortArrays:   []string{"rows", "mixed", "empty", "missing"},
+		Placeholders: map[string]string{"rows.*.v": "V", "missing.path": "<x>"},
+		IgnorePaths:  []string{"meta.at", "nothing.here"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"empty":[],"meta":{},"mixed":[null,true,1,2,"a","b"],"rows":[{"k":1,"v":"V"},{"k":1,"v":"V"},{"k":2,"v":"V"}]}`
+	if got, _ := out.ToJSON(); got != want {
+		t.Errorf("normalized = %s\nwant %s", got, want)
+	}
+	if doc.Get("meta.at").String() != "now" {
+		t.Error("normalization must not modify the input")
+	}
+
+	if _, err := normalizeSnapshot(doc, SnapshotOptions{SortArrays: []string{"meta"}}); err == nil || !strings.Contains(err.Error(), "sort meta") {
+		t.Errorf("sorting an object err = %v", err)
+	}
+
+	tb := &recordingTB{}
+	dir := t.TempDir()
+	Snapshot(tb, "../escape", doc, SnapshotOptions{Dir: dir})
+	Snapshot(tb, "", doc, SnapshotOptions{Dir: dir})
+	Snapshot(tb, "broken", Parse(`{`), SnapshotOptions{Dir: dir})
+	Snapshot(tb, "nil", nil, SnapshotOptions{Dir: dir})
+	if len(tb.errors) != 4 {
+		t.Errorf("errors = %v", tb.errors)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("invalid snapshots wrote files: %v", entries)
+	}
+}
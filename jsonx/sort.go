@@ -0,0 +1,150 @@
+package jsonx
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// 排序方法
+
+// SortBy 按元素内指定路径的值对数组排序，返回排序后的新数组（稳定排序）
+//
+// path 为空时按元素本身排序，路径不存在的元素视为 null。
+// 不同类型之间的顺序为：null < boolean < number < string < object/array，
+// object 与 array 之间不做比较，保持原有相对顺序。
+func (j *JSON) SortBy(path string, desc bool) *JSON {
+	return j.SortFunc(func(a, b *JSON) bool {
+		av, _ := a.getByPath(path)
+		bv, _ := b.getByPath(path)
+		if desc {
+			return compareJSONValues(bv, av) < 0
+		}
+		return compareJSONValues(av, bv) < 0
+	})
+}
+
+// SortFunc 使用自定义比较函数对数组排序，返回排序后的新数组（稳定排序）
+func (j *JSON) SortFunc(less func(a, b *JSON) bool) *JSON {
+	if j.err != nil {
+		return j
+	}
+
+	arr, ok := j.data.([]interface{})
+	if !ok {
+		return &JSON{data: j.data, err: fmt.Errorf("not an array")}
+	}
+
+	sorted := make([]interface{}, len(arr))
+	copy(sorted, arr)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		return less(&JSON{data: sorted[a]}, &JSON{data: sorted[b]})
+	})
+
+	return &JSON{data: sorted}
+}
+
+// compareJSONValues 比较两个 JSON 值，返回 -1、0 或 1
+func compareJSONValues(a, b interface{}) int {
+	ra, rb := typeRank(a), typeRank(b)
+	if ra != rb {
+		if ra < rb {
+			return -1
+		}
+		return 1
+	}
+
+	switch av := a.(type) {
+	case bool:
+		bv := b.(bool)
+		if av == bv {
+			return 0
+		}
+		if !av {
+			return -1
+		}
+		return 1
+	case string:
+		bv := b.(string)
+		if av < bv {
+			return -1
+		}
+		if av > bv {
+			return 1
+		}
+		return 0
+	}
+
+	if ra == rankNumber {
+		af, _ := toFloat64(a)
+		bf, _ := toFloat64(b)
+		if af < bf {
+			return -1
+		}
+		if af > bf {
+			return 1
+		}
+	}
+	return 0
+}
+
+// 类型排序等级
+const (
+	rankNull = iota
+	rankBool
+	rankNumber
+	rankString
+	rankContainer
+)
+
+// typeRank 获取值的类型排序等级
+func typeRank(v interface{}) int {
+	switch v.(type) {
+	case nil:
+		return rankNull
+	case bool:
+		return rankBool
+	case string:
+		return rankString
+	case map[string]interface{}, []interface{}:
+		return rankContainer
+	}
+	if _, ok := toFloat64(v); ok {
+		return rankNumber
+	}
+	return rankContainer
+}
+
+// toFloat64 将任意数字类型转换为 float64
+func toFloat64(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,64 @@
+package jsonx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortBy(t *testing.T) {
+	users := Parse(`[
+		{"name": "a", "profile": {"age": 30}},
+		{"name": "b", "profile": {"age": 25}},
+		{"name": "c"},
+		{"name": "d", "profile": {"age": 30}}
+	]`)
+
+	names := func(j *JSON) []string {
+		result := make([]string, 0)
+		j.ForEach(func(key string, value *JSON) bool {
+			result = append(result, value.Get("name").String())
+			return true
+		})
+		return result
+	}
+
+	asc := users.SortBy("profile.age", false)
+	if got := names(asc); !reflect.DeepEqual(got, []string{"c", "b", "a", "d"}) {
+		t.Errorf("unexpected ascending order: %v", got)
+	}
+
+	desc := users.SortBy("profile.age", true)
+	if got := names(desc); !reflect.DeepEqual(got, []string{"a", "d", "b", "c"}) {
+		t.Errorf("unexpected descending order: %v", got)
+	}
+
+	// 原数组不受影响
+	if got := names(users); !reflect.DeepEqual(got, []string{"a", "b", "c", "d"}) {
+		t.Errorf("original array should not change: %v", got)
+	}
+}
+
+func TestSortMixedTypes(t *testing.T) {
+	arr := Parse(`["b", 2, null, {"k": 1}, true, 1.5, "a", false, [1]]`)
+	sorted := arr.SortBy("", false)
+
+	expected := []interface{}{nil, false, true, 1.5, float64(2), "a", "b", map[string]interface{}{"k": float64(1)}, []interface{}{float64(1)}}
+	if !reflect.DeepEqual(sorted.ToInterface(), expected) {
+		t.Errorf("unexpected mixed order: %v", sorted.ToInterface())
+	}
+}
+
+func TestSortFunc(t *testing.T) {
+	arr := QuickArray("ccc", "a", "bb")
+	sorted := arr.SortFunc(func(a, b *JSON) bool {
+		return a.Length() < b.Length()
+	})
+
+	if !reflect.DeepEqual(sorted.ToInterface(), []interface{}{"a", "bb", "ccc"}) {
+		t.Errorf("unexpected order: %v", sorted.ToInterface())
+	}
+
+	if result := Object().SortBy("x", false); result.Error() == nil {
+		t.Error("sorting a non-array should set an error")
+	}
+}
@@ -0,0 +1,103 @@
+package jsonx
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// DocStats 文档统计信息，由 Stats 一次遍历得到
+type DocStats struct {
+	Objects        int    `json:"objects"`
+	Arrays         int    `json:"arrays"`
+	Strings        int    `json:"strings"`
+	Numbers        int    `json:"numbers"`
+	Booleans       int    `json:"booleans"`
+	Nulls          int    `json:"nulls"`
+	Leaves         int    `json:"leaves"`         // 标量节点总数，空对象与空数组不计入
+	MaxDepth       int    `json:"maxDepth"`       // 与 Depth 一致，标量根为 0
+	MaxArrayLength int    `json:"maxArrayLength"` // 最长数组的元素个数
+	LongestKey     string `json:"longestKey"`     // 字节数最多的键，长度相同时取字典序较小者
+}
+
+// Nodes 节点总数（对象、数组与标量）
+func (s DocStats) Nodes() int {
+	return s.Objects + s.Arrays + s.Leaves
+}
+
+// String 输出一行紧凑的摘要，便于日志记录
+func (s DocStats) String() string {
+	return fmt.Sprintf("nodes=%d objects=%d arrays=%d strings=%d numbers=%d booleans=%d nulls=%d leaves=%d depth=%d maxArray=%d longestKey=%s",
+		s.Nodes(), s.Objects, s.Arrays, s.Strings, s.Numbers, s.Booleans, s.Nulls, s.Leaves,
+		s.MaxDepth, s.MaxArrayLength, strconv.Quote(s.LongestKey))
+}
+
+// Stats 统计文档中各类节点的数量，可在入库前检查负载规模（如拒绝超过 1 万个节点的文档）
+// 数据存在循环引用时返回零值，并在 j 上记录 ErrCyclicData；嵌套过深时记录 ErrTooDeep
+func Stats(j *JSON) DocStats {
+	var stats DocStats
+	if j.err != nil {
+		return stats
+	}
+	if err := collectStats(j.data, 0, &stats, &cycleGuard{}); err != nil {
+		j.err = err
+		return DocStats{}
+	}
+	return stats
+}
+
+// collectStats 递归累计统计信息
+func collectStats(data interface{}, depth int, stats *DocStats, guard *cycleGuard) error {
+	if depth > stats.MaxDepth {
+		stats.MaxDepth = depth
+	}
+
+	switch v := data.(type) {
+	case map[string]interface{}:
+		stats.Objects++
+		ptr, err := guard.enter(v)
+		if err != nil {
+			return err
+		}
+		for key, val := range v {
+			if len(key) > len(stats.LongestKey) || (len(key) == len(stats.LongestKey) && key < stats.LongestKey) {
+				stats.LongestKey = key
+			}
+			if err := collectStats(val, depth+1, stats, guard); err != nil {
+				return err
+			}
+		}
+		guard.leave(ptr)
+		return nil
+	case []interface{}:
+		stats.Arrays++
+		if len(v) > stats.MaxArrayLength {
+			stats.MaxArrayLength = len(v)
+		}
+		ptr, err := guard.enter(v)
+		if err != nil {
+			return err
+		}
+		for _, val := range v {
+			if err := collectStats(val, depth+1, stats, guard); err != nil {
+				return err
+			}
+		}
+		guard.leave(ptr)
+		return nil
+	}
+
+	stats.Leaves++
+	switch data.(type) {
+	case nil:
+		stats.Nulls++
+	case string:
+		stats.Strings++
+	case bool:
+		stats.Booleans++
+	default:
+		if _, ok := toFloat64(data); ok {
+			stats.Numbers++
+		}
+	}
+	return nil
+}
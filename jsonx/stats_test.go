@@ -0,0 +1,63 @@
+package jsonx
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	j := Parse(`{
+		"id": 1,
+		"name": "order",
+		"paid": true,
+		"note": null,
+		"items": [{"sku":"a","qty":2},{"sku":"b","qty":1},{"description":"gift"}],
+		"tags": [],
+		"meta": {}
+	}`)
+
+	got := Stats(j)
+	want := DocStats{
+		Objects:        5,
+		Arrays:         2,
+		Strings:        4,
+		Numbers:        3,
+		Booleans:       1,
+		Nulls:          1,
+		Leaves:         9,
+		MaxDepth:       3,
+		MaxArrayLength: 3,
+		LongestKey:     "description",
+	}
+	if got != want {
+		t.Fatalf("Stats =\n%+v\nwant\n%+v", got, want)
+	}
+	if got.Nodes() != 16 {
+		t.Errorf("Nodes = %d, want 16", got.Nodes())
+	}
+	if d := Depth(j); d != got.MaxDepth {
+		t.Errorf("MaxDepth %d should match Depth %d", got.MaxDepth, d)
+	}
+
+	line := `nodes=16 objects=5 arrays=2 strings=4 numbers=3 booleans=1 nulls=1 leaves=9 depth=3 maxArray=3 longestKey="description"`
+	if got.String() != line {
+		t.Errorf("String() = %s", got.String())
+	}
+}
+
+func TestStatsEdgeCases(t *testing.T) {
+	if s := Stats(Parse(`"x"`)); s.Strings != 1 || s.Leaves != 1 || s.Nodes() != 1 || s.MaxDepth != 0 {
+		t.Errorf("scalar root stats = %+v", s)
+	}
+	if s := Stats(Parse(`{"ab":1,"ba":2,"a":3}`)); s.LongestKey != "ab" {
+		t.Errorf("ties should pick the smallest key, got %q", s.LongestKey)
+	}
+	if s := Stats(ParseUseNumber(`[1, 2.5]`)); s.Numbers != 2 {
+		t.Errorf("json.Number should count as numbers, got %+v", s)
+	}
+
+	cyclic := FromMap(newCyclicMap())
+	if s := Stats(cyclic); s != (DocStats{}) || !errors.Is(cyclic.Error(), ErrCyclicData) {
+		t.Errorf("cyclic Stats = %+v, err = %v", s, cyclic.Error())
+	}
+}
@@ -0,0 +1,98 @@
+package jsonx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// syntaxExcerptRadius 错误摘录在出错位置前后保留的字符数
+const syntaxExcerptRadius = 20
+
+// SyntaxError 带有行列信息的 JSON 语法错误
+type SyntaxError struct {
+	Msg     string // encoding/json 给出的错误描述
+	Offset  int64  // 出错位置的字节偏移（从 0 开始）
+	Line    int    // 出错位置所在行（从 1 开始）
+	Column  int    // 出错位置所在列（从 1 开始，按字符计）
+	Excerpt string // 出错位置所在行的片段
+
+	err *json.SyntaxError
+}
+
+// Error 实现 error 接口
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at line %d, column %d: %s (near %q)", e.Line, e.Column, e.Msg, e.Excerpt)
+}
+
+// Unwrap 返回原始的 *json.SyntaxError
+func (e *SyntaxError) Unwrap() error {
+	return e.err
+}
+
+// Validate 检查 JSON 字符串是否有效，无效时返回 *SyntaxError
+func Validate(jsonStr string) error {
+	return Parse(jsonStr).err
+}
+
+// wrapSyntaxError 将 encoding/json 的语法错误转换为 *SyntaxError，其他错误原样返回
+func wrapSyntaxError(data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return err
+	}
+
+	// Offset 表示出错前已读取的字节数，出错字符位于 Offset-1；输入提前结束时错误位于末尾
+	offset := syntaxErr.Offset
+	if offset > int64(len(data)) || (offset == int64(len(data)) && isUnexpectedEnd(syntaxErr)) {
+		offset = int64(len(data))
+	} else if offset > 0 {
+		offset--
+	}
+
+	line, lineStart := 1, 0
+	for i := 0; i < int(offset); i++ {
+		if data[i] == '\n' {
+			line++
+			lineStart = i + 1
+		}
+	}
+	lineEnd := lineStart
+	for lineEnd < len(data) && data[lineEnd] != '\n' && data[lineEnd] != '\r' {
+		lineEnd++
+	}
+
+	return &SyntaxError{
+		Msg:     syntaxErr.Error(),
+		Offset:  offset,
+		Line:    line,
+		Column:  utf8.RuneCount(data[lineStart:offset]) + 1,
+		Excerpt: syntaxExcerpt(data[lineStart:lineEnd], int(offset)-lineStart),
+		err:     syntaxErr,
+	}
+}
+
+// isUnexpectedEnd 判断是否为输入提前结束的错误
+func isUnexpectedEnd(err *json.SyntaxError) bool {
+	return err.Error() == "unexpected end of JSON input"
+}
+
+// syntaxExcerpt 截取行内出错位置前后的片段，不截断多字节字符
+func syntaxExcerpt(line []byte, pos int) string {
+	if pos > len(line) {
+		pos = len(line)
+	}
+
+	start := pos
+	for n := 0; n < syntaxExcerptRadius && start > 0; n++ {
+		_, size := utf8.DecodeLastRune(line[:start])
+		start -= size
+	}
+	end := pos
+	for n := 0; n <= syntaxExcerptRadius && end < len(line); n++ {
+		_, size := utf8.DecodeRune(line[end:])
+		end += size
+	}
+	return string(line[start:end])
+}
@@ -0,0 +1,109 @@
+package jsonx
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSyntaxErrorPosition(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		line    int
+		column  int
+		excerpt string
+	}{
+		{
+			name:    "trailing comma in array",
+			input:   `{"a": [1,2,}`,
+			line:    1,
+			column:  12,
+			excerpt: `{"a": [1,2,}`,
+		},
+		{
+			name: "missing comma in pretty JSON",
+			input: `{
+  "user": {
+    "name": "张三",
+    "age": 30
+    "email": "a@b.c"
+  }
+}`,
+			line:    5,
+			column:  5,
+			excerpt: `    "email": "a@b.c"`,
+		},
+		{
+			name: "bad literal after multibyte text",
+			input: `{
+  "标题": "值", "flag": tru
+}`,
+			line:    2,
+			column:  25, // 字面量之后的换行
+			excerpt: `"flag": tru`,
+		},
+		{
+			name:    "unexpected end",
+			input:   "{\n  \"a\": [1,\n",
+			line:    3,
+			column:  1,
+			excerpt: "",
+		},
+	}
+
+	for _, tt := range tests {
+		err := Parse(tt.input).Error()
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("%s: expected *SyntaxError, got %T %v", tt.name, err, err)
+			continue
+		}
+		if syntaxErr.Line != tt.line || syntaxErr.Column != tt.column {
+			t.Errorf("%s: position = %d:%d, want %d:%d", tt.name, syntaxErr.Line, syntaxErr.Column, tt.line, tt.column)
+		}
+		if !strings.Contains(syntaxErr.Excerpt, strings.TrimSpace(tt.excerpt)) {
+			t.Errorf("%s: excerpt = %q, want to contain %q", tt.name, syntaxErr.Excerpt, tt.excerpt)
+		}
+
+		// 仍可获取原始错误
+		var jsonErr *json.SyntaxError
+		if !errors.As(err, &jsonErr) {
+			t.Errorf("%s: should unwrap to *json.SyntaxError", tt.name)
+		}
+	}
+}
+
+func TestSyntaxErrorExcerptLimit(t *testing.T) {
+	input := `{"long": "` + strings.Repeat("x", 100) + `", oops}`
+	var syntaxErr *SyntaxError
+	if !errors.As(Validate(input), &syntaxErr) {
+		t.Fatal("expected *SyntaxError")
+	}
+	if len(syntaxErr.Excerpt) > 2*syntaxExcerptRadius+1 {
+		t.Errorf("excerpt too long: %q", syntaxErr.Excerpt)
+	}
+	if !strings.Contains(syntaxErr.Excerpt, "oops") {
+		t.Errorf("excerpt should contain offending token: %q", syntaxErr.Excerpt)
+	}
+	if !strings.Contains(syntaxErr.Error(), "line 1, column 114") {
+		t.Errorf("unexpected message: %v", syntaxErr)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate(`{"ok": true}`); err != nil {
+		t.Errorf("valid JSON: %v", err)
+	}
+	if err := Validate(`{"ok": }`); err == nil {
+		t.Error("expected error")
+	}
+
+	// JSONC 去除注释时保留位置，行列仍指向原文
+	err := ParseLenient("{\n  // 注释\n  \"a\": 1\n  \"b\": 2\n}").Error()
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) || syntaxErr.Line != 4 {
+		t.Errorf("ParseLenient error = %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package jsonx
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 数字时间戳按数量级区分单位：小于 1e11 视为秒（可表示到 5138 年），
+// 依次类推为毫秒、微秒与纳秒
+const (
+	unixMillisThreshold = 1e11
+	unixMicrosThreshold = 1e14
+	unixNanosThreshold  = 1e17
+)
+
+// Time 将当前值解析为时间，适用于不同 API 返回的多种时间表示
+//
+// 字符串依次尝试 layouts 与 RFC3339（可带小数秒），纯数字字符串按时间戳处理；
+// 数字按数量级识别为 Unix 秒、毫秒、微秒或纳秒，秒可以带小数部分。时间戳返回 UTC 时间。
+// 路径不存在时返回 Get 的错误，值不是字符串或数字时返回 *TypeError。
+func (j *JSON) Time(layouts ...string) (time.Time, error) {
+	if j.err != nil {
+		return time.Time{}, j.err
+	}
+
+	if f, ok := toFloat64(j.data); ok {
+		return j.unixTime(f)
+	}
+	s, ok := j.data.(string)
+	if !ok {
+		return time.Time{}, j.typeError("time")
+	}
+
+	s = strings.TrimSpace(s)
+	candidates := append(append([]string{}, layouts...), time.RFC3339)
+	for _, layout := range candidates {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return j.unixTime(f)
+	}
+	return time.Time{}, fmt.Errorf("value at %s is not a valid time: %q matches none of %s",
+		displayPath(j.path), s, strings.Join(candidates, ", "))
+}
+
+// TimeOr 解析时间，失败时返回 def
+func (j *JSON) TimeOr(def time.Time, layouts ...string) time.Time {
+	t, err := j.Time(layouts...)
+	if err != nil {
+		return def
+	}
+	return t
+}
+
+// unixTime 按数量级将数字时间戳转换为时间
+func (j *JSON) unixTime(f float64) (time.Time, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) || f >= math.MaxInt64 || f <= math.MinInt64 {
+		return time.Time{}, fmt.Errorf("value at %s is not a valid timestamp: %v", displayPath(j.path), j.data)
+	}
+
+	// 整数部分按单位精确转换，小数部分再折算为纳秒，避免大时间戳的浮点误差
+	whole, frac := math.Modf(f)
+	n := int64(whole)
+	var t time.Time
+	var unit time.Duration
+	switch abs := math.Abs(f); {
+	case abs < unixMillisThreshold:
+		t, unit = time.Unix(n, 0), time.Second
+	case abs < unixMicrosThreshold:
+		t, unit = time.UnixMilli(n), time.Millisecond
+	case abs < unixNanosThreshold:
+		t, unit = time.UnixMicro(n), time.Microsecond
+	default:
+		t, unit = time.Unix(0, n), time.Nanosecond
+	}
+	return t.Add(time.Duration(math.Round(frac * float64(unit)))).UTC(), nil
+}
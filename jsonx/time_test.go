@@ -0,0 +1,70 @@
+package jsonx
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTime(t *testing.T) {
+	want := time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC)
+	j := Parse(`{
+		"rfc3339": "2023-11-14T22:13:20Z",
+		"nano": "2023-11-14T22:13:20.123456789+08:00",
+		"date": "2023-11-14",
+		"seconds": 1700000000,
+		"fraction": 1700000000.5,
+		"millis": 1700000000123,
+		"micros": 1700000000123456,
+		"negative": -86400,
+		"numeric": "1700000000",
+		"bad": "yesterday",
+		"flag": true
+	}`)
+
+	cases := []struct {
+		path    string
+		layouts []string
+		want    time.Time
+	}{
+		{"rfc3339", nil, want},
+		{"nano", nil, time.Date(2023, 11, 14, 22, 13, 20, 123456789, time.FixedZone("", 8*3600))},
+		{"date", []string{"2006-01-02"}, time.Date(2023, 11, 14, 0, 0, 0, 0, time.UTC)},
+		{"seconds", nil, want},
+		{"fraction", nil, want.Add(500 * time.Millisecond)},
+		{"millis", nil, want.Add(123 * time.Millisecond)},
+		{"micros", nil, want.Add(123456 * time.Microsecond)},
+		{"negative", nil, time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{"numeric", nil, want},
+	}
+	for _, tc := range cases {
+		got, err := j.Get(tc.path).Time(tc.layouts...)
+		if err != nil || !got.Equal(tc.want) {
+			t.Errorf("Time(%s) = %v, %v, want %v", tc.path, got, err, tc.want)
+		}
+	}
+
+	if got, _ := j.Get("seconds").Time(); got.Location() != time.UTC {
+		t.Errorf("timestamps should be UTC, got %v", got.Location())
+	}
+
+	_, err := j.Get("bad").Time("2006-01-02")
+	if err == nil || err.Error() != `value at bad is not a valid time: "yesterday" matches none of 2006-01-02, 2006-01-02T15:04:05Z07:00` {
+		t.Errorf("Time(bad) error = %v", err)
+	}
+	var typeErr *TypeError
+	if _, err := j.Get("flag").Time(); !errors.As(err, &typeErr) || typeErr.Expected != "time" {
+		t.Errorf("Time(flag) error = %v", err)
+	}
+	if _, err := j.Get("missing").Time(); !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("Time(missing) error = %v", err)
+	}
+
+	def := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got := j.Get("bad").TimeOr(def); !got.Equal(def) {
+		t.Errorf("TimeOr(bad) = %v", got)
+	}
+	if got := j.Get("date").TimeOr(def, "2006-01-02"); got.Day() != 14 {
+		t.Errorf("TimeOr(date) = %v", got)
+	}
+}
@@ -0,0 +1,169 @@
+package jsonx
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// 带数值容差的比较报告
+
+// ToleranceMode 数值容差的计算方式
+type ToleranceMode int
+
+const (
+	// ToleranceAbsolute 绝对容差：|a-b| <= epsilon
+	ToleranceAbsolute ToleranceMode = iota
+	// ToleranceRelative 相对容差：|a-b| <= epsilon * max(|a|, |b|)
+	ToleranceRelative
+)
+
+// 差异种类
+const (
+	DifferenceAdded   = "added"   // 仅存在于 b
+	DifferenceRemoved = "removed" // 仅存在于 a
+	DifferenceChanged = "changed" // 两边都存在但值不同
+)
+
+// Difference 描述某个路径上的差异
+type Difference struct {
+	Path     string      `json:"path"`     // 路径，根为空
+	Kind     string      `json:"kind"`     // added / removed / changed
+	A        interface{} `json:"a"`        // a 中的值，added 时为 nil
+	B        interface{} `json:"b"`        // b 中的值，removed 时为 nil
+	NearMiss bool        `json:"nearMiss"` // 数值不同但在容差之内，不影响 EqualWithTolerance 的结果
+}
+
+// EqualWithTolerance 结构化比较两个 JSON，数值叶子在 epsilon 内视为相等，其余值精确比较
+// mode 默认为 ToleranceAbsolute；任一方带有错误时返回 false
+func EqualWithTolerance(a, b *JSON, epsilon float64, mode ...ToleranceMode) bool {
+	for _, d := range DiffWithTolerance(a, b, epsilon, mode...) {
+		if !d.NearMiss {
+			return false
+		}
+	}
+	return true
+}
+
+// DiffWithTolerance 比较两个 JSON 并返回按遍历顺序排列的差异报告
+//
+// 数值叶子（int 与 float 混合时按数值比较）在 epsilon 内时仍会报告，但 NearMiss 为 true，
+// 便于查看被容差吸收的抖动；其余叶子精确比较。对象键按字典序遍历，数组逐元素比较，
+// 长度不同时多出的元素报告为 added / removed。任一方带有错误时返回根路径的一条 changed 差异。
+func DiffWithTolerance(a, b *JSON, epsilon float64, mode ...ToleranceMode) []Difference {
+	if a == nil || b == nil || a.err != nil || b.err != nil {
+		var av, bv interface{}
+		if a != nil {
+			av = a.data
+		}
+		if b != nil {
+			bv = b.data
+		}
+		return []Difference{{Kind: DifferenceChanged, A: av, B: bv}}
+	}
+
+	d := &toleranceDiffer{epsilon: epsilon}
+	if len(mode) > 0 {
+		d.mode = mode[0]
+	}
+	d.diff(a.data, b.data, "", 0)
+	return d.out
+}
+
+// toleranceDiffer 差异收集器
+type toleranceDiffer struct {
+	epsilon float64
+	mode    ToleranceMode
+	out     []Difference
+}
+
+// diff 递归比较两个值；嵌套过深时整体报告为 changed
+func (d *toleranceDiffer) diff(a, b interface{}, path string, depth int) {
+	if depth > maxNestingDepth {
+		d.add(path, DifferenceChanged, a, b, false)
+		return
+	}
+
+	switch av := a.(type) {
+	case map[string]interface{}:
+		if bv, ok := b.(map[string]interface{}); ok {
+			d.diffObjects(av, bv, path, depth)
+			return
+		}
+	case []interface{}:
+		if bv, ok := b.([]interface{}); ok {
+			d.diffArrays(av, bv, path, depth)
+			return
+		}
+	default:
+		if af, ok := toFloat64(a); ok {
+			if bf, ok := toFloat64(b); ok {
+				if af != bf {
+					d.add(path, DifferenceChanged, a, b, d.within(af, bf))
+				}
+				return
+			}
+		}
+		if reflect.DeepEqual(a, b) {
+			return
+		}
+	}
+	d.add(path, DifferenceChanged, a, b, false)
+}
+
+// diffObjects 按字典序比较两个对象的键
+func (d *toleranceDiffer) diffObjects(a, b map[string]interface{}, path string, depth int) {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, exists := a[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		childPath := joinJSONPath(path, key)
+		av, inA := a[key]
+		bv, inB := b[key]
+		switch {
+		case !inB:
+			d.add(childPath, DifferenceRemoved, av, nil, false)
+		case !inA:
+			d.add(childPath, DifferenceAdded, nil, bv, false)
+		default:
+			d.diff(av, bv, childPath, depth+1)
+		}
+	}
+}
+
+// diffArrays 逐元素比较两个数组
+func (d *toleranceDiffer) diffArrays(a, b []interface{}, path string, depth int) {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		childPath := joinJSONPath(path, strconv.Itoa(i))
+		switch {
+		case i >= len(b):
+			d.add(childPath, DifferenceRemoved, a[i], nil, false)
+		case i >= len(a):
+			d.add(childPath, DifferenceAdded, nil, b[i], false)
+		default:
+			d.diff(a[i], b[i], childPath, depth+1)
+		}
+	}
+}
+
+// within 判断两个数值是否在容差之内
+func (d *toleranceDiffer) within(a, b float64) bool {
+	delta := math.Abs(a - b)
+	if d.mode == ToleranceRelative {
+		return delta <= d.epsilon*math.Max(math.Abs(a), math.Abs(b))
+	}
+	return delta <= d.epsilon
+}
+
+func (d *toleranceDiffer) add(path, kind string, a, b interface{}, nearMiss bool) {
+	d.out = append(d.out, Difference{Path: path, Kind: kind, A: a, B: b, NearMiss: nearMiss})
+}
@@ -0,0 +1,85 @@
+package jsonx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEqualWithTolerance(t *testing.T) {
+	x, y := 0.1, 0.2
+	stored := Parse(`{"total":0.3,"items":[{"price":0.1},{"price":0.2}],"count":3,"currency":"CNY"}`)
+	recomputed := New(map[string]interface{}{
+		"total":    x + y, // 0.30000000000000004
+		"items":    []interface{}{map[string]interface{}{"price": 0.1}, map[string]interface{}{"price": 0.2}},
+		"count":    3, // int 与解析得到的 float64 混合比较
+		"currency": "CNY",
+	})
+
+	if Equal(stored, recomputed) {
+		t.Fatal("float jitter should fail exact comparison")
+	}
+	if !EqualWithTolerance(stored, recomputed, 1e-9) {
+		t.Error("float jitter should pass within 1e-9")
+	}
+	if !EqualWithTolerance(stored, recomputed, 1e-9, ToleranceRelative) {
+		t.Error("float jitter should pass within relative 1e-9")
+	}
+	if EqualWithTolerance(stored, Parse(`{"total":0.3,"items":[{"price":0.1},{"price":0.2}],"count":3,"currency":"USD"}`), 1) {
+		t.Error("strings must compare exactly regardless of epsilon")
+	}
+	if EqualWithTolerance(Parse(`{"n":1}`), Parse(`{"n":"1"}`), 1) {
+		t.Error("number and string must not be equal")
+	}
+	if EqualWithTolerance(Parse(`{invalid`), stored, 1) {
+		t.Error("document with error should not be equal")
+	}
+}
+
+func TestToleranceBoundaries(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		epsilon float64
+		mode    ToleranceMode
+		want    bool
+	}{
+		{`10`, `10.5`, 0.5, ToleranceAbsolute, true},
+		{`10`, `10.5000001`, 0.5, ToleranceAbsolute, false},
+		{`10`, `9.5`, 0.5, ToleranceAbsolute, true},
+		{`100`, `101`, 0.01, ToleranceRelative, true},
+		{`100`, `102.1`, 0.02, ToleranceRelative, false},
+		{`-100`, `-101`, 0.01, ToleranceRelative, true},
+		{`0`, `1e-12`, 0.01, ToleranceRelative, false}, // 相对容差下 0 只与 0 相等
+		{`0`, `0.1`, 0.01, ToleranceRelative, false},
+		{`1`, `1.0`, 0, ToleranceAbsolute, true},
+	}
+	for _, tt := range tests {
+		if got := EqualWithTolerance(Parse(tt.a), Parse(tt.b), tt.epsilon, tt.mode); got != tt.want {
+			t.Errorf("EqualWithTolerance(%s, %s, %v, %v) = %v, want %v", tt.a, tt.b, tt.epsilon, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestDiffWithTolerance(t *testing.T) {
+	a := Parse(`{"price":10.0,"tax":1.0,"items":[1,2,3],"name":"a","old":true}`)
+	b := Parse(`{"price":10.004,"tax":1.5,"items":[1,2],"name":"b","new":null}`)
+
+	want := []Difference{
+		{Path: "items.2", Kind: DifferenceRemoved, A: 3.0},
+		{Path: "name", Kind: DifferenceChanged, A: "a", B: "b"},
+		{Path: "new", Kind: DifferenceAdded},
+		{Path: "old", Kind: DifferenceRemoved, A: true},
+		{Path: "price", Kind: DifferenceChanged, A: 10.0, B: 10.004, NearMiss: true},
+		{Path: "tax", Kind: DifferenceChanged, A: 1.0, B: 1.5},
+	}
+	got := DiffWithTolerance(a, b, 0.01)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffWithTolerance =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if diffs := DiffWithTolerance(a, a.Clone(), 0); len(diffs) != 0 {
+		t.Errorf("identical documents should have no differences, got %+v", diffs)
+	}
+	if diffs := DiffWithTolerance(Parse(`{"n":[1]}`), Parse(`{"n":{"0":1}}`), 0); len(diffs) != 1 || diffs[0].Path != "n" {
+		t.Errorf("array vs object should be one change at n, got %+v", diffs)
+	}
+}
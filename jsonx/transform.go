@@ -0,0 +1,68 @@
+package jsonx
+
+import "strconv"
+
+// MapDeep 深度映射：以完整路径（如 "users.0.password"）访问每个叶子值，返回新文档，不修改原文档
+//
+// 叶子指字符串、数字、布尔值与 null；空对象和空数组原样保留，不会传给回调。
+// 回调返回 (新值, true) 时替换该叶子，返回 (任意, false) 时保留原值。
+// 与 ForEach 相同，回调收到的 v 仅在本次调用内有效。
+func (j *JSON) MapDeep(fn func(path string, v *JSON) (interface{}, bool)) *JSON {
+	if j.err != nil {
+		return j
+	}
+
+	m := &deepMapper{fn: fn, elem: &JSON{}, base: j.path}
+	result, err := m.walk(j.data, "")
+	if err != nil {
+		return &JSON{err: err}
+	}
+	return &JSON{data: result}
+}
+
+// deepMapper 深度映射的遍历状态
+type deepMapper struct {
+	fn    func(path string, v *JSON) (interface{}, bool)
+	elem  *JSON
+	base  string
+	guard cycleGuard
+}
+
+// walk 复制容器并对叶子调用回调
+func (m *deepMapper) walk(v interface{}, path string) (interface{}, error) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		ptr, err := m.guard.enter(val)
+		if err != nil {
+			return nil, err
+		}
+		result := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			if result[k], err = m.walk(item, joinJSONPath(path, k)); err != nil {
+				return nil, err
+			}
+		}
+		m.guard.leave(ptr)
+		return result, nil
+	case []interface{}:
+		ptr, err := m.guard.enter(val)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]interface{}, len(val))
+		for i, item := range val {
+			if result[i], err = m.walk(item, joinJSONPath(path, strconv.Itoa(i))); err != nil {
+				return nil, err
+			}
+		}
+		m.guard.leave(ptr)
+		return result, nil
+	default:
+		*m.elem = JSON{data: v, path: joinJSONPath(m.base, path)}
+		replaced, ok := m.fn(path, m.elem)
+		if !ok {
+			return v, nil
+		}
+		return detachElem(replaced, m.elem), nil
+	}
+}
@@ -0,0 +1,91 @@
+package jsonx
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMapDeepRedact(t *testing.T) {
+	doc := Parse(`{
+		"password": "root-secret",
+		"users": [
+			{"name": "tom", "password": "p1", "meta": {"password": "p2", "tags": []}},
+			{"name": "amy", "settings": {}}
+		]
+	}`)
+	original, _ := doc.ToJSON()
+
+	var visited []string
+	redacted := doc.MapDeep(func(path string, v *JSON) (interface{}, bool) {
+		visited = append(visited, path)
+		if path == "password" || strings.HasSuffix(path, ".password") {
+			return "***", true
+		}
+		return nil, false
+	})
+	if err := redacted.Error(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{"password", "users.0.password", "users.0.meta.password"} {
+		if got := redacted.Get(path).String(); got != "***" {
+			t.Errorf("%s = %q, want redacted", path, got)
+		}
+	}
+	if redacted.Get("users.1.name").String() != "amy" || !redacted.Get("users.1.settings").IsObject() || !redacted.Get("users.0.meta.tags").IsArray() {
+		t.Error("unrelated values and empty containers should be kept")
+	}
+	if len(visited) != 5 {
+		t.Errorf("visited %d leaves, want 5: %v", len(visited), visited)
+	}
+
+	// 原文档不变，且结果与原文档不共享容器
+	if after, _ := doc.ToJSON(); after != original {
+		t.Errorf("receiver was modified: %s", after)
+	}
+	redacted.Set("users.1.name", "changed")
+	if doc.Get("users.1.name").String() != "amy" {
+		t.Error("result must not share containers with the receiver")
+	}
+}
+
+func TestMapDeepConvertDates(t *testing.T) {
+	doc := Parse(`{"created": "2024-01-02T03:04:05Z", "events": [{"at": "2024-01-02T03:04:06Z", "label": "2024 launch"}], "count": 2}`)
+
+	converted := doc.MapDeep(func(path string, v *JSON) (interface{}, bool) {
+		if t, err := v.TryTime(""); err == nil {
+			return t.Unix(), true
+		}
+		return nil, false
+	})
+
+	want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC).Unix()
+	if converted.Get("created").Int64() != want || converted.Get("events.0.at").Int64() != want+1 {
+		t.Errorf("dates not converted: %v", converted.data)
+	}
+	if converted.Get("events.0.label").String() != "2024 launch" || converted.Get("count").Int() != 2 {
+		t.Error("non-date values should be kept")
+	}
+
+	// 回调中的错误信息带有完整路径
+	var msg string
+	doc.Get("events").MapDeep(func(path string, v *JSON) (interface{}, bool) {
+		if path == "0.label" {
+			_, err := v.TryInt()
+			msg = err.Error()
+		}
+		return nil, false
+	})
+	if msg != "value at events.0.label is string, expected integer" {
+		t.Errorf("error = %q", msg)
+	}
+
+	// 根为标量时路径为空
+	if got := Parse(`"x"`).MapDeep(func(path string, v *JSON) (interface{}, bool) { return path == "", true }); !got.Bool() {
+		t.Error("scalar root should be visited with an empty path")
+	}
+	if err := FromMap(newCyclicMap()).MapDeep(func(string, *JSON) (interface{}, bool) { return nil, false }).Error(); err == nil {
+		t.Error("cyclic data should be reported")
+	}
+}
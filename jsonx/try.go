@@ -0,0 +1,105 @@
+package jsonx
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrPathNotFound 路径不存在，可通过 errors.Is 判断
+var ErrPathNotFound = errors.New("path not found")
+
+// TypeError 值的类型与期望不符
+type TypeError struct {
+	Path     string      // 值的路径，根为空
+	Expected string      // 期望的类型
+	Actual   string      // 实际类型，与 GetType 一致
+	Value    interface{} // 实际值
+}
+
+// Error 实现 error 接口
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("value at %s is %s, expected %s", displayPath(e.Path), e.Actual, e.Expected)
+}
+
+// typeError 创建类型错误
+func (j *JSON) typeError(expected string) error {
+	return &TypeError{Path: j.path, Expected: expected, Actual: GetType(j), Value: j.data}
+}
+
+// TryString 获取字符串，值不是字符串时返回 *TypeError，路径不存在时返回 Get 的错误
+func (j *JSON) TryString() (string, error) {
+	if j.err != nil {
+		return "", j.err
+	}
+	s, ok := j.data.(string)
+	if !ok {
+		return "", j.typeError("string")
+	}
+	return s, nil
+}
+
+// TryInt 获取整数，值不是数字、带有小数部分或超出范围时返回错误，不会将字符串转换为数字
+func (j *JSON) TryInt() (int, error) {
+	n, err := j.exactInteger(false)
+	if err != nil {
+		return 0, err
+	}
+	if !n.IsInt64() || n.Int64() > math.MaxInt || n.Int64() < math.MinInt {
+		return 0, j.notInteger()
+	}
+	return int(n.Int64()), nil
+}
+
+// TryFloat64 获取浮点数，值不是数字时返回 *TypeError
+func (j *JSON) TryFloat64() (float64, error) {
+	if j.err != nil {
+		return 0, j.err
+	}
+	f, ok := toFloat64(j.data)
+	if !ok {
+		return 0, j.typeError("number")
+	}
+	return f, nil
+}
+
+// TryBool 获取布尔值，值不是布尔值时返回 *TypeError
+func (j *JSON) TryBool() (bool, error) {
+	if j.err != nil {
+		return false, j.err
+	}
+	b, ok := j.data.(bool)
+	if !ok {
+		return false, j.typeError("boolean")
+	}
+	return b, nil
+}
+
+// TryTime 按 layout 解析字符串时间，layout 为空时使用 RFC3339
+func (j *JSON) TryTime(layout string) (time.Time, error) {
+	s, err := j.TryString()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("value at %s is not a valid time: %w", displayPath(j.path), err)
+	}
+	return t, nil
+}
+
+// joinJSONPath 拼接路径
+func joinJSONPath(base, path string) string {
+	switch {
+	case base == "":
+		return path
+	case path == "":
+		return base
+	default:
+		return base + "." + path
+	}
+}
@@ -0,0 +1,84 @@
+package jsonx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTryAccessors(t *testing.T) {
+	j := Parse(`{
+		"user": {"name": "tom", "age": 30, "score": 9.5, "active": true, "born": "1990-05-01T08:00:00Z", "id": "42"},
+		"items": [{"n": 1}, {"n": null}]
+	}`)
+
+	if s, err := j.Get("user.name").TryString(); err != nil || s != "tom" {
+		t.Errorf("TryString = %q, %v", s, err)
+	}
+	if n, err := j.Get("user.age").TryInt(); err != nil || n != 30 {
+		t.Errorf("TryInt = %d, %v", n, err)
+	}
+	if f, err := j.Get("user.score").TryFloat64(); err != nil || f != 9.5 {
+		t.Errorf("TryFloat64 = %v, %v", f, err)
+	}
+	if b, err := j.Get("user.active").TryBool(); err != nil || !b {
+		t.Errorf("TryBool = %v, %v", b, err)
+	}
+	born, err := j.Get("user.born").TryTime("")
+	if err != nil || !born.Equal(time.Date(1990, 5, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("TryTime = %v, %v", born, err)
+	}
+	if n, err := j.Get("items").Index(0).Get("n").TryInt(); err != nil || n != 1 {
+		t.Errorf("TryInt via Index = %d, %v", n, err)
+	}
+	if n, err := New(7).TryInt(); err != nil || n != 7 {
+		t.Errorf("TryInt on native int = %d, %v", n, err)
+	}
+}
+
+func TestTryAccessorErrors(t *testing.T) {
+	j := Parse(`{"user": {"id": "42", "score": 9.5, "tags": ["a"], "born": "yesterday"}, "items": [{"n": null}]}`)
+
+	// 路径不存在
+	_, err := j.Get("user.missing").TryInt()
+	if !errors.Is(err, ErrPathNotFound) || !strings.Contains(err.Error(), "user.missing") {
+		t.Errorf("missing path error = %v", err)
+	}
+
+	// 类型不符
+	_, err = j.Get("user.id").TryInt()
+	var typeErr *TypeError
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("expected *TypeError, got %v", err)
+	}
+	if typeErr.Path != "user.id" || typeErr.Actual != "string" || typeErr.Expected != "integer" {
+		t.Errorf("type error = %+v", typeErr)
+	}
+	if err.Error() != "value at user.id is string, expected integer" {
+		t.Errorf("message = %q", err)
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"fraction", second(j.Get("user.score").TryInt()), "value at user.score is 9.5, expected integer"},
+		{"array as string", second(j.Get("user").Get("tags").TryString()), "value at user.tags is array, expected string"},
+		{"null in array", second(j.Get("items").Index(-1).Get("n").TryFloat64()), "value at items.-1.n is null, expected number"},
+		{"bool", second(j.Get("user.id").TryBool()), "value at user.id is string, expected boolean"},
+		{"time", second(j.Get("user.born").TryTime(time.DateOnly)), "value at user.born is not a valid time"},
+		{"root", second(j.TryString()), "value at (root) is object, expected string"},
+	}
+	for _, tt := range tests {
+		if tt.err == nil || !strings.Contains(tt.err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want %q", tt.name, tt.err, tt.want)
+		}
+	}
+}
+
+// second 返回第二个返回值
+func second[T any](_ T, err error) error {
+	return err
+}
@@ -0,0 +1,123 @@
+package jsonx
+
+import (
+	"sort"
+	"strings"
+)
+
+// TypeChange 描述某个路径上的类型变化
+type TypeChange struct {
+	Path    string `json:"path"`    // 路径，数组元素使用 [] 表示，如 items[].id
+	Kind    string `json:"kind"`    // 变化类型：added / removed / changed
+	OldType string `json:"oldType"` // 旧类型，新增字段时为空
+	NewType string `json:"newType"` // 新类型，删除字段时为空
+}
+
+// 类型变化种类
+const (
+	TypeChangeAdded   = "added"
+	TypeChangeRemoved = "removed"
+	TypeChangeChanged = "changed"
+)
+
+// TypeDiff 比较两个文档的类型结构，返回按路径排序的类型变化列表
+//
+// 类型名称与 GetType 一致。数组会汇总所有元素的类型，元素类型不一致时
+// 以 "|" 连接（如 "number|string"），对象数组的字段同样跨元素汇总。
+// 父路径新增或删除时，其子路径不再重复报告。
+func TypeDiff(old, new *JSON) []TypeChange {
+	oldShape := make(map[string]map[string]bool)
+	newShape := make(map[string]map[string]bool)
+	collectShape(old.data, "", 0, oldShape)
+	collectShape(new.data, "", 0, newShape)
+
+	paths := make(map[string]bool, len(oldShape)+len(newShape))
+	for p := range oldShape {
+		paths[p] = true
+	}
+	for p := range newShape {
+		paths[p] = true
+	}
+
+	changes := make([]TypeChange, 0)
+	for p := range paths {
+		oldTypes, inOld := oldShape[p]
+		newTypes, inNew := newShape[p]
+
+		if p != "" {
+			parent := shapeParent(p)
+			if _, ok := oldShape[parent]; !ok {
+				continue
+			}
+			if _, ok := newShape[parent]; !ok {
+				continue
+			}
+		}
+
+		switch {
+		case inOld && !inNew:
+			changes = append(changes, TypeChange{Path: p, Kind: TypeChangeRemoved, OldType: joinTypes(oldTypes)})
+		case !inOld && inNew:
+			changes = append(changes, TypeChange{Path: p, Kind: TypeChangeAdded, NewType: joinTypes(newTypes)})
+		default:
+			oldType, newType := joinTypes(oldTypes), joinTypes(newTypes)
+			if oldType != newType {
+				changes = append(changes, TypeChange{Path: p, Kind: TypeChangeChanged, OldType: oldType, NewType: newType})
+			}
+		}
+	}
+
+	sort.Slice(changes, func(i, k int) bool {
+		return changes[i].Path < changes[k].Path
+	})
+	return changes
+}
+
+// collectShape 递归收集每个路径上出现过的类型，超过 maxNestingDepth 的部分不再展开
+func collectShape(data interface{}, path string, depth int, shape map[string]map[string]bool) {
+	types, ok := shape[path]
+	if !ok {
+		types = make(map[string]bool)
+		shape[path] = types
+	}
+	types[GetType(&JSON{data: data})] = true
+
+	if depth >= maxNestingDepth {
+		return
+	}
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for k, val := range v {
+			key := k
+			if path != "" {
+				key = path + "." + k
+			}
+			collectShape(val, key, depth+1, shape)
+		}
+	case []interface{}:
+		for _, val := range v {
+			collectShape(val, path+"[]", depth+1, shape)
+		}
+	}
+}
+
+// shapeParent 获取类型路径的父路径
+func shapeParent(path string) string {
+	if strings.HasSuffix(path, "[]") {
+		return strings.TrimSuffix(path, "[]")
+	}
+	if idx := strings.LastIndex(path, "."); idx >= 0 {
+		return path[:idx]
+	}
+	return ""
+}
+
+// joinTypes 将类型集合排序后以 "|" 连接
+func joinTypes(types map[string]bool) string {
+	names := make([]string, 0, len(types))
+	for t := range types {
+		names = append(names, t)
+	}
+	sort.Strings(names)
+	return strings.Join(names, "|")
+}
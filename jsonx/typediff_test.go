@@ -0,0 +1,63 @@
+package jsonx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTypeDiff(t *testing.T) {
+	v1 := Parse(`{
+		"id": 1001,
+		"name": "订单",
+		"paid": false,
+		"customer": {"id": 7, "email": "a@example.com"},
+		"items": [
+			{"sku": "A1", "qty": 2, "price": 9.9},
+			{"sku": "B2", "qty": 1, "price": 19.9}
+		],
+		"tags": ["new", "vip"],
+		"legacy": {"code": "x"}
+	}`)
+	v2 := Parse(`{
+		"id": "1001",
+		"name": "订单",
+		"paid": false,
+		"customer": {"id": 7, "email": null, "phone": "123"},
+		"items": [
+			{"sku": "A1", "qty": 2, "price": "9.90"},
+			{"sku": "B2", "qty": 1, "price": 19.9, "discount": {"rate": 0.1}}
+		],
+		"tags": ["new", 3]
+	}`)
+
+	expected := []TypeChange{
+		{Path: "customer.email", Kind: TypeChangeChanged, OldType: "string", NewType: "null"},
+		{Path: "customer.phone", Kind: TypeChangeAdded, NewType: "string"},
+		{Path: "id", Kind: TypeChangeChanged, OldType: "number", NewType: "string"},
+		{Path: "items[].discount", Kind: TypeChangeAdded, NewType: "object"},
+		{Path: "items[].price", Kind: TypeChangeChanged, OldType: "number", NewType: "number|string"},
+		{Path: "legacy", Kind: TypeChangeRemoved, OldType: "object"},
+		{Path: "tags[]", Kind: TypeChangeChanged, OldType: "string", NewType: "number|string"},
+	}
+
+	changes := TypeDiff(v1, v2)
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("unexpected type changes:\n got: %+v\nwant: %+v", changes, expected)
+	}
+
+	if changes := TypeDiff(v1, v1.Clone()); len(changes) != 0 {
+		t.Errorf("expected no changes for identical documents, got %+v", changes)
+	}
+}
+
+func TestTypeDiffRootChange(t *testing.T) {
+	changes := TypeDiff(Parse(`[1, 2]`), Parse(`{"a": 1}`))
+	expected := []TypeChange{
+		{Path: "", Kind: TypeChangeChanged, OldType: "array", NewType: "object"},
+		{Path: "[]", Kind: TypeChangeRemoved, OldType: "number"},
+		{Path: "a", Kind: TypeChangeAdded, NewType: "number"},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("unexpected type changes: %+v", changes)
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/zhoudm1743/go-util/jsonx/yamlx
+
+go 1.23.4
+
+replace github.com/zhoudm1743/go-util/jsonx => ../
+
+require (
+	github.com/zhoudm1743/go-util/jsonx v0.0.0-00010101000000-000000000000
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,126 @@
+// Package yamlx 提供 jsonx 与 YAML 之间的转换
+//
+// 独立为单独的模块以隔离 YAML 依赖，jsonx 本身仍只依赖标准库。
+package yamlx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/zhoudm1743/go-util/jsonx"
+	"gopkg.in/yaml.v3"
+)
+
+// FromYAML 解析 YAML 为 JSON
+//
+// 键统一转换为字符串；时间戳转换为 RFC3339 字符串，仅有日期的时间戳（UTC 零点）转换为 "2006-01-02"；
+// 多文档 YAML 返回由各文档组成的数组，空输入返回 null。
+// JSON 无法表示 .inf 与 .nan，遇到时返回错误。
+func FromYAML(data []byte) *jsonx.JSON {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+
+	var docs []interface{}
+	for {
+		var doc interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return jsonx.FromError(err)
+		}
+		normalized, err := normalize(doc, "")
+		if err != nil {
+			return jsonx.FromError(err)
+		}
+		docs = append(docs, normalized)
+	}
+
+	switch len(docs) {
+	case 0:
+		return jsonx.New(nil)
+	case 1:
+		return jsonx.New(docs[0])
+	default:
+		return jsonx.New(docs)
+	}
+}
+
+// ToYAML 将 JSON 序列化为 YAML，对象的键按字典序输出
+func ToYAML(j *jsonx.JSON) ([]byte, error) {
+	if err := j.Error(); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(j.ToInterface())
+}
+
+// normalize 将 YAML 解码结果转换为 jsonx 使用的表示
+func normalize(v interface{}, path string) (interface{}, error) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			normalized, err := normalize(item, joinPath(path, k))
+			if err != nil {
+				return nil, err
+			}
+			out[k] = normalized
+		}
+		return out, nil
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			key := fmt.Sprint(k)
+			if _, exists := out[key]; exists {
+				return nil, fmt.Errorf("yaml: duplicate key %q at %s after converting keys to strings", key, displayPath(path))
+			}
+			normalized, err := normalize(item, joinPath(path, key))
+			if err != nil {
+				return nil, err
+			}
+			out[key] = normalized
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			normalized, err := normalize(item, joinPath(path, fmt.Sprint(i)))
+			if err != nil {
+				return nil, err
+			}
+			out[i] = normalized
+		}
+		return out, nil
+	case time.Time:
+		if val.Location() == time.UTC && val.Equal(val.Truncate(24*time.Hour)) {
+			return val.Format("2006-01-02"), nil
+		}
+		return val.Format(time.RFC3339Nano), nil
+	case float64:
+		if math.IsInf(val, 0) || math.IsNaN(val) {
+			return nil, fmt.Errorf("yaml: %v at %s cannot be represented in JSON", val, displayPath(path))
+		}
+		return val, nil
+	default:
+		return val, nil
+	}
+}
+
+// joinPath 拼接错误信息中的路径
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// displayPath 用于错误信息的路径，根显示为 (root)
+func displayPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
@@ -0,0 +1,117 @@
+package yamlx
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zhoudm1743/go-util/jsonx"
+)
+
+func TestFromYAML(t *testing.T) {
+	j := FromYAML([]byte(`
+name: app
+port: 8080
+ratio: 0.5
+big: 12345678901234567890
+enabled: true
+empty: ~
+missing:
+created: 2024-01-02T03:04:05Z
+day: 2024-01-02
+tags: [a, b]
+codes:
+  200: ok
+  404: not found
+`))
+	if err := j.Error(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := j.Get("name").String(); got != "app" {
+		t.Errorf("name = %q", got)
+	}
+	if got := j.Get("port").Int(); got != 8080 {
+		t.Errorf("port = %d", got)
+	}
+	if got := j.Get("ratio").Float64(); got != 0.5 {
+		t.Errorf("ratio = %v", got)
+	}
+	if got := j.Get("big").ToInterface(); got != uint64(12345678901234567890) {
+		t.Errorf("big = %#v", got)
+	}
+	for _, path := range []string{"empty", "missing"} {
+		if !j.Has(path) || !j.Get(path).IsNull() {
+			t.Errorf("%s should be null", path)
+		}
+	}
+	if got := j.Get("created").String(); got != "2024-01-02T03:04:05Z" {
+		t.Errorf("created = %q", got)
+	}
+	if got := j.Get("day").String(); got != "2024-01-02" {
+		t.Errorf("day = %q", got)
+	}
+	// 非字符串键转换为字符串
+	if got := j.Get("codes.404").String(); got != "not found" {
+		t.Errorf("codes.404 = %q", got)
+	}
+}
+
+func TestFromYAMLDocuments(t *testing.T) {
+	j := FromYAML([]byte("a: 1\n---\nb: 2\n---\n- x\n"))
+	if err := j.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if !j.IsArray() || j.Length() != 3 {
+		t.Fatalf("expected array of 3 documents, got %v", j.ToInterface())
+	}
+	if got := j.Get("1.b").Int(); got != 2 {
+		t.Errorf("second document b = %d", got)
+	}
+
+	if empty := FromYAML(nil); empty.Error() != nil || !empty.IsNull() {
+		t.Errorf("empty input should be null, got %v %v", empty.ToInterface(), empty.Error())
+	}
+}
+
+func TestFromYAMLErrors(t *testing.T) {
+	tests := map[string]string{
+		"syntax":    "a: [1, 2",
+		"infinity":  "limits:\n  max: .inf\n",
+		"duplicate": "{1: a, \"1\": b}",
+	}
+	for name, src := range tests {
+		if FromYAML([]byte(src)).Error() == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if err := FromYAML([]byte("limits:\n  max: .inf\n")).Error(); !strings.Contains(err.Error(), "limits.max") {
+		t.Errorf("error should include path, got %v", err)
+	}
+}
+
+func TestToYAMLRoundTrip(t *testing.T) {
+	original := jsonx.Parse(`{"name":"app","nested":{"list":[1,"two",null,true]},"empty":null}`)
+
+	out, err := ToYAML(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back := FromYAML(out)
+	if err := back.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if !jsonx.Equal(original, back) {
+		t.Errorf("round trip mismatch:\n%s\n%v", out, back.ToInterface())
+	}
+
+	if _, err := ToYAML(jsonx.Parse(`{`)); err == nil {
+		t.Error("ToYAML should propagate JSON errors")
+	}
+	if out, _ := ToYAML(jsonx.New(nil)); strings.TrimSpace(string(out)) != "null" {
+		t.Errorf("null = %q", out)
+	}
+	if !reflect.DeepEqual(FromYAML([]byte("null")).ToInterface(), nil) {
+		t.Error("YAML null should decode to nil")
+	}
+}
@@ -0,0 +1,45 @@
+package jwt
+
+import (
+	"encoding/pem"
+	"fmt"
+	"strings"
+)
+
+// 算法限制
+//
+// 头部的 alg 由令牌持有者控制，所有解析入口都按以下规则检查：
+//   - alg 为 "none"（不区分大小写）或为空时一律拒绝，WithValidMethods 中列出也无效；
+//   - 设置了 WithValidMethods 时，alg 必须在列表中，用于同时接受多种算法的服务（如 RS256 与 ES256）；
+//   - 每种算法只接受对应类型的密钥：HMAC 要求 []byte 且不能是 PEM 编码的内容，
+//     RSA / ECDSA 要求对应的公钥或私钥，因此把公钥 PEM 当作 HMAC 密钥的混淆攻击无法通过验证。
+//
+// 以上检查失败时返回的错误均包装 ErrAlgorithmNotAllowed（密钥类型不符时为 ErrInvalidKeyType）。
+
+// checkAlgorithm 检查头部 alg 是否允许，validMethods 为空时不限制具体算法
+func checkAlgorithm(alg string, validMethods []string) error {
+	if alg == "" || strings.EqualFold(alg, "none") {
+		return fmt.Errorf("%w: alg %q", ErrAlgorithmNotAllowed, alg)
+	}
+	if len(validMethods) > 0 && !containsString(validMethods, alg) {
+		return unexpectedSigningMethod(alg)
+	}
+	return nil
+}
+
+// unexpectedSigningMethod 算法与期望不一致的错误
+func unexpectedSigningMethod(alg string) error {
+	return fmt.Errorf("%w: unexpected signing method: %s", ErrAlgorithmNotAllowed, alg)
+}
+
+// hmacKey 获取 HMAC 密钥，拒绝 PEM 编码的内容（通常是被当作 HMAC 密钥的公钥）
+func hmacKey(key interface{}) ([]byte, error) {
+	keyBytes, ok := key.([]byte)
+	if !ok {
+		return nil, ErrInvalidKeyType
+	}
+	if block, _ := pem.Decode(keyBytes); block != nil {
+		return nil, fmt.Errorf("%w: PEM encoded %s cannot be used as an HMAC secret", ErrInvalidKeyType, block.Type)
+	}
+	return keyBytes, nil
+}
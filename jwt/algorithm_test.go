@@ -0,0 +1,158 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// forgeToken 像攻击者一样直接构造令牌：secret 为 nil 时签名为空，否则使用 HMAC-SHA256
+func forgeToken(alg string, secret []byte, claims MapClaims) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signingString := base64URLEncode(header) + "." + base64URLEncode(payload)
+	if secret == nil {
+		return signingString + "."
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingString))
+	return signingString + "." + base64URLEncode(mac.Sum(nil))
+}
+
+func TestRejectNoneAlgorithm(t *testing.T) {
+	secret := []byte("none-secret")
+	claims := MapClaims{"sub": "admin", "exp": time.Now().Add(time.Hour).Unix()}
+	compatKey := func(*Token) (interface{}, error) { return secret, nil }
+	headerKey := func(*Header) (SigningMethod, interface{}, error) { return SigningMethodHS256, secret, nil }
+
+	for _, alg := range []string{"none", "None", "NONE", ""} {
+		for _, token := range []string{forgeToken(alg, nil, claims), forgeToken(alg, secret, claims)} {
+			_, errParse := New(SigningMethodHS256, secret).Parse(token)
+			_, errCompat := ParseCompat(token, compatKey)
+			_, errAllowed := ParseCompat(token, compatKey, WithValidMethods([]string{"none", "", "HS256"}))
+			_, errKeyFunc := ParseWithKeyFunc(token, headerKey)
+			_, errVerifier := NewVerifier(SigningMethodHS256, secret).Verify(token)
+			for name, err := range map[string]error{
+				"Parse": errParse, "ParseCompat": errCompat, "WithValidMethods(none)": errAllowed,
+				"ParseWithKeyFunc": errKeyFunc, "Verifier": errVerifier,
+			} {
+				if !errors.Is(err, ErrAlgorithmNotAllowed) {
+					t.Errorf("%s alg %q err = %v, want ErrAlgorithmNotAllowed", name, alg, err)
+				}
+			}
+		}
+	}
+}
+
+func TestWithValidMethodsMultipleAlgorithms(t *testing.T) {
+	rsaKey, _ := GenerateRSAKeyPair(2048)
+	ecKey, _ := GenerateECDSAKeyPair(elliptic.P256())
+	hmacSecret := []byte("hmac-secret")
+
+	// 按 token.Method 绑定密钥类型
+	keyfunc := func(token *Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *SigningMethodRSA:
+			return &rsaKey.PublicKey, nil
+		case *SigningMethodECDSA:
+			return &ecKey.PublicKey, nil
+		}
+		return hmacSecret, nil
+	}
+	allowRSAAndEC := WithValidMethods([]string{"RS256", "ES256"})
+
+	claims := MapClaims{"sub": "user"}
+	rs256, _ := GenerateRS256(rsaKey, claims)
+	es256, _ := New(SigningMethodES256, ecKey).Generate(claims)
+	for _, tokenString := range []string{rs256, es256} {
+		if _, err := ParseCompat(tokenString, keyfunc, allowRSAAndEC); err != nil {
+			t.Errorf("allowed method err = %v", err)
+		}
+	}
+
+	hs256, _ := GenerateHS256(hmacSecret, claims)
+	ps256, _ := New(SigningMethodPS256, rsaKey).Generate(claims)
+	es384, _ := New(SigningMethodES384, mustECKey(t, elliptic.P384())).Generate(claims)
+	for _, tokenString := range []string{hs256, ps256, es384} {
+		if _, err := ParseCompat(tokenString, keyfunc, allowRSAAndEC); !errors.Is(err, ErrAlgorithmNotAllowed) {
+			t.Errorf("disallowed method err = %v", err)
+		}
+	}
+	// 未设置 WithValidMethods 时 HS256 按 keyfunc 的密钥正常验证
+	if _, err := ParseCompat(hs256, keyfunc); err != nil {
+		t.Errorf("unrestricted HS256 err = %v", err)
+	}
+
+	// 单一方法的解析入口同样适用
+	if _, err := ParseWithOptions(SigningMethodHS256, hs256, hmacSecret, allowRSAAndEC); !errors.Is(err, ErrAlgorithmNotAllowed) {
+		t.Errorf("ParseWithOptions err = %v", err)
+	}
+	headerKey := func(*Header) (SigningMethod, interface{}, error) { return SigningMethodHS256, hmacSecret, nil }
+	if _, err := ParseWithKeyFunc(hs256, headerKey, allowRSAAndEC); !errors.Is(err, ErrAlgorithmNotAllowed) {
+		t.Errorf("ParseWithKeyFunc err = %v", err)
+	}
+}
+
+func TestKeyConfusionRSToHS(t *testing.T) {
+	rsaKey, _ := GenerateRSAKeyPair(2048)
+	publicPEM, _ := PublicKeyToPEM(&rsaKey.PublicKey)
+	// 攻击者拿到公开的公钥 PEM，将 alg 改为 HS256 并以其作为 HMAC 密钥签名
+	attack := forgeToken("HS256", publicPEM, MapClaims{"sub": "admin"})
+	legit, _ := GenerateRS256(rsaKey, MapClaims{"sub": "user"})
+
+	// 不检查算法、总是返回公钥 PEM 的 keyfunc：HMAC 拒绝 PEM 编码的密钥
+	naive := func(*Token) (interface{}, error) { return publicPEM, nil }
+	if _, err := ParseCompat(attack, naive); !errors.Is(err, ErrInvalidKeyType) {
+		t.Errorf("naive keyfunc err = %v, want ErrInvalidKeyType", err)
+	}
+	if _, err := ParseCompat(legit, naive); err != nil {
+		t.Errorf("legitimate RS256 token err = %v", err)
+	}
+
+	// 设置 WithValidMethods 时在使用密钥之前就被拒绝
+	if _, err := ParseCompat(attack, naive, WithValidMethods([]string{"RS256"})); !errors.Is(err, ErrAlgorithmNotAllowed) {
+		t.Errorf("WithValidMethods err = %v", err)
+	}
+
+	// 实例与 Verifier 绑定单一算法
+	for _, key := range []interface{}{publicPEM, &rsaKey.PublicKey} {
+		if _, err := New(SigningMethodRS256, key).Parse(attack); !errors.Is(err, ErrAlgorithmNotAllowed) {
+			t.Errorf("Parse(%T) err = %v", key, err)
+		}
+		if _, err := NewVerifier(SigningMethodRS256, key).Verify(attack); !errors.Is(err, ErrAlgorithmNotAllowed) {
+			t.Errorf("Verifier(%T) err = %v", key, err)
+		}
+	}
+
+	// 即使调用方误把 HS256 与公钥 PEM 配在一起，验证也会失败
+	if _, err := New(SigningMethodHS256, publicPEM).Parse(attack); !errors.Is(err, ErrInvalidKeyType) {
+		t.Errorf("HS256 with PEM key err = %v", err)
+	}
+	if _, err := NewVerifier(SigningMethodHS256, publicPEM).Verify(attack); !errors.Is(err, ErrInvalidKeyType) {
+		t.Errorf("HS256 Verifier with PEM key err = %v", err)
+	}
+	if _, err := GenerateHS256(publicPEM, MapClaims{}); !errors.Is(err, ErrInvalidKeyType) {
+		t.Errorf("signing with a PEM secret err = %v", err)
+	}
+
+	// JWKS 只提供 RSA 密钥，HS256 令牌被拒绝
+	set, _ := KeySetFromKeys(&rsaKey.PublicKey)
+	if _, err := ParseWithKeyFunc(attack, set.KeyFunc()); !errors.Is(err, ErrAlgorithmNotAllowed) {
+		t.Errorf("JWKS err = %v", err)
+	}
+}
+
+// mustECKey 生成指定曲线的 ECDSA 密钥
+func mustECKey(t *testing.T, curve elliptic.Curve) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := GenerateECDSAKeyPair(curve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
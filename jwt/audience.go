@@ -0,0 +1,84 @@
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// 受众：RFC 7519 4.1.3 允许 aud 为单个字符串或字符串数组，
+// Keycloak 等签发方常用 "aud": ["api", "web"]，验证时任一元素匹配即可。
+
+// ClaimStrings 字符串或字符串数组形式的声明，用于 aud
+// 反序列化时两种形式都接受；序列化时只有一个元素输出为字符串，否则输出为数组
+type ClaimStrings []string
+
+// UnmarshalJSON 接受字符串、字符串数组与 null
+func (s *ClaimStrings) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case nil:
+		*s = nil
+	case string:
+		*s = ClaimStrings{v}
+	case []interface{}:
+		values := make(ClaimStrings, 0, len(v))
+		for _, item := range v {
+			str, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("%w: claim array must contain only strings, got %T", ErrInvalidToken, item)
+			}
+			values = append(values, str)
+		}
+		*s = values
+	default:
+		return fmt.Errorf("%w: claim must be a string or an array of strings, got %T", ErrInvalidToken, value)
+	}
+	return nil
+}
+
+// MarshalJSON 一个元素时输出字符串，与只支持字符串形式的验证方兼容
+func (s ClaimStrings) MarshalJSON() ([]byte, error) {
+	if len(s) == 1 {
+		return json.Marshal(s[0])
+	}
+	return json.Marshal([]string(s))
+}
+
+// Contains 判断是否包含指定值
+func (s ClaimStrings) Contains(value string) bool {
+	return containsString(s, value)
+}
+
+// GetClaimStringSlice 从 MapClaims 中获取字符串或字符串数组形式的声明，数组中有非字符串元素时返回 false
+func GetClaimStringSlice(claims MapClaims, key string) ([]string, bool) {
+	switch v := claims[key].(type) {
+	case string:
+		return []string{v}, true
+	case []string:
+		return v, true
+	case ClaimStrings:
+		return v, true
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			str, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, str)
+		}
+		return values, true
+	default:
+		return nil, false
+	}
+}
+
+// hasAudience 判断 MapClaims 的 aud 是否包含指定受众
+func hasAudience(claims MapClaims, audience string) bool {
+	values, ok := GetClaimStringSlice(claims, "aud")
+	return ok && containsString(values, audience)
+}
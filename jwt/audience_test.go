@@ -0,0 +1,142 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestClaimStringsJSON(t *testing.T) {
+	tests := []struct {
+		input string
+		want  ClaimStrings
+	}{
+		{`"api"`, ClaimStrings{"api"}},
+		{`["api","web"]`, ClaimStrings{"api", "web"}},
+		{`[]`, ClaimStrings{}},
+		{`null`, nil},
+	}
+	for _, tt := range tests {
+		var got ClaimStrings
+		if err := json.Unmarshal([]byte(tt.input), &got); err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Unmarshal(%s) = %#v, %v, want %#v", tt.input, got, err, tt.want)
+		}
+	}
+	for _, input := range []string{`42`, `["api", 1]`, `{"aud":"api"}`} {
+		var got ClaimStrings
+		if err := json.Unmarshal([]byte(input), &got); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("Unmarshal(%s) err = %v, want ErrInvalidToken", input, err)
+		}
+	}
+
+	// 一个元素输出为字符串，空值被 omitempty 省略
+	for claims, want := range map[*StandardClaims]string{
+		{Audience: ClaimStrings{"api"}}:        `{"aud":"api"}`,
+		{Audience: ClaimStrings{"api", "web"}}: `{"aud":["api","web"]}`,
+		{}:                                     `{}`,
+	} {
+		if data, _ := json.Marshal(claims); string(data) != want {
+			t.Errorf("Marshal(%v) = %s, want %s", claims.Audience, data, want)
+		}
+	}
+}
+
+func TestAudienceArrayValidation(t *testing.T) {
+	secret := []byte("audience-secret")
+	// Keycloak 风格的数组形式
+	tokenString, _ := GenerateHS256(secret, MapClaims{"sub": "user", "aud": []string{"api", "web"}})
+
+	claims := &StandardClaims{}
+	if _, err := ParseWithClaims(SigningMethodHS256, tokenString, secret, claims); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(claims.Audience, ClaimStrings{"api", "web"}) || !claims.Audience.Contains("web") || claims.Audience.Contains("admin") {
+		t.Errorf("Audience = %v", claims.Audience)
+	}
+
+	for _, audience := range []string{"api", "web"} {
+		if _, err := ParseWithOptions(SigningMethodHS256, tokenString, secret, WithAudience(audience)); err != nil {
+			t.Errorf("WithAudience(%q): %v", audience, err)
+		}
+		keyFunc := func(*Header) (SigningMethod, interface{}, error) { return SigningMethodHS256, secret, nil }
+		if _, err := ParseWithClaimsKeyFunc(tokenString, &StandardClaims{}, keyFunc, WithAudience(audience)); err != nil {
+			t.Errorf("StandardClaims WithAudience(%q): %v", audience, err)
+		}
+	}
+	if _, err := ParseWithOptions(SigningMethodHS256, tokenString, secret, WithAudience("admin")); !errors.Is(err, ErrInvalidAudience) {
+		t.Errorf("unlisted audience err = %v", err)
+	}
+
+	mapClaims, _ := DecodeClaims(tokenString)
+	if err := ValidateStandardClaims(mapClaims, "web", "", "user"); err != nil {
+		t.Errorf("ValidateStandardClaims: %v", err)
+	}
+	if err := ValidateStandardClaims(mapClaims, "admin", "", ""); !errors.Is(err, ErrInvalidAudience) {
+		t.Errorf("ValidateStandardClaims unlisted err = %v", err)
+	}
+
+	// 非字符串数组无法解析到 StandardClaims
+	invalid, _ := GenerateHS256(secret, MapClaims{"aud": []interface{}{"api", 7}})
+	if _, err := ParseWithClaims(SigningMethodHS256, invalid, secret, &StandardClaims{}); err == nil {
+		t.Error("aud with a non-string element should fail to parse")
+	}
+}
+
+func TestGetClaimStringSlice(t *testing.T) {
+	claims := MapClaims{
+		"single": "api",
+		"array":  []interface{}{"api", "web"},
+		"one":    []interface{}{"api"},
+		"typed":  []string{"a", "b"},
+		"mixed":  []interface{}{"api", 1},
+		"number": 1.0,
+	}
+	tests := []struct {
+		key  string
+		want []string
+		ok   bool
+	}{
+		{"single", []string{"api"}, true},
+		{"array", []string{"api", "web"}, true},
+		{"typed", []string{"a", "b"}, true},
+		{"mixed", nil, false},
+		{"number", nil, false},
+		{"missing", nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := GetClaimStringSlice(claims, tt.key)
+		if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GetClaimStringSlice(%q) = %v, %v, want %v, %v", tt.key, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if s, ok := GetClaimString(claims, "one"); !ok || s != "api" {
+		t.Errorf("GetClaimString(one-element array) = %q, %v", s, ok)
+	}
+	if _, ok := GetClaimString(claims, "array"); ok {
+		t.Error("GetClaimString should not pick an element from a multi-value array")
+	}
+}
+
+func TestBuilderSetAudience(t *testing.T) {
+	secret := []byte("audience-secret")
+	for _, tt := range []struct {
+		audience []string
+		want     interface{}
+	}{
+		{[]string{"api"}, "api"},
+		{[]string{"api", "web"}, []interface{}{"api", "web"}},
+	} {
+		tokenString, _ := NewBuilder(SigningMethodHS256, secret).SetAudience(tt.audience...).Build()
+		claims, _ := DecodeClaims(tokenString)
+		if !reflect.DeepEqual(claims["aud"], tt.want) {
+			t.Errorf("SetAudience(%v) aud = %#v, want %#v", tt.audience, claims["aud"], tt.want)
+		}
+	}
+
+	tokenString, _ := NewBuilder(SigningMethodHS256, secret).SetAudience("api").SetAudience().Build()
+	if claims, _ := DecodeClaims(tokenString); claims["aud"] != nil {
+		t.Errorf("SetAudience() should remove aud, got %v", claims["aud"])
+	}
+}
@@ -0,0 +1,256 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// golang-jwt 迁移兼容层
+//
+// 从 github.com/golang-jwt/jwt 迁移时，替换导入路径后按下表调整调用即可：
+//
+//	golang-jwt                                   go-util/jwt
+//	jwt.Parse(s, keyfunc, opts...)               jwt.ParseCompat(s, keyfunc, opts...)
+//	jwt.ParseWithClaims(s, claims, keyfunc, ...) jwt.ParseWithClaimsCompat(s, claims, keyfunc, ...)
+//	jwt.Keyfunc                                  jwt.Keyfunc
+//	jwt.ParserOption                             jwt.ParserOption
+//	jwt.WithValidMethods / WithAudience /        同名函数
+//	  WithIssuer / WithSubject / WithLeeway
+//	jwt.NewWithClaims(method, claims)            同名函数
+//	token.SignedString(key)                      同名方法
+//	jwt.GetSigningMethod(alg)                    同名函数
+//	jwt.SigningMethodHS256 / RS256 ...           同名变量
+//	jwt.MapClaims                                同名类型
+//	jwt.ClaimStrings                             同名类型（StandardClaims.Audience）
+//	jwt.StandardClaims (v3)                      jwt.StandardClaims（Id 字段改为 ID）
+//	jwt.RegisteredClaims (v4+)                   同名类型（时间字段为 *NumericDate）
+//	jwt.NumericDate / jwt.NewNumericDate         同名类型与函数
+//	token.Header["alg"] / token.Header["kid"]    token.Header.Algorithm / token.Header.KeyID
+//	jwt.ErrTokenMalformed                        同名变量（即 ErrInvalidToken）
+//	jwt.ErrTokenSignatureInvalid                 同名变量（即 ErrInvalidSignature）
+//	jwt.ErrTokenExpired                          同名变量
+//	jwt.ErrTokenNotValidYet                      同名变量（即 ErrTokenNotYetValid）
+//	jwt.ErrTokenUnverifiable                     同名变量
+//	jwt.ErrTokenInvalidAudience / Issuer /       同名变量
+//	  Subject
+//
+// 与 golang-jwt 不同，解析失败时返回的 *Token 为 nil。
+
+// golang-jwt 同名错误
+var (
+	ErrTokenMalformed        = ErrInvalidToken
+	ErrTokenSignatureInvalid = ErrInvalidSignature
+	ErrTokenNotValidYet      = ErrTokenNotYetValid
+	ErrTokenInvalidAudience  = ErrInvalidAudience
+	ErrTokenInvalidIssuer    = ErrInvalidIssuer
+	ErrTokenInvalidSubject   = ErrInvalidSubject
+	ErrTokenUnverifiable     = errors.New("token is unverifiable")
+)
+
+// Keyfunc 根据未验证的令牌返回验证密钥（与 golang-jwt 的 jwt.Keyfunc 一致）
+type Keyfunc = func(*Token) (interface{}, error)
+
+// ParserOption 解析选项
+type ParserOption func(*parserOptions)
+
+// parserOptions 解析选项集合
+type parserOptions struct {
+	validMethods []string
+	audience     string
+	issuer       string
+	subject      string
+	leeway       time.Duration
+	scopes       []string
+	blacklist    Blacklist
+	now          func() time.Time // 验证 exp / nbf 使用的时间源，nil 表示 time.Now
+}
+
+// newParserOptions 应用解析选项
+func newParserOptions(options []ParserOption) *parserOptions {
+	opts := &parserOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+	return opts
+}
+
+// currentTime 返回验证声明使用的当前时间，解析入口统一通过它获取时间
+func (o *parserOptions) currentTime() time.Time {
+	if o.now != nil {
+		return o.now()
+	}
+	return time.Now()
+}
+
+// WithValidMethods 限制允许的签名算法，如 []string{"RS256", "ES256"}，头部 alg 不在列表中时返回 ErrAlgorithmNotAllowed
+// 无论是否设置，alg 为 "none" 或为空的令牌总被拒绝；同时接受多种算法时，Keyfunc 仍应按 token.Method 返回对应类型的密钥
+func WithValidMethods(methods []string) ParserOption {
+	return func(o *parserOptions) {
+		o.validMethods = methods
+	}
+}
+
+// WithAudience 要求 aud 声明包含指定受众，aud 为数组时任一元素匹配即可
+func WithAudience(audience string) ParserOption {
+	return func(o *parserOptions) {
+		o.audience = audience
+	}
+}
+
+// WithIssuer 要求 iss 声明与指定签发者一致
+func WithIssuer(issuer string) ParserOption {
+	return func(o *parserOptions) {
+		o.issuer = issuer
+	}
+}
+
+// WithSubject 要求 sub 声明与指定主题一致
+func WithSubject(subject string) ParserOption {
+	return func(o *parserOptions) {
+		o.subject = subject
+	}
+}
+
+// 签名方法注册表
+var (
+	signingMethods   = map[string]SigningMethod{}
+	signingMethodsMu sync.RWMutex
+)
+
+func init() {
+	for _, method := range []SigningMethod{
+		SigningMethodHS256, SigningMethodHS384, SigningMethodHS512,
+		SigningMethodRS256, SigningMethodRS384, SigningMethodRS512,
+		SigningMethodES256, SigningMethodES384, SigningMethodES512,
+		SigningMethodPS256, SigningMethodPS384, SigningMethodPS512,
+	} {
+		RegisterSigningMethod(method)
+	}
+}
+
+// RegisterSigningMethod 注册签名方法，供按算法名称查找
+func RegisterSigningMethod(method SigningMethod) {
+	signingMethodsMu.Lock()
+	defer signingMethodsMu.Unlock()
+
+	signingMethods[method.Alg()] = method
+}
+
+// GetSigningMethod 根据算法名称获取签名方法，未注册时返回 nil
+func GetSigningMethod(alg string) SigningMethod {
+	signingMethodsMu.RLock()
+	defer signingMethodsMu.RUnlock()
+
+	return signingMethods[alg]
+}
+
+// ParseCompat 以 golang-jwt 的调用方式解析令牌，签名方法由头部 alg 决定
+func ParseCompat(tokenString string, keyfunc Keyfunc, options ...ParserOption) (*Token, error) {
+	return ParseWithClaimsCompat(tokenString, make(MapClaims), keyfunc, options...)
+}
+
+// ParseWithClaimsCompat 以 golang-jwt 的调用方式解析令牌到指定声明类型
+func ParseWithClaimsCompat(tokenString string, claims Claims, keyfunc Keyfunc, options ...ParserOption) (*Token, error) {
+	opts := newParserOptions(options)
+
+	token, parts, err := decodeToken(tokenString, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	// 确定签名方法
+	alg := token.Header.Algorithm
+	if err := checkAlgorithm(alg, opts.validMethods); err != nil {
+		return nil, err
+	}
+	method := GetSigningMethod(alg)
+	if method == nil {
+		return nil, unexpectedSigningMethod(alg)
+	}
+	token.Method = method
+
+	// 获取验证密钥
+	if keyfunc == nil {
+		return nil, fmt.Errorf("%w: no keyfunc was provided", ErrTokenUnverifiable)
+	}
+	key, err := keyfunc(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTokenUnverifiable, err)
+	}
+
+	// 验证签名
+	if err := method.Verify(parts[0]+"."+parts[1], parts[2], key); err != nil {
+		return nil, err
+	}
+
+	// 验证声明
+	if err := validateClaimsAt(claims, opts.currentTime(), opts.leeway); err != nil {
+		return nil, err
+	}
+	if err := opts.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	token.Valid = true
+	return token, nil
+}
+
+// validateClaims 校验吊销状态、scope 与 aud / iss / sub 选项
+func (o *parserOptions) validateClaims(claims Claims) error {
+	if err := validateRevocation(claims, o.blacklist); err != nil {
+		return err
+	}
+	if err := validateScopes(claims, o.scopes); err != nil {
+		return err
+	}
+	if o.audience == "" && o.issuer == "" && o.subject == "" {
+		return nil
+	}
+
+	var aud []string
+	var iss, sub string
+	switch c := claims.(type) {
+	case MapClaims:
+		aud, _ = GetClaimStringSlice(c, "aud")
+		iss, _ = GetClaimString(c, "iss")
+		sub, _ = GetClaimString(c, "sub")
+	case registeredClaims:
+		std := c.registered()
+		aud, iss, sub = std.Audience, std.Issuer, std.Subject
+	default:
+		return fmt.Errorf("%w: cannot validate claims of type %T", ErrInvalidToken, claims)
+	}
+
+	if o.audience != "" && !containsString(aud, o.audience) {
+		return ErrInvalidAudience
+	}
+	if o.issuer != "" && iss != o.issuer {
+		return ErrInvalidIssuer
+	}
+	if o.subject != "" && sub != o.subject {
+		return ErrInvalidSubject
+	}
+	return nil
+}
+
+// registeredClaims 可提取标准声明的类型，嵌入 StandardClaims 的自定义声明同样满足
+type registeredClaims interface {
+	registered() StandardClaims
+}
+
+// registered 返回标准声明本身
+func (c StandardClaims) registered() StandardClaims {
+	return c
+}
+
+// containsString 判断字符串切片是否包含指定值
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// 迁移对照表的编译期断言：签名不兼容时测试无法编译
+var (
+	_ Keyfunc                                                        = func(*Token) (interface{}, error) { return nil, nil }
+	_ func(string, Keyfunc, ...ParserOption) (*Token, error)         = ParseCompat
+	_ func(string, Claims, Keyfunc, ...ParserOption) (*Token, error) = ParseWithClaimsCompat
+	_ func(SigningMethod, Claims) *Token                             = NewWithClaims
+	_ func(string) SigningMethod                                     = GetSigningMethod
+	_ func([]string) ParserOption                                    = WithValidMethods
+	_ func(string) ParserOption                                      = WithAudience
+	_ func(string) ParserOption                                      = WithIssuer
+	_ func(string) ParserOption                                      = WithSubject
+	_ func(*Token, interface{}) (string, error)                      = (*Token).SignedString
+	_ SigningMethod                                                  = SigningMethodHS256
+	_ Claims                                                         = MapClaims{}
+	_ Claims                                                         = StandardClaims{}
+	_ error                                                          = ErrTokenMalformed
+	_ error                                                          = ErrTokenSignatureInvalid
+	_ error                                                          = ErrTokenNotValidYet
+	_ func(signingString string, key interface{}) (string, error)    = SigningMethodHS256.Sign
+	_ func(signingString, signature string, key interface{}) error   = SigningMethodHS256.Verify
+	_ func(signingString string, key interface{}) (string, error)    = SigningMethodRS256.Sign
+)
+
+var hmacSampleSecret = []byte("my_secret_key")
+
+// 移植自 golang-jwt 示例：Example (Hmac)
+func TestCompatHMACExample(t *testing.T) {
+	// Create a new token object, specifying signing method and the claims
+	// you would like it to contain.
+	token := NewWithClaims(SigningMethodHS256, MapClaims{
+		"foo": "bar",
+		"nbf": time.Date(2015, 10, 10, 12, 0, 0, 0, time.UTC).Unix(),
+	})
+
+	// Sign and get the complete encoded token as a string using the secret
+	tokenString, err := token.SignedString(hmacSampleSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Parse takes the token string and a function for looking up the key.
+	token, err = ParseCompat(tokenString, func(token *Token) (interface{}, error) {
+		// Don't forget to validate the alg is what you expect:
+		if _, ok := token.Method.(*SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("Unexpected signing method: %v", token.Header.Algorithm)
+		}
+
+		// hmacSampleSecret is a []byte containing your secret, e.g. []byte("my_secret_key")
+		return hmacSampleSecret, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if claims, ok := token.Claims.(MapClaims); ok && token.Valid {
+		if claims["foo"] != "bar" {
+			t.Errorf("foo = %v, want bar", claims["foo"])
+		}
+	} else {
+		t.Error("token should be valid")
+	}
+}
+
+// 移植自 golang-jwt 示例：ExampleParseWithClaims_customClaimsType
+func TestCompatCustomClaimsExample(t *testing.T) {
+	type MyCustomClaims struct {
+		Foo string `json:"foo"`
+		StandardClaims
+	}
+
+	claims := MyCustomClaims{
+		"bar",
+		StandardClaims{
+			ExpiresAt: time.Now().Add(24 * time.Hour).Unix(),
+			Issuer:    "test",
+		},
+	}
+	token := NewWithClaims(SigningMethodHS256, claims)
+	tokenString, err := token.SignedString([]byte("AllYourBase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err = ParseWithClaimsCompat(tokenString, &MyCustomClaims{}, func(token *Token) (interface{}, error) {
+		return []byte("AllYourBase"), nil
+	}, WithIssuer("test"), WithValidMethods([]string{"HS256"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if claims, ok := token.Claims.(*MyCustomClaims); ok && token.Valid {
+		if claims.Foo != "bar" || claims.Issuer != "test" {
+			t.Errorf("unexpected claims: %+v", claims)
+		}
+	} else {
+		t.Error("token should be valid")
+	}
+
+	_, err = ParseWithClaimsCompat(tokenString, &MyCustomClaims{}, func(token *Token) (interface{}, error) {
+		return []byte("AllYourBase"), nil
+	}, WithIssuer("other"))
+	if !errors.Is(err, ErrTokenInvalidIssuer) {
+		t.Errorf("expected ErrTokenInvalidIssuer, got %v", err)
+	}
+}
+
+// 移植自 golang-jwt 示例：ExampleParse_errorChecking
+func TestCompatErrorChecking(t *testing.T) {
+	expired, _ := NewWithClaims(SigningMethodHS256, MapClaims{
+		"exp": time.Now().Add(-time.Hour).Unix(),
+	}).SignedString(hmacSampleSecret)
+
+	keyfunc := func(token *Token) (interface{}, error) {
+		return hmacSampleSecret, nil
+	}
+
+	cases := []struct {
+		name        string
+		tokenString string
+		keyfunc     Keyfunc
+		expected    error
+	}{
+		{"malformed", "not-a-token", keyfunc, ErrTokenMalformed},
+		{"expired", expired, keyfunc, ErrTokenExpired},
+		{"bad signature", expired[:len(expired)-2] + "xx", keyfunc, ErrTokenSignatureInvalid},
+		{"keyfunc error", expired, func(*Token) (interface{}, error) { return nil, errors.New("no key") }, ErrTokenUnverifiable},
+	}
+
+	for _, c := range cases {
+		_, err := ParseCompat(c.tokenString, c.keyfunc)
+		switch {
+		case errors.Is(err, ErrTokenMalformed):
+			// That's not even a token
+		case errors.Is(err, ErrTokenSignatureInvalid):
+			// Invalid signature
+		case errors.Is(err, ErrTokenExpired) || errors.Is(err, ErrTokenNotValidYet):
+			// Token is either expired or not active yet
+		}
+		if !errors.Is(err, c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, err)
+		}
+	}
+}
+
+func TestParseKeyErrorsAndTimeSource(t *testing.T) {
+	errNoKey := errors.New("no key")
+	expired, _ := GenerateHS256(hmacSampleSecret, MapClaims{"exp": time.Now().Add(-time.Hour).Unix()})
+	compatKey := func(*Token) (interface{}, error) { return hmacSampleSecret, nil }
+	headerKey := func(*Header) (SigningMethod, interface{}, error) { return SigningMethodHS256, hmacSampleSecret, nil }
+
+	// 密钥函数的错误在两个入口都可以用 errors.Is 判断
+	_, errCompat := ParseCompat(expired, func(*Token) (interface{}, error) { return nil, errNoKey })
+	_, errKeyFunc := ParseWithKeyFunc(expired, func(*Header) (SigningMethod, interface{}, error) { return nil, nil, errNoKey })
+	for name, err := range map[string]error{"ParseCompat": errCompat, "ParseWithKeyFunc": errKeyFunc} {
+		if !errors.Is(err, ErrTokenUnverifiable) || !errors.Is(err, errNoKey) {
+			t.Errorf("%s err = %v, want ErrTokenUnverifiable wrapping the key function error", name, err)
+		}
+	}
+
+	// 各解析入口按解析选项的时间源验证 exp
+	past := func(o *parserOptions) { o.now = fixedClock(time.Now().Add(-2 * time.Hour)) }
+	_, errCompat = ParseCompat(expired, compatKey, past)
+	_, errKeyFunc = ParseWithKeyFunc(expired, headerKey, past)
+	_, errOptions := ParseWithOptions(SigningMethodHS256, expired, hmacSampleSecret, past)
+	for name, err := range map[string]error{"ParseCompat": errCompat, "ParseWithKeyFunc": errKeyFunc, "ParseWithOptions": errOptions} {
+		if err != nil {
+			t.Errorf("%s with past time source err = %v", name, err)
+		}
+	}
+	if _, err := ParseCompat(expired, compatKey); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("default time source err = %v, want ErrTokenExpired", err)
+	}
+}
+
+func TestCompatValidMethods(t *testing.T) {
+	tokenString, _ := NewWithClaims(SigningMethodHS512, MapClaims{"sub": "1"}).SignedString(hmacSampleSecret)
+
+	_, err := ParseCompat(tokenString, func(*Token) (interface{}, error) {
+		return hmacSampleSecret, nil
+	}, WithValidMethods([]string{"HS256"}))
+	if err == nil {
+		t.Error("HS512 token should be rejected when only HS256 is allowed")
+	}
+
+	if GetSigningMethod("HS512") != SigningMethodHS512 || GetSigningMethod("unknown") != nil {
+		t.Error("GetSigningMethod returned unexpected result")
+	}
+}
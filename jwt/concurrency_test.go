@@ -0,0 +1,157 @@
+package jwt
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 以下测试需配合 go test -race 运行才能发现数据竞争
+
+func TestJWTConcurrentGenerateParse(t *testing.T) {
+	j := New(SigningMethodHS256, []byte("concurrent-secret")).WithStampPolicy(StampPolicy{
+		AutoIssuedAt: true,
+		AutoJTI:      true,
+		DefaultTTL:   time.Hour,
+	})
+
+	const workers, rounds = 16, 50
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				sub := fmt.Sprintf("user-%d-%d", w, i)
+				token, err := j.Generate(MapClaims{"sub": sub})
+				if err != nil {
+					errs <- err
+					return
+				}
+				parsed, err := j.Parse(token)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if got, _ := GetClaimString(parsed.Claims.(MapClaims), "sub"); got != sub {
+					errs <- fmt.Errorf("sub = %q, want %q", got, sub)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestGlobalFunctionsConcurrent(t *testing.T) {
+	secret := []byte("global-secret")
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				token, err := GenerateHS256(secret, MapClaims{"n": float64(w*100 + i)})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if _, err := ParseHS256(token, secret); err != nil {
+					t.Error(err)
+					return
+				}
+				if GetSigningMethod("HS256") == nil {
+					t.Error("HS256 not registered")
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+func TestWithMethodsReturnCopies(t *testing.T) {
+	base := New(SigningMethodHS256, []byte("secret"))
+	stamped := base.WithStampPolicy(StampPolicy{AutoJTI: true})
+	clocked := stamped.WithClock(func() time.Time { return time.Unix(0, 0) })
+
+	if base == stamped || stamped == clocked {
+		t.Fatal("With methods must return new instances")
+	}
+	if base.stampPolicy != (StampPolicy{}) || base.now != nil {
+		t.Error("WithStampPolicy / WithClock modified the original instance")
+	}
+	if stamped.now != nil {
+		t.Error("WithClock modified the instance it was called on")
+	}
+	if clocked.stampPolicy != (StampPolicy{AutoJTI: true}) {
+		t.Error("WithClock dropped the stamp policy")
+	}
+}
+
+func TestJWTBuilderForkConcurrent(t *testing.T) {
+	secret := []byte("builder-secret")
+	template := NewBuilder(SigningMethodHS256, secret).
+		SetIssuer("gateway").
+		SetAudience("api").
+		SetExpirationFromNow(time.Hour)
+
+	const workers = 16
+	var wg sync.WaitGroup
+	tokens := make([]string, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			token, err := template.Fork().
+				SetSubject(fmt.Sprintf("user-%d", w)).
+				SetClaim("worker", w).
+				Build()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			tokens[w] = token
+		}(w)
+	}
+	wg.Wait()
+
+	for w, token := range tokens {
+		parsed, err := ParseHS256(token, secret)
+		if err != nil {
+			t.Fatalf("worker %d: %v", w, err)
+		}
+		claims := parsed.Claims.(MapClaims)
+		if sub, _ := GetClaimString(claims, "sub"); sub != fmt.Sprintf("user-%d", w) {
+			t.Errorf("worker %d: sub = %q", w, sub)
+		}
+		if iss, _ := GetClaimString(claims, "iss"); iss != "gateway" {
+			t.Errorf("worker %d: iss = %q", w, iss)
+		}
+	}
+
+	// 模板本身不受 Fork 副本修改的影响
+	if _, exists := template.claims["sub"]; exists {
+		t.Error("Fork modified the template claims")
+	}
+}
+
+func TestJWTBuilderBuildTokenSnapshot(t *testing.T) {
+	b := NewBuilder(SigningMethodHS256, []byte("secret")).SetSubject("first")
+	token, err := b.BuildToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.SetSubject("second").SetClaim("extra", true)
+
+	claims := token.Claims.(MapClaims)
+	if claims["sub"] != "first" || claims["extra"] != nil {
+		t.Errorf("token claims changed after builder modification: %v", claims)
+	}
+}
@@ -0,0 +1,183 @@
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ClaimChangeKind 声明变化类型
+type ClaimChangeKind string
+
+const (
+	ClaimAdded   ClaimChangeKind = "added"
+	ClaimRemoved ClaimChangeKind = "removed"
+	ClaimChanged ClaimChangeKind = "changed"
+)
+
+// timeClaims 按时间戳渲染的声明
+var timeClaims = map[string]bool{"exp": true, "nbf": true, "iat": true, "auth_time": true}
+
+// ClaimChange 单个声明的变化
+type ClaimChange struct {
+	Path   string          // 声明路径，嵌套对象以 "." 连接，如 "profile.role"
+	Kind   ClaimChangeKind // 变化类型
+	Old    interface{}     // 旧值，新增时为 nil
+	New    interface{}     // 新值，删除时为 nil
+	IsTime bool            // 是否为时间声明（exp / nbf / iat / auth_time）
+	Delta  time.Duration   // 时间声明的变化量（新值减旧值），仅 IsTime 且两侧均存在时有效
+}
+
+// ClaimsDiff 两组声明的差异，Changes 按路径排序
+type ClaimsDiff struct {
+	Changes []ClaimChange
+}
+
+// IsEmpty 是否没有差异
+func (d ClaimsDiff) IsEmpty() bool {
+	return len(d.Changes) == 0
+}
+
+// String 以稳定顺序渲染差异，每行一个变化：
+// 新增以 "+" 开头，删除以 "-" 开头，变化以 "~" 开头并显示 "旧值 -> 新值"
+func (d ClaimsDiff) String() string {
+	var b strings.Builder
+	for _, c := range d.Changes {
+		switch c.Kind {
+		case ClaimAdded:
+			fmt.Fprintf(&b, "+ %s: %s\n", c.Path, c.render(c.New))
+		case ClaimRemoved:
+			fmt.Fprintf(&b, "- %s: %s\n", c.Path, c.render(c.Old))
+		case ClaimChanged:
+			fmt.Fprintf(&b, "~ %s: %s -> %s", c.Path, c.render(c.Old), c.render(c.New))
+			if c.IsTime {
+				sign := "+"
+				if c.Delta < 0 {
+					sign = ""
+				}
+				fmt.Fprintf(&b, " (%s%s)", sign, c.Delta)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// render 渲染单个值，时间声明渲染为 RFC3339 时间
+func (c ClaimChange) render(v interface{}) string {
+	if c.IsTime {
+		if sec, ok := claimNumber(v); ok {
+			return time.Unix(int64(sec), 0).UTC().Format(time.RFC3339)
+		}
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// DiffClaims 比较两组声明，报告新增、删除和变化的声明
+// 嵌套对象递归比较，数组整体比较
+func DiffClaims(a, b MapClaims) ClaimsDiff {
+	var changes []ClaimChange
+	diffClaimMaps(a, b, "", &changes)
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return ClaimsDiff{Changes: changes}
+}
+
+// DiffTokens 解码两个令牌的声明（不验证签名）并比较
+func DiffTokens(tokenA, tokenB string) (ClaimsDiff, error) {
+	a, err := DecodeClaims(tokenA)
+	if err != nil {
+		return ClaimsDiff{}, fmt.Errorf("decode first token: %w", err)
+	}
+	b, err := DecodeClaims(tokenB)
+	if err != nil {
+		return ClaimsDiff{}, fmt.Errorf("decode second token: %w", err)
+	}
+	return DiffClaims(a, b), nil
+}
+
+// diffClaimMaps 递归比较两个声明对象
+func diffClaimMaps(a, b map[string]interface{}, prefix string, changes *[]ClaimChange) {
+	for key, av := range a {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		isTime := prefix == "" && timeClaims[key]
+
+		bv, exists := b[key]
+		if !exists {
+			*changes = append(*changes, ClaimChange{Path: path, Kind: ClaimRemoved, Old: av, IsTime: isTime})
+			continue
+		}
+
+		am, aIsMap := av.(map[string]interface{})
+		bm, bIsMap := bv.(map[string]interface{})
+		if aIsMap && bIsMap {
+			diffClaimMaps(am, bm, path, changes)
+			continue
+		}
+
+		if claimValuesEqual(av, bv) {
+			continue
+		}
+		change := ClaimChange{Path: path, Kind: ClaimChanged, Old: av, New: bv, IsTime: isTime}
+		if isTime {
+			oldSec, oldOk := claimNumber(av)
+			newSec, newOk := claimNumber(bv)
+			if oldOk && newOk {
+				change.Delta = time.Duration(int64(newSec)-int64(oldSec)) * time.Second
+			}
+		}
+		*changes = append(*changes, change)
+	}
+
+	for key, bv := range b {
+		if _, exists := a[key]; exists {
+			continue
+		}
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		*changes = append(*changes, ClaimChange{Path: path, Kind: ClaimAdded, New: bv, IsTime: prefix == "" && timeClaims[key]})
+	}
+}
+
+// claimValuesEqual 比较两个声明值，数值按大小比较（int64 与 float64 视为相同类型）
+func claimValuesEqual(a, b interface{}) bool {
+	if an, ok := claimNumber(a); ok {
+		bn, ok := claimNumber(b)
+		return ok && an == bn
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// claimNumber 将数值声明转换为 float64
+func claimNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
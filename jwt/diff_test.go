@@ -0,0 +1,82 @@
+package jwt
+
+import (
+	"flag"
+	"os"
+	"testing"
+	"time"
+)
+
+var updateGolden = flag.Bool("update", false, "更新 testdata 中的快照文件")
+
+func diffFixtureTokens(t *testing.T) (string, string) {
+	t.Helper()
+	issuedAt := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	old, err := GenerateHS256(hmacSampleSecret, MapClaims{
+		"sub":   "user-42",
+		"iat":   issuedAt.Unix(),
+		"exp":   issuedAt.Add(time.Hour).Unix(),
+		"roles": []interface{}{"admin", "editor"},
+		"org":   map[string]interface{}{"id": "acme", "plan": "pro", "region": "eu"},
+		"email": "old@example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := GenerateHS256([]byte("another-secret"), MapClaims{
+		"sub":   "user-42",
+		"iat":   issuedAt.Add(24 * time.Hour).Unix(),
+		"exp":   issuedAt.Add(25 * time.Hour).Unix(),
+		"roles": []interface{}{"editor"},
+		"org":   map[string]interface{}{"id": "acme", "plan": "free", "seats": 3},
+		"scope": "read",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return old, updated
+}
+
+func TestDiffTokensSnapshot(t *testing.T) {
+	old, updated := diffFixtureTokens(t)
+
+	diff, err := DiffTokens(old, updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden := "testdata/claims_diff.golden"
+	if *updateGolden {
+		if err := os.WriteFile(golden, []byte(diff.String()), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.String() != string(expected) {
+		t.Errorf("diff does not match snapshot:\n got:\n%s\nwant:\n%s", diff.String(), expected)
+	}
+
+	for _, c := range diff.Changes {
+		if c.Path == "exp" && (!c.IsTime || c.Delta != 24*time.Hour) {
+			t.Errorf("exp change = %+v, want 24h time delta", c)
+		}
+	}
+}
+
+func TestDiffClaims(t *testing.T) {
+	a := MapClaims{"sub": "1", "exp": int64(100), "nested": map[string]interface{}{"a": 1}}
+	b := MapClaims{"sub": "1", "exp": float64(100), "nested": map[string]interface{}{"a": 1}}
+
+	if diff := DiffClaims(a, b); !diff.IsEmpty() {
+		t.Errorf("int64 and float64 of same value should not differ: %s", diff)
+	}
+
+	if _, err := DiffTokens("bad", "token"); err == nil {
+		t.Error("DiffTokens should fail on malformed token")
+	}
+}
@@ -0,0 +1,157 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+)
+
+// jwt.io 上的 ES256 示例令牌及其公钥，由其他 JWT 库签发
+const (
+	interopES256Token = "eyJhbGciOiJFUzI1NiIsInR5cCI6IkpXVCJ9." +
+		"eyJzdWIiOiIxMjM0NTY3ODkwIiwibmFtZSI6IkpvaG4gRG9lIiwiYWRtaW4iOnRydWUsImlhdCI6MTUxNjIzOTAyMn0." +
+		"tyh-VfuzIxCyGYDlkBA7DfyjrqmSHu6pQ2hoZuFqUSLPNY2N0mpHb3nk5K17HWP_3cYHBw7AhHale5wky6-sVA"
+	interopES256PublicKey = `-----BEGIN PUBLIC KEY-----
+MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEEVs/o5+uQbTjL3chynL4wXgUg2R9
+q9UU8I5mEovUf86QZ7kOBIjJwqnzD1omageEHWwHdBO6B+dFabmdT9POxg==
+-----END PUBLIC KEY-----`
+)
+
+func TestECDSAInterop(t *testing.T) {
+	token, err := Parse(SigningMethodES256, interopES256Token, []byte(interopES256PublicKey))
+	if err != nil {
+		t.Fatalf("Failed to parse interop token: %v", err)
+	}
+	claims, _ := ExtractClaims(token)
+	if name, _ := GetClaimString(claims, "name"); name != "John Doe" {
+		t.Errorf("name = %q, want John Doe", name)
+	}
+
+	// 篡改声明后签名不再有效
+	tampered := interopES256Token[:40] + "x" + interopES256Token[41:]
+	if _, err := Parse(SigningMethodES256, tampered, []byte(interopES256PublicKey)); err == nil {
+		t.Error("tampered token should not verify")
+	}
+}
+
+func TestECDSAMethods(t *testing.T) {
+	tests := []struct {
+		method *SigningMethodECDSA
+		curve  elliptic.Curve
+		sigLen int
+	}{
+		{SigningMethodES256, elliptic.P256(), 64},
+		{SigningMethodES384, elliptic.P384(), 96},
+		{SigningMethodES512, elliptic.P521(), 132},
+	}
+	for _, tt := range tests {
+		privateKey, err := GenerateECDSAKeyPair(tt.curve)
+		if err != nil {
+			t.Fatalf("%s: failed to generate key: %v", tt.method.Alg(), err)
+		}
+		claims := MapClaims{"sub": "test-user", "exp": time.Now().Add(time.Hour).Unix()}
+
+		tokenString, err := Generate(tt.method, privateKey, claims)
+		if err != nil {
+			t.Fatalf("%s: failed to generate token: %v", tt.method.Alg(), err)
+		}
+		token, err := Parse(tt.method, tokenString, &privateKey.PublicKey)
+		if err != nil {
+			t.Fatalf("%s: failed to parse token: %v", tt.method.Alg(), err)
+		}
+		sig, _ := base64URLDecode(token.Signature)
+		if len(sig) != tt.sigLen {
+			t.Errorf("%s: signature is %d bytes, want %d", tt.method.Alg(), len(sig), tt.sigLen)
+		}
+
+		// 构建器使用相同的签名方法
+		built, err := NewBuilder(tt.method, privateKey).SetSubject("builder").Build()
+		if err != nil {
+			t.Fatalf("%s: builder failed: %v", tt.method.Alg(), err)
+		}
+		if _, err := Parse(tt.method, built, privateKey); err != nil {
+			t.Errorf("%s: builder token did not verify: %v", tt.method.Alg(), err)
+		}
+	}
+}
+
+func TestECDSAPEMKeys(t *testing.T) {
+	privateKey, _ := GenerateECDSAKeyPair(elliptic.P256())
+	der, _ := x509.MarshalECPrivateKey(privateKey)
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+	pubDER, _ := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+
+	parsed, err := ParseECPrivateKeyFromPEM(privatePEM)
+	if err != nil || !parsed.Equal(privateKey) {
+		t.Fatalf("ParseECPrivateKeyFromPEM = %v, %v", parsed, err)
+	}
+	pub, err := ParseECPublicKeyFromPEM(publicPEM)
+	if err != nil || !pub.Equal(&privateKey.PublicKey) {
+		t.Fatalf("ParseECPublicKeyFromPEM = %v, %v", pub, err)
+	}
+
+	tokenString, err := Generate(SigningMethodES256, privatePEM, MapClaims{"sub": "pem"})
+	if err != nil {
+		t.Fatalf("Failed to generate token with PEM key: %v", err)
+	}
+	if _, err := Parse(SigningMethodES256, tokenString, publicPEM); err != nil {
+		t.Errorf("Failed to parse token with PEM key: %v", err)
+	}
+
+	rsaKey, _ := GenerateRSAKeyPair(2048)
+	if _, err := ParseECPrivateKeyFromPEM(PrivateKeyToPEM(rsaKey)); err == nil {
+		t.Error("RSA key should not parse as EC private key")
+	}
+}
+
+func TestECDSAKeyMismatch(t *testing.T) {
+	p256, _ := GenerateECDSAKeyPair(elliptic.P256())
+	p384, _ := GenerateECDSAKeyPair(elliptic.P384())
+	claims := MapClaims{"sub": "test-user"}
+
+	if _, err := Generate(SigningMethodES256, p384, claims); !errors.Is(err, ErrInvalidKeyType) {
+		t.Errorf("ES256 with P-384 key: err = %v, want ErrInvalidKeyType", err)
+	}
+	if _, err := Generate(SigningMethodES256, "secret", claims); !errors.Is(err, ErrInvalidKeyType) {
+		t.Errorf("ES256 with string key: err = %v, want ErrInvalidKeyType", err)
+	}
+	if _, err := Generate(SigningMethodES256, []byte("secret"), claims); !errors.Is(err, ErrKeyMustBePEM) {
+		t.Errorf("ES256 with HMAC secret: err = %v, want ErrKeyMustBePEM", err)
+	}
+
+	tokenString, _ := Generate(SigningMethodES256, p256, claims)
+	other, _ := GenerateECDSAKeyPair(elliptic.P256())
+	if _, err := Parse(SigningMethodES256, tokenString, &other.PublicKey); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("wrong key: err = %v, want ErrInvalidSignature", err)
+	}
+	var nilKey *ecdsa.PublicKey
+	if _, err := Parse(SigningMethodES256, tokenString, nilKey); !errors.Is(err, ErrInvalidKeyType) {
+		t.Errorf("nil key: err = %v, want ErrInvalidKeyType", err)
+	}
+}
+
+func TestECDSACompat(t *testing.T) {
+	for _, alg := range []string{"ES256", "ES384", "ES512"} {
+		if GetSigningMethod(alg) == nil {
+			t.Errorf("GetSigningMethod(%q) = nil", alg)
+		}
+	}
+
+	token, err := ParseCompat(interopES256Token, func(token *Token) (interface{}, error) {
+		if _, ok := token.Method.(*SigningMethodECDSA); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return ParseECPublicKeyFromPEM([]byte(interopES256PublicKey))
+	}, WithValidMethods([]string{"ES256"}))
+	if err != nil {
+		t.Fatalf("ParseCompat failed: %v", err)
+	}
+	if !token.Valid || token.Method != SigningMethodES256 {
+		t.Errorf("token = %+v", token)
+	}
+}
@@ -96,7 +96,7 @@ func genericAPIExample() {
 	standardClaims := &jwt.StandardClaims{
 		Subject:   "user456",
 		Issuer:    "go-util-jwt",
-		Audience:  "web-app",
+		Audience:  jwt.ClaimStrings{"web-app"},
 		ExpiresAt: time.Now().Add(time.Hour * 2).Unix(),
 		IssuedAt:  time.Now().Unix(),
 	}
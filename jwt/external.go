@@ -0,0 +1,43 @@
+package jwt
+
+import "fmt"
+
+// 外部签名：私钥保存在 HSM / KMS 中时，分两步生成令牌
+//
+//	input, _ := jwt.SigningInput(jwt.SigningMethodES256, claims, nil)
+//	sig := hsm.Sign(sha256(input))             // 由外部系统计算签名
+//	token := jwt.AssembleToken(input, sig)
+//
+// rawSignature 必须是 JWS 规定的原始签名字节（未经 base64 编码）：
+//   - RS256/384/512：RSASSA-PKCS1-v1_5 签名，长度等于模数字节数
+//   - PS256/384/512：RSASSA-PSS 签名，盐长度必须等于哈希长度
+//   - ES256/384/512：定长 R||S 编码（ES256 为 64 字节），不是 ASN.1 DER；
+//     HSM 通常输出 DER，需要先转换
+//   - HS256/384/512：HMAC 摘要
+//
+// 验证方不受影响，使用普通的 Parse 即可。
+
+// SigningInput 编码头部与声明，返回待签名的 header.payload 字符串
+// header 为 nil 时使用默认头部；header.Algorithm 为空时填入 method.Alg()，与签名方法不一致时返回错误
+func SigningInput(method SigningMethod, claims Claims, header *Header) (string, error) {
+	token := NewWithClaims(method, claims)
+	if header != nil {
+		h := *header
+		if h.Type == "" {
+			h.Type = "JWT"
+		}
+		if h.Algorithm == "" {
+			h.Algorithm = method.Alg()
+		}
+		if h.Algorithm != method.Alg() {
+			return "", fmt.Errorf("header alg %q does not match signing method %s", h.Algorithm, method.Alg())
+		}
+		token.Header = &h
+	}
+	return token.signingString()
+}
+
+// AssembleToken 将外部计算的原始签名进行 base64url 编码，并与 SigningInput 的结果拼接为完整令牌
+func AssembleToken(input string, rawSignature []byte) string {
+	return input + "." + base64URLEncode(rawSignature)
+}
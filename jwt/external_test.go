@@ -0,0 +1,88 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExternalSigning(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	claims := MapClaims{"sub": "hsm-user", "exp": time.Now().Add(time.Hour).Unix()}
+
+	// 模拟 HSM：在包外使用标准库签名
+	signRSA := func(input string) []byte {
+		digest := sha256.Sum256([]byte(input))
+		sig, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sig
+	}
+	signEC := func(input string) []byte {
+		digest := sha256.Sum256([]byte(input))
+		r, s, err := ecdsa.Sign(rand.Reader, ecKey, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig := make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+		return sig
+	}
+
+	tests := []struct {
+		method SigningMethod
+		sign   func(string) []byte
+		verify interface{}
+	}{
+		{SigningMethodRS256, signRSA, &rsaKey.PublicKey},
+		{SigningMethodES256, signEC, &ecKey.PublicKey},
+	}
+	for _, tt := range tests {
+		input, err := SigningInput(tt.method, claims, &Header{KeyID: "hsm-1"})
+		if err != nil {
+			t.Fatalf("%s: SigningInput failed: %v", tt.method.Alg(), err)
+		}
+		tokenString := AssembleToken(input, tt.sign(input))
+
+		token, err := Parse(tt.method, tokenString, tt.verify)
+		if err != nil {
+			t.Fatalf("%s: assembled token did not verify: %v", tt.method.Alg(), err)
+		}
+		if token.Header.KeyID != "hsm-1" || token.Header.Type != "JWT" {
+			t.Errorf("%s: header = %+v", tt.method.Alg(), token.Header)
+		}
+		if !strings.HasPrefix(tokenString, input+".") {
+			t.Errorf("%s: token does not start with signing input", tt.method.Alg())
+		}
+	}
+}
+
+func TestSigningInputMatchesSignedString(t *testing.T) {
+	secret := []byte("secret")
+	claims := MapClaims{"sub": "user"}
+
+	tokenString, _ := GenerateHS256(secret, claims)
+	input, err := SigningInput(SigningMethodHS256, claims, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(tokenString, input+".") {
+		t.Errorf("signing input %q is not a prefix of %q", input, tokenString)
+	}
+}
+
+func TestSigningInputAlgorithmMismatch(t *testing.T) {
+	_, err := SigningInput(SigningMethodES256, MapClaims{}, &Header{Algorithm: "RS256"})
+	if err == nil || !strings.Contains(err.Error(), "RS256") {
+		t.Errorf("err = %v, want alg mismatch", err)
+	}
+}
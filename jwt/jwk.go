@@ -0,0 +1,411 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// JWKS：从 Auth0、Keycloak 等身份服务的 JWKS 端点获取公钥并验证令牌（RFC 7517）
+//
+//	keys := jwt.NewCachedKeySet("https://example.auth0.com/.well-known/jwks.json", time.Hour)
+//	token, err := jwt.ParseWithKeyFunc(tokenString, keys.KeyFunc(), jwt.WithAudience("api"))
+//
+// 签名方法由头部 alg 决定，但必须与 JWK 的密钥类型（以及声明时的 alg）相符，
+// 因此 JWKS 中的 RSA 公钥不会被当作 HMAC 密钥使用。
+
+// ErrKeyNotFound 密钥集中没有对应 kid 的密钥
+var ErrKeyNotFound = errors.New("key not found")
+
+// jwksHTTPClient 获取 JWKS 使用的 HTTP 客户端
+var jwksHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// maxJWKSSize JWKS 文档大小上限
+const maxJWKSSize = 1 << 20
+
+// minJWKSRefreshInterval 遇到未知 kid 时两次强制刷新之间的最小间隔
+const minJWKSRefreshInterval = 30 * time.Second
+
+// JWK JSON Web Key，包含 RSA（n/e）与 EC（crv/x/y）公钥参数
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid,omitempty"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+// KeySet JWK 密钥集
+type KeySet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// ParseJWKS 解析 JWKS 文档
+func ParseJWKS(data []byte) (*KeySet, error) {
+	var set KeySet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parse JWKS: %w", err)
+	}
+	if set.Keys == nil {
+		return nil, fmt.Errorf("parse JWKS: missing \"keys\" member")
+	}
+	return &set, nil
+}
+
+// FetchJWKS 从 URL 获取并解析 JWKS 文档
+func FetchJWKS(url string) (*KeySet, error) {
+	resp, err := jwksHTTPClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch JWKS: %s returned %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetch JWKS: %w", err)
+	}
+	if len(data) > maxJWKSSize {
+		return nil, fmt.Errorf("fetch JWKS: document exceeds %d bytes", maxJWKSSize)
+	}
+	return ParseJWKS(data)
+}
+
+// Key 返回 kid 对应的公钥（*rsa.PublicKey 或 *ecdsa.PublicKey）
+// kid 为空且密钥集只有一个签名密钥时返回该密钥；找不到时返回 ErrKeyNotFound
+func (s *KeySet) Key(kid string) (interface{}, error) {
+	jwk, err := s.lookup(kid)
+	if err != nil {
+		return nil, err
+	}
+	return jwk.PublicKey()
+}
+
+// KeyFunc 返回用于 ParseWithKeyFunc 的回调，按头部 kid 选择密钥
+func (s *KeySet) KeyFunc() func(header *Header) (SigningMethod, interface{}, error) {
+	return func(header *Header) (SigningMethod, interface{}, error) {
+		jwk, err := s.lookup(header.KeyID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return jwk.verificationKey(header.Algorithm)
+	}
+}
+
+// lookup 查找用于签名验证的 JWK，use 为 enc 的密钥被忽略
+func (s *KeySet) lookup(kid string) (*JWK, error) {
+	var only *JWK
+	count := 0
+	for i := range s.Keys {
+		jwk := &s.Keys[i]
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		if kid != "" && jwk.KeyID == kid {
+			return jwk, nil
+		}
+		only = jwk
+		count++
+	}
+	if kid == "" && count == 1 {
+		return only, nil
+	}
+	if kid == "" {
+		return nil, fmt.Errorf("%w: token has no kid and the key set has %d signing keys", ErrKeyNotFound, count)
+	}
+	return nil, fmt.Errorf("%w: kid %q", ErrKeyNotFound, kid)
+}
+
+// PublicKey 根据 JWK 参数构造公钥，支持 RSA 与 EC（P-256、P-384、P-521）
+func (k *JWK) PublicKey() (interface{}, error) {
+	switch k.KeyType {
+	case "RSA":
+		return k.rsaPublicKey()
+	case "EC":
+		return k.ecPublicKey()
+	default:
+		return nil, fmt.Errorf("%w: unsupported JWK key type %q", ErrInvalidKeyType, k.KeyType)
+	}
+}
+
+func (k *JWK) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := decodeJWKInt("n", k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := decodeJWKInt("e", k.E)
+	if err != nil {
+		return nil, err
+	}
+	if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("%w: JWK RSA exponent out of range", ErrInvalidKeyType)
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func (k *JWK) ecPublicKey() (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch k.Curve {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("%w: unsupported JWK curve %q", ErrInvalidKeyType, k.Curve)
+	}
+	x, err := decodeJWKInt("x", k.X)
+	if err != nil {
+		return nil, err
+	}
+	y, err := decodeJWKInt("y", k.Y)
+	if err != nil {
+		return nil, err
+	}
+	if !curve.IsOnCurve(x, y) {
+		return nil, fmt.Errorf("%w: JWK point is not on curve %s", ErrInvalidKeyType, k.Curve)
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+// verificationKey 返回与头部 alg 对应的签名方法与公钥
+// alg 必须与 JWK 声明的 alg（若有）以及密钥类型一致
+func (k *JWK) verificationKey(alg string) (SigningMethod, interface{}, error) {
+	if k.Algorithm != "" && k.Algorithm != alg {
+		return nil, nil, fmt.Errorf("%w: key %q is for %s, token uses %s", ErrAlgorithmNotAllowed, k.KeyID, k.Algorithm, alg)
+	}
+	method := GetSigningMethod(alg)
+	switch m := method.(type) {
+	case *SigningMethodRSA, *SigningMethodRSAPSS:
+		if k.KeyType != "RSA" {
+			return nil, nil, fmt.Errorf("%w: %s requires an RSA key, key %q is %s", ErrInvalidKeyType, alg, k.KeyID, k.KeyType)
+		}
+	case *SigningMethodECDSA:
+		if k.KeyType != "EC" || k.Curve != m.CurveName {
+			return nil, nil, fmt.Errorf("%w: %s requires an EC %s key, key %q is %s %s", ErrInvalidKeyType, alg, m.CurveName, k.KeyID, k.KeyType, k.Curve)
+		}
+	default:
+		return nil, nil, unexpectedSigningMethod(alg)
+	}
+
+	key, err := k.PublicKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	return method, key, nil
+}
+
+// decodeJWKInt 解码 base64url 编码的无符号大整数参数
+func decodeJWKInt(name, value string) (*big.Int, error) {
+	if value == "" {
+		return nil, fmt.Errorf("%w: JWK is missing %q", ErrInvalidKeyType, name)
+	}
+	data, err := base64URLDecode(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: JWK %q: %v", ErrInvalidKeyType, name, err)
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+// NewJWK 由公钥构造用于签名验证的 JWK（use 为 sig），私钥只导出其公钥部分
+// alg 按密钥推断：RSA 为 RS256，EC 按曲线为 ES256 / ES384 / ES512；使用 PS256 等算法时修改返回值的 Algorithm。
+// kid 为空时使用 RFC 7638 JWK 指纹
+func NewJWK(key interface{}, kid string) (JWK, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if k == nil {
+			return JWK{}, fmt.Errorf("%w: nil RSA private key", ErrInvalidKeyType)
+		}
+		return NewJWK(&k.PublicKey, kid)
+	case *ecdsa.PrivateKey:
+		if k == nil {
+			return JWK{}, fmt.Errorf("%w: nil EC private key", ErrInvalidKeyType)
+		}
+		return NewJWK(&k.PublicKey, kid)
+	}
+
+	if kid == "" {
+		var err error
+		if kid, err = KeyIDFromKey(key); err != nil {
+			return JWK{}, err
+		}
+	}
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if k == nil || k.N == nil {
+			return JWK{}, fmt.Errorf("%w: nil RSA public key", ErrInvalidKeyType)
+		}
+		return JWK{
+			KeyType:   "RSA",
+			KeyID:     kid,
+			Use:       "sig",
+			Algorithm: SigningMethodRS256.Alg(),
+			N:         base64URLEncode(k.N.Bytes()),
+			E:         base64URLEncode(big.NewInt(int64(k.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		if k == nil || k.Curve == nil {
+			return JWK{}, fmt.Errorf("%w: nil EC public key", ErrInvalidKeyType)
+		}
+		var method *SigningMethodECDSA
+		for _, m := range []*SigningMethodECDSA{SigningMethodES256, SigningMethodES384, SigningMethodES512} {
+			if m.CurveName == k.Curve.Params().Name {
+				method = m
+			}
+		}
+		if method == nil {
+			return JWK{}, fmt.Errorf("%w: unsupported EC curve %q", ErrInvalidKeyType, k.Curve.Params().Name)
+		}
+		// 坐标按曲线长度补齐前导零（RFC 7518 6.2.1.2）
+		return JWK{
+			KeyType:   "EC",
+			KeyID:     kid,
+			Use:       "sig",
+			Algorithm: method.Alg(),
+			Curve:     method.CurveName,
+			X:         base64URLEncode(k.X.FillBytes(make([]byte, method.KeySize))),
+			Y:         base64URLEncode(k.Y.FillBytes(make([]byte, method.KeySize))),
+		}, nil
+	default:
+		return JWK{}, fmt.Errorf("%w: cannot export %T as JWK", ErrInvalidKeyType, key)
+	}
+}
+
+// PublicKeyToJWK 将 RSA 或 EC 密钥的公钥部分编码为 JWK JSON，规则同 NewJWK
+func PublicKeyToJWK(key interface{}, kid string) ([]byte, error) {
+	jwk, err := NewJWK(key, kid)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jwk)
+}
+
+// KeySetFromKeys 由多个密钥构造 JWKS，kid 使用各密钥的 JWK 指纹，与 AutoKeyID 签发的令牌对应
+// 用 json.Marshal 编码后即可作为 JWKS 端点的响应
+func KeySetFromKeys(keys ...interface{}) (*KeySet, error) {
+	set := &KeySet{Keys: make([]JWK, 0, len(keys))}
+	seen := make(map[string]bool, len(keys))
+	for i, key := range keys {
+		jwk, err := NewJWK(key, "")
+		if err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
+		if seen[jwk.KeyID] {
+			return nil, fmt.Errorf("key %d: duplicate key %q", i, jwk.KeyID)
+		}
+		seen[jwk.KeyID] = true
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set, nil
+}
+
+// CachedKeySet 带缓存的远程 JWKS
+//
+// 密钥集在 TTL 过期后的首次使用时重新获取；遇到未知 kid（签发方轮换了密钥）时立即刷新，
+// 但两次强制刷新至少间隔 30 秒，避免伪造的 kid 触发大量请求。
+// 刷新失败时继续使用已缓存的密钥集。CachedKeySet 可被多个 goroutine 并发使用。
+type CachedKeySet struct {
+	url string
+	ttl time.Duration
+	now func() time.Time
+
+	mu        sync.Mutex
+	set       *KeySet
+	fetchedAt time.Time
+}
+
+// NewCachedKeySet 创建带缓存的 JWKS，首次使用时获取
+func NewCachedKeySet(url string, ttl time.Duration) *CachedKeySet {
+	return &CachedKeySet{url: url, ttl: ttl, now: time.Now}
+}
+
+// KeySet 返回缓存的密钥集，TTL 过期时重新获取
+func (c *CachedKeySet) KeySet() (*KeySet, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.set != nil && c.now().Sub(c.fetchedAt) < c.ttl {
+		return c.set, nil
+	}
+	if err := c.refreshLocked(); err != nil && c.set == nil {
+		return nil, err
+	}
+	return c.set, nil
+}
+
+// Refresh 立即重新获取密钥集
+func (c *CachedKeySet) Refresh() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.refreshLocked()
+}
+
+// Key 返回 kid 对应的公钥，缓存中没有时尝试刷新一次
+func (c *CachedKeySet) Key(kid string) (interface{}, error) {
+	jwk, err := c.lookup(kid)
+	if err != nil {
+		return nil, err
+	}
+	return jwk.PublicKey()
+}
+
+// KeyFunc 返回用于 ParseWithKeyFunc 的回调，按头部 kid 选择密钥
+func (c *CachedKeySet) KeyFunc() func(header *Header) (SigningMethod, interface{}, error) {
+	return func(header *Header) (SigningMethod, interface{}, error) {
+		jwk, err := c.lookup(header.KeyID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return jwk.verificationKey(header.Algorithm)
+	}
+}
+
+// lookup 在缓存中查找密钥，未找到时按最小间隔强制刷新后重试
+func (c *CachedKeySet) lookup(kid string) (*JWK, error) {
+	set, err := c.KeySet()
+	if err != nil {
+		return nil, err
+	}
+	jwk, err := set.lookup(kid)
+	if !errors.Is(err, ErrKeyNotFound) {
+		return jwk, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.now().Sub(c.fetchedAt) < minJWKSRefreshInterval {
+		return nil, err
+	}
+	if refreshErr := c.refreshLocked(); refreshErr != nil {
+		return nil, fmt.Errorf("%w (refresh failed: %v)", err, refreshErr)
+	}
+	return c.set.lookup(kid)
+}
+
+// refreshLocked 获取密钥集，调用方持有 c.mu
+func (c *CachedKeySet) refreshLocked() error {
+	set, err := FetchJWKS(c.url)
+	if err != nil {
+		return err
+	}
+	c.set = set
+	c.fetchedAt = c.now()
+	return nil
+}
@@ -0,0 +1,357 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// rsaJWK 以 JWK 形式描述 RSA 公钥
+func rsaJWK(kid string, pub *rsa.PublicKey) JWK {
+	return JWK{KeyType: "RSA", KeyID: kid, Use: "sig", N: base64URLEncode(pub.N.Bytes()), E: base64URLEncode(big.NewInt(int64(pub.E)).Bytes())}
+}
+
+// ecJWK 以 JWK 形式描述 EC 公钥
+func ecJWK(kid string, pub *ecdsa.PublicKey) JWK {
+	size := (pub.Curve.Params().BitSize + 7) / 8
+	return JWK{
+		KeyType: "EC", KeyID: kid, Curve: pub.Curve.Params().Name,
+		X: base64URLEncode(pub.X.FillBytes(make([]byte, size))),
+		Y: base64URLEncode(pub.Y.FillBytes(make([]byte, size))),
+	}
+}
+
+// jwksServer 提供可替换的 JWKS 文档并统计请求次数
+type jwksServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	set      KeySet
+	requests int32
+}
+
+func newJWKSServer(t *testing.T, keys ...JWK) *jwksServer {
+	t.Helper()
+	s := &jwksServer{set: KeySet{Keys: keys}}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&s.requests, 1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(s.set)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) setKeys(keys ...JWK) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set = KeySet{Keys: keys}
+}
+
+func TestParseJWKS(t *testing.T) {
+	rsaKey, _ := GenerateRSAKeyPair(2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	enc := rsaJWK("enc", &rsaKey.PublicKey)
+	enc.Use = "enc"
+	data, _ := json.Marshal(KeySet{Keys: []JWK{rsaJWK("rsa", &rsaKey.PublicKey), ecJWK("ec", &ecKey.PublicKey), enc}})
+
+	set, err := ParseJWKS(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := set.Key("rsa")
+	if pub, ok := key.(*rsa.PublicKey); err != nil || !ok || !pub.Equal(&rsaKey.PublicKey) {
+		t.Errorf("Key(rsa) = %v, %v", key, err)
+	}
+	key, err = set.Key("ec")
+	if pub, ok := key.(*ecdsa.PublicKey); err != nil || !ok || !pub.Equal(&ecKey.PublicKey) {
+		t.Errorf("Key(ec) = %v, %v", key, err)
+	}
+
+	for _, kid := range []string{"missing", "enc", ""} {
+		if _, err := set.Key(kid); !errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("Key(%q) err = %v, want ErrKeyNotFound", kid, err)
+		}
+	}
+	// 只有一个签名密钥时，没有 kid 的令牌使用该密钥
+	single := &KeySet{Keys: []JWK{rsaJWK("rsa", &rsaKey.PublicKey), enc}}
+	if _, err := single.Key(""); err != nil {
+		t.Errorf("single key without kid: %v", err)
+	}
+
+	invalid := []struct {
+		name string
+		doc  string
+	}{
+		{"not json", `keys`},
+		{"missing keys", `{}`},
+	}
+	for _, tt := range invalid {
+		if _, err := ParseJWKS([]byte(tt.doc)); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+
+	badKeys := []JWK{
+		{KeyType: "oct", KeyID: "k"},
+		{KeyType: "RSA", KeyID: "k", N: "AQAB"},
+		{KeyType: "RSA", KeyID: "k", N: "AQAB", E: "!!"},
+		{KeyType: "EC", KeyID: "k", Curve: "P-192", X: "AQ", Y: "AQ"},
+		{KeyType: "EC", KeyID: "k", Curve: "P-256", X: "AQ", Y: "AQ"},
+	}
+	for _, jwk := range badKeys {
+		if _, err := (&KeySet{Keys: []JWK{jwk}}).Key("k"); !errors.Is(err, ErrInvalidKeyType) {
+			t.Errorf("Key(%+v) err = %v, want ErrInvalidKeyType", jwk, err)
+		}
+	}
+}
+
+func TestFetchJWKSEndToEnd(t *testing.T) {
+	rsaKey, _ := GenerateRSAKeyPair(2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	server := newJWKSServer(t, rsaJWK("rsa-1", &rsaKey.PublicKey), ecJWK("ec-1", &ecKey.PublicKey))
+
+	set, err := FetchJWKS(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsToken, _ := NewBuilder(SigningMethodRS256, rsaKey).SetKeyID("rsa-1").SetAudience("api").Build()
+	psToken, _ := NewBuilder(SigningMethodPS256, rsaKey).SetKeyID("rsa-1").Build()
+	esToken, _ := NewBuilder(SigningMethodES256, ecKey).SetKeyID("ec-1").Build()
+	for name, tokenString := range map[string]string{"RS256": rsToken, "PS256": psToken, "ES256": esToken} {
+		if _, err := ParseWithKeyFunc(tokenString, set.KeyFunc()); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	if _, err := ParseWithKeyFunc(rsToken, set.KeyFunc(), WithAudience("other")); !errors.Is(err, ErrInvalidAudience) {
+		t.Errorf("audience option err = %v", err)
+	}
+
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+	if _, err := FetchJWKS(notFound.URL); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("404 err = %v", err)
+	}
+}
+
+func TestJWKSAlgorithmBinding(t *testing.T) {
+	rsaKey, _ := GenerateRSAKeyPair(2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	pinned := rsaJWK("pinned", &rsaKey.PublicKey)
+	pinned.Algorithm = "RS256"
+	set := &KeySet{Keys: []JWK{rsaJWK("rsa", &rsaKey.PublicKey), ecJWK("ec", &ecKey.PublicKey), pinned}}
+
+	// 以 JWKS 中公开的 n 作为 HMAC 密钥伪造令牌
+	nBytes, _ := base64URLDecode(set.Keys[0].N)
+	hsToken, _ := NewBuilder(SigningMethodHS256, nBytes).SetKeyID("rsa").Build()
+	if _, err := ParseWithKeyFunc(hsToken, set.KeyFunc()); err == nil || !strings.Contains(err.Error(), "unexpected signing method: HS256") {
+		t.Errorf("HS256 with JWKS key err = %v", err)
+	}
+
+	// 令牌算法与密钥类型或曲线不符
+	es384Key, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	wrongCurve, _ := NewBuilder(SigningMethodES384, es384Key).SetKeyID("ec").Build()
+	wrongType, _ := NewBuilder(SigningMethodES256, ecKey).SetKeyID("rsa").Build()
+	for name, tokenString := range map[string]string{"curve": wrongCurve, "type": wrongType} {
+		if _, err := ParseWithKeyFunc(tokenString, set.KeyFunc()); !errors.Is(err, ErrInvalidKeyType) {
+			t.Errorf("wrong %s err = %v", name, err)
+		}
+	}
+
+	// JWK 声明了 alg 时只接受该算法
+	psToken, _ := NewBuilder(SigningMethodPS256, rsaKey).SetKeyID("pinned").Build()
+	if _, err := ParseWithKeyFunc(psToken, set.KeyFunc()); !errors.Is(err, ErrTokenUnverifiable) {
+		t.Errorf("alg pinned by JWK err = %v", err)
+	}
+}
+
+func TestCachedKeySet(t *testing.T) {
+	oldKey, _ := GenerateRSAKeyPair(2048)
+	newKey, _ := GenerateRSAKeyPair(2048)
+	server := newJWKSServer(t, rsaJWK("old", &oldKey.PublicKey))
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewCachedKeySet(server.URL, time.Hour)
+	cache.now = func() time.Time { return now }
+
+	oldToken, _ := NewBuilder(SigningMethodRS256, oldKey).SetKeyID("old").Build()
+	for i := 0; i < 3; i++ {
+		if _, err := ParseWithKeyFunc(oldToken, cache.KeyFunc()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := atomic.LoadInt32(&server.requests); n != 1 {
+		t.Errorf("requests within TTL = %d, want 1", n)
+	}
+
+	// 签发方轮换密钥：未知 kid 在最小间隔内不刷新，之后立即刷新
+	server.setKeys(rsaJWK("old", &oldKey.PublicKey), rsaJWK("new", &newKey.PublicKey))
+	newToken, _ := NewBuilder(SigningMethodRS256, newKey).SetKeyID("new").Build()
+	if _, err := ParseWithKeyFunc(newToken, cache.KeyFunc()); !errors.Is(err, ErrTokenUnverifiable) || !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("unknown kid right after fetch err = %v", err)
+	}
+	now = now.Add(time.Minute)
+	if _, err := ParseWithKeyFunc(newToken, cache.KeyFunc()); err != nil {
+		t.Errorf("rotated key: %v", err)
+	}
+	if n := atomic.LoadInt32(&server.requests); n != 2 {
+		t.Errorf("requests after rotation = %d, want 2", n)
+	}
+
+	// TTL 过期后重新获取；获取失败时继续使用缓存
+	now = now.Add(2 * time.Hour)
+	server.Close()
+	if _, err := cache.Key("old"); err != nil {
+		t.Errorf("stale key after failed refresh: %v", err)
+	}
+	if err := cache.Refresh(); err == nil {
+		t.Error("Refresh against a closed server should fail")
+	}
+
+	if _, err := NewCachedKeySet(server.URL, time.Hour).Key("old"); err == nil {
+		t.Error("empty cache with unreachable server should fail")
+	}
+}
+
+func TestCachedKeySetConcurrent(t *testing.T) {
+	rsaKey, _ := GenerateRSAKeyPair(2048)
+	server := newJWKSServer(t, rsaJWK("k", &rsaKey.PublicKey))
+	cache := NewCachedKeySet(server.URL, time.Hour)
+	tokenString, _ := NewBuilder(SigningMethodRS256, rsaKey).SetKeyID("k").Build()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := ParseWithKeyFunc(tokenString, cache.KeyFunc()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := atomic.LoadInt32(&server.requests); n != 1 {
+		t.Errorf("concurrent first use made %d requests, want 1", n)
+	}
+}
+
+func TestPublicKeyToJWK(t *testing.T) {
+	rsaKey, _ := GenerateRSAKeyPair(2048)
+	p521Key, _ := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+
+	data, err := PublicKeyToJWK(rsaKey, "rsa-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	json.Unmarshal(data, &fields)
+	for _, private := range []string{"d", "p", "q", "dp", "dq", "qi"} {
+		if _, ok := fields[private]; ok {
+			t.Errorf("exported JWK contains private parameter %q", private)
+		}
+	}
+	var jwk JWK
+	json.Unmarshal(data, &jwk)
+	want := rsaJWK("rsa-1", &rsaKey.PublicKey)
+	want.Algorithm = "RS256"
+	if jwk != want || jwk.E != "AQAB" {
+		t.Errorf("RSA JWK = %+v, want %+v", jwk, want)
+	}
+
+	// P-521 坐标补齐到 66 字节
+	jwk, err = NewJWK(&p521Key.PublicKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, _ := base64URLDecode(jwk.X)
+	y, _ := base64URLDecode(jwk.Y)
+	if jwk.Algorithm != "ES512" || jwk.Curve != "P-521" || len(x) != 66 || len(y) != 66 {
+		t.Errorf("P-521 JWK = %+v (x %d bytes, y %d bytes)", jwk, len(x), len(y))
+	}
+	if kid, _ := ECKeyThumbprint(&p521Key.PublicKey); jwk.KeyID != kid {
+		t.Errorf("default kid = %q, want thumbprint %q", jwk.KeyID, kid)
+	}
+	if pub, err := jwk.PublicKey(); err != nil || !pub.(*ecdsa.PublicKey).Equal(&p521Key.PublicKey) {
+		t.Errorf("P-521 round trip = %v, %v", pub, err)
+	}
+
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	for _, key := range []interface{}{[]byte("hmac-secret"), edKey, (*rsa.PublicKey)(nil), nil} {
+		if _, err := PublicKeyToJWK(key, "k"); !errors.Is(err, ErrInvalidKeyType) {
+			t.Errorf("PublicKeyToJWK(%T) err = %v, want ErrInvalidKeyType", key, err)
+		}
+	}
+}
+
+func TestKeySetFromKeysRoundTrip(t *testing.T) {
+	rsaKey, _ := GenerateRSAKeyPair(2048)
+	es256Key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	es384Key, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+
+	set, err := KeySetFromKeys(rsaKey, &es256Key.PublicKey, es384Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newJWKSServer(t, set.Keys...)
+	fetched, err := FetchJWKS(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signers := []struct {
+		method SigningMethod
+		key    interface{}
+	}{
+		{SigningMethodRS256, rsaKey},
+		{SigningMethodES256, es256Key},
+		{SigningMethodES384, es384Key},
+	}
+	for _, s := range signers {
+		tokenString, _ := NewBuilder(s.method, s.key).SetKeyID(AutoKeyID(s.key)).SetSubject("user").Build()
+		token, err := ParseWithKeyFunc(tokenString, fetched.KeyFunc())
+		if err != nil {
+			t.Errorf("%s: %v", s.method.Alg(), err)
+			continue
+		}
+		if token.Method != s.method {
+			t.Errorf("%s: verified with %s", s.method.Alg(), token.Method.Alg())
+		}
+	}
+
+	// 导出的 alg 为 RS256，PS256 令牌需要调整 Algorithm
+	psToken, _ := NewBuilder(SigningMethodPS256, rsaKey).SetKeyID(AutoKeyID(rsaKey)).Build()
+	if _, err := ParseWithKeyFunc(psToken, fetched.KeyFunc()); err == nil {
+		t.Error("PS256 token should not verify against a key published for RS256")
+	}
+	psJWK, _ := NewJWK(rsaKey, AutoKeyID(rsaKey))
+	psJWK.Algorithm = "PS256"
+	if _, err := ParseWithKeyFunc(psToken, (&KeySet{Keys: []JWK{psJWK}}).KeyFunc()); err != nil {
+		t.Errorf("PS256 JWK: %v", err)
+	}
+
+	if _, err := KeySetFromKeys(rsaKey, &rsaKey.PublicKey); err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Errorf("duplicate key err = %v", err)
+	}
+	if _, err := KeySetFromKeys(rsaKey, []byte("secret")); !errors.Is(err, ErrInvalidKeyType) {
+		t.Errorf("HMAC key err = %v", err)
+	}
+	if empty, err := KeySetFromKeys(); err != nil || len(empty.Keys) != 0 {
+		t.Errorf("empty key set = %+v, %v", empty, err)
+	}
+	if data, _ := json.Marshal(&KeySet{Keys: []JWK{}}); string(data) != `{"keys":[]}` {
+		t.Errorf("empty JWKS = %s", data)
+	}
+}
@@ -12,7 +12,6 @@ import (
 	"encoding/json"
 	"encoding/pem"
 	"errors"
-	"fmt"
 	"hash"
 	"strings"
 	"time"
@@ -29,6 +28,9 @@ var (
 	ErrInvalidSubject   = errors.New("invalid subject")
 	ErrInvalidKeyType   = errors.New("invalid key type")
 	ErrKeyMustBePEM     = errors.New("key must be PEM encoded")
+	ErrTTLTooLong       = errors.New("token lifetime exceeds policy")
+
+	ErrAlgorithmNotAllowed = errors.New("signing algorithm not allowed")
 )
 
 // SigningMethod 签名方法接口
@@ -47,26 +49,29 @@ type Header struct {
 
 // StandardClaims 标准声明
 type StandardClaims struct {
-	Audience  string `json:"aud,omitempty"` // 受众
-	ExpiresAt int64  `json:"exp,omitempty"` // 过期时间
-	ID        string `json:"jti,omitempty"` // JWT ID
-	IssuedAt  int64  `json:"iat,omitempty"` // 签发时间
-	Issuer    string `json:"iss,omitempty"` // 签发者
-	NotBefore int64  `json:"nbf,omitempty"` // 生效时间
-	Subject   string `json:"sub,omitempty"` // 主题
+	Audience  ClaimStrings `json:"aud,omitempty"` // 受众，字符串或字符串数组
+	ExpiresAt int64        `json:"exp,omitempty"` // 过期时间
+	ID        string       `json:"jti,omitempty"` // JWT ID
+	IssuedAt  int64        `json:"iat,omitempty"` // 签发时间
+	Issuer    string       `json:"iss,omitempty"` // 签发者
+	NotBefore int64        `json:"nbf,omitempty"` // 生效时间
+	Subject   string       `json:"sub,omitempty"` // 主题
 }
 
 // Valid 验证标准声明
 func (c StandardClaims) Valid() error {
-	now := time.Now().Unix()
+	return c.validAt(time.Now(), 0)
+}
 
+// validAt 按指定时间与时钟偏差容忍度验证标准声明
+func (c StandardClaims) validAt(t time.Time, leeway time.Duration) error {
 	// 检查过期时间
-	if c.ExpiresAt != 0 && now > c.ExpiresAt {
+	if c.ExpiresAt != 0 && expiredAt(t, c.ExpiresAt, leeway) {
 		return ErrTokenExpired
 	}
 
 	// 检查生效时间
-	if c.NotBefore != 0 && now < c.NotBefore {
+	if c.NotBefore != 0 && notYetValidAt(t, c.NotBefore, leeway) {
 		return ErrTokenNotYetValid
 	}
 
@@ -83,17 +88,20 @@ type MapClaims map[string]interface{}
 
 // Valid 验证映射声明
 func (m MapClaims) Valid() error {
-	now := time.Now().Unix()
+	return m.validAt(time.Now(), 0)
+}
 
+// validAt 按指定时间与时钟偏差容忍度验证映射声明
+func (m MapClaims) validAt(t time.Time, leeway time.Duration) error {
 	// 检查过期时间
 	if exp, ok := m["exp"]; ok {
 		switch exp := exp.(type) {
 		case float64:
-			if now > int64(exp) {
+			if expiredAt(t, int64(exp), leeway) {
 				return ErrTokenExpired
 			}
 		case int64:
-			if now > exp {
+			if expiredAt(t, exp, leeway) {
 				return ErrTokenExpired
 			}
 		}
@@ -103,11 +111,11 @@ func (m MapClaims) Valid() error {
 	if nbf, ok := m["nbf"]; ok {
 		switch nbf := nbf.(type) {
 		case float64:
-			if now < int64(nbf) {
+			if notYetValidAt(t, int64(nbf), leeway) {
 				return ErrTokenNotYetValid
 			}
 		case int64:
-			if now < nbf {
+			if notYetValidAt(t, nbf, leeway) {
 				return ErrTokenNotYetValid
 			}
 		}
@@ -128,6 +136,22 @@ type Token struct {
 
 // SignedString 生成签名后的 JWT 字符串
 func (t *Token) SignedString(key interface{}) (string, error) {
+	signingString, err := t.signingString()
+	if err != nil {
+		return "", err
+	}
+
+	// 签名
+	signature, err := t.Method.Sign(signingString, key)
+	if err != nil {
+		return "", err
+	}
+
+	return signingString + "." + signature, nil
+}
+
+// signingString 编码头部与声明，返回待签名的 header.payload 字符串
+func (t *Token) signingString() (string, error) {
 	// 编码头部
 	headerBytes, err := json.Marshal(t.Header)
 	if err != nil {
@@ -142,22 +166,19 @@ func (t *Token) SignedString(key interface{}) (string, error) {
 	}
 	claims := base64URLEncode(claimsBytes)
 
-	// 生成签名字符串
-	signingString := header + "." + claims
-
-	// 签名
-	signature, err := t.Method.Sign(signingString, key)
-	if err != nil {
-		return "", err
-	}
-
-	return signingString + "." + signature, nil
+	return header + "." + claims, nil
 }
 
 // JWT 主要结构体
+//
+// JWT 在创建后不可变：Parse / Generate 等方法只读取配置，可被多个 goroutine 并发使用；
+// WithStampPolicy / WithClock / WithLeeway 返回修改后的副本，不影响正在使用的实例。
 type JWT struct {
 	signingMethod SigningMethod
 	key           interface{}
+	stampPolicy   StampPolicy
+	now           func() time.Time
+	leeway        time.Duration
 }
 
 // New 创建新的 JWT 实例
@@ -188,37 +209,71 @@ func (j *JWT) Parse(tokenString string) (*Token, error) {
 
 // ParseWithClaims 解析带指定声明类型的 JWT 令牌
 func (j *JWT) ParseWithClaims(tokenString string, claims Claims) (*Token, error) {
+	token, parts, err := decodeToken(tokenString, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	// 验证签名方法
+	if err := checkAlgorithm(token.Header.Algorithm, nil); err != nil {
+		return nil, err
+	}
+	if token.Header.Algorithm != j.signingMethod.Alg() {
+		return nil, unexpectedSigningMethod(token.Header.Algorithm)
+	}
+	token.Method = j.signingMethod
+
+	// 验证签名
+	if err := j.signingMethod.Verify(parts[0]+"."+parts[1], parts[2], j.key); err != nil {
+		return nil, err
+	}
+
+	// 验证声明
+	if err := j.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	token.Valid = true
+	return token, nil
+}
+
+// validateClaims 验证声明，设置了 WithClock 时 MapClaims、StandardClaims 与 RegisteredClaims（含指针及嵌入它们的自定义类型）
+// 按注入的时钟检查 exp / nbf，与 Generate 补全声明使用同一时间源；WithLeeway 设置的容忍度同样只作用于这些类型，其他声明类型调用其 Valid 方法
+func (j *JWT) validateClaims(claims Claims) error {
+	if j.now == nil && j.leeway == 0 {
+		return claims.Valid()
+	}
+	return validateClaimsAt(claims, j.currentTime(), j.leeway)
+}
+
+// decodeToken 解码令牌的头部与声明（不验证签名），返回令牌及其三段内容
+func decodeToken(tokenString string, claims Claims) (*Token, []string, error) {
 	parts := strings.Split(tokenString, ".")
 	if len(parts) != 3 {
-		return nil, ErrInvalidToken
+		return nil, nil, ErrInvalidToken
 	}
 
 	token := &Token{
-		Raw: tokenString,
+		Raw:       tokenString,
+		Signature: parts[2],
 	}
 
 	// 解析头部
 	headerBytes, err := base64URLDecode(parts[0])
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	header := &Header{}
 	if err := json.Unmarshal(headerBytes, header); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	token.Header = header
 
-	// 验证签名方法
-	if header.Algorithm != j.signingMethod.Alg() {
-		return nil, fmt.Errorf("unexpected signing method: %s", header.Algorithm)
-	}
-	token.Method = j.signingMethod
-
 	// 解析声明
 	claimsBytes, err := base64URLDecode(parts[1])
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// 根据 claims 类型进行不同的处理
@@ -226,37 +281,30 @@ func (j *JWT) ParseWithClaims(tokenString string, claims Claims) (*Token, error)
 	case MapClaims:
 		var tempClaims map[string]interface{}
 		if err := json.Unmarshal(claimsBytes, &tempClaims); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		for k, v := range tempClaims {
 			c[k] = v
 		}
 	default:
 		if err := json.Unmarshal(claimsBytes, claims); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 	token.Claims = claims
 
-	// 验证签名
-	signingString := parts[0] + "." + parts[1]
-	token.Signature = parts[2]
-
-	if err := j.signingMethod.Verify(signingString, parts[2], j.key); err != nil {
-		return nil, err
-	}
-
-	// 验证声明
-	if err := claims.Valid(); err != nil {
-		return nil, err
-	}
-
-	token.Valid = true
-	return token, nil
+	return token, parts, nil
 }
 
-// Generate 生成 JWT 令牌
+// Generate 生成 JWT 令牌，设置了 StampPolicy 时先按策略补全并检查声明
 func (j *JWT) Generate(claims Claims) (string, error) {
+	if j.stampPolicy != (StampPolicy{}) {
+		stamped, err := j.stampClaims(claims)
+		if err != nil {
+			return "", err
+		}
+		claims = stamped
+	}
 	token := NewWithClaims(j.signingMethod, claims)
 	return token.SignedString(j.key)
 }
@@ -299,9 +347,9 @@ func (m *SigningMethodHMAC) Alg() string {
 }
 
 func (m *SigningMethodHMAC) Sign(signingString string, key interface{}) (string, error) {
-	keyBytes, ok := key.([]byte)
-	if !ok {
-		return "", ErrInvalidKeyType
+	keyBytes, err := hmacKey(key)
+	if err != nil {
+		return "", err
 	}
 
 	hasher := hmac.New(m.Hash.New, keyBytes)
@@ -340,19 +388,9 @@ func (m *SigningMethodRSA) Alg() string {
 }
 
 func (m *SigningMethodRSA) Sign(signingString string, key interface{}) (string, error) {
-	var rsaKey *rsa.PrivateKey
-
-	switch k := key.(type) {
-	case *rsa.PrivateKey:
-		rsaKey = k
-	case []byte:
-		var err error
-		rsaKey, err = parseRSAPrivateKeyFromPEM(k)
-		if err != nil {
-			return "", err
-		}
-	default:
-		return "", ErrInvalidKeyType
+	rsaKey, err := rsaPrivateKey(key)
+	if err != nil {
+		return "", err
 	}
 
 	var hasher hash.Hash
@@ -380,21 +418,9 @@ func (m *SigningMethodRSA) Sign(signingString string, key interface{}) (string,
 }
 
 func (m *SigningMethodRSA) Verify(signingString, signature string, key interface{}) error {
-	var rsaKey *rsa.PublicKey
-
-	switch k := key.(type) {
-	case *rsa.PublicKey:
-		rsaKey = k
-	case *rsa.PrivateKey:
-		rsaKey = &k.PublicKey
-	case []byte:
-		var err error
-		rsaKey, err = parseRSAPublicKeyFromPEM(k)
-		if err != nil {
-			return err
-		}
-	default:
-		return ErrInvalidKeyType
+	rsaKey, err := rsaPublicKey(key)
+	if err != nil {
+		return err
 	}
 
 	sig, err := base64URLDecode(signature)
@@ -421,6 +447,32 @@ func (m *SigningMethodRSA) Verify(signingString, signature string, key interface
 	return rsa.VerifyPKCS1v15(rsaKey, m.Hash, hashed, sig)
 }
 
+// rsaPrivateKey 从 *rsa.PrivateKey 或 PEM 编码的私钥获取签名密钥
+func rsaPrivateKey(key interface{}) (*rsa.PrivateKey, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, nil
+	case []byte:
+		return parseRSAPrivateKeyFromPEM(k)
+	default:
+		return nil, ErrInvalidKeyType
+	}
+}
+
+// rsaPublicKey 从 *rsa.PublicKey、*rsa.PrivateKey 或 PEM 编码的公钥获取验证密钥
+func rsaPublicKey(key interface{}) (*rsa.PublicKey, error) {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return k, nil
+	case *rsa.PrivateKey:
+		return &k.PublicKey, nil
+	case []byte:
+		return parseRSAPublicKeyFromPEM(k)
+	default:
+		return nil, ErrInvalidKeyType
+	}
+}
+
 // PEM 密钥解析工具函数
 func parseRSAPrivateKeyFromPEM(key []byte) (*rsa.PrivateKey, error) {
 	block, _ := pem.Decode(key)
@@ -240,7 +240,7 @@ func TestStandardClaims(t *testing.T) {
 	claims := &StandardClaims{
 		Issuer:    "test-issuer",
 		Subject:   "test-user",
-		Audience:  "test-audience",
+		Audience:  ClaimStrings{"test-audience"},
 		ExpiresAt: now.Add(time.Hour).Unix(),
 		IssuedAt:  now.Unix(),
 		NotBefore: now.Unix(),
@@ -487,7 +487,7 @@ func TestGenericAPIWithCustomClaims(t *testing.T) {
 	standardClaims := &StandardClaims{
 		Issuer:    "test-issuer",
 		Subject:   "test-user",
-		Audience:  "test-audience",
+		Audience:  ClaimStrings{"test-audience"},
 		ExpiresAt: now.Add(time.Hour).Unix(),
 		IssuedAt:  now.Unix(),
 		NotBefore: now.Unix(),
@@ -520,8 +520,8 @@ func TestGenericAPIWithCustomClaims(t *testing.T) {
 		t.Errorf("Expected issuer='test-issuer', got '%s'", parsedClaims.Issuer)
 	}
 
-	if parsedClaims.Audience != "test-audience" {
-		t.Errorf("Expected audience='test-audience', got '%s'", parsedClaims.Audience)
+	if !parsedClaims.Audience.Contains("test-audience") || len(parsedClaims.Audience) != 1 {
+		t.Errorf("Expected audience='test-audience', got '%v'", parsedClaims.Audience)
 	}
 }
 
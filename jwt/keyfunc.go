@@ -0,0 +1,73 @@
+package jwt
+
+import "fmt"
+
+// 按头部选择密钥
+//
+// 密钥轮换或多租户场景下，验证密钥由头部的 kid 等字段决定：
+//
+//	token, err := jwt.ParseWithKeyFunc(tokenString, func(h *jwt.Header) (jwt.SigningMethod, interface{}, error) {
+//		key, ok := publicKeys[h.KeyID]
+//		if !ok {
+//			return nil, nil, fmt.Errorf("unknown kid %q", h.KeyID)
+//		}
+//		return jwt.SigningMethodRS256, key, nil
+//	})
+//
+// 回调在头部解码之后、签名验证之前调用，返回允许的签名方法与对应的密钥。
+// 返回的方法必须与头部 alg 一致，否则解析失败，回调无法绕过算法检查：
+// 攻击者把 alg 改为 HS256 并用公钥作为 HMAC 密钥签名的令牌，会因与回调返回的 RS256 不一致而被拒绝。
+
+// ParseWithKeyFunc 解析 JWT，签名方法与验证密钥由回调根据未验证的头部决定
+// 回调返回错误时解析失败，错误包装为 ErrTokenUnverifiable；支持 WithLeeway、WithAudience 等解析选项
+func ParseWithKeyFunc(tokenString string, keyFunc func(header *Header) (SigningMethod, interface{}, error), options ...ParserOption) (*Token, error) {
+	return ParseWithClaimsKeyFunc(tokenString, make(MapClaims), keyFunc, options...)
+}
+
+// ParseWithClaimsKeyFunc 与 ParseWithKeyFunc 相同，解析到指定的声明类型
+func ParseWithClaimsKeyFunc(tokenString string, claims Claims, keyFunc func(header *Header) (SigningMethod, interface{}, error), options ...ParserOption) (*Token, error) {
+	opts := newParserOptions(options)
+
+	token, parts, err := decodeToken(tokenString, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	alg := token.Header.Algorithm
+	if err := checkAlgorithm(alg, opts.validMethods); err != nil {
+		return nil, err
+	}
+
+	// 选择签名方法与密钥，回调只能看到头部的副本
+	if keyFunc == nil {
+		return nil, fmt.Errorf("%w: no key function was provided", ErrTokenUnverifiable)
+	}
+	header := *token.Header
+	method, key, err := keyFunc(&header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTokenUnverifiable, err)
+	}
+	if method == nil {
+		return nil, fmt.Errorf("%w: key function returned no signing method", ErrTokenUnverifiable)
+	}
+	if method.Alg() != alg {
+		return nil, fmt.Errorf("%w (key function allows %s)", unexpectedSigningMethod(alg), method.Alg())
+	}
+	token.Method = method
+
+	// 验证签名
+	if err := method.Verify(parts[0]+"."+parts[1], parts[2], key); err != nil {
+		return nil, err
+	}
+
+	// 验证声明
+	if err := validateClaimsAt(claims, opts.currentTime(), opts.leeway); err != nil {
+		return nil, err
+	}
+	if err := opts.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	token.Valid = true
+	return token, nil
+}
@@ -0,0 +1,116 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseWithKeyFuncRotation(t *testing.T) {
+	oldKey, _ := GenerateRSAKeyPair(2048)
+	newKey, _ := GenerateRSAKeyPair(2048)
+	publicKeys := map[string]interface{}{
+		"2023": &oldKey.PublicKey,
+		"2024": &newKey.PublicKey,
+	}
+	keyFunc := func(h *Header) (SigningMethod, interface{}, error) {
+		key, ok := publicKeys[h.KeyID]
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown kid %q", h.KeyID)
+		}
+		return SigningMethodRS256, key, nil
+	}
+
+	for kid, key := range map[string]interface{}{"2023": oldKey, "2024": newKey} {
+		tokenString, _ := NewBuilder(SigningMethodRS256, key).SetKeyID(kid).SetSubject("user-" + kid).Build()
+		token, err := ParseWithKeyFunc(tokenString, keyFunc)
+		if err != nil {
+			t.Fatalf("kid %s: %v", kid, err)
+		}
+		claims, _ := ExtractClaims(token)
+		if sub, _ := GetClaimString(claims, "sub"); sub != "user-"+kid || !token.Valid || token.Method != SigningMethodRS256 {
+			t.Errorf("kid %s: token = %+v", kid, token)
+		}
+	}
+
+	// kid 与签名密钥不一致
+	forged, _ := NewBuilder(SigningMethodRS256, oldKey).SetKeyID("2024").Build()
+	if _, err := ParseWithKeyFunc(forged, keyFunc); err == nil {
+		t.Error("token signed by another key should not verify")
+	}
+
+	unknown, _ := NewBuilder(SigningMethodRS256, newKey).SetKeyID("2099").Build()
+	if _, err := ParseWithKeyFunc(unknown, keyFunc); !errors.Is(err, ErrTokenUnverifiable) || !strings.Contains(err.Error(), "2099") {
+		t.Errorf("unknown kid err = %v", err)
+	}
+}
+
+func TestParseWithKeyFuncMultiTenant(t *testing.T) {
+	secrets := map[string][]byte{"tenant-a": []byte("secret-a"), "tenant-b": []byte("secret-b")}
+	keyFunc := func(h *Header) (SigningMethod, interface{}, error) {
+		return SigningMethodHS256, secrets[h.KeyID], nil
+	}
+
+	tokenString, _ := NewBuilder(SigningMethodHS256, secrets["tenant-b"]).SetKeyID("tenant-b").SetIssuer("b").Build()
+	if _, err := ParseWithKeyFunc(tokenString, keyFunc, WithIssuer("b")); err != nil {
+		t.Errorf("tenant-b: %v", err)
+	}
+	if _, err := ParseWithKeyFunc(tokenString, keyFunc, WithIssuer("a")); !errors.Is(err, ErrInvalidIssuer) {
+		t.Errorf("issuer option err = %v", err)
+	}
+
+	// 过期令牌在容忍度内可以通过
+	expired, _ := NewBuilder(SigningMethodHS256, secrets["tenant-a"]).SetKeyID("tenant-a").
+		SetExpiration(time.Now().Add(-5 * time.Second)).Build()
+	if _, err := ParseWithKeyFunc(expired, keyFunc); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("expired err = %v", err)
+	}
+	if _, err := ParseWithKeyFunc(expired, keyFunc, WithLeeway(time.Minute)); err != nil {
+		t.Errorf("expired within leeway: %v", err)
+	}
+
+	// 解析到自定义声明类型
+	claims := &StandardClaims{}
+	if _, err := ParseWithClaimsKeyFunc(tokenString, claims, keyFunc); err != nil || claims.Issuer != "b" {
+		t.Errorf("ParseWithClaimsKeyFunc = %+v, %v", claims, err)
+	}
+}
+
+func TestParseWithKeyFuncAlgorithmBinding(t *testing.T) {
+	rsaKey, _ := GenerateRSAKeyPair(2048)
+	publicPEM, _ := PublicKeyToPEM(&rsaKey.PublicKey)
+	keyFunc := func(h *Header) (SigningMethod, interface{}, error) {
+		return SigningMethodRS256, publicPEM, nil
+	}
+
+	// 密钥混淆攻击：将 alg 改为 HS256，并以公开的 RSA 公钥作为 HMAC 密钥签名
+	attack := forgeToken("HS256", publicPEM, MapClaims{"sub": "admin"})
+	_, err := ParseWithKeyFunc(attack, keyFunc)
+	if err == nil || !strings.Contains(err.Error(), "unexpected signing method: HS256") {
+		t.Errorf("key confusion err = %v", err)
+	}
+
+	// 回调修改头部不会影响算法检查
+	tampering := func(h *Header) (SigningMethod, interface{}, error) {
+		h.Algorithm = "RS256"
+		return SigningMethodRS256, publicPEM, nil
+	}
+	if _, err := ParseWithKeyFunc(attack, tampering); err == nil {
+		t.Error("callback should not be able to rewrite the header algorithm")
+	}
+
+	noMethod := func(h *Header) (SigningMethod, interface{}, error) { return nil, publicPEM, nil }
+	if _, err := ParseWithKeyFunc(attack, noMethod); !errors.Is(err, ErrTokenUnverifiable) {
+		t.Errorf("nil method err = %v", err)
+	}
+	if _, err := ParseWithKeyFunc(attack, nil); !errors.Is(err, ErrTokenUnverifiable) {
+		t.Errorf("nil key function err = %v", err)
+	}
+
+	valid, _ := GenerateRS256(rsaKey, MapClaims{"sub": "user"})
+	if _, err := ParseWithKeyFunc(valid, keyFunc, WithValidMethods([]string{"ES256"})); err == nil {
+		t.Error("WithValidMethods should apply before the key function")
+	}
+}
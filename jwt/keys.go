@@ -0,0 +1,214 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// 密钥加载：从 PEM 文件加载 RSA / EC / Ed25519 密钥，从环境变量加载 HMAC 密钥
+
+// ParsePrivateKeyFromPEM 解析 PEM 编码的私钥，根据 PEM 类型自动识别格式
+// 支持 RSA PRIVATE KEY（PKCS#1）、EC PRIVATE KEY（SEC 1）与 PRIVATE KEY（PKCS#8），
+// 返回 *rsa.PrivateKey、*ecdsa.PrivateKey 或 ed25519.PrivateKey
+func ParsePrivateKeyFromPEM(data []byte) (interface{}, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM block found, key type undetectable", ErrKeyMustBePEM)
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "ENCRYPTED PRIVATE KEY":
+		return nil, fmt.Errorf("encrypted private keys are not supported")
+	default:
+		return nil, fmt.Errorf("PEM block type %q is not a supported private key", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", block.Type, err)
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey:
+		return k, nil
+	case ed25519.PrivateKey:
+		return k, nil
+	default:
+		return nil, fmt.Errorf("%s contains unsupported key type %T", block.Type, key)
+	}
+}
+
+// ParsePublicKeyFromPEM 解析 PEM 编码的公钥，根据 PEM 类型自动识别格式
+// 支持 RSA PUBLIC KEY（PKCS#1）、PUBLIC KEY（PKIX）与 CERTIFICATE（取证书公钥），
+// 返回 *rsa.PublicKey、*ecdsa.PublicKey 或 ed25519.PublicKey
+func ParsePublicKeyFromPEM(data []byte) (interface{}, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM block found, key type undetectable", ErrKeyMustBePEM)
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			key = cert.PublicKey
+		}
+	default:
+		return nil, fmt.Errorf("PEM block type %q is not a supported public key", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", block.Type, err)
+	}
+
+	switch k := key.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+		return k, nil
+	case ed25519.PublicKey:
+		return k, nil
+	default:
+		return nil, fmt.Errorf("%s contains unsupported key type %T", block.Type, key)
+	}
+}
+
+// LoadPrivateKeyFromFile 从 PEM 文件加载私钥
+func LoadPrivateKeyFromFile(path string) (interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load private key: %w", err)
+	}
+	key, err := ParsePrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("load private key %s: %w", path, err)
+	}
+	return key, nil
+}
+
+// LoadPublicKeyFromFile 从 PEM 文件加载公钥
+func LoadPublicKeyFromFile(path string) (interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load public key: %w", err)
+	}
+	key, err := ParsePublicKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("load public key %s: %w", path, err)
+	}
+	return key, nil
+}
+
+// LoadHMACSecretFromEnv 从环境变量加载 HMAC 密钥，解码后长度不足 minLen 字节时返回错误
+//
+// 值可以使用 "hex:"、"base64:"、"raw:" 前缀指定编码（base64: 接受标准或 URL 安全、可省略填充）。
+// 没有前缀时按以下顺序识别：
+//  1. 偶数长度且只含十六进制字符 → 十六进制
+//  2. 以 "=" 填充结尾的合法 base64 → base64
+//  3. 其他 → 原始字节
+//
+// 未填充的 base64 与普通字母数字密钥无法区分，不会自动识别，需使用 "base64:" 前缀，
+// 避免原始文本密钥被误解码成不同的字节而与其他服务不一致
+func LoadHMACSecretFromEnv(name string, minLen int) ([]byte, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return nil, fmt.Errorf("load HMAC secret: env %s is not set", name)
+	}
+
+	secret, encoding, err := decodeSecret(strings.TrimSpace(value))
+	if err != nil {
+		return nil, fmt.Errorf("load HMAC secret from env %s: %w", name, err)
+	}
+	if len(secret) < minLen {
+		return nil, fmt.Errorf("load HMAC secret from env %s: %s secret is %d bytes, minimum is %d", name, encoding, len(secret), minLen)
+	}
+	return secret, nil
+}
+
+// decodeSecret 解码密钥并返回识别出的编码
+func decodeSecret(value string) ([]byte, string, error) {
+	switch {
+	case strings.HasPrefix(value, "hex:"):
+		secret, err := hex.DecodeString(value[len("hex:"):])
+		if err != nil {
+			return nil, "hex", fmt.Errorf("invalid hex secret: %w", err)
+		}
+		return secret, "hex", nil
+	case strings.HasPrefix(value, "base64:"):
+		secret, ok := decodeBase64Secret(value[len("base64:"):])
+		if !ok {
+			return nil, "base64", fmt.Errorf("invalid base64 secret")
+		}
+		return secret, "base64", nil
+	case strings.HasPrefix(value, "raw:"):
+		return []byte(value[len("raw:"):]), "raw", nil
+	}
+
+	if len(value)%2 == 0 {
+		if secret, err := hex.DecodeString(value); err == nil {
+			return secret, "hex", nil
+		}
+	}
+	if strings.HasSuffix(value, "=") {
+		for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding} {
+			if secret, err := encoding.DecodeString(value); err == nil {
+				return secret, "base64", nil
+			}
+		}
+	}
+	return []byte(value), "raw", nil
+}
+
+// decodeBase64Secret 依次尝试标准与 URL 安全的 base64（含或不含填充）
+func decodeBase64Secret(value string) ([]byte, bool) {
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if secret, err := encoding.DecodeString(value); err == nil {
+			return secret, true
+		}
+	}
+	return nil, false
+}
+
+// MustLoadPrivateKeyFromFile 从 PEM 文件加载私钥，失败时 panic，用于 main 中的初始化
+func MustLoadPrivateKeyFromFile(path string) interface{} {
+	key, err := LoadPrivateKeyFromFile(path)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// MustLoadPublicKeyFromFile 从 PEM 文件加载公钥，失败时 panic，用于 main 中的初始化
+func MustLoadPublicKeyFromFile(path string) interface{} {
+	key, err := LoadPublicKeyFromFile(path)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// MustLoadHMACSecretFromEnv 从环境变量加载 HMAC 密钥，失败时 panic，用于 main 中的初始化
+func MustLoadHMACSecretFromEnv(name string, minLen int) []byte {
+	secret, err := LoadHMACSecretFromEnv(name, minLen)
+	if err != nil {
+		panic(err)
+	}
+	return secret
+}
@@ -0,0 +1,217 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writePEM 将 PEM 块写入临时文件
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadKeysFromFile(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	edPub, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	ecDER, _ := x509.MarshalECPrivateKey(ecKey)
+	pkcs8 := func(key interface{}) []byte {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return der
+	}
+	pkix := func(key interface{}) []byte {
+		der, err := x509.MarshalPKIXPublicKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return der
+	}
+
+	privateKeys := []struct {
+		name      string
+		blockType string
+		der       []byte
+		want      interface{}
+	}{
+		{"rsa-pkcs1.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey), rsaKey},
+		{"rsa-pkcs8.pem", "PRIVATE KEY", pkcs8(rsaKey), rsaKey},
+		{"ec-sec1.pem", "EC PRIVATE KEY", ecDER, ecKey},
+		{"ec-pkcs8.pem", "PRIVATE KEY", pkcs8(ecKey), ecKey},
+		{"ed25519.pem", "PRIVATE KEY", pkcs8(edKey), edKey},
+	}
+	for _, tt := range privateKeys {
+		key, err := LoadPrivateKeyFromFile(writePEM(t, tt.name, tt.blockType, tt.der))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !key.(interface{ Equal(crypto.PrivateKey) bool }).Equal(tt.want) {
+			t.Errorf("%s: loaded %T does not match", tt.name, key)
+		}
+	}
+
+	publicKeys := []struct {
+		name      string
+		blockType string
+		der       []byte
+		want      interface{}
+	}{
+		{"rsa-pkcs1.pub", "RSA PUBLIC KEY", x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey), &rsaKey.PublicKey},
+		{"rsa-pkix.pub", "PUBLIC KEY", pkix(&rsaKey.PublicKey), &rsaKey.PublicKey},
+		{"ec-pkix.pub", "PUBLIC KEY", pkix(&ecKey.PublicKey), &ecKey.PublicKey},
+		{"ed25519.pub", "PUBLIC KEY", pkix(edPub), edPub},
+		{"cert.pem", "CERTIFICATE", selfSignedCert(t, ecKey), &ecKey.PublicKey},
+	}
+	for _, tt := range publicKeys {
+		key, err := LoadPublicKeyFromFile(writePEM(t, tt.name, tt.blockType, tt.der))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !key.(interface{ Equal(crypto.PublicKey) bool }).Equal(tt.want) {
+			t.Errorf("%s: loaded %T does not match", tt.name, key)
+		}
+	}
+}
+
+// selfSignedCert 生成自签名证书
+func selfSignedCert(t *testing.T, key *ecdsa.PrivateKey) []byte {
+	t.Helper()
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+func TestLoadKeysFromFileErrors(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	pubDER, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	dir := t.TempDir()
+	garbage := filepath.Join(dir, "garbage.pem")
+	os.WriteFile(garbage, []byte("not a pem file"), 0600)
+
+	tests := []struct {
+		name string
+		load func(string) (interface{}, error)
+		path string
+		want []string
+	}{
+		{"missing file", LoadPrivateKeyFromFile, filepath.Join(dir, "missing.pem"), []string{"missing.pem"}},
+		{"not PEM", LoadPrivateKeyFromFile, garbage, []string{"garbage.pem", "undetectable"}},
+		{"public as private", LoadPrivateKeyFromFile, writePEM(t, "pub.pem", "PUBLIC KEY", pubDER), []string{"pub.pem", `"PUBLIC KEY"`}},
+		{"private as public", LoadPublicKeyFromFile, writePEM(t, "priv.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)), []string{"priv.pem", `"RSA PRIVATE KEY"`}},
+		{"corrupted body", LoadPrivateKeyFromFile, writePEM(t, "bad.pem", "EC PRIVATE KEY", []byte("garbage")), []string{"bad.pem", "EC PRIVATE KEY"}},
+		{"encrypted", LoadPrivateKeyFromFile, writePEM(t, "enc.pem", "ENCRYPTED PRIVATE KEY", []byte("x")), []string{"enc.pem", "encrypted"}},
+		{"unknown type", LoadPublicKeyFromFile, writePEM(t, "csr.pem", "CERTIFICATE REQUEST", []byte("x")), []string{"csr.pem", "CERTIFICATE REQUEST"}},
+	}
+	for _, tt := range tests {
+		_, err := tt.load(tt.path)
+		if err == nil {
+			t.Errorf("%s: expected error", tt.name)
+			continue
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("%s: error %q should mention %q", tt.name, err, want)
+			}
+		}
+	}
+
+	if _, err := LoadPrivateKeyFromFile(garbage); !errors.Is(err, ErrKeyMustBePEM) {
+		t.Errorf("non-PEM input should wrap ErrKeyMustBePEM, got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustLoadPrivateKeyFromFile should panic")
+		}
+	}()
+	MustLoadPrivateKeyFromFile(garbage)
+}
+
+func TestLoadHMACSecretFromEnv(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	tests := []struct {
+		name  string
+		value string
+		want  []byte
+	}{
+		{"hex", hex.EncodeToString(secret), secret},
+		{"base64", base64.StdEncoding.EncodeToString(secret), secret},
+		{"base64url padded", base64.URLEncoding.EncodeToString(append(secret, 0xfb, 0xff)), append(secret, 0xfb, 0xff)},
+		{"raw", "this is a raw secret with spaces!", []byte("this is a raw secret with spaces!")},
+		// 合法的未填充 base64，但没有前缀时按原始文本使用
+		{"ambiguous raw", "mySuperSecretSigningKeyForJWTTokensInProd202", []byte("mySuperSecretSigningKeyForJWTTokensInProd202")},
+		{"unpadded base64 needs prefix", "base64:" + base64.RawURLEncoding.EncodeToString(append(secret, 0xfb, 0xff)), append(secret, 0xfb, 0xff)},
+		{"forced raw", "raw:" + hex.EncodeToString(secret), []byte(hex.EncodeToString(secret))},
+		{"forced base64", "base64:" + base64.StdEncoding.EncodeToString(secret), secret},
+		{"forced hex", "hex:" + hex.EncodeToString(secret), secret},
+	}
+	for _, tt := range tests {
+		t.Setenv("JWT_TEST_SECRET", tt.value)
+		got, err := LoadHMACSecretFromEnv("JWT_TEST_SECRET", 32)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if string(got) != string(tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoadHMACSecretFromEnvErrors(t *testing.T) {
+	if _, err := LoadHMACSecretFromEnv("JWT_TEST_UNSET_SECRET", 1); err == nil || !strings.Contains(err.Error(), "JWT_TEST_UNSET_SECRET is not set") {
+		t.Errorf("unset env error = %v", err)
+	}
+
+	t.Setenv("JWT_TEST_SECRET", hex.EncodeToString([]byte("short")))
+	_, err := LoadHMACSecretFromEnv("JWT_TEST_SECRET", 32)
+	if err == nil || !strings.Contains(err.Error(), "JWT_TEST_SECRET") || !strings.Contains(err.Error(), "hex secret is 5 bytes, minimum is 32") {
+		t.Errorf("short secret error = %v", err)
+	}
+
+	t.Setenv("JWT_TEST_SECRET", "hex:zz")
+	if _, err := LoadHMACSecretFromEnv("JWT_TEST_SECRET", 1); err == nil || !strings.Contains(err.Error(), "invalid hex") {
+		t.Errorf("invalid hex error = %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustLoadHMACSecretFromEnv should panic")
+		}
+	}()
+	MustLoadHMACSecretFromEnv("JWT_TEST_UNSET_SECRET", 1)
+}
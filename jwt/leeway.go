@@ -0,0 +1,72 @@
+package jwt
+
+import "time"
+
+// 时钟偏差容忍度
+//
+// 签发方与验证方的时钟存在偏差时，刚签发的令牌可能因 nbf 被判定为尚未生效，
+// 临近过期的令牌也可能提前失效。leeway 同时放宽两端的窗口：
+// 当前时间超过 exp + leeway 才算过期，早于 nbf - leeway 才算尚未生效。默认为 0，与原有行为一致。
+
+// WithLeeway 返回使用指定时钟偏差容忍度的副本，原实例不变
+func (j *JWT) WithLeeway(leeway time.Duration) *JWT {
+	c := *j
+	c.leeway = leeway
+	return &c
+}
+
+// WithLeeway 设置 exp / nbf 检查的时钟偏差容忍度，可用于 ParseWithOptions 与 ParseCompat
+func WithLeeway(leeway time.Duration) ParserOption {
+	return func(o *parserOptions) {
+		o.leeway = leeway
+	}
+}
+
+// ParseWithOptions 使用指定算法解析 JWT，并应用解析选项
+//
+//	token, err := jwt.ParseWithOptions(jwt.SigningMethodHS256, tokenString, secret,
+//		jwt.WithLeeway(30*time.Second), jwt.WithIssuer("auth-service"))
+//
+// WithValidMethods 限制 method 本身，method 不在列表中时直接返回错误
+func ParseWithOptions(method SigningMethod, tokenString string, key interface{}, options ...ParserOption) (*Token, error) {
+	opts := newParserOptions(options)
+	if err := checkAlgorithm(method.Alg(), opts.validMethods); err != nil {
+		return nil, err
+	}
+
+	token, err := New(method, key).WithLeeway(opts.leeway).WithClock(opts.now).Parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if err := opts.validateClaims(token.Claims); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// timedClaims 可按指定时间与容忍度验证的声明
+//
+// MapClaims、StandardClaims 与 RegisteredClaims 实现了该接口，嵌入后两者的自定义声明类型通过方法提升同样满足。
+type timedClaims interface {
+	validAt(now time.Time, leeway time.Duration) error
+}
+
+// validateClaimsAt 按指定时间与容忍度验证声明，未实现 timedClaims 的声明类型调用其 Valid 方法
+//
+// 嵌入 StandardClaims / RegisteredClaims 的自定义类型按提升的 validAt 检查 exp / nbf，不调用自定义的 Valid 方法
+func validateClaimsAt(claims Claims, now time.Time, leeway time.Duration) error {
+	if c, ok := claims.(timedClaims); ok {
+		return c.validAt(now, leeway)
+	}
+	return claims.Valid()
+}
+
+// expiredAt 判断 exp 在 now 时是否已过期
+func expiredAt(now time.Time, exp int64, leeway time.Duration) bool {
+	return now.Add(-leeway).Unix() > exp
+}
+
+// notYetValidAt 判断 nbf 在 now 时是否尚未生效
+func notYetValidAt(now time.Time, nbf int64, leeway time.Duration) bool {
+	return now.Add(leeway).Unix() < nbf
+}
@@ -0,0 +1,121 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLeewayWidensWindow(t *testing.T) {
+	secret := []byte("leeway-secret")
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		claims MapClaims
+		want   error
+	}{
+		{"issued by a clock ahead", MapClaims{"nbf": now.Add(2 * time.Second).Unix()}, ErrTokenNotYetValid},
+		{"just expired", MapClaims{"exp": now.Add(-2 * time.Second).Unix()}, ErrTokenExpired},
+	}
+	for _, tt := range tests {
+		tokenString, _ := GenerateHS256(secret, tt.claims)
+
+		// 默认不容忍偏差
+		if _, err := ParseHS256(tokenString, secret); !errors.Is(err, tt.want) {
+			t.Errorf("%s: default err = %v, want %v", tt.name, err, tt.want)
+		}
+		if _, err := ParseWithOptions(SigningMethodHS256, tokenString, secret, WithLeeway(30*time.Second)); err != nil {
+			t.Errorf("%s: ParseWithOptions with leeway: %v", tt.name, err)
+		}
+		if _, err := New(SigningMethodHS256, secret).WithLeeway(30 * time.Second).Parse(tokenString); err != nil {
+			t.Errorf("%s: JWT.WithLeeway: %v", tt.name, err)
+		}
+		if _, err := New(SigningMethodHS256, secret).WithLeeway(time.Second).Parse(tokenString); !errors.Is(err, tt.want) {
+			t.Errorf("%s: leeway smaller than skew err = %v, want %v", tt.name, err, tt.want)
+		}
+		if _, err := ParseCompat(tokenString, func(*Token) (interface{}, error) { return secret, nil }, WithLeeway(30*time.Second)); err != nil {
+			t.Errorf("%s: ParseCompat with leeway: %v", tt.name, err)
+		}
+
+		claims, _ := DecodeClaims(tokenString)
+		if err := ValidateStandardClaims(claims, "", "", ""); !errors.Is(err, tt.want) {
+			t.Errorf("%s: ValidateStandardClaims err = %v, want %v", tt.name, err, tt.want)
+		}
+		if err := ValidateStandardClaimsWithLeeway(claims, "", "", "", 30*time.Second); err != nil {
+			t.Errorf("%s: ValidateStandardClaimsWithLeeway: %v", tt.name, err)
+		}
+	}
+}
+
+func TestLeewayStandardClaims(t *testing.T) {
+	secret := []byte("leeway-secret")
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	j := New(SigningMethodHS256, secret).WithClock(fixedClock(now)).WithLeeway(time.Minute)
+
+	claims := &StandardClaims{ExpiresAt: now.Add(-30 * time.Second).Unix(), NotBefore: now.Add(30 * time.Second).Unix()}
+	tokenString, _ := j.Generate(claims)
+	if _, err := j.ParseWithClaims(tokenString, &StandardClaims{}); err != nil {
+		t.Errorf("*StandardClaims within leeway: %v", err)
+	}
+	if _, err := j.Parse(tokenString); err != nil {
+		t.Errorf("MapClaims within leeway: %v", err)
+	}
+
+	// 超出容忍度仍然失败
+	late := j.WithClock(fixedClock(now.Add(2 * time.Minute)))
+	if _, err := late.ParseWithClaims(tokenString, &StandardClaims{}); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("beyond leeway err = %v, want ErrTokenExpired", err)
+	}
+
+	// WithLeeway 返回副本
+	if New(SigningMethodHS256, secret).leeway != 0 || j.WithLeeway(0).leeway != 0 || j.leeway != time.Minute {
+		t.Error("WithLeeway should not modify the receiver")
+	}
+}
+
+// embeddedStandardClaims 嵌入 StandardClaims 的自定义声明
+type embeddedStandardClaims struct {
+	StandardClaims
+	Role string `json:"role"`
+}
+
+// embeddedRegisteredClaims 嵌入 RegisteredClaims 的自定义声明
+type embeddedRegisteredClaims struct {
+	RegisteredClaims
+	Role string `json:"role"`
+}
+
+func TestLeewayEmbeddedClaims(t *testing.T) {
+	secret := []byte("leeway-secret")
+	j := New(SigningMethodHS256, secret).WithLeeway(time.Minute)
+	tokenString, _ := GenerateHS256(secret, MapClaims{"role": "admin", "exp": time.Now().Add(-10 * time.Second).Unix()})
+
+	for _, claims := range []Claims{&embeddedStandardClaims{}, &embeddedRegisteredClaims{}} {
+		if _, err := j.ParseWithClaims(tokenString, claims); err != nil {
+			t.Errorf("%T within leeway: %v", claims, err)
+		}
+		if _, err := New(SigningMethodHS256, secret).ParseWithClaims(tokenString, claims); !errors.Is(err, ErrTokenExpired) {
+			t.Errorf("%T without leeway err = %v, want ErrTokenExpired", claims, err)
+		}
+	}
+	keyFunc := func(*Header) (SigningMethod, interface{}, error) { return SigningMethodHS256, secret, nil }
+	if _, err := ParseWithClaimsKeyFunc(tokenString, &embeddedStandardClaims{}, keyFunc, WithLeeway(time.Minute)); err != nil {
+		t.Errorf("ParseWithClaimsKeyFunc within leeway: %v", err)
+	}
+}
+
+func TestParseWithOptions(t *testing.T) {
+	secret := []byte("leeway-secret")
+	tokenString, _ := GenerateHS256(secret, MapClaims{"iss": "auth", "aud": "api"})
+
+	if _, err := ParseWithOptions(SigningMethodHS256, tokenString, secret, WithIssuer("auth"), WithAudience("api")); err != nil {
+		t.Errorf("matching options: %v", err)
+	}
+	if _, err := ParseWithOptions(SigningMethodHS256, tokenString, secret, WithIssuer("other")); !errors.Is(err, ErrInvalidIssuer) {
+		t.Errorf("issuer mismatch err = %v", err)
+	}
+	if _, err := ParseWithOptions(SigningMethodHS256, tokenString, secret, WithValidMethods([]string{"RS256"})); err == nil {
+		t.Error("method outside WithValidMethods should be rejected")
+	}
+}
@@ -0,0 +1,131 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 访问令牌与刷新令牌
+//
+// 短期的访问令牌用于访问接口，长期的刷新令牌只用于换取新的访问令牌，两者通过 typ 声明区分，各自带有独立的 jti：
+//
+//	access, refresh, err := jwt.GenerateTokenPair(jwt.SigningMethodHS256, secret,
+//		jwt.MapClaims{"sub": "user-1", "role": "admin"}, 15*time.Minute, 7*24*time.Hour)
+//	newAccess, err := jwt.RefreshAccessToken(jwt.SigningMethodHS256, secret, refresh,
+//		jwt.MapClaims{"role": "admin"}, jwt.WithBlacklist(blacklist))
+//
+// 接口验证访问令牌时应检查 typ 为 TokenTypeAccess，防止刷新令牌被当作访问令牌使用；
+// 退出登录时用 RevokeToken 吊销刷新令牌即可阻止继续换取访问令牌。
+
+// 令牌类型，写入 typ 声明
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// DefaultAccessTTL RefreshAccessToken 签发的访问令牌在 claims 未指定 exp 时的有效期
+const DefaultAccessTTL = 15 * time.Minute
+
+// ErrInvalidTokenType 令牌的 typ 声明与用途不符
+var ErrInvalidTokenType = errors.New("invalid token type")
+
+// GenerateTokenPair 签发访问令牌与刷新令牌
+//
+// 访问令牌包含 claims 的全部声明，刷新令牌只携带其中的 iss、sub、aud；
+// 两者的 iat、exp、typ 由本函数设置，刷新令牌总是使用新的 jti，访问令牌在 claims 未指定 jti 时生成新的 jti。
+func GenerateTokenPair(method SigningMethod, key interface{}, claims MapClaims, accessTTL, refreshTTL time.Duration) (access, refresh string, err error) {
+	if accessTTL <= 0 || refreshTTL <= 0 {
+		return "", "", fmt.Errorf("token TTL must be positive, got access %s and refresh %s", accessTTL, refreshTTL)
+	}
+	now := time.Now()
+
+	accessClaims, err := newAccessClaims(claims, now, now.Add(accessTTL))
+	if err != nil {
+		return "", "", err
+	}
+	if access, err = Generate(method, key, accessClaims); err != nil {
+		return "", "", err
+	}
+
+	refreshJTI, err := newJTI()
+	if err != nil {
+		return "", "", err
+	}
+	refreshClaims := MapClaims{
+		"typ": TokenTypeRefresh,
+		"jti": refreshJTI,
+		"iat": now.Unix(),
+		"exp": now.Add(refreshTTL).Unix(),
+	}
+	copyIdentityClaims(refreshClaims, claims)
+	if refresh, err = Generate(method, key, refreshClaims); err != nil {
+		return "", "", err
+	}
+	return access, refresh, nil
+}
+
+// RefreshAccessToken 验证刷新令牌并签发新的访问令牌
+//
+// 刷新令牌按 ParseWithOptions 验证签名、有效期与 options（如 WithBlacklist、WithIssuer），
+// typ 不是 TokenTypeRefresh 时返回 ErrInvalidTokenType，因此访问令牌不能用于刷新。
+// 新访问令牌包含 claims 的声明，iss、sub、aud 以刷新令牌为准；claims 未指定 exp 时有效期为 DefaultAccessTTL。
+func RefreshAccessToken(method SigningMethod, key interface{}, refreshToken string, claims MapClaims, options ...ParserOption) (newAccess string, err error) {
+	token, err := ParseWithOptions(method, refreshToken, key, options...)
+	if err != nil {
+		return "", err
+	}
+	refreshClaims, ok := token.Claims.(MapClaims)
+	if !ok {
+		return "", ErrInvalidToken
+	}
+	if typ, _ := GetClaimString(refreshClaims, "typ"); typ != TokenTypeRefresh {
+		return "", fmt.Errorf("%w: expected %q token, got %q", ErrInvalidTokenType, TokenTypeRefresh, typ)
+	}
+
+	now := time.Now()
+	exp := now.Add(DefaultAccessTTL)
+	if unix, ok := GetClaimInt64(claims, "exp"); ok {
+		exp = time.Unix(unix, 0)
+	}
+	accessClaims, err := newAccessClaims(claims, now, exp)
+	if err != nil {
+		return "", err
+	}
+	for _, name := range identityClaims {
+		delete(accessClaims, name)
+	}
+	copyIdentityClaims(accessClaims, refreshClaims)
+	return Generate(method, key, accessClaims)
+}
+
+// newAccessClaims 复制 claims 并设置访问令牌的 typ、iat、exp，缺少 jti 时生成新的 jti
+func newAccessClaims(claims MapClaims, now, exp time.Time) (MapClaims, error) {
+	accessClaims := make(MapClaims, len(claims)+4)
+	for k, v := range claims {
+		accessClaims[k] = v
+	}
+	accessClaims["typ"] = TokenTypeAccess
+	accessClaims["iat"] = now.Unix()
+	accessClaims["exp"] = exp.Unix()
+	if jti, _ := GetClaimString(accessClaims, "jti"); jti == "" {
+		jti, err := newJTI()
+		if err != nil {
+			return nil, err
+		}
+		accessClaims["jti"] = jti
+	}
+	return accessClaims, nil
+}
+
+// identityClaims 刷新令牌携带、并在刷新时传递给新访问令牌的声明
+var identityClaims = []string{"iss", "sub", "aud"}
+
+// copyIdentityClaims 将 src 中的 iss、sub、aud 复制到 dst
+func copyIdentityClaims(dst, src MapClaims) {
+	for _, name := range identityClaims {
+		if v, exists := src[name]; exists {
+			dst[name] = v
+		}
+	}
+}
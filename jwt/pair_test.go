@@ -0,0 +1,121 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGenerateTokenPair(t *testing.T) {
+	secret := []byte("pair-secret")
+	access, refresh, err := GenerateTokenPair(SigningMethodHS256, secret, MapClaims{"sub": "user-1", "iss": "auth", "role": "admin"}, 15*time.Minute, 7*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accessToken, err := ParseHS256(access, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refreshToken, err := ParseHS256(refresh, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	accessClaims := accessToken.Claims.(MapClaims)
+	refreshClaims := refreshToken.Claims.(MapClaims)
+
+	if accessClaims["typ"] != TokenTypeAccess || refreshClaims["typ"] != TokenTypeRefresh {
+		t.Errorf("typ = %v / %v", accessClaims["typ"], refreshClaims["typ"])
+	}
+	accessJTI, _ := GetClaimString(accessClaims, "jti")
+	refreshJTI, _ := GetClaimString(refreshClaims, "jti")
+	if accessJTI == "" || refreshJTI == "" || accessJTI == refreshJTI {
+		t.Errorf("jti = %q / %q, want distinct IDs", accessJTI, refreshJTI)
+	}
+	// 刷新令牌只携带身份声明
+	if refreshClaims["sub"] != "user-1" || refreshClaims["iss"] != "auth" || refreshClaims["role"] != nil {
+		t.Errorf("refresh claims = %v", refreshClaims)
+	}
+	if accessClaims["role"] != "admin" {
+		t.Errorf("access claims = %v", accessClaims)
+	}
+	accessExp, _ := GetClaimInt64(accessClaims, "exp")
+	refreshExp, _ := GetClaimInt64(refreshClaims, "exp")
+	if ttl := time.Until(time.Unix(accessExp, 0)); ttl <= 14*time.Minute || ttl > 15*time.Minute {
+		t.Errorf("access ttl = %s", ttl)
+	}
+	if ttl := time.Until(time.Unix(refreshExp, 0)); ttl <= 7*24*time.Hour-time.Minute || ttl > 7*24*time.Hour {
+		t.Errorf("refresh ttl = %s", ttl)
+	}
+
+	if _, _, err := GenerateTokenPair(SigningMethodHS256, secret, MapClaims{}, 0, time.Hour); err == nil {
+		t.Error("zero access TTL should be rejected")
+	}
+}
+
+func TestRefreshAccessToken(t *testing.T) {
+	secret := []byte("pair-secret")
+	access, refresh, err := GenerateTokenPair(SigningMethodHS256, secret, MapClaims{"sub": "user-1", "role": "admin"}, time.Minute, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 身份以刷新令牌为准，claims 中的 sub 不能冒充其他用户
+	newAccess, err := RefreshAccessToken(SigningMethodHS256, secret, refresh, MapClaims{"sub": "someone-else", "role": "viewer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := ParseHS256(newAccess, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := token.Claims.(MapClaims)
+	if claims["typ"] != TokenTypeAccess || claims["sub"] != "user-1" || claims["role"] != "viewer" {
+		t.Errorf("refreshed claims = %v", claims)
+	}
+	exp, _ := GetClaimInt64(claims, "exp")
+	if ttl := time.Until(time.Unix(exp, 0)); ttl <= DefaultAccessTTL-time.Minute || ttl > DefaultAccessTTL {
+		t.Errorf("refreshed ttl = %s", ttl)
+	}
+	if jti, _ := GetClaimString(claims, "jti"); jti == "" || newAccess == access {
+		t.Error("refreshed access token should be a new token with its own jti")
+	}
+
+	// claims 指定的 exp 优先
+	exp = time.Now().Add(5 * time.Minute).Unix()
+	newAccess, _ = RefreshAccessToken(SigningMethodHS256, secret, refresh, MapClaims{"exp": exp})
+	token, _ = ParseHS256(newAccess, secret)
+	if got, _ := GetClaimInt64(token.Claims.(MapClaims), "exp"); got != exp {
+		t.Errorf("exp = %d, want %d", got, exp)
+	}
+}
+
+func TestRefreshAccessTokenRejectsAccessToken(t *testing.T) {
+	secret := []byte("pair-secret")
+	access, refresh, _ := GenerateTokenPair(SigningMethodHS256, secret, MapClaims{"sub": "user-1"}, time.Minute, time.Hour)
+
+	if _, err := RefreshAccessToken(SigningMethodHS256, secret, access, MapClaims{}); !errors.Is(err, ErrInvalidTokenType) {
+		t.Errorf("refresh with access token err = %v, want ErrInvalidTokenType", err)
+	}
+	// 没有 typ 的普通令牌同样不能刷新
+	plain, _ := GenerateHS256(secret, MapClaims{"sub": "user-1", "exp": time.Now().Add(time.Hour).Unix()})
+	if _, err := RefreshAccessToken(SigningMethodHS256, secret, plain, MapClaims{}); !errors.Is(err, ErrInvalidTokenType) {
+		t.Errorf("refresh with untyped token err = %v", err)
+	}
+
+	// 签名、过期与解析选项照常检查
+	if _, err := RefreshAccessToken(SigningMethodHS256, []byte("other-secret"), refresh, MapClaims{}); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("wrong key err = %v", err)
+	}
+	expired, _ := GenerateHS256(secret, MapClaims{"typ": TokenTypeRefresh, "exp": time.Now().Add(-time.Hour).Unix()})
+	if _, err := RefreshAccessToken(SigningMethodHS256, secret, expired, MapClaims{}); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("expired refresh token err = %v", err)
+	}
+	blacklist := NewMemoryBlacklist()
+	if err := RevokeToken(blacklist, refresh); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RefreshAccessToken(SigningMethodHS256, secret, refresh, MapClaims{}, WithBlacklist(blacklist)); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("revoked refresh token err = %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 基于 time.Time 的标准声明
+//
+// StandardClaims 的时间字段是 int64 秒级时间戳，容易与毫秒混用或漏掉零值判断。
+// RegisteredClaims 使用 *NumericDate 表示 exp / nbf / iat，nil 表示缺失，
+// JSON 中仍是 RFC 7519 的 NumericDate（自 1970-01-01 UTC 起的秒数，可带小数）。
+// 两者序列化格式相同，签发与解析时可以互换，StandardClaims 保留用于兼容。
+
+// NumericDate JWT 中的时间值，序列化为秒级时间戳，有亚秒部分时输出小数
+type NumericDate struct {
+	time.Time
+}
+
+// NewNumericDate 创建 NumericDate，精度截断到秒，与多数签发方的整数时间戳一致
+func NewNumericDate(t time.Time) *NumericDate {
+	return &NumericDate{t.Truncate(time.Second)}
+}
+
+// MarshalJSON 输出秒级时间戳，亚秒部分以小数形式精确保留
+func (d NumericDate) MarshalJSON() ([]byte, error) {
+	sec, nsec := d.Unix(), int64(d.Nanosecond())
+	if nsec == 0 {
+		return []byte(strconv.FormatInt(sec, 10)), nil
+	}
+
+	sign := ""
+	if sec < 0 {
+		// Unix() 向下取整，负数时间需要换算成 -(|sec| - 1).(1e9 - nsec)
+		sign, sec, nsec = "-", -sec-1, 1e9-nsec
+	}
+	frac := strings.TrimRight(fmt.Sprintf("%09d", nsec), "0")
+	return []byte(sign + strconv.FormatInt(sec, 10) + "." + frac), nil
+}
+
+// UnmarshalJSON 接受整数或小数秒，小数部分保留到微秒
+func (d *NumericDate) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+	number, ok := value.(json.Number)
+	if !ok {
+		return fmt.Errorf("%w: numeric date must be a number, got %T", ErrInvalidToken, value)
+	}
+
+	if sec, err := number.Int64(); err == nil {
+		d.Time = time.Unix(sec, 0)
+		return nil
+	}
+	f, err := number.Float64()
+	if err != nil || math.Abs(f) >= math.MaxInt64 {
+		return fmt.Errorf("%w: invalid numeric date %s", ErrInvalidToken, number)
+	}
+	sec, frac := math.Modf(f)
+	d.Time = time.Unix(int64(sec), int64(math.Round(frac*1e6))*int64(time.Microsecond))
+	return nil
+}
+
+// RegisteredClaims 标准声明，时间字段为 *NumericDate，nil 表示未设置
+type RegisteredClaims struct {
+	Audience  ClaimStrings `json:"aud,omitempty"` // 受众，字符串或字符串数组
+	ExpiresAt *NumericDate `json:"exp,omitempty"` // 过期时间
+	ID        string       `json:"jti,omitempty"` // JWT ID
+	IssuedAt  *NumericDate `json:"iat,omitempty"` // 签发时间
+	Issuer    string       `json:"iss,omitempty"` // 签发者
+	NotBefore *NumericDate `json:"nbf,omitempty"` // 生效时间
+	Subject   string       `json:"sub,omitempty"` // 主题
+}
+
+// Valid 验证标准声明
+func (c RegisteredClaims) Valid() error {
+	return c.validAt(time.Now(), 0)
+}
+
+// validAt 按指定时间与时钟偏差容忍度验证标准声明，时间比较保留亚秒精度
+func (c RegisteredClaims) validAt(t time.Time, leeway time.Duration) error {
+	if c.ExpiresAt != nil && t.Add(-leeway).After(c.ExpiresAt.Time) {
+		return ErrTokenExpired
+	}
+	if c.NotBefore != nil && t.Add(leeway).Before(c.NotBefore.Time) {
+		return ErrTokenNotYetValid
+	}
+	return nil
+}
+
+// registered 转换为 StandardClaims，供 aud / iss / sub 校验使用
+func (c RegisteredClaims) registered() StandardClaims {
+	return StandardClaims{
+		Audience:  c.Audience,
+		ExpiresAt: numericDateUnix(c.ExpiresAt),
+		ID:        c.ID,
+		IssuedAt:  numericDateUnix(c.IssuedAt),
+		Issuer:    c.Issuer,
+		NotBefore: numericDateUnix(c.NotBefore),
+		Subject:   c.Subject,
+	}
+}
+
+// numericDateUnix 返回秒级时间戳，nil 返回 0
+func numericDateUnix(d *NumericDate) int64 {
+	if d == nil {
+		return 0
+	}
+	return d.Unix()
+}
@@ -0,0 +1,171 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNumericDateJSON(t *testing.T) {
+	tests := []struct {
+		date NumericDate
+		json string
+	}{
+		{NumericDate{time.Unix(1700000000, 0)}, `1700000000`},
+		{NumericDate{time.Unix(1700000000, 500000000)}, `1700000000.5`},
+		{NumericDate{time.Unix(1700000000, 123456000)}, `1700000000.123456`},
+		{NumericDate{time.Unix(-2, 250000000)}, `-1.75`},
+		{NumericDate{time.Unix(-1, 500000000)}, `-0.5`},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.date)
+		if err != nil || string(data) != tt.json {
+			t.Errorf("Marshal(%v) = %s, %v, want %s", tt.date.Time, data, err, tt.json)
+		}
+		var got NumericDate
+		if err := json.Unmarshal([]byte(tt.json), &got); err != nil || !got.Equal(tt.date.Time) {
+			t.Errorf("Unmarshal(%s) = %v, %v, want %v", tt.json, got.Time, err, tt.date.Time)
+		}
+	}
+
+	var got NumericDate
+	if err := json.Unmarshal([]byte(`1.7e9`), &got); err != nil || got.Unix() != 1700000000 {
+		t.Errorf("Unmarshal(1.7e9) = %v, %v", got.Time, err)
+	}
+	for _, input := range []string{`"1700000000"`, `true`, `1e300`} {
+		if err := json.Unmarshal([]byte(input), &got); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("Unmarshal(%s) err = %v, want ErrInvalidToken", input, err)
+		}
+	}
+
+	if d := NewNumericDate(time.Unix(1700000000, 999999999)); d.Unix() != 1700000000 || d.Nanosecond() != 0 {
+		t.Errorf("NewNumericDate should truncate to seconds, got %v", d.Time)
+	}
+}
+
+func TestRegisteredClaimsValidWithLeeway(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	expired := RegisteredClaims{ExpiresAt: &NumericDate{now.Add(-1500 * time.Millisecond)}}
+	notYet := RegisteredClaims{NotBefore: &NumericDate{now.Add(1500 * time.Millisecond)}}
+
+	if err := expired.validAt(now, 0); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("expired err = %v", err)
+	}
+	if err := expired.validAt(now, 2*time.Second); err != nil {
+		t.Errorf("expired within leeway err = %v", err)
+	}
+	if err := notYet.validAt(now, 0); !errors.Is(err, ErrTokenNotYetValid) {
+		t.Errorf("not yet valid err = %v", err)
+	}
+	if err := notYet.validAt(now, 2*time.Second); err != nil {
+		t.Errorf("not yet valid within leeway err = %v", err)
+	}
+	if err := (RegisteredClaims{}).Valid(); err != nil {
+		t.Errorf("claims without times err = %v", err)
+	}
+	if err := (RegisteredClaims{ExpiresAt: NewNumericDate(time.Now().Add(-time.Minute))}).Valid(); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("Valid() err = %v", err)
+	}
+
+	// 经 JWT 实例的时钟与 leeway 验证
+	secret := []byte("registered-secret")
+	tokenString, _ := GenerateHS256(secret, expired)
+	j := New(SigningMethodHS256, secret).WithClock(fixedClock(now))
+	if _, err := j.ParseWithClaims(tokenString, &RegisteredClaims{}); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("Parse err = %v", err)
+	}
+	if _, err := j.WithLeeway(2*time.Second).ParseWithClaims(tokenString, &RegisteredClaims{}); err != nil {
+		t.Errorf("Parse with leeway err = %v", err)
+	}
+}
+
+func TestRegisteredClaimsInterchangeable(t *testing.T) {
+	secret := []byte("registered-secret")
+	exp := time.Now().Add(time.Hour).Truncate(time.Second)
+	iat := time.Now().Truncate(time.Second)
+
+	registered := &RegisteredClaims{
+		Audience:  ClaimStrings{"api", "web"},
+		ExpiresAt: NewNumericDate(exp),
+		ID:        "jti-1",
+		IssuedAt:  NewNumericDate(iat),
+		Issuer:    "auth",
+		Subject:   "user",
+	}
+	standard := &StandardClaims{
+		Audience:  ClaimStrings{"api", "web"},
+		ExpiresAt: exp.Unix(),
+		ID:        "jti-1",
+		IssuedAt:  iat.Unix(),
+		Issuer:    "auth",
+		Subject:   "user",
+	}
+
+	// 两种结构签发的令牌完全相同
+	fromRegistered, err := GenerateHS256(secret, registered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromStandard, _ := GenerateHS256(secret, standard)
+	if fromRegistered != fromStandard {
+		t.Errorf("tokens differ:\n%s\n%s", fromRegistered, fromStandard)
+	}
+
+	parsedStandard := &StandardClaims{}
+	if _, err := ParseWithClaims(SigningMethodHS256, fromRegistered, secret, parsedStandard); err != nil || !reflect.DeepEqual(parsedStandard, standard) {
+		t.Errorf("StandardClaims = %+v, %v", parsedStandard, err)
+	}
+	parsedRegistered := &RegisteredClaims{}
+	if _, err := ParseWithClaims(SigningMethodHS256, fromStandard, secret, parsedRegistered); err != nil {
+		t.Fatal(err)
+	}
+	if !parsedRegistered.ExpiresAt.Equal(exp) || !parsedRegistered.IssuedAt.Equal(iat) || parsedRegistered.NotBefore != nil || parsedRegistered.Issuer != "auth" {
+		t.Errorf("RegisteredClaims = %+v", parsedRegistered)
+	}
+
+	// 小数秒时间戳
+	fractional, _ := GenerateHS256(secret, MapClaims{"exp": float64(exp.Unix()) + 0.25})
+	parsedRegistered = &RegisteredClaims{}
+	if _, err := ParseWithClaims(SigningMethodHS256, fractional, secret, parsedRegistered); err != nil || !parsedRegistered.ExpiresAt.Equal(exp.Add(250*time.Millisecond)) {
+		t.Errorf("fractional exp = %v, %v", parsedRegistered.ExpiresAt, err)
+	}
+
+	// aud / iss / sub 解析选项同样适用
+	keyFunc := func(*Header) (SigningMethod, interface{}, error) { return SigningMethodHS256, secret, nil }
+	if _, err := ParseWithClaimsKeyFunc(fromRegistered, &RegisteredClaims{}, keyFunc, WithAudience("web"), WithIssuer("auth"), WithSubject("user")); err != nil {
+		t.Errorf("options err = %v", err)
+	}
+	if _, err := ParseWithClaimsKeyFunc(fromRegistered, &RegisteredClaims{}, keyFunc, WithIssuer("other")); !errors.Is(err, ErrInvalidIssuer) {
+		t.Errorf("issuer mismatch err = %v", err)
+	}
+}
+
+func TestStampPolicyRegisteredClaims(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	secret := []byte("stamp-secret")
+	j := New(SigningMethodHS256, secret).WithClock(fixedClock(now)).
+		WithStampPolicy(StampPolicy{AutoIssuedAt: true, AutoJTI: true, DefaultTTL: time.Hour, MaxTTL: 2 * time.Hour})
+
+	claims := &RegisteredClaims{Subject: "u1"}
+	tokenString, err := j.Generate(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.IssuedAt != nil || claims.ID != "" {
+		t.Error("Generate must not modify the caller's claims")
+	}
+
+	parsed := &RegisteredClaims{}
+	if _, err := j.ParseWithClaims(tokenString, parsed); err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.IssuedAt.Equal(now) || !parsed.ExpiresAt.Equal(now.Add(time.Hour)) || len(parsed.ID) != 32 {
+		t.Errorf("stamped claims = %+v", parsed)
+	}
+
+	if _, err := j.Generate(RegisteredClaims{ExpiresAt: NewNumericDate(now.Add(3 * time.Hour))}); !errors.Is(err, ErrTTLTooLong) {
+		t.Errorf("MaxTTL err = %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// 令牌吊销
+//
+// 退出登录等场景需要让尚未过期的令牌提前失效：按 jti 记录被吊销的令牌，
+// 解析时通过 WithBlacklist 检查。记录只需保留到令牌过期，之后令牌本身已无法通过验证。
+//
+//	blacklist := jwt.NewMemoryBlacklist()
+//	err := jwt.RevokeToken(blacklist, tokenString) // 退出登录
+//	token, err := jwt.ParseWithOptions(method, tokenString, key, jwt.WithBlacklist(blacklist))
+
+// ErrTokenRevoked 令牌已被吊销
+var ErrTokenRevoked = errors.New("token revoked")
+
+// Blacklist 已吊销令牌的存储，实现需可被多个 goroutine 并发调用
+//
+// 接口只依赖 jti 与过期时间，便于用 Redis 等外部存储实现（如 SET jti 1 并以 exp 设置过期）；
+// 外部存储查询失败时 IsRevoked 应返回 true，宁可拒绝也不放行。
+type Blacklist interface {
+	// Revoke 吊销指定 jti，exp 为令牌的过期时间，零值表示令牌不会过期、需永久保留
+	Revoke(jti string, exp time.Time) error
+	// IsRevoked 判断 jti 是否已被吊销
+	IsRevoked(jti string) bool
+}
+
+// WithBlacklist 拒绝 jti 已被吊销的令牌，返回 ErrTokenRevoked；没有 jti 的令牌无法被吊销，不受影响
+func WithBlacklist(blacklist Blacklist) ParserOption {
+	return func(o *parserOptions) {
+		o.blacklist = blacklist
+	}
+}
+
+// RevokeToken 从令牌中读取 jti 与 exp 并吊销，不验证签名，因此不需要密钥
+//
+// 应只对已通过验证的令牌调用（如退出登录请求携带的令牌），令牌没有 jti 时返回错误。
+func RevokeToken(blacklist Blacklist, tokenString string) error {
+	claims, err := DecodeClaims(tokenString)
+	if err != nil {
+		return err
+	}
+	jti, ok := GetClaimString(claims, "jti")
+	if !ok || jti == "" {
+		return fmt.Errorf("%w: token has no jti and cannot be revoked", ErrInvalidToken)
+	}
+
+	var exp time.Time
+	if unix, ok := GetClaimInt64(claims, "exp"); ok {
+		exp = time.Unix(unix, 0)
+	}
+	return blacklist.Revoke(jti, exp)
+}
+
+// validateRevocation 检查令牌的 jti 是否已被吊销
+func validateRevocation(claims Claims, blacklist Blacklist) error {
+	if blacklist == nil {
+		return nil
+	}
+
+	var jti string
+	switch c := claims.(type) {
+	case MapClaims:
+		jti, _ = GetClaimString(c, "jti")
+	case registeredClaims:
+		jti = c.registered().ID
+	default:
+		return fmt.Errorf("%w: cannot check revocation for claims of type %T", ErrInvalidToken, claims)
+	}
+	if jti != "" && blacklist.IsRevoked(jti) {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+// blacklistRetention 记录在令牌过期后的保留时间，覆盖解析时常用的时钟偏差容忍度
+const blacklistRetention = 5 * time.Minute
+
+// blacklistPruneInterval 两次自动清理之间的最小间隔
+const blacklistPruneInterval = time.Minute
+
+// MemoryBlacklist 内存中的 Blacklist 实现，适合单实例服务
+//
+// 记录保留到令牌过期后 5 分钟（覆盖常用的 leeway），Revoke 时按间隔自动清理过期记录。
+type MemoryBlacklist struct {
+	mu        sync.RWMutex
+	entries   map[string]time.Time // jti -> 可删除的时间，零值表示永久保留
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+// NewMemoryBlacklist 创建内存吊销列表
+func NewMemoryBlacklist() *MemoryBlacklist {
+	return &MemoryBlacklist{
+		entries: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+// Revoke 吊销指定 jti，重复吊销时保留较晚的过期时间
+func (b *MemoryBlacklist) Revoke(jti string, exp time.Time) error {
+	if jti == "" {
+		return fmt.Errorf("%w: empty jti", ErrInvalidToken)
+	}
+
+	var until time.Time
+	if !exp.IsZero() {
+		until = exp.Add(blacklistRetention)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if now.Sub(b.lastPrune) >= blacklistPruneInterval {
+		b.pruneLocked(now)
+	}
+	if current, exists := b.entries[jti]; exists && (current.IsZero() || !until.IsZero() && !current.Before(until)) {
+		return nil
+	}
+	b.entries[jti] = until
+	return nil
+}
+
+// IsRevoked 判断 jti 是否已被吊销，过了保留期的记录视为不存在
+func (b *MemoryBlacklist) IsRevoked(jti string) bool {
+	b.mu.RLock()
+	until, exists := b.entries[jti]
+	b.mu.RUnlock()
+	return exists && (until.IsZero() || b.now().Before(until))
+}
+
+// Prune 立即删除过了保留期的记录
+func (b *MemoryBlacklist) Prune() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pruneLocked(b.now())
+}
+
+// Len 返回当前记录数，包括尚未清理的过期记录
+func (b *MemoryBlacklist) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.entries)
+}
+
+// pruneLocked 删除过期记录，调用方需持有写锁
+func (b *MemoryBlacklist) pruneLocked(now time.Time) {
+	for jti, until := range b.entries {
+		if !until.IsZero() && !now.Before(until) {
+			delete(b.entries, jti)
+		}
+	}
+	b.lastPrune = now
+}
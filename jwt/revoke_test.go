@@ -0,0 +1,125 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRevokeTokenLogoutFlow(t *testing.T) {
+	secret := []byte("revoke-secret")
+	blacklist := NewMemoryBlacklist()
+	exp := time.Now().Add(time.Hour)
+
+	tokenString, _ := GenerateHS256(secret, MapClaims{"sub": "user", "jti": "session-1", "exp": exp.Unix()})
+	other, _ := GenerateHS256(secret, MapClaims{"sub": "user", "jti": "session-2", "exp": exp.Unix()})
+	if _, err := ParseWithOptions(SigningMethodHS256, tokenString, secret, WithBlacklist(blacklist)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 退出登录：吊销后同一令牌被拒绝，其他令牌不受影响
+	if err := RevokeToken(blacklist, tokenString); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseWithOptions(SigningMethodHS256, tokenString, secret, WithBlacklist(blacklist)); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("revoked token err = %v", err)
+	}
+	if _, err := ParseWithOptions(SigningMethodHS256, other, secret, WithBlacklist(blacklist)); err != nil {
+		t.Errorf("other token err = %v", err)
+	}
+	// 未设置 WithBlacklist 的解析不检查吊销
+	if _, err := ParseWithOptions(SigningMethodHS256, tokenString, secret); err != nil {
+		t.Errorf("parse without blacklist err = %v", err)
+	}
+
+	// 其他解析入口与声明类型
+	keyFunc := func(*Header) (SigningMethod, interface{}, error) { return SigningMethodHS256, secret, nil }
+	compatKey := func(*Token) (interface{}, error) { return secret, nil }
+	for name, claims := range map[string]Claims{"StandardClaims": &StandardClaims{}, "RegisteredClaims": &RegisteredClaims{}} {
+		if _, err := ParseWithClaimsKeyFunc(tokenString, claims, keyFunc, WithBlacklist(blacklist)); !errors.Is(err, ErrTokenRevoked) {
+			t.Errorf("%s err = %v", name, err)
+		}
+	}
+	if _, err := ParseCompat(tokenString, compatKey, WithBlacklist(blacklist)); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("ParseCompat err = %v", err)
+	}
+
+	// 没有 jti 的令牌无法吊销，解析不受影响
+	noJTI, _ := GenerateHS256(secret, MapClaims{"sub": "user"})
+	if err := RevokeToken(blacklist, noJTI); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("RevokeToken without jti err = %v", err)
+	}
+	if _, err := ParseWithOptions(SigningMethodHS256, noJTI, secret, WithBlacklist(blacklist)); err != nil {
+		t.Errorf("token without jti err = %v", err)
+	}
+	if err := RevokeToken(blacklist, "not-a-token"); err == nil {
+		t.Error("RevokeToken should reject malformed tokens")
+	}
+}
+
+func TestMemoryBlacklistPruning(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	blacklist := NewMemoryBlacklist()
+	blacklist.now = func() time.Time { return now }
+
+	blacklist.Revoke("short", now.Add(time.Minute))
+	blacklist.Revoke("long", now.Add(time.Hour))
+	blacklist.Revoke("forever", time.Time{})
+	// 重复吊销保留较晚的过期时间
+	blacklist.Revoke("long", now.Add(time.Minute))
+	if err := blacklist.Revoke("", now); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("empty jti err = %v", err)
+	}
+
+	// 过期后仍保留一段时间，覆盖解析时的 leeway
+	now = now.Add(time.Minute + blacklistRetention - time.Second)
+	if !blacklist.IsRevoked("short") {
+		t.Error("short should stay revoked within the retention window")
+	}
+	now = now.Add(time.Second)
+	if blacklist.IsRevoked("short") || !blacklist.IsRevoked("long") || !blacklist.IsRevoked("forever") {
+		t.Errorf("revoked = short:%v long:%v forever:%v", blacklist.IsRevoked("short"), blacklist.IsRevoked("long"), blacklist.IsRevoked("forever"))
+	}
+
+	// Revoke 时自动清理过期记录
+	if blacklist.Len() != 3 {
+		t.Errorf("Len before prune = %d", blacklist.Len())
+	}
+	blacklist.Revoke("new", now.Add(time.Hour))
+	if blacklist.Len() != 3 || blacklist.IsRevoked("short") {
+		t.Errorf("Len after automatic prune = %d", blacklist.Len())
+	}
+
+	now = now.Add(2 * time.Hour)
+	blacklist.Prune()
+	if blacklist.Len() != 1 || !blacklist.IsRevoked("forever") {
+		t.Errorf("Len after Prune = %d", blacklist.Len())
+	}
+}
+
+func TestMemoryBlacklistConcurrent(t *testing.T) {
+	blacklist := NewMemoryBlacklist()
+	exp := time.Now().Add(time.Hour)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				jti := fmt.Sprintf("%d-%d", worker, j)
+				blacklist.Revoke(jti, exp)
+				if !blacklist.IsRevoked(jti) {
+					t.Errorf("%s not revoked", jti)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	if blacklist.Len() != 1600 {
+		t.Errorf("Len = %d", blacklist.Len())
+	}
+}
@@ -0,0 +1,151 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 由 openssl 签发的 PS256 令牌及其公钥：
+// openssl dgst -sha256 -sign key.pem -sigopt rsa_padding_mode:pss -sigopt rsa_pss_saltlen:32
+const (
+	interopPS256Token = "eyJhbGciOiJQUzI1NiIsInR5cCI6IkpXVCJ9." +
+		"eyJzdWIiOiIxMjM0NTY3ODkwIiwibmFtZSI6IkpvaG4gRG9lIiwiYWRtaW4iOnRydWUsImlhdCI6MTUxNjIzOTAyMn0." +
+		"eNybxAURO_1cwG5pFHC5wEubVLmTtdn5X6h0EwvXql-h2Udrb3XyRPdybLBpm8zm0O8T9OBYm7_sFUSzq9dAa3I5PUgpRD4YDNDNPyLS23PsrD_LYT7x" +
+		"sb7Becmq5OzIKGaxPcdi9uVckl8j-YhFhRsJjFckr3DXfzAylvUELU9OeIXT_k37GS1nToFdyjJm1iEOSfODIUt39h2BU7Z_LLR2WgR3AOyOGSFSLu8k" +
+		"FVuUdQTJRKdYDRp0g6FQ5I1cvejbOtLNdC1biBzSo462ppGeOh8l4HzHksaLDfatM0mHhcKZ6vUCvVXFZtArih5pDljy0vhfyGDPa4D-md8I5g"
+	interopPS256PublicKey = `-----BEGIN PUBLIC KEY-----
+MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEAjBzBdrqbUVLLUTycoV0P
+Rtqzsu3RnWcmqeeRi+Vt3BPw9Esv7ASWw8LNYT9joDnBzcpQ/weQdql5gNHaB1Cb
+Je3G8+wqmfH5Gfj8/lM/ivOHnbjBmeNnILAnPZCXdVqEdvgQCuzh3kWRxbBscQj+
+ervkz4tMF0dyB0GDvRxVJ+cYDQiy2hbweL/uEVtG7dxvF2tVkBmLFDdvGKx1vIkm
+mkla0TR8r9kcnJfSiFfKbBT8SyyE1uD1564yg9FJhl2T9rZHfqn+TtRgH0RAAWn9
+3uQujg5e0auNBgSOR0HBjPUgum964szO2PSSt21OHuF8Z+iZ1kveNYfFMjURAARG
+qQIDAQAB
+-----END PUBLIC KEY-----`
+)
+
+func TestRSAPSSInterop(t *testing.T) {
+	token, err := Parse(SigningMethodPS256, interopPS256Token, []byte(interopPS256PublicKey))
+	if err != nil {
+		t.Fatalf("Failed to parse interop token: %v", err)
+	}
+	claims, _ := ExtractClaims(token)
+	if name, _ := GetClaimString(claims, "name"); name != "John Doe" {
+		t.Errorf("name = %q, want John Doe", name)
+	}
+
+	tampered := interopPS256Token[:40] + "x" + interopPS256Token[41:]
+	if _, err := Parse(SigningMethodPS256, tampered, []byte(interopPS256PublicKey)); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("tampered token: err = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestRSAPSSMethods(t *testing.T) {
+	privateKey, _ := GenerateRSAKeyPair(2048)
+	claims := MapClaims{"sub": "test-user", "exp": time.Now().Add(time.Hour).Unix()}
+
+	for _, method := range []*SigningMethodRSAPSS{SigningMethodPS256, SigningMethodPS384, SigningMethodPS512} {
+		tokenString, err := Generate(method, privateKey, claims)
+		if err != nil {
+			t.Fatalf("%s: failed to generate token: %v", method.Alg(), err)
+		}
+		if _, err := Parse(method, tokenString, &privateKey.PublicKey); err != nil {
+			t.Errorf("%s: failed to parse token: %v", method.Alg(), err)
+		}
+
+		// PEM 密钥复用 RS 的解析逻辑
+		publicPEM, _ := PublicKeyToPEM(&privateKey.PublicKey)
+		pemToken, err := Generate(method, PrivateKeyToPEM(privateKey), claims)
+		if err != nil {
+			t.Fatalf("%s: failed to generate token with PEM key: %v", method.Alg(), err)
+		}
+		if _, err := Parse(method, pemToken, publicPEM); err != nil {
+			t.Errorf("%s: failed to parse token with PEM key: %v", method.Alg(), err)
+		}
+
+		if GetSigningMethod(method.Alg()) != method {
+			t.Errorf("GetSigningMethod(%q) is not registered", method.Alg())
+		}
+	}
+}
+
+func TestRSAPSSSaltLength(t *testing.T) {
+	privateKey, _ := GenerateRSAKeyPair(2048)
+	input, _ := SigningInput(SigningMethodPS256, MapClaims{"sub": "salt"}, nil)
+	digest := sha256.Sum256([]byte(input))
+
+	// 盐长度不等于哈希长度的签名不被接受
+	sig, err := rsa.SignPSS(rand.Reader, privateKey, crypto.SHA256, digest[:], &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Parse(SigningMethodPS256, AssembleToken(input, sig), &privateKey.PublicKey); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("max salt length: err = %v, want ErrInvalidSignature", err)
+	}
+
+	sig, _ = rsa.SignPSS(rand.Reader, privateKey, crypto.SHA256, digest[:], &rsa.PSSOptions{SaltLength: 32})
+	if _, err := Parse(SigningMethodPS256, AssembleToken(input, sig), &privateKey.PublicKey); err != nil {
+		t.Errorf("hash-length salt: %v", err)
+	}
+}
+
+func TestRSAPSSRejectsRS(t *testing.T) {
+	privateKey, _ := GenerateRSAKeyPair(2048)
+	claims := MapClaims{"sub": "test-user"}
+
+	psToken, _ := Generate(SigningMethodPS256, privateKey, claims)
+	rsToken, _ := Generate(SigningMethodRS256, privateKey, claims)
+
+	if _, err := Parse(SigningMethodRS256, psToken, &privateKey.PublicKey); err == nil {
+		t.Error("RS256 parser accepted a PS256 token")
+	}
+	if _, err := Parse(SigningMethodPS256, rsToken, &privateKey.PublicKey); err == nil {
+		t.Error("PS256 parser accepted an RS256 token")
+	}
+
+	// 即使篡改头部 alg，签名填充也不同，无法互相验证
+	parts := strings.Split(rsToken, ".")
+	if err := SigningMethodPS256.Verify(parts[0]+"."+parts[1], parts[2], &privateKey.PublicKey); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("PKCS#1 v1.5 signature verified as PSS: %v", err)
+	}
+
+	if _, err := Generate(SigningMethodPS256, []byte("secret"), claims); !errors.Is(err, ErrKeyMustBePEM) {
+		t.Errorf("HMAC secret: err = %v, want ErrKeyMustBePEM", err)
+	}
+	if _, err := Generate(SigningMethodPS256, "secret", claims); !errors.Is(err, ErrInvalidKeyType) {
+		t.Errorf("string key: err = %v, want ErrInvalidKeyType", err)
+	}
+}
+
+func benchmarkRSAMethod(b *testing.B, method SigningMethod) {
+	privateKey, _ := GenerateRSAKeyPair(2048)
+	claims := MapClaims{"sub": "bench", "exp": time.Now().Add(time.Hour).Unix()}
+	tokenString, _ := Generate(method, privateKey, claims)
+
+	b.Run("Sign", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			Generate(method, privateKey, claims)
+		}
+	})
+	b.Run("Verify", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			Parse(method, tokenString, &privateKey.PublicKey)
+		}
+	})
+}
+
+func BenchmarkRS256(b *testing.B) {
+	benchmarkRSAMethod(b, SigningMethodRS256)
+}
+
+func BenchmarkPS256(b *testing.B) {
+	benchmarkRSAMethod(b, SigningMethodPS256)
+}
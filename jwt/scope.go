@@ -0,0 +1,128 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// OAuth 风格的权限范围
+//
+// 范围可以写在空格分隔的 scope 字符串中（RFC 8693），也可以写在 scp 数组中（Azure AD、Okta 等）：
+//
+//	{"scope": "read:users write:orders"}
+//	{"scp": ["read:users", "write:orders"]}
+//
+// 令牌中以 * 结尾的范围是通配符，read:* 匹配 read:users、read:orders 等所有以 read: 开头的范围。
+
+// ErrInsufficientScope 令牌缺少要求的权限范围
+var ErrInsufficientScope = errors.New("insufficient scope")
+
+// GetScopes 返回令牌授予的范围，合并 scope 与 scp 声明并去重，两者都不存在时返回 nil
+func GetScopes(claims MapClaims) []string {
+	var scopes []string
+	seen := make(map[string]bool)
+	for _, key := range []string{"scope", "scp"} {
+		for _, scope := range scopeValues(claims[key]) {
+			if !seen[scope] {
+				seen[scope] = true
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// HasScope 判断令牌是否授予了指定范围（包括通过通配符授予）
+func HasScope(claims MapClaims, scope string) bool {
+	return matchScope(GetScopes(claims), scope)
+}
+
+// HasAnyScope 判断令牌是否授予了任意一个指定范围，未指定范围时返回 false
+func HasAnyScope(claims MapClaims, scopes ...string) bool {
+	granted := GetScopes(claims)
+	for _, scope := range scopes {
+		if matchScope(granted, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAllScopes 判断令牌是否授予了全部指定范围，未指定范围时返回 true
+func HasAllScopes(claims MapClaims, scopes ...string) bool {
+	return len(missingScopes(GetScopes(claims), scopes)) == 0
+}
+
+// SetScopes 以空格分隔的 scope 声明设置权限范围
+func (b *JWTBuilder) SetScopes(scopes ...string) *JWTBuilder {
+	b.claims["scope"] = strings.Join(scopes, " ")
+	return b
+}
+
+// RequireScopes 要求令牌授予全部指定范围，否则解析失败并返回 ErrInsufficientScope
+// 只支持 MapClaims，其他声明类型没有 scope 声明，同样返回 ErrInsufficientScope
+func RequireScopes(scopes ...string) ParserOption {
+	return func(o *parserOptions) {
+		o.scopes = append(o.scopes, scopes...)
+	}
+}
+
+// validateScopes 检查声明是否授予了全部要求的范围
+func validateScopes(claims Claims, required []string) error {
+	if len(required) == 0 {
+		return nil
+	}
+	mapClaims, ok := claims.(MapClaims)
+	if !ok {
+		return fmt.Errorf("%w: claims of type %T carry no scopes", ErrInsufficientScope, claims)
+	}
+	if missing := missingScopes(GetScopes(mapClaims), required); len(missing) > 0 {
+		return fmt.Errorf("%w: missing %s", ErrInsufficientScope, strings.Join(missing, " "))
+	}
+	return nil
+}
+
+// scopeValues 解析 scope / scp 声明，支持空格分隔的字符串与字符串数组
+func scopeValues(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return strings.Fields(v)
+	case []string:
+		return v
+	case []interface{}:
+		scopes := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				scopes = append(scopes, s)
+			}
+		}
+		return scopes
+	default:
+		return nil
+	}
+}
+
+// missingScopes 返回 required 中未被授予的范围
+func missingScopes(granted, required []string) []string {
+	var missing []string
+	for _, scope := range required {
+		if !matchScope(granted, scope) {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+// matchScope 判断授予的范围是否覆盖 scope，授予的范围以 * 结尾时按前缀匹配
+func matchScope(granted []string, scope string) bool {
+	for _, g := range granted {
+		if g == scope {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(g, "*"); ok && strings.HasPrefix(scope, prefix) && len(scope) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package jwt
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetScopes(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims MapClaims
+		want   []string
+	}{
+		{"space delimited", MapClaims{"scope": "read:users  write:orders"}, []string{"read:users", "write:orders"}},
+		{"scp array from JSON", MapClaims{"scp": []interface{}{"read:users", "write:orders"}}, []string{"read:users", "write:orders"}},
+		{"scp string slice", MapClaims{"scp": []string{"admin"}}, []string{"admin"}},
+		{"scp as string", MapClaims{"scp": "a b"}, []string{"a", "b"}},
+		{"both merged", MapClaims{"scope": "a b", "scp": []interface{}{"b", "c"}}, []string{"a", "b", "c"}},
+		{"missing", MapClaims{"sub": "user"}, nil},
+		{"wrong type", MapClaims{"scope": 42}, nil},
+	}
+	for _, tt := range tests {
+		if got := GetScopes(tt.claims); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: GetScopes = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestHasScope(t *testing.T) {
+	for _, claims := range []MapClaims{
+		{"scope": "read:* write:orders"},
+		{"scp": []interface{}{"read:*", "write:orders"}},
+	} {
+		tests := []struct {
+			scope string
+			want  bool
+		}{
+			{"write:orders", true},
+			{"read:users", true},
+			{"read:users:email", true},
+			{"read:", false},
+			{"write:users", false},
+			{"write", false},
+		}
+		for _, tt := range tests {
+			if got := HasScope(claims, tt.scope); got != tt.want {
+				t.Errorf("%v: HasScope(%q) = %v, want %v", claims, tt.scope, got, tt.want)
+			}
+		}
+
+		if !HasAnyScope(claims, "admin", "read:users") || HasAnyScope(claims, "admin", "write:users") || HasAnyScope(claims) {
+			t.Errorf("%v: HasAnyScope", claims)
+		}
+		if !HasAllScopes(claims, "read:users", "write:orders") || HasAllScopes(claims, "read:users", "admin") || !HasAllScopes(claims) {
+			t.Errorf("%v: HasAllScopes", claims)
+		}
+	}
+
+	// 未授予任何范围
+	empty := MapClaims{}
+	if HasScope(empty, "read:users") || HasAnyScope(empty, "read:users") || HasAllScopes(empty, "read:users") {
+		t.Error("claims without scopes should grant nothing")
+	}
+}
+
+func TestRequireScopes(t *testing.T) {
+	secret := []byte("scope-secret")
+	tokenString, _ := NewBuilder(SigningMethodHS256, secret).SetScopes("read:*", "write:orders").Build()
+
+	claims, _ := DecodeClaims(tokenString)
+	if claims["scope"] != "read:* write:orders" {
+		t.Errorf("SetScopes produced %v", claims["scope"])
+	}
+
+	if _, err := ParseWithOptions(SigningMethodHS256, tokenString, secret, RequireScopes("read:users", "write:orders")); err != nil {
+		t.Errorf("granted scopes: %v", err)
+	}
+	_, err := ParseWithOptions(SigningMethodHS256, tokenString, secret, RequireScopes("write:orders"), RequireScopes("admin", "write:users"))
+	if !errors.Is(err, ErrInsufficientScope) || !strings.Contains(err.Error(), "admin write:users") {
+		t.Errorf("missing scopes err = %v", err)
+	}
+
+	keyFunc := func(*Header) (SigningMethod, interface{}, error) { return SigningMethodHS256, secret, nil }
+	if _, err := ParseWithKeyFunc(tokenString, keyFunc, RequireScopes("admin")); !errors.Is(err, ErrInsufficientScope) {
+		t.Errorf("ParseWithKeyFunc err = %v", err)
+	}
+
+	noScope, _ := GenerateHS256(secret, MapClaims{"sub": "user"})
+	if _, err := ParseWithOptions(SigningMethodHS256, noScope, secret, RequireScopes("read:users")); !errors.Is(err, ErrInsufficientScope) {
+		t.Errorf("token without scope err = %v", err)
+	}
+	if _, err := ParseWithClaimsKeyFunc(tokenString, &StandardClaims{}, keyFunc, RequireScopes("read:users")); !errors.Is(err, ErrInsufficientScope) {
+		t.Errorf("StandardClaims err = %v", err)
+	}
+}
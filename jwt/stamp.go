@@ -0,0 +1,216 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// StampPolicy Generate 时自动补全与检查声明的策略，字段零值表示不启用
+//
+// 策略作用于 MapClaims、StandardClaims 与 RegisteredClaims（含指针），其他自定义声明类型原样签发。
+// MapClaims 会先复制再补全，不会修改调用方传入的 map。
+type StampPolicy struct {
+	AutoIssuedAt bool          // 缺少 iat 时填入当前时间
+	AutoJTI      bool          // 缺少 jti 时填入随机 ID
+	DefaultTTL   time.Duration // 缺少 exp 时设置为 当前时间 + DefaultTTL
+	MaxTTL       time.Duration // exp 距当前时间超过 MaxTTL（或缺少 exp）时返回 ErrTTLTooLong
+}
+
+// WithStampPolicy 返回使用指定声明补全策略的副本，原实例不变
+func (j *JWT) WithStampPolicy(policy StampPolicy) *JWT {
+	c := *j
+	c.stampPolicy = policy
+	return &c
+}
+
+// WithClock 返回使用指定时间函数的副本，用于测试或统一时间源，nil 表示使用 time.Now
+// 时钟同时用于 Generate 的声明补全与 Parse 对 MapClaims / StandardClaims / RegisteredClaims（含嵌入它们的自定义类型）的 exp、nbf 检查，
+// 因此实例签发的令牌总能通过自身的验证；其他自定义声明类型的 Valid 方法不受影响
+func (j *JWT) WithClock(now func() time.Time) *JWT {
+	c := *j
+	c.now = now
+	return &c
+}
+
+// currentTime 返回当前时间
+func (j *JWT) currentTime() time.Time {
+	if j.now != nil {
+		return j.now()
+	}
+	return time.Now()
+}
+
+// stampClaims 按策略补全并检查声明，返回实际签发的声明
+func (j *JWT) stampClaims(claims Claims) (Claims, error) {
+	switch c := claims.(type) {
+	case MapClaims:
+		stamped := make(MapClaims, len(c)+3)
+		for k, v := range c {
+			stamped[k] = v
+		}
+		return stamped, j.stampPolicy.apply(mapStamp(stamped), j.currentTime())
+	case StandardClaims:
+		err := j.stampPolicy.apply((*standardStamp)(&c), j.currentTime())
+		return c, err
+	case *StandardClaims:
+		stamped := *c
+		err := j.stampPolicy.apply((*standardStamp)(&stamped), j.currentTime())
+		return &stamped, err
+	case RegisteredClaims:
+		err := j.stampPolicy.apply((*registeredStamp)(&c), j.currentTime())
+		return c, err
+	case *RegisteredClaims:
+		stamped := *c
+		err := j.stampPolicy.apply((*registeredStamp)(&stamped), j.currentTime())
+		return &stamped, err
+	default:
+		return claims, nil
+	}
+}
+
+// apply 对声明应用策略
+func (p StampPolicy) apply(target stampTarget, now time.Time) error {
+	if p.AutoIssuedAt && !target.has("iat") {
+		target.setInt("iat", now.Unix())
+	}
+	if p.AutoJTI && !target.has("jti") {
+		jti, err := newJTI()
+		if err != nil {
+			return err
+		}
+		target.setString("jti", jti)
+	}
+	if p.DefaultTTL > 0 && !target.has("exp") {
+		target.setInt("exp", now.Add(p.DefaultTTL).Unix())
+	}
+
+	if p.MaxTTL > 0 {
+		exp, ok := target.getInt("exp")
+		if !ok {
+			return fmt.Errorf("%w: exp is missing, maximum is %s", ErrTTLTooLong, p.MaxTTL)
+		}
+		if ttl := time.Unix(exp, 0).Sub(now); ttl > p.MaxTTL {
+			return fmt.Errorf("%w: exp is %s from now, maximum is %s", ErrTTLTooLong, ttl, p.MaxTTL)
+		}
+	}
+	return nil
+}
+
+// newJTI 生成 128 位随机 ID
+func newJTI() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// stampTarget 策略读写声明的统一接口
+type stampTarget interface {
+	has(key string) bool
+	getInt(key string) (int64, bool)
+	setInt(key string, value int64)
+	setString(key, value string)
+}
+
+// mapStamp MapClaims 适配器
+type mapStamp MapClaims
+
+func (m mapStamp) has(key string) bool {
+	_, exists := m[key]
+	return exists
+}
+
+func (m mapStamp) getInt(key string) (int64, bool) {
+	return GetClaimInt64(MapClaims(m), key)
+}
+
+func (m mapStamp) setInt(key string, value int64) {
+	m[key] = value
+}
+
+func (m mapStamp) setString(key, value string) {
+	m[key] = value
+}
+
+// standardStamp StandardClaims 适配器，零值视为缺失
+type standardStamp StandardClaims
+
+func (s *standardStamp) has(key string) bool {
+	switch key {
+	case "iat":
+		return s.IssuedAt != 0
+	case "exp":
+		return s.ExpiresAt != 0
+	case "jti":
+		return s.ID != ""
+	}
+	return false
+}
+
+func (s *standardStamp) getInt(key string) (int64, bool) {
+	switch key {
+	case "iat":
+		return s.IssuedAt, s.IssuedAt != 0
+	case "exp":
+		return s.ExpiresAt, s.ExpiresAt != 0
+	}
+	return 0, false
+}
+
+func (s *standardStamp) setInt(key string, value int64) {
+	switch key {
+	case "iat":
+		s.IssuedAt = value
+	case "exp":
+		s.ExpiresAt = value
+	}
+}
+
+func (s *standardStamp) setString(key, value string) {
+	if key == "jti" {
+		s.ID = value
+	}
+}
+
+// registeredStamp RegisteredClaims 适配器，nil 时间视为缺失
+type registeredStamp RegisteredClaims
+
+func (s *registeredStamp) has(key string) bool {
+	switch key {
+	case "iat":
+		return s.IssuedAt != nil
+	case "exp":
+		return s.ExpiresAt != nil
+	case "jti":
+		return s.ID != ""
+	}
+	return false
+}
+
+func (s *registeredStamp) getInt(key string) (int64, bool) {
+	switch key {
+	case "iat":
+		return numericDateUnix(s.IssuedAt), s.IssuedAt != nil
+	case "exp":
+		return numericDateUnix(s.ExpiresAt), s.ExpiresAt != nil
+	}
+	return 0, false
+}
+
+func (s *registeredStamp) setInt(key string, value int64) {
+	switch key {
+	case "iat":
+		s.IssuedAt = NewNumericDate(time.Unix(value, 0))
+	case "exp":
+		s.ExpiresAt = NewNumericDate(time.Unix(value, 0))
+	}
+}
+
+func (s *registeredStamp) setString(key, value string) {
+	if key == "jti" {
+		s.ID = value
+	}
+}
@@ -0,0 +1,207 @@
+package jwt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixedClock 返回固定时间的时钟
+func fixedClock(t time.Time) func() time.Time {
+	return func() time.Time { return t }
+}
+
+func TestStampPolicyKnobs(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	secret := []byte("stamp-secret")
+
+	t.Run("AutoIssuedAt", func(t *testing.T) {
+		j := New(SigningMethodHS256, secret).WithClock(fixedClock(now)).WithStampPolicy(StampPolicy{AutoIssuedAt: true})
+		claims := MapClaims{"sub": "u1"}
+		token, err := j.Generate(claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, _ := DecodeClaims(token)
+		if iat, _ := GetClaimInt64(decoded, "iat"); iat != now.Unix() {
+			t.Errorf("iat = %d, want %d", iat, now.Unix())
+		}
+		if _, exists := claims["iat"]; exists {
+			t.Error("caller's MapClaims must not be modified")
+		}
+
+		// 已有的 iat 保持不变
+		token, _ = j.Generate(MapClaims{"iat": int64(100)})
+		decoded, _ = DecodeClaims(token)
+		if iat, _ := GetClaimInt64(decoded, "iat"); iat != 100 {
+			t.Errorf("existing iat overwritten: %d", iat)
+		}
+	})
+
+	t.Run("AutoJTI", func(t *testing.T) {
+		j := New(SigningMethodHS256, secret).WithStampPolicy(StampPolicy{AutoJTI: true})
+		first, _ := j.Generate(MapClaims{})
+		second, _ := j.Generate(MapClaims{})
+		a, _ := DecodeClaims(first)
+		b, _ := DecodeClaims(second)
+		jtiA, _ := GetClaimString(a, "jti")
+		jtiB, _ := GetClaimString(b, "jti")
+		if len(jtiA) != 32 || jtiA == jtiB {
+			t.Errorf("jti should be unique random IDs, got %q and %q", jtiA, jtiB)
+		}
+
+		token, _ := j.Generate(MapClaims{"jti": "fixed"})
+		decoded, _ := DecodeClaims(token)
+		if jti, _ := GetClaimString(decoded, "jti"); jti != "fixed" {
+			t.Errorf("existing jti overwritten: %q", jti)
+		}
+	})
+
+	t.Run("DefaultTTL", func(t *testing.T) {
+		j := New(SigningMethodHS256, secret).WithClock(fixedClock(now)).WithStampPolicy(StampPolicy{DefaultTTL: 15 * time.Minute})
+		token, _ := j.Generate(MapClaims{})
+		decoded, _ := DecodeClaims(token)
+		if exp, _ := GetClaimInt64(decoded, "exp"); exp != now.Add(15*time.Minute).Unix() {
+			t.Errorf("exp = %d", exp)
+		}
+		if _, exists := decoded["iat"]; exists {
+			t.Error("iat should only be added by AutoIssuedAt")
+		}
+	})
+
+	t.Run("MaxTTL", func(t *testing.T) {
+		j := New(SigningMethodHS256, secret).WithClock(fixedClock(now)).WithStampPolicy(StampPolicy{MaxTTL: time.Hour})
+		if _, err := j.Generate(MapClaims{"exp": now.Add(time.Hour).Unix()}); err != nil {
+			t.Errorf("exp at MaxTTL should be accepted: %v", err)
+		}
+
+		_, err := j.Generate(MapClaims{"exp": float64(now.Add(48 * time.Hour).Unix())})
+		if !errors.Is(err, ErrTTLTooLong) || !strings.Contains(err.Error(), "exp is 48h0m0s from now, maximum is 1h0m0s") {
+			t.Errorf("far-future exp: got %v", err)
+		}
+		if _, err := j.Generate(MapClaims{"sub": "u1"}); !errors.Is(err, ErrTTLTooLong) {
+			t.Errorf("missing exp with MaxTTL: got %v", err)
+		}
+	})
+}
+
+func TestStampPolicyCombined(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	secret := []byte("stamp-secret")
+	j := New(SigningMethodHS256, secret).WithClock(fixedClock(now)).WithStampPolicy(StampPolicy{
+		AutoIssuedAt: true,
+		AutoJTI:      true,
+		DefaultTTL:   30 * time.Minute,
+		MaxTTL:       time.Hour,
+	})
+
+	token, err := j.Generate(MapClaims{"sub": "u1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := j.Parse(token)
+	if err != nil {
+		t.Fatalf("stamped token should validate: %v", err)
+	}
+	claims := parsed.Claims.(MapClaims)
+	iat, _ := GetClaimInt64(claims, "iat")
+	exp, _ := GetClaimInt64(claims, "exp")
+	if jti, _ := GetClaimString(claims, "jti"); iat != now.Unix() || exp != now.Add(30*time.Minute).Unix() || jti == "" {
+		t.Errorf("claims = %v", claims)
+	}
+
+	// DefaultTTL 超过 MaxTTL 时同样被拒绝
+	strict := j.WithStampPolicy(StampPolicy{DefaultTTL: 2 * time.Hour, MaxTTL: time.Hour})
+	if _, err := strict.Generate(MapClaims{}); !errors.Is(err, ErrTTLTooLong) {
+		t.Errorf("DefaultTTL beyond MaxTTL: got %v", err)
+	}
+}
+
+func TestStampPolicyStandardClaims(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	j := New(SigningMethodHS256, []byte("stamp-secret")).WithClock(fixedClock(now)).WithStampPolicy(StampPolicy{
+		AutoIssuedAt: true,
+		AutoJTI:      true,
+		DefaultTTL:   time.Hour,
+		MaxTTL:       2 * time.Hour,
+	})
+
+	original := &StandardClaims{Subject: "u1"}
+	for _, claims := range []Claims{StandardClaims{Subject: "u1"}, original} {
+		token, err := j.Generate(claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, _ := DecodeClaims(token)
+		iat, _ := GetClaimInt64(decoded, "iat")
+		exp, _ := GetClaimInt64(decoded, "exp")
+		jti, _ := GetClaimString(decoded, "jti")
+		if iat != now.Unix() || exp != now.Add(time.Hour).Unix() || jti == "" {
+			t.Errorf("%T not stamped: %v", claims, decoded)
+		}
+	}
+	if original.IssuedAt != 0 || original.ID != "" {
+		t.Error("caller's *StandardClaims must not be modified")
+	}
+
+	_, err := j.Generate(StandardClaims{ExpiresAt: now.Add(3 * time.Hour).Unix()})
+	if !errors.Is(err, ErrTTLTooLong) {
+		t.Errorf("StandardClaims beyond MaxTTL: got %v", err)
+	}
+}
+
+func TestWithClockAppliesToParse(t *testing.T) {
+	secret := []byte("stamp-secret")
+	offset := time.Now().Add(-48 * time.Hour)
+	j := New(SigningMethodHS256, secret).WithClock(fixedClock(offset)).WithStampPolicy(StampPolicy{DefaultTTL: time.Hour})
+
+	for _, claims := range []Claims{MapClaims{"sub": "user"}, StandardClaims{Subject: "user"}, &StandardClaims{Subject: "user"}} {
+		tokenString, err := j.Generate(claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// 同一实例按注入的时钟验证，令牌在该时间仍然有效
+		if _, err := j.Parse(tokenString); err != nil {
+			t.Errorf("%T: token minted with offset clock failed its own validation: %v", claims, err)
+		}
+		if _, err := j.ParseWithClaims(tokenString, &StandardClaims{}); err != nil {
+			t.Errorf("%T: ParseWithClaims(*StandardClaims) = %v", claims, err)
+		}
+
+		// 使用真实时间的实例认为令牌已过期
+		if _, err := New(SigningMethodHS256, secret).Parse(tokenString); !errors.Is(err, ErrTokenExpired) {
+			t.Errorf("%T: real clock err = %v, want ErrTokenExpired", claims, err)
+		}
+	}
+
+	// nbf 同样按注入的时钟检查
+	early := New(SigningMethodHS256, secret).WithClock(fixedClock(offset))
+	tokenString, _ := early.Generate(MapClaims{"nbf": offset.Add(time.Minute).Unix()})
+	if _, err := early.Parse(tokenString); !errors.Is(err, ErrTokenNotYetValid) {
+		t.Errorf("nbf err = %v, want ErrTokenNotYetValid", err)
+	}
+}
+
+func TestWithClockEmbeddedClaims(t *testing.T) {
+	secret := []byte("stamp-secret")
+	offset := time.Now().Add(-48 * time.Hour)
+	j := New(SigningMethodHS256, secret).WithClock(fixedClock(offset))
+	tokenString, _ := j.Generate(MapClaims{"role": "admin", "exp": offset.Add(time.Hour).Unix(), "nbf": offset.Add(-time.Minute).Unix()})
+
+	for _, claims := range []Claims{&embeddedStandardClaims{}, &embeddedRegisteredClaims{}} {
+		// 嵌入 StandardClaims / RegisteredClaims 的自定义类型同样按注入的时钟验证
+		if _, err := j.ParseWithClaims(tokenString, claims); err != nil {
+			t.Errorf("%T: injected clock err = %v", claims, err)
+		}
+		if _, err := New(SigningMethodHS256, secret).ParseWithClaims(tokenString, claims); !errors.Is(err, ErrTokenExpired) {
+			t.Errorf("%T: real clock err = %v, want ErrTokenExpired", claims, err)
+		}
+	}
+
+	early := j.WithClock(fixedClock(offset.Add(-time.Hour)))
+	if _, err := early.ParseWithClaims(tokenString, &embeddedRegisteredClaims{}); !errors.Is(err, ErrTokenNotYetValid) {
+		t.Errorf("nbf err = %v, want ErrTokenNotYetValid", err)
+	}
+}
@@ -0,0 +1,98 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+)
+
+// 密钥标识：RFC 7638 JWK 指纹，用于密钥轮换时在 kid 中标识签名密钥
+
+// RSAKeyThumbprint 计算 RSA 公钥的 JWK 指纹（RFC 7638）
+// 对 {"e":...,"kty":"RSA","n":...} 做 SHA-256 后进行 base64url 编码（无填充）
+func RSAKeyThumbprint(pub *rsa.PublicKey) string {
+	e := big.NewInt(int64(pub.E)).Bytes()
+	canonical := `{"e":"` + base64URLEncode(e) + `","kty":"RSA","n":"` + base64URLEncode(pub.N.Bytes()) + `"}`
+	return thumbprint(canonical)
+}
+
+// ECKeyThumbprint 计算 EC 公钥的 JWK 指纹（RFC 7638），支持 P-256、P-384 与 P-521
+// 坐标按曲线长度补齐前导零，对 {"crv":...,"kty":"EC","x":...,"y":...} 做 SHA-256 后进行 base64url 编码
+func ECKeyThumbprint(pub *ecdsa.PublicKey) (string, error) {
+	if pub == nil || pub.Curve == nil {
+		return "", fmt.Errorf("%w: nil EC public key", ErrInvalidKeyType)
+	}
+	params := pub.Curve.Params()
+	switch params.Name {
+	case "P-256", "P-384", "P-521":
+	default:
+		return "", fmt.Errorf("%w: unsupported EC curve %q", ErrInvalidKeyType, params.Name)
+	}
+
+	size := (params.BitSize + 7) / 8
+	x := pub.X.FillBytes(make([]byte, size))
+	y := pub.Y.FillBytes(make([]byte, size))
+	canonical := `{"crv":"` + params.Name + `","kty":"EC","x":"` + base64URLEncode(x) + `","y":"` + base64URLEncode(y) + `"}`
+	return thumbprint(canonical), nil
+}
+
+// Ed25519KeyThumbprint 计算 Ed25519 公钥的 JWK 指纹（RFC 8037 的 OKP 密钥）
+func Ed25519KeyThumbprint(pub ed25519.PublicKey) (string, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return "", fmt.Errorf("%w: Ed25519 public key must be %d bytes, got %d", ErrInvalidKeyType, ed25519.PublicKeySize, len(pub))
+	}
+	canonical := `{"crv":"Ed25519","kty":"OKP","x":"` + base64URLEncode(pub) + `"}`
+	return thumbprint(canonical), nil
+}
+
+// KeyIDFromKey 根据密钥类型计算 JWK 指纹，私钥使用其公钥部分，因此签名方与验证方得到相同的值
+// 支持 RSA、ECDSA 与 Ed25519 的公钥和私钥；HMAC 密钥没有可公开的指纹，返回 ErrInvalidKeyType
+func KeyIDFromKey(key interface{}) (string, error) {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if k == nil || k.N == nil {
+			return "", fmt.Errorf("%w: nil RSA public key", ErrInvalidKeyType)
+		}
+		return RSAKeyThumbprint(k), nil
+	case *rsa.PrivateKey:
+		if k == nil {
+			return "", fmt.Errorf("%w: nil RSA private key", ErrInvalidKeyType)
+		}
+		return KeyIDFromKey(&k.PublicKey)
+	case *ecdsa.PublicKey:
+		return ECKeyThumbprint(k)
+	case *ecdsa.PrivateKey:
+		if k == nil {
+			return "", fmt.Errorf("%w: nil EC private key", ErrInvalidKeyType)
+		}
+		return ECKeyThumbprint(&k.PublicKey)
+	case ed25519.PublicKey:
+		return Ed25519KeyThumbprint(k)
+	case ed25519.PrivateKey:
+		if len(k) != ed25519.PrivateKeySize {
+			return "", fmt.Errorf("%w: Ed25519 private key must be %d bytes, got %d", ErrInvalidKeyType, ed25519.PrivateKeySize, len(k))
+		}
+		return Ed25519KeyThumbprint(k.Public().(ed25519.PublicKey))
+	default:
+		return "", fmt.Errorf("%w: cannot derive key ID from %T", ErrInvalidKeyType, key)
+	}
+}
+
+// AutoKeyID 返回密钥的 JWK 指纹，用于 builder.SetKeyID(jwt.AutoKeyID(key))
+// 密钥类型不支持时返回空字符串（令牌不带 kid），需要处理错误时使用 KeyIDFromKey
+func AutoKeyID(key interface{}) string {
+	kid, err := KeyIDFromKey(key)
+	if err != nil {
+		return ""
+	}
+	return kid
+}
+
+// thumbprint 对规范化的 JWK 成员做 SHA-256 并进行 base64url 编码
+func thumbprint(canonical string) string {
+	sum := sha256.Sum256([]byte(canonical))
+	return base64URLEncode(sum[:])
+}
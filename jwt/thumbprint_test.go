@@ -0,0 +1,117 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// rfc7638Key RFC 7638 第 3.1 节示例中的 RSA 公钥
+func rfc7638Key(t *testing.T) *rsa.PublicKey {
+	t.Helper()
+	n, err := base64.RawURLEncoding.DecodeString("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}
+}
+
+func TestRSAKeyThumbprintRFC7638(t *testing.T) {
+	const want = "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"
+	if got := RSAKeyThumbprint(rfc7638Key(t)); got != want {
+		t.Fatalf("RSAKeyThumbprint = %s, want %s", got, want)
+	}
+	if got, err := KeyIDFromKey(rfc7638Key(t)); err != nil || got != want {
+		t.Fatalf("KeyIDFromKey = %s, %v", got, err)
+	}
+}
+
+func TestECKeyThumbprint(t *testing.T) {
+	// 坐标不足曲线长度时补齐前导零
+	pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: big.NewInt(1), Y: big.NewInt(2)}
+	x := make([]byte, 32)
+	x[31] = 1
+	y := make([]byte, 32)
+	y[31] = 2
+	canonical := `{"crv":"P-256","kty":"EC","x":"` + base64.RawURLEncoding.EncodeToString(x) +
+		`","y":"` + base64.RawURLEncoding.EncodeToString(y) + `"}`
+	sum := sha256.Sum256([]byte(canonical))
+	want := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	got, err := ECKeyThumbprint(pub)
+	if err != nil || got != want {
+		t.Fatalf("ECKeyThumbprint = %s, %v, want %s", got, err, want)
+	}
+
+	if _, err := ECKeyThumbprint(&ecdsa.PublicKey{Curve: elliptic.P224(), X: big.NewInt(1), Y: big.NewInt(1)}); !errors.Is(err, ErrInvalidKeyType) {
+		t.Fatalf("P-224 should be rejected, got %v", err)
+	}
+}
+
+func TestKeyIDFromKey(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	edPub, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	pairs := []struct {
+		name    string
+		private interface{}
+		public  interface{}
+	}{
+		{"rsa", rsaKey, &rsaKey.PublicKey},
+		{"ec", ecKey, &ecKey.PublicKey},
+		{"ed25519", edKey, edPub},
+	}
+	for _, p := range pairs {
+		priv, err := KeyIDFromKey(p.private)
+		if err != nil {
+			t.Fatalf("%s private: %v", p.name, err)
+		}
+		pub, err := KeyIDFromKey(p.public)
+		if err != nil {
+			t.Fatalf("%s public: %v", p.name, err)
+		}
+		if priv != pub || len(pub) != 43 {
+			t.Errorf("%s: private %s and public %s thumbprints should match", p.name, priv, pub)
+		}
+	}
+
+	if _, err := KeyIDFromKey([]byte("secret")); !errors.Is(err, ErrInvalidKeyType) {
+		t.Errorf("HMAC secret should be rejected, got %v", err)
+	}
+	if AutoKeyID([]byte("secret")) != "" {
+		t.Error("AutoKeyID should return empty string for unsupported keys")
+	}
+}
+
+func TestBuilderKeyID(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	builder := NewBuilder(SigningMethodRS256, key).SetKeyID(AutoKeyID(key)).SetSubject("user")
+
+	tokenString, err := builder.Fork().Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, err := DecodeHeader(tokenString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := RSAKeyThumbprint(&key.PublicKey); header.KeyID != want {
+		t.Fatalf("kid = %q, want %q", header.KeyID, want)
+	}
+	if _, err := ParseRS256(tokenString, &key.PublicKey); err != nil {
+		t.Fatalf("token with kid should verify: %v", err)
+	}
+
+	plain, _ := NewBuilder(SigningMethodHS256, []byte("secret")).Build()
+	if header, _ := DecodeHeader(plain); header.KeyID != "" {
+		t.Errorf("kid should be omitted by default, got %q", header.KeyID)
+	}
+}
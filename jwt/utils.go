@@ -11,9 +11,14 @@ import (
 )
 
 // JWTBuilder JWT 构建器，提供链式调用
+//
+// Set 系列方法直接修改构建器，同一个构建器不能在多个 goroutine 中同时修改。
+// 需要以公共声明为模板时，先设置好模板，再在每个 goroutine 中调用 Fork 得到独立的副本；
+// Build / BuildToken 使用声明的快照，之后修改构建器不会影响已生成的令牌。
 type JWTBuilder struct {
 	method SigningMethod
 	key    interface{}
+	keyID  string
 	claims MapClaims
 }
 
@@ -38,9 +43,16 @@ func (b *JWTBuilder) SetSubject(subject string) *JWTBuilder {
 	return b
 }
 
-// SetAudience 设置受众
-func (b *JWTBuilder) SetAudience(audience string) *JWTBuilder {
-	b.claims["aud"] = audience
+// SetAudience 设置受众，一个受众时写为字符串，多个时写为数组，不传参数时删除 aud
+func (b *JWTBuilder) SetAudience(audience ...string) *JWTBuilder {
+	switch len(audience) {
+	case 0:
+		delete(b.claims, "aud")
+	case 1:
+		b.claims["aud"] = audience[0]
+	default:
+		b.claims["aud"] = append([]string(nil), audience...)
+	}
 	return b
 }
 
@@ -80,6 +92,12 @@ func (b *JWTBuilder) SetJWTID(jti string) *JWTBuilder {
 	return b
 }
 
+// SetKeyID 设置头部的 kid，可配合 AutoKeyID 使用密钥指纹：SetKeyID(jwt.AutoKeyID(privateKey))
+func (b *JWTBuilder) SetKeyID(kid string) *JWTBuilder {
+	b.keyID = kid
+	return b
+}
+
 // SetClaim 设置自定义声明
 func (b *JWTBuilder) SetClaim(key string, value interface{}) *JWTBuilder {
 	b.claims[key] = value
@@ -94,18 +112,42 @@ func (b *JWTBuilder) SetClaims(claims map[string]interface{}) *JWTBuilder {
 	return b
 }
 
+// Fork 复制构建器，副本与原构建器的声明互不影响，可在其他 goroutine 中继续修改
+// 只复制顶层声明，嵌套的 map / slice 值仍然共享，不应原地修改
+func (b *JWTBuilder) Fork() *JWTBuilder {
+	return &JWTBuilder{
+		method: b.method,
+		key:    b.key,
+		keyID:  b.keyID,
+		claims: b.snapshot(),
+	}
+}
+
 // Build 构建 JWT 令牌字符串
 func (b *JWTBuilder) Build() (string, error) {
-	jwt := New(b.method, b.key)
-	return jwt.Generate(b.claims)
+	token, err := b.BuildToken()
+	if err != nil {
+		return "", err
+	}
+	return token.SignedString(b.key)
 }
 
 // BuildToken 构建 JWT 令牌对象
 func (b *JWTBuilder) BuildToken() (*Token, error) {
-	token := NewWithClaims(b.method, b.claims)
+	token := NewWithClaims(b.method, b.snapshot())
+	token.Header.KeyID = b.keyID
 	return token, nil
 }
 
+// snapshot 复制当前声明
+func (b *JWTBuilder) snapshot() MapClaims {
+	claims := make(MapClaims, len(b.claims))
+	for k, v := range b.claims {
+		claims[k] = v
+	}
+	return claims
+}
+
 // 通用便捷函数
 
 // Generate 使用指定算法生成 JWT
@@ -263,11 +305,15 @@ func NewMapClaims() MapClaims {
 }
 
 // GetClaimString 从 MapClaims 中获取字符串声明
+// 只有一个元素的字符串数组（如 "aud": ["api"]）同样返回该元素，多个元素时使用 GetClaimStringSlice
 func GetClaimString(claims MapClaims, key string) (string, bool) {
 	if value, exists := claims[key]; exists {
 		if str, ok := value.(string); ok {
 			return str, true
 		}
+		if values, ok := GetClaimStringSlice(claims, key); ok && len(values) == 1 {
+			return values[0], true
+		}
 	}
 	return "", false
 }
@@ -324,21 +370,26 @@ func ExtractClaims(token *Token) (MapClaims, bool) {
 
 // ValidateStandardClaims 验证标准声明
 func ValidateStandardClaims(claims MapClaims, audience, issuer, subject string) error {
-	now := time.Now().Unix()
+	return ValidateStandardClaimsWithLeeway(claims, audience, issuer, subject, 0)
+}
+
+// ValidateStandardClaimsWithLeeway 验证标准声明，exp / nbf 按 leeway 放宽时钟偏差
+func ValidateStandardClaimsWithLeeway(claims MapClaims, audience, issuer, subject string, leeway time.Duration) error {
+	now := time.Now()
 
 	// 验证过期时间
-	if exp, exists := GetClaimInt64(claims, "exp"); exists && now > exp {
+	if exp, exists := GetClaimInt64(claims, "exp"); exists && expiredAt(now, exp, leeway) {
 		return ErrTokenExpired
 	}
 
 	// 验证生效时间
-	if nbf, exists := GetClaimInt64(claims, "nbf"); exists && now < nbf {
+	if nbf, exists := GetClaimInt64(claims, "nbf"); exists && notYetValidAt(now, nbf, leeway) {
 		return ErrTokenNotYetValid
 	}
 
 	// 验证受众
 	if audience != "" {
-		if aud, exists := GetClaimString(claims, "aud"); !exists || aud != audience {
+		if !hasAudience(claims, audience) {
 			return ErrInvalidAudience
 		}
 	}
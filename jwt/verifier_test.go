@@ -0,0 +1,155 @@
+package jwt
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// verifierCorpus 生成包含有效、过期、篡改、错误算法和畸形令牌的随机语料
+func verifierCorpus(t testing.TB, size int) []string {
+	rng := rand.New(rand.NewSource(42))
+	now := time.Now()
+	tokens := make([]string, 0, size)
+
+	for i := 0; i < size; i++ {
+		// 过期时间远离当前时刻，避免 Parse 与 Verify 的判定跨越边界
+		offset := time.Duration(rng.Intn(7200)+60) * time.Second
+		if rng.Intn(4) == 0 {
+			offset = -offset
+		}
+		claims := MapClaims{
+			"sub":   fmt.Sprintf("user-%d", rng.Intn(1000)),
+			"iat":   now.Unix(),
+			"exp":   now.Add(offset).Unix(),
+			"roles": []interface{}{"reader", fmt.Sprintf("team-%d", rng.Intn(10))},
+			"n":     rng.Float64(),
+		}
+		if rng.Intn(10) == 0 {
+			claims["nbf"] = now.Add(time.Hour).Unix()
+		}
+
+		method := SigningMethod(SigningMethodHS256)
+		if rng.Intn(20) == 0 {
+			method = SigningMethodHS512
+		}
+		token, err := NewWithClaims(method, claims).SignedString(hmacSampleSecret)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		switch rng.Intn(12) {
+		case 0: // 篡改签名
+			token = token[:len(token)-3] + "abc"
+		case 1: // 篡改声明
+			parts := strings.Split(token, ".")
+			parts[1] = base64URLEncode([]byte(`{"sub":"admin"}`))
+			token = strings.Join(parts, ".")
+		case 2: // 畸形令牌
+			token = []string{"", "a.b", "a.b.c.d", "!!!.e30.sig", token[:len(token)/2], "e30.bnVsbA.x"}[rng.Intn(6)]
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+func TestVerifierMatchesParse(t *testing.T) {
+	parser := New(SigningMethodHS256, hmacSampleSecret)
+	verifier := NewVerifier(SigningMethodHS256, hmacSampleSecret)
+	tokens := verifierCorpus(t, 5000)
+
+	results := verifier.VerifyBatch(tokens, 8)
+	valid := 0
+	for i, tokenString := range tokens {
+		token, parseErr := parser.Parse(tokenString)
+		claims, verifyErr := verifier.Verify(tokenString)
+
+		if fmt.Sprint(parseErr) != fmt.Sprint(verifyErr) {
+			t.Fatalf("token %d: Parse error %v, Verify error %v", i, parseErr, verifyErr)
+		}
+		if fmt.Sprint(results[i].Err) != fmt.Sprint(parseErr) {
+			t.Fatalf("token %d: VerifyBatch error %v, Parse error %v", i, results[i].Err, parseErr)
+		}
+		if parseErr != nil {
+			continue
+		}
+		valid++
+		if !reflect.DeepEqual(token.Claims.(MapClaims), claims) || !reflect.DeepEqual(claims, results[i].Claims) {
+			t.Fatalf("token %d: claims differ", i)
+		}
+	}
+
+	if valid == 0 || valid == len(tokens) {
+		t.Fatalf("corpus should mix valid and invalid tokens, got %d valid of %d", valid, len(tokens))
+	}
+}
+
+func TestVerifierRSAAndKeyErrors(t *testing.T) {
+	privateKey, err := GenerateRSAKeyPair(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicPEM, err := PublicKeyToPEM(&privateKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenString, err := GenerateRS256(privateKey, MapClaims{"sub": "rsa"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []interface{}{publicPEM, &privateKey.PublicKey, privateKey} {
+		claims, err := NewVerifier(SigningMethodRS256, key).Verify(tokenString)
+		if err != nil || claims["sub"] != "rsa" {
+			t.Errorf("key %T: claims %v, err %v", key, claims, err)
+		}
+	}
+
+	if _, err := NewVerifier(SigningMethodRS256, []byte("not pem")).Verify(tokenString); err != ErrKeyMustBePEM {
+		t.Errorf("expected ErrKeyMustBePEM, got %v", err)
+	}
+	hsToken, _ := GenerateHS256(hmacSampleSecret, MapClaims{"sub": "1"})
+	if _, err := NewVerifier(SigningMethodHS256, "string key").Verify(hsToken); err != ErrInvalidKeyType {
+		t.Errorf("expected ErrInvalidKeyType, got %v", err)
+	}
+	if results := NewVerifier(SigningMethodHS256, hmacSampleSecret).VerifyBatch(nil, 4); len(results) != 0 {
+		t.Error("empty batch should return no results")
+	}
+}
+
+func BenchmarkParseLoop(b *testing.B) {
+	parser := New(SigningMethodHS256, hmacSampleSecret)
+	tokens := verifierCorpus(b, 1000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		parser.Parse(tokens[i%len(tokens)])
+	}
+}
+
+func BenchmarkVerifier(b *testing.B) {
+	verifier := NewVerifier(SigningMethodHS256, hmacSampleSecret)
+	tokens := verifierCorpus(b, 1000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		verifier.Verify(tokens[i%len(tokens)])
+	}
+}
+
+func BenchmarkVerifyBatch(b *testing.B) {
+	verifier := NewVerifier(SigningMethodHS256, hmacSampleSecret)
+	tokens := verifierCorpus(b, 1000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		verifier.VerifyBatch(tokens, 0)
+	}
+}
@@ -0,0 +1,157 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 类型化的环境变量读取
+//
+// 读取时去除首尾空白以及成对的首尾引号（"..." 或 '...'），去除后为空视为未设置。
+// EnvXxx 在未设置或解析失败时返回默认值；TryEnvXxx 返回错误，便于区分"未设置"与"格式错误"。
+
+// ErrEnvNotSet 环境变量未设置或为空，可通过 errors.Is 判断
+var ErrEnvNotSet = errors.New("environment variable not set")
+
+// MissingEnvError RequireEnv 返回的错误，一次性列出所有缺失的环境变量
+type MissingEnvError struct {
+	Keys []string
+}
+
+func (e *MissingEnvError) Error() string {
+	return "missing required environment variables: " + strings.Join(e.Keys, ", ")
+}
+
+// Unwrap 使 errors.Is(err, ErrEnvNotSet) 成立
+func (e *MissingEnvError) Unwrap() error {
+	return ErrEnvNotSet
+}
+
+// EnvStr 读取字符串环境变量，未设置时返回 def
+func EnvStr(key string, def string) XStr {
+	if value, ok := lookupEnv(key); ok {
+		return value
+	}
+	return XStr(def)
+}
+
+// EnvInt 读取整数环境变量，未设置或不是合法整数时返回 def
+func EnvInt(key string, def int) int {
+	if i, err := TryEnvInt(key); err == nil {
+		return i
+	}
+	return def
+}
+
+// EnvBool 读取布尔环境变量（按 strconv.ParseBool 规则，如 1/true/TRUE/0/false），未设置或非法时返回 def
+func EnvBool(key string, def bool) bool {
+	if b, err := TryEnvBool(key); err == nil {
+		return b
+	}
+	return def
+}
+
+// EnvDuration 读取时长环境变量（如 "1h30m"、"500ms"），未设置或非法时返回 def
+func EnvDuration(key string, def time.Duration) time.Duration {
+	if d, err := TryEnvDuration(key); err == nil {
+		return d
+	}
+	return def
+}
+
+// EnvSlice 以 sep 分割环境变量，每个元素去除首尾空白并忽略空元素，未设置时返回空数组
+func EnvSlice(key, sep string) XArray[string] {
+	value, ok := lookupEnv(key)
+	if !ok {
+		return XArray[string]{}
+	}
+	result := XArray[string]{}
+	for _, item := range value.Split(sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// TryEnvInt 读取整数环境变量，未设置时返回 ErrEnvNotSet，格式错误时返回解析错误
+func TryEnvInt(key string) (int, error) {
+	value, err := requireEnv(key)
+	if err != nil {
+		return 0, err
+	}
+	i, err := strconv.Atoi(value.String())
+	if err != nil {
+		return 0, fmt.Errorf("environment variable %s: invalid integer %q", key, value)
+	}
+	return i, nil
+}
+
+// TryEnvBool 读取布尔环境变量，未设置时返回 ErrEnvNotSet，格式错误时返回解析错误
+func TryEnvBool(key string) (bool, error) {
+	value, err := requireEnv(key)
+	if err != nil {
+		return false, err
+	}
+	b, err := strconv.ParseBool(value.String())
+	if err != nil {
+		return false, fmt.Errorf("environment variable %s: invalid bool %q", key, value)
+	}
+	return b, nil
+}
+
+// TryEnvDuration 读取时长环境变量，未设置时返回 ErrEnvNotSet，格式错误时返回解析错误
+func TryEnvDuration(key string) (time.Duration, error) {
+	value, err := requireEnv(key)
+	if err != nil {
+		return 0, err
+	}
+	d, err := time.ParseDuration(value.String())
+	if err != nil {
+		return 0, fmt.Errorf("environment variable %s: invalid duration %q", key, value)
+	}
+	return d, nil
+}
+
+// RequireEnv 检查所有 keys 均已设置且非空，缺失时返回列出全部缺失变量的 *MissingEnvError，
+// 适合在程序启动时一次性校验配置
+func RequireEnv(keys ...string) error {
+	var missing []string
+	for _, key := range keys {
+		if _, ok := lookupEnv(key); !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return &MissingEnvError{Keys: missing}
+	}
+	return nil
+}
+
+// requireEnv 读取环境变量，未设置时返回包含变量名的 ErrEnvNotSet
+func requireEnv(key string) (XStr, error) {
+	value, ok := lookupEnv(key)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrEnvNotSet, key)
+	}
+	return value, nil
+}
+
+// lookupEnv 读取并清理环境变量，去除空白与成对引号后为空视为未设置
+func lookupEnv(key string) (XStr, bool) {
+	raw, ok := os.LookupEnv(key)
+	if !ok {
+		return "", false
+	}
+	value := strings.TrimSpace(raw)
+	if len(value) >= 2 {
+		if first, last := value[0], value[len(value)-1]; first == last && (first == '"' || first == '\'') {
+			value = value[1 : len(value)-1]
+		}
+	}
+	return XStr(value), value != ""
+}
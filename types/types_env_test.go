@@ -0,0 +1,124 @@
+package types
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEnvStr(t *testing.T) {
+	t.Setenv("XENV_PLAIN", "  hello  ")
+	t.Setenv("XENV_DOUBLE", `"quoted value"`)
+	t.Setenv("XENV_SINGLE", " 'single' ")
+	t.Setenv("XENV_MISMATCH", `"half'`)
+	t.Setenv("XENV_EMPTY", `""`)
+
+	tests := map[string]string{
+		"XENV_PLAIN":    "hello",
+		"XENV_DOUBLE":   "quoted value",
+		"XENV_SINGLE":   "single",
+		"XENV_MISMATCH": `"half'`,
+		"XENV_EMPTY":    "fallback",
+		"XENV_UNSET":    "fallback",
+	}
+	for key, want := range tests {
+		if got := EnvStr(key, "fallback"); got.String() != want {
+			t.Errorf("EnvStr(%s) = %q, want %q", key, got, want)
+		}
+	}
+
+	// 返回 XStr，可继续链式调用
+	if got := EnvStr("XENV_PLAIN", "").Append(", world"); got != "hello, world" {
+		t.Errorf("chained Append = %q", got)
+	}
+}
+
+func TestEnvIntBool(t *testing.T) {
+	t.Setenv("XENV_PORT", " 8080 ")
+	t.Setenv("XENV_BAD_INT", "80x")
+	t.Setenv("XENV_ON", `"true"`)
+	t.Setenv("XENV_OFF", "0")
+	t.Setenv("XENV_BAD_BOOL", "yes please")
+
+	if got := EnvInt("XENV_PORT", 1); got != 8080 {
+		t.Errorf("EnvInt = %d, want 8080", got)
+	}
+	if got := EnvInt("XENV_BAD_INT", 1); got != 1 {
+		t.Errorf("EnvInt with bad value = %d, want default 1", got)
+	}
+	if got := EnvInt("XENV_UNSET", 7); got != 7 {
+		t.Errorf("EnvInt unset = %d, want 7", got)
+	}
+	if got := EnvBool("XENV_ON", false); !got {
+		t.Error("EnvBool(XENV_ON) = false")
+	}
+	if got := EnvBool("XENV_OFF", true); got {
+		t.Error("EnvBool(XENV_OFF) = true")
+	}
+	if got := EnvBool("XENV_BAD_BOOL", true); !got {
+		t.Error("EnvBool with bad value should fall back to default true")
+	}
+
+	if _, err := TryEnvInt("XENV_BAD_INT"); err == nil || !strings.Contains(err.Error(), `XENV_BAD_INT: invalid integer "80x"`) {
+		t.Errorf("TryEnvInt error = %v", err)
+	}
+	if _, err := TryEnvBool("XENV_BAD_BOOL"); err == nil || errors.Is(err, ErrEnvNotSet) {
+		t.Errorf("TryEnvBool error = %v, want parse error", err)
+	}
+	if _, err := TryEnvInt("XENV_UNSET"); !errors.Is(err, ErrEnvNotSet) {
+		t.Errorf("TryEnvInt unset error = %v, want ErrEnvNotSet", err)
+	}
+}
+
+func TestEnvDuration(t *testing.T) {
+	t.Setenv("XENV_TIMEOUT", "1m30s")
+	t.Setenv("XENV_BAD_TIMEOUT", "90")
+
+	if got := EnvDuration("XENV_TIMEOUT", time.Second); got != 90*time.Second {
+		t.Errorf("EnvDuration = %v", got)
+	}
+	if got := EnvDuration("XENV_BAD_TIMEOUT", time.Second); got != time.Second {
+		t.Errorf("EnvDuration with bad value = %v, want default", got)
+	}
+	if _, err := TryEnvDuration("XENV_BAD_TIMEOUT"); err == nil {
+		t.Error("TryEnvDuration should reject a bare number")
+	}
+}
+
+func TestEnvSlice(t *testing.T) {
+	t.Setenv("XENV_HOSTS", `" a.example , b.example,,c.example "`)
+
+	want := XArray[string]{"a.example", "b.example", "c.example"}
+	if got := EnvSlice("XENV_HOSTS", ","); !reflect.DeepEqual(got, want) {
+		t.Errorf("EnvSlice = %v, want %v", got, want)
+	}
+	if got := EnvSlice("XENV_UNSET", ","); got == nil || len(got) != 0 {
+		t.Errorf("EnvSlice unset = %#v, want empty", got)
+	}
+}
+
+func TestRequireEnv(t *testing.T) {
+	t.Setenv("XENV_A", "1")
+	t.Setenv("XENV_BLANK", "   ")
+
+	if err := RequireEnv("XENV_A"); err != nil {
+		t.Fatalf("RequireEnv = %v", err)
+	}
+
+	err := RequireEnv("XENV_MISSING_1", "XENV_A", "XENV_BLANK", "XENV_MISSING_2")
+	var missing *MissingEnvError
+	if !errors.As(err, &missing) {
+		t.Fatalf("error = %v, want *MissingEnvError", err)
+	}
+	if want := []string{"XENV_MISSING_1", "XENV_BLANK", "XENV_MISSING_2"}; !reflect.DeepEqual(missing.Keys, want) {
+		t.Errorf("Keys = %v, want %v", missing.Keys, want)
+	}
+	if !errors.Is(err, ErrEnvNotSet) {
+		t.Error("errors.Is(err, ErrEnvNotSet) = false")
+	}
+	if want := "missing required environment variables: XENV_MISSING_1, XENV_BLANK, XENV_MISSING_2"; err.Error() != want {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
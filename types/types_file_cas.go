@@ -0,0 +1,101 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 内容寻址存储：文件按 SHA-256 存放在 rootDir/ab/cd/<hash>
+
+// StoreByHash 以内容的 SHA-256 为名将文件存入 rootDir，返回存储后的文件与哈希
+// 内容先流式写入 rootDir 下的临时文件再原子重命名，目标已存在时跳过写入，
+// 因此并发存储相同内容不会互相破坏
+func (f XFile) StoreByHash(rootDir string) (XFile, string, error) {
+	src, err := os.Open(f.path)
+	if err != nil {
+		return XFile{}, "", err
+	}
+	defer src.Close()
+
+	if err := os.MkdirAll(rootDir, 0755); err != nil {
+		return XFile{}, "", err
+	}
+	tmp, err := os.CreateTemp(rootDir, ".cas-*.tmp")
+	if err != nil {
+		return XFile{}, "", err
+	}
+	tmpPath := tmp.Name()
+	committed := false
+	defer func() {
+		if !committed {
+			tmp.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hasher), src); err != nil {
+		return XFile{}, "", err
+	}
+	hash := hex.EncodeToString(hasher.Sum(nil))
+	target := casPath(rootDir, hash)
+
+	if File(target).IsFile() {
+		return File(target), hash, nil
+	}
+
+	if err := tmp.Sync(); err != nil {
+		return XFile{}, "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return XFile{}, "", err
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return XFile{}, "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return XFile{}, "", err
+	}
+	if err := os.Rename(tmpPath, target); err != nil {
+		// 部分平台不允许覆盖已存在的文件，此时目标已由并发写入者完成
+		if File(target).IsFile() {
+			return File(target), hash, nil
+		}
+		return XFile{}, "", err
+	}
+	committed = true
+
+	return File(target), hash, nil
+}
+
+// LookupByHash 在 rootDir 中查找指定哈希的文件
+func LookupByHash(rootDir, hash string) (XFile, bool) {
+	hash = strings.ToLower(hash)
+	if !isSHA256Hex(hash) {
+		return XFile{}, false
+	}
+
+	file := File(casPath(rootDir, hash))
+	if !file.IsFile() {
+		return XFile{}, false
+	}
+	return file, true
+}
+
+// casPath 计算哈希对应的两级分散存储路径
+func casPath(rootDir, hash string) string {
+	return filepath.Join(rootDir, hash[0:2], hash[2:4], hash)
+}
+
+// isSHA256Hex 判断是否为 64 位小写十六进制字符串
+func isSHA256Hex(s string) bool {
+	if len(s) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,87 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestStoreByHashConcurrentDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "store")
+	content := []byte(strings.Repeat("asset-content-", 4096))
+	sum := sha256.Sum256(content)
+	expectedHash := hex.EncodeToString(sum[:])
+
+	const workers = 16
+	sources := make([]XFile, workers)
+	for i := range sources {
+		sources[i] = File(filepath.Join(dir, fmt.Sprintf("src-%d.bin", i)))
+		if err := sources[i].Write(content); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(src XFile) {
+			defer wg.Done()
+			stored, hash, err := src.StoreByHash(root)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if hash != expectedHash {
+				errs <- fmt.Errorf("hash = %s, want %s", hash, expectedHash)
+			}
+			if stored.Path() != filepath.Join(root, hash[:2], hash[2:4], hash) {
+				errs <- fmt.Errorf("unexpected path %s", stored.Path())
+			}
+		}(sources[i])
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	var files []string
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if len(files) != 1 {
+		t.Fatalf("expected exactly one stored file, got %v", files)
+	}
+
+	stored, ok := LookupByHash(root, strings.ToUpper(expectedHash))
+	if !ok {
+		t.Fatal("LookupByHash should find stored content")
+	}
+	data, err := stored.Read()
+	if err != nil || string(data) != string(content) {
+		t.Error("stored content differs from source")
+	}
+}
+
+func TestLookupByHashMissing(t *testing.T) {
+	root := t.TempDir()
+	if _, ok := LookupByHash(root, strings.Repeat("a", 64)); ok {
+		t.Error("missing hash should not be found")
+	}
+	if _, ok := LookupByHash(root, "../../etc/passwd"); ok {
+		t.Error("invalid hash should be rejected")
+	}
+	if _, _, err := File(filepath.Join(root, "missing")).StoreByHash(root); err == nil {
+		t.Error("storing a missing file should fail")
+	}
+}
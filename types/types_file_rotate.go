@@ -0,0 +1,262 @@
+package types
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 按大小轮转的追加写入器，适合没有日志框架的服务自行落盘
+
+// rotateTimeLayout 备份文件名中的时间格式，按字典序即按时间排序，且不含 Windows 不允许的冒号
+const rotateTimeLayout = "2006-01-02T15-04-05.000"
+
+// RotatingWriter 追加写入文件，超过 maxSize 时将当前文件重命名为带时间戳的备份并重新创建
+//
+// 备份命名为 <名称>-<时间戳><扩展名>，如 app.log 轮转为 app-2024-01-02T15-04-05.000.log，
+// 启用压缩时再追加 .gz。可安全地被多个 goroutine 并发调用。
+type RotatingWriter struct {
+	mu         sync.Mutex
+	file       XFile
+	out        *os.File
+	size       int64
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	compress   bool
+	now        func() time.Time
+	rename     func(oldpath, newpath string) error
+}
+
+// NewRotatingWriter 创建轮转写入器，文件已存在时在末尾追加
+//
+// maxSize 为单个文件的最大字节数，0 表示不轮转；maxAge 与 maxBackups 控制备份保留的时长与个数，
+// 0 表示不限制；compress 为 true 时轮转后的备份使用 gzip 压缩。
+// 单次写入超过 maxSize 时不会被拆分，而是完整写入新文件。
+func NewRotatingWriter(path string, maxSize int64, maxAge time.Duration, maxBackups int, compress ...bool) (io.WriteCloser, error) {
+	w := &RotatingWriter{
+		file:       File(path),
+		maxSize:    maxSize,
+		maxAge:     maxAge,
+		maxBackups: maxBackups,
+		compress:   len(compress) > 0 && compress[0],
+		now:        time.Now,
+		rename:     os.Rename,
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write 写入数据，写入后将超过 maxSize 时先轮转
+//
+// 轮转失败（如重命名或创建新文件出错）时数据仍写入当前文件，并返回写入的字节数与轮转错误；
+// 写入器保持可用，下次写入时会再次尝试轮转。
+func (w *RotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.out == nil {
+		return 0, os.ErrClosed
+	}
+	var rotateErr error
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		rotateErr = w.rotate()
+	}
+
+	n, err := w.out.Write(p)
+	w.size += int64(n)
+	if err != nil {
+		return n, err
+	}
+	return n, rotateErr
+}
+
+// Rotate 立即轮转当前文件，当前文件为空时不产生备份
+func (w *RotatingWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.out == nil {
+		return os.ErrClosed
+	}
+	if w.size == 0 {
+		return nil
+	}
+	return w.rotate()
+}
+
+// Close 关闭当前文件，之后的写入返回 os.ErrClosed
+func (w *RotatingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.out == nil {
+		return nil
+	}
+	err := w.out.Close()
+	w.out = nil
+	return err
+}
+
+// open 以追加模式打开当前文件并记录已有大小
+func (w *RotatingWriter) open() error {
+	out, err := w.file.AppendStream()
+	if err != nil {
+		return err
+	}
+	info, err := out.Stat()
+	if err != nil {
+		out.Close()
+		return err
+	}
+	w.out = out
+	w.size = info.Size()
+	return nil
+}
+
+// rotate 重命名当前文件并打开新文件，再按需压缩、清理过期备份，调用方需持有锁
+//
+// 新文件打开成功后才关闭原句柄：重命名失败时当前文件保持不变；新文件打开失败时将备份移回原位置，
+// 原句柄始终指向同一文件，因此任何一步失败后写入器仍可继续写入。
+// 压缩与清理发生在切换之后，它们的错误会返回，但不影响后续写入。
+func (w *RotatingWriter) rotate() error {
+	now := w.now()
+	backup := w.backupPath(now)
+	if err := w.rename(w.file.Path(), backup); err != nil {
+		return fmt.Errorf("rotate %s: %w", w.file.Path(), err)
+	}
+
+	old := w.out
+	if err := w.open(); err != nil {
+		if rerr := w.rename(backup, w.file.Path()); rerr != nil {
+			return fmt.Errorf("rotate %s: %w (restore backup: %v)", w.file.Path(), err, rerr)
+		}
+		return fmt.Errorf("rotate %s: %w", w.file.Path(), err)
+	}
+	old.Close()
+
+	if w.compress {
+		if err := gzipFile(backup); err != nil {
+			return err
+		}
+	}
+	return w.prune(now)
+}
+
+// backupPath 生成备份路径，同一毫秒内多次轮转时追加序号避免覆盖
+func (w *RotatingWriter) backupPath(t time.Time) string {
+	stamp := t.Format(rotateTimeLayout)
+	for i := 0; ; i++ {
+		name := w.file.BaseName() + "-" + stamp
+		if i > 0 {
+			name += fmt.Sprintf("-%d", i)
+		}
+		candidate := w.file.DirFile().Join(name + w.file.Ext())
+		if !candidate.Exists() && !File(candidate.Path()+".gz").Exists() {
+			return candidate.Path()
+		}
+	}
+}
+
+// rotatedBackup 已存在的备份文件
+type rotatedBackup struct {
+	file XFile
+	at   time.Time
+	seq  int // 同一时间戳下的序号
+}
+
+// backups 列出当前文件的所有备份，按时间从新到旧排列
+func (w *RotatingWriter) backups() ([]rotatedBackup, error) {
+	files, err := w.file.DirFile().ListFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	prefix, ext := w.file.BaseName()+"-", w.file.Ext()
+	var result []rotatedBackup
+	for _, f := range files {
+		name := strings.TrimSuffix(f.Name(), ".gz")
+		if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		if len(stamp) < len(rotateTimeLayout) {
+			continue
+		}
+		at, err := time.ParseInLocation(rotateTimeLayout, stamp[:len(rotateTimeLayout)], time.Local)
+		if err != nil {
+			continue
+		}
+		seq, _ := strconv.Atoi(strings.TrimPrefix(stamp[len(rotateTimeLayout):], "-"))
+		result = append(result, rotatedBackup{file: f, at: at, seq: seq})
+	}
+
+	sort.SliceStable(result, func(a, b int) bool {
+		if !result[a].at.Equal(result[b].at) {
+			return result[a].at.After(result[b].at)
+		}
+		return result[a].seq > result[b].seq
+	})
+	return result, nil
+}
+
+// prune 删除超出个数或超过保留时长的备份
+func (w *RotatingWriter) prune(now time.Time) error {
+	if w.maxBackups <= 0 && w.maxAge <= 0 {
+		return nil
+	}
+	list, err := w.backups()
+	if err != nil {
+		return err
+	}
+
+	cutoff := now.Add(-w.maxAge)
+	for i, b := range list {
+		expired := w.maxAge > 0 && b.at.Before(cutoff)
+		if (w.maxBackups > 0 && i >= w.maxBackups) || expired {
+			if err := b.file.Delete(); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// gzipFile 将文件压缩为 path.gz 并删除原文件
+func gzipFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	if _, err := io.Copy(zw, src); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+	src.Close()
+	return os.Remove(path)
+}
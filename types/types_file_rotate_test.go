@@ -0,0 +1,335 @@
+package types
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestRotatingWriter 创建使用可控时钟的写入器，每次读取时钟前进 1 秒
+func newTestRotatingWriter(t *testing.T, path string, maxSize int64, maxAge time.Duration, maxBackups int, compress bool) (*RotatingWriter, *time.Time) {
+	t.Helper()
+	wc, err := NewRotatingWriter(path, maxSize, maxAge, maxBackups, compress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := wc.(*RotatingWriter)
+	clock := time.Date(2024, 1, 2, 15, 4, 5, 0, time.Local)
+	w.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	t.Cleanup(func() { w.Close() })
+	return w, &clock
+}
+
+func dirNames(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func readFileString(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestRotatingWriterRotatesAndKeepsBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	w, _ := newTestRotatingWriter(t, path, 10, 0, 2, false)
+
+	for _, line := range []string{"aaaa\n", "bbbb\n", "cccc\n", "dddd\n", "eeee\n", "ffff\n", "gg\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 每个文件容纳两行：aaaa+bbbb、cccc+dddd、eeee+ffff 依次轮转，最旧的备份被清理
+	want := []string{
+		"app-2024-01-02T15-04-07.000.log",
+		"app-2024-01-02T15-04-08.000.log",
+		"app.log",
+	}
+	if got := dirNames(t, dir); !reflect.DeepEqual(got, want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	if got := readFileString(t, filepath.Join(dir, want[0])); got != "cccc\ndddd\n" {
+		t.Errorf("older backup = %q", got)
+	}
+	if got := readFileString(t, filepath.Join(dir, want[1])); got != "eeee\nffff\n" {
+		t.Errorf("newer backup = %q", got)
+	}
+	if got := readFileString(t, path); got != "gg\n" {
+		t.Errorf("current file = %q", got)
+	}
+}
+
+func TestRotatingWriterOversizedWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "big.log")
+	w, _ := newTestRotatingWriter(t, path, 4, 0, 0, false)
+
+	for _, chunk := range []string{"0123456789", "ab", "cdefgh"} {
+		if n, err := w.Write([]byte(chunk)); err != nil || n != len(chunk) {
+			t.Fatalf("Write(%q) = %d, %v", chunk, n, err)
+		}
+	}
+
+	want := []string{
+		"big-2024-01-02T15-04-06.000.log",
+		"big-2024-01-02T15-04-07.000.log",
+		"big.log",
+	}
+	if got := dirNames(t, dir); !reflect.DeepEqual(got, want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	if got := readFileString(t, filepath.Join(dir, want[0])); got != "0123456789" {
+		t.Errorf("first backup = %q", got)
+	}
+	if got := readFileString(t, filepath.Join(dir, want[1])); got != "ab" {
+		t.Errorf("second backup = %q", got)
+	}
+	if got := readFileString(t, path); got != "cdefgh" {
+		t.Errorf("current file = %q", got)
+	}
+}
+
+func TestRotatingWriterPrunesByAge(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "svc.log")
+	w, clock := newTestRotatingWriter(t, path, 3, time.Hour, 0, false)
+
+	w.Write([]byte("old"))
+	w.Write([]byte("mid")) // 轮转出 15-04-06 的备份
+	*clock = clock.Add(2 * time.Hour)
+	w.Write([]byte("new")) // 轮转出 17-04-07 的备份，15-04-06 已超过 1 小时
+
+	want := []string{"svc-2024-01-02T17-04-07.000.log", "svc.log"}
+	if got := dirNames(t, dir); !reflect.DeepEqual(got, want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	if got := readFileString(t, filepath.Join(dir, want[0])); got != "mid" {
+		t.Errorf("backup = %q", got)
+	}
+	if got := readFileString(t, path); got != "new" {
+		t.Errorf("current file = %q", got)
+	}
+}
+
+func TestRotatingWriterCompress(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	w, _ := newTestRotatingWriter(t, path, 6, 0, 0, true)
+
+	w.Write([]byte("first\n"))
+	w.Write([]byte("second\n"))
+
+	want := []string{"app-2024-01-02T15-04-06.000.log.gz", "app.log"}
+	if got := dirNames(t, dir); !reflect.DeepEqual(got, want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+
+	f, err := os.Open(filepath.Join(dir, want[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "first\n" {
+		t.Errorf("decompressed backup = %q", data)
+	}
+	if got := readFileString(t, path); got != "second\n" {
+		t.Errorf("current file = %q", got)
+	}
+}
+
+func TestRotatingWriterAppendsToExistingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	if err := File(path).WriteString("12345"); err != nil {
+		t.Fatal(err)
+	}
+
+	w, _ := newTestRotatingWriter(t, path, 8, 0, 0, false)
+	w.Write([]byte("678"))
+	w.Write([]byte("9"))
+
+	if got := readFileString(t, path); got != "9" {
+		t.Errorf("current file = %q", got)
+	}
+	if got := readFileString(t, filepath.Join(dir, "app-2024-01-02T15-04-06.000.log")); got != "12345678" {
+		t.Errorf("backup = %q", got)
+	}
+}
+
+func TestRotatingWriterSameTimestamp(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	w, _ := newTestRotatingWriter(t, path, 1, 0, 0, false)
+	fixed := time.Date(2024, 1, 2, 15, 4, 5, 0, time.Local)
+	w.now = func() time.Time { return fixed }
+
+	for _, s := range []string{"a", "b", "c"} {
+		w.Write([]byte(s))
+	}
+
+	want := []string{
+		"app-2024-01-02T15-04-05.000-1.log",
+		"app-2024-01-02T15-04-05.000.log",
+		"app.log",
+	}
+	if got := dirNames(t, dir); !reflect.DeepEqual(got, want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	if got := readFileString(t, filepath.Join(dir, want[0])); got != "b" {
+		t.Errorf("second backup = %q", got)
+	}
+}
+
+func TestRotatingWriterConcurrentWrites(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	w, _ := newTestRotatingWriter(t, path, 64, 0, 0, false)
+
+	const workers, lines = 8, 50
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < lines; j++ {
+				w.Write([]byte("0123456789abcdef\n"))
+			}
+		}()
+	}
+	wg.Wait()
+	w.Close()
+
+	total := 0
+	for _, name := range dirNames(t, dir) {
+		content := readFileString(t, filepath.Join(dir, name))
+		if len(content) > 64 {
+			t.Errorf("%s has %d bytes, limit is 64", name, len(content))
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(content, "\n"), "\n") {
+			if line != "0123456789abcdef" {
+				t.Fatalf("%s contains torn line %q", name, line)
+			}
+			total++
+		}
+	}
+	if total != workers*lines {
+		t.Errorf("total lines = %d, want %d", total, workers*lines)
+	}
+	if _, err := w.Write([]byte("x")); err != os.ErrClosed {
+		t.Errorf("Write after Close = %v, want os.ErrClosed", err)
+	}
+}
+
+func TestRotatingWriterSurvivesRenameFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	w, _ := newTestRotatingWriter(t, path, 10, 0, 0, false)
+
+	failing := true
+	w.rename = func(oldpath, newpath string) error {
+		if failing {
+			return errors.New("injected rename failure")
+		}
+		return os.Rename(oldpath, newpath)
+	}
+
+	if _, err := w.Write([]byte("aaaa\nbbbb\n")); err != nil {
+		t.Fatal(err)
+	}
+	// 需要轮转但重命名失败：返回错误，数据仍写入当前文件
+	n, err := w.Write([]byte("cccc\n"))
+	if err == nil || !strings.Contains(err.Error(), "injected rename failure") || n != 5 {
+		t.Fatalf("Write = %d, %v; want 5 and rotation error", n, err)
+	}
+	if got := readFileString(t, path); got != "aaaa\nbbbb\ncccc\n" {
+		t.Fatalf("current file = %q", got)
+	}
+
+	// 故障恢复后写入继续成功，并完成之前未能进行的轮转
+	failing = false
+	if _, err := w.Write([]byte("dddd\n")); err != nil {
+		t.Fatalf("write after recovery: %v", err)
+	}
+	want := []string{"app-2024-01-02T15-04-07.000.log", "app.log"}
+	if got := dirNames(t, dir); !reflect.DeepEqual(got, want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	if got := readFileString(t, filepath.Join(dir, want[0])); got != "aaaa\nbbbb\ncccc\n" {
+		t.Errorf("backup = %q", got)
+	}
+	if got := readFileString(t, path); got != "dddd\n" {
+		t.Errorf("current file = %q", got)
+	}
+}
+
+func TestRotatingWriterRestoresFileWhenReopenFails(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	w, _ := newTestRotatingWriter(t, path, 10, 0, 0, false)
+
+	// 重命名后在原路径放置目录，使新文件无法打开；移回备份前再删除该目录
+	w.rename = func(oldpath, newpath string) error {
+		if newpath == path {
+			os.Remove(path)
+		}
+		if err := os.Rename(oldpath, newpath); err != nil {
+			return err
+		}
+		if oldpath == path {
+			return os.Mkdir(path, 0755)
+		}
+		return nil
+	}
+
+	if _, err := w.Write([]byte("aaaa\nbbbb\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("cccc\n")); err == nil {
+		t.Fatal("expected rotation error")
+	}
+	if _, err := w.Write([]byte("dddd\n")); err == nil {
+		t.Fatal("expected rotation error on retry")
+	}
+
+	// 备份已移回原位置，所有数据都在当前文件中
+	if got := dirNames(t, dir); !reflect.DeepEqual(got, []string{"app.log"}) {
+		t.Fatalf("files = %v", got)
+	}
+	if got := readFileString(t, path); got != "aaaa\nbbbb\ncccc\ndddd\n" {
+		t.Errorf("current file = %q", got)
+	}
+}
@@ -0,0 +1,194 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// 目录快照：记录目录下每个文件的相对路径、大小、权限与内容哈希，用于比较两次发布之间的变化
+
+// DirSnapshotEntry 快照中的单个文件
+type DirSnapshotEntry struct {
+	Path string      `json:"path"` // 相对快照根目录的路径，统一使用 / 分隔
+	Size int64       `json:"size"`
+	Mode os.FileMode `json:"mode"`
+	Hash string      `json:"hash"` // 内容的 SHA-256（十六进制）
+}
+
+// DirSnapshot 目录快照，Entries 按 Path 排序，可通过 XFile.WriteJSON / ReadJSON 保存与加载
+type DirSnapshot struct {
+	Root    string             `json:"root"`
+	Entries []DirSnapshotEntry `json:"entries"`
+}
+
+// DirChange 在两次快照中都存在但发生变化的文件
+type DirChange struct {
+	Path string           `json:"path"`
+	Old  DirSnapshotEntry `json:"old"`
+	New  DirSnapshotEntry `json:"new"`
+}
+
+// DirDiff 两次快照的差异，各列表按路径排序
+// 内容与权限同时变化的文件会同时出现在 Modified 与 ModeChanged 中
+type DirDiff struct {
+	Added       []DirSnapshotEntry `json:"added"`
+	Removed     []DirSnapshotEntry `json:"removed"`
+	Modified    []DirChange        `json:"modified"`     // 内容哈希变化
+	ModeChanged []DirChange        `json:"mode_changed"` // 权限变化
+}
+
+// IsEmpty 判断两次快照是否完全一致
+func (d DirDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0 && len(d.ModeChanged) == 0
+}
+
+// Snapshot 对目录生成快照，递归记录所有普通文件（跳过符号链接等特殊文件）
+// 文件内容由有限数量的 goroutine 并发计算哈希，适用于大型目录
+func (f XFile) Snapshot() (DirSnapshot, error) {
+	if !f.IsDir() {
+		return DirSnapshot{}, fmt.Errorf("snapshot %s: not a directory", f.path)
+	}
+
+	var entries []DirSnapshotEntry
+	var files []string
+	err := filepath.WalkDir(f.path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(f.path, path)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, DirSnapshotEntry{
+			Path: filepath.ToSlash(rel),
+			Size: info.Size(),
+			Mode: info.Mode(),
+		})
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return DirSnapshot{}, fmt.Errorf("snapshot %s: %w", f.path, err)
+	}
+
+	if err := hashFilesConcurrently(files, entries); err != nil {
+		return DirSnapshot{}, fmt.Errorf("snapshot %s: %w", f.path, err)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	return DirSnapshot{Root: f.path, Entries: entries}, nil
+}
+
+// hashFilesConcurrently 使用 runtime.NumCPU 个 worker 计算文件哈希，写入 entries 对应位置
+func hashFilesConcurrently(files []string, entries []DirSnapshotEntry) error {
+	workers := runtime.NumCPU()
+	if workers > len(files) {
+		workers = len(files)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				hash, err := hashFile(files[i])
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+					continue
+				}
+				entries[i].Hash = hash
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return firstErr
+}
+
+// hashFile 计算文件内容的 SHA-256
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// DiffSnapshots 比较两次快照，返回新增、删除、内容变化与权限变化的文件
+func DiffSnapshots(old, new DirSnapshot) DirDiff {
+	oldEntries := make(map[string]DirSnapshotEntry, len(old.Entries))
+	for _, entry := range old.Entries {
+		oldEntries[entry.Path] = entry
+	}
+	newEntries := make(map[string]DirSnapshotEntry, len(new.Entries))
+	for _, entry := range new.Entries {
+		newEntries[entry.Path] = entry
+	}
+
+	var diff DirDiff
+	for _, entry := range new.Entries {
+		before, ok := oldEntries[entry.Path]
+		if !ok {
+			diff.Added = append(diff.Added, entry)
+			continue
+		}
+		change := DirChange{Path: entry.Path, Old: before, New: entry}
+		if before.Hash != entry.Hash {
+			diff.Modified = append(diff.Modified, change)
+		}
+		if before.Mode != entry.Mode {
+			diff.ModeChanged = append(diff.ModeChanged, change)
+		}
+	}
+	for _, entry := range old.Entries {
+		if _, ok := newEntries[entry.Path]; !ok {
+			diff.Removed = append(diff.Removed, entry)
+		}
+	}
+
+	sortEntries := func(entries []DirSnapshotEntry) {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	}
+	sortChanges := func(changes []DirChange) {
+		sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	}
+	sortEntries(diff.Added)
+	sortEntries(diff.Removed)
+	sortChanges(diff.Modified)
+	sortChanges(diff.ModeChanged)
+	return diff
+}
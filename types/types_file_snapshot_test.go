@@ -0,0 +1,186 @@
+package types
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// snapshotFixture 创建快照测试用的目录树
+func snapshotFixture(t *testing.T) XFile {
+	t.Helper()
+	root := File(t.TempDir())
+	files := map[string]string{
+		"app.bin":           "binary-v1",
+		"config/app.yaml":   "port: 8080\n",
+		"config/db.yaml":    "dsn: local\n",
+		"static/index.html": "<html></html>",
+		"scripts/run.sh":    "#!/bin/sh\n",
+	}
+	for name, content := range files {
+		file := root.Join(filepath.FromSlash(name))
+		if err := file.DirFile().MkdirAll(); err != nil {
+			t.Fatal(err)
+		}
+		if err := file.WriteWithPerm([]byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func diffPaths(diff DirDiff) map[string][]string {
+	paths := map[string][]string{}
+	for _, e := range diff.Added {
+		paths["added"] = append(paths["added"], e.Path)
+	}
+	for _, e := range diff.Removed {
+		paths["removed"] = append(paths["removed"], e.Path)
+	}
+	for _, c := range diff.Modified {
+		paths["modified"] = append(paths["modified"], c.Path)
+	}
+	for _, c := range diff.ModeChanged {
+		paths["mode_changed"] = append(paths["mode_changed"], c.Path)
+	}
+	return paths
+}
+
+func TestSnapshot(t *testing.T) {
+	root := snapshotFixture(t)
+	snap, err := root.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	for _, e := range snap.Entries {
+		paths = append(paths, e.Path)
+	}
+	want := []string{"app.bin", "config/app.yaml", "config/db.yaml", "scripts/run.sh", "static/index.html"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+	first := snap.Entries[0]
+	if first.Size != 9 || len(first.Hash) != 64 {
+		t.Errorf("entry = %+v", first)
+	}
+
+	// 通过 XFile 保存与加载
+	saved := File(filepath.Join(t.TempDir(), "snapshot.json"))
+	if err := saved.WriteJSON(snap); err != nil {
+		t.Fatal(err)
+	}
+	var loaded DirSnapshot
+	if err := saved.ReadJSON(&loaded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, snap) {
+		t.Errorf("loaded snapshot differs:\n%+v\n%+v", loaded, snap)
+	}
+	if diff := DiffSnapshots(snap, loaded); !diff.IsEmpty() {
+		t.Errorf("diff of identical snapshots = %+v", diff)
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	root := snapshotFixture(t)
+	before, err := root.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 新增
+	if err := root.Join("static", "app.js").Write([]byte("console.log(1)")); err != nil {
+		t.Fatal(err)
+	}
+	// 删除
+	if err := root.Join("config", "db.yaml").Delete(); err != nil {
+		t.Fatal(err)
+	}
+	// 内容变化（大小不变）
+	if err := root.Join("app.bin").Write([]byte("binary-v2")); err != nil {
+		t.Fatal(err)
+	}
+	// 内容与权限都变化
+	if err := root.Join("config", "app.yaml").WriteString("port: 9090\n"); err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" {
+		if err := root.Join("config", "app.yaml").Chmod(0600); err != nil {
+			t.Fatal(err)
+		}
+		// 仅权限变化
+		if err := root.Join("scripts", "run.sh").Chmod(0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	after, err := root.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff := DiffSnapshots(before, after)
+
+	want := map[string][]string{
+		"added":    {"static/app.js"},
+		"removed":  {"config/db.yaml"},
+		"modified": {"app.bin", "config/app.yaml"},
+	}
+	if runtime.GOOS != "windows" {
+		want["mode_changed"] = []string{"config/app.yaml", "scripts/run.sh"}
+	}
+	if got := diffPaths(diff); !reflect.DeepEqual(got, want) {
+		t.Errorf("diff = %v, want %v", got, want)
+	}
+	if runtime.GOOS != "windows" {
+		change := diff.ModeChanged[1]
+		if change.Old.Mode != 0644 || change.New.Mode != 0755 {
+			t.Errorf("mode change = %v -> %v", change.Old.Mode, change.New.Mode)
+		}
+	}
+
+	// 反向比较时新增与删除互换
+	reverse := DiffSnapshots(after, before)
+	if len(reverse.Added) != 1 || reverse.Added[0].Path != "config/db.yaml" {
+		t.Errorf("reverse added = %+v", reverse.Added)
+	}
+}
+
+func TestSnapshotManyFiles(t *testing.T) {
+	root := File(t.TempDir())
+	const count = 200
+	for i := 0; i < count; i++ {
+		file := root.Join(fmt.Sprintf("d%d", i%10), fmt.Sprintf("f%03d.txt", i))
+		if err := file.DirFile().MkdirAll(); err != nil {
+			t.Fatal(err)
+		}
+		if err := file.WriteString(fmt.Sprintf("content-%d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snap, err := root.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snap.Entries) != count {
+		t.Fatalf("entries = %d, want %d", len(snap.Entries), count)
+	}
+	for _, e := range snap.Entries {
+		if e.Hash == "" {
+			t.Fatalf("%s has no hash", e.Path)
+		}
+	}
+}
+
+func TestSnapshotNotDirectory(t *testing.T) {
+	file := File(filepath.Join(t.TempDir(), "file.txt"))
+	os.WriteFile(file.Path(), []byte("x"), 0644)
+	if _, err := file.Snapshot(); err == nil {
+		t.Error("snapshot of a regular file should fail")
+	}
+}
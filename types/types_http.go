@@ -14,14 +14,17 @@ import (
 )
 
 type XHttp struct {
-	client  *http.Client
-	baseURL string
-	headers map[string]string
+	client   *http.Client
+	baseURL  string
+	headers  map[string]string
+	dryRun   bool
+	recorder *httpRecorder
 }
 
 type XHttpResponse struct {
 	*http.Response
 	bodyBytes []byte
+	dryRun    bool
 }
 
 // Http 创建 XHttp 实例
@@ -127,6 +130,10 @@ func (h XHttp) newRequest(method, url string, body io.Reader) (*http.Request, er
 
 // doRequest 执行请求
 func (h XHttp) doRequest(req *http.Request) (*XHttpResponse, error) {
+	if h.dryRun {
+		return newDryRunResponse(req), nil
+	}
+
 	resp, err := h.client.Do(req)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,115 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// 测试断言：供集成测试校验响应，失败信息包含请求方法、URL、状态码和响应体片段
+
+// assertBodySnippetLimit 失败信息中响应体片段的最大长度
+const assertBodySnippetLimit = 512
+
+// TestingTB 断言所需的最小测试接口，*testing.T、*testing.B 及其他测试框架均可满足
+type TestingTB interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// AssertStatus 断言状态码
+func (r *XHttpResponse) AssertStatus(t TestingTB, code int) bool {
+	t.Helper()
+	if r.StatusCode != code {
+		r.assertFailed(t, "expected status %d, got %d", code, r.StatusCode)
+		return false
+	}
+	return true
+}
+
+// AssertHeader 断言响应头的值
+func (r *XHttpResponse) AssertHeader(t TestingTB, key, want string) bool {
+	t.Helper()
+	if got := r.GetHeader(key); got != want {
+		r.assertFailed(t, "expected header %s to be %q, got %q", key, want, got)
+		return false
+	}
+	return true
+}
+
+// AssertJSONPath 断言响应 JSON 中指定路径的值（点路径，数字片段为数组索引）
+// want 先经过 JSON 序列化再比较，因此 200 与 200.0、结构体与等价的 map 视为相等
+func (r *XHttpResponse) AssertJSONPath(t TestingTB, path string, want interface{}) bool {
+	t.Helper()
+
+	var body interface{}
+	if err := json.Unmarshal(r.bodyBytes, &body); err != nil {
+		r.assertFailed(t, "expected JSON body for path %q: %v", path, err)
+		return false
+	}
+
+	got, exists := body, true
+	if path != "" {
+		got, exists = mapPathGet(body, strings.Split(path, "."))
+	}
+	if !exists {
+		r.assertFailed(t, "expected JSON path %q to exist", path)
+		return false
+	}
+
+	normalized, err := normalizeJSONValue(want)
+	if err != nil {
+		r.assertFailed(t, "cannot compare JSON path %q: %v", path, err)
+		return false
+	}
+	if !reflect.DeepEqual(got, normalized) {
+		gotJSON, _ := json.Marshal(got)
+		wantJSON, _ := json.Marshal(normalized)
+		r.assertFailed(t, "expected JSON path %q to be %s, got %s", path, wantJSON, gotJSON)
+		return false
+	}
+	return true
+}
+
+// AssertBodyContains 断言响应体包含指定子串
+func (r *XHttpResponse) AssertBodyContains(t TestingTB, substr string) bool {
+	t.Helper()
+	if !strings.Contains(r.String(), substr) {
+		r.assertFailed(t, "expected body to contain %q", substr)
+		return false
+	}
+	return true
+}
+
+// assertFailed 输出带请求上下文的失败信息
+func (r *XHttpResponse) assertFailed(t TestingTB, format string, args ...interface{}) {
+	t.Helper()
+
+	method, url := "?", "?"
+	if r.Request != nil {
+		method = r.Request.Method
+		if r.Request.URL != nil {
+			url = r.Request.URL.String()
+		}
+	}
+
+	body := r.String()
+	if len(body) > assertBodySnippetLimit {
+		body = body[:assertBodySnippetLimit] + "...(truncated)"
+	}
+
+	t.Errorf("%s\n  request: %s %s\n  status:  %s\n  body:    %s",
+		fmt.Sprintf(format, args...), method, url, r.Status, body)
+}
+
+// normalizeJSONValue 通过 JSON 往返将值转换为与解码结果一致的类型
+func normalizeJSONValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var result interface{}
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
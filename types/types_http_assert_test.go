@@ -0,0 +1,81 @@
+package types
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// mockTB 记录失败信息的测试桩
+type mockTB struct {
+	errors []string
+}
+
+func (m *mockTB) Helper() {}
+
+func (m *mockTB) Errorf(format string, args ...interface{}) {
+	m.errors = append(m.errors, fmt.Sprintf(format, args...))
+}
+
+func newAssertTestResponse(t *testing.T) *XHttpResponse {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Trace", "abc")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":7,"user":{"name":"tom","tags":["a","b"]}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	resp, err := Http().Post(server.URL+"/users", map[string]interface{}{"name": "tom"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestXHttpResponseAssertionsPass(t *testing.T) {
+	resp := newAssertTestResponse(t)
+
+	resp.AssertStatus(t, http.StatusCreated)
+	resp.AssertHeader(t, "X-Trace", "abc")
+	resp.AssertJSONPath(t, "id", 7)
+	resp.AssertJSONPath(t, "user.tags", []string{"a", "b"})
+	resp.AssertJSONPath(t, "user", map[string]interface{}{"name": "tom", "tags": []string{"a", "b"}})
+	resp.AssertBodyContains(t, `"name":"tom"`)
+}
+
+func TestXHttpResponseAssertionMessages(t *testing.T) {
+	resp := newAssertTestResponse(t)
+	tb := &mockTB{}
+
+	if resp.AssertStatus(tb, http.StatusOK) {
+		t.Error("AssertStatus should fail")
+	}
+	resp.AssertHeader(tb, "X-Trace", "xyz")
+	resp.AssertJSONPath(tb, "user.name", "jerry")
+	resp.AssertJSONPath(tb, "user.missing", nil)
+	resp.AssertBodyContains(tb, "nope")
+
+	expected := []string{
+		"expected status 200, got 201",
+		`expected header X-Trace to be "xyz", got "abc"`,
+		`expected JSON path "user.name" to be "jerry", got "tom"`,
+		`expected JSON path "user.missing" to exist`,
+		`expected body to contain "nope"`,
+	}
+	if len(tb.errors) != len(expected) {
+		t.Fatalf("got %d failures, want %d: %v", len(tb.errors), len(expected), tb.errors)
+	}
+	for i, msg := range tb.errors {
+		if !strings.HasPrefix(msg, expected[i]) {
+			t.Errorf("failure %d = %q, want prefix %q", i, msg, expected[i])
+		}
+		for _, part := range []string{"request: POST http://", "/users", "status:  201 Created", `body:    {"id":7`} {
+			if !strings.Contains(msg, part) {
+				t.Errorf("failure %d missing %q:\n%s", i, part, msg)
+			}
+		}
+	}
+}
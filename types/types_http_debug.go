@@ -0,0 +1,146 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"sort"
+	"strings"
+)
+
+// 调试辅助：请求克隆、DryRun 与请求导出
+
+// redactedValue 脱敏后的占位值
+const redactedValue = "<redacted>"
+
+// Clone 复制 XHttp 实例，请求头与客户端均为独立副本
+func (h XHttp) Clone() XHttp {
+	headers := make(map[string]string, len(h.headers))
+	for k, v := range h.headers {
+		headers[k] = v
+	}
+	h.headers = headers
+
+	if h.client != nil {
+		client := *h.client
+		h.client = &client
+	}
+	return h
+}
+
+// DryRun 开启演练模式：构建完整请求但不发送，返回仅包含 Request 的响应桩
+func (h XHttp) DryRun() XHttp {
+	h.dryRun = true
+	return h
+}
+
+// newDryRunResponse 构建演练模式下的响应桩
+func newDryRunResponse(req *http.Request) *XHttpResponse {
+	return &XHttpResponse{
+		Response: &http.Response{
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     make(http.Header),
+			Body:       http.NoBody,
+			Request:    req,
+		},
+		dryRun: true,
+	}
+}
+
+// IsDryRun 判断响应是否来自演练模式
+func (r *XHttpResponse) IsDryRun() bool {
+	return r.dryRun
+}
+
+// DumpRequest 导出发送的请求报文（基于 httputil.DumpRequestOut）
+func (r *XHttpResponse) DumpRequest(includeBody bool) (string, error) {
+	req, err := r.replayableRequest()
+	if err != nil {
+		return "", err
+	}
+
+	dump, err := httputil.DumpRequestOut(req, includeBody)
+	if err != nil {
+		return "", err
+	}
+	return string(dump), nil
+}
+
+// CurlCommand 生成等价的 curl 命令，默认对 Authorization 脱敏，传入 true 则保留原值
+func (r *XHttpResponse) CurlCommand(revealAuth ...bool) (string, error) {
+	req, err := r.replayableRequest()
+	if err != nil {
+		return "", err
+	}
+
+	reveal := len(revealAuth) > 0 && revealAuth[0]
+
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	parts := []string{"curl", "-X", shellQuote(req.Method), shellQuote(req.URL.String())}
+
+	keys := make([]string, 0, len(req.Header))
+	for k := range req.Header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		for _, v := range req.Header[k] {
+			if !reveal && strings.EqualFold(k, "Authorization") {
+				v = redactedValue
+			}
+			parts = append(parts, "-H", shellQuote(k+": "+v))
+		}
+	}
+
+	if len(body) > 0 {
+		parts = append(parts, "--data-raw", shellQuote(string(body)))
+	}
+
+	return strings.Join(parts, " "), nil
+}
+
+// replayableRequest 返回可重复读取请求体的请求副本
+func (r *XHttpResponse) replayableRequest() (*http.Request, error) {
+	if r == nil || r.Response == nil || r.Request == nil {
+		return nil, fmt.Errorf("response has no associated request")
+	}
+
+	req := r.Request.Clone(r.Request.Context())
+	if r.Request.GetBody != nil {
+		body, err := r.Request.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = body
+		return req, nil
+	}
+
+	// 无 GetBody 时读取原始请求体并回填，保证后续仍可读取
+	if r.Request.Body != nil && r.Request.Body != http.NoBody {
+		data, err := io.ReadAll(r.Request.Body)
+		if err != nil {
+			return nil, err
+		}
+		r.Request.Body = io.NopCloser(bytes.NewReader(data))
+		req.Body = io.NopCloser(bytes.NewReader(data))
+	}
+	return req, nil
+}
+
+// shellQuote 使用单引号对参数进行 shell 转义，规则与 XStr.EscapeShellArg 相同
+func shellQuote(s string) string {
+	return XStr(s).EscapeShellArg().String()
+}
@@ -0,0 +1,77 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestXHttpDryRunCurlCommand(t *testing.T) {
+	resp, err := Http().
+		BaseURL("https://api.example.com").
+		Bearer("secret-token").
+		Header("X-Request-Id", "abc'123").
+		DryRun().
+		Post("/users", map[string]interface{}{"name": "张三", "age": 30})
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+
+	if !resp.IsDryRun() {
+		t.Fatal("expected dry run response")
+	}
+	if resp.Request == nil || resp.Request.URL.String() != "https://api.example.com/users" {
+		t.Fatalf("unexpected request: %+v", resp.Request)
+	}
+
+	curl, err := resp.CurlCommand()
+	if err != nil {
+		t.Fatalf("CurlCommand failed: %v", err)
+	}
+
+	expected := `curl -X 'POST' 'https://api.example.com/users' ` +
+		`-H 'Authorization: <redacted>' ` +
+		`-H 'Content-Type: application/json' ` +
+		`-H 'X-Request-Id: abc'\''123' ` +
+		`--data-raw '{"age":30,"name":"张三"}'`
+	if curl != expected {
+		t.Errorf("unexpected curl command:\n got: %s\nwant: %s", curl, expected)
+	}
+
+	revealed, err := resp.CurlCommand(true)
+	if err != nil {
+		t.Fatalf("CurlCommand failed: %v", err)
+	}
+	if !strings.Contains(revealed, "'Authorization: Bearer secret-token'") {
+		t.Errorf("expected revealed Authorization header, got %s", revealed)
+	}
+}
+
+func TestXHttpDryRunDumpRequest(t *testing.T) {
+	resp, err := Http().DryRun().Post("http://localhost/echo", "hello")
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+
+	// 连续导出两次，请求体不应被消耗
+	for i := 0; i < 2; i++ {
+		dump, err := resp.DumpRequest(true)
+		if err != nil {
+			t.Fatalf("DumpRequest failed: %v", err)
+		}
+		if !strings.HasPrefix(dump, "POST /echo HTTP/1.1") || !strings.HasSuffix(dump, "hello") {
+			t.Errorf("unexpected dump: %q", dump)
+		}
+	}
+}
+
+func TestXHttpClone(t *testing.T) {
+	base := Http().Header("X-A", "1")
+	clone := base.Clone().Header("X-B", "2")
+
+	if _, ok := base.headers["X-B"]; ok {
+		t.Error("clone should not share headers with the original")
+	}
+	if clone.client == base.client {
+		t.Error("clone should not share the http client")
+	}
+}
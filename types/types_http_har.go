@@ -0,0 +1,606 @@
+package types
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// 流量录制、回放与 HAR 导出
+//
+// Record 开启录制模式，经过客户端的每次请求与响应（含请求体、响应体与各阶段耗时）都会被记录；
+// ExportHAR 将记录写为 HAR 1.2 文件，便于与供应商共享复现用例；LoadHAR 读取 HAR 文件，
+// 配合 Replay 在不访问网络的情况下回放：
+//
+//	client := types.Http().Record()
+//	client.Get("https://api.example.com/orders")
+//	client.ExportHAR("orders.har")
+//
+//	exchanges, _ := types.LoadHAR("orders.har")
+//	replay := types.Http().Replay(exchanges)
+
+// HttpTimings 请求各阶段的耗时，与 HAR 的 timings 对应；未发生的阶段（如复用连接时的 DNS、连接与 TLS）为负数
+type HttpTimings struct {
+	DNS     time.Duration
+	Connect time.Duration // 建立 TCP 连接的耗时，包含 TLS 握手
+	TLS     time.Duration
+	Send    time.Duration
+	Wait    time.Duration // 发送完请求到收到第一个响应字节
+	Receive time.Duration
+}
+
+// Total 返回请求总耗时（TLS 已计入 Connect，不重复累加）
+func (t HttpTimings) Total() time.Duration {
+	var total time.Duration
+	for _, d := range []time.Duration{t.DNS, t.Connect, t.Send, t.Wait, t.Receive} {
+		if d > 0 {
+			total += d
+		}
+	}
+	return total
+}
+
+// RecordedExchange 一次录制的请求与响应
+type RecordedExchange struct {
+	StartedAt      time.Time
+	Method         string
+	URL            string
+	Proto          string
+	RequestHeader  http.Header
+	RequestBody    []byte
+	Status         int
+	ResponseHeader http.Header
+	ResponseBody   []byte
+	Timings        HttpTimings
+}
+
+// httpRecorder 录制请求的传输层
+type httpRecorder struct {
+	transport http.RoundTripper
+	mu        sync.Mutex
+	exchanges []RecordedExchange
+}
+
+// Record 开启录制模式，返回的客户端（及其副本）共享同一份记录
+// 需要重试时应先调用 Record 再调用 WithRetry，每次尝试都会被记录
+func (h XHttp) Record() XHttp {
+	client := http.Client{}
+	if h.client != nil {
+		client = *h.client
+	}
+	recorder := &httpRecorder{transport: client.Transport}
+	client.Transport = recorder
+	h.client = &client
+	h.recorder = recorder
+	return h
+}
+
+// Recorded 返回已录制的请求，未开启录制时返回 nil
+func (h XHttp) Recorded() []RecordedExchange {
+	if h.recorder == nil {
+		return nil
+	}
+	h.recorder.mu.Lock()
+	defer h.recorder.mu.Unlock()
+	return append([]RecordedExchange(nil), h.recorder.exchanges...)
+}
+
+// traceTimes httptrace 回调记录的时间点，回调可能在其他 goroutine 中执行
+type traceTimes struct {
+	mu                  sync.Mutex
+	dnsStart, dnsDone   time.Time
+	connStart, connDone time.Time
+	tlsStart, tlsDone   time.Time
+	gotConn, wroteReq   time.Time
+	firstByte           time.Time
+}
+
+// set 在锁内记录时间点
+func (t *traceTimes) set(field *time.Time) {
+	now := time.Now()
+	t.mu.Lock()
+	if field.IsZero() {
+		*field = now
+	}
+	t.mu.Unlock()
+}
+
+// clientTrace 创建记录各阶段时间点的 httptrace.ClientTrace
+func (t *traceTimes) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { t.set(&t.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { t.set(&t.dnsDone) },
+		ConnectStart:         func(string, string) { t.set(&t.connStart) },
+		ConnectDone:          func(string, string, error) { t.set(&t.connDone) },
+		TLSHandshakeStart:    func() { t.set(&t.tlsStart) },
+		GotConn:              func(httptrace.GotConnInfo) { t.set(&t.gotConn) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.set(&t.wroteReq) },
+		GotFirstResponseByte: func() { t.set(&t.firstByte) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.set(&t.tlsDone) },
+	}
+}
+
+// timings 根据时间点计算各阶段耗时，start 为请求开始时间，headers 为收到响应头的时间，done 为读完响应体的时间
+func (t *traceTimes) timings(start, headers, done time.Time) HttpTimings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	span := func(from, to time.Time) time.Duration {
+		if from.IsZero() || to.IsZero() {
+			return -1
+		}
+		return to.Sub(from)
+	}
+
+	timings := HttpTimings{
+		DNS:     span(t.dnsStart, t.dnsDone),
+		Connect: span(t.connStart, t.connDone),
+		TLS:     span(t.tlsStart, t.tlsDone),
+		Receive: done.Sub(headers),
+	}
+	if timings.TLS >= 0 {
+		timings.Connect = span(t.connStart, t.tlsDone)
+	}
+
+	if send := span(t.gotConn, t.wroteReq); send >= 0 {
+		timings.Send = send
+	}
+	if wait := span(t.wroteReq, t.firstByte); wait >= 0 {
+		timings.Wait = wait
+	} else {
+		// 传输层未触发 httptrace（如 MockTransport）时，将未归入其他阶段的时间计为等待
+		timings.Wait = headers.Sub(start) - nonNegative(timings.DNS) - nonNegative(timings.Connect) - timings.Send
+		if timings.Wait < 0 {
+			timings.Wait = 0
+		}
+	}
+	return timings
+}
+
+// nonNegative 将表示不适用的负数耗时视为 0
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// RoundTrip 实现 http.RoundTripper，记录请求体、响应体与各阶段耗时
+func (r *httpRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := r.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	times := &traceTimes{}
+	traced := req.WithContext(httptrace.WithClientTrace(req.Context(), times.clientTrace()))
+	start := time.Now()
+
+	resp, err := transport.RoundTrip(traced)
+	if err != nil {
+		return nil, err
+	}
+	headersAt := time.Now()
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	exchange := RecordedExchange{
+		StartedAt:      start,
+		Method:         req.Method,
+		URL:            req.URL.String(),
+		Proto:          resp.Proto,
+		RequestHeader:  req.Header.Clone(),
+		RequestBody:    reqBody,
+		Status:         resp.StatusCode,
+		ResponseHeader: resp.Header.Clone(),
+		ResponseBody:   respBody,
+		Timings:        times.timings(start, headersAt, time.Now()),
+	}
+
+	r.mu.Lock()
+	r.exchanges = append(r.exchanges, exchange)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// readRequestBody 读取请求体并保证请求仍可发送
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// Replay 使用录制的请求回放响应，不访问网络
+// 按方法与完整 URL 匹配，同一请求多次录制时按顺序依次返回，最后一个响应重复使用
+func (h XHttp) Replay(exchanges []RecordedExchange) XHttp {
+	return h.WithTransport(ReplayTransport(exchanges))
+}
+
+// ReplayTransport 由录制的请求构建 MockTransport，可通过 AssertExpectations 检查所有录制的请求都被回放
+func ReplayTransport(exchanges []RecordedExchange) *MockTransport {
+	m := NewMockTransport()
+	routes := make(map[string]*MockRoute)
+	for _, ex := range exchanges {
+		key := ex.Method + " " + ex.URL
+		route, ok := routes[key]
+		if !ok {
+			route = m.On(ex.Method, "^"+regexp.QuoteMeta(ex.URL)+"$")
+			routes[key] = route
+		}
+		headers := ex.ResponseHeader.Clone()
+		if headers == nil {
+			headers = make(http.Header)
+		}
+		m.mu.Lock()
+		route.replies = append(route.replies, mockReply{status: ex.Status, body: ex.ResponseBody, headers: headers})
+		m.mu.Unlock()
+	}
+	return m
+}
+
+// HAR 1.2 文档结构（http://www.softwareishard.com/blog/har-12-spec/）
+
+type harDocument struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// harPostData 请求体，HAR 1.2 未定义 postData 的编码字段，二进制请求体使用自定义字段 _encoding
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Encoding string `json:"_encoding,omitempty"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+// harTimeLayout HAR 要求的 ISO 8601 时间格式（毫秒精度）
+const harTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// sensitiveHeaders 导出 HAR 时默认脱敏的请求头与响应头
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// ExportHAR 将录制的请求写为 HAR 1.2 文件，默认对 Authorization 与 Cookie 脱敏，传入 true 则保留原值
+// 非 UTF-8 的请求体与响应体以 base64 编码；未开启录制时返回错误
+func (h XHttp) ExportHAR(path string, revealSecrets ...bool) error {
+	if h.recorder == nil {
+		return fmt.Errorf("export har: client is not in recording mode")
+	}
+	reveal := len(revealSecrets) > 0 && revealSecrets[0]
+
+	exchanges := h.Recorded()
+	doc := harDocument{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "go-util", Version: "1.0"},
+		Entries: make([]harEntry, 0, len(exchanges)),
+	}}
+	for _, ex := range exchanges {
+		doc.Log.Entries = append(doc.Log.Entries, newHAREntry(ex, reveal))
+	}
+
+	if err := File(path).WriteJSON(doc); err != nil {
+		return fmt.Errorf("export har %s: %w", path, err)
+	}
+	return nil
+}
+
+// newHAREntry 将录制的请求转换为 HAR 条目
+func newHAREntry(ex RecordedExchange, reveal bool) harEntry {
+	proto := ex.Proto
+	if proto == "" {
+		proto = "HTTP/1.1"
+	}
+
+	request := harRequest{
+		Method:      ex.Method,
+		URL:         ex.URL,
+		HTTPVersion: proto,
+		Cookies:     harCookies(parseRequestCookies(ex.RequestHeader), reveal),
+		Headers:     harHeaders(ex.RequestHeader, reveal),
+		QueryString: harQueryString(ex.URL),
+		HeadersSize: -1,
+		BodySize:    len(ex.RequestBody),
+	}
+	if len(ex.RequestBody) > 0 {
+		text, encoding := harEncodeBody(ex.RequestBody)
+		request.PostData = &harPostData{MimeType: ex.RequestHeader.Get("Content-Type"), Text: text, Encoding: encoding}
+	}
+
+	response := harResponse{
+		Status:      ex.Status,
+		StatusText:  http.StatusText(ex.Status),
+		HTTPVersion: proto,
+		Cookies:     harCookies((&http.Response{Header: ex.ResponseHeader}).Cookies(), reveal),
+		Headers:     harHeaders(ex.ResponseHeader, reveal),
+		Content:     harContent{Size: len(ex.ResponseBody), MimeType: ex.ResponseHeader.Get("Content-Type")},
+		RedirectURL: ex.ResponseHeader.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    len(ex.ResponseBody),
+	}
+	if len(ex.ResponseBody) > 0 {
+		response.Content.Text, response.Content.Encoding = harEncodeBody(ex.ResponseBody)
+	}
+
+	return harEntry{
+		StartedDateTime: ex.StartedAt.Format(harTimeLayout),
+		Time:            harMillis(ex.Timings.Total()),
+		Request:         request,
+		Response:        response,
+		Timings: harTimings{
+			Blocked: -1,
+			DNS:     harMillis(ex.Timings.DNS),
+			Connect: harMillis(ex.Timings.Connect),
+			Send:    harMillis(ex.Timings.Send),
+			Wait:    harMillis(ex.Timings.Wait),
+			Receive: harMillis(ex.Timings.Receive),
+			SSL:     harMillis(ex.Timings.TLS),
+		},
+	}
+}
+
+// parseRequestCookies 解析请求头中的 Cookie
+func parseRequestCookies(header http.Header) []*http.Cookie {
+	return (&http.Request{Header: header}).Cookies()
+}
+
+// harHeaders 按名称排序转换头部，敏感头部的值脱敏
+func harHeaders(header http.Header, reveal bool) []harNameValue {
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	values := make([]harNameValue, 0, len(keys))
+	for _, k := range keys {
+		for _, v := range header[k] {
+			if !reveal && isSensitiveHeader(k) {
+				v = redactedValue
+			}
+			values = append(values, harNameValue{Name: k, Value: v})
+		}
+	}
+	return values
+}
+
+// isSensitiveHeader 判断头部是否需要脱敏
+func isSensitiveHeader(name string) bool {
+	for _, s := range sensitiveHeaders {
+		if strings.EqualFold(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// harCookies 转换 Cookie，默认保留名称、对值脱敏
+func harCookies(cookies []*http.Cookie, reveal bool) []harNameValue {
+	values := make([]harNameValue, 0, len(cookies))
+	for _, c := range cookies {
+		value := c.Value
+		if !reveal {
+			value = redactedValue
+		}
+		values = append(values, harNameValue{Name: c.Name, Value: value})
+	}
+	return values
+}
+
+// harQueryString 解析 URL 中的查询参数
+func harQueryString(rawURL string) []harNameValue {
+	values := []harNameValue{}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return values
+	}
+	query := u.Query()
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range query[k] {
+			values = append(values, harNameValue{Name: k, Value: v})
+		}
+	}
+	return values
+}
+
+// harEncodeBody UTF-8 文本原样输出，二进制内容使用 base64
+func harEncodeBody(body []byte) (text, encoding string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+// harDecodeBody harEncodeBody 的逆操作
+func harDecodeBody(text, encoding string) ([]byte, error) {
+	if encoding == "base64" {
+		return base64.StdEncoding.DecodeString(text)
+	}
+	return []byte(text), nil
+}
+
+// harMillis 将耗时转换为 HAR 使用的毫秒数，负数表示不适用，统一为 -1
+func harMillis(d time.Duration) float64 {
+	if d < 0 {
+		return -1
+	}
+	return float64(d) / float64(time.Millisecond)
+}
+
+// harDuration harMillis 的逆操作
+func harDuration(ms float64) time.Duration {
+	if ms < 0 {
+		return -1
+	}
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// LoadHAR 读取 HAR 文件，返回可用于 Replay 的请求记录
+// 导出时被脱敏的头部保持占位值
+func LoadHAR(path string) ([]RecordedExchange, error) {
+	var doc harDocument
+	if err := File(path).ReadJSON(&doc); err != nil {
+		return nil, fmt.Errorf("load har %s: %w", path, err)
+	}
+
+	exchanges := make([]RecordedExchange, 0, len(doc.Log.Entries))
+	for i, entry := range doc.Log.Entries {
+		ex, err := entry.exchange()
+		if err != nil {
+			return nil, fmt.Errorf("load har %s: entry %d: %w", path, i, err)
+		}
+		exchanges = append(exchanges, ex)
+	}
+	return exchanges, nil
+}
+
+// exchange 将 HAR 条目转换为请求记录
+func (e harEntry) exchange() (RecordedExchange, error) {
+	started, err := time.Parse(time.RFC3339Nano, e.StartedDateTime)
+	if err != nil {
+		return RecordedExchange{}, err
+	}
+
+	ex := RecordedExchange{
+		StartedAt:      started,
+		Method:         e.Request.Method,
+		URL:            e.Request.URL,
+		Proto:          e.Response.HTTPVersion,
+		RequestHeader:  harToHeader(e.Request.Headers),
+		Status:         e.Response.Status,
+		ResponseHeader: harToHeader(e.Response.Headers),
+		Timings: HttpTimings{
+			DNS:     harDuration(e.Timings.DNS),
+			Connect: harDuration(e.Timings.Connect),
+			TLS:     harDuration(e.Timings.SSL),
+			Send:    harDuration(e.Timings.Send),
+			Wait:    harDuration(e.Timings.Wait),
+			Receive: harDuration(e.Timings.Receive),
+		},
+	}
+	if e.Request.PostData != nil {
+		if ex.RequestBody, err = harDecodeBody(e.Request.PostData.Text, e.Request.PostData.Encoding); err != nil {
+			return RecordedExchange{}, fmt.Errorf("request body: %w", err)
+		}
+	}
+	if ex.ResponseBody, err = harDecodeBody(e.Response.Content.Text, e.Response.Content.Encoding); err != nil {
+		return RecordedExchange{}, fmt.Errorf("response body: %w", err)
+	}
+	if len(ex.ResponseBody) == 0 {
+		ex.ResponseBody = nil
+	}
+	return ex, nil
+}
+
+// harToHeader 将 HAR 的头部列表转换为 http.Header
+func harToHeader(values []harNameValue) http.Header {
+	header := make(http.Header, len(values))
+	for _, v := range values {
+		header.Add(v.Name, v.Value)
+	}
+	return header
+}
@@ -0,0 +1,273 @@
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// harServer 返回 JSON 与二进制响应的测试服务
+func harServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/orders":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "server-secret"})
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"orders":[{"id":1}]}`))
+		case "/thumbnail":
+			body, _ := io.ReadAll(r.Body)
+			w.Header().Set("Content-Type", "image/png")
+			w.WriteHeader(http.StatusCreated)
+			w.Write(append([]byte{0x89, 'P', 'N', 'G', 0xff}, body...))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// harSchema HAR 1.2 中必需的字段及其 JSON 类型
+var harSchema = map[string]map[string]string{
+	"log":      {"version": "string", "creator": "object", "entries": "array"},
+	"creator":  {"name": "string", "version": "string"},
+	"entry":    {"startedDateTime": "string", "time": "number", "request": "object", "response": "object", "cache": "object", "timings": "object"},
+	"request":  {"method": "string", "url": "string", "httpVersion": "string", "cookies": "array", "headers": "array", "queryString": "array", "headersSize": "number", "bodySize": "number"},
+	"response": {"status": "number", "statusText": "string", "httpVersion": "string", "cookies": "array", "headers": "array", "content": "object", "redirectURL": "string", "headersSize": "number", "bodySize": "number"},
+	"content":  {"size": "number", "mimeType": "string"},
+	"timings":  {"send": "number", "wait": "number", "receive": "number"},
+	"postData": {"mimeType": "string", "text": "string"},
+}
+
+// checkHARObject 按 harSchema 检查对象的必需字段与类型
+func checkHARObject(t *testing.T, kind string, value interface{}) map[string]interface{} {
+	t.Helper()
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		t.Fatalf("%s is %T, want object", kind, value)
+	}
+	for field, typ := range harSchema[kind] {
+		v, exists := obj[field]
+		if !exists {
+			t.Errorf("%s.%s is required", kind, field)
+			continue
+		}
+		var actual string
+		switch v.(type) {
+		case string:
+			actual = "string"
+		case float64:
+			actual = "number"
+		case map[string]interface{}:
+			actual = "object"
+		case []interface{}:
+			actual = "array"
+		}
+		if actual != typ {
+			t.Errorf("%s.%s is %s, want %s", kind, field, actual, typ)
+		}
+	}
+	return obj
+}
+
+// validateHAR 检查 HAR 文档结构，返回条目列表
+func validateHAR(t *testing.T, data []byte) []map[string]interface{} {
+	t.Helper()
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	log := checkHARObject(t, "log", doc["log"])
+	if log["version"] != "1.2" {
+		t.Errorf("version = %v", log["version"])
+	}
+	checkHARObject(t, "creator", log["creator"])
+
+	var entries []map[string]interface{}
+	for _, raw := range log["entries"].([]interface{}) {
+		entry := checkHARObject(t, "entry", raw)
+		if _, err := time.Parse(time.RFC3339, entry["startedDateTime"].(string)); err != nil {
+			t.Errorf("startedDateTime: %v", err)
+		}
+		request := checkHARObject(t, "request", entry["request"])
+		if postData, ok := request["postData"]; ok {
+			checkHARObject(t, "postData", postData)
+		}
+		response := checkHARObject(t, "response", entry["response"])
+		checkHARObject(t, "content", response["content"])
+		timings := checkHARObject(t, "timings", entry["timings"])
+		for _, phase := range []string{"send", "wait", "receive"} {
+			if timings[phase].(float64) < 0 {
+				t.Errorf("timings.%s = %v, must not be negative", phase, timings[phase])
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// headerValue 在 HAR 头部列表中查找值
+func headerValue(headers interface{}, name string) string {
+	for _, h := range headers.([]interface{}) {
+		pair := h.(map[string]interface{})
+		if strings.EqualFold(pair["name"].(string), name) {
+			return pair["value"].(string)
+		}
+	}
+	return ""
+}
+
+func TestExportHAR(t *testing.T) {
+	server := harServer(t)
+	client := Http().BaseURL(server.URL).Record().Bearer("api-token").Header("Cookie", "session=client-secret")
+
+	if _, err := client.Get("/orders?page=2"); err != nil {
+		t.Fatal(err)
+	}
+	thumb := []byte{0x00, 0x01, 0xfe}
+	if _, err := client.ContentType("application/octet-stream").Post("/thumbnail", thumb); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(client.Recorded()); n != 2 {
+		t.Fatalf("recorded %d exchanges, want 2", n)
+	}
+
+	path := filepath.Join(t.TempDir(), "traffic.har")
+	if err := client.ExportHAR(path); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := File(path).Read()
+	entries := validateHAR(t, data)
+	if len(entries) != 2 {
+		t.Fatalf("entries = %d", len(entries))
+	}
+
+	// JSON 请求：查询参数、默认脱敏
+	request := entries[0]["request"].(map[string]interface{})
+	if got := request["queryString"].([]interface{}); len(got) != 1 || got[0].(map[string]interface{})["value"] != "2" {
+		t.Errorf("queryString = %v", got)
+	}
+	if got := headerValue(request["headers"], "Authorization"); got != redactedValue {
+		t.Errorf("Authorization = %q, want redacted", got)
+	}
+	if strings.Contains(string(data), "api-token") || strings.Contains(string(data), "secret") {
+		t.Error("HAR contains secrets")
+	}
+	response := entries[0]["response"].(map[string]interface{})
+	content := response["content"].(map[string]interface{})
+	if content["text"] != `{"orders":[{"id":1}]}` || content["encoding"] != nil {
+		t.Errorf("json content = %v", content)
+	}
+	if cookies := response["cookies"].([]interface{}); len(cookies) != 1 || cookies[0].(map[string]interface{})["name"] != "session" {
+		t.Errorf("response cookies = %v", cookies)
+	}
+
+	// 二进制请求体与响应体使用 base64
+	postData := entries[1]["request"].(map[string]interface{})["postData"].(map[string]interface{})
+	if postData["_encoding"] != "base64" || postData["text"] != base64.StdEncoding.EncodeToString(thumb) {
+		t.Errorf("postData = %v", postData)
+	}
+	content = entries[1]["response"].(map[string]interface{})["content"].(map[string]interface{})
+	if content["encoding"] != "base64" || content["mimeType"] != "image/png" {
+		t.Errorf("binary content = %v", content)
+	}
+
+	// 显式要求时保留原值
+	revealed := filepath.Join(t.TempDir(), "revealed.har")
+	client.ExportHAR(revealed, true)
+	raw, _ := File(revealed).ReadString()
+	if !strings.Contains(raw, "Bearer api-token") || !strings.Contains(raw, "client-secret") {
+		t.Error("revealed HAR should keep Authorization and cookies")
+	}
+
+	if err := Http().ExportHAR(path); err == nil {
+		t.Error("ExportHAR without recording should fail")
+	}
+}
+
+func TestLoadHARReplay(t *testing.T) {
+	server := harServer(t)
+	client := Http().BaseURL(server.URL).Record()
+	client.Get("/orders")
+	client.Post("/thumbnail", []byte{0xff, 0x00})
+	client.Get("/missing")
+
+	path := filepath.Join(t.TempDir(), "traffic.har")
+	if err := client.ExportHAR(path); err != nil {
+		t.Fatal(err)
+	}
+	exchanges, err := LoadHAR(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded := client.Recorded()
+	if len(exchanges) != len(recorded) {
+		t.Fatalf("loaded %d exchanges, want %d", len(exchanges), len(recorded))
+	}
+	for i, ex := range exchanges {
+		want := recorded[i]
+		if ex.Method != want.Method || ex.URL != want.URL || ex.Status != want.Status ||
+			!bytes.Equal(ex.RequestBody, want.RequestBody) || !bytes.Equal(ex.ResponseBody, want.ResponseBody) {
+			t.Errorf("exchange %d = %+v, want %+v", i, ex, want)
+		}
+		if !ex.StartedAt.Equal(want.StartedAt.Truncate(time.Millisecond)) {
+			t.Errorf("exchange %d started at %v, want %v", i, ex.StartedAt, want.StartedAt)
+		}
+	}
+
+	// 服务关闭后通过回放得到相同的响应
+	server.Close()
+	transport := ReplayTransport(exchanges)
+	replay := Http().BaseURL(server.URL).WithTransport(transport)
+	resp, err := replay.Get("/orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.String() != `{"orders":[{"id":1}]}` || resp.GetContentType() != "application/json" {
+		t.Errorf("replayed response = %q (%s)", resp.String(), resp.GetContentType())
+	}
+	resp, _ = replay.Post("/thumbnail", []byte{0xff, 0x00})
+	if resp.StatusCode != http.StatusCreated || !bytes.Equal(resp.Bytes(), []byte{0x89, 'P', 'N', 'G', 0xff, 0xff, 0x00}) {
+		t.Errorf("replayed binary = %d %v", resp.StatusCode, resp.Bytes())
+	}
+	resp, _ = replay.Get("/missing")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("replayed status = %d", resp.StatusCode)
+	}
+	transport.AssertExpectations(t)
+
+	if _, err := Http().Replay(exchanges).Get(server.URL + "/other"); err == nil {
+		t.Error("request that was never recorded should fail")
+	}
+}
+
+func TestRecordTimings(t *testing.T) {
+	server := harServer(t)
+	client := Http().Record()
+	client.Get(server.URL + "/orders")
+	client.Get(server.URL + "/orders")
+
+	recorded := client.Recorded()
+	first, second := recorded[0].Timings, recorded[1].Timings
+	if first.Connect < 0 || first.Wait < 0 || first.Send < 0 || first.Receive < 0 {
+		t.Errorf("first request timings = %+v", first)
+	}
+	if first.DNS >= 0 || first.TLS >= 0 {
+		t.Errorf("IP address over plain HTTP should have no DNS or TLS phase: %+v", first)
+	}
+	// 复用连接时没有连接阶段
+	if second.Connect >= 0 {
+		t.Errorf("reused connection timings = %+v", second)
+	}
+	if first.Total() <= 0 {
+		t.Errorf("total = %v", first.Total())
+	}
+}
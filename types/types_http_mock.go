@@ -0,0 +1,241 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// 模拟传输层：在单元测试中替代真实网络，无需启动 httptest 服务
+
+// MockTransport 按注册顺序匹配请求并返回预设响应的 http.RoundTripper
+//
+// URL 模式匹配完整 URL（含查询参数）：以 ^ 开头时按正则表达式匹配，
+// 否则按通配符匹配，* 匹配任意字符。未匹配的请求返回包含请求详情的错误，
+// 并在 AssertExpectations 中报告。MockTransport 可被并发使用。
+type MockTransport struct {
+	mu        sync.Mutex
+	routes    []*MockRoute
+	unmatched []string
+}
+
+// MockRoute 一条模拟路由
+type MockRoute struct {
+	transport *MockTransport
+	method    string
+	pattern   string
+	re        *regexp.Regexp
+	replies   []mockReply
+	times     int
+	calls     int
+}
+
+// mockReply 预设响应
+type mockReply struct {
+	status  int
+	body    []byte
+	headers http.Header
+	err     error
+}
+
+// NewMockTransport 创建模拟传输层
+func NewMockTransport() *MockTransport {
+	return &MockTransport{}
+}
+
+// On 注册路由，method 为空或 "*" 时匹配任意方法
+// urlPattern 非法的正则表达式会直接 panic，与 regexp.MustCompile 一致
+func (m *MockTransport) On(method, urlPattern string) *MockRoute {
+	route := &MockRoute{
+		transport: m,
+		method:    strings.ToUpper(method),
+		pattern:   urlPattern,
+		re:        compileMockPattern(urlPattern),
+	}
+
+	m.mu.Lock()
+	m.routes = append(m.routes, route)
+	m.mu.Unlock()
+	return route
+}
+
+// compileMockPattern 将 URL 模式编译为正则表达式
+func compileMockPattern(pattern string) *regexp.Regexp {
+	if strings.HasPrefix(pattern, "^") {
+		return regexp.MustCompile(pattern)
+	}
+	quoted := regexp.QuoteMeta(pattern)
+	return regexp.MustCompile("^" + strings.ReplaceAll(quoted, `\*`, ".*") + "$")
+}
+
+// Reply 追加一个响应，多次调用按顺序依次返回，最后一个响应重复使用
+// body 为 string 或 []byte 时原样返回，nil 为空响应体，其他值序列化为 JSON
+func (r *MockRoute) Reply(status int, body interface{}, headers ...map[string]string) *MockRoute {
+	reply := mockReply{status: status, headers: make(http.Header)}
+	for _, h := range headers {
+		for k, v := range h {
+			reply.headers.Set(k, v)
+		}
+	}
+
+	switch b := body.(type) {
+	case nil:
+	case string:
+		reply.body = []byte(b)
+	case []byte:
+		reply.body = b
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			reply.err = fmt.Errorf("mock transport: marshal reply body: %w", err)
+		}
+		reply.body = data
+		if reply.headers.Get("Content-Type") == "" {
+			reply.headers.Set("Content-Type", "application/json")
+		}
+	}
+
+	r.transport.mu.Lock()
+	r.replies = append(r.replies, reply)
+	r.transport.mu.Unlock()
+	return r
+}
+
+// ReplyError 追加一个传输层错误（如连接失败）
+func (r *MockRoute) ReplyError(err error) *MockRoute {
+	r.transport.mu.Lock()
+	r.replies = append(r.replies, mockReply{err: err})
+	r.transport.mu.Unlock()
+	return r
+}
+
+// Times 限定路由的调用次数：达到次数后不再匹配，AssertExpectations 要求恰好调用 n 次
+// 未设置时路由可无限匹配，AssertExpectations 要求至少调用一次
+func (r *MockRoute) Times(n int) *MockRoute {
+	r.transport.mu.Lock()
+	r.times = n
+	r.transport.mu.Unlock()
+	return r
+}
+
+// Calls 返回路由已匹配的次数
+func (r *MockRoute) Calls() int {
+	r.transport.mu.Lock()
+	defer r.transport.mu.Unlock()
+	return r.calls
+}
+
+// String 返回路由描述
+func (r *MockRoute) String() string {
+	method := r.method
+	if method == "" {
+		method = "*"
+	}
+	return method + " " + r.pattern
+}
+
+// matches 判断路由是否匹配请求
+func (r *MockRoute) matches(req *http.Request) bool {
+	if r.times > 0 && r.calls >= r.times {
+		return false
+	}
+	if r.method != "" && r.method != "*" && r.method != req.Method {
+		return false
+	}
+	return r.re.MatchString(req.URL.String())
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (m *MockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+
+	m.mu.Lock()
+	var route *MockRoute
+	for _, r := range m.routes {
+		if r.matches(req) {
+			route = r
+			break
+		}
+	}
+	if route == nil {
+		detail := req.Method + " " + req.URL.String()
+		m.unmatched = append(m.unmatched, detail)
+		registered := make([]string, len(m.routes))
+		for i, r := range m.routes {
+			registered[i] = r.String()
+		}
+		m.mu.Unlock()
+		return nil, fmt.Errorf("mock transport: no route matches %s (registered: %s)", detail, strings.Join(registered, "; "))
+	}
+
+	route.calls++
+	if len(route.replies) == 0 {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("mock transport: route %s has no reply", route)
+	}
+	idx := route.calls - 1
+	if idx >= len(route.replies) {
+		idx = len(route.replies) - 1
+	}
+	reply := route.replies[idx]
+	m.mu.Unlock()
+
+	if reply.err != nil {
+		return nil, reply.err
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", reply.status, http.StatusText(reply.status)),
+		StatusCode:    reply.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        reply.headers.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(reply.body)),
+		ContentLength: int64(len(reply.body)),
+		Request:       req,
+	}, nil
+}
+
+// AssertExpectations 断言所有路由按预期被调用且没有未匹配的请求
+func (m *MockTransport) AssertExpectations(t TestingTB) bool {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ok := true
+	for _, r := range m.routes {
+		switch {
+		case r.times > 0 && r.calls != r.times:
+			t.Errorf("mock transport: %s expected %d calls, got %d", r, r.times, r.calls)
+			ok = false
+		case r.times <= 0 && r.calls == 0:
+			t.Errorf("mock transport: %s was never called", r)
+			ok = false
+		}
+	}
+	for _, detail := range m.unmatched {
+		t.Errorf("mock transport: unmatched request %s", detail)
+		ok = false
+	}
+	return ok
+}
+
+// WithTransport 使用指定的传输层发送请求（如 MockTransport），客户端为独立副本
+// 需要重试时应先调用 WithTransport 再调用 WithRetry
+func (h XHttp) WithTransport(rt http.RoundTripper) XHttp {
+	client := http.Client{}
+	if h.client != nil {
+		client = *h.client
+	}
+	client.Transport = rt
+	h.client = &client
+	return h
+}
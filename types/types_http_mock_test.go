@@ -0,0 +1,102 @@
+package types
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMockTransportReply(t *testing.T) {
+	mock := NewMockTransport()
+	mock.On("GET", "https://api.test/users/*").
+		Reply(http.StatusOK, map[string]interface{}{"id": 7, "name": "tom"}, map[string]string{"X-Trace": "abc"})
+	mock.On("POST", `^https://api\.test/users$`).Reply(http.StatusCreated, "created")
+
+	client := Http().BaseURL("https://api.test").WithTransport(mock)
+
+	resp, err := client.Get("/users/7?expand=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.AssertStatus(t, http.StatusOK)
+	resp.AssertHeader(t, "X-Trace", "abc")
+	resp.AssertHeader(t, "Content-Type", "application/json")
+	resp.AssertJSONPath(t, "name", "tom")
+
+	resp, err = client.Post("/users", map[string]interface{}{"name": "tom"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.AssertStatus(t, http.StatusCreated)
+	resp.AssertBodyContains(t, "created")
+
+	mock.AssertExpectations(t)
+}
+
+func TestMockTransportRetry(t *testing.T) {
+	mock := NewMockTransport()
+	route := mock.On("GET", "*/flaky").
+		Reply(http.StatusServiceUnavailable, nil).
+		Reply(http.StatusBadGateway, nil).
+		Reply(http.StatusOK, "ok")
+
+	client := Http().WithTransport(mock).WithRetry(RetryConfig{MaxRetries: 3})
+	resp, err := client.Get("https://api.test/flaky")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.AssertStatus(t, http.StatusOK)
+	if route.Calls() != 3 {
+		t.Errorf("calls = %d, want 3", route.Calls())
+	}
+}
+
+func TestMockTransportOrderedTimes(t *testing.T) {
+	mock := NewMockTransport()
+	mock.On("GET", "*/token").Times(1).Reply(http.StatusOK, "first")
+	mock.On("", "*/token").Reply(http.StatusOK, "later")
+
+	client := Http().WithTransport(mock)
+	for _, want := range []string{"first", "later", "later"} {
+		resp, err := client.Get("https://api.test/token")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.String() != want {
+			t.Errorf("body = %q, want %q", resp.String(), want)
+		}
+	}
+	mock.AssertExpectations(t)
+}
+
+func TestMockTransportFailures(t *testing.T) {
+	mock := NewMockTransport()
+	mock.On("GET", "*/used").Reply(http.StatusOK, nil)
+	mock.On("GET", "*/never").Reply(http.StatusOK, nil)
+	mock.On("GET", "*/twice").Times(2).Reply(http.StatusOK, nil)
+	mock.On("GET", "*/down").ReplyError(errors.New("connection refused"))
+
+	client := Http().WithTransport(mock)
+	client.Get("https://api.test/used")
+	client.Get("https://api.test/twice")
+
+	_, err := client.Get("https://api.test/missing?q=1")
+	if err == nil || !strings.Contains(err.Error(), "GET https://api.test/missing?q=1") {
+		t.Errorf("unmatched request error should include request details, got %v", err)
+	}
+	if _, err := client.Get("https://api.test/down"); err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("expected transport error, got %v", err)
+	}
+
+	tb := &mockTB{}
+	if mock.AssertExpectations(tb) {
+		t.Error("AssertExpectations should fail")
+	}
+	report := strings.Join(tb.errors, "\n")
+	for _, want := range []string{"GET */never was never called", "GET */twice expected 2 calls, got 1", "unmatched request GET https://api.test/missing?q=1"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// 内容协商：按优先级生成 Accept 请求头，并根据响应的 Content-Type 选择解码方式
+
+// ErrUnsupportedContentType 响应的 Content-Type 无法解码，可通过 errors.Is 判断
+var ErrUnsupportedContentType = errors.New("unsupported content type")
+
+// UnsupportedContentTypeError DecodeInto 遇到无法处理的 Content-Type 或字符集时返回的错误
+type UnsupportedContentTypeError struct {
+	ContentType string // 响应中的原始 Content-Type
+	Reason      string
+}
+
+func (e *UnsupportedContentTypeError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("unsupported content type %q: %s", e.ContentType, e.Reason)
+	}
+	return fmt.Sprintf("unsupported content type %q", e.ContentType)
+}
+
+// Unwrap 使 errors.Is(err, ErrUnsupportedContentType) 成立
+func (e *UnsupportedContentTypeError) Unwrap() error {
+	return ErrUnsupportedContentType
+}
+
+// acceptAliases Accept 支持的简写
+var acceptAliases = map[string]string{
+	"json": "application/json",
+	"xml":  "application/xml",
+	"form": "application/x-www-form-urlencoded",
+	"text": "text/plain",
+	"html": "text/html",
+}
+
+// Accept 按优先级设置 Accept 请求头，支持 json / xml / form / text / html 简写
+// 未显式指定 q 值的类型依次使用 1、0.9、0.8 ……（最低 0.1），如 Accept("json", "xml", "*/*")
+// 生成 "application/json, application/xml;q=0.9, */*;q=0.8"
+func (h XHttp) Accept(types ...string) XHttp {
+	parts := make([]string, 0, len(types))
+	for i, t := range types {
+		t = strings.TrimSpace(t)
+		if full, ok := acceptAliases[strings.ToLower(t)]; ok {
+			t = full
+		}
+		if i > 0 && !strings.Contains(t, ";q=") {
+			q := max(10-i, 1)
+			t += ";q=" + strconv.FormatFloat(float64(q)/10, 'f', -1, 64)
+		}
+		parts = append(parts, t)
+	}
+	return h.Header("Accept", strings.Join(parts, ", "))
+}
+
+// Negotiated 返回响应 Content-Type 中的媒体类型（小写，不含参数），无法解析时返回空字符串
+func (r *XHttpResponse) Negotiated() string {
+	mediaType, _, err := mime.ParseMediaType(r.GetContentType())
+	if err != nil {
+		return ""
+	}
+	return mediaType
+}
+
+// DecodeInto 根据响应的 Content-Type 选择解码方式
+//
+//   - application/json、*+json：json.Unmarshal
+//   - application/xml、text/xml、*+xml：xml.Unmarshal
+//   - application/x-www-form-urlencoded：v 可为 *url.Values、*map[string][]string 或 *map[string]string（取第一个值）
+//   - text/*：v 可为 *string、*XStr 或 *[]byte
+//
+// Content-Type 带有非 UTF-8 的 charset（如 gbk、gb18030、big5）时先转换为 UTF-8 再解码。
+// 无法处理的类型或字符集返回 *UnsupportedContentTypeError。
+func (r *XHttpResponse) DecodeInto(v interface{}) error {
+	contentType := r.GetContentType()
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return &UnsupportedContentTypeError{ContentType: contentType, Reason: err.Error()}
+	}
+
+	body, converted, err := decodeCharset(r.bodyBytes, params["charset"])
+	if err != nil {
+		return &UnsupportedContentTypeError{ContentType: contentType, Reason: err.Error()}
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return json.Unmarshal(body, v)
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return decodeXML(body, converted, v)
+	case mediaType == "application/x-www-form-urlencoded":
+		return decodeForm(body, v)
+	case strings.HasPrefix(mediaType, "text/"):
+		return decodeText(body, v)
+	default:
+		return &UnsupportedContentTypeError{ContentType: contentType}
+	}
+}
+
+// decodeCharset 将 body 转换为 UTF-8，返回是否发生了转换
+func decodeCharset(body []byte, charset string) ([]byte, bool, error) {
+	charset = strings.ToLower(strings.TrimSpace(charset))
+	if charset == "" || charset == "utf-8" || charset == "utf8" || charset == "us-ascii" {
+		return body, false, nil
+	}
+
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, false, fmt.Errorf("unknown charset %q", charset)
+	}
+	decoded, err := enc.NewDecoder().Bytes(body)
+	if err != nil {
+		return nil, false, fmt.Errorf("decode %s body: %w", charset, err)
+	}
+	return decoded, true, nil
+}
+
+// decodeXML 解码 XML；body 已转换为 UTF-8 时忽略 XML 声明中的 encoding
+func decodeXML(body []byte, converted bool, v interface{}) error {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		if converted {
+			return input, nil
+		}
+		enc, err := htmlindex.Get(label)
+		if err != nil {
+			return nil, fmt.Errorf("unknown charset %q", label)
+		}
+		return enc.NewDecoder().Reader(input), nil
+	}
+	return decoder.Decode(v)
+}
+
+// decodeForm 解码 application/x-www-form-urlencoded
+func decodeForm(body []byte, v interface{}) error {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return err
+	}
+
+	switch target := v.(type) {
+	case *url.Values:
+		*target = values
+	case *map[string][]string:
+		*target = values
+	case *map[string]string:
+		m := make(map[string]string, len(values))
+		for key := range values {
+			m[key] = values.Get(key)
+		}
+		*target = m
+	default:
+		return fmt.Errorf("cannot decode form body into %T", v)
+	}
+	return nil
+}
+
+// decodeText 解码 text/*
+func decodeText(body []byte, v interface{}) error {
+	switch target := v.(type) {
+	case *string:
+		*target = string(body)
+	case *XStr:
+		*target = XStr(body)
+	case *[]byte:
+		*target = append([]byte(nil), body...)
+	default:
+		return fmt.Errorf("cannot decode text body into %T", v)
+	}
+	return nil
+}
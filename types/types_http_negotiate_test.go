@@ -0,0 +1,138 @@
+package types
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// newNegotiateServer 返回固定 Content-Type 与响应体的测试服务
+func newNegotiateServer(t *testing.T, contentType string, body []byte) *XHttpResponse {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	resp, err := Http().Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestXHttpAccept(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Accept")
+	}))
+	defer server.Close()
+
+	if _, err := Http().Accept("json", "text/xml;q=0.5", "xml", "*/*").Get(server.URL); err != nil {
+		t.Fatal(err)
+	}
+	if want := "application/json, text/xml;q=0.5, application/xml;q=0.8, */*;q=0.7"; got != want {
+		t.Errorf("Accept = %q, want %q", got, want)
+	}
+}
+
+func TestXHttpDecodeInto(t *testing.T) {
+	type user struct {
+		Name string `json:"name" xml:"name"`
+		Age  int    `json:"age" xml:"age"`
+	}
+
+	t.Run("json", func(t *testing.T) {
+		resp := newNegotiateServer(t, "application/json; charset=utf-8", []byte(`{"name":"tom","age":3}`))
+		var u user
+		if err := resp.DecodeInto(&u); err != nil || u != (user{"tom", 3}) {
+			t.Errorf("DecodeInto = %+v, %v", u, err)
+		}
+		if resp.Negotiated() != "application/json" {
+			t.Errorf("Negotiated = %q", resp.Negotiated())
+		}
+	})
+
+	t.Run("problem+json", func(t *testing.T) {
+		resp := newNegotiateServer(t, "application/problem+json", []byte(`{"name":"err"}`))
+		var u user
+		if err := resp.DecodeInto(&u); err != nil || u.Name != "err" {
+			t.Errorf("DecodeInto = %+v, %v", u, err)
+		}
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		resp := newNegotiateServer(t, "text/xml", []byte(`<?xml version="1.0"?><user><name>tom</name><age>3</age></user>`))
+		var u user
+		if err := resp.DecodeInto(&u); err != nil || u != (user{"tom", 3}) {
+			t.Errorf("DecodeInto = %+v, %v", u, err)
+		}
+	})
+
+	t.Run("form", func(t *testing.T) {
+		resp := newNegotiateServer(t, "application/x-www-form-urlencoded", []byte(`name=tom&tag=a&tag=b`))
+		var values url.Values
+		if err := resp.DecodeInto(&values); err != nil || values["tag"][1] != "b" {
+			t.Errorf("DecodeInto url.Values = %v, %v", values, err)
+		}
+		var flat map[string]string
+		if err := resp.DecodeInto(&flat); err != nil || flat["name"] != "tom" || flat["tag"] != "a" {
+			t.Errorf("DecodeInto map = %v, %v", flat, err)
+		}
+		var u user
+		if err := resp.DecodeInto(&u); err == nil {
+			t.Error("form into struct should fail")
+		}
+	})
+
+	t.Run("text", func(t *testing.T) {
+		resp := newNegotiateServer(t, "text/plain", []byte("hello"))
+		var s XStr
+		if err := resp.DecodeInto(&s); err != nil || s != "hello" {
+			t.Errorf("DecodeInto = %q, %v", s, err)
+		}
+	})
+
+	t.Run("gbk json", func(t *testing.T) {
+		body, err := simplifiedchinese.GBK.NewEncoder().Bytes([]byte(`{"name":"张三","age":30}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp := newNegotiateServer(t, "application/json;charset=gbk", body)
+		var u user
+		if err := resp.DecodeInto(&u); err != nil || u != (user{"张三", 30}) {
+			t.Errorf("DecodeInto = %+v, %v", u, err)
+		}
+	})
+
+	t.Run("gbk xml declaration", func(t *testing.T) {
+		body, _ := simplifiedchinese.GBK.NewEncoder().Bytes([]byte(`<?xml version="1.0" encoding="GBK"?><user><name>李四</name></user>`))
+		for _, contentType := range []string{"application/xml", "application/xml; charset=GBK"} {
+			var u user
+			if err := newNegotiateServer(t, contentType, body).DecodeInto(&u); err != nil || u.Name != "李四" {
+				t.Errorf("%s: DecodeInto = %+v, %v", contentType, u, err)
+			}
+		}
+	})
+}
+
+func TestXHttpDecodeIntoUnsupported(t *testing.T) {
+	for _, contentType := range []string{"image/png", "application/json; charset=klingon", ""} {
+		var v interface{}
+		err := newNegotiateServer(t, contentType, []byte("x")).DecodeInto(&v)
+
+		var typed *UnsupportedContentTypeError
+		if !errors.Is(err, ErrUnsupportedContentType) || !errors.As(err, &typed) {
+			t.Errorf("%q: error = %v, want *UnsupportedContentTypeError", contentType, err)
+			continue
+		}
+		if contentType != "" && typed.ContentType != contentType {
+			t.Errorf("error should carry the content type, got %q", typed.ContentType)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package types
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// 连接池调优与统计
+
+// PoolStats 连接池统计信息，基于拨号计数，仅反映经由 XHttp 连接池设置创建的 Transport
+type PoolStats struct {
+	InFlight     int64          // 正在进行的请求数
+	Dials        int64          // 累计建立的连接数
+	OpenConns    int64          // 当前打开的连接数（含空闲连接）
+	ConnsPerHost map[string]int // 每个地址（host:port）当前打开的连接数
+}
+
+// MaxIdleConns 设置所有主机的最大空闲连接数，0 表示不限制
+func (h XHttp) MaxIdleConns(n int) XHttp {
+	return h.tunePool(func(t *http.Transport) { t.MaxIdleConns = n })
+}
+
+// MaxIdleConnsPerHost 设置每个主机的最大空闲连接数，默认 Transport 为 2
+func (h XHttp) MaxIdleConnsPerHost(n int) XHttp {
+	return h.tunePool(func(t *http.Transport) { t.MaxIdleConnsPerHost = n })
+}
+
+// MaxConnsPerHost 设置每个主机的最大连接数（含拨号中、使用中与空闲连接），0 表示不限制
+func (h XHttp) MaxConnsPerHost(n int) XHttp {
+	return h.tunePool(func(t *http.Transport) { t.MaxConnsPerHost = n })
+}
+
+// IdleConnTimeout 设置空闲连接的最长保留时间
+func (h XHttp) IdleConnTimeout(d time.Duration) XHttp {
+	return h.tunePool(func(t *http.Transport) { t.IdleConnTimeout = d })
+}
+
+// PoolStats 返回连接池统计信息，未设置过连接池参数时返回零值
+func (h XHttp) PoolStats() PoolStats {
+	if h.client == nil {
+		return PoolStats{ConnsPerHost: map[string]int{}}
+	}
+
+	rt := h.client.Transport
+	for unwrapped := false; !unwrapped; {
+		switch t := rt.(type) {
+		case *retryTransport:
+			rt = t.Transport
+		case *singleflightTransport:
+			rt = t.Transport
+		default:
+			unwrapped = true
+		}
+	}
+	if pooled, ok := rt.(*pooledTransport); ok {
+		return pooled.stats.snapshot()
+	}
+	return PoolStats{ConnsPerHost: map[string]int{}}
+}
+
+// tunePool 复制底层 Transport 后应用连接池设置，不修改共享的 Transport（包括 http.DefaultTransport）
+// 自定义的 RoundTripper（如 MockTransport）无法调整，保持不变
+func (h XHttp) tunePool(apply func(t *http.Transport)) XHttp {
+	client := http.Client{}
+	if h.client != nil {
+		client = *h.client
+	}
+	client.Transport = tuneTransport(client.Transport, apply)
+	h.client = &client
+	return h
+}
+
+// tuneTransport 复制 Transport 并应用设置，穿透重试与请求合并包装
+func tuneTransport(rt http.RoundTripper, apply func(t *http.Transport)) http.RoundTripper {
+	switch t := rt.(type) {
+	case nil:
+		if def, ok := http.DefaultTransport.(*http.Transport); ok {
+			return newPooledTransport(def.Clone(), def.DialContext, apply)
+		}
+		return newPooledTransport(&http.Transport{}, nil, apply)
+	case *http.Transport:
+		return newPooledTransport(t.Clone(), t.DialContext, apply)
+	case *pooledTransport:
+		return newPooledTransport(t.base.Clone(), t.dial, apply)
+	case *retryTransport:
+		retry := *t
+		retry.Transport = tuneTransport(t.Transport, apply)
+		return &retry
+	case *singleflightTransport:
+		return &singleflightTransport{Transport: tuneTransport(t.Transport, apply), group: t.group}
+	default:
+		return rt
+	}
+}
+
+// dialFunc 拨号函数
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// pooledTransport 带统计的 Transport，每次调优都会创建新的实例与新的统计
+type pooledTransport struct {
+	base  *http.Transport
+	dial  dialFunc // 原始拨号函数，再次调优时基于它重新包装
+	stats *poolCounters
+}
+
+// newPooledTransport 应用设置并以计数拨号器替换 DialContext
+func newPooledTransport(base *http.Transport, dial dialFunc, apply func(t *http.Transport)) *pooledTransport {
+	if dial == nil {
+		dial = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+	}
+	apply(base)
+
+	p := &pooledTransport{base: base, dial: dial, stats: &poolCounters{perHost: make(map[string]int)}}
+	base.DialContext = p.countingDial
+	return p
+}
+
+// RoundTrip 实现 http.RoundTripper，统计进行中的请求
+func (p *pooledTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	p.stats.addInFlight(1)
+	defer p.stats.addInFlight(-1)
+	return p.base.RoundTrip(req)
+}
+
+// CloseIdleConnections 关闭空闲连接，供 http.Client.CloseIdleConnections 调用
+func (p *pooledTransport) CloseIdleConnections() {
+	p.base.CloseIdleConnections()
+}
+
+// countingDial 拨号并统计连接，连接关闭时扣减
+func (p *pooledTransport) countingDial(ctx context.Context, network, addr string) (net.Conn, error) {
+	conn, err := p.dial(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+	p.stats.opened(addr)
+	return &countedConn{Conn: conn, release: func() { p.stats.closed(addr) }}, nil
+}
+
+// poolCounters 连接池计数器
+type poolCounters struct {
+	mu       sync.Mutex
+	inFlight int64
+	dials    int64
+	open     int64
+	perHost  map[string]int
+}
+
+func (c *poolCounters) addInFlight(delta int64) {
+	c.mu.Lock()
+	c.inFlight += delta
+	c.mu.Unlock()
+}
+
+func (c *poolCounters) opened(addr string) {
+	c.mu.Lock()
+	c.dials++
+	c.open++
+	c.perHost[addr]++
+	c.mu.Unlock()
+}
+
+func (c *poolCounters) closed(addr string) {
+	c.mu.Lock()
+	c.open--
+	if c.perHost[addr]--; c.perHost[addr] <= 0 {
+		delete(c.perHost, addr)
+	}
+	c.mu.Unlock()
+}
+
+func (c *poolCounters) snapshot() PoolStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	perHost := make(map[string]int, len(c.perHost))
+	for addr, n := range c.perHost {
+		perHost[addr] = n
+	}
+	return PoolStats{InFlight: c.inFlight, Dials: c.dials, OpenConns: c.open, ConnsPerHost: perHost}
+}
+
+// countedConn 关闭时只扣减一次计数的连接
+type countedConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+// Close 关闭连接
+func (c *countedConn) Close() error {
+	c.once.Do(c.release)
+	return c.Conn.Close()
+}
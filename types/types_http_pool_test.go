@@ -0,0 +1,113 @@
+package types
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHttpPoolLimitsConnections(t *testing.T) {
+	var active, peak int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt64(&active, 1)
+		defer atomic.AddInt64(&active, -1)
+		for {
+			p := atomic.LoadInt64(&peak)
+			if n <= p || atomic.CompareAndSwapInt64(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := Http().BaseURL(server.URL).MaxConnsPerHost(2).MaxIdleConnsPerHost(2)
+
+	var wg sync.WaitGroup
+	var failures, maxInFlight int64
+	for i := 0; i < 40; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get("/")
+			if err != nil || resp.String() != "ok" {
+				atomic.AddInt64(&failures, 1)
+				return
+			}
+			if stats := client.PoolStats(); stats.InFlight > atomic.LoadInt64(&maxInFlight) {
+				atomic.StoreInt64(&maxInFlight, stats.InFlight)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if failures > 0 {
+		t.Fatalf("%d requests failed", failures)
+	}
+	stats := client.PoolStats()
+	if stats.Dials < 1 || stats.Dials > 2 {
+		t.Errorf("Dials = %d, want 1..2 with MaxConnsPerHost(2)", stats.Dials)
+	}
+	if peak > 2 {
+		t.Errorf("server saw %d concurrent requests, want at most 2", peak)
+	}
+	if stats.InFlight != 0 {
+		t.Errorf("InFlight = %d after all requests finished", stats.InFlight)
+	}
+	host := server.Listener.Addr().String()
+	if stats.ConnsPerHost[host] != int(stats.OpenConns) || stats.OpenConns > 2 {
+		t.Errorf("ConnsPerHost = %v, OpenConns = %d", stats.ConnsPerHost, stats.OpenConns)
+	}
+
+	// 关闭空闲连接后计数归零
+	client.client.CloseIdleConnections()
+	if stats := client.PoolStats(); stats.OpenConns != 0 || len(stats.ConnsPerHost) != 0 {
+		t.Errorf("after CloseIdleConnections: %+v", stats)
+	}
+}
+
+func TestHttpPoolDoesNotMutateSharedTransport(t *testing.T) {
+	def := http.DefaultTransport.(*http.Transport)
+	before := def.MaxIdleConnsPerHost
+
+	base := Http()
+	tuned := base.MaxIdleConnsPerHost(64).IdleConnTimeout(time.Minute)
+	if def.MaxIdleConnsPerHost != before || base.client.Transport != nil {
+		t.Fatal("tuning must not touch http.DefaultTransport or the original client")
+	}
+
+	pooled := tuned.client.Transport.(*pooledTransport)
+	if pooled.base.MaxIdleConnsPerHost != 64 || pooled.base.IdleConnTimeout != time.Minute {
+		t.Errorf("settings not applied: %d, %v", pooled.base.MaxIdleConnsPerHost, pooled.base.IdleConnTimeout)
+	}
+
+	// 派生客户端拥有独立的 Transport 与统计
+	derived := tuned.MaxIdleConns(10)
+	if derived.client.Transport == tuned.client.Transport || pooled.base.MaxIdleConns == 10 {
+		t.Error("derived client must not share or mutate the parent transport")
+	}
+	if got := derived.client.Transport.(*pooledTransport).base.MaxIdleConnsPerHost; got != 64 {
+		t.Errorf("derived client lost inherited setting: %d", got)
+	}
+
+	// 自定义 Transport 同样被复制，重试包装被穿透
+	custom := &http.Transport{MaxIdleConns: 3}
+	retried := HttpWithClient(&http.Client{Transport: custom}).WithRetry(RetryConfig{MaxRetries: 1}).MaxConnsPerHost(5)
+	inner := retried.client.Transport.(*retryTransport).Transport.(*pooledTransport)
+	if custom.MaxConnsPerHost != 0 || inner.base.MaxConnsPerHost != 5 || inner.base.MaxIdleConns != 3 {
+		t.Error("custom transport should be cloned behind the retry wrapper")
+	}
+	if stats := retried.PoolStats(); stats.Dials != 0 || stats.ConnsPerHost == nil {
+		t.Errorf("fresh PoolStats = %+v", stats)
+	}
+
+	// 无法调整的 RoundTripper 保持不变
+	mock := NewMockTransport()
+	if Http().WithTransport(mock).MaxConnsPerHost(1).client.Transport != mock {
+		t.Error("custom RoundTripper should be left untouched")
+	}
+}
@@ -0,0 +1,149 @@
+package types
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// 请求合并：并发发出的相同 GET / HEAD 请求只访问一次上游
+
+// WithSingleflight 合并并发的相同请求
+//
+// 方法、URL 与请求头（按名称排序）都相同的 GET / HEAD 请求在前一个仍在进行时不会再次发出，
+// 而是等待同一个响应；响应体被完整读取后由所有等待者共享，每个等待者得到独立的 XHttpResponse
+// （Header 为副本，Body 可独立读取），共享的字节切片应只读。
+// 共享的请求不受单个等待者取消的影响，只有全部等待者都取消（或超时）后才会被取消。
+// 之后派生的 XHttp 共享同一个合并组。
+func (h XHttp) WithSingleflight() XHttp {
+	originalClient := h.client
+	h.client = &http.Client{
+		Timeout:       originalClient.Timeout,
+		CheckRedirect: originalClient.CheckRedirect,
+		Jar:           originalClient.Jar,
+		Transport: &singleflightTransport{
+			Transport: originalClient.Transport,
+			group:     &flightGroup{calls: make(map[string]*flightCall)},
+		},
+	}
+	return h
+}
+
+// singleflightTransport 合并相同请求的 RoundTripper
+type singleflightTransport struct {
+	Transport http.RoundTripper
+	group     *flightGroup
+}
+
+// flightGroup 进行中的请求，按合并键索引
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// flightCall 进行中的共享请求
+type flightCall struct {
+	done    chan struct{}
+	resp    *http.Response
+	body    []byte
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+func (t *singleflightTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if (req.Method != http.MethodGet && req.Method != http.MethodHead) || req.Body != nil && req.Body != http.NoBody {
+		return transport.RoundTrip(req)
+	}
+
+	g := t.group
+	key := flightKey(req)
+	g.mu.Lock()
+	call, ok := g.calls[key]
+	if !ok {
+		// 共享请求不继承第一个调用者的取消信号，由最后一个等待者决定是否取消
+		ctx, cancel := context.WithCancel(context.WithoutCancel(req.Context()))
+		call = &flightCall{done: make(chan struct{}), cancel: cancel}
+		g.calls[key] = call
+		go g.fetch(transport, req.Clone(ctx), key, call)
+	}
+	call.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		if call.err != nil {
+			return nil, call.err
+		}
+		return call.share(req), nil
+	case <-req.Context().Done():
+		g.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			call.cancel()
+			if g.calls[key] == call {
+				delete(g.calls, key)
+			}
+		}
+		g.mu.Unlock()
+		return nil, req.Context().Err()
+	}
+}
+
+// fetch 发出共享请求并完整读取响应体
+func (g *flightGroup) fetch(transport http.RoundTripper, req *http.Request, key string, call *flightCall) {
+	resp, err := transport.RoundTrip(req)
+	var body []byte
+	if err == nil {
+		body, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+
+	g.mu.Lock()
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+	g.mu.Unlock()
+
+	call.resp, call.body, call.err = resp, body, err
+	call.cancel()
+	close(call.done)
+}
+
+// share 为等待者生成独立的响应
+func (c *flightCall) share(req *http.Request) *http.Response {
+	resp := *c.resp
+	resp.Header = c.resp.Header.Clone()
+	resp.Body = io.NopCloser(bytes.NewReader(c.body))
+	resp.Request = req
+	return &resp
+}
+
+// flightKey 请求的合并键：方法、URL 与排序后的请求头
+func flightKey(req *http.Request) string {
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString(req.Method)
+	sb.WriteByte(' ')
+	sb.WriteString(req.URL.String())
+	for _, name := range names {
+		sb.WriteByte('\n')
+		sb.WriteString(name)
+		sb.WriteByte(':')
+		sb.WriteString(strings.Join(req.Header[name], ","))
+	}
+	return sb.String()
+}
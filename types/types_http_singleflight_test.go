@@ -0,0 +1,202 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flightWaiters 返回合并组中所有进行中请求的等待者总数
+func flightWaiters(h XHttp) int {
+	g := h.client.Transport.(*singleflightTransport).group
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	total := 0
+	for _, call := range g.calls {
+		total += call.waiters
+	}
+	return total
+}
+
+// waitFor 轮询直到条件成立
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSingleflightCoalescesIdenticalGets(t *testing.T) {
+	var hits int64
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&hits, 1)
+		<-release
+		w.Header().Set("X-Served", "1")
+		w.Write([]byte(`{"widgets": 3}`))
+	}))
+	defer server.Close()
+
+	client := Http().BaseURL(server.URL).WithSingleflight()
+
+	const n = 20
+	responses := make([]*XHttpResponse, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i], errs[i] = client.Get("/dashboard")
+		}(i)
+	}
+	waitFor(t, func() bool { return flightWaiters(client) == n })
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt64(&hits); got != 1 {
+		t.Fatalf("upstream hits = %d, want 1", got)
+	}
+	for i, resp := range responses {
+		if errs[i] != nil {
+			t.Fatalf("request %d: %v", i, errs[i])
+		}
+		if resp.String() != `{"widgets": 3}` || resp.GetHeader("X-Served") != "1" {
+			t.Fatalf("request %d: unexpected response %q", i, resp.String())
+		}
+	}
+
+	// 每个响应的 Header 相互独立
+	responses[0].Header.Set("X-Served", "changed")
+	if responses[1].GetHeader("X-Served") != "1" {
+		t.Error("responses should not share headers")
+	}
+
+	// 请求完成后再次请求会重新访问上游
+	if _, err := client.Get("/dashboard"); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt64(&hits); got != 2 {
+		t.Errorf("hits after completion = %d, want 2", got)
+	}
+}
+
+func TestSingleflightKeyIncludesHeaders(t *testing.T) {
+	var hits int64
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&hits, 1)
+		<-release
+		w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer server.Close()
+
+	base := Http().BaseURL(server.URL).WithSingleflight()
+	var wg sync.WaitGroup
+	results := make([]string, 2)
+	for i, token := range []string{"a", "b"} {
+		wg.Add(1)
+		go func(i int, client XHttp) {
+			defer wg.Done()
+			resp, err := client.Get("/me")
+			if err == nil {
+				results[i] = resp.String()
+			}
+		}(i, HttpWithClient(base.client).BaseURL(server.URL).Bearer(token))
+	}
+	waitFor(t, func() bool { return atomic.LoadInt64(&hits) == 2 })
+	close(release)
+	wg.Wait()
+
+	if results[0] != "Bearer a" || results[1] != "Bearer b" {
+		t.Errorf("requests with different headers must not be merged: %v", results)
+	}
+}
+
+func TestSingleflightCancellation(t *testing.T) {
+	var hits int64
+	upstreamCanceled := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&hits, 1)
+		select {
+		case <-release:
+			w.Write([]byte("ok"))
+		case <-r.Context().Done():
+			close(upstreamCanceled)
+		}
+	}))
+	defer server.Close()
+
+	client := Http().BaseURL(server.URL).WithSingleflight()
+
+	// 一个等待者取消不影响其他等待者
+	ctx, cancel := context.WithCancel(context.Background())
+	canceledErr := make(chan error, 1)
+	go func() {
+		_, err := client.GetWithContext(ctx, "/slow")
+		canceledErr <- err
+	}()
+	okResp := make(chan *XHttpResponse, 1)
+	go func() {
+		resp, _ := client.Get("/slow")
+		okResp <- resp
+	}()
+	waitFor(t, func() bool { return flightWaiters(client) == 2 })
+	cancel()
+	if err := <-canceledErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("canceled waiter error = %v", err)
+	}
+	close(release)
+	if resp := <-okResp; resp == nil || resp.String() != "ok" {
+		t.Fatal("remaining waiter should receive the shared response")
+	}
+
+	// 最后一个等待者取消时取消上游请求
+	release = make(chan struct{})
+	ctx, cancel = context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.GetWithContext(ctx, "/slow")
+		done <- err
+	}()
+	waitFor(t, func() bool { return flightWaiters(client) == 1 })
+	cancel()
+	<-done
+	select {
+	case <-upstreamCanceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shared request should be canceled when every waiter is gone")
+	}
+	if got := atomic.LoadInt64(&hits); got != 2 {
+		t.Errorf("hits = %d, want 2", got)
+	}
+}
+
+func TestSingleflightSkipsNonIdempotentMethods(t *testing.T) {
+	var hits int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&hits, 1)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := Http().BaseURL(server.URL).WithSingleflight()
+	for i := 0; i < 3; i++ {
+		if _, err := client.Post("/items", map[string]int{"n": i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if hits != 3 {
+		t.Errorf("POST hits = %d, want 3", hits)
+	}
+}
@@ -0,0 +1,245 @@
+package types
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// 分片续传上传
+//
+// 服务端需实现以下协议，同一上传的所有请求都携带 X-Upload-Session 会话 ID：
+//   - HEAD {url}：在 X-Upload-Offset 中返回已连续接收的字节数，未知会话返回 404 或偏移 0；
+//   - PUT {url}：上传一个分片，Content-Range 为 "bytes start-end/total"，X-Chunk-SHA256 为分片的十六进制 SHA-256，
+//     服务端校验通过后返回 2xx，校验失败返回 422，偏移与已接收的不一致时返回 409；
+//   - POST {url}：X-Upload-Complete 为 "true"，X-Content-SHA256 为整个文件的 SHA-256，服务端合并后返回 2xx。
+//
+// 会话 ID 由文件内容的 SHA-256 派生，中断后（包括进程重启）对同一文件再次调用即可从服务端记录的偏移继续。
+
+// 分片上传协议使用的请求头
+const (
+	UploadSessionHeader   = "X-Upload-Session"
+	UploadOffsetHeader    = "X-Upload-Offset"
+	UploadCompleteHeader  = "X-Upload-Complete"
+	ChunkChecksumHeader   = "X-Chunk-SHA256"
+	ContentChecksumHeader = "X-Content-SHA256"
+)
+
+// UploadOptions UploadFileResumable 的可选配置
+type UploadOptions struct {
+	MaxRetries int                         // 单个分片连续失败后的重试次数，0 表示默认 3 次，负数表示不重试
+	RetryDelay time.Duration               // 第 n 次重试前等待 n * RetryDelay，0 表示默认 500ms
+	Progress   func(uploaded, total int64) // 开始时与每个分片成功后调用
+}
+
+// UploadStatusError 上传请求返回了非 2xx 状态码
+type UploadStatusError struct {
+	Method     string
+	StatusCode int
+	Body       string
+}
+
+// Error 实现 error 接口
+func (e *UploadStatusError) Error() string {
+	return fmt.Sprintf("upload %s: unexpected status %d: %s", e.Method, e.StatusCode, e.Body)
+}
+
+// UploadFileResumable 使用默认客户端分片上传文件，见 XHttp.UploadFileResumable
+func UploadFileResumable(ctx context.Context, url, filePath string, chunkSize int64, opts ...UploadOptions) (*XHttpResponse, error) {
+	return Http().UploadFileResumable(ctx, url, filePath, chunkSize, opts...)
+}
+
+// UploadFileResumable 按 chunkSize 分片上传文件，支持断点续传，返回完成请求的响应
+//
+// 开始前通过 HEAD 查询服务端已接收的偏移，只上传剩余部分；单个分片失败（网络错误、5xx、408、409、422、429）时
+// 重新查询偏移后只重试该分片，连续失败超过 MaxRetries 次返回错误，之后再次调用会从已上传的位置继续。
+// 其他状态码（如 401、413）返回 *UploadStatusError，不重试。
+func (h XHttp) UploadFileResumable(ctx context.Context, url, filePath string, chunkSize int64, opts ...UploadOptions) (*XHttpResponse, error) {
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("chunk size must be positive, got %d", chunkSize)
+	}
+	var opt UploadOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.MaxRetries == 0 {
+		opt.MaxRetries = 3
+	}
+	if opt.RetryDelay == 0 {
+		opt.RetryDelay = 500 * time.Millisecond
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, file)
+	if err != nil {
+		return nil, err
+	}
+	checksum := hex.EncodeToString(hasher.Sum(nil))
+	u := &resumableUpload{h: h, url: h.buildURL(url), session: checksum[:32], size: size}
+
+	offset, err := u.offset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if opt.Progress != nil {
+		opt.Progress(offset, size)
+	}
+
+	bufSize := chunkSize
+	if size < bufSize {
+		bufSize = size
+	}
+	buf := make([]byte, bufSize)
+	for failures := 0; offset < size; {
+		end := offset + chunkSize
+		if end > size {
+			end = size
+		}
+		chunk := buf[:end-offset]
+		if _, err := file.ReadAt(chunk, offset); err != nil {
+			return nil, err
+		}
+
+		err := u.putChunk(ctx, chunk, offset)
+		if err == nil {
+			offset, failures = end, 0
+			if opt.Progress != nil {
+				opt.Progress(offset, size)
+			}
+			continue
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if !retryableUploadError(err) || failures >= opt.MaxRetries {
+			return nil, fmt.Errorf("upload bytes %d-%d of %s: %w", offset, end-1, filePath, err)
+		}
+		failures++
+		if err := sleepContext(ctx, time.Duration(failures)*opt.RetryDelay); err != nil {
+			return nil, err
+		}
+		// 分片可能已被接收，或服务端丢失了部分数据，按服务端记录的偏移继续
+		if serverOffset, err := u.offset(ctx); err == nil {
+			offset = serverOffset
+		}
+	}
+
+	return u.complete(ctx, checksum)
+}
+
+// resumableUpload 一次分片上传的请求参数
+type resumableUpload struct {
+	h       XHttp
+	url     string
+	session string
+	size    int64
+}
+
+// request 发送带会话 ID 的请求，非 2xx 响应返回 *UploadStatusError
+func (u *resumableUpload) request(ctx context.Context, method string, body []byte, headers map[string]string) (*XHttpResponse, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := u.h.newRequest(method, u.url, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(UploadSessionHeader, u.session)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := u.h.doRequest(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp, &UploadStatusError{Method: method, StatusCode: resp.StatusCode, Body: resp.String()}
+	}
+	return resp, nil
+}
+
+// offset 查询服务端已接收的字节数
+func (u *resumableUpload) offset(ctx context.Context) (int64, error) {
+	resp, err := u.request(ctx, http.MethodHead, nil, nil)
+	var statusErr *UploadStatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	value := resp.Header.Get(UploadOffsetHeader)
+	if value == "" {
+		return 0, nil
+	}
+	offset, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || offset < 0 || offset > u.size {
+		return 0, fmt.Errorf("invalid %s %q for a %d byte file", UploadOffsetHeader, value, u.size)
+	}
+	return offset, nil
+}
+
+// putChunk 上传从 offset 开始的分片
+func (u *resumableUpload) putChunk(ctx context.Context, chunk []byte, offset int64) error {
+	sum := sha256.Sum256(chunk)
+	_, err := u.request(ctx, http.MethodPut, chunk, map[string]string{
+		"Content-Type":      "application/octet-stream",
+		"Content-Range":     fmt.Sprintf("bytes %d-%d/%d", offset, offset+int64(len(chunk))-1, u.size),
+		ChunkChecksumHeader: hex.EncodeToString(sum[:]),
+	})
+	return err
+}
+
+// complete 发送完成请求
+func (u *resumableUpload) complete(ctx context.Context, checksum string) (*XHttpResponse, error) {
+	resp, err := u.request(ctx, http.MethodPost, nil, map[string]string{
+		UploadCompleteHeader:  "true",
+		ContentChecksumHeader: checksum,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// retryableUploadError 判断分片上传错误是否可以重试
+func retryableUploadError(err error) bool {
+	var statusErr *UploadStatusError
+	if !errors.As(err, &statusErr) {
+		return true
+	}
+	switch statusErr.StatusCode {
+	case http.StatusRequestTimeout, http.StatusConflict, http.StatusUnprocessableEntity, http.StatusTooManyRequests:
+		return true
+	}
+	return statusErr.StatusCode >= 500
+}
+
+// sleepContext 等待 d，ctx 先取消时返回 ctx.Err()
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,298 @@
+package types
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// uploadServer 实现分片续传协议的测试服务端
+type uploadServer struct {
+	mu        sync.Mutex
+	data      map[string][]byte // session -> 已接收的内容
+	down      bool              // 模拟服务中断：直接断开所有连接
+	failAt    map[int64]int     // 分片起始偏移 -> 剩余的 500 次数
+	dropAt    map[int64]int     // 分片起始偏移 -> 保存后断开连接（响应丢失）的剩余次数
+	status    map[int64]int     // 分片起始偏移 -> 固定返回的状态码
+	puts      []string          // 收到的 Content-Range
+	completed []string          // 完成时的文件内容
+}
+
+func newUploadServer() *uploadServer {
+	return &uploadServer{
+		data:   make(map[string][]byte),
+		failAt: make(map[int64]int),
+		dropAt: make(map[int64]int),
+		status: make(map[int64]int),
+	}
+}
+
+func (s *uploadServer) setDown(down bool) {
+	s.mu.Lock()
+	s.down = down
+	s.mu.Unlock()
+}
+
+// putsFrom 返回从 start 开始的分片被上传的次数
+func (s *uploadServer) putsFrom(start int64) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prefix := fmt.Sprintf("bytes %d-", start)
+	count := 0
+	for _, r := range s.puts {
+		if len(r) >= len(prefix) && r[:len(prefix)] == prefix {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *uploadServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.down {
+		dropConnection(w)
+		return
+	}
+	session := r.Header.Get(UploadSessionHeader)
+	if session == "" {
+		http.Error(w, "missing session", http.StatusBadRequest)
+		return
+	}
+	received, known := s.data[session]
+
+	switch r.Method {
+	case http.MethodHead:
+		if !known {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set(UploadOffsetHeader, strconv.Itoa(len(received)))
+	case http.MethodPut:
+		var start, end, total int64
+		contentRange := r.Header.Get("Content-Range")
+		if _, err := fmt.Sscanf(contentRange, "bytes %d-%d/%d", &start, &end, &total); err != nil {
+			http.Error(w, "bad Content-Range", http.StatusBadRequest)
+			return
+		}
+		s.puts = append(s.puts, contentRange)
+		if code := s.status[start]; code != 0 {
+			w.WriteHeader(code)
+			return
+		}
+		if s.failAt[start] > 0 {
+			s.failAt[start]--
+			http.Error(w, "transient", http.StatusInternalServerError)
+			return
+		}
+		if start != int64(len(received)) {
+			http.Error(w, "offset mismatch", http.StatusConflict)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		sum := sha256.Sum256(body)
+		if int64(len(body)) != end-start+1 || hex.EncodeToString(sum[:]) != r.Header.Get(ChunkChecksumHeader) {
+			http.Error(w, "checksum mismatch", http.StatusUnprocessableEntity)
+			return
+		}
+		s.data[session] = append(received, body...)
+		if s.dropAt[start] > 0 {
+			s.dropAt[start]--
+			dropConnection(w)
+			return
+		}
+	case http.MethodPost:
+		sum := sha256.Sum256(received)
+		if r.Header.Get(UploadCompleteHeader) != "true" || hex.EncodeToString(sum[:]) != r.Header.Get(ContentChecksumHeader) {
+			http.Error(w, "checksum mismatch", http.StatusUnprocessableEntity)
+			return
+		}
+		s.completed = append(s.completed, string(received))
+		delete(s.data, session)
+		w.Write([]byte(`{"status":"done"}`))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// dropConnection 不写响应直接关闭连接
+func dropConnection(w http.ResponseWriter) {
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err == nil {
+		conn.Close()
+	}
+}
+
+// writeUploadFile 写入 n 字节的测试文件
+func writeUploadFile(t *testing.T, n int) (string, []byte) {
+	t.Helper()
+	content := make([]byte, n)
+	for i := range content {
+		content[i] = byte(i * 31)
+	}
+	path := filepath.Join(t.TempDir(), "upload.bin")
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path, content
+}
+
+func TestUploadFileResumableKilledAndResumed(t *testing.T) {
+	server := newUploadServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	path, content := writeUploadFile(t, 10*1024+123)
+	client := Http().BaseURL(ts.URL)
+
+	// 上传 4 个分片后服务中断，重试耗尽后返回错误
+	opts := UploadOptions{MaxRetries: 2, RetryDelay: time.Millisecond, Progress: func(uploaded, total int64) {
+		if uploaded == 4*1024 {
+			server.setDown(true)
+		}
+	}}
+	if _, err := client.UploadFileResumable(context.Background(), "/upload", path, 1024, opts); err == nil {
+		t.Fatal("upload should fail while the server is down")
+	}
+	if server.putsFrom(4*1024) != 0 {
+		t.Errorf("chunk at 4096 reached the server %d times while down", server.putsFrom(4*1024))
+	}
+
+	// 服务恢复后再次调用，从服务端记录的偏移继续
+	server.setDown(false)
+	var progress []int64
+	opts.Progress = func(uploaded, total int64) {
+		if total != int64(len(content)) {
+			t.Errorf("total = %d", total)
+		}
+		progress = append(progress, uploaded)
+	}
+	resp, err := client.UploadFileResumable(context.Background(), "/upload", path, 1024, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.String() != `{"status":"done"}` {
+		t.Errorf("response = %s", resp.String())
+	}
+
+	if len(server.completed) != 1 || server.completed[0] != string(content) {
+		t.Fatalf("completed %d uploads, content match = %v", len(server.completed), len(server.completed) == 1 && server.completed[0] == string(content))
+	}
+	for start := int64(0); start < int64(len(content)); start += 1024 {
+		if n := server.putsFrom(start); n != 1 {
+			t.Errorf("chunk at %d uploaded %d times", start, n)
+		}
+	}
+	want := []int64{4096, 5120, 6144, 7168, 8192, 9216, 10240, int64(len(content))}
+	if fmt.Sprint(progress) != fmt.Sprint(want) {
+		t.Errorf("progress = %v, want %v", progress, want)
+	}
+	if last := server.puts[len(server.puts)-1]; last != fmt.Sprintf("bytes 10240-%d/%d", len(content)-1, len(content)) {
+		t.Errorf("last Content-Range = %s", last)
+	}
+}
+
+func TestUploadFileResumableRetriesChunk(t *testing.T) {
+	server := newUploadServer()
+	server.failAt[2048] = 2 // 两次 500 后成功
+	server.dropAt[3072] = 1 // 分片已保存但响应丢失
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	path, content := writeUploadFile(t, 5000)
+
+	_, err := Http().BaseURL(ts.URL).UploadFileResumable(context.Background(), "/upload", path, 1024, UploadOptions{RetryDelay: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(server.completed) != 1 || server.completed[0] != string(content) {
+		t.Fatal("assembled content does not match the file")
+	}
+	if n := server.putsFrom(2048); n != 3 {
+		t.Errorf("chunk at 2048 uploaded %d times, want 3", n)
+	}
+	// 重试前查询偏移，已保存的分片不再重复上传
+	if n := server.putsFrom(3072); n != 1 {
+		t.Errorf("chunk at 3072 uploaded %d times, want 1", n)
+	}
+	for _, start := range []int64{0, 1024, 4096} {
+		if n := server.putsFrom(start); n != 1 {
+			t.Errorf("chunk at %d uploaded %d times", start, n)
+		}
+	}
+}
+
+func TestUploadFileResumableErrors(t *testing.T) {
+	server := newUploadServer()
+	server.status[1024] = http.StatusRequestEntityTooLarge
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	path, _ := writeUploadFile(t, 3000)
+	client := Http().BaseURL(ts.URL)
+
+	// 非可重试状态码立即返回
+	_, err := client.UploadFileResumable(context.Background(), "/upload", path, 1024, UploadOptions{RetryDelay: time.Millisecond})
+	var statusErr *UploadStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("err = %v, want 413 UploadStatusError", err)
+	}
+	if n := server.putsFrom(1024); n != 1 {
+		t.Errorf("413 chunk uploaded %d times", n)
+	}
+
+	// 可重试状态码在 MaxRetries 次后放弃
+	server.status[1024] = http.StatusServiceUnavailable
+	_, err = client.UploadFileResumable(context.Background(), "/upload", path, 1024, UploadOptions{MaxRetries: 2, RetryDelay: time.Millisecond})
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("err = %v, want 503 UploadStatusError", err)
+	}
+	if n := server.putsFrom(1024); n != 1+3 {
+		t.Errorf("503 chunk uploaded %d times in total, want 4", n)
+	}
+
+	// 负数 MaxRetries 不重试
+	_, err = client.UploadFileResumable(context.Background(), "/upload", path, 1024, UploadOptions{MaxRetries: -1})
+	if err == nil || server.putsFrom(1024) != 5 {
+		t.Errorf("err = %v, chunk uploads = %d", err, server.putsFrom(1024))
+	}
+
+	// 重试等待期间取消
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	_, err = client.UploadFileResumable(ctx, "/upload", path, 1024, UploadOptions{RetryDelay: time.Hour})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled err = %v", err)
+	}
+
+	if _, err := client.UploadFileResumable(context.Background(), "/upload", path, 0); err == nil {
+		t.Error("zero chunk size should be rejected")
+	}
+	if _, err := client.UploadFileResumable(context.Background(), "/upload", filepath.Join(t.TempDir(), "missing"), 1024); err == nil {
+		t.Error("missing file should be rejected")
+	}
+}
+
+func TestUploadFileResumableEmptyFile(t *testing.T) {
+	server := newUploadServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	path, _ := writeUploadFile(t, 0)
+
+	if _, err := Http().BaseURL(ts.URL).UploadFileResumable(context.Background(), "/upload", path, 1024); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.puts) != 0 || len(server.completed) != 1 || !bytes.Equal([]byte(server.completed[0]), nil) {
+		t.Errorf("puts = %v, completed = %d", server.puts, len(server.completed))
+	}
+}
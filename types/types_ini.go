@@ -0,0 +1,189 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// INI 配置解析与生成
+//
+// 支持 [section] 分组、以 ; 或 # 开头的注释行、值后以空白分隔的行内注释、
+// 双引号（支持 \" \\ \n \t 转义）与单引号（原样）包裹的值。
+// 第一个分组之前的键归入名为 "" 的分组。
+
+// ParseINI 解析 INI 格式文本，返回 分组 -> 键 -> 值
+// 重复的键默认取最后一个值，strict 为 true 时返回错误
+func (s XStr) ParseINI(strict ...bool) (XMap[string, XMap[string, string]], error) {
+	isStrict := len(strict) > 0 && strict[0]
+	result := NewMap[string, XMap[string, string]]()
+	section := ""
+
+	for i, raw := range strings.Split(strings.ReplaceAll(string(s), "\r\n", "\n"), "\n") {
+		lineNo := i + 1
+		line := strings.TrimSpace(raw)
+		if i == 0 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			end := strings.IndexByte(line, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("ini line %d: unterminated section header %q", lineNo, line)
+			}
+			if rest := strings.TrimSpace(line[end+1:]); rest != "" && rest[0] != ';' && rest[0] != '#' {
+				return nil, fmt.Errorf("ini line %d: unexpected text after section header: %q", lineNo, rest)
+			}
+			section = strings.TrimSpace(line[1:end])
+			if section == "" {
+				return nil, fmt.Errorf("ini line %d: empty section name", lineNo)
+			}
+			if _, exists := result[section]; !exists {
+				result[section] = NewMap[string, string]()
+			}
+			continue
+		}
+
+		eq := strings.IndexByte(line, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("ini line %d: expected key = value, got %q", lineNo, line)
+		}
+		key := strings.TrimSpace(line[:eq])
+		if key == "" {
+			return nil, fmt.Errorf("ini line %d: empty key", lineNo)
+		}
+		value, err := parseINIValue(strings.TrimSpace(line[eq+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("ini line %d: %v", lineNo, err)
+		}
+
+		values, exists := result[section]
+		if !exists {
+			values = NewMap[string, string]()
+			result[section] = values
+		}
+		if isStrict && values.Has(key) {
+			return nil, fmt.Errorf("ini line %d: duplicate key %q in section %q", lineNo, key, section)
+		}
+		values[key] = value
+	}
+
+	return result, nil
+}
+
+// parseINIValue 解析值部分，处理引号与行内注释
+func parseINIValue(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+
+	var value, rest string
+	switch raw[0] {
+	case '"':
+		var b strings.Builder
+		closed := false
+		i := 1
+		for ; i < len(raw); i++ {
+			c := raw[i]
+			if c == '"' {
+				closed = true
+				break
+			}
+			if c == '\\' && i+1 < len(raw) {
+				i++
+				switch raw[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 't':
+					b.WriteByte('\t')
+				default:
+					b.WriteByte(raw[i])
+				}
+				continue
+			}
+			b.WriteByte(c)
+		}
+		if !closed {
+			return "", fmt.Errorf("unterminated quoted value %q", raw)
+		}
+		value, rest = b.String(), raw[i+1:]
+	case '\'':
+		end := strings.IndexByte(raw[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated quoted value %q", raw)
+		}
+		value, rest = raw[1:end+1], raw[end+2:]
+	default:
+		// 行内注释需以空白与值分隔，避免截断 URL 中的 #
+		for i := 1; i < len(raw); i++ {
+			if (raw[i] == ';' || raw[i] == '#') && (raw[i-1] == ' ' || raw[i-1] == '\t') {
+				return strings.TrimSpace(raw[:i]), nil
+			}
+		}
+		return raw, nil
+	}
+
+	rest = strings.TrimSpace(rest)
+	if rest != "" && rest[0] != ';' && rest[0] != '#' {
+		return "", fmt.Errorf("unexpected text after quoted value: %q", rest)
+	}
+	return value, nil
+}
+
+// WriteINI 生成 INI 文本，分组与键按字典序输出，"" 分组的键写在最前且不带分组头
+// 含有首尾空白、引号、注释符或换行的值自动加双引号
+func WriteINI(data XMap[string, XMap[string, string]]) XStr {
+	var b strings.Builder
+
+	sections := data.Keys()
+	sort.Strings(sections)
+	for _, section := range sections {
+		values := data[section]
+		if section == "" && values.Len() == 0 {
+			continue
+		}
+		if section != "" {
+			if b.Len() > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString("[" + section + "]\n")
+		}
+
+		keys := values.Keys()
+		sort.Strings(keys)
+		for _, key := range keys {
+			b.WriteString(key + " = " + formatINIValue(values[key]) + "\n")
+		}
+	}
+
+	return XStr(b.String())
+}
+
+// formatINIValue 按需为值加引号
+func formatINIValue(value string) string {
+	if value == "" {
+		return ""
+	}
+	if strings.TrimSpace(value) == value && !strings.ContainsAny(value, "\"';#\\\n\t") {
+		return value
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// ReadINI 读取并解析 INI 文件
+func (f XFile) ReadINI(strict ...bool) (XMap[string, XMap[string, string]], error) {
+	content, err := f.ReadString()
+	if err != nil {
+		return nil, err
+	}
+	data, err := Str(content).ParseINI(strict...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.path, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,85 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseINIFixture(t *testing.T) {
+	data, err := File("testdata/full.ini").ReadINI()
+	if err != nil {
+		t.Fatalf("ReadINI failed: %v", err)
+	}
+
+	expected := map[string]map[string]string{
+		"": {"app_name": "demo", "debug": "true"},
+		"database": {
+			"host":     "127.0.0.1",
+			"port":     "5432",
+			"password": "p;ss#word",
+			"dsn":      "postgres://u@h/db?sslmode=disable",
+			"empty":    "",
+			"url":      "http://example.com/#anchor",
+		},
+		"server": {
+			"greeting": "hello \"world\"\nbye",
+			"padded":   "  spaced  ",
+			"timeout":  "30",
+		},
+		"empty section": {},
+	}
+
+	if data.Len() != len(expected) {
+		t.Fatalf("got %d sections, want %d: %v", data.Len(), len(expected), data)
+	}
+	for section, values := range expected {
+		got, ok := data.Get(section)
+		if !ok || !got.Equal(Map(values)) {
+			t.Errorf("section %q = %v, want %v", section, got, values)
+		}
+	}
+
+	// 往返：生成后再解析应得到相同结果，且输出稳定
+	written := WriteINI(data)
+	reparsed, err := written.ParseINI(true)
+	if err != nil {
+		t.Fatalf("parse written INI failed: %v\n%s", err, written)
+	}
+	for section, values := range data {
+		if !reparsed[section].Equal(values) {
+			t.Errorf("round trip changed section %q: %v", section, reparsed[section])
+		}
+	}
+	if WriteINI(reparsed) != written {
+		t.Error("WriteINI output is not stable")
+	}
+	if !strings.HasPrefix(written.String(), "app_name = demo\ndebug = true\n\n[database]\n") {
+		t.Errorf("unexpected output:\n%s", written)
+	}
+}
+
+func TestParseINIErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		strict   bool
+		expected string
+	}{
+		{"a = 1\n[broken\nb = 2", false, "ini line 2: unterminated section header"},
+		{"[s]\njust text", false, "ini line 2: expected key = value"},
+		{"\n\n = value", false, "ini line 3: empty key"},
+		{"k = \"open", false, "ini line 1: unterminated quoted value"},
+		{"k = \"v\" extra", false, "ini line 1: unexpected text after quoted value"},
+		{"[s]\nk = 1\nk = 2", true, `ini line 3: duplicate key "k" in section "s"`},
+	}
+
+	for _, test := range tests {
+		_, err := Str(test.input).ParseINI(test.strict)
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("ParseINI(%q) error = %v, want %q", test.input, err, test.expected)
+		}
+	}
+
+	if _, err := Str("[s]\nk = 1\nk = 2").ParseINI(); err != nil {
+		t.Errorf("duplicate keys should be allowed when not strict: %v", err)
+	}
+}
@@ -121,7 +121,7 @@ func (j XJSON) GetWithSeparator(path, separator string) XJSON {
 		case map[string]interface{}:
 			current = v[key]
 		case []interface{}:
-			if index, err := strconv.Atoi(key); err == nil && index >= 0 && index < len(v) {
+			if index, ok := resolveJSONIndex(key, len(v)); ok {
 				current = v[index]
 			} else {
 				return XJSON{data: nil}
@@ -228,7 +228,7 @@ func (j XJSON) DeleteWithSeparator(path, separator string) XJSON {
 		case map[string]interface{}:
 			current = v[key]
 		case []interface{}:
-			if index, err := strconv.Atoi(key); err == nil && index >= 0 && index < len(v) {
+			if index, ok := resolveJSONIndex(key, len(v)); ok {
 				current = v[index]
 			} else {
 				return j
@@ -244,7 +244,7 @@ func (j XJSON) DeleteWithSeparator(path, separator string) XJSON {
 	case map[string]interface{}:
 		delete(v, lastKey)
 	case []interface{}:
-		if index, err := strconv.Atoi(lastKey); err == nil && index >= 0 && index < len(v) {
+		if index, ok := resolveJSONIndex(lastKey, len(v)); ok {
 			// 从数组中删除元素
 			copy(v[index:], v[index+1:])
 			v = v[:len(v)-1]
@@ -263,9 +263,62 @@ func (j XJSON) DeleteWithSeparator(path, separator string) XJSON {
 	return j
 }
 
-// Has 判断是否存在指定路径
+// Has 是 Exists 的别名，为兼容已有调用保留：值为 null 的键也视为存在，与 jsonx.JSON.Has 一致
+// 新代码建议使用意图更明确的 Exists（键存在即可）或 HasValue（存在且不为 null）
 func (j XJSON) Has(path string) bool {
-	return !j.Get(path).IsNull()
+	return j.Exists(path)
+}
+
+// Exists 判断路径是否存在（键存在即可，值可以为 null），如 {"a": null} 中 Exists("a") 为 true
+func (j XJSON) Exists(path string) bool {
+	_, ok := j.lookup(path, ".")
+	return ok
+}
+
+// HasValue 判断路径是否存在且值不为 null，如 {"a": null} 中 HasValue("a") 为 false
+func (j XJSON) HasValue(path string) bool {
+	value, ok := j.lookup(path, ".")
+	return ok && value != nil
+}
+
+// lookup 按路径查找值，返回路径是否存在
+func (j XJSON) lookup(path, separator string) (interface{}, bool) {
+	if path == "" {
+		return j.data, true
+	}
+
+	current := j.data
+	for _, key := range strings.Split(path, separator) {
+		switch v := current.(type) {
+		case map[string]interface{}:
+			value, ok := v[key]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []interface{}:
+			index, ok := resolveJSONIndex(key, len(v))
+			if !ok {
+				return nil, false
+			}
+			current = v[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// resolveJSONIndex 解析数组下标，负数表示从末尾倒数（-1 为最后一个元素），与 jsonx 的路径规则一致
+func resolveJSONIndex(key string, length int) (int, bool) {
+	index, err := strconv.Atoi(key)
+	if err != nil {
+		return 0, false
+	}
+	if index < 0 {
+		index += length
+	}
+	return index, index >= 0 && index < length
 }
 
 // Keys 获取对象的所有键
@@ -0,0 +1,57 @@
+package types
+
+import "testing"
+
+func TestXJSONExistsAndHasValue(t *testing.T) {
+	j, err := ParseJSON(`{"a": null, "b": {"c": null, "d": 0}, "list": [null, 1]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path             string
+		exists, hasValue bool
+	}{
+		{"a", true, false},
+		{"b", true, true},
+		{"b.c", true, false},
+		{"b.d", true, true},
+		{"b.e", false, false},
+		{"a.x", false, false},
+		{"list.0", true, false},
+		{"list.1", true, true},
+		{"list.2", false, false},
+		{"list.-1", true, true},
+		{"list.-2", true, false},
+		{"list.-3", false, false},
+		{"missing", false, false},
+	}
+	for _, tt := range tests {
+		if got := j.Exists(tt.path); got != tt.exists {
+			t.Errorf("Exists(%q) = %v, want %v", tt.path, got, tt.exists)
+		}
+		if got := j.Has(tt.path); got != tt.exists {
+			t.Errorf("Has(%q) = %v, want %v", tt.path, got, tt.exists)
+		}
+		if got := j.HasValue(tt.path); got != tt.hasValue {
+			t.Errorf("HasValue(%q) = %v, want %v", tt.path, got, tt.hasValue)
+		}
+	}
+}
+
+func TestXJSONNegativeIndex(t *testing.T) {
+	j, err := ParseJSON(`{"list": [1, 2, {"k": "v"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := j.Get("list.-1.k").data; got != "v" {
+		t.Errorf("Get(list.-1.k) = %v", got)
+	}
+	if got := j.Get("list.-4").data; got != nil {
+		t.Errorf("out of range negative index = %v", got)
+	}
+	j = j.Delete("list.-1")
+	if got := j.Get("list").data.([]interface{}); len(got) != 2 {
+		t.Errorf("Delete(list.-1) = %v", got)
+	}
+}
@@ -0,0 +1,272 @@
+package types
+
+import (
+	"bytes"
+	"container/list"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// XOrderedMap 按插入顺序保存键值对的 Map
+//
+// 更新已有的键不改变其位置，删除后重新插入的键排在最后。
+// JSON 编码按当前顺序输出字段，解码按字段在文档中出现的顺序插入。
+// 与 XMap 一样不是并发安全的。
+type XOrderedMap[K comparable, V any] struct {
+	entries map[K]*list.Element
+	order   *list.List // 元素为 *orderedEntry[K, V]
+}
+
+type orderedEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// NewOrderedMap 创建空的有序 Map
+func NewOrderedMap[K comparable, V any]() *XOrderedMap[K, V] {
+	return &XOrderedMap[K, V]{
+		entries: make(map[K]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// OrderedMapFromXMap 从 XMap 创建有序 Map，可排序的键按升序插入，其他键类型的顺序不确定
+func OrderedMapFromXMap[K comparable, V any](m XMap[K, V]) *XOrderedMap[K, V] {
+	om := NewOrderedMap[K, V]()
+	for _, k := range m.SortedKeys() {
+		om.Set(k, m[k])
+	}
+	return om
+}
+
+// init 延迟初始化，使零值 XOrderedMap 可直接使用
+func (m *XOrderedMap[K, V]) init() {
+	if m.entries == nil {
+		m.entries = make(map[K]*list.Element)
+		m.order = list.New()
+	}
+}
+
+// Len 返回键值对数量
+func (m *XOrderedMap[K, V]) Len() int {
+	return len(m.entries)
+}
+
+// Set 设置键值对，键已存在时只更新值，位置不变
+func (m *XOrderedMap[K, V]) Set(key K, value V) *XOrderedMap[K, V] {
+	m.init()
+	if e, ok := m.entries[key]; ok {
+		e.Value.(*orderedEntry[K, V]).value = value
+		return m
+	}
+	m.entries[key] = m.order.PushBack(&orderedEntry[K, V]{key: key, value: value})
+	return m
+}
+
+// Get 获取值，如果不存在返回零值和 false
+func (m *XOrderedMap[K, V]) Get(key K) (V, bool) {
+	if e, ok := m.entries[key]; ok {
+		return e.Value.(*orderedEntry[K, V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Has 判断是否包含指定键
+func (m *XOrderedMap[K, V]) Has(key K) bool {
+	_, ok := m.entries[key]
+	return ok
+}
+
+// Delete 删除指定键
+func (m *XOrderedMap[K, V]) Delete(key K) *XOrderedMap[K, V] {
+	if e, ok := m.entries[key]; ok {
+		m.order.Remove(e)
+		delete(m.entries, key)
+	}
+	return m
+}
+
+// Keys 按插入顺序返回所有键
+func (m *XOrderedMap[K, V]) Keys() []K {
+	keys := make([]K, 0, m.Len())
+	m.ForEach(func(k K, _ V) {
+		keys = append(keys, k)
+	})
+	return keys
+}
+
+// Values 按插入顺序返回所有值
+func (m *XOrderedMap[K, V]) Values() []V {
+	values := make([]V, 0, m.Len())
+	m.ForEach(func(_ K, v V) {
+		values = append(values, v)
+	})
+	return values
+}
+
+// ForEach 按插入顺序遍历
+func (m *XOrderedMap[K, V]) ForEach(fn func(K, V)) {
+	if m.order == nil {
+		return
+	}
+	for e := m.order.Front(); e != nil; e = e.Next() {
+		entry := e.Value.(*orderedEntry[K, V])
+		fn(entry.key, entry.value)
+	}
+}
+
+// MoveToFront 将键移动到最前，键不存在时返回 false
+func (m *XOrderedMap[K, V]) MoveToFront(key K) bool {
+	e, ok := m.entries[key]
+	if ok {
+		m.order.MoveToFront(e)
+	}
+	return ok
+}
+
+// MoveToBack 将键移动到最后，键不存在时返回 false
+func (m *XOrderedMap[K, V]) MoveToBack(key K) bool {
+	e, ok := m.entries[key]
+	if ok {
+		m.order.MoveToBack(e)
+	}
+	return ok
+}
+
+// ToXMap 转换为 XMap（丢失顺序）
+func (m *XOrderedMap[K, V]) ToXMap() XMap[K, V] {
+	result := make(XMap[K, V], m.Len())
+	m.ForEach(func(k K, v V) {
+		result[k] = v
+	})
+	return result
+}
+
+// MarshalJSON 按插入顺序编码为 JSON 对象
+// 键的编码规则与 encoding/json 相同：字符串、整数或实现 encoding.TextMarshaler 的类型
+// 使用值接收者，作为结构体字段（非指针）时同样生效
+func (m XOrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	first := true
+	var err error
+	m.ForEach(func(k K, v V) {
+		if err != nil {
+			return
+		}
+		var key string
+		if key, err = encodeOrderedKey(k); err != nil {
+			return
+		}
+		var keyJSON, valueJSON []byte
+		if keyJSON, err = json.Marshal(key); err != nil {
+			return
+		}
+		if valueJSON, err = json.Marshal(v); err != nil {
+			err = fmt.Errorf("marshal value of key %q: %w", key, err)
+			return
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		buf.Write(keyJSON)
+		buf.WriteByte(':')
+		buf.Write(valueJSON)
+	})
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON 解码 JSON 对象，按字段出现的顺序插入，原有内容被清空
+// 重复的字段保留第一次出现的位置和最后一次出现的值；null 得到空 Map
+func (m *XOrderedMap[K, V]) UnmarshalJSON(data []byte) error {
+	m.entries = make(map[K]*list.Element)
+	m.order = list.New()
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("cannot unmarshal %v into XOrderedMap: expected object", tok)
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, err := decodeOrderedKey[K](tok.(string))
+		if err != nil {
+			return err
+		}
+		var value V
+		if err := dec.Decode(&value); err != nil {
+			return fmt.Errorf("unmarshal value of key %q: %w", tok, err)
+		}
+		m.Set(key, value)
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// encodeOrderedKey 将键转换为 JSON 字段名
+func encodeOrderedKey(key any) (string, error) {
+	if tm, ok := key.(encoding.TextMarshaler); ok {
+		text, err := tm.MarshalText()
+		return string(text), err
+	}
+	rv := reflect.ValueOf(key)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("unsupported map key type %T", key)
+}
+
+// decodeOrderedKey 将 JSON 字段名转换为键
+func decodeOrderedKey[K comparable](s string) (K, error) {
+	var key K
+	if tu, ok := any(&key).(encoding.TextUnmarshaler); ok {
+		err := tu.UnmarshalText([]byte(s))
+		return key, err
+	}
+	rv := reflect.ValueOf(&key).Elem()
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, rv.Type().Bits())
+		if err != nil {
+			return key, fmt.Errorf("invalid map key %q: %w", s, err)
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, rv.Type().Bits())
+		if err != nil {
+			return key, fmt.Errorf("invalid map key %q: %w", s, err)
+		}
+		rv.SetUint(n)
+	default:
+		return key, fmt.Errorf("unsupported map key type %T", key)
+	}
+	return key, nil
+}
@@ -0,0 +1,140 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOrderedMapBasics(t *testing.T) {
+	m := NewOrderedMap[string, int]()
+	m.Set("c", 3).Set("a", 1).Set("b", 2)
+
+	if got := m.Keys(); !reflect.DeepEqual(got, []string{"c", "a", "b"}) {
+		t.Fatalf("Keys = %v", got)
+	}
+	if got := m.Values(); !reflect.DeepEqual(got, []int{3, 1, 2}) {
+		t.Fatalf("Values = %v", got)
+	}
+
+	// 更新不改变位置
+	m.Set("c", 30)
+	if v, ok := m.Get("c"); !ok || v != 30 || m.Keys()[0] != "c" {
+		t.Fatalf("update should keep position, got %v %v", m.Keys(), v)
+	}
+
+	// 删除后重新插入排在最后
+	m.Delete("c").Set("c", 300)
+	if got := m.Keys(); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Fatalf("reinsert order = %v", got)
+	}
+	if m.Len() != 3 || !m.Has("a") || m.Has("z") {
+		t.Fatal("Len/Has mismatch")
+	}
+
+	if !m.MoveToFront("c") || !m.MoveToBack("a") || m.MoveToFront("z") {
+		t.Fatal("Move should report key existence")
+	}
+	if got := m.Keys(); !reflect.DeepEqual(got, []string{"c", "b", "a"}) {
+		t.Fatalf("after moves = %v", got)
+	}
+
+	var visited []string
+	m.ForEach(func(k string, v int) {
+		visited = append(visited, fmt.Sprintf("%s=%d", k, v))
+	})
+	if strings.Join(visited, ",") != "c=300,b=2,a=1" {
+		t.Fatalf("ForEach = %v", visited)
+	}
+
+	var zero XOrderedMap[string, int]
+	zero.Set("x", 1)
+	if zero.Len() != 1 {
+		t.Fatal("zero value should be usable")
+	}
+}
+
+func TestOrderedMapJSONOrderStable(t *testing.T) {
+	m := NewOrderedMap[string, int]()
+	var want strings.Builder
+	want.WriteByte('{')
+	for i := 0; i < 200; i++ {
+		key := fmt.Sprintf("k%03d", (i*37)%200)
+		m.Set(key, i)
+		if i > 0 {
+			want.WriteByte(',')
+		}
+		fmt.Fprintf(&want, "%q:%d", key, i)
+	}
+	want.WriteByte('}')
+
+	for round := 0; round < 5; round++ {
+		data, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != want.String() {
+			t.Fatalf("round %d: marshal order changed:\n%s", round, data)
+		}
+	}
+
+	// 解码保留文档中的顺序，再次编码得到相同结果
+	decoded := NewOrderedMap[string, int]()
+	if err := json.Unmarshal([]byte(want.String()), decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded.Keys(), m.Keys()) {
+		t.Fatal("unmarshal should record encounter order")
+	}
+	again, _ := json.Marshal(decoded)
+	if string(again) != want.String() {
+		t.Fatal("round trip changed output")
+	}
+}
+
+func TestOrderedMapJSONValues(t *testing.T) {
+	type config struct {
+		Servers XOrderedMap[string, []string] `json:"servers"`
+	}
+	var c config
+	input := `{"servers": {"zeta": ["a"], "alpha": [], "mid": ["b", "c"]}}`
+	if err := json.Unmarshal([]byte(input), &c); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Servers.Keys(); !reflect.DeepEqual(got, []string{"zeta", "alpha", "mid"}) {
+		t.Fatalf("Keys = %v", got)
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"servers":{"zeta":["a"],"alpha":[],"mid":["b","c"]}}` {
+		t.Fatalf("marshal = %s", data)
+	}
+
+	ints := NewOrderedMap[int, string]()
+	if err := json.Unmarshal([]byte(`{"10": "x", "2": "y"}`), ints); err != nil {
+		t.Fatal(err)
+	}
+	if got := ints.Keys(); !reflect.DeepEqual(got, []int{10, 2}) {
+		t.Fatalf("int keys = %v", got)
+	}
+	if err := json.Unmarshal([]byte(`{"abc": "x"}`), ints); err == nil {
+		t.Error("non-numeric key should fail for int keys")
+	}
+	if err := json.Unmarshal([]byte(`[1, 2]`), ints); err == nil {
+		t.Error("array should be rejected")
+	}
+}
+
+func TestOrderedMapXMapConversion(t *testing.T) {
+	m := OrderedMapFromXMap(XMap[string, int]{"b": 2, "a": 1, "c": 3})
+	if got := m.Keys(); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Fatalf("sorted keys expected, got %v", got)
+	}
+	if x := m.ToXMap(); !x.Equal(XMap[string, int]{"a": 1, "b": 2, "c": 3}) {
+		t.Fatalf("ToXMap = %v", x)
+	}
+}
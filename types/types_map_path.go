@@ -0,0 +1,206 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 点路径投影，语义与 jsonx.Pick / jsonx.Omit 保持一致：
+//   - 路径以 "." 分隔，数字片段作为切片索引，如 "servers.0.password"
+//   - Pick 只保留存在的路径，构造恰好包含这些叶子的最小嵌套结构，切片保留原索引位置，空位为 nil
+//   - Omit / Mask 只作用于存在的路径，切片元素本身不会被删除
+//   - 结果为深拷贝，修改结果不影响原 Map
+//
+// 嵌套值支持 map[string]any、XMap[string, any] 与 []any。
+// 仅 XMap[string, any] 支持嵌套路径，其他类型的 Map 按 fmt.Sprint(key) 匹配顶层键。
+
+// Pick 按点路径选择字段
+func (m XMap[K, V]) Pick(paths ...string) XMap[K, V] {
+	if src, ok := any(m).(XMap[string, any]); ok {
+		var result interface{} = make(map[string]interface{})
+		for _, path := range paths {
+			parts := strings.Split(path, ".")
+			if value, exists := mapPathGet(map[string]interface{}(src), parts); exists {
+				result, _ = mapPathSet(result, parts, mapPathCopy(value))
+			}
+		}
+		return any(XMap[string, any](result.(map[string]interface{}))).(XMap[K, V])
+	}
+
+	result := NewMap[K, V]()
+	for k, v := range m {
+		if containsPath(paths, fmt.Sprint(k)) {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// Omit 按点路径排除字段
+func (m XMap[K, V]) Omit(paths ...string) XMap[K, V] {
+	if src, ok := any(m).(XMap[string, any]); ok {
+		result := mapPathCopy(map[string]interface{}(src)).(map[string]interface{})
+		for _, path := range paths {
+			mapPathDelete(result, strings.Split(path, "."))
+		}
+		return any(XMap[string, any](result)).(XMap[K, V])
+	}
+
+	return m.Filter(func(k K, _ V) bool {
+		return !containsPath(paths, fmt.Sprint(k))
+	})
+}
+
+// Mask 将指定路径的值替换为 replacement，常用于打印配置时隐藏密钥
+func (m XMap[K, V]) Mask(paths []string, replacement any) XMap[K, V] {
+	if src, ok := any(m).(XMap[string, any]); ok {
+		var result interface{} = mapPathCopy(map[string]interface{}(src))
+		for _, path := range paths {
+			parts := strings.Split(path, ".")
+			if _, exists := mapPathGet(result, parts); exists {
+				result, _ = mapPathSet(result, parts, replacement)
+			}
+		}
+		return any(XMap[string, any](result.(map[string]interface{}))).(XMap[K, V])
+	}
+
+	result := m.Copy()
+	masked, ok := replacement.(V)
+	if !ok {
+		return result
+	}
+	for k := range result {
+		if containsPath(paths, fmt.Sprint(k)) {
+			result[k] = masked
+		}
+	}
+	return result
+}
+
+// containsPath 判断路径列表是否包含指定路径
+func containsPath(paths []string, path string) bool {
+	for _, p := range paths {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
+
+// mapPathNode 将嵌套对象统一为 map[string]interface{}
+func mapPathNode(v interface{}) (map[string]interface{}, bool) {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		return node, true
+	case XMap[string, any]:
+		return map[string]interface{}(node), true
+	}
+	return nil, false
+}
+
+// mapPathGet 按路径获取值
+func mapPathGet(data interface{}, parts []string) (interface{}, bool) {
+	current := data
+	for _, part := range parts {
+		if obj, ok := mapPathNode(current); ok {
+			value, exists := obj[part]
+			if !exists {
+				return nil, false
+			}
+			current = value
+			continue
+		}
+
+		arr, ok := current.([]interface{})
+		if !ok {
+			return nil, false
+		}
+		idx, err := strconv.Atoi(part)
+		if err != nil || idx < 0 || idx >= len(arr) {
+			return nil, false
+		}
+		current = arr[idx]
+	}
+	return current, true
+}
+
+// mapPathSet 按路径设置值，自动创建中间容器，返回更新后的容器
+func mapPathSet(current interface{}, parts []string, value interface{}) (interface{}, error) {
+	if len(parts) == 0 {
+		return value, nil
+	}
+
+	part := parts[0]
+	if current == nil {
+		if _, err := strconv.Atoi(part); err == nil {
+			current = make([]interface{}, 0)
+		} else {
+			current = make(map[string]interface{})
+		}
+	}
+
+	if obj, ok := mapPathNode(current); ok {
+		child, err := mapPathSet(obj[part], parts[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		obj[part] = child
+		return current, nil
+	}
+
+	arr, ok := current.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cannot set '%s' on %T", part, current)
+	}
+	idx, err := strconv.Atoi(part)
+	if err != nil || idx < 0 {
+		return nil, fmt.Errorf("invalid slice index: %s", part)
+	}
+	for len(arr) <= idx {
+		arr = append(arr, nil)
+	}
+	child, err := mapPathSet(arr[idx], parts[1:], value)
+	if err != nil {
+		return nil, err
+	}
+	arr[idx] = child
+	return arr, nil
+}
+
+// mapPathDelete 按路径删除对象字段
+func mapPathDelete(data interface{}, parts []string) {
+	parent, exists := mapPathGet(data, parts[:len(parts)-1])
+	if !exists {
+		return
+	}
+	if obj, ok := mapPathNode(parent); ok {
+		delete(obj, parts[len(parts)-1])
+	}
+}
+
+// mapPathCopy 深拷贝嵌套的 map 与切片
+func mapPathCopy(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			result[k] = mapPathCopy(item)
+		}
+		return result
+	case XMap[string, any]:
+		result := make(XMap[string, any], len(val))
+		for k, item := range val {
+			result[k] = mapPathCopy(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(val))
+		for i, item := range val {
+			result[i] = mapPathCopy(item)
+		}
+		return result
+	default:
+		return v
+	}
+}
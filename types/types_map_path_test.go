@@ -0,0 +1,111 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// projectionCase 与 jsonx 共享的投影测试用例，见 testdata/projection_cases.json
+// jsonx 是独立的模块，用例在 jsonx/testdata 中另有一份，TestProjectionCasesInSync 检查两份内容一致
+type projectionCase struct {
+	Name     string                 `json:"name"`
+	Input    map[string]interface{} `json:"input"`
+	Pick     []string               `json:"pick"`
+	Omit     []string               `json:"omit"`
+	Expected map[string]interface{} `json:"expected"`
+}
+
+func loadProjectionCases(t *testing.T) []projectionCase {
+	t.Helper()
+	data, err := os.ReadFile("testdata/projection_cases.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cases []projectionCase
+	if err := json.Unmarshal(data, &cases); err != nil {
+		t.Fatal(err)
+	}
+	return cases
+}
+
+func TestProjectionCasesInSync(t *testing.T) {
+	ours, err := os.ReadFile("testdata/projection_cases.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 只在完整的仓库中检查，模块缓存中没有 jsonx 目录
+	theirs, err := os.ReadFile("../jsonx/testdata/projection_cases.json")
+	if os.IsNotExist(err) {
+		t.Skip("jsonx module not available")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ours, theirs) {
+		t.Error("types/testdata/projection_cases.json and jsonx/testdata/projection_cases.json differ; keep the shared cases identical")
+	}
+}
+
+func TestXMapPickOmitSharedCases(t *testing.T) {
+	for _, c := range loadProjectionCases(t) {
+		m := Map[string, any](c.Input)
+		before, _ := json.Marshal(c.Input)
+
+		result := m
+		if c.Pick != nil {
+			result = result.Pick(c.Pick...)
+		}
+		if c.Omit != nil {
+			result = result.Omit(c.Omit...)
+		}
+
+		if !reflect.DeepEqual(map[string]interface{}(result), c.Expected) {
+			t.Errorf("%s: got %v, want %v", c.Name, result, c.Expected)
+		}
+		if after, _ := json.Marshal(c.Input); string(after) != string(before) {
+			t.Errorf("%s: input was modified", c.Name)
+		}
+	}
+}
+
+func TestXMapMask(t *testing.T) {
+	config := Map[string, any](map[string]interface{}{
+		"db": map[string]interface{}{"user": "root", "password": "secret"},
+		"servers": []interface{}{
+			map[string]interface{}{"host": "a", "token": "t1"},
+		},
+		"name": "app",
+	})
+
+	masked := config.Mask([]string{"db.password", "servers.0.token", "missing.key"}, "***")
+
+	if v, _ := mapPathGet(map[string]interface{}(masked), []string{"db", "password"}); v != "***" {
+		t.Errorf("db.password = %v, want ***", v)
+	}
+	if v, _ := mapPathGet(map[string]interface{}(masked), []string{"servers", "0", "token"}); v != "***" {
+		t.Errorf("servers.0.token = %v, want ***", v)
+	}
+	if masked.Has("missing") {
+		t.Error("Mask should not create missing paths")
+	}
+	if v, _ := mapPathGet(map[string]interface{}(config), []string{"db", "password"}); v != "secret" {
+		t.Error("Mask should not modify the original map")
+	}
+}
+
+func TestXMapPickTopLevelForTypedMaps(t *testing.T) {
+	m := Map(map[string]int{"a": 1, "b": 2, "c": 3})
+
+	if got := m.Pick("a", "c", "a.x"); !got.Equal(Map(map[string]int{"a": 1, "c": 3})) {
+		t.Errorf("Pick = %v", got)
+	}
+	if got := m.Omit("a"); !got.Equal(Map(map[string]int{"b": 2, "c": 3})) {
+		t.Errorf("Omit = %v", got)
+	}
+	if got := m.Mask([]string{"b"}, 0); got["b"] != 0 || m["b"] != 2 {
+		t.Errorf("Mask = %v", got)
+	}
+}
@@ -0,0 +1,207 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Optional 可能不存在的值，用于替代“零值 + bool”的返回方式
+//
+//	port := types.Str(os.Getenv("PORT")).IntOpt().OrElse(8080)
+//
+// Optional 是值类型，零值表示不存在
+type Optional[T any] struct {
+	value   T
+	present bool
+}
+
+// Some 创建存在值的 Optional
+func Some[T any](value T) Optional[T] {
+	return Optional[T]{value: value, present: true}
+}
+
+// None 创建不存在值的 Optional
+func None[T any]() Optional[T] {
+	return Optional[T]{}
+}
+
+// OptionalOf 由“值 + bool”创建 Optional
+func OptionalOf[T any](value T, ok bool) Optional[T] {
+	if !ok {
+		return Optional[T]{}
+	}
+	return Some(value)
+}
+
+// Get 返回值以及是否存在
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.present
+}
+
+// IsPresent 判断值是否存在
+func (o Optional[T]) IsPresent() bool {
+	return o.present
+}
+
+// OrElse 值存在时返回值，否则返回默认值
+func (o Optional[T]) OrElse(defaultValue T) T {
+	if o.present {
+		return o.value
+	}
+	return defaultValue
+}
+
+// OrElseGet 值存在时返回值，否则返回 fn 的结果，fn 只在值不存在时调用
+func (o Optional[T]) OrElseGet(fn func() T) T {
+	if o.present {
+		return o.value
+	}
+	return fn()
+}
+
+// Map 值存在时对其应用 fn，不存在时保持不存在
+// 需要转换为其他类型时使用 MapOptional
+func (o Optional[T]) Map(fn func(T) T) Optional[T] {
+	if !o.present {
+		return o
+	}
+	return Some(fn(o.value))
+}
+
+// Filter 值存在且满足条件时保留，否则返回不存在
+func (o Optional[T]) Filter(predicate func(T) bool) Optional[T] {
+	if o.present && predicate(o.value) {
+		return o
+	}
+	return Optional[T]{}
+}
+
+// String 实现 fmt.Stringer
+func (o Optional[T]) String() string {
+	if !o.present {
+		return "None"
+	}
+	return fmt.Sprintf("Some(%v)", o.value)
+}
+
+// MapOptional 将 Optional[T] 转换为 Optional[U]，值不存在时不调用 fn
+func MapOptional[T, U any](o Optional[T], fn func(T) U) Optional[U] {
+	if !o.present {
+		return Optional[U]{}
+	}
+	return Some(fn(o.value))
+}
+
+// Result 值或错误，用于在链式调用中携带错误
+//
+//	n := types.ResultOf(strconv.Atoi(s)).MapErr(wrap).OrElse(0)
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+// Ok 创建成功的 Result
+func Ok[T any](value T) Result[T] {
+	return Result[T]{value: value}
+}
+
+// Err 创建失败的 Result
+func Err[T any](err error) Result[T] {
+	return Result[T]{err: err}
+}
+
+// ResultOf 由“值 + error”创建 Result，可以直接包装函数调用
+func ResultOf[T any](value T, err error) Result[T] {
+	if err != nil {
+		return Result[T]{err: err}
+	}
+	return Result[T]{value: value}
+}
+
+// Get 返回值与错误
+func (r Result[T]) Get() (T, error) {
+	return r.value, r.err
+}
+
+// IsOk 判断是否成功
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+// Err 返回错误，成功时为 nil
+func (r Result[T]) Err() error {
+	return r.err
+}
+
+// Unwrap 返回值，失败时 panic
+func (r Result[T]) Unwrap() T {
+	if r.err != nil {
+		panic(fmt.Sprintf("types: Unwrap called on failed Result: %v", r.err))
+	}
+	return r.value
+}
+
+// OrElse 成功时返回值，失败时返回默认值
+func (r Result[T]) OrElse(defaultValue T) T {
+	if r.err != nil {
+		return defaultValue
+	}
+	return r.value
+}
+
+// Map 成功时对值应用 fn，失败时保持原错误
+// 需要转换为其他类型时使用 MapResult
+func (r Result[T]) Map(fn func(T) T) Result[T] {
+	if r.err != nil {
+		return r
+	}
+	return Ok(fn(r.value))
+}
+
+// MapErr 失败时对错误应用 fn，常用于补充上下文，成功时保持不变
+func (r Result[T]) MapErr(fn func(error) error) Result[T] {
+	if r.err == nil {
+		return r
+	}
+	return Result[T]{err: fn(r.err)}
+}
+
+// Optional 转换为 Optional，失败时为不存在
+func (r Result[T]) Optional() Optional[T] {
+	if r.err != nil {
+		return Optional[T]{}
+	}
+	return Some(r.value)
+}
+
+// MapResult 将 Result[T] 转换为 Result[U]，失败时不调用 fn
+func MapResult[T, U any](r Result[T], fn func(T) U) Result[U] {
+	if r.err != nil {
+		return Result[U]{err: r.err}
+	}
+	return Ok(fn(r.value))
+}
+
+// IntOpt 转换为整数，无法解析时为不存在
+// 与 Int 不同，可以区分 "0" 与无效输入
+func (s XStr) IntOpt() Optional[int] {
+	i, err := strconv.Atoi(string(s))
+	if err != nil {
+		return Optional[int]{}
+	}
+	return Some(i)
+}
+
+// GetOpt 获取值，键不存在时为不存在
+func (m XMap[K, V]) GetOpt(key K) Optional[V] {
+	value, exists := m[key]
+	return OptionalOf(value, exists)
+}
+
+// FirstOpt 获取第一个元素，数组为空时为不存在
+func (a XArray[T]) FirstOpt() Optional[T] {
+	if len(a) == 0 {
+		return Optional[T]{}
+	}
+	return Some(a[0])
+}
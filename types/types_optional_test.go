@@ -0,0 +1,150 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestOptionalChaining(t *testing.T) {
+	double := func(n int) int { return n * 2 }
+
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"21", 42},
+		{"0", 0},
+		{"abc", -1},
+		{"", -1},
+	}
+	for _, tt := range tests {
+		if got := Str(tt.input).IntOpt().Map(double).OrElse(-1); got != tt.want {
+			t.Errorf("Str(%q).IntOpt().Map(double).OrElse(-1) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+
+	// Int 无法区分 "0" 与无效输入，IntOpt 可以
+	if Str("0").Int() != Str("x").Int() || !Str("0").IntOpt().IsPresent() || Str("x").IntOpt().IsPresent() {
+		t.Error("IntOpt should distinguish zero from invalid input")
+	}
+
+	label := MapOptional(Str("7").IntOpt().Filter(func(n int) bool { return n > 5 }), strconv.Itoa).OrElse("small")
+	if label != "7" {
+		t.Errorf("MapOptional = %q", label)
+	}
+	if label := MapOptional(Str("3").IntOpt().Filter(func(n int) bool { return n > 5 }), strconv.Itoa).OrElse("small"); label != "small" {
+		t.Errorf("filtered MapOptional = %q", label)
+	}
+
+	calls := 0
+	fallback := func() int { calls++; return 1 }
+	Some(5).OrElseGet(fallback)
+	if None[int]().OrElseGet(fallback) != 1 || calls != 1 {
+		t.Errorf("OrElseGet should call fallback only when absent, calls = %d", calls)
+	}
+
+	if s := fmt.Sprint(Some(3)); s != "Some(3)" {
+		t.Errorf("Some(3) = %s", s)
+	}
+	if s := fmt.Sprint(None[string]()); s != "None" {
+		t.Errorf("None = %s", s)
+	}
+	var zero Optional[int]
+	if _, ok := zero.Get(); ok {
+		t.Error("zero Optional should be absent")
+	}
+}
+
+func TestOptionalXTypes(t *testing.T) {
+	m := XMap[string, int]{"a": 1, "zero": 0}
+	if v, ok := m.GetOpt("zero").Get(); !ok || v != 0 {
+		t.Errorf("GetOpt(zero) = %d, %v", v, ok)
+	}
+	if m.GetOpt("missing").IsPresent() {
+		t.Error("GetOpt(missing) should be absent")
+	}
+	if got := m.GetOpt("a").Map(func(v int) int { return v + 10 }).OrElse(0); got != 11 {
+		t.Errorf("GetOpt(a).Map = %d", got)
+	}
+
+	if got := Arrays("x", "y").FirstOpt().OrElse("none"); got != "x" {
+		t.Errorf("FirstOpt = %q", got)
+	}
+	if got := Arrays[string]().FirstOpt().OrElse("none"); got != "none" {
+		t.Errorf("empty FirstOpt = %q", got)
+	}
+}
+
+func TestResult(t *testing.T) {
+	parse := func(s string) Result[int] { return ResultOf(strconv.Atoi(s)) }
+
+	if got := parse("20").Map(func(n int) int { return n + 1 }).Unwrap(); got != 21 {
+		t.Errorf("Unwrap = %d", got)
+	}
+	if got := parse("oops").Map(func(n int) int { return n + 1 }).OrElse(-1); got != -1 {
+		t.Errorf("OrElse = %d", got)
+	}
+
+	wrapped := parse("oops").MapErr(func(err error) error { return fmt.Errorf("parse port: %w", err) })
+	var numErr *strconv.NumError
+	if wrapped.IsOk() || !errors.As(wrapped.Err(), &numErr) || !strings.HasPrefix(wrapped.Err().Error(), "parse port:") {
+		t.Errorf("MapErr = %v", wrapped.Err())
+	}
+	if ok := parse("1").MapErr(func(error) error { t.Error("MapErr called on success"); return nil }); !ok.IsOk() {
+		t.Error("MapErr should keep success")
+	}
+
+	if got := MapResult(parse("5"), strconv.Itoa).OrElse(""); got != "5" {
+		t.Errorf("MapResult = %q", got)
+	}
+	if r := MapResult(Err[int](errors.New("boom")), strconv.Itoa); r.Err() == nil || r.Err().Error() != "boom" {
+		t.Errorf("MapResult error = %v", r.Err())
+	}
+	if parse("x").Optional().IsPresent() || parse("8").Optional().OrElse(0) != 8 {
+		t.Error("Result.Optional")
+	}
+	if v, err := Ok("v").Get(); v != "v" || err != nil {
+		t.Errorf("Ok.Get = %q, %v", v, err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Unwrap on failed Result should panic")
+		}
+	}()
+	parse("x").Unwrap()
+}
+
+func BenchmarkOptionalPresent(b *testing.B) {
+	m := XMap[string, int]{"port": 8080}
+	arr := Arrays(1, 2, 3)
+	double := func(n int) int { return n * 2 }
+	b.ReportAllocs()
+	b.ResetTimer()
+	var sink int
+	for i := 0; i < b.N; i++ {
+		sink += m.GetOpt("port").Map(double).OrElse(0)
+		sink += arr.FirstOpt().OrElse(0)
+		sink += Str("42").IntOpt().OrElse(0)
+		sink += ResultOf(strconv.Atoi("7")).Map(double).OrElse(0)
+	}
+	_ = sink
+}
+
+func TestOptionalPresentPathDoesNotAllocate(t *testing.T) {
+	m := XMap[string, int]{"port": 8080}
+	arr := Arrays(1, 2, 3)
+	double := func(n int) int { return n * 2 }
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = m.GetOpt("port").Map(double).OrElse(0)
+		_ = arr.FirstOpt().OrElse(0)
+		_ = Str("42").IntOpt().OrElse(0)
+		_ = ResultOf(strconv.Atoi("7")).Map(double).OrElse(0)
+	})
+	if allocs != 0 {
+		t.Errorf("present path allocates %v times per run", allocs)
+	}
+}
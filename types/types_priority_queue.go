@@ -0,0 +1,135 @@
+package types
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// XPriorityQueue 优先级队列，优先级高的元素先出队，相同优先级按入队顺序（FIFO）出队
+type XPriorityQueue[T any] struct {
+	items    pqItems[T]
+	seq      uint64
+	capacity int
+	mu       *sync.Mutex
+}
+
+type pqItem[T any] struct {
+	value    T
+	priority float64
+	seq      uint64
+}
+
+// pqItems 实现 heap.Interface
+type pqItems[T any] []pqItem[T]
+
+func (p pqItems[T]) Len() int {
+	return len(p)
+}
+
+func (p pqItems[T]) Less(i, j int) bool {
+	if p[i].priority == p[j].priority {
+		return p[i].seq < p[j].seq
+	}
+	return p[i].priority > p[j].priority
+}
+
+func (p pqItems[T]) Swap(i, j int) {
+	p[i], p[j] = p[j], p[i]
+}
+
+func (p *pqItems[T]) Push(x any) {
+	*p = append(*p, x.(pqItem[T]))
+}
+
+func (p *pqItems[T]) Pop() any {
+	old := *p
+	n := len(old)
+	item := old[n-1]
+	*p = old[:n-1]
+	return item
+}
+
+// NewPriorityQueue 创建优先级队列
+// capacity <= 0 表示不限容量；超出容量时淘汰优先级最低的元素（同优先级淘汰最晚入队的）
+// threadSafe 为 true 时所有操作加锁，可在多个 goroutine 间共享
+func NewPriorityQueue[T any](capacity int, threadSafe bool) *XPriorityQueue[T] {
+	q := &XPriorityQueue[T]{capacity: capacity}
+	if threadSafe {
+		q.mu = &sync.Mutex{}
+	}
+	return q
+}
+
+func (q *XPriorityQueue[T]) lock() {
+	if q.mu != nil {
+		q.mu.Lock()
+	}
+}
+
+func (q *XPriorityQueue[T]) unlock() {
+	if q.mu != nil {
+		q.mu.Unlock()
+	}
+}
+
+// Push 入队
+func (q *XPriorityQueue[T]) Push(item T, priority float64) {
+	q.lock()
+	defer q.unlock()
+
+	q.seq++
+	heap.Push(&q.items, pqItem[T]{value: item, priority: priority, seq: q.seq})
+
+	if q.capacity > 0 && q.items.Len() > q.capacity {
+		heap.Remove(&q.items, q.lowestIndex())
+	}
+}
+
+// Pop 取出优先级最高的元素
+func (q *XPriorityQueue[T]) Pop() (T, bool) {
+	q.lock()
+	defer q.unlock()
+
+	if q.items.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	item := heap.Pop(&q.items).(pqItem[T])
+	return item.value, true
+}
+
+// Peek 查看优先级最高的元素但不出队
+func (q *XPriorityQueue[T]) Peek() (T, bool) {
+	q.lock()
+	defer q.unlock()
+
+	if q.items.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return q.items[0].value, true
+}
+
+// Len 获取队列长度
+func (q *XPriorityQueue[T]) Len() int {
+	q.lock()
+	defer q.unlock()
+
+	return q.items.Len()
+}
+
+// IsEmpty 判断队列是否为空
+func (q *XPriorityQueue[T]) IsEmpty() bool {
+	return q.Len() == 0
+}
+
+// lowestIndex 查找优先级最低（同优先级最晚入队）的元素位置
+func (q *XPriorityQueue[T]) lowestIndex() int {
+	lowest := 0
+	for i := 1; i < q.items.Len(); i++ {
+		if q.items.Less(lowest, i) {
+			lowest = i
+		}
+	}
+	return lowest
+}
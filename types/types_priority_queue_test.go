@@ -0,0 +1,81 @@
+package types
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestPriorityQueueOrdering(t *testing.T) {
+	q := NewPriorityQueue[string](0, false)
+	q.Push("low", 1)
+	q.Push("high", 10)
+	q.Push("mid-1", 5)
+	q.Push("mid-2", 5)
+	q.Push("mid-3", 5)
+
+	if top, ok := q.Peek(); !ok || top != "high" {
+		t.Fatalf("Peek = %q, want high", top)
+	}
+
+	expected := []string{"high", "mid-1", "mid-2", "mid-3", "low"}
+	for _, want := range expected {
+		got, ok := q.Pop()
+		if !ok || got != want {
+			t.Fatalf("Pop = %q, want %q", got, want)
+		}
+	}
+
+	if _, ok := q.Pop(); ok {
+		t.Error("Pop on empty queue should return false")
+	}
+}
+
+func TestPriorityQueueEviction(t *testing.T) {
+	q := NewPriorityQueue[string](3, false)
+	q.Push("a", 2)
+	q.Push("b", 1)
+	q.Push("c", 3)
+	q.Push("d", 4) // 淘汰 b
+	q.Push("e", 2) // 与 a 同优先级，淘汰更晚入队的 e
+
+	if q.Len() != 3 {
+		t.Fatalf("Len = %d, want 3", q.Len())
+	}
+
+	expected := []string{"d", "c", "a"}
+	for _, want := range expected {
+		if got, _ := q.Pop(); got != want {
+			t.Errorf("Pop = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestPriorityQueueConcurrentProducers(t *testing.T) {
+	q := NewPriorityQueue[int](0, true)
+
+	var wg sync.WaitGroup
+	for p := 0; p < 8; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				q.Push(p*100+i, float64(i))
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	if q.Len() != 800 {
+		t.Fatalf("Len = %d, want 800", q.Len())
+	}
+
+	last := 100.0
+	for !q.IsEmpty() {
+		v, _ := q.Pop()
+		priority := float64(v % 100)
+		if priority > last {
+			t.Fatalf("priority order violated: %v after %v", priority, last)
+		}
+		last = priority
+	}
+}
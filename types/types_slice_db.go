@@ -0,0 +1,327 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 数据库数组列支持
+//
+// 泛型实例化（如 XArray[string]）无法单独定义方法，因此通过包装类型实现 sql.Scanner / driver.Valuer：
+//   - DBArray[T]     读写 Postgres 数组字面量 {a,b,"c,d",NULL}，Scan 时也兼容 JSON 数组
+//   - DBJSONArray[T] 读写 JSON 数组，用于 MySQL json 列
+//
+// NULL 元素扫描为零值；整列为 NULL 时扫描为 nil 切片，nil 切片写入为 NULL。
+
+// DBArrayElem 支持数据库数组列的元素类型
+type DBArrayElem interface {
+	string | int | int64
+}
+
+// ErrPgArraySyntax Postgres 数组字面量格式错误，可通过 errors.Is 判断
+var ErrPgArraySyntax = errors.New("invalid postgres array literal")
+
+// DBArray Postgres 数组列包装类型，可直接转换为 XArray[T]
+type DBArray[T DBArrayElem] XArray[T]
+
+// DBJSONArray JSON 数组列包装类型，可直接转换为 XArray[T]
+type DBJSONArray[T DBArrayElem] XArray[T]
+
+// Array 转换为 XArray 以使用链式方法
+func (a DBArray[T]) Array() XArray[T] {
+	return XArray[T](a)
+}
+
+// Value 实现 driver.Valuer 接口，输出 Postgres 数组字面量
+func (a DBArray[T]) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	elems := make([]*string, len(a))
+	for i, v := range a {
+		s := formatDBElem(v)
+		elems[i] = &s
+	}
+	return FormatPgArray(elems), nil
+}
+
+// Scan 实现 sql.Scanner 接口，接受 Postgres 数组字面量或 JSON 数组
+func (a *DBArray[T]) Scan(value interface{}) error {
+	text, isNull, err := dbArrayText(value, "DBArray")
+	if err != nil {
+		return err
+	}
+	if isNull {
+		*a = nil
+		return nil
+	}
+
+	if trimmed := strings.TrimSpace(text); strings.HasPrefix(trimmed, "[") {
+		var j DBJSONArray[T]
+		if err := j.Scan(trimmed); err != nil {
+			return err
+		}
+		*a = DBArray[T](j)
+		return nil
+	}
+
+	elems, err := ParsePgArray(text)
+	if err != nil {
+		return err
+	}
+	out := make(DBArray[T], len(elems))
+	for i, e := range elems {
+		if e == nil {
+			continue
+		}
+		if out[i], err = parseDBElem[T](*e); err != nil {
+			return fmt.Errorf("array element %d: %w", i, err)
+		}
+	}
+	*a = out
+	return nil
+}
+
+// GormDataType 返回 GORM 数据类型
+func (a DBArray[T]) GormDataType() string {
+	var v T
+	switch any(v).(type) {
+	case int, int64:
+		return "bigint[]"
+	default:
+		return "text[]"
+	}
+}
+
+// Array 转换为 XArray 以使用链式方法
+func (a DBJSONArray[T]) Array() XArray[T] {
+	return XArray[T](a)
+}
+
+// Value 实现 driver.Valuer 接口，输出 JSON 数组
+func (a DBJSONArray[T]) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal([]T(a))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 实现 sql.Scanner 接口，JSON 中的 null 元素扫描为零值
+func (a *DBJSONArray[T]) Scan(value interface{}) error {
+	text, isNull, err := dbArrayText(value, "DBJSONArray")
+	if err != nil {
+		return err
+	}
+	if isNull || strings.TrimSpace(text) == "null" {
+		*a = nil
+		return nil
+	}
+
+	var out []T
+	if err := json.Unmarshal([]byte(text), &out); err != nil {
+		return fmt.Errorf("cannot scan json array: %w", err)
+	}
+	if out == nil {
+		out = []T{}
+	}
+	*a = out
+	return nil
+}
+
+// GormDataType 返回 GORM 数据类型
+func (a DBJSONArray[T]) GormDataType() string {
+	return "json"
+}
+
+// ParsePgArray 解析一维 Postgres 数组字面量，NULL 元素以 nil 表示
+// 支持双引号包裹、反斜杠转义以及未加引号元素的首尾空白
+func ParsePgArray(s string) ([]*string, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return nil, fmt.Errorf("%w: %q", ErrPgArraySyntax, s)
+	}
+	body := s[1 : len(s)-1]
+	if strings.TrimSpace(body) == "" {
+		return []*string{}, nil
+	}
+
+	var out []*string
+	for i := 0; ; {
+		for i < len(body) && isPgSpace(body[i]) {
+			i++
+		}
+		if i >= len(body) {
+			return nil, fmt.Errorf("%w: missing element in %q", ErrPgArraySyntax, s)
+		}
+
+		var sb strings.Builder
+		quoted := false
+		switch body[i] {
+		case '{':
+			return nil, fmt.Errorf("%w: multi-dimensional arrays are not supported", ErrPgArraySyntax)
+		case '"':
+			quoted = true
+			i++
+			for {
+				if i >= len(body) {
+					return nil, fmt.Errorf("%w: unterminated quoted element in %q", ErrPgArraySyntax, s)
+				}
+				c := body[i]
+				if c == '"' {
+					i++
+					break
+				}
+				if c == '\\' {
+					i++
+					if i >= len(body) {
+						return nil, fmt.Errorf("%w: dangling escape in %q", ErrPgArraySyntax, s)
+					}
+					c = body[i]
+				}
+				sb.WriteByte(c)
+				i++
+			}
+		default:
+			for i < len(body) && body[i] != ',' {
+				c := body[i]
+				switch c {
+				case '"', '{', '}':
+					return nil, fmt.Errorf("%w: unexpected %q in %q", ErrPgArraySyntax, c, s)
+				case '\\':
+					i++
+					if i >= len(body) {
+						return nil, fmt.Errorf("%w: dangling escape in %q", ErrPgArraySyntax, s)
+					}
+					c = body[i]
+				}
+				sb.WriteByte(c)
+				i++
+			}
+		}
+
+		for i < len(body) && isPgSpace(body[i]) {
+			i++
+		}
+		elem := sb.String()
+		if !quoted {
+			elem = strings.TrimRight(elem, " \t\n\r\v\f")
+		}
+		if !quoted && strings.EqualFold(elem, "NULL") {
+			out = append(out, nil)
+		} else {
+			out = append(out, &elem)
+		}
+
+		if i >= len(body) {
+			return out, nil
+		}
+		if body[i] != ',' {
+			return nil, fmt.Errorf("%w: expected ',' at offset %d in %q", ErrPgArraySyntax, i+1, s)
+		}
+		i++
+	}
+}
+
+// FormatPgArray 生成一维 Postgres 数组字面量，nil 元素输出为 NULL
+// 与 Postgres 的输出规则一致：仅在元素为空、含特殊字符或空白、或等于 NULL 时加引号
+func FormatPgArray(elems []*string) string {
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i, e := range elems {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		if e == nil {
+			sb.WriteString("NULL")
+			continue
+		}
+		if !pgNeedsQuote(*e) {
+			sb.WriteString(*e)
+			continue
+		}
+		sb.WriteByte('"')
+		for j := 0; j < len(*e); j++ {
+			if c := (*e)[j]; c == '"' || c == '\\' {
+				sb.WriteByte('\\')
+			}
+			sb.WriteByte((*e)[j])
+		}
+		sb.WriteByte('"')
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+func pgNeedsQuote(s string) bool {
+	if s == "" || strings.EqualFold(s, "NULL") {
+		return true
+	}
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\', '{', '}', ',':
+			return true
+		default:
+			if isPgSpace(c) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isPgSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
+
+// dbArrayText 将驱动返回的值统一为字符串
+func dbArrayText(value interface{}, typeName string) (string, bool, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", true, nil
+	case []byte:
+		return string(v), false, nil
+	case string:
+		return v, false, nil
+	default:
+		return "", false, fmt.Errorf("cannot scan %T into %s", value, typeName)
+	}
+}
+
+func formatDBElem[T DBArrayElem](v T) string {
+	switch x := any(v).(type) {
+	case int:
+		return strconv.Itoa(x)
+	case int64:
+		return strconv.FormatInt(x, 10)
+	default:
+		return any(v).(string)
+	}
+}
+
+func parseDBElem[T DBArrayElem](s string) (T, error) {
+	var zero T
+	switch any(zero).(type) {
+	case int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return zero, err
+		}
+		return any(n).(T), nil
+	case int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return zero, err
+		}
+		return any(n).(T), nil
+	default:
+		return any(s).(T), nil
+	}
+}
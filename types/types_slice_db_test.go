@@ -0,0 +1,138 @@
+package types
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDBArrayStringRoundTrip(t *testing.T) {
+	// 字面量取自 Postgres 15 对 text[] 列的 SELECT 输出
+	tests := []struct {
+		literal string
+		want    DBArray[string]
+	}{
+		{`{}`, DBArray[string]{}},
+		{`{a,b,c}`, DBArray[string]{"a", "b", "c"}},
+		{`{"a,b",c}`, DBArray[string]{"a,b", "c"}},
+		{`{"say \"hi\"","back\\slash"}`, DBArray[string]{`say "hi"`, `back\slash`}},
+		{`{"{x}","}",""}`, DBArray[string]{"{x}", "}", ""}},
+		{`{"with space","NULL",null-ish}`, DBArray[string]{"with space", "NULL", "null-ish"}},
+		{`{中文,"逗号,分隔"}`, DBArray[string]{"中文", "逗号,分隔"}},
+	}
+	for _, tt := range tests {
+		var got DBArray[string]
+		if err := got.Scan([]byte(tt.literal)); err != nil {
+			t.Fatalf("Scan(%s) error: %v", tt.literal, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Scan(%s) = %#v, want %#v", tt.literal, got, tt.want)
+		}
+		value, err := got.Value()
+		if err != nil {
+			t.Fatalf("Value() error: %v", err)
+		}
+		if value != tt.literal {
+			t.Errorf("Value() = %v, want %s", value, tt.literal)
+		}
+	}
+}
+
+func TestDBArrayNullsAndWhitespace(t *testing.T) {
+	var s DBArray[string]
+	if err := s.Scan(`{ a , NULL,"b" ,NuLL }`); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s, DBArray[string]{"a", "", "b", ""}) {
+		t.Errorf("got %#v", s)
+	}
+
+	elems, err := ParsePgArray(`{1,NULL,"NULL"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(elems) != 3 || elems[1] != nil || elems[2] == nil || *elems[2] != "NULL" {
+		t.Errorf("ParsePgArray should distinguish NULL from \"NULL\": %v", elems)
+	}
+	if got := FormatPgArray(elems); got != `{1,NULL,"NULL"}` {
+		t.Errorf("FormatPgArray = %s", got)
+	}
+
+	if err := s.Scan(nil); err != nil || s != nil {
+		t.Errorf("Scan(nil) = %#v, %v", s, err)
+	}
+	if v, err := s.Value(); v != nil || err != nil {
+		t.Errorf("nil array Value() = %v, %v", v, err)
+	}
+}
+
+func TestDBArrayInts(t *testing.T) {
+	var ints DBArray[int]
+	if err := ints.Scan([]byte(`{1,-2,NULL,30}`)); err != nil {
+		t.Fatal(err)
+	}
+	if !ints.Array().Equal(Arrays(1, -2, 0, 30)) {
+		t.Errorf("got %v", ints)
+	}
+	if v, _ := ints.Value(); v != "{1,-2,0,30}" {
+		t.Errorf("Value() = %v", v)
+	}
+
+	var big DBArray[int64]
+	if err := big.Scan(`{9223372036854775807,-1}`); err != nil {
+		t.Fatal(err)
+	}
+	if big[0] != 9223372036854775807 || big[1] != -1 {
+		t.Errorf("got %v", big)
+	}
+
+	if err := ints.Scan(`{1,x}`); err == nil {
+		t.Error("non-numeric element should fail")
+	}
+}
+
+func TestDBArrayJSONFallback(t *testing.T) {
+	var s DBArray[string]
+	if err := s.Scan(`["a,b","{c}"]`); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s, DBArray[string]{"a,b", "{c}"}) {
+		t.Errorf("got %#v", s)
+	}
+
+	// MySQL json 列
+	var j DBJSONArray[int64]
+	if err := j.Scan([]byte(`[1, null, 3]`)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(j, DBJSONArray[int64]{1, 0, 3}) {
+		t.Errorf("got %#v", j)
+	}
+	if v, _ := j.Value(); v != "[1,0,3]" {
+		t.Errorf("Value() = %v", v)
+	}
+
+	var strs DBJSONArray[string]
+	if err := strs.Scan(`["say \"hi\"", "x"]`); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := strs.Value(); v != `["say \"hi\"","x"]` {
+		t.Errorf("Value() = %v", v)
+	}
+	if err := strs.Scan(`null`); err != nil || strs != nil {
+		t.Errorf("Scan(null) = %#v, %v", strs, err)
+	}
+}
+
+func TestDBArrayInvalid(t *testing.T) {
+	for _, literal := range []string{`a,b`, `{"a}`, `{a,}`, `{{1,2},{3,4}}`, `{a"b}`, `{"a"b}`} {
+		var s DBArray[string]
+		if err := s.Scan(literal); !errors.Is(err, ErrPgArraySyntax) {
+			t.Errorf("Scan(%s) error = %v, want ErrPgArraySyntax", literal, err)
+		}
+	}
+	var s DBArray[string]
+	if err := s.Scan(42); err == nil {
+		t.Error("Scan(int) should fail")
+	}
+}
@@ -0,0 +1,95 @@
+package types
+
+import "math"
+
+// 滚动窗口计算：返回与输入等长的切片，第 i 个元素对应以 a[i] 结尾的窗口
+
+// RollingSum 滚动求和，前 window-1 个元素为已有样本的部分和
+// window 小于等于 0 时返回 nil
+func (a XArray[T]) RollingSum(window int) XArray[T] {
+	if window <= 0 {
+		return nil
+	}
+
+	out := make(XArray[T], len(a))
+	for i := range a {
+		var sum T
+		for _, v := range a[max(0, i-window+1) : i+1] {
+			sum = sum + v
+		}
+		out[i] = sum
+	}
+	return out
+}
+
+// RollingAverage 滚动平均，元素经 ToFloat64 转换
+// 前 window-1 个元素默认为 NaN，partial 为 true 时使用已有样本的部分平均
+// window 小于等于 0 时返回 nil
+func (a XArray[T]) RollingAverage(window int, partial ...bool) []float64 {
+	if window <= 0 {
+		return nil
+	}
+	usePartial := len(partial) > 0 && partial[0]
+
+	values := a.ToFloat64()
+	out := make([]float64, len(values))
+	var sum float64
+	for i, v := range values {
+		sum += v
+		if i >= window {
+			sum -= values[i-window]
+		}
+
+		switch {
+		case i >= window-1:
+			out[i] = sum / float64(window)
+		case usePartial:
+			out[i] = sum / float64(i+1)
+		default:
+			out[i] = math.NaN()
+		}
+	}
+	return out
+}
+
+// RollingMax 滚动最大值，前 window-1 个元素为已有样本的最大值
+// window 小于等于 0 时返回 nil
+func (a XArray[T]) RollingMax(window int) XArray[T] {
+	if window <= 0 {
+		return nil
+	}
+
+	out := make(XArray[T], len(a))
+	// deque 保存窗口内下标，对应的值单调递减，队首即为最大值
+	deque := make([]int, 0, window)
+	for i, v := range a {
+		if len(deque) > 0 && deque[0] <= i-window {
+			deque = deque[1:]
+		}
+		for len(deque) > 0 && a[deque[len(deque)-1]] <= v {
+			deque = deque[:len(deque)-1]
+		}
+		deque = append(deque, i)
+		out[i] = a[deque[0]]
+	}
+	return out
+}
+
+// EWMA 指数加权移动平均，s[0] = x[0]，s[i] = alpha*x[i] + (1-alpha)*s[i-1]
+// alpha 不在 (0, 1] 区间时返回 nil
+func (a XArray[T]) EWMA(alpha float64) []float64 {
+	if !(alpha > 0 && alpha <= 1) {
+		return nil
+	}
+
+	values := a.ToFloat64()
+	out := make([]float64, len(values))
+	for i, v := range values {
+		if i == 0 {
+			out[i] = v
+			continue
+		}
+		out[i] = alpha*v + (1-alpha)*out[i-1]
+	}
+	return out
+}
@@ -0,0 +1,95 @@
+package types
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestRollingAgainstBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for round := 0; round < 50; round++ {
+		n := rng.Intn(40)
+		window := 1 + rng.Intn(8)
+		ints := make(XArray[int], n)
+		for i := range ints {
+			ints[i] = rng.Intn(200) - 100
+		}
+
+		sums := ints.RollingSum(window)
+		maxes := ints.RollingMax(window)
+		avgs := ints.RollingAverage(window)
+		partialAvgs := ints.RollingAverage(window, true)
+		if len(sums) != n || len(maxes) != n || len(avgs) != n || len(partialAvgs) != n {
+			t.Fatalf("outputs must be aligned to input length %d", n)
+		}
+
+		for i := 0; i < n; i++ {
+			start := i - window + 1
+			if start < 0 {
+				start = 0
+			}
+			wantSum, wantMax := 0, ints[start]
+			for _, v := range ints[start : i+1] {
+				wantSum += v
+				if v > wantMax {
+					wantMax = v
+				}
+			}
+			if sums[i] != wantSum {
+				t.Errorf("RollingSum(%d)[%d] = %d, want %d", window, i, sums[i], wantSum)
+			}
+			if maxes[i] != wantMax {
+				t.Errorf("RollingMax(%d)[%d] = %d, want %d", window, i, maxes[i], wantMax)
+			}
+
+			partial := float64(wantSum) / float64(i+1-start)
+			if math.Abs(partialAvgs[i]-partial) > 1e-9 {
+				t.Errorf("RollingAverage(%d, true)[%d] = %v, want %v", window, i, partialAvgs[i], partial)
+			}
+			if i < window-1 {
+				if !math.IsNaN(avgs[i]) {
+					t.Errorf("RollingAverage(%d)[%d] = %v, want NaN", window, i, avgs[i])
+				}
+			} else if math.Abs(avgs[i]-partial) > 1e-9 {
+				t.Errorf("RollingAverage(%d)[%d] = %v, want %v", window, i, avgs[i], partial)
+			}
+		}
+	}
+}
+
+func TestRollingFloatsAndEWMA(t *testing.T) {
+	samples := Arrays(1.5, 3.0, 2.0, 8.0, 4.0)
+
+	if got := samples.RollingMax(2); !got.Equal(Arrays(1.5, 3.0, 3.0, 8.0, 8.0)) {
+		t.Errorf("RollingMax = %v", got)
+	}
+	if got := samples.RollingSum(3); !got.Equal(Arrays(1.5, 4.5, 6.5, 13.0, 14.0)) {
+		t.Errorf("RollingSum = %v", got)
+	}
+
+	ewma := samples.EWMA(0.5)
+	want := []float64{1.5}
+	for _, v := range samples[1:] {
+		want = append(want, 0.5*v+0.5*want[len(want)-1])
+	}
+	for i := range want {
+		if math.Abs(ewma[i]-want[i]) > 1e-12 {
+			t.Errorf("EWMA[%d] = %v, want %v", i, ewma[i], want[i])
+		}
+	}
+	if got := samples.EWMA(1); got[3] != 8.0 {
+		t.Errorf("EWMA(1) should follow the input, got %v", got)
+	}
+
+	// 参数非法
+	if samples.RollingSum(0) != nil || samples.RollingAverage(-1) != nil || samples.RollingMax(0) != nil {
+		t.Error("non-positive window should return nil")
+	}
+	if samples.EWMA(0) != nil || samples.EWMA(1.5) != nil || samples.EWMA(math.NaN()) != nil {
+		t.Error("alpha outside (0, 1] should return nil")
+	}
+	if got := Arrays[int]().RollingAverage(3); len(got) != 0 {
+		t.Errorf("empty input = %v", got)
+	}
+}
@@ -0,0 +1,240 @@
+package types
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 拓扑排序：按依赖关系排列元素，依赖排在依赖它的元素之前
+//
+// deps 返回元素直接依赖的元素。排序是稳定的：任意时刻有多个元素可以输出时，按其在 items 中的顺序输出，
+// 因此没有依赖关系的元素保持原有顺序。items 中重复的元素只保留第一次出现的位置。
+// 依赖不在 items 中时默认返回 ErrMissingDependency，ignoreMissing 为 true 时忽略该依赖。
+
+// ErrCycle 依赖关系中存在环，可通过 errors.Is 判断
+var ErrCycle = errors.New("dependency cycle")
+
+// ErrMissingDependency 依赖的元素不在待排序的元素中，可通过 errors.Is 判断
+var ErrMissingDependency = errors.New("missing dependency")
+
+// CycleError TopoSort / Layers 遇到依赖环时返回的错误
+// Nodes 按依赖方向列出环上的元素，Nodes[i] 依赖 Nodes[i+1]，最后一个元素依赖 Nodes[0]
+type CycleError[T comparable] struct {
+	Nodes []T
+}
+
+func (e *CycleError[T]) Error() string {
+	parts := make([]string, 0, len(e.Nodes)+1)
+	for _, node := range e.Nodes {
+		parts = append(parts, fmt.Sprint(node))
+	}
+	if len(e.Nodes) > 0 {
+		parts = append(parts, fmt.Sprint(e.Nodes[0]))
+	}
+	return "dependency cycle: " + strings.Join(parts, " -> ")
+}
+
+// Unwrap 使 errors.Is(err, ErrCycle) 成立
+func (e *CycleError[T]) Unwrap() error {
+	return ErrCycle
+}
+
+// TopoSort 返回稳定的拓扑顺序，存在环时返回 *CycleError[T]
+func TopoSort[T comparable](items []T, deps func(T) []T, ignoreMissing ...bool) ([]T, error) {
+	g, err := newTopoGraph(items, deps, len(ignoreMissing) > 0 && ignoreMissing[0])
+	if err != nil {
+		return nil, err
+	}
+
+	indegree := g.indegree()
+	ready := &topoQueue{}
+	for i, d := range indegree {
+		if d == 0 {
+			heap.Push(ready, i)
+		}
+	}
+
+	order := make([]T, 0, len(g.items))
+	for ready.Len() > 0 {
+		i := heap.Pop(ready).(int)
+		order = append(order, g.items[i])
+		for _, dependent := range g.dependents[i] {
+			indegree[dependent]--
+			if indegree[dependent] == 0 {
+				heap.Push(ready, dependent)
+			}
+		}
+	}
+	if len(order) < len(g.items) {
+		return nil, g.cycle(indegree)
+	}
+	return order, nil
+}
+
+// Layers 将元素分为可并行执行的层：第 0 层没有依赖，第 k 层只依赖前 k 层的元素
+// 每层内按 items 中的顺序排列，存在环时返回 *CycleError[T]
+func Layers[T comparable](items []T, deps func(T) []T, ignoreMissing ...bool) ([][]T, error) {
+	g, err := newTopoGraph(items, deps, len(ignoreMissing) > 0 && ignoreMissing[0])
+	if err != nil {
+		return nil, err
+	}
+
+	indegree := g.indegree()
+	var current []int
+	for i, d := range indegree {
+		if d == 0 {
+			current = append(current, i)
+		}
+	}
+
+	var layers [][]T
+	done := 0
+	for len(current) > 0 {
+		layer := make([]T, len(current))
+		var next []int
+		for k, i := range current {
+			layer[k] = g.items[i]
+			for _, dependent := range g.dependents[i] {
+				indegree[dependent]--
+				if indegree[dependent] == 0 {
+					next = append(next, dependent)
+				}
+			}
+		}
+		layers = append(layers, layer)
+		done += len(current)
+		sort.Ints(next)
+		current = next
+	}
+	if done < len(g.items) {
+		return nil, g.cycle(indegree)
+	}
+	return layers, nil
+}
+
+// TopoSort 按依赖关系排序，规则同包级函数 TopoSort
+func (a XArray[T]) TopoSort(deps func(T) []T, ignoreMissing ...bool) (XArray[T], error) {
+	return TopoSort([]T(a), deps, ignoreMissing...)
+}
+
+// topoGraph 以下标表示的依赖图
+type topoGraph[T comparable] struct {
+	items      []T
+	deps       [][]int // deps[i] 为 items[i] 依赖的元素
+	dependents [][]int // dependents[i] 为依赖 items[i] 的元素
+}
+
+// newTopoGraph 去除重复元素与重复依赖后建图
+func newTopoGraph[T comparable](items []T, deps func(T) []T, ignoreMissing bool) (*topoGraph[T], error) {
+	index := make(map[T]int, len(items))
+	g := &topoGraph[T]{}
+	for _, item := range items {
+		if _, ok := index[item]; !ok {
+			index[item] = len(g.items)
+			g.items = append(g.items, item)
+		}
+	}
+
+	g.deps = make([][]int, len(g.items))
+	g.dependents = make([][]int, len(g.items))
+	for i, item := range g.items {
+		seen := make(map[int]bool)
+		for _, dep := range deps(item) {
+			j, ok := index[dep]
+			if !ok {
+				if ignoreMissing {
+					continue
+				}
+				return nil, fmt.Errorf("%w: %v depends on %v", ErrMissingDependency, item, dep)
+			}
+			if seen[j] {
+				continue
+			}
+			seen[j] = true
+			g.deps[i] = append(g.deps[i], j)
+			g.dependents[j] = append(g.dependents[j], i)
+		}
+	}
+	return g, nil
+}
+
+// indegree 返回每个元素尚未满足的依赖数
+func (g *topoGraph[T]) indegree() []int {
+	indegree := make([]int, len(g.items))
+	for i, deps := range g.deps {
+		indegree[i] = len(deps)
+	}
+	return indegree
+}
+
+// cycle 在排序后仍有未满足依赖的元素中找出一个环
+// 这些元素都至少依赖另一个未输出的元素，沿依赖前进必然回到已经过的元素
+func (g *topoGraph[T]) cycle(indegree []int) error {
+	start := -1
+	for i, d := range indegree {
+		if d > 0 {
+			start = i
+			break
+		}
+	}
+
+	position := make(map[int]int)
+	var path []int
+	for node := start; ; {
+		if p, ok := position[node]; ok {
+			path = path[p:]
+			break
+		}
+		position[node] = len(path)
+		path = append(path, node)
+		for _, dep := range g.deps[node] {
+			if indegree[dep] > 0 {
+				node = dep
+				break
+			}
+		}
+	}
+
+	// 从环上 items 中最靠前的元素开始
+	first := 0
+	for k, node := range path {
+		if node < path[first] {
+			first = k
+		}
+	}
+	nodes := make([]T, len(path))
+	for k := range path {
+		nodes[k] = g.items[path[(first+k)%len(path)]]
+	}
+	return &CycleError[T]{Nodes: nodes}
+}
+
+// topoQueue 按下标排序的最小堆，实现 heap.Interface
+type topoQueue []int
+
+func (q topoQueue) Len() int {
+	return len(q)
+}
+
+func (q topoQueue) Less(i, j int) bool {
+	return q[i] < q[j]
+}
+
+func (q topoQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *topoQueue) Push(x any) {
+	*q = append(*q, x.(int))
+}
+
+func (q *topoQueue) Pop() any {
+	old := *q
+	n := len(old)
+	x := old[n-1]
+	*q = old[:n-1]
+	return x
+}
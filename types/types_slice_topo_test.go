@@ -0,0 +1,202 @@
+package types
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// depsOf 由映射生成 deps 函数
+func depsOf[T comparable](graph map[T][]T) func(T) []T {
+	return func(item T) []T { return graph[item] }
+}
+
+func TestTopoSort(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []string
+		graph map[string][]string
+		want  []string
+	}{
+		{
+			"diamond",
+			[]string{"app", "http", "db", "config"},
+			map[string][]string{"app": {"http", "db"}, "http": {"config"}, "db": {"config"}},
+			[]string{"config", "http", "db", "app"},
+		},
+		{
+			"independent components keep input order",
+			[]string{"b2", "a2", "b1", "a1", "c"},
+			map[string][]string{"a2": {"a1"}, "b2": {"b1"}},
+			[]string{"b1", "b2", "a1", "a2", "c"},
+		},
+		{
+			"ready item emitted as soon as possible",
+			[]string{"z", "m", "a"},
+			map[string][]string{"z": {"a"}},
+			[]string{"m", "a", "z"},
+		},
+		{
+			"duplicates and repeated deps",
+			[]string{"b", "a", "b"},
+			map[string][]string{"b": {"a", "a"}},
+			[]string{"a", "b"},
+		},
+		{"empty", nil, nil, []string{}},
+	}
+	for _, tt := range tests {
+		got, err := TopoSort(tt.items, depsOf(tt.graph))
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: TopoSort = %v, %v, want %v", tt.name, got, err, tt.want)
+		}
+	}
+
+	sorted, err := Arrays(3, 1, 2).TopoSort(depsOf(map[int][]int{1: {2}}))
+	if err != nil || !reflect.DeepEqual(sorted, XArray[int]{3, 2, 1}) {
+		t.Errorf("XArray.TopoSort = %v, %v", sorted, err)
+	}
+}
+
+func TestTopoSortCycle(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []string
+		graph map[string][]string
+		want  []string
+		msg   string
+	}{
+		{
+			"three nodes",
+			[]string{"setup", "c", "a", "b", "after"},
+			map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"a"}, "after": {"c"}},
+			[]string{"c", "a", "b"},
+			"dependency cycle: c -> a -> b -> c",
+		},
+		{
+			"self dependency",
+			[]string{"x", "y"},
+			map[string][]string{"y": {"y"}},
+			[]string{"y"},
+			"dependency cycle: y -> y",
+		},
+	}
+	for _, tt := range tests {
+		_, err := TopoSort(tt.items, depsOf(tt.graph))
+		var cycle *CycleError[string]
+		if !errors.Is(err, ErrCycle) || !errors.As(err, &cycle) {
+			t.Fatalf("%s: err = %v, want *CycleError", tt.name, err)
+		}
+		if !reflect.DeepEqual(cycle.Nodes, tt.want) || err.Error() != tt.msg {
+			t.Errorf("%s: cycle = %v (%q), want %v", tt.name, cycle.Nodes, err.Error(), tt.want)
+		}
+		if _, err := Layers(tt.items, depsOf(tt.graph)); !errors.As(err, &cycle) || !reflect.DeepEqual(cycle.Nodes, tt.want) {
+			t.Errorf("%s: Layers err = %v", tt.name, err)
+		}
+	}
+}
+
+func TestTopoSortMissingDependency(t *testing.T) {
+	items := []string{"app", "db"}
+	graph := map[string][]string{"app": {"db", "cache"}}
+
+	if _, err := TopoSort(items, depsOf(graph)); !errors.Is(err, ErrMissingDependency) || err.Error() != "missing dependency: app depends on cache" {
+		t.Errorf("TopoSort err = %v", err)
+	}
+	if _, err := Layers(items, depsOf(graph)); !errors.Is(err, ErrMissingDependency) {
+		t.Errorf("Layers err = %v", err)
+	}
+
+	got, err := TopoSort(items, depsOf(graph), true)
+	if err != nil || !reflect.DeepEqual(got, []string{"db", "app"}) {
+		t.Errorf("ignoring missing = %v, %v", got, err)
+	}
+	layers, err := Layers(items, depsOf(graph), true)
+	if err != nil || !reflect.DeepEqual(layers, [][]string{{"db"}, {"app"}}) {
+		t.Errorf("Layers ignoring missing = %v, %v", layers, err)
+	}
+}
+
+func TestLayers(t *testing.T) {
+	items := []string{"deploy", "test", "lint", "build", "fetch", "docs"}
+	graph := map[string][]string{
+		"deploy": {"test", "build"},
+		"test":   {"build"},
+		"lint":   {"fetch"},
+		"build":  {"fetch"},
+	}
+	layers, err := Layers(items, depsOf(graph))
+	want := [][]string{{"fetch", "docs"}, {"lint", "build"}, {"test"}, {"deploy"}}
+	if err != nil || !reflect.DeepEqual(layers, want) {
+		t.Errorf("Layers = %v, %v, want %v", layers, err, want)
+	}
+	if layers, err := Layers([]int{}, depsOf(map[int][]int{})); err != nil || layers != nil {
+		t.Errorf("empty Layers = %v, %v", layers, err)
+	}
+}
+
+// naiveTopoSort 每次输出 items 中第一个依赖已全部输出的元素
+func naiveTopoSort(items []int, graph map[int][]int) []int {
+	done := make(map[int]bool)
+	var order []int
+	for len(order) < len(items) {
+		for _, item := range items {
+			if done[item] {
+				continue
+			}
+			ready := true
+			for _, dep := range graph[item] {
+				ready = ready && done[dep]
+			}
+			if ready {
+				done[item] = true
+				order = append(order, item)
+				break
+			}
+		}
+	}
+	return order
+}
+
+func TestTopoSortStability(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for round := 0; round < 100; round++ {
+		n := rng.Intn(30)
+		// 只依赖编号更小的元素，保证无环；打乱输入顺序
+		graph := make(map[int][]int)
+		for i := 1; i < n; i++ {
+			for k := rng.Intn(3); k > 0; k-- {
+				graph[i] = append(graph[i], rng.Intn(i))
+			}
+		}
+		items := rng.Perm(n)
+
+		got, err := TopoSort(items, depsOf(graph))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := naiveTopoSort(items, graph); !reflect.DeepEqual(got, want) && n > 0 {
+			t.Fatalf("round %d: TopoSort(%v) = %v, want %v", round, items, got, want)
+		}
+
+		layers, _ := Layers(items, depsOf(graph))
+		level := make(map[int]int)
+		count := 0
+		for l, layer := range layers {
+			for _, item := range layer {
+				level[item] = l
+				count++
+			}
+		}
+		if count != n {
+			t.Fatalf("round %d: layers contain %d items, want %d", round, count, n)
+		}
+		for item, deps := range graph {
+			for _, dep := range deps {
+				if level[dep] >= level[item] {
+					t.Fatalf("round %d: %d (layer %d) depends on %d (layer %d)", round, item, level[item], dep, level[dep])
+				}
+			}
+		}
+	}
+}
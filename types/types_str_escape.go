@@ -0,0 +1,75 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 针对不同输出位置的转义
+//
+// 每个方法只保证对应位置的语法安全，不能互相替代：例如 EscapeSQLLike 的结果仍需作为参数绑定，
+// 而不是拼接进 SQL 语句。
+
+// EscapeShellArg 转义为 POSIX shell 的单个参数
+// 整体用单引号包裹，内部的单引号先结束引号、转义后再重新开始，空字符串得到一对单引号：
+//
+//	it's  =>  'it'\''s'
+//
+// 单引号内没有其他特殊字符，换行、$、反引号与反斜杠都按字面传递。
+// 参数中不能包含 NUL 字节（shell 与 exec 都无法传递）
+func (s XStr) EscapeShellArg() XStr {
+	return XStr("'" + strings.ReplaceAll(string(s), "'", `'\''`) + "'")
+}
+
+// EscapeSQLLike 转义 LIKE 模式中的通配符，使字符串按字面匹配
+// %、_ 与 escapeChar 本身前加 escapeChar，查询中需要声明相同的转义字符，如
+//
+//	db.Query("SELECT * FROM t WHERE name LIKE ? ESCAPE '!'", "%"+keyword.EscapeSQLLike('!').String()+"%")
+//
+// 结果只转义 LIKE 通配符，不处理引号，必须作为参数绑定。escapeChar 为 % 或 _ 时 panic
+func (s XStr) EscapeSQLLike(escapeChar rune) XStr {
+	if escapeChar == '%' || escapeChar == '_' {
+		panic(fmt.Sprintf("invalid LIKE escape character %q", escapeChar))
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for _, r := range string(s) {
+		if r == '%' || r == '_' || r == escapeChar {
+			sb.WriteRune(escapeChar)
+		}
+		sb.WriteRune(r)
+	}
+	return XStr(sb.String())
+}
+
+// EscapeRegexp 转义正则表达式元字符，结果作为正则表达式时只匹配原字符串（regexp.QuoteMeta）
+func (s XStr) EscapeRegexp() XStr {
+	return XStr(regexp.QuoteMeta(string(s)))
+}
+
+// EscapeCSVField 按 RFC 4180 转义 CSV 字段（逗号分隔），规则与 encoding/csv 相同
+// 含逗号、双引号、\r、\n 或以空白开头时用双引号包裹，内部双引号写作 ""，其余原样返回。
+// 不处理电子表格的公式注入（以 =、+、-、@ 开头的字段），需要时应另行处理
+func (s XStr) EscapeCSVField() XStr {
+	str := string(s)
+	if !csvFieldNeedsQuotes(str) {
+		return s
+	}
+	return XStr(`"` + strings.ReplaceAll(str, `"`, `""`) + `"`)
+}
+
+// csvFieldNeedsQuotes 判断 CSV 字段是否需要引号，与 encoding/csv 的判断一致
+func csvFieldNeedsQuotes(field string) bool {
+	if field == "" {
+		return false
+	}
+	if field == `\.` || strings.ContainsAny(field, ",\"\r\n") {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(field)
+	return unicode.IsSpace(r)
+}
@@ -0,0 +1,196 @@
+package types
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os/exec"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// escapeVectors 覆盖各输出位置特殊字符的测试字符串
+var escapeVectors = []string{
+	"",
+	"plain",
+	"it's",
+	`say "hi"`,
+	`back\slash`,
+	"100%",
+	"a_b",
+	"line1\nline2",
+	"cr\r\nlf",
+	"$HOME `id` $(id) !x ;|&<>*?",
+	"'''",
+	`\'"%_\`,
+	" leading space",
+	"中文，全角",
+	"a,b",
+	`\.`,
+}
+
+func TestEscapeShellArg(t *testing.T) {
+	tests := map[string]string{
+		"":       `''`,
+		"plain":  `'plain'`,
+		"it's":   `'it'\''s'`,
+		"a\nb":   "'a\nb'",
+		`$x \n`:  `'$x \n'`,
+		"'":      `''\'''`,
+		`"q"`:    `'"q"'`,
+		"100% _": `'100% _'`,
+	}
+	for in, want := range tests {
+		if got := Str(in).EscapeShellArg().String(); got != want {
+			t.Errorf("EscapeShellArg(%q) = %s, want %s", in, got, want)
+		}
+	}
+
+	// 交给真实的 shell 解析，参数应原样到达
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	for _, in := range escapeVectors {
+		out, err := exec.Command(sh, "-c", "printf %s "+Str(in).EscapeShellArg().String()).Output()
+		if err != nil {
+			t.Fatalf("sh failed for %q: %v", in, err)
+		}
+		if string(out) != in {
+			t.Errorf("shell received %q, want %q", out, in)
+		}
+	}
+}
+
+func TestEscapeSQLLike(t *testing.T) {
+	tests := []struct {
+		in     string
+		escape rune
+		want   string
+	}{
+		{"100%", '\\', `100\%`},
+		{"a_b", '\\', `a\_b`},
+		{`back\slash`, '\\', `back\\slash`},
+		{`it's "q"`, '\\', `it's "q"`},
+		{"50%_off!", '!', "50!%!_off!!"},
+		{"line1\nline2", '\\', "line1\nline2"},
+		{"", '\\', ""},
+	}
+	for _, tt := range tests {
+		if got := Str(tt.in).EscapeSQLLike(tt.escape).String(); got != tt.want {
+			t.Errorf("EscapeSQLLike(%q, %q) = %q, want %q", tt.in, tt.escape, got, tt.want)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for % as escape character")
+		}
+	}()
+	Str("x").EscapeSQLLike('%')
+}
+
+// likeMatch 用正则表达式模拟 SQL LIKE：% 匹配任意串，_ 匹配单个字符，escape 后的字符按字面匹配
+func likeMatch(pattern string, escape rune, s string) bool {
+	var sb strings.Builder
+	sb.WriteString(`(?s)\A`)
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == escape:
+			escaped = true
+		case r == '%':
+			sb.WriteString(".*")
+		case r == '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString(`\z`)
+	return regexp.MustCompile(sb.String()).MatchString(s)
+}
+
+func TestEscapeSQLLikeRoundTrip(t *testing.T) {
+	for _, escape := range []rune{'\\', '!', '#'} {
+		for _, in := range escapeVectors {
+			pattern := Str(in).EscapeSQLLike(escape).String()
+			if !likeMatch(pattern, escape, in) {
+				t.Errorf("escaped %q (escape %q) does not match itself", in, escape)
+			}
+			// 通配符被转义后不能匹配其他字符串
+			for _, other := range []string{in + "x", "x" + in, strings.ReplaceAll(strings.ReplaceAll(in, "%", "zz"), "_", "z")} {
+				if other != in && likeMatch(pattern, escape, other) {
+					t.Errorf("escaped %q (escape %q) also matches %q", in, escape, other)
+				}
+			}
+			// 作为子串搜索时仍按字面匹配
+			if !likeMatch("%"+pattern+"%", escape, "head "+in+" tail") {
+				t.Errorf("contains search for %q failed", in)
+			}
+		}
+	}
+}
+
+func TestEscapeRegexp(t *testing.T) {
+	if got := Str(`a.b*c(d)[e]\f$^|+?{1}`).EscapeRegexp().String(); got != `a\.b\*c\(d\)\[e\]\\f\$\^\|\+\?\{1\}` {
+		t.Errorf("EscapeRegexp = %s", got)
+	}
+	for _, in := range escapeVectors {
+		re := regexp.MustCompile(`\A` + Str(in).EscapeRegexp().String() + `\z`)
+		if !re.MatchString(in) {
+			t.Errorf("escaped %q does not match itself", in)
+		}
+	}
+	if regexp.MustCompile(Str("a.c").EscapeRegexp().String()).MatchString("abc") {
+		t.Error("escaped dot should not match any character")
+	}
+}
+
+func TestEscapeCSVField(t *testing.T) {
+	tests := map[string]string{
+		"plain":          "plain",
+		"":               "",
+		"a,b":            `"a,b"`,
+		`say "hi"`:       `"say ""hi"""`,
+		"line1\nline2":   "\"line1\nline2\"",
+		"cr\r":           "\"cr\r\"",
+		" leading space": `" leading space"`,
+		"100% _'":        "100% _'",
+		`back\slash`:     `back\slash`,
+	}
+	for in, want := range tests {
+		if got := Str(in).EscapeCSVField().String(); got != want {
+			t.Errorf("EscapeCSVField(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	// 与 encoding/csv 的输出一致，并能被原样读回
+	fields := make([]string, 0, len(escapeVectors))
+	for _, in := range escapeVectors {
+		fields = append(fields, Str(in).EscapeCSVField().String())
+	}
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(escapeVectors); err != nil {
+		t.Fatal(err)
+	}
+	w.Flush()
+	if got, want := strings.Join(fields, ",")+"\n", buf.String(); got != want {
+		t.Errorf("csv mismatch:\n got %q\nwant %q", got, want)
+	}
+
+	record, err := csv.NewReader(strings.NewReader(strings.Join(fields, ","))).Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, in := range escapeVectors {
+		// csv.Reader 会把引号内的 \r\n 规范化为 \n
+		if want := strings.ReplaceAll(in, "\r\n", "\n"); record[i] != want {
+			t.Errorf("field %d read back as %q, want %q", i, record[i], want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// JSON 比较：用于测试断言，失败时生成可读的差异报告
+
+// jsonDiffLimit 差异报告最多列出的差异数量
+const jsonDiffLimit = 10
+
+// JSONEquals 按结构比较两个 JSON 字符串，s 为实际值，other 为期望值
+//
+// 对象键的顺序与空白不影响结果，数值按大小比较。不相等时返回差异报告，
+// 最多列出前 10 处不同的路径及两侧的值，可直接用于 t.Errorf。
+// 任一侧不是合法 JSON 时退化为逐行比较。
+func (s XStr) JSONEquals(other string) (bool, string) {
+	got, gotErr := ParseJSON(string(s))
+	want, wantErr := ParseJSON(other)
+	if gotErr != nil || wantErr != nil {
+		return lineDiff(string(s), other)
+	}
+
+	var diffs []string
+	jsonDiff(got.data, want.data, "", &diffs)
+	if len(diffs) == 0 {
+		return true, ""
+	}
+	return false, diffReport("JSON mismatch", diffs)
+}
+
+// jsonDiff 递归收集差异
+func jsonDiff(got, want interface{}, path string, diffs *[]string) {
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(g)+len(w))
+		for k := range w {
+			keys = append(keys, k)
+		}
+		for k := range g {
+			if _, exists := w[k]; !exists {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := diffPathKey(path, k)
+			gv, inGot := g[k]
+			wv, inWant := w[k]
+			switch {
+			case !inGot:
+				*diffs = append(*diffs, fmt.Sprintf("%s: missing, want %s", child, diffValue(wv)))
+			case !inWant:
+				*diffs = append(*diffs, fmt.Sprintf("%s: unexpected %s", child, diffValue(gv)))
+			default:
+				jsonDiff(gv, wv, child, diffs)
+			}
+		}
+		return
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok {
+			break
+		}
+		if len(g) != len(w) {
+			*diffs = append(*diffs, fmt.Sprintf("%s: got %d items, want %d", displayDiffPath(path), len(g), len(w)))
+		}
+		for i := 0; i < len(g) && i < len(w); i++ {
+			jsonDiff(g[i], w[i], fmt.Sprintf("%s[%d]", path, i), diffs)
+		}
+		return
+	}
+
+	gotJSON, wantJSON := diffValue(got), diffValue(want)
+	if gotJSON != wantJSON {
+		*diffs = append(*diffs, fmt.Sprintf("%s: got %s, want %s", displayDiffPath(path), gotJSON, wantJSON))
+	}
+}
+
+// lineDiff 逐行比较非 JSON 文本
+func lineDiff(got, want string) (bool, string) {
+	if got == want {
+		return true, ""
+	}
+
+	gotLines := strings.Split(got, "\n")
+	wantLines := strings.Split(want, "\n")
+	var diffs []string
+	for i := 0; i < len(gotLines) || i < len(wantLines); i++ {
+		switch {
+		case i >= len(gotLines):
+			diffs = append(diffs, fmt.Sprintf("line %d: missing, want %q", i+1, wantLines[i]))
+		case i >= len(wantLines):
+			diffs = append(diffs, fmt.Sprintf("line %d: unexpected %q", i+1, gotLines[i]))
+		case gotLines[i] != wantLines[i]:
+			diffs = append(diffs, fmt.Sprintf("line %d: got %q, want %q", i+1, gotLines[i], wantLines[i]))
+		}
+	}
+	return false, diffReport("text mismatch (not valid JSON)", diffs)
+}
+
+// diffReport 生成差异报告，超出上限的差异只报告数量
+func diffReport(title string, diffs []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %d difference(s)", title, len(diffs))
+	for i, d := range diffs {
+		if i == jsonDiffLimit {
+			fmt.Fprintf(&b, "\n  ... and %d more", len(diffs)-jsonDiffLimit)
+			break
+		}
+		b.WriteString("\n  " + d)
+	}
+	return b.String()
+}
+
+// diffValue 将值渲染为紧凑 JSON
+func diffValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// diffPathKey 拼接对象字段路径
+func diffPathKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// displayDiffPath 用于报告的路径，根显示为 (root)
+func displayDiffPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
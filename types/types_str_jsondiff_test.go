@@ -0,0 +1,70 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestJSONEquals(t *testing.T) {
+	ok, report := Str(`{"b": [1, 2.0], "a": {"x": null}}`).JSONEquals(`{"a":{"x":null},"b":[1,2]}`)
+	if !ok || report != "" {
+		t.Errorf("equivalent JSON should be equal, report: %s", report)
+	}
+
+	got := `{"user": {"name": "tom", "age": 30, "tags": ["a", "b", "c"]}, "extra": true}`
+	want := `{"user": {"name": "jerry", "age": 30, "tags": ["a", "b"], "email": "j@x.io"}}`
+	ok, report = Str(got).JSONEquals(want)
+	if ok {
+		t.Fatal("expected mismatch")
+	}
+	for _, line := range []string{
+		"JSON mismatch: 4 difference(s)",
+		`extra: unexpected true`,
+		`user.email: missing, want "j@x.io"`,
+		`user.name: got "tom", want "jerry"`,
+		`user.tags: got 3 items, want 2`,
+	} {
+		if !strings.Contains(report, line) {
+			t.Errorf("report missing %q:\n%s", line, report)
+		}
+	}
+
+	// 类型不同与数组元素路径
+	_, report = Str(`[{"id": 1}, {"id": "2"}]`).JSONEquals(`[{"id": 1}, {"id": 2}]`)
+	if !strings.Contains(report, `[1].id: got "2", want 2`) {
+		t.Errorf("unexpected report:\n%s", report)
+	}
+	_, report = Str(`1`).JSONEquals(`{}`)
+	if !strings.Contains(report, "(root): got 1, want {}") {
+		t.Errorf("unexpected report:\n%s", report)
+	}
+}
+
+func TestJSONEqualsLimit(t *testing.T) {
+	var got, want []string
+	for i := 0; i < 15; i++ {
+		got = append(got, fmt.Sprint(i))
+		want = append(want, fmt.Sprint(i+100))
+	}
+	_, report := Str("[" + strings.Join(got, ",") + "]").JSONEquals("[" + strings.Join(want, ",") + "]")
+	if !strings.Contains(report, "... and 5 more") || strings.Contains(report, "[10]") {
+		t.Errorf("report should be truncated:\n%s", report)
+	}
+}
+
+func TestJSONEqualsTextFallback(t *testing.T) {
+	ok, report := Str("line one\nline two").JSONEquals("line one\nline 2\nline three")
+	if ok {
+		t.Fatal("expected mismatch")
+	}
+	for _, line := range []string{"not valid JSON", `line 2: got "line two", want "line 2"`, `line 3: missing, want "line three"`} {
+		if !strings.Contains(report, line) {
+			t.Errorf("report missing %q:\n%s", line, report)
+		}
+	}
+
+	if ok, _ := Str("not json").JSONEquals("not json"); !ok {
+		t.Error("identical text should be equal")
+	}
+}
@@ -0,0 +1,42 @@
+package types
+
+import "strings"
+
+// XStr 与 XArray 之间的桥接，便于链式处理文本
+
+// SplitX 将 s 以 sep 分割为 XArray，可继续链式调用 Map / Filter 等方法
+func (s XStr) SplitX(sep string) XArray[string] {
+	return XArray[string](strings.Split(string(s), sep))
+}
+
+// FieldsX 按空白字符分割为 XArray，忽略连续空白
+func (s XStr) FieldsX() XArray[string] {
+	return XArray[string](strings.Fields(string(s)))
+}
+
+// LinesX 按行分割为 XArray，兼容 "\r\n" 换行（行尾的 "\r" 会被去除）
+func (s XStr) LinesX() XArray[string] {
+	lines := strings.Split(string(s), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return XArray[string](lines)
+}
+
+// MapLines 对每一行应用 fn，保留原有的换行符（"\n" 或 "\r\n"）
+func (s XStr) MapLines(fn func(string) string) XStr {
+	lines := strings.Split(string(s), "\n")
+	for i, line := range lines {
+		if trimmed, ok := strings.CutSuffix(line, "\r"); ok {
+			lines[i] = fn(trimmed) + "\r"
+			continue
+		}
+		lines[i] = fn(line)
+	}
+	return XStr(strings.Join(lines, "\n"))
+}
+
+// JoinX 以 sep 连接数组元素，返回 XStr 以便继续链式调用
+func (a XArray[T]) JoinX(sep string) XStr {
+	return XStr(a.Join(sep))
+}
@@ -0,0 +1,66 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStrPipeline(t *testing.T) {
+	csv := " apple, banana ,, cherry ,  ,date "
+	trim := func(s string) string { return strings.TrimSpace(s) }
+	nonEmpty := func(s string) bool { return s != "" }
+
+	// 传统写法
+	var parts []string
+	for _, p := range Str(csv).Split(",") {
+		if p = trim(p); nonEmpty(p) {
+			parts = append(parts, strings.ToUpper(p))
+		}
+	}
+	verbose := strings.Join(parts, "|")
+
+	// 链式写法
+	chained := Str(csv).SplitX(",").Map(trim).Filter(nonEmpty).Map(strings.ToUpper).JoinX("|")
+
+	if chained.String() != verbose || verbose != "APPLE|BANANA|CHERRY|DATE" {
+		t.Errorf("chained = %q, verbose = %q", chained, verbose)
+	}
+}
+
+func TestStrLinesPipeline(t *testing.T) {
+	text := "# comment\r\n  name = tom  \r\n\r\nage = 18\n# trailing"
+
+	var kept []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(strings.TrimSuffix(line, "\r"))
+		if line != "" && !strings.HasPrefix(line, "#") {
+			kept = append(kept, line)
+		}
+	}
+	verbose := strings.Join(kept, ";")
+
+	chained := Str(text).LinesX().
+		Map(strings.TrimSpace).
+		Filter(func(s string) bool { return s != "" && !strings.HasPrefix(s, "#") }).
+		JoinX(";")
+	if chained.String() != verbose || verbose != "name = tom;age = 18" {
+		t.Errorf("chained = %q, verbose = %q", chained, verbose)
+	}
+
+	if got := Str("a  b\tc\n d").FieldsX(); !got.Equal(Arrays("a", "b", "c", "d")) {
+		t.Errorf("FieldsX = %v", got)
+	}
+	if got := Arrays(1, 2, 3).JoinX("-").Upper(); got != "1-2-3" {
+		t.Errorf("JoinX = %q", got)
+	}
+}
+
+func TestMapLines(t *testing.T) {
+	got := Str("a\r\nb\nc").MapLines(func(line string) string { return "> " + line })
+	if got != "> a\r\n> b\n> c" {
+		t.Errorf("MapLines = %q", got)
+	}
+	if got := Str("").MapLines(strings.ToUpper); got != "" {
+		t.Errorf("MapLines(empty) = %q", got)
+	}
+}
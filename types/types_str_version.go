@@ -0,0 +1,234 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 版本号解析与比较（semver 的精简实现）
+//
+// 接受 "1.2.3"、"v1.2.3"、"1.2.3-rc.1+build.5" 以及省略次版本号或修订号的 "1" / "1.2"（缺省为 0），
+// 构建元数据（+ 之后的部分）不参与比较。
+
+// semver 解析后的版本号
+type semver struct {
+	major, minor, patch int
+	pre                 []string
+	parts               int // 版本核心给出的段数，用于 ~ 与 ^ 约束
+}
+
+// parseSemver 解析版本号
+func parseSemver(s string) (semver, error) {
+	var v semver
+	raw := s
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	s, _, _ = strings.Cut(s, "+")
+	core, pre, hasPre := strings.Cut(s, "-")
+
+	fields := strings.Split(core, ".")
+	if core == "" || len(fields) > 3 {
+		return v, fmt.Errorf("invalid version %q", raw)
+	}
+	nums := [3]int{}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 || f[0] == '+' || f[0] == '-' {
+			return v, fmt.Errorf("invalid version %q: bad number %q", raw, f)
+		}
+		nums[i] = n
+	}
+	v.major, v.minor, v.patch, v.parts = nums[0], nums[1], nums[2], len(fields)
+
+	if hasPre {
+		v.pre = strings.Split(pre, ".")
+		for _, id := range v.pre {
+			if id == "" || strings.Trim(id, "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz-") != "" {
+				return v, fmt.Errorf("invalid version %q: bad pre-release %q", raw, pre)
+			}
+		}
+	}
+	return v, nil
+}
+
+// compare 按 semver 优先级比较，返回 -1、0 或 1
+func (v semver) compare(o semver) int {
+	for _, d := range [3]int{v.major - o.major, v.minor - o.minor, v.patch - o.patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+
+	// 有预发布标识的版本低于正式版本
+	switch {
+	case len(v.pre) == 0 && len(o.pre) == 0:
+		return 0
+	case len(v.pre) == 0:
+		return 1
+	case len(o.pre) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.pre) && i < len(o.pre); i++ {
+		a, b := v.pre[i], o.pre[i]
+		an, aErr := strconv.Atoi(a)
+		bn, bErr := strconv.Atoi(b)
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return sign(an - bn)
+			}
+		case aErr == nil:
+			return -1 // 数字标识低于字母标识
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(a, b); c != 0 {
+				return c
+			}
+		}
+	}
+	return sign(len(v.pre) - len(o.pre))
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// ParseVersion 解析版本号，允许前导 "v"，pre 为预发布标识（不含 "-"）
+func (s XStr) ParseVersion() (major, minor, patch int, pre string, err error) {
+	v, err := parseSemver(string(s))
+	if err != nil {
+		return 0, 0, 0, "", err
+	}
+	return v.major, v.minor, v.patch, strings.Join(v.pre, "."), nil
+}
+
+// IsVersion 判断是否为合法的版本号
+func (s XStr) IsVersion() bool {
+	_, err := parseSemver(string(s))
+	return err == nil
+}
+
+// CompareVersion 按 semver 优先级与 other 比较，返回 -1、0 或 1
+// 如 1.10.0 > 1.9.0，1.0.0-alpha < 1.0.0-alpha.1 < 1.0.0-beta < 1.0.0；
+// 无法解析的版本低于任何合法版本，两者都无法解析时按字符串比较
+func (s XStr) CompareVersion(other string) int {
+	a, aErr := parseSemver(string(s))
+	b, bErr := parseSemver(other)
+	switch {
+	case aErr != nil && bErr != nil:
+		return strings.Compare(string(s), other)
+	case aErr != nil:
+		return -1
+	case bErr != nil:
+		return 1
+	}
+	return a.compare(b)
+}
+
+// SatisfiesConstraint 判断版本是否满足约束
+//
+// 约束由空格（或逗号）分隔的比较式组成，全部满足才算满足，"||" 分隔的任一组满足即可：
+//   - 比较符 =、==、!=、>、>=、<、<=，省略时为 =，如 ">=1.2.0 <2.0.0"
+//   - ~1.2.3 表示 >=1.2.3 <1.3.0，~1.2 表示 >=1.2.0 <1.3.0，~1 表示 >=1.0.0 <2.0.0
+//   - ^1.2.3 表示 >=1.2.3 <2.0.0；主版本号为 0 时锁定次版本号，如 ^0.2.3 表示 >=0.2.3 <0.3.0
+//
+// 预发布版本按优先级参与比较（2.0.0-rc.1 满足 <2.0.0）。版本号或约束格式错误时返回错误。
+func (s XStr) SatisfiesConstraint(constraint string) (bool, error) {
+	v, err := parseSemver(string(s))
+	if err != nil {
+		return false, err
+	}
+
+	groups := strings.Split(constraint, "||")
+	results := make([]bool, len(groups))
+	for i, group := range groups {
+		ok, err := satisfiesGroup(v, group)
+		if err != nil {
+			return false, err
+		}
+		results[i] = ok
+	}
+	for _, ok := range results {
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// satisfiesGroup 判断是否满足一组以空格分隔的比较式
+func satisfiesGroup(v semver, group string) (bool, error) {
+	tokens := strings.Fields(strings.ReplaceAll(group, ",", " "))
+	if len(tokens) == 0 {
+		return false, fmt.Errorf("empty version constraint")
+	}
+
+	satisfied := true
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		// 允许比较符与版本号之间有空格，如 ">= 1.2.0"
+		if strings.Trim(token, "<>=!~^") == "" && i+1 < len(tokens) {
+			i++
+			token += tokens[i]
+		}
+
+		ok, err := satisfiesComparator(v, token)
+		if err != nil {
+			return false, err
+		}
+		satisfied = satisfied && ok
+	}
+	return satisfied, nil
+}
+
+// satisfiesComparator 判断是否满足单个比较式
+func satisfiesComparator(v semver, token string) (bool, error) {
+	op := token[:len(token)-len(strings.TrimLeft(token, "<>=!~^"))]
+	target, err := parseSemver(token[len(op):])
+	if err != nil {
+		return false, fmt.Errorf("invalid constraint %q: %w", token, err)
+	}
+	c := v.compare(target)
+
+	switch op {
+	case "", "=", "==":
+		return c == 0, nil
+	case "!=":
+		return c != 0, nil
+	case ">":
+		return c > 0, nil
+	case ">=":
+		return c >= 0, nil
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case "~":
+		upper := semver{major: target.major + 1}
+		if target.parts >= 2 {
+			upper = semver{major: target.major, minor: target.minor + 1}
+		}
+		return c >= 0 && v.compare(upper) < 0, nil
+	case "^":
+		var upper semver
+		switch {
+		case target.major > 0 || target.parts == 1:
+			upper = semver{major: target.major + 1}
+		case target.minor > 0 || target.parts == 2:
+			upper = semver{minor: target.minor + 1}
+		default:
+			upper = semver{patch: target.patch + 1}
+		}
+		return c >= 0 && v.compare(upper) < 0, nil
+	default:
+		return false, fmt.Errorf("invalid constraint operator %q in %q", op, token)
+	}
+}
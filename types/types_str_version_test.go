@@ -0,0 +1,147 @@
+package types
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in                  string
+		major, minor, patch int
+		pre                 string
+	}{
+		{"1.2.3", 1, 2, 3, ""},
+		{"v1.2.3", 1, 2, 3, ""},
+		{"1.0.0-alpha.1", 1, 0, 0, "alpha.1"},
+		{"1.0.0-x-y-z.--", 1, 0, 0, "x-y-z.--"},
+		{"1.0.0+20130313144700", 1, 0, 0, ""},
+		{"1.0.0-beta+exp.sha.5114f85", 1, 0, 0, "beta"},
+		{"2", 2, 0, 0, ""},
+		{"v2.1", 2, 1, 0, ""},
+	}
+	for _, tt := range tests {
+		major, minor, patch, pre, err := Str(tt.in).ParseVersion()
+		if err != nil {
+			t.Errorf("ParseVersion(%q) error: %v", tt.in, err)
+			continue
+		}
+		if major != tt.major || minor != tt.minor || patch != tt.patch || pre != tt.pre {
+			t.Errorf("ParseVersion(%q) = %d.%d.%d-%s", tt.in, major, minor, patch, pre)
+		}
+	}
+
+	for _, bad := range []string{"", "v", "1.2.3.4", "a.b.c", "1..2", "1.2.", "-1.2.3", "1.-2.3", "1.+2.3", "1.2.3-", "1.2.3-a..b", "1.2.3-a_b", "1.2.x"} {
+		if _, _, _, _, err := Str(bad).ParseVersion(); err == nil {
+			t.Errorf("ParseVersion(%q) expected error", bad)
+		}
+		if Str(bad).IsVersion() {
+			t.Errorf("IsVersion(%q) = true", bad)
+		}
+	}
+}
+
+func TestCompareVersion(t *testing.T) {
+	// semver.org 第 11 节的优先级示例，按升序排列
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"2.0.0",
+		"2.1.0",
+		"2.1.1",
+		"2.10.0",
+	}
+	for i := range ordered {
+		for k := range ordered {
+			want := 0
+			if i < k {
+				want = -1
+			} else if i > k {
+				want = 1
+			}
+			if got := Str(ordered[i]).CompareVersion(ordered[k]); got != want {
+				t.Errorf("CompareVersion(%q, %q) = %d, want %d", ordered[i], ordered[k], got, want)
+			}
+		}
+	}
+
+	if got := Str("v1.2.3+build.1").CompareVersion("1.2.3+build.2"); got != 0 {
+		t.Errorf("build metadata should be ignored, got %d", got)
+	}
+	if got := Str("1.2").CompareVersion("1.2.0"); got != 0 {
+		t.Errorf("1.2 vs 1.2.0 = %d", got)
+	}
+	if got := Str("garbage").CompareVersion("0.0.1"); got != -1 {
+		t.Errorf("invalid vs valid = %d, want -1", got)
+	}
+	if got := Str("0.0.1").CompareVersion("garbage"); got != 1 {
+		t.Errorf("valid vs invalid = %d, want 1", got)
+	}
+}
+
+func TestSatisfiesConstraint(t *testing.T) {
+	tests := []struct {
+		version, constraint string
+		want                bool
+	}{
+		{"1.5.0", ">=1.2.0 <2.0.0", true},
+		{"2.0.0", ">=1.2.0 <2.0.0", false},
+		{"1.1.9", ">=1.2.0 <2.0.0", false},
+		{"2.0.0-rc.1", ">=1.2.0 <2.0.0", true},
+		{"1.5.0", ">= 1.2.0, < 2.0.0", true},
+		{"1.2.3", "1.2.3", true},
+		{"1.2.3", "=1.2.4", false},
+		{"1.2.3", "!=1.2.4", true},
+		{"1.2.3", ">1.2.3", false},
+		{"1.2.3", "<=1.2.3", true},
+
+		{"1.2.0", "~1.2", true},
+		{"1.2.9", "~1.2", true},
+		{"1.3.0", "~1.2", false},
+		{"1.2.2", "~1.2.3", false},
+		{"1.2.5", "~1.2.3", true},
+		{"1.9.0", "~1", true},
+		{"2.0.0", "~1", false},
+
+		{"1.2.3", "^1.2.3", true},
+		{"1.9.9", "^1.2.3", true},
+		{"2.0.0", "^1.2.3", false},
+		{"1.2.2", "^1.2.3", false},
+		{"1.5.0", "^1.2", true},
+		{"0.2.5", "^0.2.3", true},
+		{"0.3.0", "^0.2.3", false},
+		{"0.0.3", "^0.0.3", true},
+		{"0.0.4", "^0.0.3", false},
+		{"0.0.9", "^0.0", true},
+		{"0.1.0", "^0.0", false},
+
+		{"3.1.0", "^1.2 || ^3.0", true},
+		{"2.1.0", "^1.2 || ^3.0", false},
+	}
+	for _, tt := range tests {
+		got, err := Str(tt.version).SatisfiesConstraint(tt.constraint)
+		if err != nil {
+			t.Errorf("SatisfiesConstraint(%q, %q) error: %v", tt.version, tt.constraint, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("SatisfiesConstraint(%q, %q) = %v, want %v", tt.version, tt.constraint, got, tt.want)
+		}
+	}
+
+	errCases := []struct{ version, constraint string }{
+		{"1.2.3", ""},
+		{"1.2.3", ">=1.x"},
+		{"1.2.3", "=>1.2.0"},
+		{"1.2.3", ">=1.0.0 ||"},
+		{"not-a-version", ">=1.0.0"},
+	}
+	for _, tt := range errCases {
+		if _, err := Str(tt.version).SatisfiesConstraint(tt.constraint); err == nil {
+			t.Errorf("SatisfiesConstraint(%q, %q) expected error", tt.version, tt.constraint)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package types
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 可注入的时钟，定时任务通过 context 获取，测试时换成 FakeClock 即可精确控制触发时间
+
+// Clock 时钟接口
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer 定时器接口，语义与 time.Timer 一致
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+}
+
+// SystemClock 基于 time 包的系统时钟
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+type systemTimer struct {
+	t *time.Timer
+}
+
+func (s systemTimer) C() <-chan time.Time {
+	return s.t.C
+}
+
+func (s systemTimer) Stop() bool {
+	return s.t.Stop()
+}
+
+// clockKey context 中时钟的键
+type clockKey struct{}
+
+// WithClock 返回携带指定时钟的 context，AlignedTicker / At / Debounce / Throttle 使用该时钟
+func WithClock(ctx context.Context, clock Clock) context.Context {
+	return context.WithValue(ctx, clockKey{}, clock)
+}
+
+// clockFrom 获取 context 中的时钟，未设置时返回 SystemClock
+func clockFrom(ctx context.Context) Clock {
+	if clock, ok := ctx.Value(clockKey{}).(Clock); ok && clock != nil {
+		return clock
+	}
+	return SystemClock
+}
+
+// FakeClock 手动推进的时钟，用于测试，可被多个 goroutine 并发使用
+//
+// 时间只在调用 Advance 时前进；定时器按到期时间依次触发，触发时 Now 等于该定时器的到期时间。
+type FakeClock struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// NewFakeClock 创建从指定时间开始的 FakeClock
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now 返回当前的模拟时间
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer 创建在 d 之后到期的定时器，d <= 0 时立即触发
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTimer{clock: c, deadline: c.now.Add(d), ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		t.ch <- c.now
+		return t
+	}
+	c.timers = append(c.timers, t)
+	c.cond.Broadcast()
+	return t
+}
+
+// Advance 将时间推进 d，期间到期的定时器按到期时间先后触发
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	target := c.now.Add(d)
+	sort.SliceStable(c.timers, func(i, j int) bool {
+		return c.timers[i].deadline.Before(c.timers[j].deadline)
+	})
+	for len(c.timers) > 0 && !c.timers[0].deadline.After(target) {
+		t := c.timers[0]
+		c.timers = c.timers[1:]
+		c.now = t.deadline
+		t.ch <- t.deadline
+	}
+	c.now = target
+	c.cond.Broadcast()
+}
+
+// BlockUntil 阻塞直到至少有 n 个未触发且未停止的定时器，用于等待被测 goroutine 进入等待状态
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+// remove 移除未触发的定时器，返回是否移除成功
+func (c *FakeClock) remove(t *fakeTimer) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, pending := range c.timers {
+		if pending == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			c.cond.Broadcast()
+			return true
+		}
+	}
+	return false
+}
+
+// fakeTimer FakeClock 的定时器
+type fakeTimer struct {
+	clock    *FakeClock
+	deadline time.Time
+	ch       chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTimer) Stop() bool {
+	return t.clock.remove(t)
+}
@@ -0,0 +1,195 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// isoDuration ISO 8601 时长的各组成部分
+type isoDuration struct {
+	negative bool
+	years    int
+	months   int
+	weeks    int
+	days     int
+	clock    time.Duration // 时、分、秒部分
+}
+
+// parseISODuration 解析 ISO 8601 时长，如 P1Y2M3DT4H5M6.5S、P2W、-PT30M
+// 仅秒允许带小数
+func parseISODuration(s string) (isoDuration, error) {
+	var d isoDuration
+	str := strings.TrimSpace(s)
+
+	if strings.HasPrefix(str, "-") {
+		d.negative = true
+		str = str[1:]
+	} else if strings.HasPrefix(str, "+") {
+		str = str[1:]
+	}
+
+	if !strings.HasPrefix(str, "P") || len(str) == 1 {
+		return d, fmt.Errorf("invalid ISO 8601 duration: %q", s)
+	}
+	str = str[1:]
+
+	inTime := false
+	found := false
+	for len(str) > 0 {
+		if str[0] == 'T' {
+			if inTime {
+				return d, fmt.Errorf("invalid ISO 8601 duration: %q", s)
+			}
+			inTime = true
+			str = str[1:]
+			if len(str) == 0 {
+				return d, fmt.Errorf("invalid ISO 8601 duration: %q", s)
+			}
+			continue
+		}
+
+		i := 0
+		for i < len(str) && (str[i] >= '0' && str[i] <= '9' || str[i] == '.' || str[i] == ',') {
+			i++
+		}
+		if i == 0 || i == len(str) {
+			return d, fmt.Errorf("invalid ISO 8601 duration: %q", s)
+		}
+		number := strings.ReplaceAll(str[:i], ",", ".")
+		unit := str[i]
+		str = str[i+1:]
+		found = true
+
+		if unit == 'S' && inTime {
+			seconds, err := strconv.ParseFloat(number, 64)
+			if err != nil {
+				return d, fmt.Errorf("invalid ISO 8601 duration: %q", s)
+			}
+			d.clock += time.Duration(seconds * float64(time.Second))
+			continue
+		}
+
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			return d, fmt.Errorf("invalid ISO 8601 duration: %q (only seconds may be fractional)", s)
+		}
+
+		switch {
+		case !inTime && unit == 'Y':
+			d.years = n
+		case !inTime && unit == 'M':
+			d.months = n
+		case !inTime && unit == 'W':
+			d.weeks = n
+		case !inTime && unit == 'D':
+			d.days = n
+		case inTime && unit == 'H':
+			d.clock += time.Duration(n) * time.Hour
+		case inTime && unit == 'M':
+			d.clock += time.Duration(n) * time.Minute
+		default:
+			return d, fmt.Errorf("invalid ISO 8601 duration: %q (unexpected unit %q)", s, unit)
+		}
+	}
+
+	if !found {
+		return d, fmt.Errorf("invalid ISO 8601 duration: %q", s)
+	}
+	return d, nil
+}
+
+// ParseISODuration 解析 ISO 8601 时长为 time.Duration，支持周、天、时、分、秒
+// 天按 24 小时计算；年、月长度不固定，包含年或月时返回错误，请使用 XTime.AddISODuration
+func ParseISODuration(s string) (time.Duration, error) {
+	d, err := parseISODuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d.years != 0 || d.months != 0 {
+		return 0, fmt.Errorf("ISO 8601 duration %q contains years or months, which have no fixed length", s)
+	}
+
+	result := time.Duration(d.weeks*7+d.days)*24*time.Hour + d.clock
+	if d.negative {
+		result = -result
+	}
+	return result, nil
+}
+
+// FormatISODuration 将 time.Duration 格式化为 ISO 8601 时长，如 P3DT4H30M
+// 超过 24 小时的部分以天表示，零值格式化为 PT0S
+func FormatISODuration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+
+	var b strings.Builder
+	if d < 0 {
+		b.WriteString("-")
+		d = -d
+	}
+	b.WriteString("P")
+
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	d -= minutes * time.Minute
+
+	if days > 0 {
+		b.WriteString(strconv.FormatInt(int64(days), 10) + "D")
+	}
+	if hours > 0 || minutes > 0 || d > 0 {
+		b.WriteString("T")
+	}
+	if hours > 0 {
+		b.WriteString(strconv.FormatInt(int64(hours), 10) + "H")
+	}
+	if minutes > 0 {
+		b.WriteString(strconv.FormatInt(int64(minutes), 10) + "M")
+	}
+	if d > 0 {
+		b.WriteString(strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "S")
+	}
+	return b.String()
+}
+
+// AddISODuration 按日历添加 ISO 8601 时长
+// 年、月按日历计算（P1M 为下个月的同一天，日期超出目标月天数时取月末），
+// 周、天按日历天计算，时分秒按绝对时长计算
+func (x XTime) AddISODuration(s string) (XTime, error) {
+	d, err := parseISODuration(s)
+	if err != nil {
+		return x, err
+	}
+
+	sign := 1
+	if d.negative {
+		sign = -1
+	}
+
+	t := addMonthsClamped(x.t, sign*(d.years*12+d.months))
+	t = t.AddDate(0, 0, sign*(d.weeks*7+d.days))
+	t = t.Add(time.Duration(sign) * d.clock)
+	return XTime{t: t}, nil
+}
+
+// addMonthsClamped 添加月份，日期超出目标月天数时取目标月最后一天
+func addMonthsClamped(t time.Time, months int) time.Time {
+	if months == 0 {
+		return t
+	}
+
+	year, month, day := t.Date()
+	hour, minute, sec := t.Clock()
+	target := time.Date(year, month+time.Month(months), 1, hour, minute, sec, t.Nanosecond(), t.Location())
+
+	lastDay := target.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return target.AddDate(0, 0, day-1)
+}
@@ -0,0 +1,70 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestISODurationRoundTrip(t *testing.T) {
+	tests := []struct {
+		iso      string
+		duration time.Duration
+	}{
+		{"PT0S", 0},
+		{"PT30M", 30 * time.Minute},
+		{"P3DT4H30M", 3*24*time.Hour + 4*time.Hour + 30*time.Minute},
+		{"PT1H0.5S", time.Hour + 500*time.Millisecond},
+		{"P1D", 24 * time.Hour},
+		{"-PT15S", -15 * time.Second},
+	}
+
+	for _, test := range tests {
+		d, err := ParseISODuration(test.iso)
+		if err != nil {
+			t.Errorf("ParseISODuration(%q) error: %v", test.iso, err)
+			continue
+		}
+		if d != test.duration {
+			t.Errorf("ParseISODuration(%q) = %v, want %v", test.iso, d, test.duration)
+		}
+		if s := FormatISODuration(d); s != test.iso {
+			t.Errorf("FormatISODuration(%v) = %q, want %q", d, s, test.iso)
+		}
+	}
+
+	if d, _ := ParseISODuration("P2W"); d != 14*24*time.Hour {
+		t.Errorf("P2W = %v, want 336h", d)
+	}
+
+	for _, invalid := range []string{"", "P", "PT", "3D", "P1H", "PT1D", "P1.5D", "P1M", "P1Y"} {
+		if _, err := ParseISODuration(invalid); err == nil {
+			t.Errorf("ParseISODuration(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestAddISODurationCalendarAware(t *testing.T) {
+	tests := []struct {
+		start    XTime
+		iso      string
+		expected XTime
+	}{
+		{Date(2024, 1, 31, 10, 0, 0), "P1M", Date(2024, 2, 29, 10, 0, 0)},
+		{Date(2023, 1, 31, 10, 0, 0), "P1M", Date(2023, 2, 28, 10, 0, 0)},
+		{Date(2024, 3, 31, 0, 0, 0), "-P1M", Date(2024, 2, 29, 0, 0, 0)},
+		{Date(2024, 2, 29, 0, 0, 0), "P1Y", Date(2025, 2, 28, 0, 0, 0)},
+		{Date(2024, 1, 15, 8, 0, 0), "P1M2DT3H", Date(2024, 2, 17, 11, 0, 0)},
+		{Date(2024, 12, 31, 23, 0, 0), "PT2H", Date(2025, 1, 1, 1, 0, 0)},
+	}
+
+	for _, test := range tests {
+		got, err := test.start.AddISODuration(test.iso)
+		if err != nil {
+			t.Errorf("AddISODuration(%q) error: %v", test.iso, err)
+			continue
+		}
+		if !got.Equal(test.expected) {
+			t.Errorf("%s + %s = %s, want %s", test.start, test.iso, got, test.expected)
+		}
+	}
+}
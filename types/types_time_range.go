@@ -0,0 +1,123 @@
+package types
+
+import (
+	"sort"
+	"time"
+)
+
+// 时间区间运算：合并、求空闲时段与间隙，用于预约排期
+//
+// 区间为左闭右开 [Start, End)，首尾相接的区间视为连续；End 早于 Start 的区间按交换后处理，
+// 长度为 0 的区间不占用时间，在结果中会被丢弃。所有函数都接受未排序的输入且不修改入参。
+
+// XTimeRange 时间区间 [Start, End)
+type XTimeRange struct {
+	Start XTime
+	End   XTime
+}
+
+// TimeRange 创建时间区间，end 早于 start 时自动交换
+func TimeRange(start, end XTime) XTimeRange {
+	if end.Before(start) {
+		start, end = end, start
+	}
+	return XTimeRange{Start: start, End: end}
+}
+
+// Duration 区间时长，End 早于 Start 时返回 0
+func (r XTimeRange) Duration() time.Duration {
+	if d := r.End.Sub(r.Start); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// IsEmpty 区间长度是否为 0
+func (r XTimeRange) IsEmpty() bool {
+	return r.Duration() == 0
+}
+
+// Contains 时间点是否落在区间内（含 Start，不含 End）
+func (r XTimeRange) Contains(t XTime) bool {
+	return !t.Before(r.Start) && t.Before(r.End)
+}
+
+// Overlaps 两个区间是否有重叠，首尾相接不算重叠
+func (r XTimeRange) Overlaps(other XTimeRange) bool {
+	return r.Start.Before(other.End) && other.Start.Before(r.End)
+}
+
+// MergeRanges 合并重叠或首尾相接的区间，返回按开始时间排序的不相交区间
+func MergeRanges(ranges []XTimeRange) []XTimeRange {
+	sorted := make([]XTimeRange, 0, len(ranges))
+	for _, r := range ranges {
+		if r = TimeRange(r.Start, r.End); !r.IsEmpty() {
+			sorted = append(sorted, r)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+
+	merged := make([]XTimeRange, 0, len(sorted))
+	for _, r := range sorted {
+		if n := len(merged); n > 0 && !r.Start.After(merged[n-1].End) {
+			if r.End.After(merged[n-1].End) {
+				merged[n-1].End = r.End
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// SubtractRanges 从 base 中减去 holes，返回剩余的空闲时段
+// holes 超出 base 的部分会被忽略
+func SubtractRanges(base XTimeRange, holes []XTimeRange) []XTimeRange {
+	base = TimeRange(base.Start, base.End)
+	free := make([]XTimeRange, 0)
+	if base.IsEmpty() {
+		return free
+	}
+
+	cursor := base.Start
+	for _, hole := range MergeRanges(holes) {
+		if !hole.End.After(cursor) {
+			continue
+		}
+		if !hole.Start.Before(base.End) {
+			break
+		}
+		if hole.Start.After(cursor) {
+			free = append(free, XTimeRange{Start: cursor, End: hole.Start})
+		}
+		cursor = hole.End
+	}
+	if cursor.Before(base.End) {
+		free = append(free, XTimeRange{Start: cursor, End: base.End})
+	}
+	return free
+}
+
+// FindGaps 返回合并后相邻区间之间时长不小于 min 的间隙，不包含首个区间之前与最后一个区间之后
+func FindGaps(ranges []XTimeRange, min time.Duration) []XTimeRange {
+	merged := MergeRanges(ranges)
+	gaps := make([]XTimeRange, 0)
+	for i := 1; i < len(merged); i++ {
+		gap := XTimeRange{Start: merged[i-1].End, End: merged[i].Start}
+		if gap.Duration() >= min {
+			gaps = append(gaps, gap)
+		}
+	}
+	return gaps
+}
+
+// TotalDuration 合并后的总时长，重叠部分只计算一次
+func TotalDuration(ranges []XTimeRange) time.Duration {
+	var total time.Duration
+	for _, r := range MergeRanges(ranges) {
+		total += r.Duration()
+	}
+	return total
+}
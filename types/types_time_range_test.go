@@ -0,0 +1,110 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+// at 构造 2024-03-15 当天 hh:mm 的时间区间
+func at(from, to string) XTimeRange {
+	parse := func(hm string) XTime {
+		x, err := ParseTime(DateTimeFormat, "2024-03-15 "+hm+":00")
+		if err != nil {
+			panic(err)
+		}
+		return x
+	}
+	return XTimeRange{Start: parse(from), End: parse(to)}
+}
+
+func formatRanges(ranges []XTimeRange) []string {
+	out := make([]string, len(ranges))
+	for i, r := range ranges {
+		out[i] = r.Start.Format("15:04") + "-" + r.End.Format("15:04")
+	}
+	return out
+}
+
+func assertRanges(t *testing.T, name string, got []XTimeRange, want ...string) {
+	t.Helper()
+	formatted := formatRanges(got)
+	if len(formatted) != len(want) {
+		t.Fatalf("%s = %v, want %v", name, formatted, want)
+	}
+	for i := range want {
+		if formatted[i] != want[i] {
+			t.Fatalf("%s = %v, want %v", name, formatted, want)
+		}
+	}
+}
+
+// 一天的会议安排，故意打乱顺序并包含重叠、相接、零长度与反向区间
+var meetings = []XTimeRange{
+	at("14:00", "15:00"),
+	at("09:30", "10:00"),
+	at("10:00", "10:30"), // 与 09:30 相接
+	at("09:45", "10:15"), // 重叠
+	at("12:00", "12:00"), // 零长度
+	at("16:30", "16:00"), // 反向
+	at("11:00", "11:20"),
+	at("14:30", "14:45"), // 被 14:00-15:00 包含
+}
+
+func TestMergeRanges(t *testing.T) {
+	assertRanges(t, "MergeRanges", MergeRanges(meetings),
+		"09:30-10:30", "11:00-11:20", "14:00-15:00", "16:00-16:30")
+
+	if got := MergeRanges(nil); len(got) != 0 {
+		t.Errorf("MergeRanges(nil) = %v", got)
+	}
+	if meetings[0] != at("14:00", "15:00") || meetings[5] != at("16:30", "16:00") {
+		t.Error("input must not be modified")
+	}
+}
+
+func TestSubtractRanges(t *testing.T) {
+	workday := at("09:00", "18:00")
+	assertRanges(t, "SubtractRanges", SubtractRanges(workday, meetings),
+		"09:00-09:30", "10:30-11:00", "11:20-14:00", "15:00-16:00", "16:30-18:00")
+
+	// 超出 base 的部分被裁剪
+	assertRanges(t, "clipped", SubtractRanges(at("10:00", "12:00"), []XTimeRange{at("08:00", "10:30"), at("11:30", "13:00")}),
+		"10:30-11:30")
+	assertRanges(t, "fully covered", SubtractRanges(at("10:00", "11:00"), []XTimeRange{at("09:00", "12:00")}))
+	assertRanges(t, "no holes", SubtractRanges(workday, nil), "09:00-18:00")
+	assertRanges(t, "empty base", SubtractRanges(at("10:00", "10:00"), meetings))
+}
+
+func TestFindGapsAndTotalDuration(t *testing.T) {
+	assertRanges(t, "FindGaps(0)", FindGaps(meetings, 0),
+		"10:30-11:00", "11:20-14:00", "15:00-16:00")
+	assertRanges(t, "FindGaps(45m)", FindGaps(meetings, 45*time.Minute),
+		"11:20-14:00", "15:00-16:00")
+	assertRanges(t, "FindGaps(1h)", FindGaps(meetings, time.Hour),
+		"11:20-14:00", "15:00-16:00")
+	assertRanges(t, "FindGaps(2h)", FindGaps(meetings, 2*time.Hour), "11:20-14:00")
+
+	// 09:30-10:30 + 11:00-11:20 + 14:00-15:00 + 16:00-16:30
+	if got, want := TotalDuration(meetings), 2*time.Hour+50*time.Minute; got != want {
+		t.Errorf("TotalDuration = %v, want %v", got, want)
+	}
+	if got := TotalDuration([]XTimeRange{at("12:00", "12:00")}); got != 0 {
+		t.Errorf("zero-length TotalDuration = %v", got)
+	}
+}
+
+func TestXTimeRangeMethods(t *testing.T) {
+	r := TimeRange(at("11:00", "10:00").Start, at("11:00", "10:00").End)
+	if r != at("10:00", "11:00") {
+		t.Errorf("TimeRange should swap reversed bounds, got %v", formatRanges([]XTimeRange{r}))
+	}
+	if r.Duration() != time.Hour || r.IsEmpty() {
+		t.Errorf("Duration = %v", r.Duration())
+	}
+	if !r.Contains(r.Start) || r.Contains(r.End) {
+		t.Error("range should be half-open")
+	}
+	if r.Overlaps(at("11:00", "12:00")) || !r.Overlaps(at("10:59", "12:00")) {
+		t.Error("adjacent ranges should not overlap")
+	}
+}
@@ -0,0 +1,173 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// 定时任务辅助函数，时钟从 ctx 获取（见 WithClock），ctx 取消后停止
+
+// AlignedTicker 在与墙上时钟对齐的时刻调用 fn，阻塞直到 ctx 取消并返回 ctx.Err()
+//
+// 如 interval 为 5 分钟时在 :00、:05、:10 ... 触发，对齐以时钟所在时区的本地时间为准。
+// 每次触发后按当前时间重新计算下一个对齐时刻，不会累积漂移；fn 耗时超过 interval 时跳过错过的时刻，
+// 不会补触发。fn 接收的是对齐时刻本身，且不会并发执行。
+func AlignedTicker(ctx context.Context, interval time.Duration, fn func(t XTime)) error {
+	if interval <= 0 {
+		return fmt.Errorf("aligned ticker interval must be positive, got %s", interval)
+	}
+	clock := clockFrom(ctx)
+	next := alignedNext(clock.Now(), interval)
+	for {
+		if err := sleepUntil(ctx, clock, next); err != nil {
+			return err
+		}
+		fn(Time(next))
+
+		now := clock.Now()
+		if now.Before(next) {
+			now = next
+		}
+		next = alignedNext(now, interval)
+	}
+}
+
+// alignedNext 返回 t 之后第一个按本地时间与 interval 对齐的时刻
+func alignedNext(t time.Time, interval time.Duration) time.Time {
+	_, offset := t.Zone()
+	shift := time.Duration(offset) * time.Second
+	return t.Add(shift).Truncate(interval).Add(interval).Add(-shift)
+}
+
+// At 每天在 loc 时区的 hour:minute 调用 fn，阻塞直到 ctx 取消并返回 ctx.Err()，loc 为 nil 时使用 time.Local
+//
+// 夏令时切换日：指定时刻因时钟拨快而不存在时（如 02:30），在切换后的对应时刻（03:30）执行一次；
+// 因时钟拨回而出现两次时，只在第一次执行。
+func At(ctx context.Context, hour, minute int, loc *time.Location, fn func(t XTime)) error {
+	if hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return fmt.Errorf("invalid daily schedule time %02d:%02d", hour, minute)
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+	clock := clockFrom(ctx)
+	for {
+		next := nextDaily(clock.Now(), hour, minute, loc)
+		if err := sleepUntil(ctx, clock, next); err != nil {
+			return err
+		}
+		fn(Time(next))
+	}
+}
+
+// nextDaily 返回 now 之后第一个 hour:minute，按日历日递增而不是加 24 小时，以免夏令时切换后偏移一小时
+func nextDaily(now time.Time, hour, minute int, loc *time.Location) time.Time {
+	local := now.In(loc)
+	for day := 0; ; day++ {
+		next := dailyTime(local.Year(), local.Month(), local.Day()+day, hour, minute, loc)
+		if next.After(now) {
+			return next
+		}
+	}
+}
+
+// dailyTime 返回指定日期的 hour:minute，该时刻不存在时按切换前的偏移换算，即顺延到切换后的对应时刻
+func dailyTime(year int, month time.Month, day, hour, minute int, loc *time.Location) time.Time {
+	t := time.Date(year, month, day, hour, minute, 0, 0, loc)
+	if t.Hour() == hour && t.Minute() == minute {
+		return t
+	}
+	// time.Date 对不存在的时刻可能给出切换前的时间（如 01:30），改用该偏移解释墙上时间并取较晚者
+	_, offset := t.Zone()
+	wall := time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	if shifted := wall.Add(-time.Duration(offset) * time.Second).In(loc); shifted.After(t) {
+		return shifted
+	}
+	return t
+}
+
+// sleepUntil 等待到 t，ctx 先取消时返回 ctx.Err()
+func sleepUntil(ctx context.Context, clock Clock, t time.Time) error {
+	timer := clock.NewTimer(t.Sub(clock.Now()))
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		return ctx.Err()
+	case <-timer.C():
+		return nil
+	}
+}
+
+// Debounce 返回防抖后的函数：每次调用都重新计时，最后一次调用 d 之后在新的 goroutine 中执行 fn
+//
+// ctx 取消后尚未执行的调用被丢弃，之后的调用不再生效。
+func Debounce(ctx context.Context, d time.Duration, fn func()) func() {
+	clock := clockFrom(ctx)
+	var (
+		mu      sync.Mutex
+		pending chan struct{}
+	)
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if ctx.Err() != nil {
+			return
+		}
+		if pending != nil {
+			close(pending)
+		}
+		cancel := make(chan struct{})
+		pending = cancel
+		timer := clock.NewTimer(d)
+
+		go func() {
+			select {
+			case <-timer.C():
+			case <-cancel:
+				timer.Stop()
+				return
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+
+			mu.Lock()
+			current := pending == cancel
+			if current {
+				pending = nil
+			}
+			mu.Unlock()
+			if current && ctx.Err() == nil {
+				fn()
+			}
+		}()
+	}
+}
+
+// Throttle 返回节流后的函数：距上次执行不足 d 的调用被忽略，其余调用在调用方 goroutine 中同步执行 fn
+//
+// ctx 取消后调用不再生效。
+func Throttle(ctx context.Context, d time.Duration, fn func()) func() {
+	clock := clockFrom(ctx)
+	var (
+		mu      sync.Mutex
+		lastRun time.Time
+		ran     bool
+	)
+	return func() {
+		if ctx.Err() != nil {
+			return
+		}
+		mu.Lock()
+		now := clock.Now()
+		if ran && now.Sub(lastRun) < d {
+			mu.Unlock()
+			return
+		}
+		lastRun, ran = now, true
+		mu.Unlock()
+		fn()
+	}
+}
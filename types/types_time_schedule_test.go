@@ -0,0 +1,233 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+// fireRecorder 并发安全地记录触发时间
+type fireRecorder struct {
+	mu    sync.Mutex
+	times []string
+}
+
+func (r *fireRecorder) record(layout string) func(XTime) {
+	return func(t XTime) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.times = append(r.times, t.Format(layout))
+	}
+}
+
+func (r *fireRecorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.times...)
+}
+
+// stepClock 每次等待被测 goroutine 进入等待后推进 step，共推进 n 次
+//
+// 定时器触发后 goroutine 需执行完 fn 并创建新的定时器，BlockUntil 保证下一步推进前已完成
+func stepClock(clock *FakeClock, step time.Duration, n int) {
+	for i := 0; i < n; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(step)
+	}
+	clock.BlockUntil(1)
+}
+
+func TestAlignedTicker(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 5, 1, 10, 3, 20, 0, time.UTC))
+	ctx, cancel := context.WithCancel(WithClock(context.Background(), clock))
+	recorder := &fireRecorder{}
+	record := recorder.record("15:04:05")
+	done := make(chan error, 1)
+	go func() {
+		done <- AlignedTicker(ctx, 5*time.Minute, func(t XTime) {
+			record(t)
+			if t.Minute() == 10 {
+				// 模拟耗时任务，错过 :15 与 :20
+				clock.Advance(12 * time.Minute)
+			}
+		})
+	}()
+
+	stepClock(clock, 20*time.Second, 54) // 加上任务中推进的 12 分钟，共推进到 10:33:20
+	want := []string{"10:05:00", "10:10:00", "10:25:00", "10:30:00"}
+	if got := recorder.get(); !reflect.DeepEqual(got, want) {
+		t.Errorf("fire times = %v, want %v", got, want)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("AlignedTicker returned %v", err)
+	}
+	if err := AlignedTicker(ctx, 0, func(XTime) {}); err == nil {
+		t.Error("zero interval should fail")
+	}
+}
+
+func TestAlignedNextLocalOffset(t *testing.T) {
+	// +05:30 时区的整点对齐本地时间而不是 UTC
+	loc := time.FixedZone("IST", 5*3600+1800)
+	now := time.Date(2024, 5, 1, 9, 40, 0, 0, loc)
+	if got := alignedNext(now, time.Hour); !got.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, loc)) {
+		t.Errorf("alignedNext = %v", got)
+	}
+	if got := alignedNext(time.Date(2024, 5, 1, 10, 0, 0, 0, loc), 15*time.Minute); !got.Equal(time.Date(2024, 5, 1, 10, 15, 0, 0, loc)) {
+		t.Errorf("alignedNext on boundary = %v", got)
+	}
+}
+
+func TestAtAcrossDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		hour, minute int
+		start        time.Time
+		want         []string
+	}{
+		{
+			// 2024-03-10 02:00 拨快到 03:00，02:30 不存在，顺延到 03:30 执行一次
+			name: "spring forward", hour: 2, minute: 30,
+			start: time.Date(2024, 3, 9, 12, 0, 0, 0, ny),
+			want:  []string{"2024-03-10 03:30 EDT", "2024-03-11 02:30 EDT", "2024-03-12 02:30 EDT"},
+		},
+		{
+			// 2024-11-03 02:00 拨回到 01:00，01:30 出现两次，只执行第一次
+			name: "fall back", hour: 1, minute: 30,
+			start: time.Date(2024, 11, 2, 12, 0, 0, 0, ny),
+			want:  []string{"2024-11-03 01:30 EDT", "2024-11-04 01:30 EST", "2024-11-05 01:30 EST"},
+		},
+		{
+			// 23 小时的一天之后仍在同一墙上时间执行
+			name: "wall clock", hour: 9, minute: 0,
+			start: time.Date(2024, 3, 9, 12, 0, 0, 0, ny),
+			want:  []string{"2024-03-10 09:00 EDT", "2024-03-11 09:00 EDT", "2024-03-12 09:00 EDT"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewFakeClock(tt.start.UTC())
+			ctx, cancel := context.WithCancel(WithClock(context.Background(), clock))
+			defer cancel()
+			recorder := &fireRecorder{}
+			go At(ctx, tt.hour, tt.minute, ny, recorder.record("2006-01-02 15:04 MST"))
+
+			stepClock(clock, 15*time.Minute, 3*24*4)
+			if got := recorder.get(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fire times = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if err := At(context.Background(), 24, 0, ny, func(XTime) {}); err == nil {
+		t.Error("hour 24 should fail")
+	}
+}
+
+// waitCount 等待计数达到 n，超时则失败
+func waitCount(t *testing.T, count *int32, n int32) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(count) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("count = %d, want %d", atomic.LoadInt32(count), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestDebounce(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	ctx, cancel := context.WithCancel(WithClock(context.Background(), clock))
+	var count int32
+	debounced := Debounce(ctx, time.Second, func() { atomic.AddInt32(&count, 1) })
+
+	// 连续调用只在最后一次之后执行一次
+	for i := 0; i < 5; i++ {
+		debounced()
+		clock.Advance(500 * time.Millisecond)
+	}
+	if atomic.LoadInt32(&count) != 0 {
+		t.Fatal("fn ran while calls were still arriving")
+	}
+	clock.Advance(500 * time.Millisecond)
+	waitCount(t, &count, 1)
+
+	debounced()
+	clock.Advance(2 * time.Second)
+	waitCount(t, &count, 2)
+
+	// ctx 取消后丢弃未执行的调用，之后的调用不再创建定时器
+	debounced()
+	cancel()
+	clock.Advance(2 * time.Second)
+	debounced()
+	time.Sleep(10 * time.Millisecond)
+	if got := atomic.LoadInt32(&count); got != 2 {
+		t.Errorf("count after cancel = %d, want 2", got)
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	ctx, cancel := context.WithCancel(WithClock(context.Background(), clock))
+	var runs []string
+	throttled := Throttle(ctx, time.Second, func() { runs = append(runs, clock.Now().Format("05.000")) })
+
+	for i := 0; i < 6; i++ {
+		throttled()
+		clock.Advance(400 * time.Millisecond)
+	}
+	// 2.0 距上次执行 1.2 只有 0.8 秒，被忽略
+	want := []string{"00.000", "01.200"}
+	if !reflect.DeepEqual(runs, want) {
+		t.Errorf("runs = %v, want %v", runs, want)
+	}
+
+	cancel()
+	throttled()
+	if len(runs) != 2 {
+		t.Errorf("throttled ran after cancel: %v", runs)
+	}
+}
+
+func TestFakeClockTimers(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	late, early, stopped := clock.NewTimer(2*time.Second), clock.NewTimer(time.Second), clock.NewTimer(time.Second)
+	if !stopped.Stop() || stopped.Stop() {
+		t.Error("Stop should report true only for a pending timer")
+	}
+
+	clock.Advance(3 * time.Second)
+	if got := (<-early.C()).Second(); got != 1 {
+		t.Errorf("early fired at %d", got)
+	}
+	if got := (<-late.C()).Second(); got != 2 {
+		t.Errorf("late fired at %d", got)
+	}
+	if len(stopped.C()) != 0 || late.Stop() {
+		t.Error("stopped or fired timers must not fire again")
+	}
+	if clock.Now().Second() != 3 {
+		t.Errorf("Now = %v", clock.Now())
+	}
+	if got := <-clock.NewTimer(0).C(); !got.Equal(clock.Now()) {
+		t.Errorf("zero timer fired at %v", got)
+	}
+
+	if clockFrom(context.Background()) != SystemClock {
+		t.Error("default clock should be SystemClock")
+	}
+}
@@ -0,0 +1,70 @@
+package types
+
+import "time"
+
+// 周与财年：可配置的周起始日、美国周编号以及按起始月份计算的财年与财季
+
+// StartOfWeekOn 获取以指定星期为起始日的本周开始时间
+// StartOfWeek 固定以周一为起始日，美国习惯使用 StartOfWeekOn(time.Sunday)
+func (x XTime) StartOfWeekOn(day time.Weekday) XTime {
+	days := (int(x.t.Weekday()) - int(day) + 7) % 7
+	return x.AddDays(-days).StartOfDay()
+}
+
+// EndOfWeekOn 获取以指定星期为起始日的本周结束时间
+func (x XTime) EndOfWeekOn(day time.Weekday) XTime {
+	return x.StartOfWeekOn(day).AddDays(6).EndOfDay()
+}
+
+// ISOWeek 获取 ISO 8601 周编号及其所属年份
+// 年初或年末的日期可能属于相邻年份的周，如 2021-01-01 属于 2020 年第 53 周
+func (x XTime) ISOWeek() (year, week int) {
+	return x.t.ISOWeek()
+}
+
+// WeekOfYearUS 获取美国习惯的周编号 (1-54)
+// 每周从周日开始，包含 1 月 1 日的周为第 1 周，年末的日期不会归入下一年
+func (x XTime) WeekOfYearUS() int {
+	jan1 := time.Date(x.t.Year(), time.January, 1, 0, 0, 0, 0, x.t.Location())
+	return (x.t.YearDay()-1+int(jan1.Weekday()))/7 + 1
+}
+
+// fiscalStartMonth 规范化财年起始月份，超出 1-12 时按 1 月处理
+func fiscalStartMonth(startMonth int) int {
+	if startMonth < 1 || startMonth > 12 {
+		return 1
+	}
+	return startMonth
+}
+
+// FiscalYear 获取财年，startMonth 为财年起始月份 (1-12)
+// 财年以其结束所在的日历年命名，如 startMonth 为 4 时 2024-04-01 至 2025-03-31 为 2025 财年；
+// startMonth 为 1 时与日历年相同
+func (x XTime) FiscalYear(startMonth int) int {
+	startMonth = fiscalStartMonth(startMonth)
+	if startMonth == 1 || x.Month() < startMonth {
+		return x.Year()
+	}
+	return x.Year() + 1
+}
+
+// StartOfFiscalYear 获取所在财年的开始时间
+func (x XTime) StartOfFiscalYear(startMonth int) XTime {
+	startMonth = fiscalStartMonth(startMonth)
+	year := x.Year()
+	if x.Month() < startMonth {
+		year--
+	}
+	return XTime{t: time.Date(year, time.Month(startMonth), 1, 0, 0, 0, 0, x.t.Location())}
+}
+
+// EndOfFiscalYear 获取所在财年的结束时间
+func (x XTime) EndOfFiscalYear(startMonth int) XTime {
+	return x.StartOfFiscalYear(startMonth).AddYears(1).AddDays(-1).EndOfDay()
+}
+
+// FiscalQuarter 获取财季 (1-4)，财年起始月份所在的三个月为第 1 季度
+func (x XTime) FiscalQuarter(startMonth int) int {
+	startMonth = fiscalStartMonth(startMonth)
+	return (x.Month()-startMonth+12)%12/3 + 1
+}
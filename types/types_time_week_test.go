@@ -0,0 +1,93 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeekNumbering(t *testing.T) {
+	tests := []struct {
+		date    string
+		isoYear int
+		isoWeek int
+		usWeek  int
+	}{
+		{"2020-12-31", 2020, 53, 53}, // 周四
+		{"2021-01-01", 2020, 53, 1},  // 周五：ISO 属于上一年，美国为第 1 周
+		{"2021-01-03", 2020, 53, 2},  // 周日：美国新周开始
+		{"2021-01-04", 2021, 1, 2},   // 周一：ISO 第 1 周
+		{"2024-12-30", 2025, 1, 53},  // 周一：ISO 属于下一年
+		{"2023-01-01", 2022, 52, 1},  // 周日
+		{"2022-12-31", 2022, 52, 53}, // 周六
+		{"2028-12-31", 2028, 52, 54}, // 闰年且 1 月 1 日为周六
+	}
+
+	for _, tt := range tests {
+		d, err := ParseDate(tt.date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if year, week := d.ISOWeek(); year != tt.isoYear || week != tt.isoWeek {
+			t.Errorf("%s ISOWeek = %d-W%d, want %d-W%d", tt.date, year, week, tt.isoYear, tt.isoWeek)
+		}
+		if week := d.WeekOfYearUS(); week != tt.usWeek {
+			t.Errorf("%s WeekOfYearUS = %d, want %d", tt.date, week, tt.usWeek)
+		}
+	}
+}
+
+func TestStartOfWeekOn(t *testing.T) {
+	d := Date(2021, 1, 1, 15, 30, 0) // 周五
+
+	if got := d.StartOfWeekOn(time.Sunday).FormatDateTime(); got != "2020-12-27 00:00:00" {
+		t.Errorf("StartOfWeekOn(Sunday) = %s", got)
+	}
+	if got := d.StartOfWeekOn(time.Monday).FormatDateTime(); got != d.StartOfWeek().FormatDateTime() {
+		t.Errorf("StartOfWeekOn(Monday) = %s, want %s", got, d.StartOfWeek().FormatDateTime())
+	}
+	if got := d.StartOfWeekOn(time.Friday).FormatDate(); got != "2021-01-01" {
+		t.Errorf("StartOfWeekOn(Friday) = %s", got)
+	}
+	if got := d.EndOfWeekOn(time.Sunday).FormatDateTime(); got != "2021-01-02 23:59:59" {
+		t.Errorf("EndOfWeekOn(Sunday) = %s", got)
+	}
+}
+
+func TestFiscalYear(t *testing.T) {
+	tests := []struct {
+		date    string
+		start   int
+		year    int
+		quarter int
+		begin   string
+		end     string
+	}{
+		{"2024-03-31", 4, 2024, 4, "2023-04-01", "2024-03-31"},
+		{"2024-04-01", 4, 2025, 1, "2024-04-01", "2025-03-31"},
+		{"2024-12-15", 4, 2025, 3, "2024-04-01", "2025-03-31"},
+		{"2025-01-10", 4, 2025, 4, "2024-04-01", "2025-03-31"},
+		{"2024-09-30", 10, 2024, 4, "2023-10-01", "2024-09-30"},
+		{"2024-10-01", 10, 2025, 1, "2024-10-01", "2025-09-30"},
+		{"2024-05-20", 1, 2024, 2, "2024-01-01", "2024-12-31"},
+		{"2024-05-20", 0, 2024, 2, "2024-01-01", "2024-12-31"}, // 非法月份按 1 月处理
+	}
+
+	for _, tt := range tests {
+		d, err := ParseDate(tt.date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := d.FiscalYear(tt.start); got != tt.year {
+			t.Errorf("%s FiscalYear(%d) = %d, want %d", tt.date, tt.start, got, tt.year)
+		}
+		if got := d.FiscalQuarter(tt.start); got != tt.quarter {
+			t.Errorf("%s FiscalQuarter(%d) = %d, want %d", tt.date, tt.start, got, tt.quarter)
+		}
+		if got := d.StartOfFiscalYear(tt.start).FormatDate(); got != tt.begin {
+			t.Errorf("%s StartOfFiscalYear(%d) = %s, want %s", tt.date, tt.start, got, tt.begin)
+		}
+		if got := d.EndOfFiscalYear(tt.start).FormatDateTime(); got != tt.end+" 23:59:59" {
+			t.Errorf("%s EndOfFiscalYear(%d) = %s, want %s", tt.date, tt.start, got, tt.end)
+		}
+	}
+}